@@ -0,0 +1,150 @@
+// backend/cmd/api/access.go
+/*
+ * Wiring for the RBAC access policy.
+ *
+ * ACCESS_POLICY_PATH enables API-key authentication and role-based
+ * authorization on every API and admin route. Sending SIGHUP reloads the
+ * policy file without a restart. The built-in roles predict, readonly and
+ * admin need not be defined in it.
+ *
+ * JWT_JWKS_URL also accepts bearer tokens from the site's identity
+ * provider, signed by a key in that JWKS, with the issuer JWT_ISSUER and
+ * audience JWT_AUDIENCE when set. JWT_TENANT_CLAIM and JWT_ROLES_CLAIM
+ * (default "tenant" and "roles") name the claims mapped to the caller's
+ * tenant and roles; the JWKS is refetched every JWT_JWKS_REFRESH (default
+ * 1h). Without a policy file, tokens are authorized by the built-in roles.
+ *
+ * REQUEST_SIGNING_KEYS, a JSON object mapping API key IDs to HMAC
+ * secrets (usually a secret store reference), makes those keys sign every
+ * request; timestamps may be off by REQUEST_SIGNING_SKEW (default 5m).
+ *
+ * Repeated authentication failures lock the client address or credential
+ * out: AUTH_LOCKOUT_THRESHOLD (default 5) failures within
+ * AUTH_LOCKOUT_WINDOW (default 15m) start a lockout of AUTH_LOCKOUT_BASE
+ * (default 1m), doubling with every further one up to AUTH_LOCKOUT_MAX
+ * (default 1h). AUTH_LOCKOUT=false turns this off.
+ *
+ * With either in place, browsers can upload using single-use tokens
+ * valid for UPLOAD_TOKEN_TTL (default 5m) instead of an embedded API key.
+ * Health checks stay public.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/access"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/lockout"
+	"github.com/josephed37/mammoscan-AI/backend/internal/signing"
+	"github.com/josephed37/mammoscan-AI/backend/internal/uploadtoken"
+)
+
+func setupAccess(ctx context.Context, handler *handlers.Handler) {
+	path := os.Getenv("ACCESS_POLICY_PATH")
+	jwksURL := os.Getenv("JWT_JWKS_URL")
+	if path == "" && jwksURL == "" {
+		log.Println("Neither ACCESS_POLICY_PATH nor JWT_JWKS_URL set; API routes are unauthenticated")
+		return
+	}
+	engine, err := access.NewEngine(path)
+	if err != nil {
+		log.Fatalf("Access policy init failed: %v", err)
+	}
+	handler.Access = engine
+	handler.UploadTokens = uploadtoken.NewIssuer(getEnvDuration("UPLOAD_TOKEN_TTL", uploadtoken.DefaultTTL))
+	log.Printf("Access policy loaded: %d API key(s), %d role(s)", len(engine.Policy().APIKeys), len(engine.Policy().Roles))
+
+	setupRequestSigning(handler)
+	setupLockouts(handler)
+
+	if jwksURL != "" {
+		verifier, err := access.NewJWTVerifier(ctx, access.JWTConfig{
+			JWKSURL:     jwksURL,
+			Issuer:      os.Getenv("JWT_ISSUER"),
+			Audience:    os.Getenv("JWT_AUDIENCE"),
+			TenantClaim: os.Getenv("JWT_TENANT_CLAIM"),
+			RolesClaim:  os.Getenv("JWT_ROLES_CLAIM"),
+			Refresh:     getEnvDuration("JWT_JWKS_REFRESH", 0),
+		})
+		if err != nil {
+			log.Fatalf("JWT validation init failed: %v", err)
+		}
+		handler.JWT = verifier
+		log.Printf("JWT validation enabled: %d key(s) from %s", verifier.Keys(), jwksURL)
+	}
+	if path == "" {
+		return
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if err := engine.Reload(); err != nil {
+				log.Printf("Access policy reload failed, keeping previous policy: %v", err)
+				continue
+			}
+			log.Println("Access policy reloaded")
+		}
+	}()
+}
+
+// setupLockouts enables brute-force protection unless AUTH_LOCKOUT=false.
+func setupLockouts(handler *handlers.Handler) {
+	if !getEnvBool("AUTH_LOCKOUT", true) {
+		log.Println("AUTH_LOCKOUT=false; failed authentications are not rate limited")
+		return
+	}
+	handler.Lockouts = lockout.New(lockout.Config{
+		Threshold: getEnvInt("AUTH_LOCKOUT_THRESHOLD", lockout.DefaultThreshold),
+		Window:    getEnvDuration("AUTH_LOCKOUT_WINDOW", lockout.DefaultWindow),
+		Base:      getEnvDuration("AUTH_LOCKOUT_BASE", lockout.DefaultBase),
+		Max:       getEnvDuration("AUTH_LOCKOUT_MAX", lockout.DefaultMax),
+	})
+}
+
+// setupRequestSigning requires signatures from the API keys listed in
+// REQUEST_SIGNING_KEYS. The secret is re-read when the store rotates it.
+func setupRequestSigning(handler *handlers.Handler) {
+	if os.Getenv("REQUEST_SIGNING_KEYS") == "" {
+		return
+	}
+	getter := getSecret("REQUEST_SIGNING_KEYS")
+	parse := func(raw string) (map[string]string, error) {
+		var keys map[string]string
+		err := json.Unmarshal([]byte(raw), &keys)
+		return keys, err
+	}
+	keys, err := parse(getter())
+	if err != nil {
+		log.Fatalf("Invalid REQUEST_SIGNING_KEYS: %v", err)
+	}
+
+	var mu sync.Mutex
+	raw := getter()
+	handler.RequestSigning = signing.NewVerifier(getEnvDuration("REQUEST_SIGNING_SKEW", signing.DefaultSkew))
+	handler.SigningSecret = func(keyID string) (string, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if current := getter(); current != raw {
+			// A rotated value that does not parse keeps the previous keys.
+			if parsed, err := parse(current); err == nil {
+				keys, raw = parsed, current
+			} else {
+				log.Printf("Rotated REQUEST_SIGNING_KEYS unreadable, keeping previous keys: %v", err)
+				raw = current
+			}
+		}
+		secret, ok := keys[keyID]
+		return secret, ok && secret != ""
+	}
+	log.Printf("Request signing required for %d API key(s)", len(keys))
+}
@@ -0,0 +1,63 @@
+// backend/cmd/api/audit.go
+/*
+ * Wiring for the security audit log.
+ *
+ * Failed authentications, lockouts, break-glass accesses and legal hold
+ * changes (holds placed and released, deletions refused) are written as
+ * JSON lines to AUDIT_LOG_DEST: stdout (the default), file:///path, syslog,
+ * syslog://host:port or forward (the log forwarder set up by
+ * LOG_FORWARD_URL). Files are rotated past AUDIT_LOG_MAX_SIZE_MB
+ * (default 100), keeping AUDIT_LOG_MAX_BACKUPS (default 5) old files.
+ * With WRITE_BEHIND_DIR set, entries are queued on local disk first and
+ * survive the destination being down.
+ * In offline mode they are also synced to the central service (see
+ * offline.go).
+ *
+ * Entries are recorded as the events are published, never dropped under
+ * load. Break-glass accesses are written by the handler itself before
+ * access is granted, so an access that cannot be recorded is refused.
+ */
+
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/audit"
+	"github.com/josephed37/mammoscan-AI/backend/internal/events"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+)
+
+// auditEvents are the event types the audit log records from the bus;
+// events.BreakGlassAccess is recorded through handler.Audit.
+var auditEvents = []string{
+	events.AuthFailed, events.AuthLockedOut,
+	events.LegalHoldPlaced, events.LegalHoldReleased, events.LegalHoldBlocked,
+}
+
+func setupAuditLog(ctx context.Context, handler *handlers.Handler) {
+	dest := getEnv("AUDIT_LOG_DEST", "stdout")
+	w, err := openLogDest("AUDIT_LOG", "audit", dest)
+	if err != nil {
+		log.Fatalf("Invalid AUDIT_LOG_DEST: %v", err)
+	}
+	auditLog := audit.New(w)
+	queue := writeBehindQueue("audit")
+	if queue == nil {
+		// Without a local queue every entry is written to the destination
+		// as it happens, so the bus subscriber stays asynchronous and a
+		// slow destination only delays the audit log.
+		handler.Audit = auditLog.Append
+		handler.Events.Subscribe("audit", 0, auditLog.Record, auditEvents...)
+		log.Printf("Audit log: security events to %s", dest)
+		return
+	}
+	queued := audit.NewQueued(auditLog, queue, writeBehindConfig())
+	go queued.Flusher().Run(ctx)
+	// Queueing is a local disk write: recorded inside Publish, no entry
+	// is dropped when events come faster than the destination takes them.
+	handler.Audit = queued.Append
+	handler.Events.SubscribeSync("audit", queued.Record, auditEvents...)
+	log.Printf("Audit log: security events to %s", dest)
+}
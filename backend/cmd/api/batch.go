@@ -0,0 +1,54 @@
+// backend/cmd/api/batch.go
+/*
+ * Wiring for batched inference.
+ *
+ * Concurrent predictions on the served model are scored in batches of up
+ * to BATCH_MAX_SIZE (default 8; 1 disables batching). BATCH_MAX_WAIT
+ * (default 0) lets a batch wait that long for more inputs before it
+ * starts; without it batches only form while the model is busy, which
+ * adds no latency. Models on a worker pool (INFERENCE_WORKERS) are not
+ * batched.
+ *
+ * BATCH_TARGET_P95 (e.g. 250ms) tunes the batch size and wait to the
+ * hardware and load instead, keeping the 95th percentile of batched
+ * inference latency under the target; BATCH_MAX_SIZE and BATCH_MAX_WAIT
+ * (default a quarter of the target) then bound the tuning. The limits in
+ * force are reported on /admin/overview.
+ */
+
+package main
+
+import (
+	"log"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/inference"
+)
+
+func setupBatching(handler *handlers.Handler) {
+	target := getEnvDuration("BATCH_TARGET_P95", 0)
+	cfg := inference.BatcherConfig{
+		MaxSize:   getEnvInt("BATCH_MAX_SIZE", inference.DefaultMaxBatchSize),
+		MaxWait:   getEnvDuration("BATCH_MAX_WAIT", target/4),
+		TargetP95: target,
+	}
+	if cfg.MaxSize <= 1 {
+		return
+	}
+	if modelOptions.Workers > 1 {
+		log.Printf("Batched inference disabled: predictions run on %d inference workers", modelOptions.Workers)
+		return
+	}
+	if cfg.MaxWait < 0 {
+		log.Fatalf("Invalid BATCH_MAX_WAIT: must not be negative")
+	}
+	if cfg.TargetP95 < 0 {
+		log.Fatalf("Invalid BATCH_TARGET_P95: must not be negative")
+	}
+	handler.Batcher = inference.NewBatcher(cfg)
+	if cfg.TargetP95 > 0 {
+		log.Printf("Batched inference enabled, tuned to a p95 of %s (up to %d inputs, waiting up to %s)", cfg.TargetP95, cfg.MaxSize, cfg.MaxWait)
+		return
+	}
+	log.Printf("Batched inference enabled (up to %d inputs, waiting up to %s)", cfg.MaxSize, cfg.MaxWait)
+}
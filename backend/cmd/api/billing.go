@@ -0,0 +1,73 @@
+// backend/cmd/api/billing.go
+/*
+ * Wiring for billing event emission.
+ *
+ * BILLING_SINK selects the destination (stdout, file:///path or an
+ * http(s) URL). Leaving it unset disables billing events entirely. Events
+ * carry the estimated energy and carbon of the compute when the footprint
+ * estimate is configured (see footprint.go).
+ */
+
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/billing"
+	"github.com/josephed37/mammoscan-AI/backend/internal/events"
+	"github.com/josephed37/mammoscan-AI/backend/internal/footprint"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+)
+
+func setupBilling(handler *handlers.Handler) {
+	uri := os.Getenv("BILLING_SINK")
+	if uri == "" {
+		return
+	}
+
+	sink, err := billing.NewSink(uri, getSecret("BILLING_AUTH_TOKEN"))
+	if err != nil {
+		log.Fatalf("Billing sink init failed: %v", err)
+	}
+	emitter := billing.NewEmitter(sink, billing.EmitterConfig{
+		BufferSize: getEnvInt("BILLING_BUFFER_SIZE", 0),
+		SoftQuota:  getEnvInt("BILLING_SOFT_QUOTA", 0),
+	})
+	tier := getEnv("COMPUTE_TIER", "cpu")
+	handler.Events.Subscribe("billing", 0, func(ev events.Event) {
+		if e, ok := billingEvent(ev, tier, handler.Footprint); ok {
+			emitter.Emit(e)
+		}
+	}, events.PredictionCompleted, events.FrameScored)
+	handler.Billing = emitter
+	log.Printf("Billing events enabled (sink: %s)", uri)
+}
+
+// billingEvent converts a bus event into a billable unit of work, with
+// the compute's footprint when fp is set.
+func billingEvent(ev events.Event, tier string, fp *footprint.Estimator) (billing.Event, bool) {
+	e := billing.Event{EventID: ev.ID, Timestamp: ev.Time, Tenant: ev.Tenant, ComputeTier: tier}
+	var compute time.Duration
+	switch data := ev.Data.(type) {
+	case events.Prediction:
+		e.Type = "prediction"
+		e.StudyID = data.StudyID
+		e.PredictionID = data.Response.PredictionID
+		e.Model = data.Response.ModelName
+		compute = data.ComputeTime
+	case events.Frame:
+		e.Type = "stream_frame"
+		e.Model = data.Frame.ModelName
+		compute = data.ComputeTime
+	default:
+		return billing.Event{}, false
+	}
+	e.ComputeMillis = compute.Milliseconds()
+	if fp != nil {
+		cost := fp.Estimate(compute)
+		e.EnergyWh, e.CarbonGrams = cost.EnergyWh, cost.CarbonGrams
+	}
+	return e, true
+}
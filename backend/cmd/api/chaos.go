@@ -0,0 +1,47 @@
+//go:build chaos
+
+// backend/cmd/api/chaos.go
+/*
+ * Wiring for fault injection (`go build -tags chaos`, never for
+ * production). CHAOS_INFERENCE_DELAY and CHAOS_INFERENCE_DELAY_PERCENT slow
+ * down inference; CHAOS_INFERENCE_ERROR_PERCENT, CHAOS_DECODE_ERROR_PERCENT
+ * and CHAOS_STORAGE_ERROR_PERCENT fail that share of operations.
+ */
+
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/chaos"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+)
+
+func setupChaos(handler *handlers.Handler) {
+	cfg := chaos.Config{
+		InferenceDelay:        getEnvDuration("CHAOS_INFERENCE_DELAY", 0),
+		InferenceDelayPercent: getEnvFloat("CHAOS_INFERENCE_DELAY_PERCENT", 0),
+		InferenceErrorPercent: getEnvFloat("CHAOS_INFERENCE_ERROR_PERCENT", 0),
+		DecodeErrorPercent:    getEnvFloat("CHAOS_DECODE_ERROR_PERCENT", 0),
+		StorageErrorPercent:   getEnvFloat("CHAOS_STORAGE_ERROR_PERCENT", 0),
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid fault injection configuration: %v", err)
+	}
+	if cfg == (chaos.Config{}) {
+		return
+	}
+
+	faults := chaos.New(cfg)
+	handler.Faults = faults
+	handler.Images = faults.Images(handler.Images)
+	load := handler.LoadEngine
+	handler.LoadEngine = func(ctx context.Context, ref string) (handlers.Predictor, error) {
+		if err := faults.Storage("model fetch"); err != nil {
+			return nil, err
+		}
+		return load(ctx, ref)
+	}
+	log.Printf("⚠️  FAULT INJECTION ENABLED: %+v", cfg)
+}
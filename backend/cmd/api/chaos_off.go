@@ -0,0 +1,12 @@
+//go:build !chaos
+
+// backend/cmd/api/chaos_off.go
+/*
+ * Fault injection is compiled out of regular builds.
+ */
+
+package main
+
+import "github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+
+func setupChaos(*handlers.Handler) {}
@@ -0,0 +1,120 @@
+// backend/cmd/api/cpu.go
+/*
+ * Wiring for CPU tuning.
+ *
+ * In a container with a CPU limit GOMAXPROCS defaults to the host's cores
+ * and the process is throttled for most of every period. At startup
+ * GOMAXPROCS is lowered to the cgroup's CPU quota, rounded down; set
+ * GOMAXPROCS to choose a value, or GOMAXPROCS_AUTO=false to keep the
+ * runtime's.
+ *
+ * INFERENCE_CPUS (a CPU list such as "2-5" or "2,3,6") pins inference:
+ * gorgonnx workers each run on a thread restricted to an even share of
+ * the CPUs, and ONNX Runtime's intra-op threads are spread over them.
+ * INFERENCE_NUMA_NODES (a node list such as "0" or "0-1") pins it to the
+ * CPUs of those NUMA nodes and INFERENCE_CORE_TYPE ("performance" or
+ * "efficiency") to one kind of core on big.LITTLE and hybrid hosts; set
+ * together, these and INFERENCE_CPUS narrow each other, and CPUs outside
+ * the container's cpuset are dropped. Workers never span NUMA nodes, and
+ * load their weights into their own node's memory. Leaving some CPUs to
+ * the API keeps request handling from competing with inference.
+ * ORT_INTRA_OP_THREADS and ORT_INTER_OP_THREADS size ONNX Runtime's
+ * thread pools; the intra-op pool defaults to one thread per pinned CPU
+ * or per GOMAXPROCS.
+ */
+
+package main
+
+import (
+	"errors"
+	"log"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/cputune"
+)
+
+func setupCPU() {
+	if os.Getenv("GOMAXPROCS") != "" || !getEnvBool("GOMAXPROCS_AUTO", true) {
+		log.Printf("GOMAXPROCS: %d", runtime.GOMAXPROCS(0))
+		return
+	}
+	procs, quota, err := cputune.Procs()
+	if err != nil {
+		log.Printf("Warning: failed to read the CPU quota, GOMAXPROCS stays %d: %v", runtime.GOMAXPROCS(0), err)
+		return
+	}
+	if procs == 0 || procs >= runtime.GOMAXPROCS(0) {
+		log.Printf("GOMAXPROCS: %d", runtime.GOMAXPROCS(0))
+		return
+	}
+	runtime.GOMAXPROCS(procs)
+	log.Printf("GOMAXPROCS: %d (CPU quota %g)", procs, quota)
+}
+
+// inferenceCPUs returns the CPUs inference is pinned to: those in
+// INFERENCE_CPUS, INFERENCE_NUMA_NODES and INFERENCE_CORE_TYPE, each
+// narrowing the others, or nil when none is set.
+func inferenceCPUs() []int {
+	var cpus []int
+	narrow := func(name string, list []int) {
+		if cpus == nil {
+			cpus = list
+			return
+		}
+		cpus = slices.DeleteFunc(cpus, func(cpu int) bool { return !slices.Contains(list, cpu) })
+		if len(cpus) == 0 {
+			log.Fatalf("%s leaves no CPU to run inference on", name)
+		}
+	}
+	if list := os.Getenv("INFERENCE_CPUS"); list != "" {
+		parsed, err := cputune.ParseCPUList(list)
+		if err != nil {
+			log.Fatalf("Invalid INFERENCE_CPUS: %v", err)
+		}
+		narrow("INFERENCE_CPUS", parsed)
+	}
+	if list := os.Getenv("INFERENCE_NUMA_NODES"); list != "" {
+		nodes, err := cputune.ParseCPUList(list)
+		if err != nil {
+			log.Fatalf("Invalid INFERENCE_NUMA_NODES: %v", err)
+		}
+		nodeCPUs, err := cputune.NodeCPUs(nodes)
+		if err != nil {
+			log.Fatalf("Invalid INFERENCE_NUMA_NODES: %v", err)
+		}
+		narrow("INFERENCE_NUMA_NODES", nodeCPUs)
+	}
+	if kind := os.Getenv("INFERENCE_CORE_TYPE"); kind != "" {
+		typeCPUs, err := cputune.CoreTypeCPUs(cputune.CoreType(strings.ToLower(kind)))
+		switch {
+		case errors.Is(err, cputune.ErrHomogeneous):
+			log.Printf("INFERENCE_CORE_TYPE ignored: %v", err)
+		case err != nil:
+			log.Fatalf("Invalid INFERENCE_CORE_TYPE: %v", err)
+		default:
+			narrow("INFERENCE_CORE_TYPE", typeCPUs)
+		}
+	}
+	if cpus == nil {
+		return nil
+	}
+	// CPUs outside the container's cpuset cannot be pinned to.
+	if allowed, err := cputune.AllowedCPUs(); err == nil {
+		narrow("The container's cpuset", allowed)
+	}
+	log.Printf("Inference pinned to CPUs %v", cpus)
+	return cpus
+}
+
+// ortThreads returns the thread count in the named variable, 0 when it
+// is unset.
+func ortThreads(name string) int {
+	n := getEnvInt(name, 0)
+	if n < 0 {
+		log.Fatalf("Invalid %s: must not be negative", name)
+	}
+	return n
+}
@@ -0,0 +1,37 @@
+// backend/cmd/api/dependencies.go
+/*
+ * Wiring for downstream dependency health.
+ *
+ * The prediction database and webhook endpoints sit behind circuit
+ * breakers: after CIRCUIT_FAILURES consecutive failures (default 5) calls
+ * fail at once for CIRCUIT_COOLDOWN (default 30s) instead of waiting on a
+ * dependency that is down. Dependencies with a health check (the
+ * database's ping) are checked every DEPENDENCY_CHECK_INTERVAL (default
+ * 15s), each within DEPENDENCY_CHECK_TIMEOUT (default 5s). GET /healthz
+ * reports them all.
+ */
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/dependency"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+)
+
+func setupDependencies(ctx context.Context, handler *handlers.Handler) {
+	handler.Dependencies = &dependency.Set{}
+	go handler.Dependencies.Run(ctx,
+		getEnvDuration("DEPENDENCY_CHECK_INTERVAL", 15*time.Second),
+		getEnvDuration("DEPENDENCY_CHECK_TIMEOUT", 5*time.Second))
+}
+
+// breakerConfig is the circuit breaker tuning shared by every dependency.
+func breakerConfig() dependency.BreakerConfig {
+	return dependency.BreakerConfig{
+		Failures: getEnvInt("CIRCUIT_FAILURES", dependency.DefaultFailures),
+		Cooldown: getEnvDuration("CIRCUIT_COOLDOWN", dependency.DefaultCooldown),
+	}
+}
@@ -0,0 +1,45 @@
+// backend/cmd/api/disclaimer.go
+/*
+ * Wiring for jurisdiction-specific disclaimers.
+ *
+ * DISCLAIMERS_PATH points at the catalog file. REPORT_JURISDICTION selects
+ * the wording used in scheduled reports (defaults to the catalog default).
+ */
+
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/disclaimer"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+)
+
+func setupDisclaimers(handler *handlers.Handler) {
+	path := os.Getenv("DISCLAIMERS_PATH")
+	if path == "" {
+		return
+	}
+	catalog, err := disclaimer.Load(path)
+	if err != nil {
+		log.Fatalf("Disclaimer catalog init failed: %v", err)
+	}
+	handler.Disclaimers = catalog
+	log.Printf("Disclaimers loaded for %d jurisdiction(s)", len(catalog.Jurisdictions))
+}
+
+// reportDisclaimer returns the wording to attach to scheduled reports.
+func reportDisclaimer(catalog *disclaimer.Catalog) string {
+	if catalog == nil {
+		return ""
+	}
+	if j := os.Getenv("REPORT_JURISDICTION"); j != "" {
+		text, ok := catalog.Jurisdictions[j]
+		if !ok {
+			log.Fatalf("REPORT_JURISDICTION %q has no disclaimer text", j)
+		}
+		return text
+	}
+	return catalog.For("")
+}
@@ -0,0 +1,64 @@
+// backend/cmd/api/dropfolder.go
+/*
+ * Wiring for drop-folder ingestion.
+ *
+ * DROP_FOLDER enables the watcher: a local or mounted directory, or (not
+ * in the edge build) sftp://user@host[:port]/path. New images are scored
+ * for DROP_FOLDER_TENANT every DROP_FOLDER_INTERVAL and the results written
+ * back next to them. SFTP authenticates with DROP_FOLDER_SFTP_KEY (a
+ * private key file) and/or DROP_FOLDER_SFTP_PASSWORD, and verifies the
+ * server against DROP_FOLDER_KNOWN_HOSTS.
+ */
+
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/dropfolder"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+)
+
+func setupDropFolder(ctx context.Context, handler *handlers.Handler) {
+	uri := os.Getenv("DROP_FOLDER")
+	if uri == "" {
+		return
+	}
+	tenant := os.Getenv("DROP_FOLDER_TENANT")
+	if err := handler.Residency.CheckTenant(tenant); err != nil {
+		log.Fatalf("Drop folder: %v", err)
+	}
+
+	var fs dropfolder.FS
+	if strings.Contains(uri, "://") {
+		if err := handler.Residency.CheckBackend("DROP_FOLDER", uri); err != nil {
+			log.Fatalf("Residency policy: %v", err)
+		}
+		remote, err := openRemoteDropFolder(uri)
+		if err != nil {
+			log.Fatalf("Drop folder init failed: %v", err)
+		}
+		fs = remote
+	} else {
+		if info, err := os.Stat(uri); err != nil || !info.IsDir() {
+			log.Fatalf("Drop folder init failed: %s is not a directory", uri)
+		}
+		fs = dropfolder.Dir(filepath.Clean(uri))
+	}
+
+	maxSize := handler.Uploads.MaxImageBytes
+	if maxSize <= 0 {
+		maxSize = handlers.DefaultMaxImageBytes
+	}
+	watcher := dropfolder.New(fs, dropfolder.Config{
+		Interval:    getEnvDuration("DROP_FOLDER_INTERVAL", 0),
+		MaxFileSize: maxSize,
+	}, func(ctx context.Context, name string, data []byte) (int, []byte) {
+		return handler.Ingest(ctx, tenant, name, data, map[string]string{"client_reference": name})
+	})
+	go watcher.Run(ctx)
+}
@@ -0,0 +1,18 @@
+//go:build edge
+
+// backend/cmd/api/dropfolder_edge.go
+/*
+ * The edge build watches local folders only.
+ */
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/dropfolder"
+)
+
+func openRemoteDropFolder(uri string) (dropfolder.FS, error) {
+	return nil, fmt.Errorf("remote drop folder %q not available in the %s build", uri, buildProfile)
+}
@@ -0,0 +1,70 @@
+//go:build !edge
+
+// backend/cmd/api/dropfolder_sftp.go
+/*
+ * SFTP drop folders for the standard and local builds.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/dropfolder"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// openRemoteDropFolder connects the watcher to an sftp:// URI.
+func openRemoteDropFolder(uri string) (dropfolder.FS, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "sftp" || u.Host == "" || u.User == nil {
+		return nil, fmt.Errorf("invalid DROP_FOLDER %q (want sftp://user@host[:port]/path)", uri)
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "22")
+	}
+
+	home, _ := os.UserHomeDir()
+	hostKeys, err := knownhosts.New(getEnv("DROP_FOLDER_KNOWN_HOSTS", filepath.Join(home, ".ssh", "known_hosts")))
+	if err != nil {
+		return nil, fmt.Errorf("known hosts: %w", err)
+	}
+
+	var auth []ssh.AuthMethod
+	if keyPath := os.Getenv("DROP_FOLDER_SFTP_KEY"); keyPath != "" {
+		pem, err := os.ReadFile(keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("read DROP_FOLDER_SFTP_KEY: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(pem)
+		if err != nil {
+			return nil, fmt.Errorf("parse DROP_FOLDER_SFTP_KEY: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if os.Getenv("DROP_FOLDER_SFTP_PASSWORD") != "" {
+		password := getSecret("DROP_FOLDER_SFTP_PASSWORD")
+		auth = append(auth, ssh.PasswordCallback(func() (string, error) { return password(), nil }))
+	}
+	if len(auth) == 0 {
+		return nil, fmt.Errorf("set DROP_FOLDER_SFTP_KEY or DROP_FOLDER_SFTP_PASSWORD")
+	}
+
+	dir := u.Path
+	if dir == "" {
+		dir = "."
+	}
+	return dropfolder.NewSFTP(dropfolder.SFTPConfig{
+		Addr:    addr,
+		User:    u.User.Username(),
+		Dir:     dir,
+		Auth:    auth,
+		HostKey: hostKeys,
+	})
+}
@@ -0,0 +1,31 @@
+// backend/cmd/api/encryption.go
+/*
+ * Wiring for client-side encrypted uploads.
+ *
+ * UPLOAD_DECRYPTION_KEYS_DIR holds one private key per tenant
+ * (`<tenant>.pem`). When set, uploads sent as JWE envelopes are decrypted
+ * in memory before preprocessing.
+ */
+
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/envelope"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+)
+
+func setupEncryption(handler *handlers.Handler) {
+	dir := os.Getenv("UPLOAD_DECRYPTION_KEYS_DIR")
+	if dir == "" {
+		return
+	}
+	keyring, err := envelope.LoadDir(dir)
+	if err != nil {
+		log.Fatalf("Upload decryption keys init failed: %v", err)
+	}
+	handler.Decryption = keyring
+	log.Printf("Encrypted uploads enabled for %d tenant(s)", keyring.Len())
+}
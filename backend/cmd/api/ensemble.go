@@ -0,0 +1,115 @@
+// backend/cmd/api/ensemble.go
+/*
+ * Wiring for model ensembles.
+ *
+ * ENSEMBLE_MODELS lists additional models as comma-separated references
+ * (local paths or remote URIs, optionally prefixed with "name="); they
+ * only flag disagreement, the served model decides.
+ * ENSEMBLE_MAX_DISAGREEMENT is the score gap above which a study is
+ * flagged needs_review (default 0.2).
+ *
+ * ENSEMBLE_CONFIG describes the ensemble in JSON instead, inline or in
+ * the file it names: the models with their weights and thresholds, and
+ * the method combining them (see internal/ensemble). With weighted_mean
+ * or majority_vote the ensemble decides every prediction.
+ */
+
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/ensemble"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+func setupEnsemble(ctx context.Context, handler *handlers.Handler) {
+	cfg, ok := ensembleConfig()
+	if !ok {
+		return
+	}
+
+	method, _ := ensemble.ParseMethod(string(cfg.Method))
+	e := &ensemble.Ensemble{
+		MaxDisagreement:   cfg.MaxDisagreement,
+		Threshold:         handler.Model.Threshold,
+		Method:            method,
+		ServedWeight:      cfg.ServedModelWeight(),
+		DecisionThreshold: cfg.Threshold,
+	}
+	if e.MaxDisagreement == 0 {
+		e.MaxDisagreement = getEnvFloat("ENSEMBLE_MAX_DISAGREEMENT", ensemble.DefaultMaxDisagreement)
+	}
+	if e.MaxDisagreement <= 0 || e.MaxDisagreement >= 1 {
+		log.Fatalf("ENSEMBLE_MAX_DISAGREEMENT must be between 0 and 1, got %v", e.MaxDisagreement)
+	}
+	for _, m := range cfg.Models {
+		name := m.Name
+		if name == "" {
+			name = strings.TrimSuffix(path.Base(m.Source), path.Ext(m.Source))
+		}
+		engine, err := handler.LoadEngine(ctx, m.Source)
+		if err != nil {
+			log.Fatalf("Ensemble model %s failed to load: %v", name, err)
+		}
+		threshold := m.Threshold
+		if threshold == 0 && handler.ModelThreshold != nil {
+			threshold = handler.ModelThreshold(ctx, m.Source, models.ModelInfo{Name: name})
+		}
+		e.Members = append(e.Members, ensemble.Member{
+			Name:      name,
+			Source:    m.Source,
+			Engine:    engine,
+			Weight:    m.ModelWeight(),
+			Threshold: threshold,
+		})
+	}
+	handler.Ensemble = e
+	if e.Decides() {
+		log.Printf("Ensemble of %d additional model(s) deciding by %s, flagging disagreement above %.2f", len(e.Members), e.Method, e.MaxDisagreement)
+		return
+	}
+	log.Printf("Ensemble of %d additional model(s), flagging disagreement above %.2f", len(e.Members), e.MaxDisagreement)
+}
+
+// ensembleConfig reads ENSEMBLE_CONFIG, or builds a review-only
+// configuration from ENSEMBLE_MODELS; false when neither is set.
+func ensembleConfig() (ensemble.Config, bool) {
+	raw := strings.TrimSpace(os.Getenv("ENSEMBLE_CONFIG"))
+	refs := splitList(os.Getenv("ENSEMBLE_MODELS"))
+	if raw == "" {
+		if len(refs) == 0 {
+			return ensemble.Config{}, false
+		}
+		var cfg ensemble.Config
+		for _, ref := range refs {
+			name, source, ok := strings.Cut(ref, "=")
+			if !ok || strings.ContainsAny(name, "/:") {
+				// No name given (an "=" in a URI query does not count).
+				name, source = "", ref
+			}
+			cfg.Models = append(cfg.Models, ensemble.ModelConfig{Name: name, Source: source})
+		}
+		return cfg, true
+	}
+	if len(refs) > 0 {
+		log.Fatal("Set either ENSEMBLE_CONFIG or ENSEMBLE_MODELS, not both")
+	}
+	data := []byte(raw)
+	if !strings.HasPrefix(raw, "{") {
+		var err error
+		if data, err = os.ReadFile(raw); err != nil {
+			log.Fatalf("Invalid ENSEMBLE_CONFIG: %v", err)
+		}
+	}
+	cfg, err := ensemble.ParseConfig(data)
+	if err != nil {
+		log.Fatalf("Invalid ENSEMBLE_CONFIG: %v", err)
+	}
+	return cfg, true
+}
@@ -0,0 +1,34 @@
+// backend/cmd/api/events.go
+/*
+ * Wiring for the internal event bus.
+ *
+ * The handler publishes completed predictions and scored stream frames;
+ * the publishers below add failed jobs and significant score drift. Sinks
+ * (billing, webhooks) subscribe in their own setup functions.
+ */
+
+package main
+
+import (
+	"github.com/josephed37/mammoscan-AI/backend/internal/events"
+	"github.com/josephed37/mammoscan-AI/backend/internal/jobs"
+	"github.com/josephed37/mammoscan-AI/backend/internal/stats"
+)
+
+// publishDrift returns a stats.Config.OnDrift hook that publishes
+// DriftDetected.
+func publishDrift(bus *events.Bus) func(stats.DriftStats) {
+	return func(d stats.DriftStats) {
+		bus.Publish(events.Event{Type: events.DriftDetected, Data: d})
+	}
+}
+
+// publishJobFailures returns a jobs.Manager.OnFinish hook that publishes
+// JobFailed.
+func publishJobFailures(bus *events.Bus) func(jobs.Job) {
+	return func(j jobs.Job) {
+		if j.Status == jobs.StatusFailed {
+			bus.Publish(events.Event{Type: events.JobFailed, Tenant: j.Tenant, Data: j})
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// backend/cmd/api/experiment.go
+/*
+ * Wiring for model experiments.
+ *
+ * Experiments are started through the admin API. EXPERIMENTS_PATH persists
+ * them so a running experiment survives a restart.
+ * EXPERIMENT_SHADOW_CONCURRENCY bounds how many studies a shadow
+ * experiment's candidate scores at once (default 2).
+ */
+
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/experiment"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+)
+
+func setupExperiments(ctx context.Context, handler *handlers.Handler) {
+	manager, err := experiment.NewManager(experiment.Config{
+		Path:  os.Getenv("EXPERIMENTS_PATH"),
+		Store: handler.Store,
+		Load: func(ctx context.Context, ref string) (experiment.Predictor, error) {
+			return handler.LoadEngine(ctx, ref)
+		},
+		ShadowConcurrency: getEnvInt("EXPERIMENT_SHADOW_CONCURRENCY", 0),
+	})
+	if err != nil {
+		log.Fatalf("Experiments init failed: %v", err)
+	}
+	handler.Experiments = manager
+	go manager.Run(ctx)
+}
@@ -0,0 +1,54 @@
+// backend/cmd/api/explain.go
+/*
+ * Wiring for occlusion and Grad-CAM explanations.
+ *
+ * EXPLAIN_OCCLUSION_GRID (cells along the image's long side) offers the
+ * `explain_method=occlusion` explanations, which work without tiling but
+ * cost one inference per cell. Unset or 0 leaves them off.
+ *
+ * EXPLAIN_GRADCAM=true offers `explain_method=gradcam`, at the cost of a
+ * second, differentiable copy of the model in memory (see
+ * loadModelOptions). Models it cannot differentiate are explained by
+ * occlusion instead, when that is enabled.
+ */
+
+package main
+
+import (
+	"log"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/explain"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+)
+
+func setupOcclusion(handler *handlers.Handler) {
+	grid := getEnvInt("EXPLAIN_OCCLUSION_GRID", 0)
+	if grid <= 0 {
+		return
+	}
+	cfg := explain.Occlusion{Grid: grid}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid occlusion configuration: %v", err)
+	}
+	handler.Occlusion = &cfg
+	log.Printf("Occlusion explanations enabled (%d cells along the long side)", cfg.Grid)
+}
+
+func setupGradCAM(handler *handlers.Handler) {
+	if !modelOptions.GradCAM {
+		return
+	}
+	handler.GradCAM = true
+	engine, _ := handler.ServedModel()
+	if checker, ok := engine.(interface{ CheckGradCAM() error }); ok {
+		if err := checker.CheckGradCAM(); err != nil {
+			if handler.Occlusion != nil {
+				log.Printf("Grad-CAM unavailable, falling back to occlusion: %v", err)
+			} else {
+				log.Printf("Grad-CAM unavailable and occlusion is off: %v", err)
+			}
+			return
+		}
+	}
+	log.Printf("Grad-CAM explanations enabled")
+}
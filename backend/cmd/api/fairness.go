@@ -0,0 +1,49 @@
+// backend/cmd/api/fairness.go
+/*
+ * Wiring for fairness/subgroup monitoring.
+ *
+ * The on-demand report (GET /admin/fairness) is always available. Setting
+ * FAIRNESS_CHECK_INTERVAL additionally runs a background monitor that
+ * alerts on disparities beyond FAIRNESS_MAX_GAP.
+ */
+
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/fairness"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+)
+
+func setupFairness(ctx context.Context, handler *handlers.Handler) {
+	handler.Fairness = fairness.Config{
+		MaxGap:       getEnvFloat("FAIRNESS_MAX_GAP", 0.1),
+		MinGroupSize: getEnvInt("FAIRNESS_MIN_GROUP_SIZE", 30),
+	}
+
+	interval := getEnvDuration("FAIRNESS_CHECK_INTERVAL", 0)
+	if interval <= 0 {
+		return
+	}
+	monitor := &fairness.Monitor{
+		Store:      handler.Store,
+		Config:     handler.Fairness,
+		Interval:   interval,
+		Window:     getEnvDuration("FAIRNESS_WINDOW", 30*24*time.Hour),
+		WebhookURL: os.Getenv("FAIRNESS_ALERT_WEBHOOK"),
+	}
+	go monitor.Run(ctx)
+	log.Printf("Fairness monitor enabled (every %s)", interval)
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	if v, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return v
+	}
+	return fallback
+}
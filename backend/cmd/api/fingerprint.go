@@ -0,0 +1,38 @@
+// backend/cmd/api/fingerprint.go
+/*
+ * Wiring for duplicate-study detection.
+ *
+ * Enabled with DUPLICATE_DETECTION=true. Fingerprints are kept in memory
+ * and, when FINGERPRINT_INDEX_PATH is set, persisted across restarts.
+ * DUPLICATE_WINDOW also bounds the index: older fingerprints are dropped.
+ * With DUPLICATE_CROSS_TENANT a resubmission by another tenant is flagged
+ * as a duplicate without revealing the original prediction's ID.
+ */
+
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/fingerprint"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+)
+
+func setupFingerprints(handler *handlers.Handler) {
+	if !getEnvBool("DUPLICATE_DETECTION", false) {
+		return
+	}
+
+	index, err := fingerprint.NewIndex(fingerprint.IndexConfig{
+		MaxDistance: getEnvInt("DUPLICATE_MAX_DISTANCE", 4),
+		CrossTenant: getEnvBool("DUPLICATE_CROSS_TENANT", true),
+		Window:      getEnvDuration("DUPLICATE_WINDOW", 0),
+		Path:        os.Getenv("FINGERPRINT_INDEX_PATH"),
+	})
+	if err != nil {
+		log.Fatalf("Fingerprint index init failed: %v", err)
+	}
+	handler.Fingerprints = index
+	log.Printf("Duplicate detection enabled (%d studies indexed)", index.Len())
+}
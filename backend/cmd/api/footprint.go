@@ -0,0 +1,37 @@
+// backend/cmd/api/footprint.go
+/*
+ * Wiring for energy and carbon estimates.
+ *
+ * COMPUTE_POWER_WATTS, the power drawn by one busy inference worker,
+ * enables the estimates: every prediction then reports its energy use,
+ * and the stats, reports and billing events total it. DATACENTER_PUE
+ * (default 1) adds the facility's overhead and GRID_CARBON_INTENSITY
+ * (gCO2e per kWh) converts energy into emissions; unset, emissions are
+ * not reported. Compute time is totalled either way.
+ */
+
+package main
+
+import (
+	"log"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/footprint"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+)
+
+func setupFootprint(handler *handlers.Handler) {
+	watts := getEnvFloat("COMPUTE_POWER_WATTS", 0)
+	if watts <= 0 {
+		return
+	}
+	fp := footprint.Estimator{
+		Watts:           watts,
+		PUE:             getEnvFloat("DATACENTER_PUE", 1),
+		CarbonIntensity: getEnvFloat("GRID_CARBON_INTENSITY", 0),
+	}
+	if err := fp.Validate(); err != nil {
+		log.Fatalf("Invalid footprint configuration: %v", err)
+	}
+	handler.Footprint = &fp
+	log.Printf("Footprint estimates enabled (%.0f W, PUE %.2f, %.0f gCO2e/kWh)", fp.Watts, fp.PUE, fp.CarbonIntensity)
+}
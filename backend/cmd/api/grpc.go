@@ -0,0 +1,107 @@
+//go:build !edge
+
+// backend/cmd/api/grpc.go
+/*
+ * Wiring for the gRPC prediction service.
+ *
+ * GRPC_PORT enables the service (mammoscan.v1.PredictionService, see
+ * proto/mammoscan/v1/prediction.proto) on a second port, alongside the
+ * standard gRPC health service. Each study is submitted to the REST
+ * router's POST /api/v1/predict, so authentication, access policies,
+ * network rules and residency apply exactly as to REST calls; clients
+ * that must sign their requests (REQUEST_SIGNING_KEYS) cannot use it.
+ * A stream scores up to GRPC_STREAM_CONCURRENCY studies at a time
+ * (default GOMAXPROCS). GRPC_REFLECTION=true serves the reflection API
+ * for tools such as grpcurl. The port speaks plaintext HTTP/2; terminate
+ * TLS in front of it, as for the REST port.
+ *
+ * CONNECT_API=true also serves the service on the REST port, under
+ * /mammoscan.v1.PredictionService/, to Connect, gRPC-web and gRPC
+ * clients: browsers and proxies that cannot reach the gRPC port or speak
+ * native gRPC. Its PredictStream needs HTTP/2 end to end.
+ */
+
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+
+	"connectrpc.com/connect"
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/grpcapi"
+	"github.com/josephed37/mammoscan-AI/backend/internal/grpcapi/mammoscanv1"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+func setupGRPC(srv *server, router http.Handler, handler *handlers.Handler) {
+	port := os.Getenv("GRPC_PORT")
+	if port == "" {
+		return
+	}
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		log.Fatalf("gRPC listen failed: %v", err)
+	}
+	s := grpc.NewServer(grpc.MaxRecvMsgSize(maxMessageBytes(handler)))
+	mammoscanv1.RegisterPredictionServiceServer(s, predictionService(router))
+	status := health.NewServer()
+	healthpb.RegisterHealthServer(s, status)
+	if getEnvBool("GRPC_REFLECTION", false) {
+		reflection.Register(s)
+	}
+	go func() {
+		if err := s.Serve(listener); err != nil {
+			log.Fatalf("gRPC server failed: %v", err)
+		}
+	}()
+
+	srv.onShutdown(func(ctx context.Context) {
+		status.Shutdown()
+		stopped := make(chan struct{})
+		go func() {
+			s.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			s.Stop()
+		}
+	})
+	log.Printf("gRPC prediction service listening on :%s", port)
+}
+
+// setupConnect mounts the prediction service on the REST router when
+// CONNECT_API=true. It must run before the router serves.
+func setupConnect(router *gin.Engine, handler *handlers.Handler) {
+	if !getEnvBool("CONNECT_API", false) {
+		return
+	}
+	path, h := predictionService(router).ConnectHandler(connect.WithReadMaxBytes(maxMessageBytes(handler)))
+	router.POST(path+":method", gin.WrapH(h))
+	log.Printf("Connect prediction service on the REST port under %s", path)
+}
+
+// predictionService returns the prediction service, submitting each
+// study to the router's POST /api/v1/predict.
+func predictionService(router http.Handler) *grpcapi.Server {
+	backend := func(ctx context.Context, header http.Header, remoteAddr, filename string, image []byte, fields map[string]string) (int, []byte) {
+		return handlers.ServeUpload(ctx, router, "/api/v1/predict", header, remoteAddr, filename, image, fields)
+	}
+	return grpcapi.New(backend, getEnvInt("GRPC_STREAM_CONCURRENCY", runtime.GOMAXPROCS(0)))
+}
+
+// maxMessageBytes bounds an RPC message, which carries a whole study and
+// its fields.
+func maxMessageBytes(handler *handlers.Handler) int {
+	return int(handler.Uploads.MaxImageBytes) + 1<<20
+}
@@ -0,0 +1,29 @@
+//go:build edge
+
+// backend/cmd/api/grpc_edge.go
+/*
+ * The edge build serves REST only.
+ */
+
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+)
+
+func setupConnect(_ *gin.Engine, _ *handlers.Handler) {
+	if getEnvBool("CONNECT_API", false) {
+		log.Printf("CONNECT_API ignored: no gRPC service in the %s build", buildProfile)
+	}
+}
+
+func setupGRPC(_ *server, _ http.Handler, _ *handlers.Handler) {
+	if os.Getenv("GRPC_PORT") != "" {
+		log.Printf("GRPC_PORT ignored: no gRPC service in the %s build", buildProfile)
+	}
+}
@@ -0,0 +1,36 @@
+// backend/cmd/api/i18n.go
+/*
+ * Wiring for localized error messages.
+ *
+ * Error messages are translated per Accept-Language using the built-in
+ * catalog. ERROR_LOCALES_DIR adds languages or overrides wording with
+ * <lang>.json files mapping error codes to messages; ERROR_LOCALIZATION=false
+ * keeps every message in English.
+ */
+
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/i18n"
+)
+
+func setupMessages(handler *handlers.Handler) {
+	if !getEnvBool("ERROR_LOCALIZATION", true) {
+		return
+	}
+	catalog, err := i18n.New()
+	if err != nil {
+		log.Fatalf("Message catalog init failed: %v", err)
+	}
+	if dir := os.Getenv("ERROR_LOCALES_DIR"); dir != "" {
+		if err := catalog.LoadDir(dir); err != nil {
+			log.Fatalf("Message catalog init failed: %v", err)
+		}
+	}
+	handler.Messages = catalog
+	log.Printf("Error messages localized for %v", catalog.Languages())
+}
@@ -0,0 +1,41 @@
+// backend/cmd/api/journal.go
+/*
+ * Wiring for the disaster-recovery request journal.
+ *
+ * JOURNAL_DIR enables the journal: every prediction request is recorded
+ * there until answered. Put it on durable storage that survives the
+ * container. Requests left over from before a restart are reported at
+ * startup and listed by GET /admin/journal. With TENANT_KEYS_DIR (see
+ * store.go) entries are encrypted with the tenant's key.
+ */
+
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/journal"
+)
+
+func setupJournal(handler *handlers.Handler) {
+	dir := os.Getenv("JOURNAL_DIR")
+	if dir == "" {
+		return
+	}
+	j, err := journal.Open(dir, handler.TenantKeys)
+	if err != nil {
+		log.Fatalf("Request journal init failed: %v", err)
+	}
+	handler.Journal = j
+	pending, err := j.Pending()
+	if err != nil {
+		log.Fatalf("Request journal init failed: %v", err)
+	}
+	if len(pending) > 0 {
+		log.Printf("Request journal: %d request(s) received before the last shutdown were never answered; see GET /admin/journal", len(pending))
+	} else {
+		log.Printf("Request journal enabled in %s", dir)
+	}
+}
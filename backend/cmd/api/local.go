@@ -0,0 +1,50 @@
+// backend/cmd/api/local.go
+/*
+ * Defaults and guards for the local (on-prem) build profile.
+ *
+ * On-prem installs forbid outbound cloud calls, so in this profile every
+ * piece of state lives under LOCAL_DATA_DIR: the prediction journal, the
+ * retained images and the billing/audit log. Remote sinks are rejected at
+ * startup rather than silently attempted.
+ */
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// applyLocalProfile fills unset storage settings with paths under
+// LOCAL_DATA_DIR and refuses any remote storage or audit sink.
+func applyLocalProfile() error {
+	dir := getEnv("LOCAL_DATA_DIR", "/var/lib/mammoscan")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("data directory: %w", err)
+	}
+
+	defaults := map[string]string{
+		"PREDICTION_STORE_PATH":  filepath.Join(dir, "predictions.jsonl"),
+		"IMAGE_STORE_DIR":        filepath.Join(dir, "images"),
+		"FINGERPRINT_INDEX_PATH": filepath.Join(dir, "fingerprints.jsonl"),
+		"BILLING_SINK":           "file://" + filepath.Join(dir, "usage.jsonl"),
+		"AUDIT_LOG_DEST":         "file://" + filepath.Join(dir, "audit.jsonl"),
+	}
+	for key, value := range defaults {
+		if os.Getenv(key) == "" {
+			os.Setenv(key, value)
+		}
+	}
+
+	for _, key := range []string{"PREDICTION_STORE_PATH", "IMAGE_STORE_DIR", "FINGERPRINT_INDEX_PATH", "BILLING_SINK", "AUDIT_LOG_DEST"} {
+		if v := os.Getenv(key); strings.Contains(v, "://") && !strings.HasPrefix(v, "file://") {
+			return fmt.Errorf("%s=%q is remote; the local profile only allows local paths", key, v)
+		}
+	}
+
+	log.Printf("Local profile: state stored under %s", dir)
+	return nil
+}
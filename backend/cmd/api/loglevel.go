@@ -0,0 +1,32 @@
+// backend/cmd/api/loglevel.go
+/*
+ * Wiring for the log level.
+ *
+ * LOG_LEVEL=debug (default info) adds debug records to the log (see
+ * logging.go), among them the decoded study and the per-channel input
+ * tensor statistics of every prediction, keyed by prediction ID. They cost a pass over each input and contain no
+ * patient data, but are verbose; enable them while investigating.
+ */
+
+package main
+
+import (
+	"log"
+	"log/slog"
+	"strings"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/logging"
+)
+
+func setupLogLevel(handler *handlers.Handler) {
+	switch level := strings.ToLower(getEnv("LOG_LEVEL", "info")); level {
+	case "info":
+	case "debug":
+		logging.Level.Set(slog.LevelDebug)
+		handler.DebugTensors = true
+		log.Printf("Debug logging enabled: tensor statistics are logged for every prediction")
+	default:
+		log.Fatalf("Invalid LOG_LEVEL %q (want info or debug)", level)
+	}
+}
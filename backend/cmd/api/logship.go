@@ -0,0 +1,56 @@
+// backend/cmd/api/logship.go
+/*
+ * Wiring for log forwarding.
+ *
+ * LOG_FORWARD_URL (syslog://, syslog+tcp://, syslog+tls://, fluentd:// or
+ * fluentd+tls://) ships the service log to a collector. Up to
+ * LOG_FORWARD_BUFFER records (default 10000) are held while it is slow or
+ * down; LOG_FORWARD_OVERFLOW chooses what happens beyond that: "block"
+ * (the default) holds up logging callers, "drop" discards and counts.
+ * Records are sent in batches of LOG_FORWARD_BATCH_SIZE (default 100) at
+ * least every LOG_FORWARD_FLUSH_INTERVAL (default 1s). The log is also
+ * written to stderr unless LOG_FORWARD_STDERR=false. ACCESS_LOG_DEST=forward
+ * sends access logs the same way, tagged "access".
+ */
+
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/logship"
+)
+
+// logForwarder is set when log forwarding is enabled.
+var logForwarder *logship.Forwarder
+
+func setupLogForwarding() {
+	dest := os.Getenv("LOG_FORWARD_URL")
+	if dest == "" {
+		return
+	}
+	cfg := logship.Config{
+		BufferSize:    getEnvInt("LOG_FORWARD_BUFFER", 10000),
+		BatchSize:     getEnvInt("LOG_FORWARD_BATCH_SIZE", 100),
+		FlushInterval: getEnvDuration("LOG_FORWARD_FLUSH_INTERVAL", time.Second),
+		Overflow:      getEnv("LOG_FORWARD_OVERFLOW", logship.Block),
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid LOG_FORWARD_OVERFLOW: %v", err)
+	}
+	sink, err := logship.Open(dest, logship.SinkOptions{App: "mammoscan"})
+	if err != nil {
+		log.Fatalf("Invalid LOG_FORWARD_URL: %v", err)
+	}
+	logForwarder = logship.New(sink, cfg)
+
+	var out io.Writer = logForwarder.Writer("app")
+	if getEnvBool("LOG_FORWARD_STDERR", true) {
+		out = io.MultiWriter(os.Stderr, out)
+	}
+	log.SetOutput(out)
+	log.Printf("Log forwarding to %s (overflow policy %s)", dest, cfg.Overflow)
+}
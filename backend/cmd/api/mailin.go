@@ -0,0 +1,84 @@
+//go:build !edge
+
+// backend/cmd/api/mailin.go
+/*
+ * Wiring for the email-in submission gateway.
+ *
+ * EMAIL_IN_IMAP_ADDR (host:port, implicit TLS) enables the gateway. It
+ * logs in as EMAIL_IN_IMAP_USER / EMAIL_IN_IMAP_PASSWORD, watches
+ * EMAIL_IN_MAILBOX every EMAIL_IN_INTERVAL and scores images sent by
+ * EMAIL_IN_ALLOWED_SENDERS (addresses or @domains, required) for
+ * EMAIL_IN_TENANT. Replies go out through EMAIL_IN_SMTP_ADDR (default
+ * REPORT_SMTP_ADDR) from EMAIL_IN_FROM. DKIM is required unless
+ * EMAIL_IN_SKIP_DKIM=true.
+ */
+
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/mailin"
+)
+
+func setupMailIn(ctx context.Context, handler *handlers.Handler) {
+	addr := os.Getenv("EMAIL_IN_IMAP_ADDR")
+	if addr == "" {
+		return
+	}
+	tenant := os.Getenv("EMAIL_IN_TENANT")
+	if err := handler.Residency.CheckTenant(tenant); err != nil {
+		log.Fatalf("Email gateway: %v", err)
+	}
+	smtpAddr := getEnv("EMAIL_IN_SMTP_ADDR", os.Getenv("REPORT_SMTP_ADDR"))
+	if smtpAddr == "" {
+		log.Fatalf("Email gateway init failed: EMAIL_IN_SMTP_ADDR is required to reply")
+	}
+	// Submitted scans pass through both mail servers.
+	if err := handler.Residency.CheckBackend("EMAIL_IN_IMAP_ADDR", "imaps://"+addr); err != nil {
+		log.Fatalf("Residency policy: %v", err)
+	}
+	if err := handler.Residency.CheckBackend("EMAIL_IN_SMTP_ADDR", "smtp://"+smtpAddr); err != nil {
+		log.Fatalf("Residency policy: %v", err)
+	}
+
+	user := os.Getenv("EMAIL_IN_IMAP_USER")
+	mailbox := mailin.NewIMAP(mailin.IMAPConfig{
+		Addr:            addr,
+		Username:        user,
+		Password:        getSecret("EMAIL_IN_IMAP_PASSWORD"),
+		Mailbox:         os.Getenv("EMAIL_IN_MAILBOX"),
+		MaxMessageBytes: int64(getEnvInt("EMAIL_IN_MAX_MESSAGE_BYTES", 0)),
+	})
+	sender := &mailin.SMTP{
+		Addr:     smtpAddr,
+		Username: getEnv("EMAIL_IN_SMTP_USER", os.Getenv("REPORT_SMTP_USER")),
+		Password: getSecret(smtpPasswordKey()),
+	}
+	gateway, err := mailin.New(mailbox, sender, mailin.Config{
+		Interval:       getEnvDuration("EMAIL_IN_INTERVAL", 0),
+		From:           getEnv("EMAIL_IN_FROM", user),
+		Allowed:        splitList(os.Getenv("EMAIL_IN_ALLOWED_SENDERS")),
+		SkipDKIM:       getEnvBool("EMAIL_IN_SKIP_DKIM", false),
+		MaxAttachments: getEnvInt("EMAIL_IN_MAX_ATTACHMENTS", 0),
+		Disclaimer:     reportDisclaimer(handler.Disclaimers),
+	}, func(ctx context.Context, filename string, data []byte) (int, []byte) {
+		return handler.Ingest(ctx, tenant, filename, data, nil)
+	})
+	if err != nil {
+		log.Fatalf("Email gateway init failed: %v", err)
+	}
+	go gateway.Run(ctx)
+}
+
+// smtpPasswordKey shares the report mailer's credentials unless the
+// gateway has its own.
+func smtpPasswordKey() string {
+	if os.Getenv("EMAIL_IN_SMTP_USER") != "" {
+		return "EMAIL_IN_SMTP_PASSWORD"
+	}
+	return "REPORT_SMTP_PASSWORD"
+}
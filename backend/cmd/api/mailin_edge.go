@@ -0,0 +1,22 @@
+//go:build edge
+
+// backend/cmd/api/mailin_edge.go
+/*
+ * The edge build runs disconnected and has no email gateway.
+ */
+
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+)
+
+func setupMailIn(_ context.Context, _ *handlers.Handler) {
+	if os.Getenv("EMAIL_IN_IMAP_ADDR") != "" {
+		log.Printf("EMAIL_IN_IMAP_ADDR ignored: no email gateway in the %s build", buildProfile)
+	}
+}
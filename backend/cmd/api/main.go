@@ -16,74 +16,300 @@ package main
 import (
 	"context"
 	"fmt"
+	"image"
 	"io"
 	"log"
-	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
-	"cloud.google.com/go/storage"
-	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/events"
 	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
 	"github.com/josephed37/mammoscan-AI/backend/internal/inference"
+	"github.com/josephed37/mammoscan-AI/backend/internal/jobs"
+	"github.com/josephed37/mammoscan-AI/backend/internal/jsonenc"
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+	"github.com/josephed37/mammoscan-AI/backend/internal/stats"
 )
 
-func downloadFromGCS(ctx context.Context, bucket, object, dest string) error {
-	client, err := storage.NewClient(ctx)
-	if err != nil {
-		return fmt.Errorf("storage client: %w", err)
+func main() {
+	// ctx stops background work once the server has drained.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	setupLogForwarding()
+	setupLogging()
+	log.Printf("Starting MammoScan API (%s build)", buildProfile)
+	setupCPU()
+
+	if localBuild {
+		if err := applyLocalProfile(); err != nil {
+			log.Fatalf("Local profile init failed: %v", err)
+		}
 	}
-	defer client.Close()
+	// The probes answer while the model is fetched and loaded.
+	srv := startServer(getEnv("PORT", "8080"))
+	// Model stores may take their credentials from secret references.
+	setupSecrets(ctx)
 
-	os.MkdirAll(filepath.Dir(dest), 0755)
-	
-	rc, err := client.Bucket(bucket).Object(object).NewReader(ctx)
-	if err != nil {
-		return fmt.Errorf("object reader: %w", err)
+	// fetchModel and readModel are provided by the build profile: the
+	// standard build fetches the model from its store (MODEL_URI), the
+	// edge and local builds only read a local file. In memory mode nothing is written to disk.
+	modelOptions = loadModelOptions()
+	var modelPath, modelSource string
+	var inferenceEngine inference.Engine
+	if getEnvBool("MODEL_IN_MEMORY", false) {
+		data, source, err := readModel(ctx)
+		if err != nil {
+			log.Fatalf("Model fetch failed: %v", err)
+		}
+		modelSource = source
+		opts, err := optionsFor(ctx, strings.TrimPrefix(source, "file://"))
+		if err != nil {
+			log.Fatalf("Load model failed: %v", err)
+		}
+		if inferenceEngine, err = inference.LoadBytes(data, opts); err != nil {
+			log.Fatalf("Load model failed: %v", err)
+		}
+	} else {
+		path, source, err := fetchModel(ctx)
+		if err != nil {
+			log.Fatalf("Model fetch failed: %v", err)
+		}
+		modelPath, modelSource = path, source
+		opts, err := optionsFor(ctx, strings.TrimPrefix(source, "file://"))
+		if err != nil {
+			log.Fatalf("Load model failed: %v", err)
+		}
+		if inferenceEngine, err = inference.Load(modelPath, opts); err != nil {
+			log.Fatalf("Load model failed: %v", err)
+		}
 	}
-	defer rc.Close()
 
-	f, err := os.Create(dest)
-	if err != nil {
-		return fmt.Errorf("create file: %w", err)
+	log.Println("✅ Model loaded successfully")
+	for _, o := range inferenceEngine.Optimizations() {
+		log.Printf("Graph optimization %s: %d rewrite(s)", o.Pass, o.Rewrites)
+	}
+	if v := inferenceEngine.Verification(); v != nil {
+		log.Printf("Golden set: %d input(s), max deviation %g (tolerance %g)", v.Samples, v.MaxDeviation, v.Tolerance)
 	}
-	defer f.Close()
 
-	if _, err := io.Copy(f, rc); err != nil {
-		return fmt.Errorf("copy: %w", err)
+	handler := handlers.NewHandler(inferenceEngine)
+	handler.BuildProfile = buildProfile
+	handler.Model.Source = modelSource
+	handler.Model.Path = modelPath
+	handler.Model.LoadedAt = time.Now().UTC()
+	inferenceEngine.Describe(&handler.Model)
+	handler.DecodeOptions.MultiFrame = preprocess.MultiFramePolicy(getEnv("MULTI_FRAME_POLICY", string(preprocess.RejectMultiFrame)))
+	if p := handler.DecodeOptions.MultiFrame; p != preprocess.RejectMultiFrame && p != preprocess.FirstFrame {
+		log.Fatalf("Invalid MULTI_FRAME_POLICY %q (want %q or %q)", p, preprocess.RejectMultiFrame, preprocess.FirstFrame)
+	}
+	handler.DecodeOptions.Color = preprocess.ColorPolicy(getEnv("ICC_POLICY", string(preprocess.ConvertICC)))
+	if p := handler.DecodeOptions.Color; p != preprocess.ConvertICC && p != preprocess.StripICC {
+		log.Fatalf("Invalid ICC_POLICY %q (want %q or %q)", p, preprocess.ConvertICC, preprocess.StripICC)
+	}
+	handler.DecodeOptions.Orientation = preprocess.OrientationPolicy(getEnv("ORIENTATION_POLICY", string(preprocess.OrientAuto)))
+	switch handler.DecodeOptions.Orientation {
+	case preprocess.OrientNone, preprocess.OrientEXIF, preprocess.OrientAuto:
+	default:
+		log.Fatalf("Invalid ORIENTATION_POLICY %q (want none, exif or auto)", handler.DecodeOptions.Orientation)
 	}
+	handler.DecodeOptions.Laterality = preprocess.LateralityPolicy(getEnv("LATERALITY_POLICY", string(preprocess.LateralityNone)))
+	if p := handler.DecodeOptions.Laterality; p != preprocess.LateralityNone && p != preprocess.LateralityFlip {
+		log.Fatalf("Invalid LATERALITY_POLICY %q (want %q or %q)", p, preprocess.LateralityNone, preprocess.LateralityFlip)
+	}
+	handler.Events = events.NewBus()
+	handler.Stats = stats.New(stats.Config{
+		Window:        getEnvInt("STATS_WINDOW", 0),
+		ReferenceSize: getEnvInt("DRIFT_REFERENCE_SIZE", 0),
+		Encoder:       jsonenc.Codec,
+		OnDrift:       publishDrift(handler.Events),
+	})
+	handler.Jobs = jobs.NewManager(getEnvInt("JOB_CONCURRENCY", 1))
+	handler.Jobs.OnFinish = publishJobFailures(handler.Events)
+	// Asynchronous predictions get their own workers and a bounded queue.
+	handler.PredictionJobs = jobs.NewManager(getEnvInt("PREDICTION_JOB_WORKERS", 2))
+	handler.PredictionJobs.MaxQueued = getEnvInt("PREDICTION_JOB_QUEUE_DEPTH", 100)
+	handler.PredictionJobs.OnFinish = publishJobFailures(handler.Events)
+	handler.LoadEngine = loadEngine
+	setupModelMemory(handler, inferenceEngine)
+	setupModelVersion(ctx, handler)
+	setupThresholds(ctx, handler)
+	handler.StreamSources = splitList(os.Getenv("STREAM_SOURCES"))
+	handler.StreamMaxDuration = getEnvDuration("STREAM_MAX_DURATION", 0)
+	setupLogLevel(handler)
+	setupUploads(handler)
+	setupValidation(handler)
+	setupBatching(handler)
+	setupResidency(handler)
+	setupNetworkPolicy(handler)
+	setupAccess(ctx, handler)
+	setupAuditLog(ctx, handler)
+	setupEncryption(handler)
+	setupDependencies(ctx, handler)
+	setupStore(ctx, handler)
+	setupOOD(handler)
+	setupTiling(handler)
+	setupTTA(handler)
+	setupOcclusion(handler)
+	setupGradCAM(handler)
+	setupEnsemble(ctx, handler)
+	setupExperiments(ctx, handler)
+	setupFairness(ctx, handler)
+	setupOffline(ctx, handler)
+	setupFingerprints(handler)
+	setupJournal(handler)
+	setupWebhooks(handler)
+	setupFootprint(handler)
+	setupBilling(handler)
+	setupDisclaimers(handler)
+	setupMessages(handler)
+	setupReports(ctx, handler)
+	setupSelfCheck(ctx, handler)
+	setupModelSwap(ctx, handler)
+	setupModelAge(ctx, handler)
+	setupDropFolder(ctx, handler)
+	setupMailIn(ctx, handler)
+	// Faults are injected only once the self-check has passed.
+	setupChaos(handler)
+
+	router := newRouter(requestLog(), setupAccessLog())
+	registerRoutes(router, handler)
+	setupConnect(router, handler)
+	srv.serve(router)
+	setupGRPC(srv, router, handler)
+	handler.SetReady()
+	log.Printf("Server ready")
 
-	log.Printf("Downloaded gs://%s/%s to %s", bucket, object, dest)
-	return nil
+	srv.waitForShutdown(handler)
+	cancel()
+	if closer, ok := handler.Store.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Printf("Prediction store close failed: %v", err)
+		}
+	}
 }
 
-func main() {
-	ctx := context.Background()
-	
-	bucket := getEnv("MODEL_GCS_BUCKET", "mammoscan-ai-models")
-	object := getEnv("MODEL_GCS_OBJECT", "champion_model.onnx")
-	modelPath := getEnv("MODEL_PATH", "/tmp/champion_model.onnx")
+// modelOptions applies to every model the service loads, with the golden
+// set and preprocessing profile of each model (see optionsFor).
+var modelOptions inference.Options
 
-	log.Printf("Downloading model from gs://%s/%s", bucket, object)
-	if err := downloadFromGCS(ctx, bucket, object, modelPath); err != nil {
-		log.Fatalf("Download failed: %v", err)
-	}
+// goldenImages is the golden set, preprocessed for each model as it is
+// loaded.
+var goldenImages []image.Image
 
-	inferenceEngine, err := inference.NewONNXInference(modelPath)
+// loadModelOptions reads INFERENCE_BACKEND: "gorgonnx" (the default) or
+// "onnxruntime", whose shared library may be named by ONNXRUNTIME_LIB;
+// INFERENCE_WORKERS, the number of gorgonnx instances scoring
+// concurrently (default 1), and INFERENCE_WORKER_WAIT, how long a
+// prediction waits for one before it is refused with 503; INFERENCE_CPUS,
+// ORT_INTRA_OP_THREADS and ORT_INTER_OP_THREADS (see cpu.go);
+// MODEL_OPTIMIZATIONS: "none" (the default), "all"
+// or a comma-separated list of graph optimization passes, and
+// MODEL_PRECISION ("fp32" or "fp16"); a precision the backend cannot run
+// stops startup rather than silently running in fp32. Transformed models
+// are checked against the original on the golden set: the images in
+// MODEL_GOLDEN_SET, or a synthetic image, within MODEL_VERIFY_TOLERANCE.
+// EXPLAIN_GRADCAM loads a differentiable copy of each model for Grad-CAM
+// explanations.
+func loadModelOptions() inference.Options {
+	backend, err := inference.ParseBackend(os.Getenv("INFERENCE_BACKEND"))
 	if err != nil {
-		log.Fatalf("Load model failed: %v", err)
+		log.Fatalf("Invalid INFERENCE_BACKEND: %v", err)
 	}
+	passes, err := inference.ParsePasses(getEnv("MODEL_OPTIMIZATIONS", "none"))
+	if err != nil {
+		log.Fatalf("Invalid MODEL_OPTIMIZATIONS: %v", err)
+	}
+	precision, err := inference.ParsePrecision(os.Getenv("MODEL_PRECISION"))
+	if err != nil {
+		log.Fatalf("Invalid MODEL_PRECISION: %v", err)
+	}
+	if err := inference.CheckPrecision(backend, precision); err != nil {
+		log.Fatalf("Invalid MODEL_PRECISION: %v", err)
+	}
+	workers := getEnvInt("INFERENCE_WORKERS", 1)
+	if workers < 1 {
+		log.Fatalf("Invalid INFERENCE_WORKERS: must be at least 1")
+	}
+	if goldenImages, err = loadGoldenSet(os.Getenv("MODEL_GOLDEN_SET")); err != nil {
+		log.Fatalf("Invalid MODEL_GOLDEN_SET: %v", err)
+	}
+	return inference.Options{
+		Backend:        backend,
+		RuntimeLibrary: os.Getenv("ONNXRUNTIME_LIB"),
+		Optimizations:  passes,
+		Precision:      precision,
+		Tolerance:      getEnvFloat("MODEL_VERIFY_TOLERANCE", inference.DefaultTolerance),
+		GradCAM:        getEnvBool("EXPLAIN_GRADCAM", false),
+		Workers:        workers,
+		WorkerWait:     getEnvDuration("INFERENCE_WORKER_WAIT", inference.DefaultWorkerWait),
+		CPUs:           inferenceCPUs(),
+		IntraOpThreads: ortThreads("ORT_INTRA_OP_THREADS"),
+		InterOpThreads: ortThreads("ORT_INTER_OP_THREADS"),
+	}
+}
 
-	log.Println("✅ Model loaded successfully")
-
-	handler := handlers.NewHandler(inferenceEngine)
-	router := gin.Default()
-	router.GET("/healthy", handler.HealthCheck)
-	router.POST("/api/v1/predict", handler.Predict)
+// loadGoldenSet decodes every image in dir. Without a directory the
+// golden set is a single mid-gray image, which catches broken rewrites but
+// says little about accuracy.
+func loadGoldenSet(dir string) ([]image.Image, error) {
+	if dir == "" {
+		img := image.NewGray(image.Rect(0, 0, 256, 256))
+		for i := range img.Pix {
+			img.Pix[i] = 128
+		}
+		return []image.Image{img}, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var golden []image.Image
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		img, err := preprocess.DecodeImageBytes(data, preprocess.DefaultOptions())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		golden = append(golden, img)
+	}
+	if len(golden) == 0 {
+		return nil, fmt.Errorf("no images in %s", dir)
+	}
+	return golden, nil
+}
 
-	port := getEnv("PORT", "8080")
-	log.Printf("Server starting on :%s", port)
-	http.ListenAndServe(":"+port, router)
+// loadEngine fetches an additional model by reference and loads it.
+func loadEngine(ctx context.Context, ref string) (handlers.Predictor, error) {
+	var engine inference.Engine
+	opts, err := optionsFor(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if getEnvBool("MODEL_IN_MEMORY", false) {
+		var data []byte
+		if data, err = readModelRef(ctx, ref); err == nil {
+			engine, err = inference.LoadBytes(data, opts)
+		}
+	} else {
+		var path string
+		if path, err = fetchModelRef(ctx, ref); err == nil {
+			engine, err = inference.Load(path, opts)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return engine, nil
 }
 
 func getEnv(key, fallback string) string {
@@ -91,4 +317,25 @@ func getEnv(key, fallback string) string {
 		return v
 	}
 	return fallback
-}
\ No newline at end of file
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	if v, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return v
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return v
+	}
+	return fallback
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if v, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,46 @@
+// backend/cmd/api/memory.go
+/*
+ * Wiring for the model memory budget.
+ *
+ * Every loaded model is measured after its warm-up run. With
+ * MODEL_MEMORY_BUDGET_BYTES set, additional models (ensemble members,
+ * experiment candidates, re-scoring models) that would take the total
+ * over the budget are refused; the served model alone must fit or the
+ * service does not start.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/inference"
+)
+
+func setupModelMemory(handler *handlers.Handler, engine inference.Engine) {
+	budget := inference.NewMemoryBudget(int64(getEnvInt("MODEL_MEMORY_BUDGET_BYTES", 0)))
+	if err := budget.Admit(handler.Model.Name, engine); err != nil {
+		log.Fatalf("Model memory: %v", err)
+	}
+	log.Printf("Model memory: %s holds %d bytes", handler.Model.Name, engine.MemoryBytes())
+
+	load := handler.LoadEngine
+	handler.LoadEngine = func(ctx context.Context, ref string) (handlers.Predictor, error) {
+		p, err := load(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		engine, ok := p.(inference.Engine)
+		if !ok {
+			return nil, fmt.Errorf("model %s: unexpected engine type %T", ref, p)
+		}
+		if err := budget.Admit(ref, engine); err != nil {
+			return nil, err
+		}
+		return engine, nil
+	}
+	handler.ModelMemory = budget
+}
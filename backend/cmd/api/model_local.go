@@ -0,0 +1,81 @@
+//go:build edge || local
+
+// backend/cmd/api/model_local.go
+/*
+ * Model source for the edge and local builds (`-tags edge`, `-tags local`).
+ *
+ * Mobile screening vans and on-prem hospital installs run without cloud
+ * access, so these profiles never link the GCS client. The model must
+ * already be on local disk, typically baked into the image or copied onto
+ * the device.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// fetchModel verifies that the local model file exists and returns its path.
+func fetchModel(ctx context.Context) (string, string, error) {
+	modelPath := getEnv("MODEL_PATH", "models/saved_models/champion_model.onnx")
+
+	info, err := os.Stat(modelPath)
+	if err != nil {
+		return "", "", fmt.Errorf("local model not available: %w", err)
+	}
+	if info.IsDir() {
+		return "", "", fmt.Errorf("model path %s is a directory", modelPath)
+	}
+
+	log.Printf("Using local model %s (%d bytes)", modelPath, info.Size())
+	return modelPath, "file://" + modelPath, nil
+}
+
+// fetchModelRef resolves an additional model reference. These builds have
+// no remote storage, so only local paths are accepted.
+func fetchModelRef(ctx context.Context, ref string) (string, error) {
+	if strings.Contains(ref, "://") {
+		return "", fmt.Errorf("remote model %q not available in the %s build", ref, buildProfile)
+	}
+	return ref, nil
+}
+
+// modelVersion identifies the current version of a local model file.
+func modelVersion(ctx context.Context, ref string) (string, error) {
+	return localModelVersion(ref)
+}
+
+// modelBuiltAt returns the modification time of a local model file.
+func modelBuiltAt(ctx context.Context, ref string) (time.Time, error) {
+	return localModelBuiltAt(ref)
+}
+
+// readModelMetadata reads the metadata sidecar next to a local model.
+func readModelMetadata(ctx context.Context, ref string) ([]byte, bool, error) {
+	return localModelMetadata(ref)
+}
+
+// readModel reads the local model file into memory.
+func readModel(ctx context.Context) ([]byte, string, error) {
+	path, source, err := fetchModel(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := os.ReadFile(path)
+	return data, source, err
+}
+
+// readModelRef reads an additional local model into memory.
+func readModelRef(ctx context.Context, ref string) ([]byte, error) {
+	path, err := fetchModelRef(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,193 @@
+//go:build !edge && !local
+
+// backend/cmd/api/model_remote.go
+/*
+ * Model source for the standard (cloud) build.
+ *
+ * The model artifact is fetched from MODEL_URI at startup, whose scheme
+ * picks the store (see internal/modelstore): gs://, s3://, azblob://,
+ * https://, or a local path or file:// URI. Without MODEL_URI the object
+ * MODEL_GCS_OBJECT in MODEL_GCS_BUCKET is used. Remote artifacts are
+ * downloaded to MODEL_PATH (default /tmp/<name>); downloads resume where
+ * they broke off (MODEL_DOWNLOAD_ATTEMPTS tries with backoff, within
+ * MODEL_DOWNLOAD_TIMEOUT). With MODEL_IN_MEMORY=true the artifact is
+ * streamed into memory instead, for read-only root filesystems.
+ * Compressed `.onnx.zst` objects are stored as downloaded and only
+ * decompressed when the model is loaded.
+ *
+ * Artifacts are checked against the checksums their store records and,
+ * for the startup model, against MODEL_SHA256 when set. MODEL_S3_PATH_STYLE
+ * enables path-style S3 addressing for S3-compatible stores, and
+ * MODEL_HTTP_AUTHORIZATION is sent as the Authorization header of HTTPS
+ * fetches. Additional models (rollback, swaps, comparisons) may be named
+ * by any of these URIs too.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/modelfetch"
+	"github.com/josephed37/mammoscan-AI/backend/internal/modelstore"
+)
+
+const (
+	buildProfile = "standard"
+	edgeBuild    = false
+	localBuild   = false
+)
+
+// modelStore is built on first use; each store connects when a model on
+// it is first fetched.
+var modelStore = sync.OnceValue(func() *modelstore.Store {
+	web := &modelstore.HTTP{Authorization: getSecret("MODEL_HTTP_AUTHORIZATION")}
+	return modelstore.New(map[string]modelstore.Fetcher{
+		"gs":     &modelstore.GCS{},
+		"s3":     &modelstore.S3{UsePathStyle: getEnvBool("MODEL_S3_PATH_STYLE", false)},
+		"azblob": &modelstore.Azure{},
+		"https":  web,
+	}, downloadOptions())
+})
+
+// fetchModel fetches the configured model and returns the local path it
+// was written to along with a URI describing where it came from. An
+// interrupt or SIGTERM during the download aborts it.
+func fetchModel(ctx context.Context) (string, string, error) {
+	ctx, cancel := modelDownloadContext(ctx)
+	defer cancel()
+
+	uri := modelURI()
+	dest := getEnv("MODEL_PATH", filepath.Join("/tmp", artifactName(uri)))
+	log.Printf("Fetching model from %s", modelstore.Redact(uri))
+	modelPath, err := modelStore().Fetch(ctx, uri, dest, os.Getenv("MODEL_SHA256"))
+	if err != nil {
+		return "", "", fmt.Errorf("download failed: %w", err)
+	}
+	return modelPath, modelSourceURI(uri), nil
+}
+
+// readModel streams the configured model into memory without touching
+// the filesystem.
+func readModel(ctx context.Context) ([]byte, string, error) {
+	ctx, cancel := modelDownloadContext(ctx)
+	defer cancel()
+
+	uri := modelURI()
+	log.Printf("Reading model from %s into memory", modelstore.Redact(uri))
+	data, err := modelStore().Read(ctx, uri, os.Getenv("MODEL_SHA256"))
+	if err != nil {
+		return nil, "", fmt.Errorf("download failed: %w", err)
+	}
+	return data, modelSourceURI(uri), nil
+}
+
+// fetchModelRef resolves an additional model reference to a local path,
+// downloading remote URIs into the temp directory first.
+func fetchModelRef(ctx context.Context, ref string) (string, error) {
+	dest := ref
+	if _, local := modelstore.LocalPath(ref); !local {
+		u, err := url.Parse(ref)
+		if err != nil {
+			return "", errors.New("invalid model URI")
+		}
+		dest = filepath.Join(os.TempDir(), "mammoscan-models", u.Scheme, u.Host, filepath.FromSlash(path.Clean("/"+u.Path)))
+	}
+	return modelStore().Fetch(ctx, ref, dest, "")
+}
+
+// readModelRef is fetchModelRef for in-memory loading.
+func readModelRef(ctx context.Context, ref string) ([]byte, error) {
+	return modelStore().Read(ctx, ref, "")
+}
+
+// modelVersion identifies the current version of a model: the object
+// generation for gs:// URIs, the version ID or ETag for s3://, the ETag
+// for azblob:// and https://, the file's modification time otherwise.
+func modelVersion(ctx context.Context, ref string) (string, error) {
+	obj, err := modelStore().Stat(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return obj.Version(), nil
+}
+
+// modelBuiltAt returns when a model artifact was produced: for gs://
+// URIs the object's Custom-Time when the pipeline sets one, else when it
+// was written, as for the other stores.
+func modelBuiltAt(ctx context.Context, ref string) (time.Time, error) {
+	obj, err := modelStore().Stat(ctx, ref)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if obj.Modified().IsZero() {
+		return time.Time{}, fmt.Errorf("%s does not record when it was written", modelstore.Redact(ref))
+	}
+	return obj.Modified(), nil
+}
+
+// readModelMetadata reads the metadata sidecar published next to the
+// model at ref; ok is false when there is none.
+func readModelMetadata(ctx context.Context, ref string) (data []byte, ok bool, err error) {
+	sidecar := ref + modelMetadataSuffix
+	if u, perr := url.Parse(ref); perr == nil && u.RawQuery != "" {
+		u.Path += modelMetadataSuffix
+		sidecar = u.String()
+	}
+	data, err = modelStore().Read(ctx, sidecar, "")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	return data, err == nil, err
+}
+
+// modelURI returns MODEL_URI, or the URI of the configured GCS object.
+func modelURI() string {
+	if uri := os.Getenv("MODEL_URI"); uri != "" {
+		return uri
+	}
+	return fmt.Sprintf("gs://%s/%s", getEnv("MODEL_GCS_BUCKET", "mammoscan-ai-models"), getEnv("MODEL_GCS_OBJECT", "champion_model.onnx"))
+}
+
+// modelSourceURI is how a model fetched from uri is reported, and how it
+// is found again for swaps and sidecars.
+func modelSourceURI(uri string) string {
+	if p, local := modelstore.LocalPath(uri); local {
+		return "file://" + p
+	}
+	return uri
+}
+
+// artifactName is the file name of the artifact at uri.
+func artifactName(uri string) string {
+	if u, err := url.Parse(uri); err == nil && u.Path != "" {
+		return path.Base(u.Path)
+	}
+	return path.Base(uri)
+}
+
+// modelDownloadContext bounds the startup download and aborts it on an
+// interrupt or SIGTERM.
+func modelDownloadContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	ctx, cancel := context.WithTimeout(ctx, getEnvDuration("MODEL_DOWNLOAD_TIMEOUT", time.Hour))
+	return ctx, func() { cancel(); stop() }
+}
+
+func downloadOptions() modelfetch.Options {
+	opts := modelfetch.DefaultOptions()
+	opts.Attempts = getEnvInt("MODEL_DOWNLOAD_ATTEMPTS", opts.Attempts)
+	return opts
+}
@@ -0,0 +1,75 @@
+// backend/cmd/api/modelage.go
+/*
+ * Wiring for the stale-model guard.
+ *
+ * MODEL_MAX_AGE (e.g. "8760h" for a year) enables the guard: the health
+ * check reports DEGRADED and a model.stale alert is raised once the served
+ * model's artifact is older than that. The age is measured from the GCS
+ * object's Custom-Time (else its creation time) or a local file's
+ * modification time; MODEL_BUILT_AT (RFC 3339) overrides it for the model
+ * loaded at startup, e.g. when the file was copied onto the device. The
+ * age is checked every MODEL_AGE_CHECK_INTERVAL (default 1h).
+ * MODEL_STALE_BLOCK=true also refuses predictions while the model is
+ * stale.
+ */
+
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+)
+
+func setupModelAge(ctx context.Context, handler *handlers.Handler) {
+	maxAge := getEnvDuration("MODEL_MAX_AGE", 0)
+	if maxAge <= 0 {
+		return
+	}
+	handler.MaxModelAge = handlers.ModelAgeLimit{
+		MaxAge: maxAge,
+		Block:  getEnvBool("MODEL_STALE_BLOCK", false),
+	}
+	handler.ArtifactTime = modelBuiltAt
+
+	var built time.Time
+	var err error
+	if v := os.Getenv("MODEL_BUILT_AT"); v != "" {
+		if built, err = time.Parse(time.RFC3339, v); err != nil {
+			log.Fatalf("Invalid MODEL_BUILT_AT: %v", err)
+		}
+	} else if built, err = modelBuiltAt(ctx, strings.TrimPrefix(handler.Model.Source, "file://")); err != nil {
+		log.Printf("Model age: build time of the served model unknown, it will not be reported stale: %v", err)
+	}
+	if err == nil {
+		built = built.UTC()
+		handler.Model.BuiltAt = &built
+	}
+
+	go func() {
+		ticker := time.NewTicker(getEnvDuration("MODEL_AGE_CHECK_INTERVAL", time.Hour))
+		defer ticker.Stop()
+		for {
+			handler.CheckModelAge(time.Now())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	log.Printf("Stale-model guard enabled (maximum age %s, blocking %t)", maxAge, handler.MaxModelAge.Block)
+}
+
+// localModelBuiltAt returns the modification time of a local model file.
+func localModelBuiltAt(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
@@ -0,0 +1,119 @@
+// backend/cmd/api/modelswap.go
+/*
+ * Wiring for scheduled model swaps.
+ *
+ * MODEL_SWAP_INTERVAL (e.g. "10m") enables watching the model artifact for
+ * new versions (the GCS object generation, or a local file's modification
+ * time and size). New versions are swapped in only during the windows in
+ * MODEL_SWAP_WINDOWS, semicolon-separated cron-style expressions such as
+ * "* 1-4 * * *" (every minute from 01:00 to 04:59), read in
+ * MODEL_SWAP_TIMEZONE (default UTC); without windows a new version is
+ * swapped in as soon as it is seen. Before it serves, the new model must
+ * load within the memory budget and pass the sanity inference; the model
+ * it replaces stays in the standby slot, so POST /admin/model/revert
+ * undoes the swap, and POST /admin/models/reload swaps a new version in
+ * at once. MODEL_RETAIN_VERSIONS keeps that many more replaced versions
+ * loaded for POST /admin/models/:version/activate.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/modelswap"
+)
+
+// setupModelVersion records the served model's version, so predictions
+// report it, and how many replaced versions stay loaded.
+func setupModelVersion(ctx context.Context, handler *handlers.Handler) {
+	version, err := modelVersion(ctx, servedModelRef(handler))
+	if err != nil {
+		log.Printf("Version of the served model unknown: %v", err)
+	}
+	handler.Model.Version = version
+	handler.RetainModels = getEnvInt("MODEL_RETAIN_VERSIONS", 0)
+}
+
+// servedModelRef returns where the served model was loaded from. Local
+// sources are loaded by path; the edge and local builds refuse URIs for
+// additional models.
+func servedModelRef(handler *handlers.Handler) string {
+	return strings.TrimPrefix(handler.Model.Source, "file://")
+}
+
+func setupModelSwap(ctx context.Context, handler *handlers.Handler) {
+	interval := getEnvDuration("MODEL_SWAP_INTERVAL", 0)
+	if interval <= 0 {
+		return
+	}
+	var windows []*modelswap.Window
+	for _, spec := range strings.Split(os.Getenv("MODEL_SWAP_WINDOWS"), ";") {
+		if spec = strings.TrimSpace(spec); spec == "" {
+			continue
+		}
+		w, err := modelswap.ParseWindow(spec)
+		if err != nil {
+			log.Fatalf("Invalid MODEL_SWAP_WINDOWS: %v", err)
+		}
+		windows = append(windows, w)
+	}
+	loc, err := time.LoadLocation(getEnv("MODEL_SWAP_TIMEZONE", "UTC"))
+	if err != nil {
+		log.Fatalf("Invalid MODEL_SWAP_TIMEZONE: %v", err)
+	}
+
+	ref := servedModelRef(handler)
+	current := handler.Model.Version
+	if current == "" {
+		log.Printf("Model swap: version of the served model unknown, adopting the first one seen")
+	}
+
+	scheduler, err := modelswap.NewScheduler(modelswap.Config{
+		Interval: interval,
+		Windows:  windows,
+		Location: loc,
+		Current:  current,
+		Version: func(ctx context.Context) (string, error) {
+			return modelVersion(ctx, ref)
+		},
+		Swap: func(ctx context.Context, version string) error {
+			return swapModel(ctx, handler, ref, version)
+		},
+	})
+	if err != nil {
+		log.Fatalf("Model swap init failed: %v", err)
+	}
+	handler.ModelSwaps = scheduler
+	go scheduler.Run(ctx)
+}
+
+// swapModel loads and validates the model at ref and, if it passes,
+// makes it the served model. The replaced model stays in standby.
+func swapModel(ctx context.Context, handler *handlers.Handler, ref, version string) error {
+	_, served := handler.ServedModel()
+	engine, info, err := handler.LoadModel(ctx, ref, models.ModelInfo{Name: served.Name, Version: version})
+	if err != nil {
+		return err
+	}
+	info.Source = served.Source
+	handler.SwapModel(engine, info)
+	return nil
+}
+
+// localModelVersion identifies a local model file by its modification
+// time and size.
+func localModelVersion(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d-%d", info.ModTime().UnixNano(), info.Size()), nil
+}
@@ -0,0 +1,61 @@
+// backend/cmd/api/netacl.go
+/*
+ * Wiring for the network policy.
+ *
+ * NETWORK_POLICY_PATH restricts the health, api and admin endpoint groups
+ * to the CIDR allow- and denylists in that file, for installs without a
+ * firewall in front. Sending SIGHUP reloads the lists; the trusted
+ * proxies take a restart.
+ *
+ * TRUSTED_PROXIES lists the addresses or CIDR ranges of reverse proxies
+ * whose X-Forwarded-For header is believed, in addition to those of the
+ * network policy. By default none are, and the client address is the
+ * peer's.
+ */
+
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/netacl"
+)
+
+func setupNetworkPolicy(handler *handlers.Handler) {
+	path := os.Getenv("NETWORK_POLICY_PATH")
+	if path == "" {
+		return
+	}
+	engine, err := netacl.NewEngine(path)
+	if err != nil {
+		log.Fatalf("Network policy init failed: %v", err)
+	}
+	handler.Network = engine
+	log.Printf("Network policy loaded: %d group(s), %d trusted proxy range(s)", len(engine.Policy().Groups), len(engine.Policy().TrustedProxies))
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if err := engine.Reload(); err != nil {
+				log.Printf("Network policy reload failed, keeping previous policy: %v", err)
+				continue
+			}
+			log.Println("Network policy reloaded")
+		}
+	}()
+}
+
+// trustedProxies returns the proxies listed in TRUSTED_PROXIES and the
+// network policy; nil trusts none.
+func trustedProxies(handler *handlers.Handler) []string {
+	proxies := splitList(os.Getenv("TRUSTED_PROXIES"))
+	if handler.Network != nil {
+		proxies = append(proxies, handler.Network.Policy().TrustedProxies...)
+	}
+	return proxies
+}
@@ -0,0 +1,82 @@
+// backend/cmd/api/offline.go
+/*
+ * Wiring for offline-first (store-and-forward) mode.
+ *
+ * Setting OFFLINE_SPOOL_DIR makes every prediction durable on local disk,
+ * and every audit entry too, in its "audit" subdirectory. When SYNC_URL is
+ * also set, background forwarders deliver both queues to the central
+ * service whenever it becomes reachable; records carry their kind
+ * ("prediction" or "audit"). Record files that cannot be read are moved
+ * to each queue's "quarantine" subdirectory. With TENANT_KEYS_DIR (see
+ * store.go) queued predictions are encrypted with the tenant's key on
+ * disk and decrypted only to be sent.
+ */
+
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/audit"
+	"github.com/josephed37/mammoscan-AI/backend/internal/events"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/spool"
+)
+
+func setupOffline(ctx context.Context, handler *handlers.Handler) {
+	dir := os.Getenv("OFFLINE_SPOOL_DIR")
+	if dir == "" {
+		return
+	}
+
+	queue, err := spool.Open(dir)
+	if err != nil {
+		log.Fatalf("Offline spool init failed: %v", err)
+	}
+	handler.Offline = queue
+	handler.OfflineStoreImages = getEnvBool("OFFLINE_STORE_IMAGES", true)
+	log.Printf("Offline mode enabled: spooling to %s (%d pending)", dir, queue.Len())
+
+	auditQueue, err := spool.Open(filepath.Join(dir, "audit"))
+	if err != nil {
+		log.Fatalf("Offline audit spool init failed: %v", err)
+	}
+	setupOfflineAudit(handler, audit.NewOffline(auditQueue))
+
+	endpoint := os.Getenv("SYNC_URL")
+	if endpoint == "" {
+		log.Println("SYNC_URL not set; records will accumulate until a sync target is configured")
+		return
+	}
+
+	hostname, _ := os.Hostname()
+	cfg := spool.ForwarderConfig{
+		Endpoint:   endpoint,
+		AuthToken:  getSecret("SYNC_AUTH_TOKEN"),
+		DeviceID:   getEnv("DEVICE_ID", hostname),
+		Interval:   getEnvDuration("SYNC_INTERVAL", 0),
+		MaxBackoff: getEnvDuration("SYNC_MAX_BACKOFF", 0),
+		Keys:       handler.TenantKeys,
+	}
+	go spool.NewForwarder(queue, cfg).Run(ctx)
+	go spool.NewForwarder(auditQueue, cfg).Run(ctx)
+}
+
+// setupOfflineAudit queues every audit entry for the central service as
+// well as writing it to the local audit log (see audit.go). A break-glass
+// access is granted only once it is in both.
+func setupOfflineAudit(handler *handlers.Handler, offline *audit.Offline) {
+	local := handler.Audit
+	handler.Audit = func(ev events.Event) error {
+		if local != nil {
+			if err := local(ev); err != nil {
+				return err
+			}
+		}
+		return offline.Append(ev)
+	}
+	handler.Events.SubscribeSync("audit-sync", offline.Record, auditEvents...)
+}
@@ -0,0 +1,32 @@
+// backend/cmd/api/ood.go
+/*
+ * Wiring for the out-of-distribution guard.
+ *
+ * OOD_PROFILE_PATH points at a profile fitted with cmd/oodprofile; when
+ * set, uploads unlike the training mammograms are refused. OOD_MAX_DISTANCE
+ * overrides the cutoff stored in the profile.
+ */
+
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/ood"
+)
+
+func setupOOD(handler *handlers.Handler) {
+	path := os.Getenv("OOD_PROFILE_PATH")
+	if path == "" {
+		return
+	}
+	profile, err := ood.Load(path)
+	if err != nil {
+		log.Fatalf("OOD guard init failed: %v", err)
+	}
+	profile.MaxDistance = getEnvFloat("OOD_MAX_DISTANCE", profile.MaxDistance)
+	handler.OOD = profile
+	log.Printf("OOD guard enabled (fitted on %d images, max distance %.2f)", profile.Samples, profile.MaxDistance)
+}
@@ -0,0 +1,64 @@
+// backend/cmd/api/preprocessing.go
+/*
+ * Wiring for model preprocessing profiles.
+ *
+ * With MODEL_METADATA_SIDECAR=true, the "preprocessing" object of a
+ * model's "<model>.metadata.json" sidecar describes the input pipeline it
+ * was trained with: layout, size, channels, range and normalization (see
+ * internal/preprocess/profile.go). Each model, served, standby, candidate
+ * or ensemble member, is loaded with its own profile, and models without
+ * one keep the champion's pipeline. Unlike a threshold, a profile that
+ * cannot be read fails the load: a model fed the wrong pipeline scores
+ * garbage with confidence.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/inference"
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+)
+
+// optionsFor returns modelOptions for the model at ref: with its
+// preprocessing profile, and the golden set preprocessed with it.
+func optionsFor(ctx context.Context, ref string) (inference.Options, error) {
+	profile, err := modelProfile(ctx, ref)
+	if err != nil {
+		return inference.Options{}, err
+	}
+	opts := modelOptions
+	opts.Profile = profile
+	opts.Golden = nil
+	for _, img := range goldenImages {
+		opts.Golden = append(opts.Golden, profile.Tensor(img))
+	}
+	return opts, nil
+}
+
+// modelProfile returns the preprocessing profile in the metadata sidecar
+// of the model at ref, or the default profile when there is none.
+func modelProfile(ctx context.Context, ref string) (preprocess.Profile, error) {
+	if !getEnvBool("MODEL_METADATA_SIDECAR", false) {
+		return preprocess.DefaultProfile(), nil
+	}
+	data, ok, err := readModelMetadata(ctx, ref)
+	if err != nil {
+		return preprocess.Profile{}, fmt.Errorf("model metadata of %s unreadable: %w", ref, err)
+	}
+	if !ok {
+		return preprocess.DefaultProfile(), nil
+	}
+	profile, ok, err := preprocess.ParseProfile(data)
+	if err != nil {
+		return preprocess.Profile{}, fmt.Errorf("%s: %w", ref, err)
+	}
+	if ok && !profile.IsDefault() {
+		log.Printf("Preprocessing for %s: %s %dx%d, %d channel(s), range %s, mean %v, std %v",
+			ref, profile.Layout, profile.Width, profile.Height, profile.Channels, profile.Range, profile.Mean, profile.Std)
+	}
+	return profile, nil
+}
@@ -0,0 +1,14 @@
+//go:build edge
+
+// backend/cmd/api/profile_edge.go
+/*
+ * Build profile for mobile screening units (`go build -tags edge`).
+ */
+
+package main
+
+const (
+	buildProfile = "edge"
+	edgeBuild    = true
+	localBuild   = false
+)
@@ -0,0 +1,14 @@
+//go:build local && !edge
+
+// backend/cmd/api/profile_local.go
+/*
+ * Build profile for on-prem hospital installs (`go build -tags local`).
+ */
+
+package main
+
+const (
+	buildProfile = "local"
+	edgeBuild    = false
+	localBuild   = true
+)
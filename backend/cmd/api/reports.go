@@ -0,0 +1,102 @@
+// backend/cmd/api/reports.go
+/*
+ * Wiring for scheduled summary reports.
+ *
+ * REPORT_PERIODS (e.g. "daily,weekly") enables the scheduler. Reports are
+ * delivered to REPORT_WEBHOOK_URL and/or by email via REPORT_SMTP_ADDR.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/reports"
+)
+
+func setupReports(ctx context.Context, handler *handlers.Handler) {
+	periods := splitList(os.Getenv("REPORT_PERIODS"))
+	if len(periods) == 0 {
+		return
+	}
+
+	hour, minute, err := parseClock(getEnv("REPORT_TIME", "07:00"))
+	if err != nil {
+		log.Fatalf("Report scheduler init failed: %v", err)
+	}
+	weekday, err := parseWeekday(getEnv("REPORT_WEEKDAY", "monday"))
+	if err != nil {
+		log.Fatalf("Report scheduler init failed: %v", err)
+	}
+	loc, err := time.LoadLocation(getEnv("REPORT_TIMEZONE", "UTC"))
+	if err != nil {
+		log.Fatalf("Report scheduler init failed: %v", err)
+	}
+
+	var deliverers []reports.Deliverer
+	if url := os.Getenv("REPORT_WEBHOOK_URL"); url != "" {
+		deliverers = append(deliverers, &reports.Webhook{URL: url, Format: getEnv("REPORT_FORMAT", "json")})
+	}
+	if addr := os.Getenv("REPORT_SMTP_ADDR"); addr != "" {
+		deliverers = append(deliverers, &reports.Email{
+			Addr:     addr,
+			Username: os.Getenv("REPORT_SMTP_USER"),
+			Password: getSecret("REPORT_SMTP_PASSWORD"),
+			From:     getEnv("REPORT_EMAIL_FROM", "mammoscan@localhost"),
+			To:       splitList(os.Getenv("REPORT_EMAIL_TO")),
+		})
+	}
+	if len(deliverers) == 0 {
+		log.Println("REPORT_PERIODS set but no REPORT_WEBHOOK_URL or REPORT_SMTP_ADDR; reports are preview-only")
+	}
+
+	scheduler, err := reports.NewScheduler(reports.ScheduleConfig{
+		Periods:    periods,
+		Hour:       hour,
+		Minute:     minute,
+		Weekday:    weekday,
+		Location:   loc,
+		Disclaimer: reportDisclaimer(handler.Disclaimers),
+	}, handler.Stats, deliverers...)
+	if err != nil {
+		log.Fatalf("Report scheduler init failed: %v", err)
+	}
+	if len(deliverers) > 0 {
+		scheduler.Start(ctx)
+	}
+	handler.Reports = scheduler
+}
+
+// splitList parses a comma-separated environment value, dropping blanks.
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func parseClock(v string) (int, int, error) {
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q (want HH:MM)", v)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+func parseWeekday(v string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), v) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid weekday %q", v)
+}
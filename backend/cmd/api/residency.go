@@ -0,0 +1,58 @@
+// backend/cmd/api/residency.go
+/*
+ * Wiring for data-residency enforcement.
+ *
+ * RESIDENCY_POLICY_PATH enables the policy. At startup every configured
+ * backend (model source, sync target, billing sink, report and alert
+ * webhooks) is checked and the service refuses to start on a violation.
+ * At runtime, requests from tenants pinned to another region and
+ * candidate models loaded from out-of-region sources are refused.
+ */
+
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/residency"
+)
+
+func setupResidency(handler *handlers.Handler) {
+	path := os.Getenv("RESIDENCY_POLICY_PATH")
+	if path == "" {
+		return
+	}
+	policy, err := residency.Load(path)
+	if err != nil {
+		log.Fatalf("Residency policy init failed: %v", err)
+	}
+
+	backends := []struct{ name, uri string }{
+		{"model source", handler.Model.Source},
+		{"SYNC_URL", os.Getenv("SYNC_URL")},
+		{"BILLING_SINK", os.Getenv("BILLING_SINK")},
+		{"REPORT_WEBHOOK_URL", os.Getenv("REPORT_WEBHOOK_URL")},
+		{"FAIRNESS_ALERT_WEBHOOK", os.Getenv("FAIRNESS_ALERT_WEBHOOK")},
+	}
+	if addr := os.Getenv("REPORT_SMTP_ADDR"); addr != "" {
+		backends = append(backends, struct{ name, uri string }{"REPORT_SMTP_ADDR", "smtp://" + addr})
+	}
+	for _, b := range backends {
+		if err := policy.CheckBackend(b.name, b.uri); err != nil {
+			log.Fatalf("Residency policy: %v", err)
+		}
+	}
+
+	load := handler.LoadEngine
+	handler.LoadEngine = func(ctx context.Context, ref string) (handlers.Predictor, error) {
+		if err := policy.CheckBackend("model", ref); err != nil {
+			return nil, err
+		}
+		return load(ctx, ref)
+	}
+	handler.Residency = policy
+	log.Printf("Residency policy enforced for region %q (%d pinned tenant(s))", policy.Region, len(policy.Tenants))
+}
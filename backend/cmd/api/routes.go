@@ -0,0 +1,132 @@
+// backend/cmd/api/routes.go
+/*
+ * Route registration for the MammoScan AI backend API.
+ *
+ * The core routes (health and prediction) are available in every build.
+ * Optional subsystems register their routes here behind `edgeBuild` so the
+ * edge profile ships only what a disconnected screening unit needs.
+ */
+
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+)
+
+// newRouter builds the Gin engine for the active build profile. Requests
+// are logged by requestLog (see logging.go) rather than Gin's text
+// logger, and in the standard formats by accessLog when set. The edge
+// build runs Gin in release mode to keep output quiet on low-power
+// devices.
+func newRouter(requestLog, accessLog gin.HandlerFunc) *gin.Engine {
+	if edgeBuild {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	router := gin.New()
+	router.Use(gin.Recovery(), requestLog)
+	if accessLog != nil {
+		router.Use(accessLog)
+	}
+	return router
+}
+
+// registerRoutes wires every HTTP endpoint onto the router.
+func registerRoutes(router *gin.Engine, handler *handlers.Handler) {
+	router.Use(handler.Localize)
+	// Forwarded client addresses are only believed from listed proxies;
+	// with none listed X-Forwarded-For is ignored, so a caller cannot pick
+	// the address its lockouts and network policy see.
+	if err := router.SetTrustedProxies(trustedProxies(handler)); err != nil {
+		log.Fatalf("Invalid trusted proxies: %v", err)
+	}
+	health := router.Group("/", handler.RestrictNetwork(handlers.NetworkGroupHealth))
+	health.GET("/healthy", handler.HealthCheck)
+	health.GET("/ready", handler.Ready)
+	health.GET("/healthz", handler.Healthz)
+	health.GET("/selfcheck", handler.SelfCheckReport)
+
+	api := router.Group("/api/v1", handler.RestrictNetwork(handlers.NetworkGroupAPI), handler.Authorize, handler.EnforceResidency)
+	api.GET("/capabilities", handler.Capabilities)
+	api.GET("/model", handler.GetModel)
+	api.POST("/predict", handler.Predict)
+	api.POST("/predict/batch", handler.PredictBatch)
+	api.POST("/explain", handler.Explain)
+	api.POST("/compare", handler.Compare)
+	api.POST("/preprocess/debug", handler.PreprocessDebug)
+	api.GET("/predictions", handler.ListPredictions)
+	api.GET("/predictions/:id", handler.GetPrediction)
+	api.DELETE("/predictions/:id", handler.DeletePrediction)
+	api.POST("/predictions/:id/restore", handler.RestorePrediction)
+	api.POST("/predictions/:id/feedback", handler.SubmitFeedback)
+	api.GET("/encryption-key", handler.EncryptionKey)
+	api.POST("/streams/predict", handler.PredictStream)
+	api.POST("/graphql", handler.GraphQL(false))
+	api.POST("/jobs", handler.SubmitPredictionJob)
+	api.GET("/jobs/:id", handler.GetJob)
+	if handler.Access != nil {
+		api.POST("/upload-tokens", handler.IssueUploadToken)
+	}
+	if handler.Webhooks != nil {
+		api.GET("/webhooks", handler.ListWebhooks)
+		api.POST("/webhooks", handler.CreateWebhook)
+		api.GET("/webhooks/:id", handler.GetWebhook)
+		api.PATCH("/webhooks/:id", handler.UpdateWebhook)
+		api.DELETE("/webhooks/:id", handler.DeleteWebhook)
+		api.POST("/webhooks/:id/rotate-secret", handler.RotateWebhookSecret)
+	}
+
+	if edgeBuild {
+		return
+	}
+
+	// Admin APIs are only exposed when an access policy or a token has been
+	// configured. The policy, when present, governs admin routes too.
+	var adminAuth gin.HandlerFunc
+	switch {
+	case handler.Access != nil:
+		adminAuth = handler.Authorize
+	case os.Getenv("ADMIN_TOKEN") != "":
+		adminAuth = handlers.RequireAdminToken(getSecret("ADMIN_TOKEN"))
+	}
+	if adminAuth != nil {
+		admin := router.Group("/admin", handler.RestrictNetwork(handlers.NetworkGroupAdmin), adminAuth)
+		admin.GET("/overview", handler.AdminOverview)
+		admin.GET("/reports/preview", handler.ReportPreview)
+		admin.GET("/fairness", handler.FairnessReport)
+		admin.GET("/profile", handler.ProfileModel)
+		admin.GET("/model/slots", handler.ModelSlots)
+		admin.POST("/model/standby", handler.LoadStandbyModel)
+		admin.POST("/model/switch", handler.SwitchModel)
+		admin.POST("/model/revert", handler.RevertModel)
+		admin.GET("/models", handler.ListModels)
+		admin.POST("/models/reload", handler.ReloadModel)
+		admin.POST("/models/:version/activate", handler.ActivateModel)
+		admin.DELETE("/predictions/:id", handler.PurgePrediction)
+		if handler.LegalHolds != nil {
+			admin.POST("/legal-holds", handler.PlaceLegalHold)
+			admin.GET("/legal-holds", handler.ListLegalHolds)
+			admin.GET("/legal-holds/:id", handler.GetLegalHold)
+			admin.POST("/legal-holds/:id/release", handler.ReleaseLegalHold)
+		}
+		admin.POST("/jobs/rescore", handler.StartRescore)
+		admin.POST("/jobs/calibration", handler.StartCalibration)
+		admin.POST("/experiments", handler.StartExperiment)
+		admin.GET("/experiments", handler.ListExperiments)
+		admin.GET("/experiments/:id", handler.GetExperiment)
+		admin.POST("/experiments/:id/stop", handler.StopExperiment)
+		admin.GET("/jobs", handler.ListJobs)
+		admin.GET("/jobs/:id", handler.GetJob)
+		if handler.Journal != nil {
+			admin.GET("/journal", handler.ListJournal)
+			admin.POST("/journal/replay", handler.ReplayJournal)
+			admin.DELETE("/journal/:id", handler.DiscardJournalEntry)
+		}
+		admin.POST("/graphql", handler.GraphQL(true))
+	} else {
+		log.Println("None of ACCESS_POLICY_PATH, JWT_JWKS_URL or ADMIN_TOKEN set; admin APIs disabled")
+	}
+}
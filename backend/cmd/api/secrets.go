@@ -0,0 +1,81 @@
+// backend/cmd/api/secrets.go
+/*
+ * Wiring for external secret stores.
+ *
+ * Any secret environment variable (ADMIN_TOKEN, SYNC_AUTH_TOKEN,
+ * BILLING_AUTH_TOKEN, REPORT_SMTP_PASSWORD, ...) may hold a reference such
+ * as `vault:secret/data/mammoscan#admin_token` or
+ * `gcpsm:projects/p/secrets/admin-token` instead of the raw value.
+ *
+ * Vault is enabled by VAULT_ADDR, authenticating with VAULT_TOKEN or the
+ * token file at VAULT_TOKEN_FILE. GCP Secret Manager uses Application
+ * Default Credentials and is only available in the standard build.
+ * Referenced secrets are re-fetched every SECRETS_REFRESH_INTERVAL.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/secrets"
+)
+
+// secretManager resolves secret references for the setup functions. It is
+// initialised by setupSecrets before any of them run.
+var secretManager = secrets.NewManager(nil)
+
+func setupSecrets(ctx context.Context) {
+	providers := make(map[string]secrets.Provider)
+
+	if addr := os.Getenv("VAULT_ADDR"); addr != "" {
+		providers["vault"] = &secrets.Vault{
+			Addr:      addr,
+			Token:     vaultToken,
+			Namespace: os.Getenv("VAULT_NAMESPACE"),
+		}
+	}
+	if token := gcpAccessToken(ctx); token != nil {
+		providers["gcpsm"] = &secrets.GCPSecretManager{AccessToken: token}
+	}
+	secretManager = secrets.NewManager(providers)
+
+	interval := getEnvDuration("SECRETS_REFRESH_INTERVAL", 5*time.Minute)
+	if interval > 0 {
+		go secretManager.Run(ctx, interval)
+	}
+}
+
+// getSecret resolves a secret environment variable. An unset variable
+// yields a getter for "". Resolution failures are fatal: starting with a
+// missing credential would only fail later and less clearly.
+func getSecret(key string) secrets.Getter {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	getter, err := secretManager.Resolve(ctx, key, os.Getenv(key))
+	if err != nil {
+		log.Fatalf("Secret init failed: %v", err)
+	}
+	return getter
+}
+
+// vaultToken reads the Vault token from VAULT_TOKEN_FILE (re-read on every
+// call so an agent sidecar can renew it) or VAULT_TOKEN.
+func vaultToken() (string, error) {
+	if path := os.Getenv("VAULT_TOKEN_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	if token := os.Getenv("VAULT_TOKEN"); token != "" {
+		return token, nil
+	}
+	return "", errors.New("neither VAULT_TOKEN nor VAULT_TOKEN_FILE is set")
+}
@@ -0,0 +1,36 @@
+//go:build !edge && !local
+
+// backend/cmd/api/secrets_gcp.go
+/*
+ * GCP credentials for Secret Manager in the standard build.
+ */
+
+package main
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// gcpAccessToken returns a token function backed by Application Default
+// Credentials. Credentials are only looked up when a gcpsm: secret is
+// actually used, so deployments without GCP are unaffected.
+func gcpAccessToken(ctx context.Context) func(context.Context) (string, error) {
+	source := sync.OnceValues(func() (oauth2.TokenSource, error) {
+		return google.DefaultTokenSource(ctx, "https://www.googleapis.com/auth/cloud-platform")
+	})
+	return func(context.Context) (string, error) {
+		ts, err := source()
+		if err != nil {
+			return "", err
+		}
+		token, err := ts.Token()
+		if err != nil {
+			return "", err
+		}
+		return token.AccessToken, nil
+	}
+}
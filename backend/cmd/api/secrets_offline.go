@@ -0,0 +1,15 @@
+//go:build edge || local
+
+// backend/cmd/api/secrets_offline.go
+/*
+ * The edge and local builds make no cloud calls, so GCP Secret Manager is
+ * not available; Vault (typically on-prem) still is.
+ */
+
+package main
+
+import "context"
+
+func gcpAccessToken(ctx context.Context) func(context.Context) (string, error) {
+	return nil
+}
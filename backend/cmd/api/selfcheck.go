@@ -0,0 +1,106 @@
+// backend/cmd/api/selfcheck.go
+/*
+ * Wiring for the startup self-check.
+ *
+ * The self-check runs after every subsystem is set up and before the
+ * server listens. Its report is logged as one JSON line, served at
+ * GET /selfcheck and, with SELFCHECK_REPORT_PATH, written to a file. A
+ * failed self-check stops the process unless SELFCHECK_STRICT=false.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/selfcheck"
+	"github.com/josephed37/mammoscan-AI/backend/internal/store"
+)
+
+func setupSelfCheck(ctx context.Context, handler *handlers.Handler) {
+	ctx, cancel := context.WithTimeout(ctx, getEnvDuration("SELFCHECK_TIMEOUT", time.Minute))
+	defer cancel()
+
+	report := selfcheck.Run(ctx, buildProfile, selfChecks(handler))
+	handler.SelfCheck = &report
+
+	body, err := json.Marshal(report)
+	if err != nil {
+		log.Fatalf("Self-check report encoding failed: %v", err)
+	}
+	log.Printf("Self-check %s: %s", report.Status, body)
+	if path := os.Getenv("SELFCHECK_REPORT_PATH"); path != "" {
+		if err := os.WriteFile(path, append(body, '\n'), 0o644); err != nil {
+			log.Fatalf("Self-check report write failed: %v", err)
+		}
+	}
+	if report.Status != selfcheck.Pass && getEnvBool("SELFCHECK_STRICT", true) {
+		log.Fatalf("Self-check failed; refusing to serve (set SELFCHECK_STRICT=false to override)")
+	}
+}
+
+// selfChecks lists the checks for the configured subsystems.
+func selfChecks(handler *handlers.Handler) []selfcheck.Check {
+	checks := []selfcheck.Check{
+		{Name: "config", Run: func(context.Context) (string, error) {
+			// Each setup function refuses invalid settings on its own;
+			// what is left to report are legal but risky choices.
+			var notes []string
+			if handler.Access == nil && os.Getenv("ADMIN_TOKEN") == "" && !edgeBuild {
+				notes = append(notes, "admin APIs disabled")
+			}
+			if os.Getenv("PREDICTION_STORE_PATH") == "" {
+				notes = append(notes, "predictions kept in memory only")
+			}
+			if len(notes) == 0 {
+				return "ok", nil
+			}
+			return strings.Join(notes, "; "), nil
+		}},
+		{Name: "model", Run: func(context.Context) (string, error) {
+			if handler.InferenceEngine == nil {
+				return "", fmt.Errorf("no model loaded")
+			}
+			m := handler.Model
+			return fmt.Sprintf("%s from %s", m.Name, m.Source), nil
+		}},
+		{Name: "inference", Run: selfcheck.SanityInference(handler.InferenceEngine)},
+	}
+	if handler.Ensemble != nil {
+		for _, m := range handler.Ensemble.Members {
+			checks = append(checks, selfcheck.Check{Name: "inference:" + m.Name, Run: selfcheck.SanityInference(m.Engine)})
+		}
+	}
+
+	checks = append(checks, selfcheck.Check{Name: "prediction_store", Run: func(ctx context.Context) (string, error) {
+		if _, err := handler.Store.Find(ctx, store.Filter{Limit: 1}); err != nil {
+			return "", err
+		}
+		return "reachable", nil
+	}})
+	if handler.Images != nil {
+		checks = append(checks, selfcheck.Check{Name: "image_store", Run: func(ctx context.Context) (string, error) {
+			// A write, read and delete round trip under a name no
+			// prediction can have.
+			id := fmt.Sprintf("selfcheck-%d", time.Now().UnixNano())
+			if err := handler.Images.PutImage(ctx, "", id, []byte("selfcheck")); err != nil {
+				return "", fmt.Errorf("write: %w", err)
+			}
+			if _, err := handler.Images.GetImage(ctx, id); err != nil {
+				return "", fmt.Errorf("read: %w", err)
+			}
+			if err := handler.Images.DeleteImage(ctx, id); err != nil {
+				return "", fmt.Errorf("delete: %w", err)
+			}
+			return "read/write ok", nil
+		}})
+	}
+	return checks
+}
@@ -0,0 +1,117 @@
+// backend/cmd/api/server.go
+/*
+ * Wiring for the HTTP server and its lifecycle.
+ *
+ * The server listens on PORT as soon as the process starts, before the
+ * model is fetched, and until startup completes answers only the probes:
+ * /healthy with 200 (the process is alive), /ready and everything else
+ * with 503. Orchestrators therefore neither restart a service that is
+ * still downloading its model nor route traffic to it. Once every
+ * subsystem is set up the API router takes over and /ready answers 200.
+ *
+ * On SIGTERM or SIGINT the service reports itself draining on /ready,
+ * stops accepting connections and waits up to SHUTDOWN_TIMEOUT (default
+ * 30s) for the requests in flight, the gRPC service's included, before
+ * it exits.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+)
+
+// server is the HTTP server, whose handler is replaced once startup
+// completes.
+type server struct {
+	http    *http.Server
+	handler atomic.Pointer[http.Handler]
+	// drains stop other listeners (gRPC) alongside the HTTP server.
+	drains []func(context.Context)
+}
+
+// startServer listens on port, answering the probes only.
+func startServer(port string) *server {
+	s := &server{}
+	s.http = &http.Server{Addr: ":" + port, Handler: s, ReadHeaderTimeout: 30 * time.Second}
+	var starting http.Handler = http.HandlerFunc(startingResponse)
+	s.handler.Store(&starting)
+	go func() {
+		if err := s.http.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Server failed: %v", err)
+		}
+	}()
+	log.Printf("Server listening on :%s (starting)", port)
+	return s
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	(*s.handler.Load()).ServeHTTP(w, r)
+}
+
+// serve hands every following request to h.
+func (s *server) serve(h http.Handler) {
+	s.handler.Store(&h)
+}
+
+// onShutdown has drain stop another listener when the server drains,
+// within the same deadline.
+func (s *server) onShutdown(drain func(context.Context)) {
+	s.drains = append(s.drains, drain)
+}
+
+// startingResponse answers requests that arrive during startup.
+func startingResponse(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	switch r.URL.Path {
+	case "/healthy", "/healthz":
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"OK"}`))
+	case "/ready":
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"status":"` + handlers.ReadinessStarting + `"}`))
+	default:
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":"the service is starting","code":"starting"}`))
+	}
+}
+
+// waitForShutdown blocks until the process is told to stop, then drains
+// the server.
+func (s *server) waitForShutdown(handler *handlers.Handler) {
+	stop, release := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer release()
+	<-stop.Done()
+
+	timeout := getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second)
+	log.Printf("Shutting down: draining requests in flight (up to %s)", timeout)
+	handler.Drain()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	for _, drain := range s.drains {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			drain(ctx)
+		}()
+	}
+	defer wg.Wait()
+	if err := s.http.Shutdown(ctx); err != nil {
+		log.Printf("Shutdown: %v; remaining requests were cut off", err)
+		return
+	}
+	log.Printf("Shutdown complete")
+}
@@ -0,0 +1,170 @@
+// backend/cmd/api/store.go
+/*
+ * Wiring for the prediction store.
+ *
+ * Predictions are recorded in memory by default. Setting
+ * PREDICTION_STORE_PATH journals them to disk so lookups keep working
+ * across restarts. PREDICTION_DB_URL records them in a database instead:
+ * sqlite:///var/lib/mammoscan/predictions.db, or (except in the edge
+ * build) postgres://user@host/db. Independently of the store,
+ * IMAGE_STORE_DIR retains the uploaded images (needed for re-scoring).
+ * PREDICTION_RETENTION is the minimum age before a deleted prediction may
+ * be purged for good. Legal holds (the /admin/legal-holds API) exempt
+ * predictions from deletion altogether; LEGAL_HOLD_PATH persists them and
+ * should always be set in production.
+ *
+ * A database store is pinged as a dependency (see dependencies.go) and
+ * each call gives up after PREDICTION_DB_TIMEOUT (default 2s), so a slow
+ * database cannot hold prediction responses. PREDICTION_DB_MAX_OPEN_CONNS,
+ * PREDICTION_DB_MAX_IDLE_CONNS, PREDICTION_DB_CONN_MAX_LIFETIME and
+ * PREDICTION_DB_CONN_MAX_IDLE_TIME size and recycle its connection pool.
+ *
+ * WRITE_BEHIND_DIR queues prediction writes (and audit entries, see
+ * audit.go) on local disk and applies them in the background, so a
+ * storage outage delays history instead of losing it.
+ * WRITE_BEHIND_INTERVAL and WRITE_BEHIND_MAX_BACKOFF pace the flushing.
+ *
+ * TENANT_KEYS_DIR encrypts the prediction history, the retained images,
+ * the queued prediction writes, the offline spool and the request journal
+ * with per-tenant keys (see internal/tenantkey). Predictions of a tenant
+ * without a key file are refused (403 tenant_key_missing), so nothing is
+ * scored that could not be kept, unless TENANT_KEYS_DEFAULT_FALLBACK=true,
+ * which encrypts their data with _default.key instead.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/legalhold"
+	"github.com/josephed37/mammoscan-AI/backend/internal/spool"
+	"github.com/josephed37/mammoscan-AI/backend/internal/store"
+	"github.com/josephed37/mammoscan-AI/backend/internal/tenantkey"
+)
+
+func setupStore(ctx context.Context, handler *handlers.Handler) {
+	keys := tenantKeys()
+	handler.TenantKeys = keys
+	if dbURL := getSecret("PREDICTION_DB_URL")(); dbURL != "" {
+		cfg, err := sqlStoreConfig(dbURL)
+		if err != nil {
+			log.Fatalf("Invalid PREDICTION_DB_URL: %v", err)
+		}
+		cfg.MaxOpenConns = getEnvInt("PREDICTION_DB_MAX_OPEN_CONNS", 0)
+		cfg.MaxIdleConns = getEnvInt("PREDICTION_DB_MAX_IDLE_CONNS", 0)
+		cfg.ConnMaxLifetime = getEnvDuration("PREDICTION_DB_CONN_MAX_LIFETIME", 30*time.Minute)
+		cfg.ConnMaxIdleTime = getEnvDuration("PREDICTION_DB_CONN_MAX_IDLE_TIME", 5*time.Minute)
+		cfg.Keys = keys
+		s, err := store.NewSQLStore(ctx, cfg)
+		if err != nil {
+			log.Fatalf("Prediction store init failed: %v", err)
+		}
+		breaker := handler.Dependencies.Breaker("prediction_db", breakerConfig(), s.Ping)
+		handler.Store = store.Guard(s, breaker, getEnvDuration("PREDICTION_DB_TIMEOUT", 2*time.Second))
+		log.Printf("Recording predictions in %s", cfg.Dialect)
+	} else {
+		s, err := store.NewMemoryStore(store.MemoryConfig{
+			JournalPath: os.Getenv("PREDICTION_STORE_PATH"),
+			MaxRecords:  getEnvInt("PREDICTION_STORE_MAX", 0),
+			Keys:        keys,
+		})
+		if err != nil {
+			log.Fatalf("Prediction store init failed: %v", err)
+		}
+		handler.Store = s
+	}
+	if queue := writeBehindQueue("predictions"); queue != nil {
+		wb, err := store.NewWriteBehind(handler.Store, queue, keys, writeBehindConfig())
+		if err != nil {
+			log.Fatalf("Write-behind store init failed: %v", err)
+		}
+		handler.Store = wb
+		go wb.Flusher().Run(ctx)
+		log.Printf("Prediction writes queued in %s (%d pending)", queue.Dir(), wb.Pending())
+	}
+	handler.Retention = getEnvDuration("PREDICTION_RETENTION", 0)
+	holdPath := os.Getenv("LEGAL_HOLD_PATH")
+	holds, err := legalhold.NewRegistry(holdPath)
+	if err != nil {
+		log.Fatalf("Legal hold registry init failed: %v", err)
+	}
+	handler.LegalHolds = holds
+	if holdPath == "" {
+		log.Println("LEGAL_HOLD_PATH not set; legal holds are lost on restart")
+	}
+
+	if dir := os.Getenv("IMAGE_STORE_DIR"); dir != "" {
+		images, err := store.NewDirImageStore(dir, keys)
+		if err != nil {
+			log.Fatalf("Image store init failed: %v", err)
+		}
+		handler.Images = images
+		log.Printf("Retaining uploaded images in %s", dir)
+	}
+}
+
+// tenantKeys loads TENANT_KEYS_DIR; nil when unset.
+func tenantKeys() *tenantkey.Keyring {
+	dir := os.Getenv("TENANT_KEYS_DIR")
+	if dir == "" {
+		return nil
+	}
+	cfg := tenantkey.Config{DefaultFallback: getEnvBool("TENANT_KEYS_DEFAULT_FALLBACK", false)}
+	keys, err := tenantkey.LoadDir(dir, cfg)
+	if err != nil {
+		log.Fatalf("Invalid TENANT_KEYS_DIR: %v", err)
+	}
+	log.Printf("Encrypting persisted data with keys for %d tenant(s)", keys.Len())
+	if cfg.DefaultFallback {
+		log.Println("Tenants without a key file share the default key")
+	}
+	return keys
+}
+
+// sqlStoreConfig maps a database URL onto a dialect and registered driver.
+func sqlStoreConfig(dbURL string) (store.SQLConfig, error) {
+	if path, ok := strings.CutPrefix(dbURL, "sqlite://"); ok {
+		if path == "" {
+			return store.SQLConfig{}, fmt.Errorf("no database path in %q", dbURL)
+		}
+		// WAL lets lookups proceed while a prediction is written.
+		return store.SQLConfig{Dialect: store.SQLite, Driver: "sqlite", DSN: "file:" + path + "?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)"}, nil
+	}
+	if strings.HasPrefix(dbURL, "postgres://") || strings.HasPrefix(dbURL, "postgresql://") {
+		if postgresDriver == "" {
+			return store.SQLConfig{}, fmt.Errorf("postgres is not available in the %s build", buildProfile)
+		}
+		return store.SQLConfig{Dialect: store.Postgres, Driver: postgresDriver, DSN: dbURL}, nil
+	}
+	return store.SQLConfig{}, fmt.Errorf("unsupported database URL (want sqlite:// or postgres://)")
+}
+
+// writeBehindQueue opens the write-behind queue called name, or returns
+// nil when WRITE_BEHIND_DIR is not set.
+func writeBehindQueue(name string) *spool.Queue {
+	dir := os.Getenv("WRITE_BEHIND_DIR")
+	if dir == "" {
+		return nil
+	}
+	queue, err := spool.Open(filepath.Join(dir, name))
+	if err != nil {
+		log.Fatalf("Write-behind queue init failed: %v", err)
+	}
+	return queue
+}
+
+// writeBehindConfig paces the write-behind flushers.
+func writeBehindConfig() spool.FlusherConfig {
+	return spool.FlusherConfig{
+		Interval:   getEnvDuration("WRITE_BEHIND_INTERVAL", 0),
+		MaxBackoff: getEnvDuration("WRITE_BEHIND_MAX_BACKOFF", 0),
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !edge
+
+// backend/cmd/api/store_drivers.go
+/*
+ * Database drivers for the standard and local builds: SQLite and
+ * Postgres.
+ */
+
+package main
+
+import (
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+// postgresDriver is the database/sql driver name of pgx.
+const postgresDriver = "pgx"
@@ -0,0 +1,16 @@
+//go:build edge
+
+// backend/cmd/api/store_drivers_edge.go
+/*
+ * Database drivers for the edge build: a screening unit keeps its history
+ * in a local SQLite file, so Postgres is not linked.
+ */
+
+package main
+
+import (
+	_ "modernc.org/sqlite"
+)
+
+// postgresDriver is empty: Postgres is unavailable in this build.
+const postgresDriver = ""
@@ -0,0 +1,83 @@
+// backend/cmd/api/threshold.go
+/*
+ * Wiring for decision thresholds.
+ *
+ * Every model gets its own threshold (see internal/threshold for the
+ * precedence). DECISION_THRESHOLD sets the default in place of the
+ * built-in one, and MODEL_THRESHOLDS_PATH names a JSON file of defaults
+ * and per-version (or per-name) overrides, so sensitivity can be tuned
+ * without a rebuild. With MODEL_METADATA_SIDECAR=true the threshold is
+ * also read from the "<model>.metadata.json" sidecar the training
+ * pipeline publishes next to each artifact. Thresholds are resolved when
+ * a model is loaded; changing them takes a restart or a model reload.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/threshold"
+)
+
+// modelMetadataSuffix names a model's metadata sidecar after the model.
+const modelMetadataSuffix = ".metadata.json"
+
+func setupThresholds(ctx context.Context, handler *handlers.Handler) {
+	var cfg threshold.Config
+	if path := os.Getenv("MODEL_THRESHOLDS_PATH"); path != "" {
+		var err error
+		if cfg, err = threshold.Load(path); err != nil {
+			log.Fatalf("Invalid MODEL_THRESHOLDS_PATH: %v", err)
+		}
+	}
+	if t := getEnvFloat("DECISION_THRESHOLD", 0); t != 0 {
+		if err := threshold.Check(t); err != nil {
+			log.Fatalf("Invalid DECISION_THRESHOLD: %v", err)
+		}
+		cfg.Default = t
+	}
+	sidecars := getEnvBool("MODEL_METADATA_SIDECAR", false)
+
+	handler.ModelThreshold = func(ctx context.Context, ref string, info models.ModelInfo) float64 {
+		var sidecar float64
+		if sidecars {
+			sidecar = sidecarThreshold(ctx, ref)
+		}
+		return cfg.Resolve(info.Name, info.Version, sidecar, handlers.DefaultThreshold)
+	}
+	handler.Model.Threshold = handler.ModelThreshold(ctx, servedModelRef(handler), handler.Model)
+	log.Printf("Decision threshold %g for %s", handler.Model.Threshold, handler.Model.Name)
+}
+
+// sidecarThreshold returns the threshold in the metadata sidecar of the
+// model at ref, zero when there is none or it cannot be read.
+func sidecarThreshold(ctx context.Context, ref string) float64 {
+	data, ok, err := readModelMetadata(ctx, ref)
+	if err == nil && ok {
+		var t float64
+		if t, err = threshold.ParseSidecar(data); err == nil {
+			return t
+		}
+	}
+	if err != nil {
+		log.Printf("Model metadata of %s unreadable, threshold not taken from it: %v", ref, err)
+	}
+	return 0
+}
+
+// localModelMetadata reads the metadata sidecar next to a local model
+// file.
+func localModelMetadata(path string) ([]byte, bool, error) {
+	data, err := os.ReadFile(path + modelMetadataSuffix)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	return data, err == nil, err
+}
@@ -0,0 +1,39 @@
+// backend/cmd/api/tiling.go
+/*
+ * Wiring for tiled inference.
+ *
+ * TILE_SIZE (patch side in source pixels) enables tiling mode for models
+ * trained on full-resolution patches. TILE_OVERLAP is the fraction shared
+ * by neighbouring patches, TILE_AGGREGATION combines the patch scores
+ * (max, mean or noisy_or) and TILE_MAX_PATCHES bounds the work per image.
+ * TILE_MAP_SIZE sets the stitched probability map's resolution along the
+ * long side (negative disables the map).
+ */
+
+package main
+
+import (
+	"log"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/tiling"
+)
+
+func setupTiling(handler *handlers.Handler) {
+	size := getEnvInt("TILE_SIZE", 0)
+	if size <= 0 {
+		return
+	}
+	cfg := tiling.Config{
+		PatchSize:   size,
+		Overlap:     getEnvFloat("TILE_OVERLAP", 0.25),
+		Aggregation: getEnv("TILE_AGGREGATION", tiling.Max),
+		MaxPatches:  getEnvInt("TILE_MAX_PATCHES", tiling.DefaultMaxPatches),
+		MapSize:     getEnvInt("TILE_MAP_SIZE", tiling.DefaultMapSize),
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid tiling configuration: %v", err)
+	}
+	handler.Tiling = &cfg
+	log.Printf("Tiling mode enabled (%dpx patches, %.0f%% overlap, %s aggregation)", cfg.PatchSize, cfg.Overlap*100, cfg.Aggregation)
+}
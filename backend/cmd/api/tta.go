@@ -0,0 +1,38 @@
+// backend/cmd/api/tta.go
+/*
+ * Wiring for test-time augmentation.
+ *
+ * Clients ask for TTA per request with `tta=true`. TTA_AUGMENTATIONS
+ * lists the augmented copies scored (default
+ * identity,hflip,rotate:-5,rotate:5,crop:0.9) and TTA_DEFAULT=true runs
+ * TTA for requests that do not ask either way. TTA scores whole images,
+ * so it cannot be the default in tiling mode.
+ */
+
+package main
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/tta"
+)
+
+func setupTTA(handler *handlers.Handler) {
+	if spec := os.Getenv("TTA_AUGMENTATIONS"); spec != "" {
+		augs, err := tta.Parse(spec)
+		if err != nil {
+			log.Fatalf("Invalid TTA_AUGMENTATIONS: %v", err)
+		}
+		handler.TTA.Augmentations = augs
+	}
+	handler.TTA.Default = getEnvBool("TTA_DEFAULT", false)
+	if handler.TTA.Default {
+		if handler.Tiling != nil {
+			log.Fatal("TTA_DEFAULT cannot be combined with tiling mode (TILE_SIZE)")
+		}
+		log.Printf("Test-time augmentation on by default (%s)", strings.Join(handler.TTA.Names(), ", "))
+	}
+}
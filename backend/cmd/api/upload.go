@@ -0,0 +1,70 @@
+// backend/cmd/api/upload.go
+/*
+ * Wiring for upload size limits and validation.
+ *
+ * UPLOAD_MAX_BYTES caps the uploaded image. Images larger than
+ * UPLOAD_SPOOL_THRESHOLD bytes are spooled to UPLOAD_SPOOL_DIR (default:
+ * the OS temp directory) while they are received rather than held in
+ * memory. A batch upload takes at most UPLOAD_MAX_BATCH_IMAGES images
+ * totalling UPLOAD_MAX_BATCH_BYTES.
+ *
+ * UPLOAD_ALLOWED_FORMATS narrows the accepted image formats to a
+ * comma-separated list (e.g. "dicom,png"); others are refused by their
+ * magic bytes before decoding. Decoded images must be at least
+ * IMAGE_MIN_WIDTH by IMAGE_MIN_HEIGHT pixels (default 64), spread over more
+ * than IMAGE_BLANK_RANGE of the luminance scale (default 3/255; 0 accepts
+ * blank images) and, when IMAGE_MIN_CONTRAST is set, have at least that
+ * standard deviation of luminance (0-1).
+ */
+
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+)
+
+func setupUploads(handler *handlers.Handler) {
+	limits := handlers.UploadLimits{
+		MaxImageBytes:  int64(getEnvInt("UPLOAD_MAX_BYTES", handlers.DefaultMaxImageBytes)),
+		SpoolThreshold: int64(getEnvInt("UPLOAD_SPOOL_THRESHOLD", handlers.DefaultSpoolThreshold)),
+		SpoolDir:       os.Getenv("UPLOAD_SPOOL_DIR"),
+		MaxBatchImages: getEnvInt("UPLOAD_MAX_BATCH_IMAGES", handlers.DefaultMaxBatchImages),
+		MaxBatchBytes:  int64(getEnvInt("UPLOAD_MAX_BATCH_BYTES", handlers.DefaultMaxBatchBytes)),
+	}
+	if limits.MaxImageBytes <= 0 || limits.SpoolThreshold <= 0 || limits.MaxBatchImages <= 0 || limits.MaxBatchBytes <= 0 {
+		log.Fatalf("Invalid upload limits: UPLOAD_MAX_BYTES, UPLOAD_SPOOL_THRESHOLD, UPLOAD_MAX_BATCH_IMAGES and UPLOAD_MAX_BATCH_BYTES must be positive")
+	}
+	if limits.SpoolDir != "" {
+		if info, err := os.Stat(limits.SpoolDir); err != nil || !info.IsDir() {
+			log.Fatalf("Invalid UPLOAD_SPOOL_DIR %q: not a directory", limits.SpoolDir)
+		}
+	}
+	handler.Uploads = limits
+}
+
+func setupValidation(handler *handlers.Handler) {
+	rules := handler.Validation
+	formats, err := preprocess.ParseFormats(os.Getenv("UPLOAD_ALLOWED_FORMATS"))
+	if err != nil {
+		log.Fatalf("Invalid UPLOAD_ALLOWED_FORMATS: %v", err)
+	}
+	rules.Formats = formats
+	rules.MinWidth = getEnvInt("IMAGE_MIN_WIDTH", rules.MinWidth)
+	rules.MinHeight = getEnvInt("IMAGE_MIN_HEIGHT", rules.MinHeight)
+	rules.BlankRange = getEnvFloat("IMAGE_BLANK_RANGE", rules.BlankRange)
+	rules.MinContrast = getEnvFloat("IMAGE_MIN_CONTRAST", rules.MinContrast)
+	if rules.MinWidth < 1 || rules.MinHeight < 1 {
+		log.Fatalf("Invalid IMAGE_MIN_WIDTH/IMAGE_MIN_HEIGHT: must be positive")
+	}
+	if rules.BlankRange < 0 || rules.BlankRange >= 1 || rules.MinContrast < 0 || rules.MinContrast >= 0.5 {
+		log.Fatalf("Invalid IMAGE_BLANK_RANGE or IMAGE_MIN_CONTRAST: want 0 <= range < 1 and 0 <= contrast < 0.5")
+	}
+	handler.Validation = rules
+	if len(formats) > 0 {
+		log.Printf("Accepting %v uploads only", formats)
+	}
+}
@@ -0,0 +1,114 @@
+// backend/cmd/api/webhooks.go
+/*
+ * Wiring for tenant-managed webhooks.
+ *
+ * WEBHOOKS_ENABLED=true exposes the /api/v1/webhooks management API and
+ * forwards prediction.completed, job.failed, drift.alert, model.stale and
+ * security.break_glass events from the event bus to the registered
+ * endpoints. WEBHOOK_STORE_PATH persists the registrations (and their
+ * signing secrets) across restarts; WEBHOOK_ATTEMPTS bounds retries.
+ * Each endpoint has a circuit breaker (see dependencies.go). The
+ * edge build, which runs disconnected, never delivers webhooks.
+ *
+ * WEBHOOK_OUTBOX_DIR turns on the outbox: every delivery is written
+ * there before the prediction is answered and retried until its endpoint
+ * acknowledges it. Deliveries still unacknowledged after
+ * WEBHOOK_OUTBOX_MAX_AGE (default 24h) are moved to the dead letter queue
+ * in WEBHOOK_DEAD_LETTER_DIR (default: "dead-letter" in the outbox) and
+ * counted in the admin overview. Receivers deduplicate on the
+ * Idempotency-Key header: the prediction ID for prediction.completed, the
+ * bus event ID otherwise.
+ *
+ * With the outbox and a SQL or memory prediction store, the
+ * prediction.completed event is written in the same store write as the
+ * prediction and relayed from there to the outbox, so it is neither lost
+ * nor sent for a prediction that was never saved. The write-behind store
+ * has no event outbox; with it, events still come off the bus.
+ */
+
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/events"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/jobs"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/spool"
+	"github.com/josephed37/mammoscan-AI/backend/internal/stats"
+	"github.com/josephed37/mammoscan-AI/backend/internal/store"
+	"github.com/josephed37/mammoscan-AI/backend/internal/webhook"
+)
+
+func setupWebhooks(handler *handlers.Handler) {
+	if edgeBuild || !getEnvBool("WEBHOOKS_ENABLED", false) {
+		return
+	}
+	registry, err := webhook.NewRegistry(os.Getenv("WEBHOOK_STORE_PATH"))
+	if err != nil {
+		log.Fatalf("Webhook registry init failed: %v", err)
+	}
+	cfg := webhook.DispatcherConfig{
+		Attempts:     getEnvInt("WEBHOOK_ATTEMPTS", 0),
+		Breaker:      breakerConfig(),
+		OutboxMaxAge: getEnvDuration("WEBHOOK_OUTBOX_MAX_AGE", 0),
+	}
+	if dir := os.Getenv("WEBHOOK_OUTBOX_DIR"); dir != "" {
+		if cfg.Outbox, err = spool.Open(dir); err != nil {
+			log.Fatalf("Webhook outbox init failed: %v", err)
+		}
+		deadLetter := getEnv("WEBHOOK_DEAD_LETTER_DIR", filepath.Join(dir, "dead-letter"))
+		if cfg.DeadLetter, err = spool.Open(deadLetter); err != nil {
+			log.Fatalf("Webhook dead letter queue init failed: %v", err)
+		}
+		log.Printf("Webhook outbox enabled (%s, dead letters in %s)", dir, deadLetter)
+		if n := cfg.DeadLetter.Len(); n > 0 {
+			log.Printf("Webhook dead letter queue holds %d undelivered event(s)", n)
+		}
+	}
+	dispatcher := webhook.NewDispatcher(registry, cfg)
+	handler.Webhooks = dispatcher
+	handler.Dependencies.Add(dispatcher)
+	if outbox, ok := handler.Store.(store.OutboxStore); ok && cfg.Outbox != nil {
+		dispatcher.RelayFrom(outbox)
+		log.Println("Prediction webhooks relayed from the prediction store")
+	}
+	forward := func(ev events.Event) {
+		wev := webhook.Event{ID: ev.ID, Tenant: ev.Tenant, CreatedAt: ev.Time, Data: ev.Data}
+		switch data := ev.Data.(type) {
+		case events.Prediction:
+			if dispatcher.Relaying() {
+				// Written with the prediction (see putPrediction).
+				return
+			}
+			wev.ID, wev.Type, wev.Data = data.Response.PredictionID, webhook.PredictionCompleted, data.Response
+			dispatcher.Notify(wev)
+		case jobs.Job:
+			wev.Type = webhook.JobFailed
+			dispatcher.Notify(wev)
+		case stats.DriftStats:
+			wev.Type = webhook.DriftAlert
+			dispatcher.Broadcast(wev)
+		case models.ModelAge:
+			wev.Type = webhook.ModelStaleAlert
+			dispatcher.Broadcast(wev)
+		case events.BreakGlass:
+			wev.Type = webhook.BreakGlassAlert
+			dispatcher.Notify(wev)
+		}
+	}
+	types := []string{events.PredictionCompleted, events.JobFailed, events.DriftDetected, events.ModelStale, events.BreakGlassAccess}
+	if cfg.Outbox != nil {
+		// Stored before the publisher carries on, so an event is in the
+		// outbox before its prediction is answered.
+		handler.Events.SubscribeSync("webhooks", forward, types...)
+	} else {
+		handler.Events.Subscribe("webhooks", 0, forward, types...)
+	}
+	if os.Getenv("WEBHOOK_STORE_PATH") == "" {
+		log.Println("Webhooks enabled without WEBHOOK_STORE_PATH; registrations are lost on restart")
+	}
+}
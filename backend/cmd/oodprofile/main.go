@@ -0,0 +1,78 @@
+// backend/cmd/oodprofile/main.go
+/*
+ * oodprofile fits the out-of-distribution guard's profile on a set of
+ * training mammograms.
+ *
+ *   go run ./cmd/oodprofile -out ood_profile.json ../data/processed/train
+ *
+ * Every image under the given directories is decoded exactly as the API
+ * decodes uploads, so the fitted statistics match what the guard sees at
+ * serving time. Point OOD_PROFILE_PATH at the output to enable the guard.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/ood"
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+)
+
+var imageExts = []string{".jpg", ".jpeg", ".png", ".gif", ".tif", ".tiff", ".heic", ".heif", ".dcm"}
+
+func main() {
+	out := flag.String("out", "ood_profile.json", "where to write the profile")
+	quantile := flag.Float64("quantile", 0.995, "training distance quantile used as the cutoff")
+	margin := flag.Float64("margin", 1.5, "multiplier applied to the cutoff")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		log.Fatal("usage: oodprofile [-out file] [-quantile q] [-margin m] dir...")
+	}
+
+	var features [][]float64
+	var skipped int
+	for _, root := range flag.Args() {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !slices.Contains(imageExts, strings.ToLower(filepath.Ext(path))) {
+				return err
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			img, err := preprocess.DecodeImageWithOptions(f, preprocess.DefaultOptions())
+			if err != nil {
+				log.Printf("skipping %s: %v", path, err)
+				skipped++
+				return nil
+			}
+			features = append(features, ood.Features(img))
+			return nil
+		})
+		if err != nil {
+			log.Fatalf("walk %s: %v", root, err)
+		}
+	}
+
+	profile, err := ood.Fit(features, *quantile, *margin)
+	if err != nil {
+		log.Fatalf("fit profile: %v", err)
+	}
+	data, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, append(data, '\n'), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Fitted on %d images (%d skipped); max distance %.3f; wrote %s", profile.Samples, skipped, profile.MaxDistance, *out)
+}
@@ -0,0 +1,188 @@
+package access
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+// testProvider is an identity provider serving the public half of its
+// signing keys as a JWKS.
+type testProvider struct {
+	keys    map[string]*ecdsa.PrivateKey
+	fetches atomic.Int32
+	server  *httptest.Server
+}
+
+func newTestProvider(t *testing.T, kids ...string) *testProvider {
+	t.Helper()
+	p := &testProvider{keys: map[string]*ecdsa.PrivateKey{}}
+	for _, kid := range kids {
+		k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.keys[kid] = k
+	}
+	p.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		p.fetches.Add(1)
+		var set jose.JSONWebKeySet
+		for kid, k := range p.keys {
+			set.Keys = append(set.Keys, jose.JSONWebKey{Key: &k.PublicKey, KeyID: kid, Algorithm: string(jose.ES256), Use: "sig"})
+		}
+		json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *testProvider) sign(t *testing.T, kid string, claims map[string]any) string {
+	t.Helper()
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: p.keys[kid]}, (&jose.SignerOptions{}).WithHeader("kid", kid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tok, err := jwt.Signed(signer).Claims(claims).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tok
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub":    "dr-a",
+		"iss":    "https://idp.example",
+		"aud":    "mammoscan",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"tenant": "clinic-a",
+		"realm_access": map[string]any{
+			"roles": []any{"readonly", 7, "predict"},
+		},
+	}
+}
+
+func TestJWTAuthenticate(t *testing.T) {
+	idp := newTestProvider(t, "k1")
+	v, err := NewJWTVerifier(context.Background(), JWTConfig{
+		JWKSURL:    idp.server.URL,
+		Issuer:     "https://idp.example",
+		Audience:   "mammoscan",
+		RolesClaim: "realm_access.roles",
+	})
+	if err != nil {
+		t.Fatalf("NewJWTVerifier: %v", err)
+	}
+
+	p, err := v.Authenticate(context.Background(), idp.sign(t, "k1", validClaims()))
+	if err != nil {
+		t.Fatalf("Authenticate(valid): %v", err)
+	}
+	if p.ID != "dr-a" || p.Tenant != "clinic-a" || !slices.Equal(p.Roles, []string{"readonly", "predict"}) {
+		t.Errorf("principal = %+v", p)
+	}
+
+	with := func(key string, value any) string {
+		c := validClaims()
+		if value == nil {
+			delete(c, key)
+		} else {
+			c[key] = value
+		}
+		return idp.sign(t, "k1", c)
+	}
+	other := newTestProvider(t, "k1")
+	cases := map[string]string{
+		"malformed":      "eyJhbGciOiJFUzI1NiJ9.not-a-token.sig",
+		"not a JWT":      "an-api-key",
+		"expired":        with("exp", time.Now().Add(-time.Hour).Unix()),
+		"no expiry":      with("exp", nil),
+		"not yet valid":  with("nbf", time.Now().Add(time.Hour).Unix()),
+		"wrong issuer":   with("iss", "https://evil.example"),
+		"wrong audience": with("aud", "another-service"),
+		"no subject":     with("sub", nil),
+		"foreign key":    other.sign(t, "k1", validClaims()),
+	}
+	for name, tok := range cases {
+		if _, err := v.Authenticate(context.Background(), tok); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: error = %v, want ErrInvalidToken", name, err)
+		}
+	}
+}
+
+func TestJWTRejectsSymmetricTokens(t *testing.T) {
+	idp := newTestProvider(t, "k1")
+	v, err := NewJWTVerifier(context.Background(), JWTConfig{JWKSURL: idp.server.URL})
+	if err != nil {
+		t.Fatalf("NewJWTVerifier: %v", err)
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("0123456789abcdef0123456789abcdef")}, (&jose.SignerOptions{}).WithHeader("kid", "k1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tok, err := jwt.Signed(signer).Claims(validClaims()).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Authenticate(context.Background(), tok); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("HS256 token: error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestJWTUnknownKeyRefetchIsRateLimited(t *testing.T) {
+	idp := newTestProvider(t, "k1")
+	v, err := NewJWTVerifier(context.Background(), JWTConfig{JWKSURL: idp.server.URL})
+	if err != nil {
+		t.Fatalf("NewJWTVerifier: %v", err)
+	}
+	rotated := newTestProvider(t, "k2")
+	for range 5 {
+		if _, err := v.Authenticate(context.Background(), rotated.sign(t, "k2", validClaims())); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("unknown kid: error = %v, want ErrInvalidToken", err)
+		}
+	}
+	if got := idp.fetches.Load(); got != 1 {
+		t.Errorf("JWKS fetched %d times, want 1 within the refetch interval", got)
+	}
+}
+
+func TestNewJWTVerifierFailures(t *testing.T) {
+	if _, err := NewJWTVerifier(context.Background(), JWTConfig{}); err == nil {
+		t.Error("NewJWTVerifier without a URL succeeded")
+	}
+	for name, handler := range map[string]http.HandlerFunc{
+		"error status": func(w http.ResponseWriter, _ *http.Request) { http.Error(w, "down", http.StatusServiceUnavailable) },
+		"not json":     func(w http.ResponseWriter, _ *http.Request) { w.Write([]byte("<html>")) },
+	} {
+		srv := httptest.NewServer(handler)
+		if _, err := NewJWTVerifier(context.Background(), JWTConfig{JWKSURL: srv.URL}); err == nil {
+			t.Errorf("%s: NewJWTVerifier succeeded", name)
+		}
+		srv.Close()
+	}
+}
+
+func TestLooksLikeJWT(t *testing.T) {
+	for s, want := range map[string]bool{
+		"eyJhbGciOiJFUzI1NiJ9.e30.sig": true,
+		"eyJhbGciOiJFUzI1NiJ9.e30":     false,
+		"abc.def.ghi":                  false,
+		"ms_live_0123456789":           false,
+	} {
+		if got := LooksLikeJWT(s); got != want {
+			t.Errorf("LooksLikeJWT(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
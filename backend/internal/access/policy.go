@@ -0,0 +1,297 @@
+// backend/internal/access/policy.go
+/*
+ * This file contains the role-based access policy.
+ *
+ * The policy is a JSON document that maps API keys to principals (a tenant
+ * and a set of roles) and roles to the endpoints, tenants and response
+ * fields they may access. Changing who can do what is a config change:
+ *
+ *   {
+ *     "api_keys": [
+ *       {"id": "ws-berlin-1", "sha256": "<hex digest of key>",
+ *        "tenant": "clinic-berlin", "roles": ["radiologist"]}
+ *     ],
+ *     "roles": {
+ *       "radiologist": {"rules": [
+ *         {"methods": ["POST"], "paths": ["/api/v1/predict"]},
+ *         {"paths": ["/api/v1/predictions*"]}
+ *       ]},
+ *       "auditor": {
+ *         "rules": [{"methods": ["GET"], "paths": ["/api/v1/predictions*"], "tenants": ["*"]}],
+ *         "redact": ["confidence_score", "subgroups"]
+ *       }
+ *     }
+ *   }
+ *
+ * Paths are route templates (e.g. /api/v1/predictions/:id); a trailing *
+ * matches any suffix. Rules without "tenants" only grant access to the
+ * principal's own tenant; "*" grants every tenant. Only digests of API keys
+ * are stored, never the keys themselves.
+ *
+ * A rule with "break_glass": true is an emergency grant: it only applies
+ * to requests that state a justification for the access (see
+ * AuthorizeBreakGlass), and each such access is audited and alerted on.
+ * It lets, say, an on-call radiologist read another site's studies in an
+ * emergency without standing access to them.
+ *
+ * Three roles are built in and may be used without being defined (or
+ * redefined to taste): "predict" submits studies and polls their jobs,
+ * "readonly" reads everything of its tenant, and "admin" may do anything,
+ * including the admin APIs, for any tenant.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package access
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// Principal is an authenticated caller.
+type Principal struct {
+	ID     string   `json:"id"`
+	Tenant string   `json:"tenant"`
+	Roles  []string `json:"roles"`
+}
+
+// APIKey binds a key digest to a principal.
+type APIKey struct {
+	Principal
+	// SHA256 is the hex-encoded SHA-256 digest of the key.
+	SHA256 string `json:"sha256"`
+}
+
+// Rule grants access to a set of endpoints.
+type Rule struct {
+	// Methods lists HTTP methods; empty means any.
+	Methods []string `json:"methods"`
+	// Paths lists route templates; a trailing * matches any suffix.
+	Paths []string `json:"paths"`
+	// Tenants lists the tenants that may be accessed; empty means the
+	// principal's own tenant only, "*" means any.
+	Tenants []string `json:"tenants"`
+	// BreakGlass marks an emergency rule, applied only to requests that
+	// state a justification.
+	BreakGlass bool `json:"break_glass"`
+}
+
+// Role is a named set of rules plus the response fields it may not see.
+type Role struct {
+	Rules  []Rule   `json:"rules"`
+	Redact []string `json:"redact"`
+}
+
+// Policy is a loaded access policy.
+type Policy struct {
+	APIKeys []APIKey        `json:"api_keys"`
+	Roles   map[string]Role `json:"roles"`
+}
+
+// BuiltinRoles are available to every policy that does not define roles
+// of the same names.
+var BuiltinRoles = map[string]Role{
+	"predict": {Rules: []Rule{
+		{Methods: []string{"POST"}, Paths: []string{"/api/v1/predict", "/api/v1/predict/batch", "/api/v1/explain", "/api/v1/compare", "/api/v1/preprocess/debug", "/api/v1/streams/predict", "/api/v1/jobs", "/api/v1/upload-tokens"}},
+		{Methods: []string{"GET"}, Paths: []string{"/api/v1/jobs/:id", "/api/v1/capabilities", "/api/v1/model", "/api/v1/encryption-key"}},
+	}},
+	"readonly": {Rules: []Rule{
+		{Methods: []string{"GET"}, Paths: []string{"/api/v1/*"}},
+	}},
+	"admin": {Rules: []Rule{
+		{Paths: []string{"*"}, Tenants: []string{"*"}},
+	}},
+}
+
+// Decision is the outcome of an authorization check.
+type Decision struct {
+	Allowed bool
+	// Redact lists response fields to remove before the response is sent.
+	Redact []string
+}
+
+// Load reads and validates a policy file.
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	p.addBuiltinRoles()
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// Builtin returns a policy with no API keys and the built-in roles, for
+// deployments that only authenticate bearer tokens.
+func Builtin() *Policy {
+	p := &Policy{}
+	p.addBuiltinRoles()
+	return p
+}
+
+func (p *Policy) addBuiltinRoles() {
+	if p.Roles == nil {
+		p.Roles = make(map[string]Role, len(BuiltinRoles))
+	}
+	for name, r := range BuiltinRoles {
+		if _, ok := p.Roles[name]; !ok {
+			p.Roles[name] = r
+		}
+	}
+}
+
+// Validate checks that every key is well-formed and references known roles.
+func (p *Policy) Validate() error {
+	seen := make(map[string]bool)
+	for _, k := range p.APIKeys {
+		if k.ID == "" {
+			return fmt.Errorf("api key without id")
+		}
+		if seen[k.ID] {
+			return fmt.Errorf("duplicate api key id %q", k.ID)
+		}
+		seen[k.ID] = true
+		if b, err := hex.DecodeString(k.SHA256); err != nil || len(b) != sha256.Size {
+			return fmt.Errorf("api key %q: sha256 must be a hex SHA-256 digest", k.ID)
+		}
+		for _, r := range k.Roles {
+			if _, ok := p.Roles[r]; !ok {
+				return fmt.Errorf("api key %q: unknown role %q", k.ID, r)
+			}
+		}
+	}
+	return nil
+}
+
+// Authenticate returns the principal owning a presented API key.
+func (p *Policy) Authenticate(key string) (*Principal, bool) {
+	if key == "" {
+		return nil, false
+	}
+	sum := sha256.Sum256([]byte(key))
+	digest := hex.EncodeToString(sum[:])
+	for i := range p.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(digest), []byte(strings.ToLower(p.APIKeys[i].SHA256))) == 1 {
+			return &p.APIKeys[i].Principal, true
+		}
+	}
+	return nil, false
+}
+
+// Authorize decides whether a principal may call route with method on
+// behalf of tenant. Fields are only redacted if every role granting
+// access redacts them. Break-glass rules are not considered.
+func (p *Policy) Authorize(principal *Principal, method, route, tenant string) Decision {
+	return p.authorize(principal, method, route, tenant, false)
+}
+
+// AuthorizeBreakGlass is Authorize for a request that states a
+// justification: break-glass rules apply too. Callers audit every access
+// it allows that Authorize does not.
+func (p *Policy) AuthorizeBreakGlass(principal *Principal, method, route, tenant string) Decision {
+	return p.authorize(principal, method, route, tenant, true)
+}
+
+func (p *Policy) authorize(principal *Principal, method, route, tenant string, breakGlass bool) Decision {
+	var d Decision
+	var redact []string
+	for _, name := range principal.Roles {
+		role, ok := p.Roles[name]
+		if !ok || !role.allows(principal, method, route, tenant, breakGlass) {
+			continue
+		}
+		if !d.Allowed {
+			d.Allowed = true
+			redact = slices.Clone(role.Redact)
+			continue
+		}
+		redact = slices.DeleteFunc(redact, func(f string) bool { return !slices.Contains(role.Redact, f) })
+	}
+	d.Redact = redact
+	return d
+}
+
+func (r Role) allows(principal *Principal, method, route, tenant string, breakGlass bool) bool {
+	for _, rule := range r.Rules {
+		if rule.BreakGlass && !breakGlass {
+			continue
+		}
+		if rule.matches(principal, method, route, tenant) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) matches(principal *Principal, method, route, tenant string) bool {
+	if len(r.Methods) > 0 && !slices.ContainsFunc(r.Methods, func(m string) bool { return strings.EqualFold(m, method) }) {
+		return false
+	}
+	if !slices.ContainsFunc(r.Paths, func(p string) bool { return matchPath(p, route) }) {
+		return false
+	}
+	if len(r.Tenants) == 0 {
+		return tenant == principal.Tenant
+	}
+	return slices.Contains(r.Tenants, "*") || slices.Contains(r.Tenants, tenant)
+}
+
+func matchPath(pattern, route string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(route, prefix)
+	}
+	return pattern == route
+}
+
+// Engine serves the current policy and reloads it from disk on demand,
+// so access changes take effect without a restart.
+type Engine struct {
+	path    string
+	current atomic.Pointer[Policy]
+}
+
+// NewEngine loads the policy at path; without a path the engine serves
+// the built-in roles.
+func NewEngine(path string) (*Engine, error) {
+	e := &Engine{path: path}
+	if err := e.Reload(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Reload re-reads the policy file. On error the previous policy stays in
+// force.
+func (e *Engine) Reload() error {
+	if e.path == "" {
+		e.current.Store(Builtin())
+		return nil
+	}
+	p, err := Load(e.path)
+	if err != nil {
+		return err
+	}
+	e.current.Store(p)
+	return nil
+}
+
+// Policy returns the policy currently in force.
+func (e *Engine) Policy() *Policy {
+	return e.current.Load()
+}
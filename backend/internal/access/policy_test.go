@@ -0,0 +1,163 @@
+package access
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func digest(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func writePolicy(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	return path
+}
+
+func TestLoadRejectsMalformedPolicies(t *testing.T) {
+	cases := []struct {
+		name, body, want string
+	}{
+		{"not json", `{"api_keys": [`, "parse"},
+		{"missing id", `{"api_keys": [{"sha256": "` + digest("k") + `"}]}`, "without id"},
+		{"duplicate id", `{"api_keys": [{"id": "a", "sha256": "` + digest("k1") + `"}, {"id": "a", "sha256": "` + digest("k2") + `"}]}`, "duplicate"},
+		{"bad digest", `{"api_keys": [{"id": "a", "sha256": "not-hex"}]}`, "sha256"},
+		{"short digest", `{"api_keys": [{"id": "a", "sha256": "abcd"}]}`, "sha256"},
+		{"unknown role", `{"api_keys": [{"id": "a", "sha256": "` + digest("k") + `", "roles": ["root"]}]}`, "unknown role"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(writePolicy(t, tc.body))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Load error = %v, want one mentioning %q", err, tc.want)
+			}
+		})
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Load of a missing file succeeded")
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	p := &Policy{APIKeys: []APIKey{
+		{Principal: Principal{ID: "ws-1", Tenant: "clinic-a", Roles: []string{"predict"}}, SHA256: strings.ToUpper(digest("secret"))},
+	}}
+	if got, ok := p.Authenticate("secret"); !ok || got.ID != "ws-1" {
+		t.Errorf("Authenticate(valid) = %+v, %v", got, ok)
+	}
+	for _, key := range []string{"", "Secret", "secret ", digest("secret")} {
+		if got, ok := p.Authenticate(key); ok {
+			t.Errorf("Authenticate(%q) = %+v, want rejected", key, got)
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	p := Builtin()
+	p.Roles["auditor"] = Role{
+		Rules:  []Rule{{Methods: []string{"get"}, Paths: []string{"/api/v1/predictions*"}, Tenants: []string{"*"}}},
+		Redact: []string{"confidence_score", "subgroups"},
+	}
+	p.Roles["reviewer"] = Role{
+		Rules:  []Rule{{Methods: []string{"GET"}, Paths: []string{"/api/v1/predictions/:id"}}},
+		Redact: []string{"subgroups"},
+	}
+	p.Roles["oncall"] = Role{Rules: []Rule{
+		{Methods: []string{"GET"}, Paths: []string{"/api/v1/predictions/:id"}, Tenants: []string{"clinic-b"}, BreakGlass: true},
+	}}
+
+	cases := []struct {
+		name                  string
+		roles                 []string
+		method, route, tenant string
+		breakGlass            bool
+		allowed               bool
+		redact                []string
+	}{
+		{"own tenant", []string{"predict"}, "POST", "/api/v1/predict", "clinic-a", false, true, nil},
+		{"other tenant", []string{"predict"}, "POST", "/api/v1/predict", "clinic-b", false, false, nil},
+		{"wrong method", []string{"predict"}, "GET", "/api/v1/predict", "clinic-a", false, false, nil},
+		{"unlisted route", []string{"predict"}, "POST", "/admin/reload", "clinic-a", false, false, nil},
+		{"exact path is not a prefix", []string{"reviewer"}, "GET", "/api/v1/predictions/:id/feedback", "clinic-a", false, false, nil},
+		{"unknown role", []string{"root"}, "GET", "/api/v1/capabilities", "clinic-a", false, false, nil},
+		{"no roles", nil, "GET", "/api/v1/capabilities", "clinic-a", false, false, nil},
+		{"wildcard tenant, case-insensitive method", []string{"auditor"}, "GET", "/api/v1/predictions", "clinic-z", false, true, []string{"confidence_score", "subgroups"}},
+		{"redact only what every role redacts", []string{"auditor", "reviewer"}, "GET", "/api/v1/predictions/:id", "clinic-a", false, true, []string{"subgroups"}},
+		{"unredacted role wins", []string{"auditor", "readonly"}, "GET", "/api/v1/predictions/:id", "clinic-a", false, true, nil},
+		{"break glass without justification", []string{"oncall"}, "GET", "/api/v1/predictions/:id", "clinic-b", false, false, nil},
+		{"break glass with justification", []string{"oncall"}, "GET", "/api/v1/predictions/:id", "clinic-b", true, true, nil},
+		{"break glass outside its tenants", []string{"oncall"}, "GET", "/api/v1/predictions/:id", "clinic-c", true, false, nil},
+		{"admin", []string{"admin"}, "DELETE", "/admin/webhooks/:id", "clinic-z", false, true, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			principal := &Principal{ID: "p", Tenant: "clinic-a", Roles: tc.roles}
+			authorize := p.Authorize
+			if tc.breakGlass {
+				authorize = p.AuthorizeBreakGlass
+			}
+			d := authorize(principal, tc.method, tc.route, tc.tenant)
+			if d.Allowed != tc.allowed {
+				t.Fatalf("Allowed = %v, want %v", d.Allowed, tc.allowed)
+			}
+			slices.Sort(d.Redact)
+			if !slices.Equal(d.Redact, tc.redact) {
+				t.Errorf("Redact = %v, want %v", d.Redact, tc.redact)
+			}
+		})
+	}
+}
+
+func TestEngineReloadKeepsPolicyOnError(t *testing.T) {
+	path := writePolicy(t, `{"api_keys": [{"id": "a", "sha256": "`+digest("k")+`", "tenant": "clinic-a", "roles": ["readonly"]}]}`)
+	e, err := NewEngine(path)
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{"roles": `), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Reload(); err == nil {
+		t.Fatal("Reload of a malformed policy succeeded")
+	}
+	if _, ok := e.Policy().Authenticate("k"); !ok {
+		t.Error("previous policy not kept after a failed reload")
+	}
+
+	if _, err := NewEngine(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("NewEngine with a missing policy file succeeded")
+	}
+	builtin, err := NewEngine("")
+	if err != nil {
+		t.Fatalf("NewEngine without a path: %v", err)
+	}
+	if _, ok := builtin.Policy().Authenticate("k"); ok {
+		t.Error("built-in policy accepted an API key")
+	}
+}
+
+func TestRedactJSON(t *testing.T) {
+	body := []byte(`{"confidence_score":0.9,"items":[{"confidenceScore":0.1,"id":"a","subgroups":{"age":"50"}}],"id":"x"}`)
+	got := string(RedactJSON(body, []string{"confidence_score", "Subgroups"}))
+	if want := `{"id":"x","items":[{"id":"a"}]}`; got != want {
+		t.Errorf("RedactJSON = %s, want %s", got, want)
+	}
+	for _, invalid := range []string{`not json`, `{"confidence_score":`, ``} {
+		if got := string(RedactJSON([]byte(invalid), []string{"confidence_score"})); got != invalid {
+			t.Errorf("RedactJSON(%q) = %q, want unchanged", invalid, got)
+		}
+	}
+	if got := string(RedactJSON(body, nil)); got != string(body) {
+		t.Errorf("RedactJSON without fields = %s, want unchanged", got)
+	}
+}
@@ -0,0 +1,58 @@
+// backend/internal/access/redact.go
+/*
+ * This file removes redacted fields from JSON response bodies.
+ */
+
+package access
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// RedactJSON deletes the named fields from every object in a JSON
+// document, at any depth. Names match regardless of case and underscores,
+// so "confidence_score" also covers GraphQL's "confidenceScore". Bodies
+// that are not valid JSON are returned unchanged.
+func RedactJSON(body []byte, fields []string) []byte {
+	if len(fields) == 0 {
+		return body
+	}
+	normalized := make([]string, len(fields))
+	for i, f := range fields {
+		normalized[i] = normalize(f)
+	}
+	fields = normalized
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return body
+	}
+	redact(doc, fields)
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+func redact(v any, fields []string) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if slices.Contains(fields, normalize(k)) {
+				delete(v, k)
+				continue
+			}
+			redact(child, fields)
+		}
+	case []any:
+		for _, child := range v {
+			redact(child, fields)
+		}
+	}
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
@@ -0,0 +1,85 @@
+// backend/internal/audit/audit.go
+/*
+ * This file implements the security audit log.
+ *
+ * The audit log subscribes to security-relevant events on the event bus
+ * (failed authentications, lockouts, break-glass accesses, legal hold
+ * changes) and writes each one as a JSON line:
+ *
+ *   {"id": "...", "time": "...", "type": "security.lockout",
+ *    "tenant": "clinic-berlin", "data": {...}}
+ *
+ * Where the lines go -- stdout, a rotated file, syslog or the log
+ * forwarder -- is decided by whoever supplies the writer. A Queued log
+ * writes each entry to a local spool first, so entries survive the
+ * destination being down (see queued.go).
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/events"
+)
+
+// Entry is one line of the audit log.
+type Entry struct {
+	ID     string    `json:"id"`
+	Time   time.Time `json:"time"`
+	Type   string    `json:"type"`
+	Tenant string    `json:"tenant,omitempty"`
+	Data   any       `json:"data,omitempty"`
+}
+
+// Log writes audit entries to a writer, one JSON object per line.
+type Log struct {
+	mu  sync.Mutex
+	w   io.Writer
+	enc *json.Encoder
+}
+
+// New returns a log writing to w.
+func New(w io.Writer) *Log {
+	return &Log{w: w, enc: json.NewEncoder(w)}
+}
+
+// Write appends e to the log.
+func (l *Log) Write(e Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.enc.Encode(e)
+}
+
+// writeLine appends an encoded entry to the log.
+func (l *Log) writeLine(line []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err := l.w.Write(append(line, '\n'))
+	return err
+}
+
+// Append writes ev to the log.
+func (l *Log) Append(ev events.Event) error {
+	return l.Write(entryOf(ev))
+}
+
+// Record writes ev to the log; it is meant to be subscribed to the bus.
+// Write failures are logged, never returned to the publisher.
+func (l *Log) Record(ev events.Event) {
+	if err := l.Append(ev); err != nil {
+		slog.Error("audit: write failed", "event_type", ev.Type, "event_id", ev.ID, "tenant", ev.Tenant, "error", err)
+	}
+}
+
+func entryOf(ev events.Event) Entry {
+	return Entry{ID: ev.ID, Time: ev.Time, Type: ev.Type, Tenant: ev.Tenant, Data: ev.Data}
+}
@@ -0,0 +1,55 @@
+// backend/internal/audit/offline.go
+/*
+ * This file queues audit entries for the central service.
+ *
+ * An edge unit keeps its own audit log, but the security team reads the
+ * central one. In store-and-forward mode every entry is also written to
+ * an offline spool, and the spool's Forwarder syncs it to the central
+ * service with the predictions once the unit is back online. Entries keep
+ * their event ID as the record ID, so replays are discarded centrally.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/events"
+	"github.com/josephed37/mammoscan-AI/backend/internal/spool"
+)
+
+// Offline queues audit entries in a spool synced to the central service.
+type Offline struct {
+	queue *spool.Queue
+}
+
+// NewOffline returns a sink queueing entries in queue.
+func NewOffline(queue *spool.Queue) *Offline {
+	return &Offline{queue: queue}
+}
+
+// Append queues ev for syncing.
+func (o *Offline) Append(ev events.Event) error {
+	payload, err := json.Marshal(entryOf(ev))
+	if err != nil {
+		return fmt.Errorf("encode %s event %s: %w", ev.Type, ev.ID, err)
+	}
+	if err := o.queue.Enqueue(spool.Record{ID: ev.ID, Kind: entryKind, CreatedAt: ev.Time, Payload: payload}, nil); err != nil {
+		return fmt.Errorf("queue %s event %s for sync: %w", ev.Type, ev.ID, err)
+	}
+	return nil
+}
+
+// Record queues ev; it is meant to be subscribed to the bus. Failures
+// are logged, never returned to the publisher.
+func (o *Offline) Record(ev events.Event) {
+	if err := o.Append(ev); err != nil {
+		slog.Error("audit: queue for sync failed", "event_type", ev.Type, "event_id", ev.ID, "tenant", ev.Tenant, "error", err)
+	}
+}
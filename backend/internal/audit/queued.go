@@ -0,0 +1,78 @@
+// backend/internal/audit/queued.go
+/*
+ * This file implements the write-behind audit log.
+ *
+ * Written directly, an entry is lost when the destination fails: the
+ * syslog server is restarting, the disk holding the log file is full.
+ * A Queued log writes each entry to a spool directory instead and a
+ * background Flusher appends the queued entries to the log in order,
+ * removing them once written. While the destination is down entries
+ * accumulate on local disk and are written when it comes back.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/events"
+	"github.com/josephed37/mammoscan-AI/backend/internal/spool"
+)
+
+// entryKind tags queued audit entries in the spool.
+const entryKind = "audit"
+
+// Queued is an audit log written behind a durable queue.
+type Queued struct {
+	log     *Log
+	queue   *spool.Queue
+	flusher *spool.Flusher
+}
+
+// NewQueued returns a log queueing entries in queue and writing them to
+// l; run Flusher().Run to write them.
+func NewQueued(l *Log, queue *spool.Queue, cfg spool.FlusherConfig) *Queued {
+	q := &Queued{log: l, queue: queue}
+	if cfg.Name == "" {
+		cfg.Name = "audit log"
+	}
+	q.flusher = spool.NewFlusher(queue, func(_ context.Context, rec spool.Record) error {
+		return l.writeLine(rec.Payload)
+	}, cfg)
+	return q
+}
+
+// Flusher returns the loop writing queued entries.
+func (q *Queued) Flusher() *spool.Flusher {
+	return q.flusher
+}
+
+// Append queues ev. An entry that cannot be queued is written directly;
+// the error reports that it was neither queued nor written.
+func (q *Queued) Append(ev events.Event) error {
+	payload, err := json.Marshal(entryOf(ev))
+	if err != nil {
+		return fmt.Errorf("encode %s event %s: %w", ev.Type, ev.ID, err)
+	}
+	if err := q.queue.Enqueue(spool.Record{ID: ev.ID, Kind: entryKind, CreatedAt: ev.Time, Payload: payload}, nil); err != nil {
+		slog.Warn("audit: queue failed, writing the entry directly", "event_type", ev.Type, "event_id", ev.ID, "tenant", ev.Tenant, "error", err)
+		return q.log.Append(ev)
+	}
+	q.flusher.Wake()
+	return nil
+}
+
+// Record queues ev; it is meant to be subscribed to the bus. Failures
+// are logged, never returned to the publisher.
+func (q *Queued) Record(ev events.Event) {
+	if err := q.Append(ev); err != nil {
+		slog.Error("audit: record failed", "event_type", ev.Type, "event_id", ev.ID, "tenant", ev.Tenant, "error", err)
+	}
+}
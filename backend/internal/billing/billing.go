@@ -0,0 +1,149 @@
+// backend/internal/billing/billing.go
+/*
+ * This file defines structured billing events and the emitter that ships
+ * them to a configurable sink.
+ *
+ * One event is emitted per prediction. Emission is asynchronous and never
+ * blocks or fails a clinical request: if the sink is slow the buffer
+ * absorbs bursts, and if the buffer is full the event is dropped and
+ * counted. Quota tracking here is "soft" — exceeding a quota only marks
+ * the event so finance can see overage; enforcement is a separate concern.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package billing
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Event is a single billable unit of work.
+type Event struct {
+	EventID       string    `json:"event_id"`
+	Type          string    `json:"type"`
+	Timestamp     time.Time `json:"timestamp"`
+	Tenant        string    `json:"tenant"`
+	StudyID       string    `json:"study_id"`
+	PredictionID  string    `json:"prediction_id"`
+	Model         string    `json:"model"`
+	ComputeTier   string    `json:"compute_tier"`
+	ComputeMillis int64     `json:"compute_ms"`
+	// EnergyWh and CarbonGrams estimate the energy use and emissions of
+	// the compute, when the deployment estimates them.
+	EnergyWh    float64 `json:"energy_wh,omitempty"`
+	CarbonGrams float64 `json:"carbon_g_co2e,omitempty"`
+	Units       int     `json:"units"`
+	// QuotaExceeded is set when the tenant is past its soft monthly quota.
+	QuotaExceeded bool `json:"quota_exceeded,omitempty"`
+}
+
+// Sink is a destination for billing events.
+type Sink interface {
+	Emit(ctx context.Context, e Event) error
+	Close() error
+}
+
+// EmitterConfig controls buffering and soft quotas.
+type EmitterConfig struct {
+	// BufferSize is the number of events held while the sink catches up.
+	BufferSize int
+	// SoftQuota is the per-tenant monthly unit count after which events
+	// are flagged. Zero disables quota tracking.
+	SoftQuota int
+}
+
+// Emitter asynchronously forwards events to a Sink.
+type Emitter struct {
+	sink    Sink
+	cfg     EmitterConfig
+	events  chan Event
+	dropped atomic.Int64
+	done    chan struct{}
+
+	mu    sync.Mutex
+	month string
+	usage map[string]int
+}
+
+// NewEmitter starts a background goroutine that drains events into sink.
+func NewEmitter(sink Sink, cfg EmitterConfig) *Emitter {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 1024
+	}
+	e := &Emitter{
+		sink:   sink,
+		cfg:    cfg,
+		events: make(chan Event, cfg.BufferSize),
+		done:   make(chan struct{}),
+		usage:  make(map[string]int),
+	}
+	go e.run()
+	return e
+}
+
+// Emit queues an event. It never blocks.
+func (e *Emitter) Emit(ev Event) {
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now().UTC()
+	}
+	if ev.Units == 0 {
+		ev.Units = 1
+	}
+	ev.QuotaExceeded = e.track(ev.Tenant, ev.Units, ev.Timestamp)
+
+	select {
+	case e.events <- ev:
+	default:
+		e.dropped.Add(1)
+		log.Printf("billing: buffer full, dropped event %s", ev.EventID)
+	}
+}
+
+// Dropped returns how many events were discarded because the buffer was full.
+func (e *Emitter) Dropped() int64 {
+	return e.dropped.Load()
+}
+
+// Close flushes buffered events and closes the sink.
+func (e *Emitter) Close() error {
+	close(e.events)
+	<-e.done
+	return e.sink.Close()
+}
+
+func (e *Emitter) run() {
+	defer close(e.done)
+	for ev := range e.events {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := e.sink.Emit(ctx, ev); err != nil {
+			log.Printf("billing: emit %s failed: %v", ev.EventID, err)
+		}
+		cancel()
+	}
+}
+
+// track adds units to the tenant's usage for the current calendar month
+// and reports whether the soft quota has been exceeded.
+func (e *Emitter) track(tenant string, units int, at time.Time) bool {
+	if e.cfg.SoftQuota <= 0 {
+		return false
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	month := at.Format("2006-01")
+	if month != e.month {
+		e.month = month
+		e.usage = make(map[string]int)
+	}
+	e.usage[tenant] += units
+	return e.usage[tenant] > e.cfg.SoftQuota
+}
@@ -0,0 +1,123 @@
+// backend/internal/billing/sinks.go
+/*
+ * This file contains the built-in billing sinks and the URI parser that
+ * selects one from configuration:
+ *
+ *   stdout                   JSON Lines on standard output
+ *   file:///var/log/bill.jsonl  JSON Lines appended to a file
+ *   https://finance/ingest   one HTTP POST per event
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package billing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// NewSink builds a sink from a URI-style configuration string. authToken,
+// if non-nil, supplies the bearer token for HTTP sinks on every request.
+func NewSink(uri string, authToken func() string) (Sink, error) {
+	if uri == "stdout" {
+		return &writerSink{w: os.Stdout}, nil
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid billing sink %q: %w", uri, err)
+	}
+	switch u.Scheme {
+	case "file":
+		f, err := os.OpenFile(u.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+		if err != nil {
+			return nil, fmt.Errorf("open billing file: %w", err)
+		}
+		return &writerSink{w: f, closer: f}, nil
+	case "http", "https":
+		return &httpSink{
+			url:    uri,
+			token:  authToken,
+			client: &http.Client{Timeout: 10 * time.Second},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported billing sink scheme %q", u.Scheme)
+	}
+}
+
+// writerSink writes one JSON object per line.
+type writerSink struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+func (s *writerSink) Emit(_ context.Context, e Event) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
+
+func (s *writerSink) Close() error {
+	if s.closer != nil {
+		return s.closer.Close()
+	}
+	return nil
+}
+
+// httpSink POSTs each event as JSON. The event ID doubles as an
+// idempotency key so the receiver can discard retries.
+type httpSink struct {
+	url    string
+	token  func() string
+	client *http.Client
+}
+
+func (s *httpSink) Emit(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", e.EventID)
+	if s.token != nil {
+		if token := s.token(); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("billing sink returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *httpSink) Close() error {
+	return nil
+}
@@ -0,0 +1,247 @@
+// backend/internal/calibration/calibration.go
+/*
+ * This file implements per-site calibration learning.
+ *
+ * Scanners and sites shift the score distribution: the same raw score can
+ * mean different risks on different equipment. Given reviewer feedback,
+ * we fit a Platt correction per group,
+ *
+ *   calibrated = sigmoid(slope * logit(score) + intercept)
+ *
+ * and derive the raw-score threshold at which the group's calibrated risk
+ * equals the global decision threshold. The result is a proposal for an
+ * admin to review; nothing is applied automatically.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package calibration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/fairness"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/store"
+)
+
+// DefaultMinSamples is the number of reviewed studies a group needs
+// before a correction is fitted for it.
+const DefaultMinSamples = 50
+
+// Params describes a calibration run.
+type Params struct {
+	// Dimension groups studies; it must be one of fairness.Dimensions,
+	// typically "site" or "scanner_vendor".
+	Dimension string `json:"dimension" binding:"required"`
+	// Days is the trailing window of studies used (default 90).
+	Days int `json:"days"`
+	// MinSamples is the number of reviewed studies a group needs.
+	MinSamples int `json:"min_samples"`
+	// ModelName restricts the fit to one model's scores, by default the
+	// served model; scores from different models are not comparable.
+	ModelName string `json:"model_name"`
+	// Threshold is the global decision threshold the proposed per-group
+	// thresholds are matched to.
+	Threshold float64 `json:"threshold"`
+	Tenant    string  `json:"tenant,omitempty"`
+}
+
+// Correction is the proposed calibration for one group.
+type Correction struct {
+	Group     string  `json:"group"`
+	Samples   int     `json:"samples"`
+	Positives int     `json:"positives"`
+	Slope     float64 `json:"slope"`
+	Intercept float64 `json:"intercept"`
+	// Threshold is the raw-score threshold giving the same calibrated
+	// risk as the global threshold; omitted if the fit is degenerate.
+	Threshold *float64 `json:"threshold,omitempty"`
+	// Brier scores before and after correction; lower is better.
+	BrierBefore float64 `json:"brier_before"`
+	BrierAfter  float64 `json:"brier_after"`
+}
+
+// Skipped is a group without enough feedback to fit.
+type Skipped struct {
+	Group   string `json:"group"`
+	Samples int    `json:"samples"`
+}
+
+// Proposal is the result of a calibration run.
+type Proposal struct {
+	GeneratedAt time.Time    `json:"generated_at"`
+	Dimension   string       `json:"dimension"`
+	ModelName   string       `json:"model_name"`
+	From        time.Time    `json:"from"`
+	Threshold   float64      `json:"threshold"`
+	Corrections []Correction `json:"corrections"`
+	Skipped     []Skipped    `json:"skipped"`
+}
+
+// Run fits a correction for every group with enough reviewed studies.
+func Run(ctx context.Context, p Params, s store.Store, report func(float64)) (*Proposal, error) {
+	if !slices.Contains(fairness.Dimensions, p.Dimension) {
+		return nil, fmt.Errorf("unknown dimension %q (want one of %v)", p.Dimension, fairness.Dimensions)
+	}
+	if p.Days <= 0 {
+		p.Days = 90
+	}
+	if p.MinSamples <= 0 {
+		p.MinSamples = DefaultMinSamples
+	}
+
+	from := time.Now().UTC().AddDate(0, 0, -p.Days)
+	records, err := s.Find(ctx, store.Filter{Tenant: p.Tenant, CreatedFrom: from})
+	if err != nil {
+		return nil, fmt.Errorf("select studies: %w", err)
+	}
+
+	groups := map[string][]models.StoredPrediction{}
+	for _, rec := range records {
+		group := rec.Subgroups[p.Dimension]
+		if group == "" || rec.Feedback == nil || rec.ModelName != p.ModelName || rec.Duplicate || rec.DuplicateOf != "" {
+			continue
+		}
+		groups[group] = append(groups[group], rec)
+	}
+	names := make([]string, 0, len(groups))
+	for g := range groups {
+		names = append(names, g)
+	}
+	sort.Strings(names)
+
+	proposal := &Proposal{
+		GeneratedAt: time.Now().UTC(),
+		Dimension:   p.Dimension,
+		ModelName:   p.ModelName,
+		From:        from,
+		Threshold:   p.Threshold,
+		Corrections: []Correction{},
+		Skipped:     []Skipped{},
+	}
+	for i, name := range names {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		report(float64(i) / float64(len(names)))
+
+		recs := groups[name]
+		if len(recs) < p.MinSamples {
+			proposal.Skipped = append(proposal.Skipped, Skipped{Group: name, Samples: len(recs)})
+			continue
+		}
+		scores := make([]float64, len(recs))
+		labels := make([]bool, len(recs))
+		for j, rec := range recs {
+			scores[j] = rec.ConfidenceScore
+			labels[j] = rec.Feedback.GroundTruth == models.LabelCancer
+		}
+		c, err := fit(scores, labels)
+		if err != nil {
+			proposal.Skipped = append(proposal.Skipped, Skipped{Group: name, Samples: len(recs)})
+			continue
+		}
+		c.Group = name
+		if c.Slope > 0 {
+			t := sigmoid((logit(p.Threshold) - c.Intercept) / c.Slope)
+			c.Threshold = &t
+		}
+		proposal.Corrections = append(proposal.Corrections, c)
+	}
+	return proposal, nil
+}
+
+// errDegenerate is returned when a group's feedback is all one class.
+var errDegenerate = errors.New("feedback has a single outcome")
+
+// fit performs Platt scaling by Newton's method on the log-likelihood,
+// using Platt's smoothed targets so small groups do not overfit.
+func fit(scores []float64, labels []bool) (Correction, error) {
+	var pos int
+	for _, l := range labels {
+		if l {
+			pos++
+		}
+	}
+	neg := len(labels) - pos
+	if pos == 0 || neg == 0 {
+		return Correction{}, errDegenerate
+	}
+	hi := (float64(pos) + 1) / (float64(pos) + 2)
+	lo := 1 / (float64(neg) + 2)
+
+	x := make([]float64, len(scores))
+	t := make([]float64, len(scores))
+	for i, s := range scores {
+		x[i] = logit(s)
+		t[i] = lo
+		if labels[i] {
+			t[i] = hi
+		}
+	}
+
+	a, b := 1.0, 0.0
+	for iter := 0; iter < 100; iter++ {
+		// Gradient and Hessian of the negative log-likelihood, with a
+		// tiny ridge term to keep the Hessian invertible.
+		var ga, gb, haa, hab, hbb float64
+		for i := range x {
+			p := sigmoid(a*x[i] + b)
+			d := p - t[i]
+			w := p * (1 - p)
+			ga += d * x[i]
+			gb += d
+			haa += w * x[i] * x[i]
+			hab += w * x[i]
+			hbb += w
+		}
+		haa += 1e-9
+		hbb += 1e-9
+		det := haa*hbb - hab*hab
+		if det == 0 {
+			break
+		}
+		da := (hbb*ga - hab*gb) / det
+		db := (haa*gb - hab*ga) / det
+		a, b = a-da, b-db
+		if math.Abs(da) < 1e-9 && math.Abs(db) < 1e-9 {
+			break
+		}
+	}
+	if math.IsNaN(a) || math.IsNaN(b) || math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return Correction{}, errDegenerate
+	}
+
+	c := Correction{Samples: len(scores), Positives: pos, Slope: a, Intercept: b}
+	for i, s := range scores {
+		y := 0.0
+		if labels[i] {
+			y = 1
+		}
+		c.BrierBefore += (s - y) * (s - y)
+		cal := sigmoid(a*x[i] + b)
+		c.BrierAfter += (cal - y) * (cal - y)
+	}
+	c.BrierBefore /= float64(len(scores))
+	c.BrierAfter /= float64(len(scores))
+	return c, nil
+}
+
+// logit is clamped so scores of exactly 0 or 1 stay finite.
+func logit(p float64) float64 {
+	p = min(max(p, 1e-6), 1-1e-6)
+	return math.Log(p / (1 - p))
+}
+
+func sigmoid(z float64) float64 {
+	return 1 / (1 + math.Exp(-z))
+}
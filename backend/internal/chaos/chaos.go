@@ -0,0 +1,158 @@
+// backend/internal/chaos/chaos.go
+/*
+ * This file implements fault injection for resilience testing.
+ *
+ * Integration environments can make a share of requests fail the way
+ * production occasionally does: slow or failing inference, undecodable
+ * uploads and storage (GCS, image store) errors. Clients get to see those
+ * failure modes before they meet them for real.
+ *
+ * The injector is only wired up in binaries built with `-tags chaos`;
+ * other builds cannot enable it, whatever their environment says. All
+ * methods are safe to call on a nil *Injector and then do nothing.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+	"github.com/josephed37/mammoscan-AI/backend/internal/store"
+)
+
+// ErrInjected marks a failure produced by the injector.
+var ErrInjected = errors.New("injected fault")
+
+// Config sets the share of operations (0-100) hit by each fault.
+type Config struct {
+	// InferenceDelay is added to InferenceDelayPercent of inferences.
+	InferenceDelay        time.Duration
+	InferenceDelayPercent float64
+	InferenceErrorPercent float64
+	DecodeErrorPercent    float64
+	// StorageErrorPercent applies to model fetches and the image store.
+	StorageErrorPercent float64
+}
+
+// Validate checks that every percentage is in range.
+func (c Config) Validate() error {
+	for name, p := range map[string]float64{
+		"inference delay": c.InferenceDelayPercent,
+		"inference error": c.InferenceErrorPercent,
+		"decode error":    c.DecodeErrorPercent,
+		"storage error":   c.StorageErrorPercent,
+	} {
+		if p < 0 || p > 100 {
+			return fmt.Errorf("%s percentage must be between 0 and 100, got %v", name, p)
+		}
+	}
+	if c.InferenceDelay < 0 {
+		return fmt.Errorf("inference delay must not be negative")
+	}
+	return nil
+}
+
+// Injector decides which operations fail.
+type Injector struct {
+	cfg Config
+}
+
+// New returns an injector for cfg.
+func New(cfg Config) *Injector {
+	return &Injector{cfg: cfg}
+}
+
+// Inference is called before each inference. It may sleep (returning
+// early if ctx ends) and may return an injected error.
+func (i *Injector) Inference(ctx context.Context) error {
+	if i == nil {
+		return nil
+	}
+	if i.cfg.InferenceDelay > 0 && hit(i.cfg.InferenceDelayPercent) {
+		slog.WarnContext(ctx, "chaos: delaying inference", "delay", i.cfg.InferenceDelay.String())
+		select {
+		case <-time.After(i.cfg.InferenceDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return i.fail(i.cfg.InferenceErrorPercent, "inference")
+}
+
+// Decode is called after each successful decode and may turn it into a
+// failure.
+func (i *Injector) Decode() error {
+	if i == nil {
+		return nil
+	}
+	if err := i.fail(i.cfg.DecodeErrorPercent, "decode"); err != nil {
+		// Indistinguishable from a corrupt upload to the caller.
+		return fmt.Errorf("%w: %w", preprocess.ErrDecode, err)
+	}
+	return nil
+}
+
+// Storage is called before each storage operation.
+func (i *Injector) Storage(op string) error {
+	if i == nil {
+		return nil
+	}
+	return i.fail(i.cfg.StorageErrorPercent, op)
+}
+
+// Images wraps an image store so its operations are subject to storage
+// faults.
+func (i *Injector) Images(s store.ImageStore) store.ImageStore {
+	if i == nil || s == nil {
+		return s
+	}
+	return &faultyImages{ImageStore: s, faults: i}
+}
+
+func (i *Injector) fail(percent float64, op string) error {
+	if !hit(percent) {
+		return nil
+	}
+	slog.Warn("chaos: injecting failure", "operation", op)
+	return fmt.Errorf("%s: %w", op, ErrInjected)
+}
+
+func hit(percent float64) bool {
+	return percent > 0 && rand.Float64()*100 < percent
+}
+
+type faultyImages struct {
+	store.ImageStore
+	faults *Injector
+}
+
+func (f *faultyImages) PutImage(ctx context.Context, tenant, id string, data []byte) error {
+	if err := f.faults.Storage("image write"); err != nil {
+		return err
+	}
+	return f.ImageStore.PutImage(ctx, tenant, id, data)
+}
+
+func (f *faultyImages) GetImage(ctx context.Context, id string) ([]byte, error) {
+	if err := f.faults.Storage("image read"); err != nil {
+		return nil, err
+	}
+	return f.ImageStore.GetImage(ctx, id)
+}
+
+func (f *faultyImages) DeleteImage(ctx context.Context, id string) error {
+	if err := f.faults.Storage("image delete"); err != nil {
+		return err
+	}
+	return f.ImageStore.DeleteImage(ctx, id)
+}
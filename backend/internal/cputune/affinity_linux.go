@@ -0,0 +1,32 @@
+// backend/internal/cputune/affinity_linux.go
+/*
+ * This file pins threads to CPUs with sched_setaffinity.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package cputune
+
+import (
+	"fmt"
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// PinThread locks the calling goroutine to its OS thread and restricts
+// that thread to cpus. The goroutine stays locked; it should be one that
+// exists to run pinned work.
+func PinThread(cpus []int) error {
+	runtime.LockOSThread()
+	var set unix.CPUSet
+	for _, cpu := range cpus {
+		set.Set(cpu)
+	}
+	if err := unix.SchedSetaffinity(0, &set); err != nil {
+		return fmt.Errorf("pin thread to CPUs %v: %w", cpus, err)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+// backend/internal/cputune/affinity_other.go
+/*
+ * CPU affinity is only supported on Linux.
+ */
+
+package cputune
+
+import "errors"
+
+// PinThread is not supported on this platform.
+func PinThread(cpus []int) error {
+	return errors.New("CPU affinity is only supported on Linux")
+}
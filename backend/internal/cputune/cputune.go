@@ -0,0 +1,192 @@
+// backend/internal/cputune/cputune.go
+/*
+ * This file sizes the process to the CPUs it is actually given.
+ *
+ * In a container with a CPU limit the Go runtime still sees every core of
+ * the host, so GOMAXPROCS (and with it the garbage collector's and the
+ * inference engines' parallelism) is far above the quota; the kernel then
+ * throttles the process for most of each scheduling period and tail
+ * latency suffers. Quota reads the limit from the process's cgroup (v2's
+ * cpu.max, or v1's cpu.cfs_quota_us over cpu.cfs_period_us), taking the
+ * tightest limit on the path to the root, and Procs turns it into a
+ * GOMAXPROCS value.
+ *
+ * ParseCPUList and Split describe the CPUs inference may be pinned to;
+ * PinThread (affinity_linux.go) pins the calling thread.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package cputune
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// cgroupRoot is where the cgroup hierarchies are mounted.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// Quota returns the CPU limit of the process's cgroup in CPUs, or 0 when
+// there is none or the process does not run in a cgroup.
+func Quota() (float64, error) {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	// Lines read hierarchy-ID:controllers:path. On hybrid hosts the cpu
+	// controller stays on v1 and takes precedence over the unified line.
+	var unified string
+	hasUnified := false
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if controllers := strings.Split(parts[1], ","); slices.Contains(controllers, "cpu") {
+			return walkQuota(path.Join(cgroupRoot, parts[1]), parts[2], readV1Quota)
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			unified, hasUnified = parts[2], true
+		}
+	}
+	if !hasUnified {
+		return 0, nil
+	}
+	return walkQuota(cgroupRoot, unified, readV2Quota)
+}
+
+// walkQuota returns the tightest quota from the cgroup at mount/dir up to
+// the mount. Inside a cgroup namespace dir may not exist under the mount,
+// in which case the mount itself is the process's cgroup.
+func walkQuota(mount, dir string, read func(string) (float64, error)) (float64, error) {
+	var quota float64
+	for dir = path.Clean("/" + dir); ; dir = path.Dir(dir) {
+		q, err := read(path.Join(mount, dir))
+		if err != nil {
+			return 0, err
+		}
+		if q > 0 && (quota == 0 || q < quota) {
+			quota = q
+		}
+		if dir == "/" {
+			return quota, nil
+		}
+	}
+}
+
+// readV2Quota reads cpu.max ("max 100000" or "<quota> <period>").
+func readV2Quota(dir string) (float64, error) {
+	data, err := os.ReadFile(path.Join(dir, "cpu.max"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0, nil
+	}
+	return ratio(fields[0], fields[1])
+}
+
+// readV1Quota reads cpu.cfs_quota_us (-1 without a limit) and
+// cpu.cfs_period_us.
+func readV1Quota(dir string) (float64, error) {
+	quota, err := os.ReadFile(path.Join(dir, "cpu.cfs_quota_us"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	period, err := os.ReadFile(path.Join(dir, "cpu.cfs_period_us"))
+	if err != nil {
+		return 0, err
+	}
+	if strings.TrimSpace(string(quota)) == "-1" {
+		return 0, nil
+	}
+	return ratio(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+}
+
+func ratio(quota, period string) (float64, error) {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CPU quota %q", quota)
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0, fmt.Errorf("invalid CPU period %q", period)
+	}
+	return q / p, nil
+}
+
+// Procs returns the GOMAXPROCS the quota allows: the quota rounded down,
+// at least 1 and at most the CPUs the process may run on. It returns 0
+// when there is no quota.
+func Procs() (procs int, quota float64, err error) {
+	if quota, err = Quota(); err != nil || quota == 0 {
+		return 0, quota, err
+	}
+	return max(1, min(int(math.Floor(quota)), runtime.NumCPU())), quota, nil
+}
+
+// ParseCPUList parses a Linux CPU list such as "0-3,8,10-11".
+func ParseCPUList(s string) ([]int, error) {
+	var cpus []int
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(item, "-")
+		first, err := strconv.Atoi(lo)
+		last := first
+		if err == nil && isRange {
+			last, err = strconv.Atoi(hi)
+		}
+		if err != nil || first < 0 || last < first {
+			return nil, fmt.Errorf("invalid CPU list item %q", item)
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			if !slices.Contains(cpus, cpu) {
+				cpus = append(cpus, cpu)
+			}
+		}
+	}
+	if len(cpus) == 0 {
+		return nil, errors.New("empty CPU list")
+	}
+	return cpus, nil
+}
+
+// Split divides cpus into n contiguous, near-equal shares. With fewer
+// CPUs than shares, the CPUs are shared round-robin.
+func Split(cpus []int, n int) [][]int {
+	shares := make([][]int, n)
+	if len(cpus) < n {
+		for i := range shares {
+			shares[i] = []int{cpus[i%len(cpus)]}
+		}
+		return shares
+	}
+	for i := range shares {
+		shares[i] = cpus[i*len(cpus)/n : (i+1)*len(cpus)/n]
+	}
+	return shares
+}
@@ -0,0 +1,212 @@
+// backend/internal/cputune/topology.go
+/*
+ * This file reads the host's CPU topology, for placing inference workers.
+ *
+ * On multi-socket hosts each NUMA node has its own memory; a worker whose
+ * threads run on one node while its weights live on another pays for
+ * every access across the interconnect, and a worker spread over two
+ * nodes pays for half of them. Place therefore gives each worker CPUs of
+ * a single node. NodeCPUs lists the CPUs of given nodes, from
+ * /sys/devices/system/node.
+ *
+ * On heterogeneous (big.LITTLE, hybrid) hosts cores differ in speed, and
+ * a worker on a slow core sets the tail latency. CoreTypeCPUs lists the
+ * performance or the efficiency cores: on Intel hybrid parts from the
+ * kernel's cpu_core and cpu_atom PMU devices, elsewhere by each CPU's
+ * cpu_capacity or, where the kernel does not report it, its maximum
+ * frequency.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package cputune
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// sysCPU is where the kernel describes CPUs and NUMA nodes.
+const sysCPU = "/sys/devices/system"
+
+// ErrHomogeneous is returned by CoreTypeCPUs on a host whose cores are
+// all alike.
+var ErrHomogeneous = errors.New("all CPUs have the same capacity")
+
+// CoreType selects cores by speed on heterogeneous hosts.
+type CoreType string
+
+const (
+	PerformanceCores CoreType = "performance"
+	EfficiencyCores  CoreType = "efficiency"
+)
+
+// Nodes maps each CPU to its NUMA node. A host without NUMA information
+// has a single node, and every CPU maps to 0.
+func Nodes() (map[int]int, error) {
+	dirs, err := filepath.Glob(filepath.Join(sysCPU, "node", "node[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+	nodes := make(map[int]int)
+	for _, dir := range dirs {
+		node, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "node"))
+		if err != nil {
+			continue
+		}
+		cpus, err := readCPUList(filepath.Join(dir, "cpulist"))
+		if err != nil {
+			return nil, err
+		}
+		for _, cpu := range cpus {
+			nodes[cpu] = node
+		}
+	}
+	return nodes, nil
+}
+
+// NodeCPUs lists the CPUs of the given NUMA nodes.
+func NodeCPUs(nodes []int) ([]int, error) {
+	var cpus []int
+	for _, node := range nodes {
+		list, err := readCPUList(filepath.Join(sysCPU, "node", fmt.Sprintf("node%d", node), "cpulist"))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("no NUMA node %d", node)
+		}
+		if err != nil {
+			return nil, err
+		}
+		cpus = append(cpus, list...)
+	}
+	return cpus, nil
+}
+
+// CoreTypeCPUs lists the CPUs of the given type: those of the highest
+// capacity for PerformanceCores, the others for EfficiencyCores.
+func CoreTypeCPUs(t CoreType) ([]int, error) {
+	if t != PerformanceCores && t != EfficiencyCores {
+		return nil, fmt.Errorf("unknown core type %q (want %s or %s)", t, PerformanceCores, EfficiencyCores)
+	}
+	pmu := map[CoreType]string{PerformanceCores: "cpu_core", EfficiencyCores: "cpu_atom"}[t]
+	if cpus, err := readCPUList(filepath.Join("/sys/devices", pmu, "cpus")); err == nil && len(cpus) > 0 {
+		return cpus, nil
+	}
+	online, err := readCPUList(filepath.Join(sysCPU, "cpu", "online"))
+	if err != nil {
+		return nil, err
+	}
+	capacity := make(map[int]int, len(online))
+	highest := 0
+	for _, cpu := range online {
+		dir := filepath.Join(sysCPU, "cpu", fmt.Sprintf("cpu%d", cpu))
+		c, err := readInt(filepath.Join(dir, "cpu_capacity"))
+		if err != nil {
+			c, err = readInt(filepath.Join(dir, "cpufreq", "cpuinfo_max_freq"))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("CPU %d reports neither its capacity nor its frequency", cpu)
+		}
+		capacity[cpu] = c
+		highest = max(highest, c)
+	}
+	var cpus []int
+	for _, cpu := range online {
+		if (capacity[cpu] == highest) == (t == PerformanceCores) {
+			cpus = append(cpus, cpu)
+		}
+	}
+	if len(cpus) == 0 || len(cpus) == len(online) {
+		return nil, ErrHomogeneous
+	}
+	return cpus, nil
+}
+
+// Place divides cpus among n workers without spreading a worker over
+// NUMA nodes. Nodes get workers in proportion to their CPUs, at least one
+// each while there are workers enough; with fewer workers than nodes the
+// nodes with the most CPUs are used and the others left idle.
+func Place(cpus []int, n int) ([][]int, error) {
+	nodeOf, err := Nodes()
+	if err != nil {
+		return nil, err
+	}
+	var groups [][]int
+	index := make(map[int]int)
+	for _, cpu := range cpus {
+		node := nodeOf[cpu]
+		i, ok := index[node]
+		if !ok {
+			i = len(groups)
+			index[node] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], cpu)
+	}
+	if len(groups) == 1 {
+		return Split(cpus, n), nil
+	}
+	slices.SortStableFunc(groups, func(a, b []int) int { return cmp.Compare(len(b), len(a)) })
+	groups = groups[:min(n, len(groups))]
+
+	counts := make([]int, len(groups))
+	for i := range counts {
+		counts[i] = 1
+	}
+	for assigned := len(groups); assigned < n; assigned++ {
+		// The next worker goes to the node with the most CPUs per worker.
+		best := 0
+		for i := range groups {
+			if len(groups[i])*counts[best] > len(groups[best])*counts[i] {
+				best = i
+			}
+		}
+		counts[best]++
+	}
+	shares := make([][]int, 0, n)
+	for i, group := range groups {
+		shares = append(shares, Split(group, counts[i])...)
+	}
+	return shares, nil
+}
+
+// AllowedCPUs lists the CPUs the process may run on.
+func AllowedCPUs() ([]int, error) {
+	data, err := os.ReadFile("/proc/self/status")
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if list, ok := strings.CutPrefix(line, "Cpus_allowed_list:"); ok {
+			return ParseCPUList(list)
+		}
+	}
+	return nil, errors.New("/proc/self/status does not list the allowed CPUs")
+}
+
+func readCPUList(path string) ([]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		// A memory-only node has no CPUs.
+		return nil, nil
+	}
+	return ParseCPUList(strings.TrimSpace(string(data)))
+}
+
+func readInt(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
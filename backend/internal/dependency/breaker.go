@@ -0,0 +1,171 @@
+// backend/internal/dependency/breaker.go
+/*
+ * This file implements a circuit breaker for a downstream dependency.
+ *
+ * A database that has stopped answering does not fail calls quickly, it
+ * holds them until they time out, and every request waiting on it waits
+ * too. A Breaker counts consecutive failures of a dependency; after
+ * BreakerConfig.Failures of them it opens, and calls fail at once with
+ * ErrOpen instead of waiting. After Cooldown one call (or health check)
+ * is let through: its success closes the breaker, its failure opens it
+ * for another cooldown.
+ *
+ *   closed     calls go through; failures are counted
+ *   open       calls fail with ErrOpen
+ *   half_open  the cooldown is over and one probe is in flight
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package dependency
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// ErrOpen is returned by calls refused while a breaker is open.
+var ErrOpen = errors.New("circuit open")
+
+// Breaker states, as reported in models.DependencyStatus.
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half_open"
+)
+
+// Defaults for BreakerConfig.
+const (
+	DefaultFailures = 5
+	DefaultCooldown = 30 * time.Second
+)
+
+// BreakerConfig tunes a Breaker.
+type BreakerConfig struct {
+	// Failures is the number of consecutive failures that opens the
+	// breaker (default DefaultFailures).
+	Failures int
+	// Cooldown is how long the breaker stays open before a probe is let
+	// through (default DefaultCooldown).
+	Cooldown time.Duration
+}
+
+// Breaker guards calls to one dependency. It is safe for concurrent use.
+type Breaker struct {
+	name string
+	cfg  BreakerConfig
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	lastErr  error
+	failedAt time.Time
+	okAt     time.Time
+	latency  time.Duration
+}
+
+// NewBreaker returns a closed breaker for the dependency called name.
+func NewBreaker(name string, cfg BreakerConfig) *Breaker {
+	if cfg.Failures <= 0 {
+		cfg.Failures = DefaultFailures
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = DefaultCooldown
+	}
+	return &Breaker{name: name, cfg: cfg, state: StateClosed}
+}
+
+// Name names the dependency.
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Allow reports whether a call may proceed: nil while the breaker is
+// closed and for the probe after a cooldown, an error wrapping ErrOpen
+// otherwise. Every allowed call must be followed by Record.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case StateClosed:
+		return nil
+	case StateOpen, StateHalfOpen:
+		// A probe that never reported back is replaced after a cooldown.
+		if time.Since(b.openedAt) >= b.cfg.Cooldown {
+			b.state, b.openedAt = StateHalfOpen, time.Now()
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: %w after %d failures (last: %v)", b.name, ErrOpen, b.failures, b.lastErr)
+}
+
+// Record reports the outcome of a call that took latency; a nil err is a
+// success.
+func (b *Breaker) Record(err error, latency time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.latency = latency
+	if err == nil {
+		b.state, b.failures, b.okAt = StateClosed, 0, time.Now()
+		return
+	}
+	b.failures++
+	b.lastErr, b.failedAt = err, time.Now()
+	if b.state == StateHalfOpen || b.failures >= b.cfg.Failures {
+		b.state, b.openedAt = StateOpen, time.Now()
+	}
+}
+
+// Do runs fn if the breaker allows it and records the outcome. Errors
+// for which failed returns false (e.g. "not found") count as successes;
+// a nil failed counts every error.
+func (b *Breaker) Do(fn func() error, failed func(error) bool) error {
+	if err := b.Allow(); err != nil {
+		return err
+	}
+	start := time.Now()
+	err := fn()
+	outcome := err
+	if err != nil && failed != nil && !failed(err) {
+		outcome = nil
+	}
+	b.Record(outcome, time.Since(start))
+	return err
+}
+
+// Open reports whether calls are being refused.
+func (b *Breaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == StateOpen
+}
+
+// Status reports the breaker's state and the dependency's recent calls.
+func (b *Breaker) Status() models.DependencyStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := models.DependencyStatus{
+		Name:                b.name,
+		State:               b.state,
+		Healthy:             b.state == StateClosed,
+		ConsecutiveFailures: b.failures,
+		LatencyMillis:       float64(b.latency) / float64(time.Millisecond),
+	}
+	if b.lastErr != nil {
+		s.LastError = b.lastErr.Error()
+		failedAt := b.failedAt.UTC()
+		s.LastFailureAt = &failedAt
+	}
+	if !b.okAt.IsZero() {
+		okAt := b.okAt.UTC()
+		s.LastSuccessAt = &okAt
+	}
+	return s
+}
@@ -0,0 +1,130 @@
+// backend/internal/dependency/set.go
+/*
+ * This file tracks the health of the service's downstream dependencies.
+ *
+ * A Set holds one Reporter per dependency: a Breaker, or a component that
+ * summarises several (the webhook dispatcher has a breaker per endpoint).
+ * Dependencies registered with a health check are checked every interval
+ * by Run; the outcome feeds their breaker, so a database that went away
+ * opens its circuit before a request has to find out, and one that came
+ * back closes it without waiting for traffic.
+ *
+ * Status is what GET /healthz reports.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package dependency
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// Reporter reports the health of a dependency.
+type Reporter interface {
+	Status() models.DependencyStatus
+}
+
+// Check probes a dependency, e.g. by pinging a database.
+type Check func(ctx context.Context) error
+
+// checked is a breaker with its health check.
+type checked struct {
+	breaker *Breaker
+	check   Check
+}
+
+// Set is the service's dependencies. The zero value is empty and ready.
+type Set struct {
+	mu        sync.Mutex
+	reporters []Reporter
+	checks    []checked
+}
+
+// Add registers a dependency that reports its own health.
+func (s *Set) Add(r Reporter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reporters = append(s.reporters, r)
+}
+
+// Breaker registers a dependency guarded by a new breaker, checked with
+// check by Run when check is not nil, and returns the breaker.
+func (s *Set) Breaker(name string, cfg BreakerConfig, check Check) *Breaker {
+	b := NewBreaker(name, cfg)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reporters = append(s.reporters, b)
+	if check != nil {
+		s.checks = append(s.checks, checked{breaker: b, check: check})
+	}
+	return b
+}
+
+// Run checks the dependencies with health checks every interval, each
+// within timeout, until ctx is done.
+func (s *Set) Run(ctx context.Context, interval, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.checkAll(ctx, timeout)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkAll runs every health check concurrently, so one hung dependency
+// does not delay the others' verdicts.
+func (s *Set) checkAll(ctx context.Context, timeout time.Duration) {
+	s.mu.Lock()
+	checks := append([]checked(nil), s.checks...)
+	s.mu.Unlock()
+	var wg sync.WaitGroup
+	for _, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wasOpen := c.breaker.Open()
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			start := time.Now()
+			err := c.check(checkCtx)
+			if ctx.Err() != nil {
+				return
+			}
+			c.breaker.Record(err, time.Since(start))
+			switch open := c.breaker.Open(); {
+			case open && !wasOpen:
+				log.Printf("Dependency %s unhealthy, circuit open: %v", c.breaker.Name(), err)
+			case !open && wasOpen:
+				log.Printf("Dependency %s healthy again, circuit closed", c.breaker.Name())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Status reports every dependency, in registration order.
+func (s *Set) Status() []models.DependencyStatus {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	reporters := append([]Reporter(nil), s.reporters...)
+	s.mu.Unlock()
+	statuses := make([]models.DependencyStatus, len(reporters))
+	for i, r := range reporters {
+		statuses[i] = r.Status()
+	}
+	return statuses
+}
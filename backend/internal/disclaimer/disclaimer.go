@@ -0,0 +1,91 @@
+// backend/internal/disclaimer/disclaimer.go
+/*
+ * This file contains the catalog of jurisdiction-specific disclaimers.
+ *
+ * Regulatory wording differs by market, so the text attached to results is
+ * configured rather than hard-coded. The catalog is a JSON file mapping
+ * jurisdictions to their wording and tenants to their jurisdiction:
+ *
+ *   {
+ *     "default": "intl",
+ *     "jurisdictions": {
+ *       "intl": "For research use only.",
+ *       "eu":   "CE-marked decision support. Not a diagnosis."
+ *     },
+ *     "tenants": {"clinic-berlin": "eu"}
+ *   }
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package disclaimer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Catalog resolves the disclaimer text for a tenant. A nil Catalog returns
+// no text, so callers need not check whether disclaimers are configured.
+type Catalog struct {
+	// Default is the jurisdiction used for tenants without an entry.
+	Default string `json:"default"`
+	// Jurisdictions maps a jurisdiction code to its disclaimer text.
+	Jurisdictions map[string]string `json:"jurisdictions"`
+	// Tenants maps a tenant ID to its jurisdiction code.
+	Tenants map[string]string `json:"tenants"`
+}
+
+// Load reads and validates a catalog file.
+func Load(path string) (*Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Catalog
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Validate checks that every referenced jurisdiction has wording, so a
+// typo is caught at startup rather than silently omitting the text.
+func (c *Catalog) Validate() error {
+	if c.Default != "" {
+		if _, ok := c.Jurisdictions[c.Default]; !ok {
+			return fmt.Errorf("default jurisdiction %q has no disclaimer text", c.Default)
+		}
+	}
+	for tenant, j := range c.Tenants {
+		if _, ok := c.Jurisdictions[j]; !ok {
+			return fmt.Errorf("tenant %q: jurisdiction %q has no disclaimer text", tenant, j)
+		}
+	}
+	return nil
+}
+
+// Jurisdiction returns the jurisdiction code that applies to a tenant.
+func (c *Catalog) Jurisdiction(tenant string) string {
+	if c == nil {
+		return ""
+	}
+	if j, ok := c.Tenants[tenant]; ok {
+		return j
+	}
+	return c.Default
+}
+
+// For returns the disclaimer text for a tenant, or "" if none applies.
+func (c *Catalog) For(tenant string) string {
+	if c == nil {
+		return ""
+	}
+	return c.Jurisdictions[c.Jurisdiction(tenant)]
+}
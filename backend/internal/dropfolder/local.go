@@ -0,0 +1,72 @@
+// backend/internal/dropfolder/local.go
+/*
+ * This file implements the drop folder on a local or mounted directory
+ * (NFS, SMB/CIFS share, ...).
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package dropfolder
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Dir is a drop folder on the local filesystem.
+type Dir string
+
+// List implements FS.
+func (d Dir) List(context.Context) ([]File, error) {
+	entries, err := os.ReadDir(string(d))
+	if err != nil {
+		return nil, err
+	}
+	var files []File
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			// Removed between the listing and the stat.
+			continue
+		}
+		files = append(files, File{Name: e.Name(), Size: info.Size(), ModTime: info.ModTime()})
+	}
+	return files, nil
+}
+
+// Read implements FS.
+func (d Dir) Read(_ context.Context, name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(string(d), name))
+}
+
+// Write implements FS.
+func (d Dir) Write(_ context.Context, name string, data []byte) error {
+	tmp, err := os.CreateTemp(string(d), ".mammoscan-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	// Partners read the results with their own accounts.
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(string(d), name))
+}
+
+func (d Dir) String() string {
+	return fmt.Sprintf("dir %s", string(d))
+}
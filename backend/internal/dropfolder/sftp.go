@@ -0,0 +1,175 @@
+//go:build !edge
+
+// backend/internal/dropfolder/sftp.go
+/*
+ * This file implements the drop folder on a remote SFTP server.
+ *
+ * The connection is opened lazily and dropped on any error, so a server
+ * restart or network blip costs one failed poll rather than the watcher.
+ * The server's host key is always verified. Not built into the edge
+ * profile, which runs disconnected.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package dropfolder
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// SFTPConfig locates and authenticates to an SFTP drop folder.
+type SFTPConfig struct {
+	// Addr is the server as host:port.
+	Addr string
+	User string
+	// Dir is the drop folder on the server.
+	Dir string
+	// Auth lists the authentication methods to offer (key, password).
+	Auth []ssh.AuthMethod
+	// HostKey verifies the server, typically from a known_hosts file.
+	HostKey ssh.HostKeyCallback
+}
+
+// SFTP is a drop folder on an SFTP server.
+type SFTP struct {
+	cfg SFTPConfig
+
+	mu     sync.Mutex
+	conn   *ssh.Client
+	client *sftp.Client
+}
+
+// NewSFTP returns an SFTP drop folder. It does not connect until first use.
+func NewSFTP(cfg SFTPConfig) (*SFTP, error) {
+	if cfg.HostKey == nil {
+		return nil, fmt.Errorf("sftp drop folder: a host key callback is required")
+	}
+	return &SFTP{cfg: cfg}, nil
+}
+
+// List implements FS.
+func (s *SFTP) List(ctx context.Context) ([]File, error) {
+	var files []File
+	err := s.do(func(c *sftp.Client) error {
+		entries, err := c.ReadDir(s.cfg.Dir)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if e.Mode().IsRegular() {
+				files = append(files, File{Name: e.Name(), Size: e.Size(), ModTime: e.ModTime()})
+			}
+		}
+		return nil
+	})
+	return files, err
+}
+
+// Read implements FS.
+func (s *SFTP) Read(ctx context.Context, name string) ([]byte, error) {
+	var data []byte
+	err := s.do(func(c *sftp.Client) error {
+		f, err := c.Open(path.Join(s.cfg.Dir, name))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		data, err = io.ReadAll(f)
+		return err
+	})
+	return data, err
+}
+
+// Write implements FS.
+func (s *SFTP) Write(ctx context.Context, name string, data []byte) error {
+	return s.do(func(c *sftp.Client) error {
+		tmp := path.Join(s.cfg.Dir, fmt.Sprintf(".mammoscan-%d.tmp", time.Now().UnixNano()))
+		f, err := c.Create(tmp)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(data); err != nil {
+			f.Close()
+			c.Remove(tmp)
+			return err
+		}
+		if err := f.Close(); err != nil {
+			c.Remove(tmp)
+			return err
+		}
+		// Plain SFTP rename fails if the target exists; the POSIX
+		// extension replaces it atomically where the server supports it.
+		dest := path.Join(s.cfg.Dir, name)
+		if err := c.PosixRename(tmp, dest); err != nil {
+			c.Remove(dest)
+			if err := c.Rename(tmp, dest); err != nil {
+				c.Remove(tmp)
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close closes the connection, if open.
+func (s *SFTP) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reset()
+	return nil
+}
+
+func (s *SFTP) String() string {
+	return fmt.Sprintf("sftp://%s@%s%s", s.cfg.User, s.cfg.Addr, s.cfg.Dir)
+}
+
+// do runs fn on a connected client and drops the connection if fn fails,
+// so the next call reconnects.
+func (s *SFTP) do(fn func(*sftp.Client) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.client == nil {
+		conn, err := ssh.Dial("tcp", s.cfg.Addr, &ssh.ClientConfig{
+			User:            s.cfg.User,
+			Auth:            s.cfg.Auth,
+			HostKeyCallback: s.cfg.HostKey,
+			Timeout:         30 * time.Second,
+		})
+		if err != nil {
+			return fmt.Errorf("connect: %w", err)
+		}
+		client, err := sftp.NewClient(conn)
+		if err != nil {
+			conn.Close()
+			return fmt.Errorf("start sftp: %w", err)
+		}
+		s.conn, s.client = conn, client
+	}
+	if err := fn(s.client); err != nil {
+		s.reset()
+		return err
+	}
+	return nil
+}
+
+// reset must be called with s.mu held.
+func (s *SFTP) reset() {
+	if s.client != nil {
+		s.client.Close()
+	}
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	s.client, s.conn = nil, nil
+}
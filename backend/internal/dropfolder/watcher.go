@@ -0,0 +1,199 @@
+// backend/internal/dropfolder/watcher.go
+/*
+ * This file implements the drop-folder ingestion watcher.
+ *
+ * Some partners can only exchange files: they drop images into a shared
+ * folder (a mounted share or an SFTP directory) and pick up results from
+ * the same place. The watcher polls the folder, scores every new image and
+ * writes the result next to it:
+ *
+ *   study-123.png               dropped by the partner
+ *   study-123.png.result.json   the prediction response
+ *   study-123.png.error.json    written instead if the study was refused
+ *
+ * A file is only picked up once its size and modification time are
+ * unchanged between two polls, so half-copied uploads are left alone. A
+ * file that already has a result or error file is considered done;
+ * deleting the error file retries it. Server-side failures (5xx) write no
+ * error file and are retried on the next poll. Polling rather than filesystem
+ * notifications is deliberate: notifications are unreliable on network
+ * shares and do not exist over SFTP.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package dropfolder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// Result and error file suffixes.
+const (
+	ResultSuffix = ".result.json"
+	ErrorSuffix  = ".error.json"
+)
+
+// File describes one entry of the folder.
+type File struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+// FS is the folder being watched. Names are relative to the folder root.
+type FS interface {
+	// List returns the regular files directly in the folder.
+	List(ctx context.Context) ([]File, error)
+	// Read returns the content of a file.
+	Read(ctx context.Context, name string) ([]byte, error)
+	// Write creates or replaces a file. Implementations write to a
+	// temporary name and rename, so readers never see a partial result.
+	Write(ctx context.Context, name string, data []byte) error
+	// String describes the folder for log messages.
+	String() string
+}
+
+// ScoreFunc scores one file and returns the HTTP status and JSON body of
+// the prediction response.
+type ScoreFunc func(ctx context.Context, name string, data []byte) (int, []byte)
+
+// Config controls the watcher.
+type Config struct {
+	// Interval is the time between polls.
+	Interval time.Duration
+	// MaxFileSize skips larger files (they get an error file).
+	MaxFileSize int64
+}
+
+// Watcher polls a folder and scores new files.
+type Watcher struct {
+	fs    FS
+	cfg   Config
+	score ScoreFunc
+
+	// seen holds the size and time of candidate files at the last poll.
+	seen map[string]File
+}
+
+// New creates a watcher.
+func New(fs FS, cfg Config, score ScoreFunc) *Watcher {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	return &Watcher{fs: fs, cfg: cfg, score: score, seen: make(map[string]File)}
+}
+
+// Run polls until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) {
+	log.Printf("Drop folder: watching %s every %s", w.fs, w.cfg.Interval)
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := w.Poll(ctx); err != nil {
+			log.Printf("Drop folder: poll %s: %v", w.fs, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll lists the folder once and scores every file that is complete and
+// has no result yet.
+func (w *Watcher) Poll(ctx context.Context) error {
+	files, err := w.fs.List(ctx)
+	if err != nil {
+		return err
+	}
+	done := make(map[string]bool)
+	for _, f := range files {
+		if base, ok := strings.CutSuffix(f.Name, ResultSuffix); ok {
+			done[base] = true
+		} else if base, ok := strings.CutSuffix(f.Name, ErrorSuffix); ok {
+			done[base] = true
+		}
+	}
+
+	seen := make(map[string]File)
+	for _, f := range files {
+		if done[f.Name] || !candidate(f.Name) {
+			continue
+		}
+		// Only files unchanged since the previous poll are complete.
+		if prev, ok := w.seen[f.Name]; !ok || prev.Size != f.Size || !prev.ModTime.Equal(f.ModTime) {
+			seen[f.Name] = f
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !w.process(ctx, f) {
+			seen[f.Name] = f
+		}
+	}
+	w.seen = seen
+	return nil
+}
+
+// process scores one file and writes its result or error file. It
+// reports false if the file should be retried.
+func (w *Watcher) process(ctx context.Context, f File) bool {
+	var status int
+	var body []byte
+	if w.cfg.MaxFileSize > 0 && f.Size > w.cfg.MaxFileSize {
+		status, body = http.StatusRequestEntityTooLarge, errorBody("upload_too_large", fmt.Sprintf("file exceeds %d bytes", w.cfg.MaxFileSize))
+	} else if data, err := w.fs.Read(ctx, f.Name); err != nil {
+		log.Printf("Drop folder: read %s: %v", f.Name, err)
+		return false
+	} else {
+		status, body = w.score(ctx, f.Name, data)
+	}
+	if status >= http.StatusInternalServerError {
+		log.Printf("Drop folder: %s failed (%d), will retry: %s", f.Name, status, body)
+		return false
+	}
+
+	out := f.Name + ResultSuffix
+	if status != http.StatusOK {
+		out = f.Name + ErrorSuffix
+	}
+	if err := w.fs.Write(ctx, out, body); err != nil {
+		log.Printf("Drop folder: write %s: %v", out, err)
+		return false
+	}
+	log.Printf("Drop folder: %s -> %s (%d)", f.Name, out, status)
+	return true
+}
+
+// candidate reports whether a file name looks like a dropped study:
+// hidden files, our own output and in-progress transfers are skipped.
+func candidate(name string) bool {
+	base := path.Base(name)
+	if strings.HasPrefix(base, ".") || strings.HasPrefix(base, "~") {
+		return false
+	}
+	switch strings.ToLower(path.Ext(base)) {
+	case ".json", ".tmp", ".part", ".filepart", ".crdownload":
+		return false
+	}
+	return true
+}
+
+func errorBody(code, message string) []byte {
+	body, _ := json.Marshal(models.ErrorResponse{Error: message, Code: code})
+	return body
+}
@@ -0,0 +1,137 @@
+// backend/internal/ensemble/config.go
+/*
+ * This file reads the ensemble configuration.
+ *
+ * An ensemble is described in JSON:
+ *
+ *   {
+ *     "method": "weighted_mean",
+ *     "served_weight": 1,
+ *     "threshold": 0.3,
+ *     "max_disagreement": 0.2,
+ *     "models": [
+ *       {"name": "efficientnet", "source": "gs://models/effnet.onnx", "weight": 2}
+ *     ]
+ *   }
+ *
+ * Only "models" is required. Weights default to 1. "threshold" labels the
+ * weighted mean score and defaults to the served model's threshold; a
+ * model's own "threshold", used for its label and its vote, defaults to
+ * the threshold resolved for it like any other model's.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package ensemble
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/threshold"
+)
+
+// Config is the JSON description of an ensemble.
+type Config struct {
+	Method          Method        `json:"method,omitempty"`
+	ServedWeight    *float64      `json:"served_weight,omitempty"`
+	Threshold       float64       `json:"threshold,omitempty"`
+	MaxDisagreement float64       `json:"max_disagreement,omitempty"`
+	Models          []ModelConfig `json:"models"`
+}
+
+// ModelConfig describes one additional model.
+type ModelConfig struct {
+	// Name defaults to the file name of Source.
+	Name      string   `json:"name,omitempty"`
+	Source    string   `json:"source"`
+	Weight    *float64 `json:"weight,omitempty"`
+	Threshold float64  `json:"threshold,omitempty"`
+}
+
+// ParseConfig reads and validates an ensemble configuration.
+func ParseConfig(data []byte) (Config, error) {
+	var cfg Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// Validate checks the method, weights and thresholds.
+func (c Config) Validate() error {
+	if _, err := ParseMethod(string(c.Method)); err != nil {
+		return err
+	}
+	if len(c.Models) == 0 {
+		return fmt.Errorf("no models")
+	}
+	if c.MaxDisagreement != 0 && !(c.MaxDisagreement > 0 && c.MaxDisagreement < 1) {
+		return fmt.Errorf("max_disagreement must be between 0 and 1, got %v", c.MaxDisagreement)
+	}
+	if c.Threshold != 0 {
+		if err := threshold.Check(c.Threshold); err != nil {
+			return err
+		}
+	}
+	total := weight(c.ServedWeight)
+	if err := checkWeight(total); err != nil {
+		return fmt.Errorf("served_weight: %w", err)
+	}
+	names := make(map[string]bool)
+	for i, m := range c.Models {
+		if m.Source == "" {
+			return fmt.Errorf("model %d: no source", i+1)
+		}
+		if m.Name != "" {
+			if names[m.Name] {
+				return fmt.Errorf("model %s listed twice", m.Name)
+			}
+			names[m.Name] = true
+		}
+		if err := checkWeight(weight(m.Weight)); err != nil {
+			return fmt.Errorf("model %s: %w", m.Source, err)
+		}
+		if m.Threshold != 0 {
+			if err := threshold.Check(m.Threshold); err != nil {
+				return fmt.Errorf("model %s: %w", m.Source, err)
+			}
+		}
+		total += weight(m.Weight)
+	}
+	if total == 0 {
+		return fmt.Errorf("every model has weight zero")
+	}
+	return nil
+}
+
+// ServedModelWeight returns the served model's weight.
+func (c Config) ServedModelWeight() float64 {
+	return weight(c.ServedWeight)
+}
+
+// ModelWeight returns the model's weight.
+func (m ModelConfig) ModelWeight() float64 {
+	return weight(m.Weight)
+}
+
+// weight returns w, 1 when unset.
+func weight(w *float64) float64 {
+	if w == nil {
+		return 1
+	}
+	return *w
+}
+
+func checkWeight(w float64) error {
+	if w < 0 || math.IsInf(w, 0) || math.IsNaN(w) {
+		return fmt.Errorf("weight must be zero or positive, got %v", w)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+// backend/internal/ensemble/ensemble.go
+/*
+ * This file implements model ensembles.
+ *
+ * When additional models are configured, each of them also scores the
+ * study and we measure how far apart the scores are. Models that disagree
+ * strongly are our best proxy for a hard case, so such studies are
+ * flagged for human review. Disagreement is the largest absolute
+ * difference in confidence score between any two models, including the
+ * served one.
+ *
+ * Who decides depends on the method:
+ *
+ *   review         the served model decides; the others only flag
+ *                  disagreement (the default)
+ *   weighted_mean  the weighted mean of all scores, labelled with the
+ *                  ensemble's decision threshold
+ *   majority_vote  every model votes with its own label and threshold;
+ *                  the score is the weighted share of malignant votes.
+ *                  A tied vote is labelled malignant, the costlier miss,
+ *                  and flagged for review.
+ *
+ * Each model, the served one included, has a weight (default 1); a model
+ * of weight zero is scored and compared but does not decide.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package ensemble
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sync"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+	"gorgonia.org/tensor"
+)
+
+// DefaultMaxDisagreement is the score gap above which a study is flagged.
+const DefaultMaxDisagreement = 0.2
+
+// Method is how the ensemble reaches its decision.
+type Method string
+
+// Ensemble methods.
+const (
+	MethodReview       Method = "review"
+	MethodWeightedMean Method = "weighted_mean"
+	MethodMajorityVote Method = "majority_vote"
+)
+
+// ParseMethod validates a method name; empty means MethodReview.
+func ParseMethod(s string) (Method, error) {
+	switch m := Method(s); m {
+	case "":
+		return MethodReview, nil
+	case MethodReview, MethodWeightedMean, MethodMajorityVote:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown ensemble method %q (want %s, %s or %s)", s, MethodReview, MethodWeightedMean, MethodMajorityVote)
+}
+
+// Predictor scores a preprocessed image; *inference.ONNXInference
+// satisfies it.
+type Predictor interface {
+	Predict(input tensor.Tensor) ([]float32, error)
+}
+
+// Member is one additional model of the ensemble.
+type Member struct {
+	Name string
+	// Source is the reference the model was loaded from.
+	Source string
+	Engine Predictor
+	// Weight is the member's say in the decision.
+	Weight float64
+	// Threshold labels the member's score; zero uses the ensemble's.
+	Threshold float64
+}
+
+// Ensemble scores studies with its members and flags disagreement.
+type Ensemble struct {
+	Members []Member
+	// MaxDisagreement is the largest tolerated pairwise score gap.
+	MaxDisagreement float64
+	// Threshold labels member scores, as for the served model.
+	Threshold float64
+	// Method decides how the ensemble decides.
+	Method Method
+	// ServedWeight is the served model's say in the decision.
+	ServedWeight float64
+	// DecisionThreshold labels the weighted mean score; zero uses
+	// Threshold.
+	DecisionThreshold float64
+}
+
+// Decides reports whether the ensemble, rather than the served model,
+// decides predictions.
+func (e *Ensemble) Decides() bool {
+	return e.Method == MethodWeightedMean || e.Method == MethodMajorityVote
+}
+
+// Result is the outcome of scoring one study with the whole ensemble.
+type Result struct {
+	// Scores holds the served model's score followed by each member's.
+	Scores       []models.ModelScore
+	Disagreement float64
+	NeedsReview  bool
+	// Decision is the ensemble's decision, nil with MethodReview.
+	Decision *models.EnsembleDecision
+}
+
+// Evaluate scores img with every member, each preprocessing it the way it
+// was trained, and compares the scores with the served model's. Members
+// run concurrently; any member failure fails the evaluation, since
+// disagreement cannot then be assessed.
+func (e *Ensemble) Evaluate(img image.Image, served models.ModelScore) (Result, error) {
+	scores := make([]models.ModelScore, len(e.Members)+1)
+	scores[0] = served
+	errs := make([]error, len(e.Members))
+
+	var wg sync.WaitGroup
+	for i, m := range e.Members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out, err := m.Engine.Predict(preprocess.TensorFor(m.Engine, img))
+			if err == nil && len(out) == 0 {
+				err = fmt.Errorf("empty output")
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("ensemble model %s: %w", m.Name, err)
+				return
+			}
+			score := float64(out[0])
+			threshold := m.Threshold
+			if threshold == 0 {
+				threshold = e.Threshold
+			}
+			scores[i+1] = models.ModelScore{
+				ModelName:       m.Name,
+				ConfidenceScore: score,
+				Prediction:      models.LabelFor(score, threshold),
+			}
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return Result{}, err
+		}
+	}
+
+	d := Disagreement(scores)
+	result := Result{Scores: scores, Disagreement: d, NeedsReview: d > e.MaxDisagreement}
+	if e.Decides() {
+		weights := make([]float64, len(scores))
+		weights[0] = e.ServedWeight
+		for i, m := range e.Members {
+			weights[i+1] = m.Weight
+		}
+		for i := range scores {
+			scores[i].Weight = weights[i]
+		}
+		var tied bool
+		result.Decision, tied = e.decide(scores, weights)
+		result.NeedsReview = result.NeedsReview || tied
+	}
+	return result, nil
+}
+
+// decide combines the weighted scores with the ensemble's method, and
+// reports a tied vote.
+func (e *Ensemble) decide(scores []models.ModelScore, weights []float64) (*models.EnsembleDecision, bool) {
+	var total, sum float64
+	for i, s := range scores {
+		total += weights[i]
+		if e.Method == MethodMajorityVote {
+			if s.Prediction == models.LabelCancer {
+				sum += weights[i]
+			}
+		} else {
+			sum += weights[i] * s.ConfidenceScore
+		}
+	}
+	score := sum / total
+	if e.Method == MethodMajorityVote {
+		const half = 0.5
+		tied := math.Abs(score-half) < 1e-9
+		label := models.LabelFor(score, half)
+		if tied {
+			label = models.LabelCancer
+		}
+		return &models.EnsembleDecision{Method: string(e.Method), ConfidenceScore: score, Prediction: label, Threshold: half}, tied
+	}
+	threshold := e.DecisionThreshold
+	if threshold == 0 {
+		threshold = e.Threshold
+	}
+	return &models.EnsembleDecision{
+		Method:          string(e.Method),
+		ConfidenceScore: score,
+		Prediction:      models.LabelFor(score, threshold),
+		Threshold:       threshold,
+	}, false
+}
+
+// Disagreement returns the largest pairwise score gap, which is the
+// spread between the highest and lowest score.
+func Disagreement(scores []models.ModelScore) float64 {
+	if len(scores) == 0 {
+		return 0
+	}
+	lo, hi := scores[0].ConfidenceScore, scores[0].ConfidenceScore
+	for _, s := range scores[1:] {
+		lo, hi = min(lo, s.ConfidenceScore), max(hi, s.ConfidenceScore)
+	}
+	return hi - lo
+}
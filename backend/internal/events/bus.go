@@ -0,0 +1,268 @@
+// backend/internal/events/bus.go
+/*
+ * This file implements the internal event bus.
+ *
+ * Handlers and background subsystems publish what happened (a prediction
+ * completed, a job failed, scores drifted, the model changed) and every
+ * cross-cutting consumer -- billing, webhooks, and whatever sink comes next
+ * -- subscribes to the event types it cares about. Producers no longer
+ * need to know who is listening.
+ *
+ * Each subscriber has its own buffered queue and goroutine, so a slow sink
+ * only ever delays itself. Publishing never blocks: when a subscriber's
+ * queue is full the event is dropped for that subscriber and counted.
+ * The exception are synchronous subscribers, which persist events that
+ * must not be lost (the webhook outbox): they run inside Publish, so the
+ * event is durable before the request that produced it is answered.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package events
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// Event types.
+const (
+	// PredictionCompleted carries a Prediction.
+	PredictionCompleted = "prediction.completed"
+	// FrameScored carries a Frame.
+	FrameScored = "stream.frame_scored"
+	// JobFailed carries the failed jobs.Job.
+	JobFailed = "job.failed"
+	// DriftDetected carries the stats.DriftStats that crossed into a
+	// significant shift. It is service-wide and has no tenant.
+	DriftDetected = "drift.detected"
+	// ModelSwapped carries a ModelSwap.
+	ModelSwapped = "model.swapped"
+	// ModelStale carries the models.ModelAge of a served model found
+	// older than the maximum age. It is service-wide and has no tenant.
+	ModelStale = "model.stale"
+	// AuthFailed carries an AuthFailure. Its tenant is the one the
+	// request claimed, if any.
+	AuthFailed = "security.auth_failed"
+	// AuthLockedOut carries a Lockout.
+	AuthLockedOut = "security.lockout"
+	// BreakGlassAccess carries a BreakGlass. Its tenant is the one whose
+	// data was accessed.
+	BreakGlassAccess = "security.break_glass"
+	// LegalHoldPlaced and LegalHoldReleased carry the legalhold.Hold.
+	LegalHoldPlaced   = "legal_hold.placed"
+	LegalHoldReleased = "legal_hold.released"
+	// LegalHoldBlocked carries a HeldDeletion.
+	LegalHoldBlocked = "legal_hold.blocked"
+)
+
+// Event is one published occurrence.
+type Event struct {
+	ID   string
+	Type string
+	// Tenant is the tenant the event concerns; empty for service-wide
+	// events.
+	Tenant string
+	Time   time.Time
+	Data   any
+}
+
+// New returns an event with its ID and time filled in, for publishers
+// that need them before publishing it.
+func New(typ, tenant string, data any) Event {
+	return Event{ID: newID(), Type: typ, Tenant: tenant, Time: time.Now().UTC(), Data: data}
+}
+
+// Prediction is the payload of PredictionCompleted.
+type Prediction struct {
+	Response    models.PredictionResponse
+	StudyID     string
+	ComputeTime time.Duration
+}
+
+// Frame is the payload of FrameScored.
+type Frame struct {
+	Frame       models.FramePrediction
+	ComputeTime time.Duration
+}
+
+// ModelSwap is the payload of ModelSwapped.
+type ModelSwap struct {
+	Previous models.ModelInfo
+	Current  models.ModelInfo
+}
+
+// AuthFailure is the payload of AuthFailed.
+type AuthFailure struct {
+	ClientIP string `json:"client_ip"`
+	// KeyHint identifies the presented credential without revealing it.
+	KeyHint string `json:"key_hint,omitempty"`
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Reason  string `json:"reason"`
+	// Failures is the number of recent failures from the client address.
+	Failures int `json:"failures"`
+}
+
+// Lockout is the payload of AuthLockedOut.
+type Lockout struct {
+	// Subject is what was locked out: "ip" or "key".
+	Subject  string    `json:"subject"`
+	ClientIP string    `json:"client_ip,omitempty"`
+	KeyHint  string    `json:"key_hint,omitempty"`
+	Lockouts int       `json:"lockouts"`
+	Until    time.Time `json:"until"`
+}
+
+// BreakGlass is the payload of BreakGlassAccess: emergency access granted
+// on a stated justification.
+type BreakGlass struct {
+	Principal       string `json:"principal"`
+	PrincipalTenant string `json:"principal_tenant,omitempty"`
+	Method          string `json:"method"`
+	Path            string `json:"path"`
+	Justification   string `json:"justification"`
+	ClientIP        string `json:"client_ip"`
+	RequestID       string `json:"request_id,omitempty"`
+}
+
+// HeldDeletion is the payload of LegalHoldBlocked: a deletion refused
+// because the prediction is under legal hold.
+type HeldDeletion struct {
+	PredictionID string `json:"prediction_id"`
+	// Action is "delete" or "purge".
+	Action string   `json:"action"`
+	Actor  string   `json:"actor,omitempty"`
+	Holds  []string `json:"holds"`
+}
+
+// DefaultBuffer is the per-subscriber queue length used when none is given.
+const DefaultBuffer = 1024
+
+// subscription is one subscriber's queue.
+type subscription struct {
+	name  string
+	types []string
+	queue chan Event
+	// sync, when set, is called inside Publish instead of queueing.
+	sync    func(Event)
+	dropped atomic.Int64
+}
+
+// Bus routes published events to subscribers. The zero value is not
+// usable; a nil *Bus accepts and discards events.
+type Bus struct {
+	mu   sync.RWMutex
+	subs []*subscription
+}
+
+// NewBus creates an empty bus.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers fn for the given event types (all types if none are
+// given). fn runs on the subscription's own goroutine, one event at a
+// time, in publish order. buffer is the queue length; zero selects
+// DefaultBuffer.
+func (b *Bus) Subscribe(name string, buffer int, fn func(Event), types ...string) {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	s := &subscription{name: name, types: types, queue: make(chan Event, buffer)}
+	go func() {
+		for ev := range s.queue {
+			fn(ev)
+		}
+	}()
+
+	b.mu.Lock()
+	b.subs = append(b.subs, s)
+	b.mu.Unlock()
+}
+
+// SubscribeSync registers fn for the given event types (all types if none
+// are given) to run inside Publish, on the publisher's goroutine. fn must
+// be quick and must not wait on the network: it delays the publisher.
+func (b *Bus) SubscribeSync(name string, fn func(Event), types ...string) {
+	b.mu.Lock()
+	b.subs = append(b.subs, &subscription{name: name, types: types, sync: fn})
+	b.mu.Unlock()
+}
+
+// Publish hands ev to every interested subscriber without blocking on
+// asynchronous ones. The ID and time are filled in when unset.
+func (b *Bus) Publish(ev Event) {
+	b.PublishContext(context.Background(), ev)
+}
+
+// PublishContext is Publish for an event raised while handling a request:
+// what it logs carries the request ID of ctx.
+func (b *Bus) PublishContext(ctx context.Context, ev Event) {
+	if b == nil {
+		return
+	}
+	if ev.ID == "" {
+		ev.ID = newID()
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now().UTC()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, s := range b.subs {
+		if len(s.types) > 0 && !slices.Contains(s.types, ev.Type) {
+			continue
+		}
+		if s.sync != nil {
+			s.sync(ev)
+			continue
+		}
+		select {
+		case s.queue <- ev:
+		default:
+			s.dropped.Add(1)
+			slog.WarnContext(ctx, "events: subscriber queue full, event dropped",
+				"subscriber", s.name, "event_type", ev.Type, "event_id", ev.ID, "tenant", ev.Tenant)
+		}
+	}
+}
+
+// Dropped returns, per subscriber, how many events were discarded because
+// its queue was full. Subscribers that never dropped are omitted.
+func (b *Bus) Dropped() map[string]int64 {
+	if b == nil {
+		return nil
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	var out map[string]int64
+	for _, s := range b.subs {
+		if n := s.dropped.Load(); n > 0 {
+			if out == nil {
+				out = make(map[string]int64)
+			}
+			out[s.name] += n
+		}
+	}
+	return out
+}
+
+// newID returns a time-ordered event ID.
+func newID() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.NewString()
+	}
+	return id.String()
+}
@@ -0,0 +1,540 @@
+// backend/internal/experiment/experiment.go
+/*
+ * This file implements time-boxed model experiments.
+ *
+ * An admin defines an experiment: a candidate model, the share of traffic
+ * it should score, how long to run, and the metric that decides success.
+ * While it runs, each prediction is assigned to the "control" arm (the
+ * served model) or the "candidate" arm by hashing its ID, so assignment is
+ * random but reproducible. When the experiment expires, or is stopped
+ * early, both arms are tallied from the prediction store using the
+ * reviewer feedback recorded so far, and a winner is declared once each
+ * arm has enough reviewed studies.
+ *
+ * In shadow mode the candidate never answers: the served model scores
+ * every study as usual and the candidate scores the selected share again
+ * in the background, its score stored alongside the served one. Both
+ * models are then tallied on the same reviewed studies. Shadow scoring
+ * is bounded (Config.ShadowConcurrency); a study arriving while every
+ * slot is busy is not shadowed, so the candidate never slows the service
+ * down.
+ *
+ * Only one experiment runs at a time, so every prediction belongs to at
+ * most one of them and the arms stay comparable.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package experiment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/josephed37/mammoscan-AI/backend/internal/fairness"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/store"
+	"gorgonia.org/tensor"
+)
+
+// Arm names.
+const (
+	ArmControl   = "control"
+	ArmCandidate = "candidate"
+)
+
+// Status is the lifecycle state of an experiment.
+type Status string
+
+const (
+	// StatusStarting means the candidate model is still loading.
+	StatusStarting  Status = "starting"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusStopped   Status = "stopped"
+	StatusFailed    Status = "failed"
+)
+
+// Modes.
+const (
+	// ModeSplit routes the candidate's share of traffic to it.
+	ModeSplit = "split"
+	// ModeShadow scores the candidate's share with it in the background,
+	// without affecting the response.
+	ModeShadow = "shadow"
+)
+
+// DefaultShadowConcurrency bounds concurrent shadow scoring.
+const DefaultShadowConcurrency = 2
+
+// DefaultMinSamples is the number of reviewed studies each arm needs
+// before a winner is declared.
+const DefaultMinSamples = 30
+
+// checkInterval is how often Run looks for expired experiments.
+const checkInterval = 30 * time.Second
+
+var (
+	// ErrNotFound is returned for unknown experiment IDs.
+	ErrNotFound = errors.New("experiment not found")
+	// ErrActive is returned when starting an experiment while another runs.
+	ErrActive = errors.New("another experiment is already active")
+	// ErrInvalid is returned for an unusable definition.
+	ErrInvalid = errors.New("invalid experiment")
+	// ErrFinished is returned when stopping an experiment that has ended.
+	ErrFinished = errors.New("experiment has already finished")
+)
+
+// Predictor scores a preprocessed image; *inference.ONNXInference
+// satisfies it.
+type Predictor interface {
+	Predict(input tensor.Tensor) ([]float32, error)
+}
+
+// Definition is what an admin submits to start an experiment.
+type Definition struct {
+	Name string `json:"name" binding:"required"`
+	// TrafficPercent is the share of predictions scored by the candidate.
+	TrafficPercent float64 `json:"traffic_percent" binding:"required,gt=0,lte=100"`
+	// Mode is ModeSplit (the default) or ModeShadow.
+	Mode string `json:"mode,omitempty" binding:"omitempty,oneof=split shadow"`
+	// ModelRef locates the candidate model (local path or remote URI).
+	ModelRef  string  `json:"model_ref" binding:"required"`
+	ModelName string  `json:"model_name"`
+	Threshold float64 `json:"threshold"`
+	// Duration is how long the experiment runs, e.g. "168h".
+	Duration string `json:"duration" binding:"required"`
+	// SuccessMetric decides the winner; higher is better for each.
+	SuccessMetric string `json:"success_metric" binding:"required,oneof=sensitivity specificity accuracy"`
+	// MinSamples is the number of reviewed studies each arm needs.
+	MinSamples int `json:"min_samples"`
+	// Tenant, if set, limits the experiment to that tenant's traffic.
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// Experiment is the externally visible state of an experiment.
+type Experiment struct {
+	ID string `json:"id"`
+	Definition
+	Status    Status     `json:"status"`
+	Error     string     `json:"error,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Results   *Results   `json:"results,omitempty"`
+}
+
+// Active reports whether the experiment has not ended yet.
+func (e Experiment) Active() bool {
+	return e.Status == StatusStarting || e.Status == StatusRunning
+}
+
+// Results is the tally of both arms.
+type Results struct {
+	TalliedAt time.Time           `json:"tallied_at"`
+	Metric    string              `json:"metric"`
+	Control   fairness.GroupStats `json:"control"`
+	Candidate fairness.GroupStats `json:"candidate"`
+	// Difference is the candidate's metric minus the control's, when both
+	// are known.
+	Difference *float64 `json:"difference,omitempty"`
+	// Winner is "control", "candidate" or "inconclusive".
+	Winner string `json:"winner"`
+	Reason string `json:"reason"`
+}
+
+// Assignment tells the predict handler how to score one study.
+type Assignment struct {
+	ExperimentID string
+	Arm          string
+	// Engine, ModelName and Threshold are set for the candidate arm and
+	// for shadowed studies.
+	Engine    Predictor
+	ModelName string
+	Threshold float64
+	// Shadow means the served model answers and Engine scores the study
+	// again in the background; Arm is then ArmControl.
+	Shadow bool
+}
+
+// Config configures a Manager.
+type Config struct {
+	// Path, if set, persists experiments to a JSON file so a running
+	// experiment survives restarts.
+	Path string
+	// Store is tallied when an experiment ends.
+	Store store.Store
+	// Load fetches and loads a candidate model.
+	Load func(ctx context.Context, ref string) (Predictor, error)
+	// ShadowConcurrency bounds concurrent shadow scoring (default
+	// DefaultShadowConcurrency).
+	ShadowConcurrency int
+}
+
+// Manager runs experiments.
+type Manager struct {
+	cfg Config
+
+	mu          sync.RWMutex
+	experiments map[string]*Experiment
+	// active is the running or starting experiment, if any, and engine
+	// its loaded candidate model.
+	active *Experiment
+	engine Predictor
+
+	// shadowSlots holds a token per shadow scoring in progress.
+	shadowSlots chan struct{}
+}
+
+// NewManager creates a manager, restoring persisted experiments.
+func NewManager(cfg Config) (*Manager, error) {
+	if cfg.ShadowConcurrency <= 0 {
+		cfg.ShadowConcurrency = DefaultShadowConcurrency
+	}
+	m := &Manager{cfg: cfg, experiments: make(map[string]*Experiment), shadowSlots: make(chan struct{}, cfg.ShadowConcurrency)}
+	if cfg.Path == "" {
+		return m, nil
+	}
+	data, err := os.ReadFile(cfg.Path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read experiments: %w", err)
+	}
+	var list []*Experiment
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parse experiments %s: %w", cfg.Path, err)
+	}
+	for _, e := range list {
+		m.experiments[e.ID] = e
+		if e.Active() {
+			m.active = e
+		}
+	}
+	return m, nil
+}
+
+// Start validates def and launches the experiment. The candidate model is
+// loaded in the background; the experiment clock starts once it is ready.
+func (m *Manager) Start(def Definition) (Experiment, error) {
+	if d, err := time.ParseDuration(def.Duration); err != nil || d <= 0 {
+		return Experiment{}, fmt.Errorf("%w: duration %q is not a positive duration", ErrInvalid, def.Duration)
+	}
+	if def.ModelName == "" {
+		def.ModelName = def.ModelRef
+	}
+	if def.MinSamples <= 0 {
+		def.MinSamples = DefaultMinSamples
+	}
+	if def.Mode == "" {
+		def.Mode = ModeSplit
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.active != nil {
+		return Experiment{}, fmt.Errorf("%w: %s", ErrActive, m.active.ID)
+	}
+	e := &Experiment{
+		ID:         uuid.NewString(),
+		Definition: def,
+		Status:     StatusStarting,
+		CreatedAt:  time.Now().UTC(),
+	}
+	m.experiments[e.ID] = e
+	m.active = e
+	m.save()
+
+	go m.launch(e)
+	return *e, nil
+}
+
+// launch loads the candidate model and starts the experiment clock.
+func (m *Manager) launch(e *Experiment) {
+	engine, err := m.cfg.Load(context.Background(), e.ModelRef)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.active != e {
+		return // stopped while loading
+	}
+	if err != nil {
+		now := time.Now().UTC()
+		e.Status, e.Error, e.EndedAt = StatusFailed, fmt.Sprintf("load candidate model: %v", err), &now
+		m.active = nil
+		m.save()
+		log.Printf("Experiment %s failed: %s", e.ID, e.Error)
+		return
+	}
+	m.engine = engine
+	if e.Status == StatusStarting {
+		d, _ := time.ParseDuration(e.Duration)
+		now := time.Now().UTC()
+		ends := now.Add(d)
+		e.Status, e.StartedAt, e.EndsAt = StatusRunning, &now, &ends
+		m.save()
+	}
+	log.Printf("Experiment %s (%s) running until %s", e.ID, e.Name, e.EndsAt.Format(time.RFC3339))
+}
+
+// Assign decides which arm scores a study. ok is false when no experiment
+// applies to it, including on a nil Manager.
+func (m *Manager) Assign(predictionID, tenant string) (Assignment, bool) {
+	if m == nil {
+		return Assignment{}, false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	e := m.active
+	if e == nil || e.Status != StatusRunning || m.engine == nil {
+		return Assignment{}, false
+	}
+	if e.Tenant != "" && e.Tenant != tenant {
+		return Assignment{}, false
+	}
+	a := Assignment{ExperimentID: e.ID, Arm: ArmControl}
+	selected := bucket(predictionID) < e.TrafficPercent
+	if e.Mode == ModeShadow {
+		if !selected {
+			// Only shadowed studies take part.
+			return Assignment{}, false
+		}
+		a.Shadow, a.Engine, a.ModelName, a.Threshold = true, m.engine, e.ModelName, e.Threshold
+		return a, true
+	}
+	if selected {
+		a.Arm, a.Engine, a.ModelName, a.Threshold = ArmCandidate, m.engine, e.ModelName, e.Threshold
+	}
+	return a, true
+}
+
+// ShadowSlot reserves one of the shadow scoring slots; call release when
+// done. ok is false when every slot is busy.
+func (m *Manager) ShadowSlot() (release func(), ok bool) {
+	select {
+	case m.shadowSlots <- struct{}{}:
+		return func() { <-m.shadowSlots }, true
+	default:
+		return nil, false
+	}
+}
+
+// bucket maps an ID uniformly onto [0, 100).
+func bucket(id string) float64 {
+	h := fnv.New64a()
+	h.Write([]byte(id))
+	return float64(h.Sum64()%10000) / 100
+}
+
+// Stop ends an experiment early and tallies it.
+func (m *Manager) Stop(ctx context.Context, id string) (Experiment, error) {
+	m.mu.Lock()
+	e, ok := m.experiments[id]
+	if !ok {
+		m.mu.Unlock()
+		return Experiment{}, ErrNotFound
+	}
+	if !e.Active() {
+		m.mu.Unlock()
+		return *e, ErrFinished
+	}
+	m.end(e, StatusStopped)
+	m.mu.Unlock()
+
+	return m.tally(ctx, e), nil
+}
+
+// Get returns the experiment with the given ID.
+func (m *Manager) Get(id string) (Experiment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	e, ok := m.experiments[id]
+	if !ok {
+		return Experiment{}, ErrNotFound
+	}
+	return *e, nil
+}
+
+// List returns all experiments, newest first.
+func (m *Manager) List() []Experiment {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]Experiment, 0, len(m.experiments))
+	for _, e := range m.experiments {
+		out = append(out, *e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out
+}
+
+// Run resumes a persisted experiment and ends experiments as they expire,
+// until ctx is cancelled.
+func (m *Manager) Run(ctx context.Context) {
+	m.mu.RLock()
+	resume := m.active
+	m.mu.RUnlock()
+	if resume != nil {
+		go m.launch(resume)
+	}
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.expire(ctx)
+		}
+	}
+}
+
+func (m *Manager) expire(ctx context.Context) {
+	m.mu.Lock()
+	e := m.active
+	if e == nil || e.Status != StatusRunning || time.Now().Before(*e.EndsAt) {
+		m.mu.Unlock()
+		return
+	}
+	m.end(e, StatusCompleted)
+	m.mu.Unlock()
+
+	done := m.tally(ctx, e)
+	log.Printf("Experiment %s (%s) completed: %s", done.ID, done.Name, done.Results.Winner)
+}
+
+// end stops assigning traffic to e; callers must hold m.mu.
+func (m *Manager) end(e *Experiment, status Status) {
+	now := time.Now().UTC()
+	e.Status, e.EndedAt = status, &now
+	m.active, m.engine = nil, nil
+	m.save()
+}
+
+// tally computes and records the results of an ended experiment.
+func (m *Manager) tally(ctx context.Context, e *Experiment) Experiment {
+	var results *Results
+	if e.StartedAt != nil {
+		r, err := m.compute(ctx, e)
+		if err != nil {
+			log.Printf("Experiment %s: tally failed: %v", e.ID, err)
+		}
+		results = r
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e.Results = results
+	m.save()
+	return *e
+}
+
+func (m *Manager) compute(ctx context.Context, e *Experiment) (*Results, error) {
+	records, err := m.cfg.Store.Find(ctx, store.Filter{Tenant: e.Tenant, CreatedFrom: *e.StartedAt})
+	if err != nil {
+		return nil, err
+	}
+	arms := map[string][]models.StoredPrediction{}
+	for _, rec := range records {
+		// Resubmissions would count the same study twice.
+		if rec.Experiment != e.ID || rec.Duplicate || rec.DuplicateOf != "" {
+			continue
+		}
+		if e.Mode == ModeShadow {
+			// Both models are judged on the studies both scored.
+			if rec.Shadow == nil {
+				continue
+			}
+			shadowed := rec
+			shadowed.Prediction, shadowed.ConfidenceScore = rec.Shadow.Prediction, rec.Shadow.ConfidenceScore
+			arms[ArmControl] = append(arms[ArmControl], rec)
+			arms[ArmCandidate] = append(arms[ArmCandidate], shadowed)
+			continue
+		}
+		arms[rec.ExperimentArm] = append(arms[rec.ExperimentArm], rec)
+	}
+
+	r := &Results{
+		TalliedAt: time.Now().UTC(),
+		Metric:    e.SuccessMetric,
+		Control:   fairness.Tally(ArmControl, arms[ArmControl]),
+		Candidate: fairness.Tally(ArmCandidate, arms[ArmCandidate]),
+		Winner:    "inconclusive",
+	}
+	control, nControl := metric(r.Control, e.SuccessMetric)
+	candidate, nCandidate := metric(r.Candidate, e.SuccessMetric)
+	if nControl > 0 && nCandidate > 0 {
+		diff := candidate - control
+		r.Difference = &diff
+	}
+	switch {
+	case nControl < e.MinSamples || nCandidate < e.MinSamples:
+		r.Reason = fmt.Sprintf("each arm needs %d reviewed studies for %s; control has %d, candidate has %d",
+			e.MinSamples, e.SuccessMetric, nControl, nCandidate)
+	case candidate > control:
+		r.Winner = ArmCandidate
+		r.Reason = fmt.Sprintf("candidate %s %.3f vs control %.3f", e.SuccessMetric, candidate, control)
+	case control > candidate:
+		r.Winner = ArmControl
+		r.Reason = fmt.Sprintf("control %s %.3f vs candidate %.3f", e.SuccessMetric, control, candidate)
+	default:
+		r.Reason = fmt.Sprintf("both arms have %s %.3f", e.SuccessMetric, control)
+	}
+	return r, nil
+}
+
+// metric returns the value of the named metric for g and the number of
+// reviewed studies it is based on.
+func metric(g fairness.GroupStats, name string) (float64, int) {
+	var hits, n int
+	switch name {
+	case "sensitivity":
+		hits, n = g.TruePositives, g.TruePositives+g.FalseNegatives
+	case "specificity":
+		hits, n = g.TrueNegatives, g.TrueNegatives+g.FalsePositives
+	case "accuracy":
+		hits, n = g.TruePositives+g.TrueNegatives, g.WithFeedback
+	}
+	if n == 0 {
+		return 0, 0
+	}
+	return float64(hits) / float64(n), n
+}
+
+// save persists all experiments; callers must hold m.mu.
+func (m *Manager) save() {
+	if m.cfg.Path == "" {
+		return
+	}
+	list := make([]*Experiment, 0, len(m.experiments))
+	for _, e := range m.experiments {
+		list = append(list, e)
+	}
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		log.Printf("experiments: encode: %v", err)
+		return
+	}
+	tmp := m.cfg.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o640); err != nil {
+		log.Printf("experiments: save: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, m.cfg.Path); err != nil {
+		log.Printf("experiments: save: %v", err)
+	}
+}
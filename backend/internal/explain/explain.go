@@ -0,0 +1,244 @@
+// backend/internal/explain/explain.go
+/*
+ * This file builds the explanation returned with a prediction.
+ *
+ * Clients choose how much explanation they pay for with one parameter:
+ *
+ *   none          the score only (the default, and the fastest)
+ *   heatmap       a coarse map of how suspicious each part of the image is
+ *   topk-regions  only the most suspicious areas, as boxes with scores
+ *   full          both
+ *
+ * so simple integrations stay fast while clinician UIs get what they need
+ * from the same endpoint. The heatmap comes from the patch scores of
+ * tiled inference, the one mode in which the model itself localises,
+ * from Grad-CAM (see gradcam.go), where the model exposes gradients, or
+ * from occlusion (see occlusion.go), which works with any model.
+ * Coordinates are in pixels of the scored image.
+ *
+ * Regions are hotspots, not single cells: neighbouring cells that score
+ * at least half the map's peak are merged into one region, reported with
+ * its bounding box, peak and mean score and approximate size, so a lesion
+ * straddling a cell boundary is one finding rather than several. Clients
+ * pick how many they want with `top_k`.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package explain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/tiling"
+)
+
+// Level is the requested richness of an explanation.
+type Level string
+
+// Explanation levels.
+const (
+	None        Level = "none"
+	Heatmap     Level = "heatmap"
+	TopKRegions Level = "topk-regions"
+	Full        Level = "full"
+)
+
+// Levels lists every level, least detailed first.
+var Levels = []Level{None, Heatmap, TopKRegions, Full}
+
+// Methods by which a heatmap is obtained.
+const (
+	// MethodPatchScores stitches the heatmap from tiled patch scores.
+	MethodPatchScores = "patch_scores"
+	// MethodOcclusion measures how the score drops as parts of the image
+	// are masked.
+	MethodOcclusion = "occlusion"
+	// MethodGradCAM weights the model's last feature map by the gradient
+	// of the score.
+	MethodGradCAM = "gradcam"
+)
+
+// Methods lists every method.
+var Methods = []string{MethodPatchScores, MethodGradCAM, MethodOcclusion}
+
+// DefaultTopK is the number of regions returned when the client does not
+// ask for a number; MaxTopK the most it may ask for.
+const (
+	DefaultTopK = 3
+	MaxTopK     = 20
+)
+
+// hotspotFraction is the share of the map's peak score a cell needs to
+// belong to a hotspot.
+const hotspotFraction = 0.5
+
+// ParseLevel normalises a client-supplied level. An empty value means
+// None.
+func ParseLevel(s string) (Level, error) {
+	l := Level(strings.ToLower(strings.TrimSpace(s)))
+	switch l {
+	case "":
+		return None, nil
+	case None, Heatmap, TopKRegions, Full:
+		return l, nil
+	}
+	return "", fmt.Errorf("explain must be one of %v, got %q", Levels, s)
+}
+
+// ParseMethod normalises a client-supplied method. An empty value means
+// the deployment's default.
+func ParseMethod(s string) (string, error) {
+	m := strings.ToLower(strings.TrimSpace(s))
+	switch m {
+	case "", MethodPatchScores, MethodGradCAM, MethodOcclusion:
+		return m, nil
+	}
+	return "", fmt.Errorf("explain_method must be one of %v, got %q", Methods, s)
+}
+
+// ParseTopK normalises a client-supplied region count. An empty value
+// means DefaultTopK.
+func ParseTopK(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return DefaultTopK, nil
+	}
+	k, err := strconv.Atoi(s)
+	if err != nil || k < 1 || k > MaxTopK {
+		return 0, fmt.Errorf("top_k must be an integer from 1 to %d, got %q", MaxTopK, s)
+	}
+	return k, nil
+}
+
+// FromTiles explains a tiled score at level l, with at most k regions;
+// nil for None.
+func FromTiles(l Level, t *models.TiledScore, k int) *models.Explanation {
+	if l == None || t == nil {
+		return nil
+	}
+	// The map is always derived from the patches, so it is available
+	// even when the tiling configuration leaves it out of the response.
+	m := t.ProbabilityMap
+	if m == nil {
+		m = tiling.Stitch(t, tiling.DefaultMapSize)
+	}
+	return FromMap(l, MethodPatchScores, m, t.Width, t.Height, k)
+}
+
+// FromMap explains a map obtained by method over an image of w×h
+// pixels at level l, with at most k regions; nil for None.
+func FromMap(l Level, method string, m *models.ProbabilityMap, w, h, k int) *models.Explanation {
+	if l == None || m == nil {
+		return nil
+	}
+	e := &models.Explanation{Level: string(l), Method: method}
+	if l == Heatmap || l == Full {
+		e.Heatmap = m
+	}
+	if l == TopKRegions || l == Full {
+		e.Regions = topRegions(m, w, h, k)
+	}
+	return e
+}
+
+// topRegions returns the k highest-scoring hotspots of m, which covers
+// an image of w×h pixels, highest peak first. A hotspot is a group of
+// 8-connected cells scoring at least hotspotFraction of the peak; a map
+// scoring zero everywhere has none.
+func topRegions(m *models.ProbabilityMap, w, h, k int) []models.Region {
+	peak := 0.0
+	for _, row := range m.Values {
+		for _, v := range row {
+			peak = max(peak, v)
+		}
+	}
+	if peak <= 0 {
+		return nil
+	}
+	hot := func(x, y int) bool {
+		return y >= 0 && y < len(m.Values) && x >= 0 && x < len(m.Values[y]) && m.Values[y][x] >= peak*hotspotFraction
+	}
+
+	seen := make(map[[2]int]bool)
+	var regions []models.Region
+	for y, row := range m.Values {
+		for x := range row {
+			if !hot(x, y) || seen[[2]int{x, y}] {
+				continue
+			}
+			regions = append(regions, hotspot(m, w, h, x, y, hot, seen))
+		}
+	}
+	sort.SliceStable(regions, func(i, j int) bool { return regions[i].Score > regions[j].Score })
+	return regions[:min(k, len(regions))]
+}
+
+// hotspot flood-fills the hotspot containing cell (x, y), marking its
+// cells seen, and describes it in image pixels.
+func hotspot(m *models.ProbabilityMap, w, h, x, y int, hot func(x, y int) bool, seen map[[2]int]bool) models.Region {
+	minX, minY, maxX, maxY := x, y, x, y
+	var peak, sum float64
+	var cells, area int
+	stack := [][2]int{{x, y}}
+	seen[[2]int{x, y}] = true
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		cx, cy := c[0], c[1]
+		v := m.Values[cy][cx]
+		peak, sum, cells = max(peak, v), sum+v, cells+1
+		minX, minY, maxX, maxY = min(minX, cx), min(minY, cy), max(maxX, cx), max(maxY, cy)
+		area += (cellEdge(cx+1, m.Width, w) - cellEdge(cx, m.Width, w)) * (cellEdge(cy+1, m.Height, h) - cellEdge(cy, m.Height, h))
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				n := [2]int{cx + dx, cy + dy}
+				if hot(n[0], n[1]) && !seen[n] {
+					seen[n] = true
+					stack = append(stack, n)
+				}
+			}
+		}
+	}
+
+	x0, x1 := cellEdge(minX, m.Width, w), cellEdge(maxX+1, m.Width, w)
+	y0, y1 := cellEdge(minY, m.Height, h), cellEdge(maxY+1, m.Height, h)
+	r := models.Region{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0, Score: peak, MeanScore: sum / float64(cells), Area: area}
+	if w > 0 && h > 0 {
+		r.AreaFraction = float64(area) / float64(w*h)
+	}
+	return r
+}
+
+// cellEdge returns the pixel position of the i-th of n cell edges along
+// an axis of length pixels.
+func cellEdge(i, n, length int) int {
+	return int(math.Round(float64(i) * float64(length) / float64(n)))
+}
+
+// Diff returns the cell-by-cell difference b - a of two maps of the same
+// image, so cells where b is more suspicious are positive; nil when the
+// maps do not have the same cells.
+func Diff(a, b *models.ProbabilityMap) *models.ProbabilityMap {
+	if a == nil || b == nil || a.Width != b.Width || a.Height != b.Height || len(a.Values) != len(b.Values) {
+		return nil
+	}
+	d := &models.ProbabilityMap{Width: a.Width, Height: a.Height, CellPixel: a.CellPixel, Values: make([][]float64, a.Height)}
+	for y := range d.Values {
+		if len(a.Values[y]) != len(b.Values[y]) {
+			return nil
+		}
+		d.Values[y] = make([]float64, len(a.Values[y]))
+		for x := range d.Values[y] {
+			d.Values[y][x] = b.Values[y][x] - a.Values[y][x]
+		}
+	}
+	return d
+}
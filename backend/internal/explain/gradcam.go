@@ -0,0 +1,63 @@
+// backend/internal/explain/gradcam.go
+/*
+ * This file turns Grad-CAM activations into a heatmap.
+ *
+ * A Grad-CAM map has the resolution of the model's last feature map
+ * (7×7 or so) and covers the model input, which is the whole study
+ * stretched to a square. It is resampled bilinearly onto a map of square
+ * cells over the study, DefaultMapSize cells along its long side like a
+ * stitched patch map, so every method yields maps of the same shape and
+ * regions are found the same way.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package explain
+
+import (
+	"math"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/tiling"
+)
+
+// FromActivations resamples a Grad-CAM map, indexed [row][column], onto
+// a probability map over an image of w×h pixels; nil for an empty map.
+func FromActivations(cam [][]float64, w, h int) *models.ProbabilityMap {
+	if len(cam) == 0 || len(cam[0]) == 0 {
+		return nil
+	}
+	rows, cols := len(cam), len(cam[0])
+	cell := float64(max(w, h, 1)) / float64(tiling.DefaultMapSize)
+	m := &models.ProbabilityMap{
+		Width:     max(int(math.Round(float64(w)/cell)), 1),
+		Height:    max(int(math.Round(float64(h)/cell)), 1),
+		CellPixel: cell,
+	}
+	m.Values = make([][]float64, m.Height)
+	for y := range m.Values {
+		m.Values[y] = make([]float64, m.Width)
+		// Cell centres, in map coordinates, where activation i sits at i.
+		fy := (float64(y)+0.5)*float64(rows)/float64(m.Height) - 0.5
+		for x := range m.Values[y] {
+			fx := (float64(x)+0.5)*float64(cols)/float64(m.Width) - 0.5
+			m.Values[y][x] = bilinear(cam, fx, fy)
+		}
+	}
+	return m
+}
+
+// bilinear samples grid at (x, y), clamping to its edges.
+func bilinear(grid [][]float64, x, y float64) float64 {
+	rows, cols := len(grid), len(grid[0])
+	x = min(max(x, 0), float64(cols-1))
+	y = min(max(y, 0), float64(rows-1))
+	x0, y0 := int(x), int(y)
+	x1, y1 := min(x0+1, cols-1), min(y0+1, rows-1)
+	tx, ty := x-float64(x0), y-float64(y0)
+	top := grid[y0][x0]*(1-tx) + grid[y0][x1]*tx
+	bottom := grid[y1][x0]*(1-tx) + grid[y1][x1]*tx
+	return top*(1-ty) + bottom*ty
+}
@@ -0,0 +1,197 @@
+// backend/internal/fairness/fairness.go
+/*
+ * This file computes subgroup performance for bias monitoring.
+ *
+ * For every subgroup dimension (age band, site, scanner vendor, ...) we
+ * report each group's volume and positivity rate and — for studies with
+ * confirmed ground truth — sensitivity and specificity. A dimension is
+ * flagged when the gap between its best and worst sufficiently large group
+ * exceeds the configured tolerance.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package fairness
+
+import (
+	"sort"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// Dimensions are the subgroup keys accepted on prediction requests.
+var Dimensions = []string{"age_band", "site", "scanner_vendor"}
+
+// Config controls disparity alerting.
+type Config struct {
+	// MaxGap is the largest tolerated difference in a rate between groups.
+	MaxGap float64
+	// MinGroupSize excludes groups too small to compare meaningfully.
+	MinGroupSize int
+}
+
+// GroupStats describes one subgroup.
+type GroupStats struct {
+	Group          string   `json:"group"`
+	Predictions    int      `json:"predictions"`
+	Positives      int      `json:"positives"`
+	PositivityRate float64  `json:"positivity_rate"`
+	WithFeedback   int      `json:"with_feedback"`
+	TruePositives  int      `json:"true_positives"`
+	FalsePositives int      `json:"false_positives"`
+	TrueNegatives  int      `json:"true_negatives"`
+	FalseNegatives int      `json:"false_negatives"`
+	Sensitivity    *float64 `json:"sensitivity,omitempty"`
+	Specificity    *float64 `json:"specificity,omitempty"`
+}
+
+// Disparity is a flagged gap within one dimension.
+type Disparity struct {
+	Dimension string  `json:"dimension"`
+	Metric    string  `json:"metric"`
+	Gap       float64 `json:"gap"`
+	Lowest    string  `json:"lowest_group"`
+	Highest   string  `json:"highest_group"`
+}
+
+// Report is the full subgroup analysis.
+type Report struct {
+	GeneratedAt time.Time               `json:"generated_at"`
+	Studies     int                     `json:"studies"`
+	Dimensions  map[string][]GroupStats `json:"dimensions"`
+	Disparities []Disparity             `json:"disparities"`
+}
+
+// Analyse builds a Report from stored predictions.
+func Analyse(records []models.StoredPrediction, cfg Config) Report {
+	report := Report{
+		GeneratedAt: time.Now().UTC(),
+		Studies:     len(records),
+		Dimensions:  make(map[string][]GroupStats),
+		Disparities: []Disparity{},
+	}
+
+	for _, dim := range Dimensions {
+		groups := map[string]*GroupStats{}
+		for _, rec := range records {
+			value := rec.Subgroups[dim]
+			if value == "" {
+				continue
+			}
+			g, ok := groups[value]
+			if !ok {
+				g = &GroupStats{Group: value}
+				groups[value] = g
+			}
+			accumulate(g, rec)
+		}
+		if len(groups) == 0 {
+			continue
+		}
+
+		var list []GroupStats
+		for _, g := range groups {
+			finalise(g)
+			list = append(list, *g)
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Group < list[j].Group })
+		report.Dimensions[dim] = list
+		report.Disparities = append(report.Disparities, disparities(dim, list, cfg)...)
+	}
+	return report
+}
+
+// Tally computes the statistics of a single group of records.
+func Tally(group string, records []models.StoredPrediction) GroupStats {
+	g := GroupStats{Group: group}
+	for _, rec := range records {
+		accumulate(&g, rec)
+	}
+	finalise(&g)
+	return g
+}
+
+func accumulate(g *GroupStats, rec models.StoredPrediction) {
+	g.Predictions++
+	predictedPositive := rec.Prediction == models.LabelCancer
+	if predictedPositive {
+		g.Positives++
+	}
+	if rec.Feedback == nil {
+		return
+	}
+
+	g.WithFeedback++
+	actualPositive := rec.Feedback.GroundTruth == models.LabelCancer
+	switch {
+	case predictedPositive && actualPositive:
+		g.TruePositives++
+	case predictedPositive && !actualPositive:
+		g.FalsePositives++
+	case !predictedPositive && actualPositive:
+		g.FalseNegatives++
+	default:
+		g.TrueNegatives++
+	}
+}
+
+func finalise(g *GroupStats) {
+	if g.Predictions > 0 {
+		g.PositivityRate = float64(g.Positives) / float64(g.Predictions)
+	}
+	if n := g.TruePositives + g.FalseNegatives; n > 0 {
+		v := float64(g.TruePositives) / float64(n)
+		g.Sensitivity = &v
+	}
+	if n := g.TrueNegatives + g.FalsePositives; n > 0 {
+		v := float64(g.TrueNegatives) / float64(n)
+		g.Specificity = &v
+	}
+}
+
+// disparities compares groups that meet the minimum size on each metric.
+func disparities(dim string, groups []GroupStats, cfg Config) []Disparity {
+	metrics := map[string]func(GroupStats) (float64, bool){
+		"positivity_rate": func(g GroupStats) (float64, bool) {
+			return g.PositivityRate, g.Predictions >= cfg.MinGroupSize
+		},
+		"sensitivity": func(g GroupStats) (float64, bool) {
+			if g.Sensitivity == nil {
+				return 0, false
+			}
+			return *g.Sensitivity, g.TruePositives+g.FalseNegatives >= cfg.MinGroupSize
+		},
+		"specificity": func(g GroupStats) (float64, bool) {
+			if g.Specificity == nil {
+				return 0, false
+			}
+			return *g.Specificity, g.TrueNegatives+g.FalsePositives >= cfg.MinGroupSize
+		},
+	}
+
+	var out []Disparity
+	for _, name := range []string{"positivity_rate", "sensitivity", "specificity"} {
+		var lo, hi *GroupStats
+		var loV, hiV float64
+		for i := range groups {
+			v, ok := metrics[name](groups[i])
+			if !ok {
+				continue
+			}
+			if lo == nil || v < loV {
+				lo, loV = &groups[i], v
+			}
+			if hi == nil || v > hiV {
+				hi, hiV = &groups[i], v
+			}
+		}
+		if lo == nil || lo == hi || hiV-loV <= cfg.MaxGap {
+			continue
+		}
+		out = append(out, Disparity{Dimension: dim, Metric: name, Gap: hiV - loV, Lowest: lo.Group, Highest: hi.Group})
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+// backend/internal/fairness/monitor.go
+/*
+ * This file contains the periodic fairness monitor, which re-runs the
+ * subgroup analysis over a trailing window and raises an alert (log line
+ * and optional webhook) whenever a disparity is flagged.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package fairness
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/store"
+)
+
+// Monitor periodically checks for subgroup disparities.
+type Monitor struct {
+	Store    store.Store
+	Config   Config
+	Interval time.Duration
+	// Window is the trailing period analysed on each check.
+	Window time.Duration
+	// WebhookURL, if set, receives a JSON alert for each flagged check.
+	WebhookURL string
+}
+
+// Run checks until ctx is cancelled.
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.check(ctx)
+		}
+	}
+}
+
+func (m *Monitor) check(ctx context.Context) {
+	records, err := m.Store.Find(ctx, store.Filter{CreatedFrom: time.Now().Add(-m.Window)})
+	if err != nil {
+		log.Printf("fairness monitor: %v", err)
+		return
+	}
+	report := Analyse(records, m.Config)
+	for _, d := range report.Disparities {
+		log.Printf("ALERT fairness disparity: %s %s gap %.3f (%s vs %s)", d.Dimension, d.Metric, d.Gap, d.Lowest, d.Highest)
+	}
+	if len(report.Disparities) == 0 || m.WebhookURL == "" {
+		return
+	}
+
+	body, _ := json.Marshal(map[string]any{"type": "fairness.disparity", "report": report})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		log.Printf("fairness monitor: alert webhook: %v", err)
+		return
+	}
+	resp.Body.Close()
+}
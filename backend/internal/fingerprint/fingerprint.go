@@ -0,0 +1,78 @@
+// backend/internal/fingerprint/fingerprint.go
+/*
+ * This file implements anonymized image fingerprinting for duplicate-study
+ * detection.
+ *
+ * We compute a 64-bit difference hash (dHash) of each image: the image is
+ * reduced to a 9x8 grayscale thumbnail and each bit records whether a pixel
+ * is brighter than its right-hand neighbour. The hash survives re-encoding,
+ * resizing and mild contrast changes, yet it is impossible to reconstruct
+ * the image from it, so it can be retained and shared across tenants
+ * without exposing patient data.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package fingerprint
+
+import (
+	"fmt"
+	"image"
+	"math/bits"
+	"strconv"
+
+	"github.com/nfnt/resize"
+)
+
+// Hash is a 64-bit perceptual hash of an image.
+type Hash uint64
+
+// Compute returns the difference hash of img.
+func Compute(img image.Image) Hash {
+	// --- Step 1: Shrink to a 9x8 thumbnail ---
+	// One extra column gives us 8 horizontal comparisons per row.
+	small := resize.Resize(9, 8, img, resize.Bilinear)
+	b := small.Bounds()
+
+	// --- Step 2: Compare neighbouring pixels ---
+	var h Hash
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			left := luminance(small, b.Min.X+x, b.Min.Y+y)
+			right := luminance(small, b.Min.X+x+1, b.Min.Y+y)
+			h <<= 1
+			if left > right {
+				h |= 1
+			}
+		}
+	}
+	return h
+}
+
+// Distance returns the Hamming distance between two hashes. Identical
+// images have distance 0; unrelated images average around 32.
+func Distance(a, b Hash) int {
+	return bits.OnesCount64(uint64(a ^ b))
+}
+
+// String renders the hash as 16 hex digits.
+func (h Hash) String() string {
+	return fmt.Sprintf("%016x", uint64(h))
+}
+
+// Parse reads a hash produced by String.
+func Parse(s string) (Hash, error) {
+	v, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid fingerprint %q: %w", s, err)
+	}
+	return Hash(v), nil
+}
+
+// luminance returns the ITU-R BT.601 luma of a pixel on a 16-bit scale.
+func luminance(img image.Image, x, y int) uint32 {
+	r, g, b, _ := img.At(x, y).RGBA()
+	return (299*r + 587*g + 114*b) / 1000
+}
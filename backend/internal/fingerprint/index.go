@@ -0,0 +1,239 @@
+// backend/internal/fingerprint/index.go
+/*
+ * This file contains the duplicate-study index.
+ *
+ * The index remembers the fingerprint of every study we have scored and
+ * answers "have we seen this image before?". Entries can optionally be
+ * persisted to an append-only JSON Lines file so duplicates are still
+ * detected after a restart. With a match window, entries that fall out of
+ * it are dropped, and the file is rewritten once most of its lines are
+ * expired so it does not grow without bound.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package fingerprint
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Entry is a single fingerprinted study.
+type Entry struct {
+	Hash         Hash      `json:"-"`
+	HashHex      string    `json:"hash"`
+	PredictionID string    `json:"prediction_id"`
+	Tenant       string    `json:"tenant,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// IndexConfig controls how matches are found.
+type IndexConfig struct {
+	// MaxDistance is the largest Hamming distance still treated as the
+	// same study.
+	MaxDistance int
+	// CrossTenant allows matches against studies submitted by other
+	// tenants (the same patient is often screened at several sites).
+	CrossTenant bool
+	// Window limits matches to studies newer than this; zero disables it.
+	Window time.Duration
+	// Path, if set, persists entries to a JSON Lines file.
+	Path string
+}
+
+// Index is a concurrency-safe store of study fingerprints.
+type Index struct {
+	cfg     IndexConfig
+	mu      sync.RWMutex
+	entries []Entry
+	file    *os.File
+	// lines counts the entries in the file, expired ones included.
+	lines int
+}
+
+// NewIndex creates an index, loading any previously persisted entries.
+func NewIndex(cfg IndexConfig) (*Index, error) {
+	idx := &Index{cfg: cfg}
+	if cfg.Path == "" {
+		return idx, nil
+	}
+
+	if err := idx.load(); err != nil {
+		return nil, err
+	}
+	idx.prune()
+	if idx.lines > len(idx.entries) {
+		if err := idx.compact(); err != nil {
+			return nil, err
+		}
+		return idx, nil
+	}
+	if err := idx.open(); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// Match returns the earliest prior study whose fingerprint is within the
+// configured distance of h, if any. With CrossTenant the entry may belong
+// to another tenant; callers must not disclose its PredictionID to tenant.
+func (idx *Index) Match(h Hash, tenant string) (Entry, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var cutoff time.Time
+	if idx.cfg.Window > 0 {
+		cutoff = time.Now().Add(-idx.cfg.Window)
+	}
+
+	// Entries are appended in time order, so the first hit is the original.
+	for _, e := range idx.entries {
+		if !cutoff.IsZero() && e.CreatedAt.Before(cutoff) {
+			continue
+		}
+		if !idx.cfg.CrossTenant && e.Tenant != tenant {
+			continue
+		}
+		if Distance(e.Hash, h) <= idx.cfg.MaxDistance {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Add records a newly scored study.
+func (idx *Index) Add(e Entry) error {
+	e.HashHex = e.Hash.String()
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now().UTC()
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.entries = append(idx.entries, e)
+	idx.prune()
+	if idx.file == nil {
+		return nil
+	}
+	// Rewrite the file once expired entries make up most of it.
+	if idx.lines > 0 && idx.lines >= 2*len(idx.entries) {
+		return idx.compact()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode fingerprint: %w", err)
+	}
+	if _, err := idx.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("persist fingerprint: %w", err)
+	}
+	idx.lines++
+	return nil
+}
+
+// Len returns the number of indexed studies.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.entries)
+}
+
+// prune drops entries older than the window. Entries are appended in
+// time order, so the expired ones are a prefix.
+func (idx *Index) prune() {
+	if idx.cfg.Window <= 0 {
+		return
+	}
+	cutoff := time.Now().Add(-idx.cfg.Window)
+	n := 0
+	for n < len(idx.entries) && idx.entries[n].CreatedAt.Before(cutoff) {
+		n++
+	}
+	if n > 0 {
+		idx.entries = append([]Entry(nil), idx.entries[n:]...)
+	}
+}
+
+// compact replaces the file with the live entries and reopens it for
+// appending. The new file is renamed into place, so a crash leaves either
+// the old file or the new one.
+func (idx *Index) compact() error {
+	tmp := idx.cfg.Path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("compact fingerprint index: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	for _, e := range idx.entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			f.Close()
+			return fmt.Errorf("encode fingerprint: %w", err)
+		}
+		w.Write(append(line, '\n'))
+	}
+	if err := w.Flush(); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, idx.cfg.Path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("compact fingerprint index: %w", err)
+	}
+
+	if idx.file != nil {
+		idx.file.Close()
+		idx.file = nil
+	}
+	idx.lines = len(idx.entries)
+	return idx.open()
+}
+
+func (idx *Index) open() error {
+	f, err := os.OpenFile(idx.cfg.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("open fingerprint index: %w", err)
+	}
+	idx.file = f
+	return nil
+}
+
+func (idx *Index) load() error {
+	f, err := os.Open(idx.cfg.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open fingerprint index: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		idx.lines++
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			// Skip a torn final line left by a crash mid-write.
+			continue
+		}
+		h, err := Parse(e.HashHex)
+		if err != nil {
+			continue
+		}
+		e.Hash = h
+		idx.entries = append(idx.entries, e)
+	}
+	return scanner.Err()
+}
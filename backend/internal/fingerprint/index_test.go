@@ -0,0 +1,100 @@
+package fingerprint
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open index file: %v", err)
+	}
+	defer f.Close()
+	n := 0
+	for s := bufio.NewScanner(f); s.Scan(); {
+		n++
+	}
+	return n
+}
+
+func TestIndexPrunesExpiredEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fingerprints.jsonl")
+	cfg := IndexConfig{MaxDistance: 0, Window: time.Hour, Path: path}
+	idx, err := NewIndex(cfg)
+	if err != nil {
+		t.Fatalf("NewIndex: %v", err)
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	for i := range 4 {
+		if err := idx.Add(Entry{Hash: Hash(i), PredictionID: "old", CreatedAt: old}); err != nil {
+			t.Fatalf("Add expired: %v", err)
+		}
+	}
+	if err := idx.Add(Entry{Hash: Hash(100), PredictionID: "fresh"}); err != nil {
+		t.Fatalf("Add fresh: %v", err)
+	}
+
+	if got := idx.Len(); got != 1 {
+		t.Errorf("Len = %d, want 1 after expired entries are pruned", got)
+	}
+	if _, ok := idx.Match(Hash(0), ""); ok {
+		t.Error("expired entry still matches")
+	}
+	if e, ok := idx.Match(Hash(100), ""); !ok || e.PredictionID != "fresh" {
+		t.Errorf("Match(fresh) = %+v, %v", e, ok)
+	}
+	if got := countLines(t, path); got != 1 {
+		t.Errorf("index file has %d lines, want 1 after compaction", got)
+	}
+
+	// Appends after compaction go to the new file, and a reload keeps
+	// only live entries.
+	if err := idx.Add(Entry{Hash: Hash(200), PredictionID: "later"}); err != nil {
+		t.Fatalf("Add after compaction: %v", err)
+	}
+	reloaded, err := NewIndex(cfg)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if got := reloaded.Len(); got != 2 {
+		t.Errorf("reloaded Len = %d, want 2", got)
+	}
+}
+
+func TestIndexLoadCompactsExpiredFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fingerprints.jsonl")
+	idx, err := NewIndex(IndexConfig{Path: path})
+	if err != nil {
+		t.Fatalf("NewIndex: %v", err)
+	}
+	if err := idx.Add(Entry{Hash: Hash(1), PredictionID: "old", CreatedAt: time.Now().Add(-48 * time.Hour)}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := idx.Add(Entry{Hash: Hash(2), PredictionID: "fresh"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	// A torn final line is dropped by the rewrite too.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	f.WriteString(`{"hash":`)
+	f.Close()
+
+	windowed, err := NewIndex(IndexConfig{Window: 24 * time.Hour, Path: path})
+	if err != nil {
+		t.Fatalf("NewIndex with window: %v", err)
+	}
+	if got := windowed.Len(); got != 1 {
+		t.Errorf("Len = %d, want 1", got)
+	}
+	if got := countLines(t, path); got != 1 {
+		t.Errorf("index file has %d lines, want 1", got)
+	}
+}
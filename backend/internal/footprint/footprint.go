@@ -0,0 +1,69 @@
+// backend/internal/footprint/footprint.go
+/*
+ * This file estimates the energy and carbon cost of inference.
+ *
+ * Sustainability reporting asks how much energy our AI workload uses and
+ * what it emits. We cannot meter the hardware, so the cost of a prediction
+ * is estimated from the compute time spent on it:
+ *
+ *   energy (Wh)    = compute time (h) × power draw (W) × PUE
+ *   carbon (gCO2e) = energy (kWh) × grid carbon intensity (gCO2e/kWh)
+ *
+ * The power draw is what the operator attributes to one busy inference
+ * worker (e.g. a share of the node's TDP); PUE scales it up for the data
+ * centre's cooling and power overhead. These are estimates for trend and
+ * budget reporting, not measurements.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package footprint
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// Estimator converts compute time into energy and carbon.
+type Estimator struct {
+	// Watts is the power drawn while computing.
+	Watts float64
+	// PUE is the data centre's power usage effectiveness (default 1).
+	PUE float64
+	// CarbonIntensity is the grid's emissions in gCO2e per kWh; zero
+	// leaves carbon unreported.
+	CarbonIntensity float64
+}
+
+// Validate checks the estimator.
+func (e Estimator) Validate() error {
+	switch {
+	case e.Watts <= 0:
+		return fmt.Errorf("power draw must be positive, got %v W", e.Watts)
+	case e.PUE != 0 && e.PUE < 1:
+		return fmt.Errorf("PUE must be at least 1, got %v", e.PUE)
+	case e.CarbonIntensity < 0:
+		return fmt.Errorf("carbon intensity must not be negative, got %v", e.CarbonIntensity)
+	}
+	return nil
+}
+
+// Estimate returns the cost of d of compute. A nil estimator only reports
+// the compute time.
+func (e *Estimator) Estimate(d time.Duration) models.ComputeCost {
+	cost := models.ComputeCost{ComputeMillis: float64(d.Microseconds()) / 1000}
+	if e == nil {
+		return cost
+	}
+	pue := e.PUE
+	if pue == 0 {
+		pue = 1
+	}
+	cost.EnergyWh = d.Hours() * e.Watts * pue
+	cost.CarbonGrams = cost.EnergyWh / 1000 * e.CarbonIntensity
+	return cost
+}
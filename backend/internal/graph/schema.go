@@ -0,0 +1,287 @@
+// backend/internal/graph/schema.go
+/*
+ * This file defines the GraphQL facade over the REST API.
+ *
+ * The graph exposes predictions (with their reviewer feedback), the served
+ * model and, on the admin schema, background jobs. Clients select exactly
+ * the fields they need instead of receiving whole REST documents. Field
+ * names are camelCase per GraphQL convention; values and semantics match
+ * the REST endpoints, including tenant scoping.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/graphql-go/graphql"
+	"github.com/josephed37/mammoscan-AI/backend/internal/jobs"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/store"
+)
+
+// MaxResults caps list fields, matching the REST lookup limit.
+const MaxResults = 100
+
+// Deps are the services the resolvers read from.
+type Deps struct {
+	// Find, Get and SubmitFeedback are tenant-scoped accessors shared
+	// with the REST handlers.
+	Find func(ctx context.Context, filter store.Filter) ([]models.StoredPrediction, error)
+	Get  func(ctx context.Context, tenant, id string) (models.StoredPrediction, error)
+	// SubmitFeedback's expected is the feedback version being replaced,
+	// required once the prediction has feedback.
+	SubmitFeedback func(ctx context.Context, tenant, id string, fb models.Feedback, expected *int) (models.StoredPrediction, error)
+	Model          func() models.ModelInfo
+	// Jobs, when set, adds the admin-only job fields to the schema.
+	Jobs *jobs.Manager
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a context whose queries are scoped to tenant.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+func tenantFrom(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// NewSchema builds the schema.
+func NewSchema(d Deps) (graphql.Schema, error) {
+	query := graphql.Fields{
+		"prediction": &graphql.Field{
+			Type: predictionType,
+			Args: graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				rec, err := d.Get(p.Context, tenantFrom(p.Context), p.Args["id"].(string))
+				if errors.Is(err, store.ErrNotFound) {
+					return nil, nil
+				}
+				return rec, err
+			},
+		},
+		"predictions": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(predictionType))),
+			Description: "Look up predictions by accession number and/or client reference.",
+			Args: graphql.FieldConfigArgument{
+				"accessionNumber": {Type: graphql.String},
+				"clientReference": {Type: graphql.String},
+				"limit":           {Type: graphql.Int, DefaultValue: MaxResults},
+			},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				filter := store.Filter{Tenant: tenantFrom(p.Context), Limit: min(p.Args["limit"].(int), MaxResults)}
+				filter.AccessionNumber, _ = p.Args["accessionNumber"].(string)
+				filter.ClientReference, _ = p.Args["clientReference"].(string)
+				if filter.AccessionNumber == "" && filter.ClientReference == "" {
+					return nil, errors.New("accessionNumber or clientReference is required")
+				}
+				records, err := d.Find(p.Context, filter)
+				if records == nil {
+					records = []models.StoredPrediction{}
+				}
+				return records, err
+			},
+		},
+		"model": &graphql.Field{
+			Type: graphql.NewNonNull(modelType),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return d.Model(), nil
+			},
+		},
+	}
+	if d.Jobs != nil {
+		query["jobs"] = &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(jobType))),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				list := d.Jobs.List()
+				sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+				return list, nil
+			},
+		}
+		query["job"] = &graphql.Field{
+			Type: jobType,
+			Args: graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				job, err := d.Jobs.Get(p.Args["id"].(string))
+				if errors.Is(err, jobs.ErrNotFound) {
+					return nil, nil
+				}
+				return job, err
+			},
+		}
+	}
+
+	mutation := graphql.Fields{
+		"submitFeedback": &graphql.Field{
+			Type:        predictionType,
+			Description: "Record the reviewer-confirmed outcome of a prediction.",
+			Args: graphql.FieldConfigArgument{
+				"predictionId": {Type: graphql.NewNonNull(graphql.ID)},
+				"groundTruth":  {Type: graphql.NewNonNull(groundTruthEnum)},
+				"reviewer":     {Type: graphql.String},
+				"notes":        {Type: graphql.String},
+				"expectedVersion": {
+					Type:        graphql.Int,
+					Description: "The feedback version being replaced; required once feedback exists.",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				fb := models.Feedback{GroundTruth: p.Args["groundTruth"].(string)}
+				fb.Reviewer, _ = p.Args["reviewer"].(string)
+				fb.Notes, _ = p.Args["notes"].(string)
+				var expected *int
+				if v, ok := p.Args["expectedVersion"].(int); ok {
+					expected = &v
+				}
+				rec, err := d.SubmitFeedback(p.Context, tenantFrom(p.Context), p.Args["predictionId"].(string), fb, expected)
+				if errors.Is(err, store.ErrNotFound) {
+					return nil, errors.New("prediction not found")
+				}
+				return rec, err
+			},
+		},
+	}
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: query}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{Name: "Mutation", Fields: mutation}),
+	})
+}
+
+// field builds a resolver that reads a value from a source of type T.
+func field[T any](typ graphql.Output, get func(T) any) *graphql.Field {
+	return &graphql.Field{
+		Type: typ,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			src, ok := p.Source.(T)
+			if !ok {
+				return nil, nil
+			}
+			return get(src), nil
+		},
+	}
+}
+
+// optional maps zero values to null.
+func optional[V comparable](v V) any {
+	var zero V
+	if v == zero {
+		return nil
+	}
+	return v
+}
+
+var groundTruthEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "GroundTruth",
+	Values: graphql.EnumValueConfigMap{
+		"CANCER":     {Value: models.LabelCancer},
+		"NON_CANCER": {Value: models.LabelNonCancer},
+	},
+})
+
+var feedbackType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Feedback",
+	Fields: graphql.Fields{
+		"groundTruth": field(graphql.NewNonNull(groundTruthEnum), func(f *models.Feedback) any { return f.GroundTruth }),
+		"reviewer":    field(graphql.String, func(f *models.Feedback) any { return optional(f.Reviewer) }),
+		"notes":       field(graphql.String, func(f *models.Feedback) any { return optional(f.Notes) }),
+		"recordedAt":  field(graphql.NewNonNull(graphql.DateTime), func(f *models.Feedback) any { return f.RecordedAt }),
+		"version":     field(graphql.NewNonNull(graphql.Int), func(f *models.Feedback) any { return max(f.Version, 1) }),
+	},
+})
+
+var subgroupType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Subgroup",
+	Fields: graphql.Fields{
+		"dimension": field(graphql.NewNonNull(graphql.String), func(s [2]string) any { return s[0] }),
+		"value":     field(graphql.NewNonNull(graphql.String), func(s [2]string) any { return s[1] }),
+	},
+})
+
+var predictionType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Prediction",
+	Fields: graphql.Fields{
+		"id":              field(graphql.NewNonNull(graphql.ID), func(r models.StoredPrediction) any { return r.PredictionID }),
+		"prediction":      field(graphql.NewNonNull(graphql.String), func(r models.StoredPrediction) any { return r.Prediction }),
+		"confidenceScore": field(graphql.NewNonNull(graphql.Float), func(r models.StoredPrediction) any { return r.ConfidenceScore }),
+		"modelName":       field(graphql.NewNonNull(graphql.String), func(r models.StoredPrediction) any { return r.ModelName }),
+		"modelVersion":    field(graphql.String, func(r models.StoredPrediction) any { return optional(r.ModelVersion) }),
+		"modelThreshold":  field(graphql.NewNonNull(graphql.Float), func(r models.StoredPrediction) any { return r.ModelThreshold }),
+		"clientReference": field(graphql.String, func(r models.StoredPrediction) any { return optional(r.ClientReference) }),
+		"accessionNumber": field(graphql.String, func(r models.StoredPrediction) any { return optional(r.AccessionNumber) }),
+		"duplicateOf":     field(graphql.ID, func(r models.StoredPrediction) any { return optional(r.DuplicateOf) }),
+		"duplicate":       field(graphql.NewNonNull(graphql.Boolean), func(r models.StoredPrediction) any { return r.Duplicate || r.DuplicateOf != "" }),
+		"disclaimer":      field(graphql.String, func(r models.StoredPrediction) any { return optional(r.Disclaimer) }),
+		"createdAt":       field(graphql.NewNonNull(graphql.DateTime), func(r models.StoredPrediction) any { return r.CreatedAt }),
+		"tenant":          field(graphql.String, func(r models.StoredPrediction) any { return optional(r.Tenant) }),
+		"subgroups": field(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(subgroupType))), func(r models.StoredPrediction) any {
+			out := make([][2]string, 0, len(r.Subgroups))
+			for k, v := range r.Subgroups {
+				out = append(out, [2]string{k, v})
+			}
+			sort.Slice(out, func(i, j int) bool { return out[i][0] < out[j][0] })
+			return out
+		}),
+		"feedback": field(feedbackType, func(r models.StoredPrediction) any {
+			if r.Feedback == nil {
+				return nil
+			}
+			return r.Feedback
+		}),
+	},
+})
+
+var modelType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Model",
+	Fields: graphql.Fields{
+		"name":     field(graphql.NewNonNull(graphql.String), func(m models.ModelInfo) any { return m.Name }),
+		"source":   field(graphql.String, func(m models.ModelInfo) any { return optional(m.Source) }),
+		"loadedAt": field(graphql.DateTime, func(m models.ModelInfo) any { return optional(m.LoadedAt) }),
+	},
+})
+
+var jobType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Job",
+	Fields: graphql.Fields{
+		"id":        field(graphql.NewNonNull(graphql.ID), func(j jobs.Job) any { return j.ID }),
+		"type":      field(graphql.NewNonNull(graphql.String), func(j jobs.Job) any { return j.Type }),
+		"status":    field(graphql.NewNonNull(graphql.String), func(j jobs.Job) any { return string(j.Status) }),
+		"progress":  field(graphql.NewNonNull(graphql.Float), func(j jobs.Job) any { return j.Progress }),
+		"error":     field(graphql.String, func(j jobs.Job) any { return optional(j.Error) }),
+		"createdAt": field(graphql.NewNonNull(graphql.DateTime), func(j jobs.Job) any { return j.CreatedAt }),
+		"startedAt": field(graphql.DateTime, func(j jobs.Job) any {
+			if j.StartedAt == nil {
+				return nil
+			}
+			return *j.StartedAt
+		}),
+		"finishedAt": field(graphql.DateTime, func(j jobs.Job) any {
+			if j.FinishedAt == nil {
+				return nil
+			}
+			return *j.FinishedAt
+		}),
+		"resultJson": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The job result as a JSON document, once finished.",
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				job, ok := p.Source.(jobs.Job)
+				if !ok || job.Result == nil {
+					return nil, nil
+				}
+				body, err := json.Marshal(job.Result)
+				return string(body), err
+			},
+		},
+	},
+})
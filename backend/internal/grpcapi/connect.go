@@ -0,0 +1,83 @@
+// backend/internal/grpcapi/connect.go
+/*
+ * This file serves the prediction service as a plain HTTP handler (see
+ * connectrpc.com/connect), speaking three protocols on the same paths:
+ *
+ *   Connect   POST /mammoscan.v1.PredictionService/Predict with a JSON or
+ *             protobuf body; curl and fetch() can call it
+ *   gRPC-web  what browser gRPC clients send, without an Envoy in front
+ *   gRPC      native gRPC over HTTP/2, as on the gRPC port
+ *
+ * Studies are scored exactly as by the grpc-go server: HTTP headers are
+ * passed to the REST API as they come, less those of the protocol, and
+ * failures carry the same ErrorInfo. PredictStream is bidirectional, so it
+ * needs HTTP/2 end to end; over HTTP/1.1 only Predict can be called.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"connectrpc.com/connect"
+	"github.com/josephed37/mammoscan-AI/backend/internal/grpcapi/mammoscanv1"
+	"github.com/josephed37/mammoscan-AI/backend/internal/grpcapi/mammoscanv1/mammoscanv1connect"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ConnectHandler returns the service as an HTTP handler for the Connect,
+// gRPC and gRPC-web protocols, and the path prefix to mount it on.
+func (s *Server) ConnectHandler(opts ...connect.HandlerOption) (string, http.Handler) {
+	return mammoscanv1connect.NewPredictionServiceHandler(connectService{s}, opts...)
+}
+
+// connectService adapts a Server to the connect-go handler interface.
+type connectService struct {
+	s *Server
+}
+
+func (c connectService) Predict(ctx context.Context, req *connect.Request[mammoscanv1.PredictRequest]) (*connect.Response[mammoscanv1.PredictResponse], error) {
+	resp, st := c.s.score(ctx, callerHeader(req.Header()), req.Peer().Addr, req.Msg)
+	if st != nil {
+		return nil, connectError(st)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (c connectService) PredictStream(ctx context.Context, stream *connect.BidiStream[mammoscanv1.PredictStreamRequest, mammoscanv1.PredictStreamResponse]) error {
+	return c.s.serveStream(ctx, callerHeader(stream.RequestHeader()), stream.Peer().Addr, stream.Receive, stream.Send)
+}
+
+// callerHeader returns the headers of an RPC that the REST API takes.
+func callerHeader(h http.Header) http.Header {
+	header := make(http.Header, len(h))
+	for k, values := range h {
+		if !transportHeader(k) {
+			header[k] = values
+		}
+	}
+	return header
+}
+
+// connectError translates a failure status, keeping its details. gRPC and
+// Connect share their codes.
+func connectError(st *status.Status) *connect.Error {
+	err := connect.NewError(connect.Code(st.Code()), errors.New(st.Message()))
+	for _, d := range st.Details() {
+		msg, ok := d.(proto.Message)
+		if !ok {
+			continue
+		}
+		if detail, derr := connect.NewErrorDetail(msg); derr == nil {
+			err.AddDetail(detail)
+		}
+	}
+	return err
+}
@@ -0,0 +1,675 @@
+// backend/proto/mammoscan/v1/prediction.proto
+//
+// The gRPC prediction service. It scores studies through the same
+// pipeline as POST /api/v1/predict: the same validation, storage,
+// thresholds and response fields. Authenticate with the metadata the REST
+// API takes as headers (authorization or x-api-key, x-tenant-id,
+// accept-language).
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: mammoscan/v1/prediction.proto
+
+package mammoscanv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PredictRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The study: DICOM, PNG or JPEG.
+	Image []byte `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	// The study's file name, when it has one.
+	Filename string `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	// Opaque correlation fields, echoed in the response.
+	ClientReference string `protobuf:"bytes,3,opt,name=client_reference,json=clientReference,proto3" json:"client_reference,omitempty"`
+	AccessionNumber string `protobuf:"bytes,4,opt,name=accession_number,json=accessionNumber,proto3" json:"accession_number,omitempty"`
+	// Any other form field of POST /api/v1/predict, e.g. laterality.
+	Fields        map[string]string `protobuf:"bytes,5,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PredictRequest) Reset() {
+	*x = PredictRequest{}
+	mi := &file_mammoscan_v1_prediction_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PredictRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictRequest) ProtoMessage() {}
+
+func (x *PredictRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mammoscan_v1_prediction_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictRequest.ProtoReflect.Descriptor instead.
+func (*PredictRequest) Descriptor() ([]byte, []int) {
+	return file_mammoscan_v1_prediction_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PredictRequest) GetImage() []byte {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+func (x *PredictRequest) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *PredictRequest) GetClientReference() string {
+	if x != nil {
+		return x.ClientReference
+	}
+	return ""
+}
+
+func (x *PredictRequest) GetAccessionNumber() string {
+	if x != nil {
+		return x.AccessionNumber
+	}
+	return ""
+}
+
+func (x *PredictRequest) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type PredictResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	PredictionId string                 `protobuf:"bytes,1,opt,name=prediction_id,json=predictionId,proto3" json:"prediction_id,omitempty"`
+	// The classification label, e.g. "Cancer" or "Non-Cancer".
+	Prediction string `protobuf:"bytes,2,opt,name=prediction,proto3" json:"prediction,omitempty"`
+	// The model's probability score, 0 to 1.
+	ConfidenceScore float64 `protobuf:"fixed64,3,opt,name=confidence_score,json=confidenceScore,proto3" json:"confidence_score,omitempty"`
+	ModelName       string  `protobuf:"bytes,4,opt,name=model_name,json=modelName,proto3" json:"model_name,omitempty"`
+	ModelVersion    string  `protobuf:"bytes,5,opt,name=model_version,json=modelVersion,proto3" json:"model_version,omitempty"`
+	// The threshold the label was decided with.
+	ModelThreshold  float64 `protobuf:"fixed64,6,opt,name=model_threshold,json=modelThreshold,proto3" json:"model_threshold,omitempty"`
+	ClientReference string  `protobuf:"bytes,7,opt,name=client_reference,json=clientReference,proto3" json:"client_reference,omitempty"`
+	AccessionNumber string  `protobuf:"bytes,8,opt,name=accession_number,json=accessionNumber,proto3" json:"accession_number,omitempty"`
+	// If the study was already scored for the same tenant, the ID of the
+	// original prediction.
+	DuplicateOf string `protobuf:"bytes,9,opt,name=duplicate_of,json=duplicateOf,proto3" json:"duplicate_of,omitempty"`
+	// Whether the study was already scored, for this tenant or another.
+	Duplicate bool `protobuf:"varint,15,opt,name=duplicate,proto3" json:"duplicate,omitempty"`
+	// Regulatory wording required in the tenant's jurisdiction.
+	Disclaimer string `protobuf:"bytes,10,opt,name=disclaimer,proto3" json:"disclaimer,omitempty"`
+	// Every model's score when an ensemble is configured, the largest gap
+	// between them and whether it calls for human review.
+	Ensemble     []*ModelScore `protobuf:"bytes,11,rep,name=ensemble,proto3" json:"ensemble,omitempty"`
+	Disagreement *float64      `protobuf:"fixed64,12,opt,name=disagreement,proto3,oneof" json:"disagreement,omitempty"`
+	NeedsReview  bool          `protobuf:"varint,13,opt,name=needs_review,json=needsReview,proto3" json:"needs_review,omitempty"`
+	// The REST API's JSON response, which also holds what has no field
+	// here (tiling, explanation, compute cost).
+	Json          string `protobuf:"bytes,14,opt,name=json,proto3" json:"json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PredictResponse) Reset() {
+	*x = PredictResponse{}
+	mi := &file_mammoscan_v1_prediction_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PredictResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictResponse) ProtoMessage() {}
+
+func (x *PredictResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mammoscan_v1_prediction_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictResponse.ProtoReflect.Descriptor instead.
+func (*PredictResponse) Descriptor() ([]byte, []int) {
+	return file_mammoscan_v1_prediction_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PredictResponse) GetPredictionId() string {
+	if x != nil {
+		return x.PredictionId
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetPrediction() string {
+	if x != nil {
+		return x.Prediction
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetConfidenceScore() float64 {
+	if x != nil {
+		return x.ConfidenceScore
+	}
+	return 0
+}
+
+func (x *PredictResponse) GetModelName() string {
+	if x != nil {
+		return x.ModelName
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetModelVersion() string {
+	if x != nil {
+		return x.ModelVersion
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetModelThreshold() float64 {
+	if x != nil {
+		return x.ModelThreshold
+	}
+	return 0
+}
+
+func (x *PredictResponse) GetClientReference() string {
+	if x != nil {
+		return x.ClientReference
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetAccessionNumber() string {
+	if x != nil {
+		return x.AccessionNumber
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetDuplicateOf() string {
+	if x != nil {
+		return x.DuplicateOf
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
+func (x *PredictResponse) GetDisclaimer() string {
+	if x != nil {
+		return x.Disclaimer
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetEnsemble() []*ModelScore {
+	if x != nil {
+		return x.Ensemble
+	}
+	return nil
+}
+
+func (x *PredictResponse) GetDisagreement() float64 {
+	if x != nil && x.Disagreement != nil {
+		return *x.Disagreement
+	}
+	return 0
+}
+
+func (x *PredictResponse) GetNeedsReview() bool {
+	if x != nil {
+		return x.NeedsReview
+	}
+	return false
+}
+
+func (x *PredictResponse) GetJson() string {
+	if x != nil {
+		return x.Json
+	}
+	return ""
+}
+
+type ModelScore struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ModelName       string                 `protobuf:"bytes,1,opt,name=model_name,json=modelName,proto3" json:"model_name,omitempty"`
+	ConfidenceScore float64                `protobuf:"fixed64,2,opt,name=confidence_score,json=confidenceScore,proto3" json:"confidence_score,omitempty"`
+	Prediction      string                 `protobuf:"bytes,3,opt,name=prediction,proto3" json:"prediction,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ModelScore) Reset() {
+	*x = ModelScore{}
+	mi := &file_mammoscan_v1_prediction_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModelScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModelScore) ProtoMessage() {}
+
+func (x *ModelScore) ProtoReflect() protoreflect.Message {
+	mi := &file_mammoscan_v1_prediction_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModelScore.ProtoReflect.Descriptor instead.
+func (*ModelScore) Descriptor() ([]byte, []int) {
+	return file_mammoscan_v1_prediction_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ModelScore) GetModelName() string {
+	if x != nil {
+		return x.ModelName
+	}
+	return ""
+}
+
+func (x *ModelScore) GetConfidenceScore() float64 {
+	if x != nil {
+		return x.ConfidenceScore
+	}
+	return 0
+}
+
+func (x *ModelScore) GetPrediction() string {
+	if x != nil {
+		return x.Prediction
+	}
+	return ""
+}
+
+type PredictStreamRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Identifies the study in the answer; the stream does not check it.
+	Sequence      int64           `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Study         *PredictRequest `protobuf:"bytes,2,opt,name=study,proto3" json:"study,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PredictStreamRequest) Reset() {
+	*x = PredictStreamRequest{}
+	mi := &file_mammoscan_v1_prediction_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PredictStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictStreamRequest) ProtoMessage() {}
+
+func (x *PredictStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mammoscan_v1_prediction_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictStreamRequest.ProtoReflect.Descriptor instead.
+func (*PredictStreamRequest) Descriptor() ([]byte, []int) {
+	return file_mammoscan_v1_prediction_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PredictStreamRequest) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *PredictStreamRequest) GetStudy() *PredictRequest {
+	if x != nil {
+		return x.Study
+	}
+	return nil
+}
+
+type PredictStreamResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Sequence int64                  `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*PredictStreamResponse_Prediction
+	//	*PredictStreamResponse_Error
+	Result        isPredictStreamResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PredictStreamResponse) Reset() {
+	*x = PredictStreamResponse{}
+	mi := &file_mammoscan_v1_prediction_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PredictStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictStreamResponse) ProtoMessage() {}
+
+func (x *PredictStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mammoscan_v1_prediction_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictStreamResponse.ProtoReflect.Descriptor instead.
+func (*PredictStreamResponse) Descriptor() ([]byte, []int) {
+	return file_mammoscan_v1_prediction_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PredictStreamResponse) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *PredictStreamResponse) GetResult() isPredictStreamResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *PredictStreamResponse) GetPrediction() *PredictResponse {
+	if x != nil {
+		if x, ok := x.Result.(*PredictStreamResponse_Prediction); ok {
+			return x.Prediction
+		}
+	}
+	return nil
+}
+
+func (x *PredictStreamResponse) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Result.(*PredictStreamResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isPredictStreamResponse_Result interface {
+	isPredictStreamResponse_Result()
+}
+
+type PredictStreamResponse_Prediction struct {
+	Prediction *PredictResponse `protobuf:"bytes,2,opt,name=prediction,proto3,oneof"`
+}
+
+type PredictStreamResponse_Error struct {
+	Error *Error `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*PredictStreamResponse_Prediction) isPredictStreamResponse_Result() {}
+
+func (*PredictStreamResponse_Error) isPredictStreamResponse_Result() {}
+
+// Error is a failed study in a stream.
+type Error struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The gRPC status code Predict would have failed with.
+	Status int32 `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	// The REST API's stable error code, e.g. "image_required".
+	Code string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	// The error message, localized per accept-language.
+	Message       string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_mammoscan_v1_prediction_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_mammoscan_v1_prediction_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_mammoscan_v1_prediction_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Error) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *Error) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_mammoscan_v1_prediction_proto protoreflect.FileDescriptor
+
+const file_mammoscan_v1_prediction_proto_rawDesc = "" +
+	"\n" +
+	"\x1dmammoscan/v1/prediction.proto\x12\fmammoscan.v1\"\x95\x02\n" +
+	"\x0ePredictRequest\x12\x14\n" +
+	"\x05image\x18\x01 \x01(\fR\x05image\x12\x1a\n" +
+	"\bfilename\x18\x02 \x01(\tR\bfilename\x12)\n" +
+	"\x10client_reference\x18\x03 \x01(\tR\x0fclientReference\x12)\n" +
+	"\x10accession_number\x18\x04 \x01(\tR\x0faccessionNumber\x12@\n" +
+	"\x06fields\x18\x05 \x03(\v2(.mammoscan.v1.PredictRequest.FieldsEntryR\x06fields\x1a9\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xcc\x04\n" +
+	"\x0fPredictResponse\x12#\n" +
+	"\rprediction_id\x18\x01 \x01(\tR\fpredictionId\x12\x1e\n" +
+	"\n" +
+	"prediction\x18\x02 \x01(\tR\n" +
+	"prediction\x12)\n" +
+	"\x10confidence_score\x18\x03 \x01(\x01R\x0fconfidenceScore\x12\x1d\n" +
+	"\n" +
+	"model_name\x18\x04 \x01(\tR\tmodelName\x12#\n" +
+	"\rmodel_version\x18\x05 \x01(\tR\fmodelVersion\x12'\n" +
+	"\x0fmodel_threshold\x18\x06 \x01(\x01R\x0emodelThreshold\x12)\n" +
+	"\x10client_reference\x18\a \x01(\tR\x0fclientReference\x12)\n" +
+	"\x10accession_number\x18\b \x01(\tR\x0faccessionNumber\x12!\n" +
+	"\fduplicate_of\x18\t \x01(\tR\vduplicateOf\x12\x1c\n" +
+	"\tduplicate\x18\x0f \x01(\bR\tduplicate\x12\x1e\n" +
+	"\n" +
+	"disclaimer\x18\n" +
+	" \x01(\tR\n" +
+	"disclaimer\x124\n" +
+	"\bensemble\x18\v \x03(\v2\x18.mammoscan.v1.ModelScoreR\bensemble\x12'\n" +
+	"\fdisagreement\x18\f \x01(\x01H\x00R\fdisagreement\x88\x01\x01\x12!\n" +
+	"\fneeds_review\x18\r \x01(\bR\vneedsReview\x12\x12\n" +
+	"\x04json\x18\x0e \x01(\tR\x04jsonB\x0f\n" +
+	"\r_disagreement\"v\n" +
+	"\n" +
+	"ModelScore\x12\x1d\n" +
+	"\n" +
+	"model_name\x18\x01 \x01(\tR\tmodelName\x12)\n" +
+	"\x10confidence_score\x18\x02 \x01(\x01R\x0fconfidenceScore\x12\x1e\n" +
+	"\n" +
+	"prediction\x18\x03 \x01(\tR\n" +
+	"prediction\"f\n" +
+	"\x14PredictStreamRequest\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x03R\bsequence\x122\n" +
+	"\x05study\x18\x02 \x01(\v2\x1c.mammoscan.v1.PredictRequestR\x05study\"\xab\x01\n" +
+	"\x15PredictStreamResponse\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x03R\bsequence\x12?\n" +
+	"\n" +
+	"prediction\x18\x02 \x01(\v2\x1d.mammoscan.v1.PredictResponseH\x00R\n" +
+	"prediction\x12+\n" +
+	"\x05error\x18\x03 \x01(\v2\x13.mammoscan.v1.ErrorH\x00R\x05errorB\b\n" +
+	"\x06result\"M\n" +
+	"\x05Error\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage2\xb9\x01\n" +
+	"\x11PredictionService\x12F\n" +
+	"\aPredict\x12\x1c.mammoscan.v1.PredictRequest\x1a\x1d.mammoscan.v1.PredictResponse\x12\\\n" +
+	"\rPredictStream\x12\".mammoscan.v1.PredictStreamRequest\x1a#.mammoscan.v1.PredictStreamResponse(\x010\x01BUZSgithub.com/josephed37/mammoscan-AI/backend/internal/grpcapi/mammoscanv1;mammoscanv1b\x06proto3"
+
+var (
+	file_mammoscan_v1_prediction_proto_rawDescOnce sync.Once
+	file_mammoscan_v1_prediction_proto_rawDescData []byte
+)
+
+func file_mammoscan_v1_prediction_proto_rawDescGZIP() []byte {
+	file_mammoscan_v1_prediction_proto_rawDescOnce.Do(func() {
+		file_mammoscan_v1_prediction_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_mammoscan_v1_prediction_proto_rawDesc), len(file_mammoscan_v1_prediction_proto_rawDesc)))
+	})
+	return file_mammoscan_v1_prediction_proto_rawDescData
+}
+
+var file_mammoscan_v1_prediction_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_mammoscan_v1_prediction_proto_goTypes = []any{
+	(*PredictRequest)(nil),        // 0: mammoscan.v1.PredictRequest
+	(*PredictResponse)(nil),       // 1: mammoscan.v1.PredictResponse
+	(*ModelScore)(nil),            // 2: mammoscan.v1.ModelScore
+	(*PredictStreamRequest)(nil),  // 3: mammoscan.v1.PredictStreamRequest
+	(*PredictStreamResponse)(nil), // 4: mammoscan.v1.PredictStreamResponse
+	(*Error)(nil),                 // 5: mammoscan.v1.Error
+	nil,                           // 6: mammoscan.v1.PredictRequest.FieldsEntry
+}
+var file_mammoscan_v1_prediction_proto_depIdxs = []int32{
+	6, // 0: mammoscan.v1.PredictRequest.fields:type_name -> mammoscan.v1.PredictRequest.FieldsEntry
+	2, // 1: mammoscan.v1.PredictResponse.ensemble:type_name -> mammoscan.v1.ModelScore
+	0, // 2: mammoscan.v1.PredictStreamRequest.study:type_name -> mammoscan.v1.PredictRequest
+	1, // 3: mammoscan.v1.PredictStreamResponse.prediction:type_name -> mammoscan.v1.PredictResponse
+	5, // 4: mammoscan.v1.PredictStreamResponse.error:type_name -> mammoscan.v1.Error
+	0, // 5: mammoscan.v1.PredictionService.Predict:input_type -> mammoscan.v1.PredictRequest
+	3, // 6: mammoscan.v1.PredictionService.PredictStream:input_type -> mammoscan.v1.PredictStreamRequest
+	1, // 7: mammoscan.v1.PredictionService.Predict:output_type -> mammoscan.v1.PredictResponse
+	4, // 8: mammoscan.v1.PredictionService.PredictStream:output_type -> mammoscan.v1.PredictStreamResponse
+	7, // [7:9] is the sub-list for method output_type
+	5, // [5:7] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_mammoscan_v1_prediction_proto_init() }
+func file_mammoscan_v1_prediction_proto_init() {
+	if File_mammoscan_v1_prediction_proto != nil {
+		return
+	}
+	file_mammoscan_v1_prediction_proto_msgTypes[1].OneofWrappers = []any{}
+	file_mammoscan_v1_prediction_proto_msgTypes[4].OneofWrappers = []any{
+		(*PredictStreamResponse_Prediction)(nil),
+		(*PredictStreamResponse_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_mammoscan_v1_prediction_proto_rawDesc), len(file_mammoscan_v1_prediction_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_mammoscan_v1_prediction_proto_goTypes,
+		DependencyIndexes: file_mammoscan_v1_prediction_proto_depIdxs,
+		MessageInfos:      file_mammoscan_v1_prediction_proto_msgTypes,
+	}.Build()
+	File_mammoscan_v1_prediction_proto = out.File
+	file_mammoscan_v1_prediction_proto_goTypes = nil
+	file_mammoscan_v1_prediction_proto_depIdxs = nil
+}
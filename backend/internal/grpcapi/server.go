@@ -0,0 +1,290 @@
+// backend/internal/grpcapi/server.go
+/*
+ * This file serves the gRPC prediction service (proto/mammoscan/v1), for
+ * hospital integration middleware that speaks gRPC rather than REST
+ * multipart.
+ *
+ * Every study is handed to the Backend, which submits it to the REST
+ * API's POST /api/v1/predict; the answer is translated back. gRPC callers
+ * therefore share the REST pipeline entirely: authentication and access
+ * control, tenancy, validation, storage, thresholds and batching on the
+ * model. Request metadata becomes HTTP headers, so callers authenticate
+ * with `authorization` or `x-api-key` and pick their tenant and language
+ * with `x-tenant-id` and `accept-language`.
+ *
+ * Failures become gRPC status codes carrying a google.rpc.ErrorInfo whose
+ * reason is the REST error code; in a stream they are answered in place
+ * of the study's prediction.
+ *
+ * The same service is served by grpc-go (Register) and as an HTTP
+ * handler speaking the Connect, gRPC and gRPC-web protocols (see
+ * connect.go), for browsers and proxies that cannot speak native gRPC.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+//go:generate protoc -I ../../proto --go_out=. --go_opt=module=github.com/josephed37/mammoscan-AI/backend/internal/grpcapi --go-grpc_out=. --go-grpc_opt=module=github.com/josephed37/mammoscan-AI/backend/internal/grpcapi --connect-go_out=. --connect-go_opt=module=github.com/josephed37/mammoscan-AI/backend/internal/grpcapi mammoscan/v1/prediction.proto
+
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/grpcapi/mammoscanv1"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the domain of the ErrorInfo attached to failures.
+const ErrorDomain = "mammoscan.ai"
+
+// Backend scores a study as POST /api/v1/predict would for the caller
+// described by header and remoteAddr, returning the HTTP status and JSON
+// body of the answer.
+type Backend func(ctx context.Context, header http.Header, remoteAddr, filename string, image []byte, fields map[string]string) (int, []byte)
+
+// Server implements mammoscan.v1.PredictionService.
+type Server struct {
+	mammoscanv1.UnimplementedPredictionServiceServer
+
+	backend Backend
+	// concurrency bounds the studies of one stream scored at a time.
+	concurrency int
+}
+
+// New returns a server scoring studies with backend, up to concurrency
+// at a time per stream.
+func New(backend Backend, concurrency int) *Server {
+	return &Server{backend: backend, concurrency: max(concurrency, 1)}
+}
+
+// Predict scores one study.
+func (s *Server) Predict(ctx context.Context, req *mammoscanv1.PredictRequest) (*mammoscanv1.PredictResponse, error) {
+	header, remoteAddr := caller(ctx)
+	resp, st := s.score(ctx, header, remoteAddr, req)
+	if st != nil {
+		return nil, st.Err()
+	}
+	return resp, nil
+}
+
+// PredictStream scores the studies of a stream concurrently, answering
+// each as it is scored.
+func (s *Server) PredictStream(stream mammoscanv1.PredictionService_PredictStreamServer) error {
+	ctx := stream.Context()
+	header, remoteAddr := caller(ctx)
+	err := s.serveStream(ctx, header, remoteAddr, stream.Recv, stream.Send)
+	if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return status.FromContextError(err).Err()
+	}
+	return err
+}
+
+// serveStream scores the studies received with recv, up to s.concurrency
+// at a time, and answers each with send as it is scored. It returns when
+// recv reports the end of the stream and every answer has been sent.
+func (s *Server) serveStream(ctx context.Context, header http.Header, remoteAddr string, recv func() (*mammoscanv1.PredictStreamRequest, error), send func(*mammoscanv1.PredictStreamResponse) error) error {
+	slots := make(chan struct{}, s.concurrency)
+	var wg sync.WaitGroup
+	// Answers may not be sent once the handler has returned.
+	defer wg.Wait()
+	var sendMu sync.Mutex
+	for {
+		req, err := recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			out := &mammoscanv1.PredictStreamResponse{Sequence: req.GetSequence()}
+			if resp, st := s.score(ctx, header, remoteAddr, req.GetStudy()); st != nil {
+				out.Result = &mammoscanv1.PredictStreamResponse_Error{Error: &mammoscanv1.Error{
+					Status:  int32(st.Code()),
+					Code:    errorCode(st),
+					Message: st.Message(),
+				}}
+			} else {
+				out.Result = &mammoscanv1.PredictStreamResponse_Prediction{Prediction: resp}
+			}
+			sendMu.Lock()
+			defer sendMu.Unlock()
+			// A failed send means the stream is gone; recv reports it.
+			_ = send(out)
+		}()
+	}
+}
+
+// score submits a study on behalf of the caller described by header and
+// remoteAddr to the backend and translates the answer.
+func (s *Server) score(ctx context.Context, header http.Header, remoteAddr string, req *mammoscanv1.PredictRequest) (*mammoscanv1.PredictResponse, *status.Status) {
+	if len(req.GetImage()) == 0 {
+		// As the REST API answers a form without an image part.
+		return nil, errorStatus(http.StatusBadRequest, models.ErrorResponse{Error: "image file is required", Code: "image_required"})
+	}
+	fields := make(map[string]string, len(req.GetFields())+2)
+	for k, v := range req.GetFields() {
+		fields[k] = v
+	}
+	if req.GetClientReference() != "" {
+		fields["client_reference"] = req.GetClientReference()
+	}
+	if req.GetAccessionNumber() != "" {
+		fields["accession_number"] = req.GetAccessionNumber()
+	}
+	filename := req.GetFilename()
+	if filename == "" {
+		filename = "study"
+	}
+	code, body := s.backend(ctx, header, remoteAddr, filename, req.GetImage(), fields)
+	if code < 200 || code > 299 {
+		var e models.ErrorResponse
+		if err := json.Unmarshal(body, &e); err != nil || e.Error == "" {
+			e.Error = http.StatusText(code)
+		}
+		return nil, errorStatus(code, e)
+	}
+	var pred models.PredictionResponse
+	if err := json.Unmarshal(body, &pred); err != nil {
+		return nil, status.Newf(codes.Internal, "unreadable prediction: %v", err)
+	}
+	return toProto(&pred, body), nil
+}
+
+// caller turns the request metadata into HTTP headers and returns them
+// with the client's address.
+func caller(ctx context.Context) (http.Header, string) {
+	header := make(http.Header)
+	md, _ := metadata.FromIncomingContext(ctx)
+	for k, values := range md {
+		if transportHeader(k) {
+			continue
+		}
+		for _, v := range values {
+			header.Add(k, v)
+		}
+	}
+	var remoteAddr string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		remoteAddr = p.Addr.String()
+	}
+	return header, remoteAddr
+}
+
+// transportHeader reports whether the metadata key or HTTP header k
+// belongs to the RPC protocol rather than the caller, having no
+// counterpart in a REST request: pseudo-headers, gRPC and Connect
+// headers, binary metadata and the framing of the RPC body.
+func transportHeader(k string) bool {
+	k = strings.ToLower(k)
+	switch k {
+	case "content-type", "content-length", "content-encoding", "accept-encoding", "te", "x-grpc-web", "x-user-agent":
+		return true
+	}
+	return strings.HasPrefix(k, ":") || strings.HasPrefix(k, "grpc-") || strings.HasPrefix(k, "connect-") || strings.HasSuffix(k, "-bin")
+}
+
+// errorStatus translates a REST error answer.
+func errorStatus(httpStatus int, e models.ErrorResponse) *status.Status {
+	st := status.New(grpcCode(httpStatus), e.Error)
+	info := &errdetails.ErrorInfo{
+		Reason:   e.Code,
+		Domain:   ErrorDomain,
+		Metadata: map[string]string{"http_status": strconv.Itoa(httpStatus)},
+	}
+	if e.Detail != "" {
+		info.Metadata["detail"] = e.Detail
+	}
+	if detailed, err := st.WithDetails(info); err == nil {
+		return detailed
+	}
+	return st
+}
+
+// errorCode returns the REST error code carried by st, if any.
+func errorCode(st *status.Status) string {
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.GetDomain() == ErrorDomain {
+			return info.GetReason()
+		}
+	}
+	return ""
+}
+
+// grpcCode maps an HTTP status to the gRPC code with the same meaning.
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden, http.StatusUnavailableForLegalReasons:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusRequestEntityTooLarge, http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	if httpStatus >= 400 && httpStatus < 500 {
+		return codes.FailedPrecondition
+	}
+	return codes.Internal
+}
+
+// toProto converts a REST prediction, keeping its JSON.
+func toProto(p *models.PredictionResponse, body []byte) *mammoscanv1.PredictResponse {
+	resp := &mammoscanv1.PredictResponse{
+		PredictionId:    p.PredictionID,
+		Prediction:      p.Prediction,
+		ConfidenceScore: p.ConfidenceScore,
+		ModelName:       p.ModelName,
+		ModelVersion:    p.ModelVersion,
+		ModelThreshold:  p.ModelThreshold,
+		ClientReference: p.ClientReference,
+		AccessionNumber: p.AccessionNumber,
+		DuplicateOf:     p.DuplicateOf,
+		Duplicate:       p.Duplicate,
+		Disclaimer:      p.Disclaimer,
+		Disagreement:    p.Disagreement,
+		NeedsReview:     p.NeedsReview,
+		Json:            string(body),
+	}
+	for _, m := range p.Ensemble {
+		resp.Ensemble = append(resp.Ensemble, &mammoscanv1.ModelScore{
+			ModelName:       m.ModelName,
+			ConfidenceScore: m.ConfidenceScore,
+			Prediction:      m.Prediction,
+		})
+	}
+	return resp
+}
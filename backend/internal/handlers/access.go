@@ -0,0 +1,194 @@
+// backend/internal/handlers/access.go
+/*
+ * This file contains the middleware that enforces the RBAC policy.
+ *
+ * Callers authenticate with an API key (`Authorization: Bearer <key>` or
+ * `X-API-Key`) or, when a JWKS is configured, a JWT from the site's
+ * identity provider (`Authorization: Bearer <jwt>`). The request's tenant defaults to the caller's own; acting
+ * on another tenant via X-Tenant-ID requires a rule that grants it. Fields
+ * the caller's roles may not see are stripped from JSON responses.
+ *
+ * Emergency access through break-glass rules needs a stated justification
+ * and is audited (see breakglass.go).
+ *
+ * API keys with a signing secret must also sign every request (see
+ * signing.go). Repeated failures lock the caller out (see lockout.go).
+ *
+ * Browsers instead present a single-use upload token minted through
+ * `POST /api/v1/upload-tokens`; it acts as the minting principal, for its
+ * own tenant, and only on the predict endpoint.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/access"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/uploadtoken"
+)
+
+// principalKey is the gin context key holding the authenticated caller.
+const principalKey = "principal"
+
+// Authorize is a middleware that authenticates the caller and checks the
+// route against the access policy. It is a no-op when no policy is
+// configured.
+func (h *Handler) Authorize(c *gin.Context) {
+	if h.Access == nil {
+		c.Next()
+		return
+	}
+	policy := h.Access.Policy()
+	if h.lockedOut(c) {
+		return
+	}
+
+	key := apiKey(c)
+	var principal *access.Principal
+	if strings.HasPrefix(key, uploadtoken.Prefix) {
+		var ok bool
+		if principal, ok = h.redeemUploadToken(c, key); !ok {
+			return
+		}
+	} else if h.JWT != nil && access.LooksLikeJWT(key) {
+		var err error
+		if principal, err = h.JWT.Authenticate(c.Request.Context(), key); err != nil {
+			h.authFailed(c, http.StatusUnauthorized, "unauthenticated", err.Error(), "valid bearer token required")
+			return
+		}
+	} else {
+		var ok bool
+		if principal, ok = policy.Authenticate(key); !ok {
+			h.authFailed(c, http.StatusUnauthorized, "unauthenticated", "missing or unknown API key", "valid API key required")
+			return
+		}
+		if !h.verifySignature(c, principal.ID) {
+			return
+		}
+	}
+	h.authSucceeded(c)
+
+	tenant := c.GetHeader(tenantHeader)
+	if tenant == "" {
+		tenant = principal.Tenant
+		c.Request.Header.Set(tenantHeader, tenant)
+	}
+
+	decision := policy.Authorize(principal, c.Request.Method, c.FullPath(), tenant)
+	if !decision.Allowed {
+		var ok bool
+		if decision, ok = h.authorizeBreakGlass(c, policy, principal, tenant); !ok {
+			return
+		}
+	}
+	c.Set(principalKey, principal)
+
+	if len(decision.Redact) == 0 {
+		c.Next()
+		return
+	}
+	w := &redactingWriter{ResponseWriter: c.Writer}
+	c.Writer = w
+	c.Next()
+	w.flush(decision.Redact)
+}
+
+// redeemUploadToken consumes an upload token presented on the predict
+// route and returns the principal it acts as. On failure the request has
+// already been aborted.
+func (h *Handler) redeemUploadToken(c *gin.Context, token string) (*access.Principal, bool) {
+	if h.UploadTokens == nil || c.Request.Method != http.MethodPost || c.FullPath() != "/api/v1/predict" {
+		h.Stats.RecordError(http.StatusForbidden, "upload token used outside predict")
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{Error: "upload tokens may only be used to submit a study", Code: "forbidden"})
+		return nil, false
+	}
+	principal, err := h.UploadTokens.Redeem(token)
+	if err != nil {
+		h.authFailed(c, http.StatusUnauthorized, "invalid_upload_token", err.Error(), err.Error())
+		return nil, false
+	}
+	if tenant := c.GetHeader(tenantHeader); tenant != "" && tenant != principal.Tenant {
+		h.Stats.RecordError(http.StatusForbidden, "upload token used for another tenant")
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{Error: "upload token is bound to a different tenant", Code: "forbidden"})
+		return nil, false
+	}
+	return principal, true
+}
+
+// IssueUploadToken mints a short-lived, single-use token that lets a
+// browser submit one study on behalf of the authenticated caller.
+func (h *Handler) IssueUploadToken(c *gin.Context) {
+	principal, ok := c.Get(principalKey)
+	if !ok || h.UploadTokens == nil {
+		h.respondErrorCode(c, http.StatusConflict, "upload_tokens_disabled", "upload tokens require an access policy")
+		return
+	}
+	p := *principal.(*access.Principal)
+	// A token minted for another tenant (by a principal allowed to act on
+	// it) is bound to that tenant.
+	p.Tenant = c.GetHeader(tenantHeader)
+
+	token, expires, err := h.UploadTokens.Issue(p)
+	if err != nil {
+		h.respondError(c, http.StatusInternalServerError, "failed to mint upload token")
+		return
+	}
+	c.JSON(http.StatusCreated, models.UploadToken{Token: token, ExpiresAt: expires})
+}
+
+// PrincipalID returns the ID of the authenticated caller of c, or "" when
+// no access policy authenticated it. Access logs name the caller with it.
+func PrincipalID(c *gin.Context) string {
+	if p, ok := c.Get(principalKey); ok {
+		return p.(*access.Principal).ID
+	}
+	return ""
+}
+
+// TenantID returns the tenant c was made for, as sent by the caller.
+func TenantID(c *gin.Context) string {
+	return c.GetHeader(tenantHeader)
+}
+
+// apiKey extracts the presented API key from the request.
+func apiKey(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return ""
+}
+
+// redactingWriter buffers the response body so redacted fields can be
+// removed before anything reaches the client.
+type redactingWriter struct {
+	gin.ResponseWriter
+	buf bytes.Buffer
+}
+
+func (w *redactingWriter) Write(b []byte) (int, error) {
+	return w.buf.Write(b)
+}
+
+func (w *redactingWriter) WriteString(s string) (int, error) {
+	return w.buf.WriteString(s)
+}
+
+func (w *redactingWriter) flush(fields []string) {
+	body := w.buf.Bytes()
+	if strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		body = access.RedactJSON(body, fields)
+	}
+	w.ResponseWriter.Write(body)
+}
@@ -0,0 +1,188 @@
+// backend/internal/handlers/admin.go
+/*
+ * This file contains the read-only admin APIs that back the internal ops
+ * dashboard.
+ *
+ * `GET /admin/overview` aggregates everything an operator needs on one
+ * screen: service stats, model status, recent errors, drift indicators
+ * and queue depths. The admin routes are protected by a shared bearer
+ * token (ADMIN_TOKEN).
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"image"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/fairness"
+	"github.com/josephed37/mammoscan-AI/backend/internal/inference"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+	"github.com/josephed37/mammoscan-AI/backend/internal/store"
+	"gorgonia.org/tensor"
+)
+
+// RequireAdminToken is a middleware that only lets requests carrying
+// `Authorization: Bearer <token>` through to the admin handlers. The token
+// is read on every request so a rotated secret takes effect immediately.
+func RequireAdminToken(token func() string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		presented := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		expected := token()
+		if expected == "" || subtle.ConstantTimeCompare([]byte(presented), []byte(expected)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{Error: "admin token required", Code: "unauthenticated"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// AdminOverview returns the aggregated dashboard view of the service.
+func (h *Handler) AdminOverview(c *gin.Context) {
+	_, served := h.ServedModel()
+	overview := models.AdminOverview{
+		Service: h.Stats.Snapshot(),
+		Model:   served,
+		// Nil unless a maximum model age is configured.
+		ModelAge: h.MaxModelAge.check(served, time.Now()),
+	}
+
+	// --- Queue Depths ---
+	// Each optional subsystem that buffers work reports its backlog here.
+	if h.Offline != nil {
+		overview.Queues.OfflinePending = h.Offline.Len()
+	}
+	if h.Billing != nil {
+		overview.Queues.BillingDropped = h.Billing.Dropped()
+	}
+	if h.Webhooks != nil {
+		overview.Queues.WebhooksDropped = h.Webhooks.Dropped()
+		overview.Queues.WebhooksPending = h.Webhooks.Pending()
+		overview.Queues.WebhooksDeadLettered = h.Webhooks.DeadLettered()
+	}
+	overview.Queues.EventsDropped = h.Events.Dropped()
+	if wb, ok := h.Store.(*store.WriteBehind); ok {
+		overview.Queues.StorePending = wb.Pending()
+	}
+	if h.Journal != nil {
+		if pending, err := h.Journal.Pending(); err == nil {
+			overview.Queues.JournalPending = len(pending)
+		}
+	}
+	if h.Fingerprints != nil {
+		overview.DuplicateIndexSize = h.Fingerprints.Len()
+	}
+	if h.ModelSwaps != nil {
+		status := h.ModelSwaps.Status()
+		overview.ModelSwap = &status
+	}
+	if h.ModelMemory != nil {
+		usage := h.ModelMemory.Usage()
+		overview.ModelMemory = &usage
+	}
+	if h.Batcher != nil {
+		batching := h.Batcher.Status()
+		overview.Batching = &batching
+	}
+
+	c.JSON(http.StatusOK, overview)
+}
+
+// ReportPreview renders the scheduled summary for `?period=daily|weekly`
+// on demand, as JSON or (with `?format=html`) as the email body.
+func (h *Handler) ReportPreview(c *gin.Context) {
+	if h.Reports == nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "scheduled reports are not configured", Code: "reports_not_configured"})
+		return
+	}
+
+	summary := h.Reports.Preview(c.DefaultQuery("period", "daily"))
+	// The end of the period is always "now"; the figures are what a
+	// cached copy has to match.
+	validator := summary
+	validator.To = time.Time{}
+	if c.Query("format") == "html" {
+		body, err := summary.HTML()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error(), Code: "internal_error"})
+			return
+		}
+		key, _ := json.Marshal(validator)
+		respondCached(c, reportMaxAge, etag("html", string(key)), "text/html; charset=utf-8", body)
+		return
+	}
+	respondCachedJSON(c, reportMaxAge, validator, summary)
+}
+
+// FairnessReport returns subgroup positivity and (where feedback exists)
+// sensitivity/specificity over the last `?days=` days (default 30).
+func (h *Handler) FairnessReport(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days <= 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "days must be a positive integer", Code: "invalid_request"})
+		return
+	}
+
+	records, err := h.Store.Find(c.Request.Context(), store.Filter{
+		Tenant:      c.Query("tenant"),
+		CreatedFrom: time.Now().AddDate(0, 0, -days),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "prediction lookup failed", Code: "internal_error"})
+		return
+	}
+	report := fairness.Analyse(records, h.Fairness)
+	validator := report
+	validator.GeneratedAt = time.Time{}
+	respondCachedJSON(c, reportMaxAge, validator, report)
+}
+
+// OperatorProfiler is implemented by inference engines that can time the
+// individual operations of their graph.
+type OperatorProfiler interface {
+	Profile(input tensor.Tensor, runs int) (*inference.Profile, error)
+}
+
+// ProfileModel runs the served model `?runs=` times (default 3) on a
+// synthetic study and reports the time spent per operator, so model work
+// can target the layers that dominate inference. Predictions wait while
+// the profile runs.
+func (h *Handler) ProfileModel(c *gin.Context) {
+	engine, served := h.ServedModel()
+	profiler, ok := engine.(OperatorProfiler)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, models.ErrorResponse{Error: "the inference backend does not support operator profiling", Code: "profiling_unavailable"})
+		return
+	}
+	runs, err := strconv.Atoi(c.DefaultQuery("runs", "3"))
+	if err != nil || runs <= 0 || runs > inference.MaxProfileRuns {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: fmt.Sprintf("runs must be between 1 and %d", inference.MaxProfileRuns), Code: "invalid_request"})
+		return
+	}
+
+	// Operator cost depends on the input shape, not its content, so a
+	// uniform image is as good as a real study.
+	img := image.NewGray(image.Rect(0, 0, 256, 256))
+	for i := range img.Pix {
+		img.Pix[i] = 128
+	}
+	profile, err := profiler.Profile(preprocess.TensorFor(profiler, img), runs)
+	if err != nil {
+		status, code := classifyError(err)
+		c.JSON(status, models.ErrorResponse{Error: fmt.Sprintf("profiling failed: %v", err), Code: code})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"model": served, "profile": profile})
+}
@@ -0,0 +1,231 @@
+// backend/internal/handlers/batch.go
+/*
+ * This file contains the batch prediction endpoint.
+ *
+ *   POST /api/v1/predict/batch
+ *
+ * takes several images in one multipart request, as repeated `images`
+ * parts or as one zip `archive` part, and returns one result per image,
+ * in upload order, each with its file name and the status and body that
+ * POST /api/v1/predict would have returned for it. The other form fields
+ * (laterality, explain, ...) apply to every image.
+ *
+ * Images go through the single-image pipeline concurrently, so they are
+ * validated, stored, journaled and published exactly like single
+ * predictions; concurrent inputs are batched on the model by the Batcher.
+ * One image failing does not fail the batch.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// errInvalidArchive is returned when the archive part is not a readable
+// zip file.
+var errInvalidArchive = errors.New("invalid archive")
+
+// PredictBatch scores every image of a batch upload.
+func (h *Handler) PredictBatch(c *gin.Context) {
+	// A stale model refuses every image; the upload need not be read.
+	if h.refuseStaleModel(c) {
+		return
+	}
+	uploads, err := h.readBatchUpload(c)
+	if err != nil {
+		h.respondBatchUploadError(c, err)
+		return
+	}
+
+	header, fields := forwardedRequest(c)
+	c.JSON(http.StatusOK, h.predictUploads(c.Request.Context(), header, uploads, fields, nil))
+}
+
+// respondBatchUploadError reports an error from readBatchUpload.
+func (h *Handler) respondBatchUploadError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errImageRequired):
+		h.respondErrorCode(c, http.StatusBadRequest, "image_required", "at least one image is required")
+	case errors.Is(err, errUploadTooLarge):
+		h.respondErrorCode(c, http.StatusRequestEntityTooLarge, "upload_too_large", err.Error())
+	case errors.Is(err, errInvalidArchive):
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_archive", err.Error())
+	default:
+		h.respondError(c, http.StatusInternalServerError, "failed to read uploaded files")
+	}
+}
+
+// forwardedRequest returns the headers and form fields of c that apply to
+// every image of an upload: each image is submitted for the caller's
+// tenant, in its language, with the same parameters.
+func forwardedRequest(c *gin.Context) (http.Header, map[string]string) {
+	fields := make(map[string]string, len(c.Request.PostForm))
+	for k, v := range c.Request.PostForm {
+		if len(v) > 0 {
+			fields[k] = v[0]
+		}
+	}
+	header := make(http.Header)
+	for _, k := range []string{tenantHeader, "Accept-Language"} {
+		if v := c.GetHeader(k); v != "" {
+			header.Set(k, v)
+		}
+	}
+	return header, fields
+}
+
+// predictUploads scores uploads concurrently through the single-image
+// pipeline. progress, when set, is called with the fraction done after
+// each image.
+func (h *Handler) predictUploads(ctx context.Context, header http.Header, uploads []upload, fields map[string]string, progress func(float64)) models.BatchPredictionResponse {
+	resp := models.BatchPredictionResponse{Results: make([]models.BatchItemResult, len(uploads))}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	for range min(len(uploads), runtime.GOMAXPROCS(0)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				up := uploads[i]
+				status, body := h.ingest(ctx, header, up.filename, up.image, fields)
+				resp.Results[i] = batchItemResult(up.filename, status, body)
+				if progress != nil {
+					mu.Lock()
+					done++
+					progress(float64(done) / float64(len(uploads)))
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range uploads {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	for _, r := range resp.Results {
+		if r.Result != nil {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+	return resp
+}
+
+// batchItemResult decodes the predict response for one image.
+func batchItemResult(filename string, status int, body []byte) models.BatchItemResult {
+	r := models.BatchItemResult{Filename: filename, Status: status}
+	if status == http.StatusOK {
+		var pred models.PredictionResponse
+		if err := json.Unmarshal(body, &pred); err == nil {
+			r.Result = &pred
+			return r
+		}
+	}
+	var e models.ErrorResponse
+	if err := json.Unmarshal(body, &e); err != nil || e.Code == "" {
+		e = models.ErrorResponse{Error: "prediction failed", Code: "internal_error"}
+	}
+	r.Error = &e
+	return r
+}
+
+// readBatchUpload parses a batch upload: `images` (or `image`) file
+// parts, or zip `archive` parts.
+func (h *Handler) readBatchUpload(c *gin.Context) ([]upload, error) {
+	limits := h.uploadLimits()
+	var uploads []upload
+	var total int64
+	add := func(filename string, data []byte) error {
+		if len(uploads) == limits.MaxBatchImages {
+			return fmt.Errorf("%w: more than %d images", errUploadTooLarge, limits.MaxBatchImages)
+		}
+		if total += int64(len(data)); total > limits.MaxBatchBytes {
+			return fmt.Errorf("%w: images exceed %d bytes", errUploadTooLarge, limits.MaxBatchBytes)
+		}
+		uploads = append(uploads, upload{image: data, filename: filename})
+		return nil
+	}
+
+	err := readForm(c, limits.MaxBatchBytes, func(part *multipart.Part) error {
+		switch part.FormName() {
+		case "images", "image":
+			data, err := receiveImage(part, limits)
+			if err != nil {
+				return err
+			}
+			return add(part.FileName(), data)
+		case "archive":
+			archiveLimits := limits
+			archiveLimits.MaxImageBytes = limits.MaxBatchBytes
+			data, err := receiveImage(part, archiveLimits)
+			if err != nil {
+				return err
+			}
+			return unzipImages(data, limits.MaxImageBytes, add)
+		}
+		return drainPart(part)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(uploads) == 0 {
+		return nil, errImageRequired
+	}
+	return uploads, nil
+}
+
+// unzipImages hands every file of a zip archive to add, skipping
+// directories and hidden files such as __MACOSX/ metadata. Entries are
+// read through a limit, whatever size the archive claims for them.
+func unzipImages(data []byte, maxImageBytes int64, add func(filename string, data []byte) error) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("%w: %v", errInvalidArchive, err)
+	}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") || strings.HasPrefix(path.Base(f.Name), ".") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", errInvalidArchive, f.Name, err)
+		}
+		image, err := io.ReadAll(io.LimitReader(rc, maxImageBytes+1))
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", errInvalidArchive, f.Name, err)
+		}
+		if int64(len(image)) > maxImageBytes {
+			return fmt.Errorf("%w: %s exceeds %d bytes", errUploadTooLarge, f.Name, maxImageBytes)
+		}
+		if err := add(f.Name, image); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// backend/internal/handlers/breakglass.go
+/*
+ * This file contains break-glass emergency access.
+ *
+ * Access policies may grant emergency access with break-glass rules (see
+ * internal/access). A caller uses one by stating why, in the request:
+ *
+ *   X-Break-Glass-Justification: patient in ED, prior study from Berlin needed
+ *
+ * A request the caller's ordinary rules refuse but a break-glass rule
+ * allows is refused without a justification and let through with one.
+ * Every access granted this way is logged, recorded in the audit log and
+ * sent as a security.break_glass webhook to the tenant whose data was
+ * accessed, so it can be reviewed. The audit entry is written before
+ * access is granted: when it cannot be, the request is refused.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/access"
+	"github.com/josephed37/mammoscan-AI/backend/internal/events"
+	"github.com/josephed37/mammoscan-AI/backend/internal/logging"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// breakGlassHeader carries the justification of an emergency access.
+const breakGlassHeader = "X-Break-Glass-Justification"
+
+// Bounds of a justification, so it says something and stays readable.
+const (
+	minJustificationLen = 10
+	maxJustificationLen = 1000
+)
+
+// authorizeBreakGlass decides a request the ordinary rules refused. It
+// reports whether break-glass access was granted; when it was not, the
+// request has been aborted.
+func (h *Handler) authorizeBreakGlass(c *gin.Context, policy *access.Policy, principal *access.Principal, tenant string) (access.Decision, bool) {
+	decision := policy.AuthorizeBreakGlass(principal, c.Request.Method, c.FullPath(), tenant)
+	if !decision.Allowed {
+		h.Stats.RecordError(http.StatusForbidden, "access denied by policy")
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{Error: "not permitted for this role or tenant", Code: "forbidden"})
+		return decision, false
+	}
+	justification := strings.TrimSpace(c.GetHeader(breakGlassHeader))
+	if justification == "" {
+		h.Stats.RecordError(http.StatusForbidden, "break-glass access without justification")
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{
+			Error: "emergency access requires a justification in the " + breakGlassHeader + " header",
+			Code:  "justification_required",
+		})
+		return decision, false
+	}
+	if n := len([]rune(justification)); n < minJustificationLen || n > maxJustificationLen {
+		h.Stats.RecordError(http.StatusBadRequest, "invalid break-glass justification")
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: fmt.Sprintf("justification must be %d to %d characters", minJustificationLen, maxJustificationLen),
+			Code:  "invalid_justification",
+		})
+		return decision, false
+	}
+
+	grant := events.BreakGlass{
+		Principal:       principal.ID,
+		PrincipalTenant: principal.Tenant,
+		Method:          c.Request.Method,
+		Path:            c.Request.URL.Path,
+		Justification:   justification,
+		ClientIP:        c.ClientIP(),
+		RequestID:       logging.RequestID(c.Request.Context()),
+	}
+	ev := events.New(events.BreakGlassAccess, tenant, grant)
+	if h.Audit != nil {
+		if err := h.Audit(ev); err != nil {
+			slog.ErrorContext(c.Request.Context(), "break-glass access refused: audit log unavailable",
+				"principal", grant.Principal, "tenant", tenant, "path", grant.Path, "error", err)
+			h.Stats.RecordError(http.StatusServiceUnavailable, "break-glass access not recorded")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, models.ErrorResponse{
+				Error: "emergency access cannot be recorded in the audit log; retry later",
+				Code:  "audit_unavailable",
+			})
+			return decision, false
+		}
+	}
+	slog.WarnContext(c.Request.Context(), "break-glass access",
+		"principal", grant.Principal, "tenant", tenant, "method", grant.Method, "path", grant.Path,
+		"justification", justification)
+	h.Events.PublishContext(c.Request.Context(), ev)
+	return decision, true
+}
@@ -0,0 +1,98 @@
+// backend/internal/handlers/caching.go
+/*
+ * This file contains HTTP caching for slow-changing resources.
+ *
+ * Dashboards poll model metadata, capabilities and reports every few
+ * seconds although they rarely change. These responses carry an ETag and
+ * a short Cache-Control lifetime; a request whose If-None-Match matches
+ * the current ETag gets an empty 304 instead of the document.
+ *
+ * Responses are marked private: they sit behind authentication and some
+ * are tenant-scoped, so shared caches must not keep them.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/jsonenc"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// Cache lifetimes. Metadata changes only on a model reload or restart;
+// reports move with traffic, so clients revalidate them sooner.
+const (
+	metadataMaxAge = time.Minute
+	reportMaxAge   = 5 * time.Second
+)
+
+// GetModel returns metadata about the served model.
+func (h *Handler) GetModel(c *gin.Context) {
+	_, served := h.ServedModel()
+	respondCachedJSON(c, metadataMaxAge, nil, served)
+}
+
+// respondCachedJSON writes body as JSON with caching headers. The ETag is
+// derived from validator, or from body when validator is nil; pass a
+// validator when body contains values that change on every request (such
+// as a generation timestamp) but do not matter to the client's cache.
+func respondCachedJSON(c *gin.Context, maxAge time.Duration, validator, body any) {
+	buf, err := jsonenc.Encode(body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error(), Code: "internal_error"})
+		return
+	}
+	defer jsonenc.Release(buf)
+	data := buf.Bytes()
+	tag := string(data)
+	if validator != nil {
+		v, err := jsonenc.Marshal(validator)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error(), Code: "internal_error"})
+			return
+		}
+		tag = string(v)
+	}
+	respondCached(c, maxAge, etag("json", tag), jsonContentType, data)
+}
+
+// respondCached writes data with the given ETag and lifetime, or a 304
+// when the client already holds it.
+func respondCached(c *gin.Context, maxAge time.Duration, tag, contentType string, data []byte) {
+	c.Header("ETag", tag)
+	c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int(maxAge.Seconds())))
+	if etagMatches(c.GetHeader("If-None-Match"), tag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// etag builds a strong ETag from a representation name and its content.
+func etag(representation, content string) string {
+	sum := sha256.Sum256([]byte(representation + "\x00" + content))
+	return `"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+// etagMatches applies If-None-Match's weak comparison: any listed tag,
+// with or without the W/ prefix, or "*".
+func etagMatches(header, tag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+// backend/internal/handlers/capabilities.go
+/*
+ * This file contains the capability discovery handler.
+ *
+ * Deployments differ: the edge build has no admin APIs, image retention,
+ * encryption and duplicate detection are opt-in, and so on.
+ *
+ *   GET /api/v1/capabilities
+ *
+ * reports what this deployment actually supports, derived from the same
+ * configuration the handlers run with, so it cannot drift from reality.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+	"github.com/josephed37/mammoscan-AI/backend/internal/stream"
+)
+
+// Capabilities describes the features and limits of this deployment.
+func (h *Handler) Capabilities(c *gin.Context) {
+	_, served := h.ServedModel()
+	caps := models.Capabilities{
+		BuildProfile:     h.BuildProfile,
+		Formats:          h.Validation.AllowedFormats(),
+		MultiFramePolicy: string(h.DecodeOptions.MultiFrame),
+		Models:           []models.ModelInfo{served},
+		Limits: models.CapabilityLimits{
+			MaxStreamFrameBytes:      stream.MaxFrameSize,
+			MaxStreamDurationSeconds: h.StreamMaxDuration.Seconds(),
+			MaxCorrelationFieldLen:   maxCorrelationFieldLen,
+			MaxListPageSize:          maxLookupResults,
+			MaxJobWaitSeconds:        maxJobWait.Seconds(),
+			MaxBatchImages:           h.uploadLimits().MaxBatchImages,
+			MinImageWidth:            max(h.Validation.MinWidth, 1),
+			MinImageHeight:           max(h.Validation.MinHeight, 1),
+		},
+		Features: map[string]bool{
+			// Explanations (the `explain` parameter) are built from
+			// tiled patch scores, Grad-CAM or occlusion.
+			"explainability":    h.Tiling != nil || h.GradCAM || h.Occlusion != nil,
+			"gradcam":           h.GradCAM && h.Tiling == nil,
+			"occlusion":         h.Occlusion != nil,
+			"async_jobs":        h.Jobs != nil,
+			"compute_footprint": h.Footprint != nil,
+			"batch_predict":     true,
+			"ensemble_review":   h.Ensemble != nil,
+			"ensemble_decision": h.Ensemble != nil && h.Ensemble.Decides(),
+			"ood_guard":         h.OOD != nil,
+			"laterality_flip":   h.DecodeOptions.Laterality == preprocess.LateralityFlip,
+			"tiling":            h.Tiling != nil,
+			"probability_map":   h.Tiling != nil && h.Tiling.MapSize >= 0,
+			// Test-time augmentation (the `tta` parameter) scores whole
+			// images only.
+			"test_time_augmentation": h.Tiling == nil,
+			"stream_push":            true,
+			"stream_pull":            len(h.StreamSources) > 0,
+			"graphql":                true,
+			"prediction_lookup":      h.Store != nil,
+			"feedback":               h.Store != nil,
+			"image_retention":        h.Images != nil,
+			"encrypted_uploads":      h.Decryption != nil,
+			"upload_tokens":          h.UploadTokens != nil,
+			"duplicate_detection":    h.Fingerprints != nil,
+			"offline_mode":           h.Offline != nil,
+			"disclaimers":            h.Disclaimers != nil,
+			"webhooks":               h.Webhooks != nil,
+		},
+		Languages: []string{"en"},
+	}
+	if h.Ensemble != nil {
+		for _, m := range h.Ensemble.Members {
+			caps.Models = append(caps.Models, models.ModelInfo{Name: m.Name, Source: m.Source})
+		}
+	}
+	if h.Messages != nil {
+		caps.Languages = h.Messages.Languages()
+	}
+	respondCachedJSON(c, metadataMaxAge, nil, caps)
+}
@@ -0,0 +1,134 @@
+// backend/internal/handlers/compare.go
+/*
+ * This file contains the model comparison endpoint.
+ *
+ *   POST /api/v1/compare
+ *
+ * scores one study with two loaded models, named by the `model_a` and
+ * `model_b` fields ("active", "standby" or the version of any retained
+ * model, see registry.go), and returns both results side by side with the
+ * score delta, whether the labels agree and, when the deployment offers
+ * explanations, both heatmaps and their difference. Reviewers no longer
+ * need two deployments to see what a new model version changes.
+ *
+ * The heatmaps are obtained by the same method (the `method` field, or
+ * the deployment's default), so their cells line up. Like explanations,
+ * comparisons are not stored or counted as predictions.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/explain"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// Compare scores a study with two loaded models.
+func (h *Handler) Compare(c *gin.Context) {
+	received, err := h.readUpload(c)
+	switch {
+	case errors.Is(err, errImageRequired):
+		h.respondErrorCode(c, http.StatusBadRequest, "image_required", "image file is required")
+		return
+	case errors.Is(err, errUploadTooLarge):
+		h.respondErrorCode(c, http.StatusRequestEntityTooLarge, "upload_too_large", err.Error())
+		return
+	case errors.Is(err, errInvalidUpload):
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_upload", err.Error())
+		return
+	case err != nil:
+		h.respondError(c, http.StatusInternalServerError, "failed to read uploaded file")
+		return
+	}
+
+	var engines [2]Predictor
+	var infos [2]models.ModelInfo
+	for i, field := range []string{"model_a", "model_b"} {
+		id := strings.TrimSpace(c.PostForm(field))
+		if id == "" {
+			h.respondErrorCode(c, http.StatusBadRequest, "model_required", field+" is required")
+			return
+		}
+		if engines[i], infos[i], err = h.loadedModel(id); err != nil {
+			h.respondErrorCode(c, http.StatusNotFound, "model_version_not_loaded", fmt.Sprintf("%s: %v", field, err))
+			return
+		}
+	}
+	topK, err := explain.ParseTopK(c.PostForm("top_k"))
+	if err != nil {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_top_k", err.Error())
+		return
+	}
+	requested, err := explain.ParseMethod(c.PostForm("method"))
+	if err != nil {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_explain_method", err.Error())
+		return
+	}
+	// Without a requested method, a deployment that cannot explain still
+	// compares scores.
+	method, err := h.explainMethod(requested)
+	if err != nil && requested != "" {
+		h.respondErrorCode(c, http.StatusUnprocessableEntity, "explanation_unavailable", err.Error())
+		return
+	}
+	level := explain.Full
+	if err != nil {
+		level = explain.None
+	}
+	img, ok := h.decodeStudy(c, received.image)
+	if !ok {
+		return
+	}
+
+	var sides [2]models.ModelComparison
+	for i := range sides {
+		if sides[i], err = h.compareSide(engines[i], infos[i], img, level, method, topK); err != nil {
+			status, code := classifyError(err)
+			h.respondErrorCode(c, status, code, fmt.Sprintf("comparison failed: %s: %v", infos[i].Name, err))
+			return
+		}
+	}
+	resp := models.CompareResponse{
+		A:           sides[0],
+		B:           sides[1],
+		ScoreDelta:  sides[1].ConfidenceScore - sides[0].ConfidenceScore,
+		LabelsAgree: sides[0].Prediction == sides[1].Prediction,
+	}
+	// A model without gradients falls back to occlusion; maps obtained
+	// differently are not diffed.
+	if a, b := sides[0].Explanation, sides[1].Explanation; a != nil && b != nil && a.Method == b.Method {
+		resp.HeatmapDiff = explain.Diff(a.Heatmap, b.Heatmap)
+	}
+	renderJSON(c, http.StatusOK, resp)
+}
+
+// compareSide scores and, at level, explains img with one model.
+func (h *Handler) compareSide(engine Predictor, info models.ModelInfo, img image.Image, level explain.Level, method string, k int) (models.ModelComparison, error) {
+	score, tiled, err := h.scoreStudy(engine, img)
+	if err != nil {
+		return models.ModelComparison{}, err
+	}
+	threshold := decisionThreshold(info)
+	side := models.ModelComparison{
+		ModelName:       info.Name,
+		ModelVersion:    info.Version,
+		ModelThreshold:  threshold,
+		Prediction:      models.LabelFor(score, threshold),
+		ConfidenceScore: score,
+	}
+	if level != explain.None {
+		side.Explanation, err = h.explain(engine, img, score, tiled, level, method, k)
+	}
+	return side, err
+}
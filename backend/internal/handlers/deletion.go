@@ -0,0 +1,163 @@
+// backend/internal/handlers/deletion.go
+/*
+ * This file contains the prediction deletion handlers.
+ *
+ * Deleting a prediction through the API is a soft delete: the record is
+ * hidden from lookups, listings, fairness monitoring and re-scoring, but
+ * kept for audit and can be brought back:
+ *
+ *   DELETE /api/v1/predictions/:id
+ *   POST   /api/v1/predictions/:id/restore
+ *   GET    /api/v1/predictions?include_deleted=true
+ *
+ * Only an admin can remove a record for good, and only once it has been
+ * soft-deleted and has outlived the retention period:
+ *
+ *   DELETE /admin/predictions/:id
+ *
+ * Predictions under legal hold (see legalhold.go) can be neither deleted
+ * nor purged.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/access"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/store"
+)
+
+// Errors aborting the update of a record being deleted or restored.
+var (
+	// errUnchanged reports a record already in the requested state.
+	errUnchanged = errors.New("prediction unchanged")
+	errHeld      = errors.New("prediction under legal hold")
+)
+
+// DeletePrediction soft-deletes a prediction. Deleting an already deleted
+// prediction is a no-op.
+func (h *Handler) DeletePrediction(c *gin.Context) {
+	// The record is changed in a store update, not read and written back,
+	// so feedback or a restore saved meanwhile is not overwritten. The
+	// tenant and the legal holds are checked on the record being updated.
+	var current models.StoredPrediction
+	var holds []string
+	h.updateDeletion(c, &current, "failed to delete prediction", func(rec *models.StoredPrediction) error {
+		if rec.DeletedAt != nil {
+			return errUnchanged
+		}
+		if holds = h.heldBy(*rec); len(holds) > 0 {
+			return errHeld
+		}
+		now := time.Now().UTC()
+		rec.DeletedAt = &now
+		rec.DeletedBy = actor(c)
+		return nil
+	}, func() { h.respondHeldDeletion(c, current, "delete", holds) })
+}
+
+// RestorePrediction undoes a soft delete.
+func (h *Handler) RestorePrediction(c *gin.Context) {
+	var current models.StoredPrediction
+	h.updateDeletion(c, &current, "failed to restore prediction", func(rec *models.StoredPrediction) error {
+		if rec.DeletedAt == nil {
+			return errUnchanged
+		}
+		rec.DeletedAt, rec.DeletedBy = nil, ""
+		return nil
+	}, nil)
+}
+
+// updateDeletion applies change to the prediction named in the request,
+// if it belongs to the caller's tenant, and responds with the record.
+// current receives the record as read before the change. held responds
+// when change refuses the record because of a legal hold.
+func (h *Handler) updateDeletion(c *gin.Context, current *models.StoredPrediction, failure string, change func(*models.StoredPrediction) error, held func()) {
+	tenant := c.GetHeader(tenantHeader)
+	rec, err := h.Store.Update(c.Request.Context(), c.Param("id"), func(rec *models.StoredPrediction) error {
+		if tenant != "" && rec.Tenant != tenant {
+			return store.ErrNotFound
+		}
+		*current = *rec
+		return change(rec)
+	})
+	switch {
+	case errors.Is(err, errUnchanged):
+		rec = *current
+	case errors.Is(err, errHeld):
+		held()
+		return
+	case errors.Is(err, store.ErrNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: store.ErrNotFound.Error(), Code: "prediction_not_found"})
+		return
+	case err != nil:
+		h.respondStoreError(c, err, failure)
+		return
+	}
+	c.JSON(http.StatusOK, rec)
+}
+
+// PurgePrediction permanently removes a soft-deleted prediction and its
+// retained image. Records still inside the retention period are refused.
+func (h *Handler) PurgePrediction(c *gin.Context) {
+	ctx := c.Request.Context()
+	rec, err := h.Store.Get(ctx, c.Param("id"))
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: err.Error(), Code: "prediction_not_found"})
+		return
+	}
+	if err != nil {
+		h.respondStoreError(c, err, "prediction lookup failed")
+		return
+	}
+
+	if rec.DeletedAt == nil {
+		h.respondErrorCode(c, http.StatusConflict, "prediction_not_deleted",
+			"only soft-deleted predictions can be purged; delete it first")
+		return
+	}
+	if h.refuseHeldDeletion(c, rec, "purge") {
+		return
+	}
+	if until := rec.CreatedAt.Add(h.Retention); time.Now().Before(until) {
+		h.respondErrorCode(c, http.StatusConflict, "retention_period_active",
+			fmt.Sprintf("prediction must be retained until %s", until.Format(time.RFC3339)))
+		return
+	}
+
+	// The image goes first: a failure then leaves the record in place, so
+	// the purge can simply be retried.
+	if h.Images != nil {
+		if err := h.Images.DeleteImage(ctx, rec.PredictionID); err != nil {
+			h.respondError(c, http.StatusInternalServerError, "failed to purge retained image")
+			return
+		}
+	}
+	if err := h.Store.Delete(ctx, rec.PredictionID); err != nil {
+		h.respondStoreError(c, err, "failed to purge prediction")
+		return
+	}
+	slog.InfoContext(ctx, "prediction purged",
+		"prediction_id", rec.PredictionID, "tenant", rec.Tenant, "deleted_by", rec.DeletedBy, "actor", actor(c))
+	c.Status(http.StatusNoContent)
+}
+
+// actor identifies the caller for the audit trail: the authenticated
+// principal when an access policy is in force, otherwise the tenant.
+func actor(c *gin.Context) string {
+	if p, ok := c.Get(principalKey); ok {
+		return p.(*access.Principal).ID
+	}
+	return c.GetHeader(tenantHeader)
+}
@@ -0,0 +1,37 @@
+// backend/internal/handlers/encryption.go
+/*
+ * This file contains the endpoint that publishes tenant encryption keys.
+ *
+ * Clients that encrypt uploads end to end fetch their tenant's public key
+ * from `GET /api/v1/encryption-key` and send the image as a compact JWE
+ * in the usual `image` form field.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/envelope"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// EncryptionKey returns the public key (as a JWK) that the caller's tenant
+// must encrypt uploads to.
+func (h *Handler) EncryptionKey(c *gin.Context) {
+	if h.Decryption == nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "encrypted uploads are not enabled", Code: "encryption_not_supported"})
+		return
+	}
+	jwk, ok := h.Decryption.PublicKey(c.GetHeader(tenantHeader))
+	if !ok {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: envelope.ErrNoKey.Error(), Code: "encryption_key_not_found"})
+		return
+	}
+	c.JSON(http.StatusOK, jwk)
+}
@@ -0,0 +1,102 @@
+// backend/internal/handlers/experiments.go
+/*
+ * This file contains the admin APIs for model experiments.
+ *
+ *   POST /admin/experiments            start an experiment
+ *   GET  /admin/experiments            list experiments
+ *   GET  /admin/experiments/:id        status and, once ended, results
+ *   POST /admin/experiments/:id/stop   end early and tally
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/experiment"
+	"github.com/josephed37/mammoscan-AI/backend/internal/listing"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// StartExperiment launches a time-boxed experiment for a candidate model.
+func (h *Handler) StartExperiment(c *gin.Context) {
+	var def experiment.Definition
+	if err := c.ShouldBindJSON(&def); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error(), Code: "invalid_request"})
+		return
+	}
+	if def.Threshold == 0 {
+		def.Threshold = h.thresholdFor(c.Request.Context(), def.ModelRef, models.ModelInfo{Name: def.ModelName})
+	}
+
+	e, err := h.Experiments.Start(def)
+	switch {
+	case errors.Is(err, experiment.ErrInvalid):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error(), Code: "invalid_request"})
+		return
+	case errors.Is(err, experiment.ErrActive):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: err.Error(), Code: "experiment_active"})
+		return
+	case err != nil:
+		h.respondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusAccepted, e)
+}
+
+// experimentListing declares the sortable and filterable experiment
+// fields.
+var experimentListing = listing.Spec[experiment.Experiment]{
+	Fields: map[string]listing.Field[experiment.Experiment]{
+		"id":         {Kind: listing.String, Get: func(e experiment.Experiment) any { return e.ID }},
+		"name":       {Kind: listing.String, Get: func(e experiment.Experiment) any { return e.Name }},
+		"status":     {Kind: listing.String, Get: func(e experiment.Experiment) any { return string(e.Status) }},
+		"mode":       {Kind: listing.String, Get: func(e experiment.Experiment) any { return e.Mode }},
+		"tenant":     {Kind: listing.String, Get: func(e experiment.Experiment) any { return e.Tenant }},
+		"created_at": {Kind: listing.Time, Get: func(e experiment.Experiment) any { return e.CreatedAt }},
+	},
+	ID:           func(e experiment.Experiment) string { return e.ID },
+	DefaultSort:  "-created_at",
+	DefaultLimit: 50,
+	MaxLimit:     100,
+}
+
+// ListExperiments lists every experiment, newest first, using the shared
+// sort/filter/cursor grammar.
+func (h *Handler) ListExperiments(c *gin.Context) {
+	page, ok := listPage(h, c, h.Experiments.List(), experimentListing)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, withCursor(gin.H{"experiments": page.Items}, page.NextCursor))
+}
+
+// GetExperiment returns one experiment.
+func (h *Handler) GetExperiment(c *gin.Context) {
+	e, err := h.Experiments.Get(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: err.Error(), Code: "experiment_not_found"})
+		return
+	}
+	c.JSON(http.StatusOK, e)
+}
+
+// StopExperiment ends an experiment before it expires and tallies it.
+func (h *Handler) StopExperiment(c *gin.Context) {
+	e, err := h.Experiments.Stop(c.Request.Context(), c.Param("id"))
+	switch {
+	case errors.Is(err, experiment.ErrNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: err.Error(), Code: "experiment_not_found"})
+		return
+	case errors.Is(err, experiment.ErrFinished):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: err.Error(), Code: "experiment_finished"})
+		return
+	}
+	c.JSON(http.StatusOK, e)
+}
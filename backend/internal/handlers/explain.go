@@ -0,0 +1,195 @@
+// backend/internal/handlers/explain.go
+/*
+ * This file contains the explanation endpoint.
+ *
+ *   POST /api/v1/explain
+ *
+ * scores one study like POST /api/v1/predict and returns its full
+ * explanation (heatmap and regions, see internal/explain) with the
+ * heatmap drawn over the study as a PNG. The `method` field picks how the
+ * heatmap is obtained, as `explain_method` does for predictions, and
+ * `top_k` how many regions are returned. Grad-CAM falls back to occlusion
+ * for models whose graph cannot be differentiated.
+ *
+ * The overlay comes base64 encoded in a JSON body, or as the raw PNG with
+ * `?format=png` or `Accept: image/png`; the score and method then travel
+ * in X-MammoScan-* headers.
+ *
+ * An explanation is a view of a study, not a new prediction: it is not
+ * stored, published or counted in the prediction statistics, and always
+ * uses the served model, whatever experiment is running.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/envelope"
+	"github.com/josephed37/mammoscan-AI/backend/internal/explain"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+	"github.com/josephed37/mammoscan-AI/backend/internal/tiling"
+)
+
+const pngContentType = "image/png"
+
+// Explain scores a study and returns its explanation and overlay.
+func (h *Handler) Explain(c *gin.Context) {
+	received, err := h.readUpload(c)
+	switch {
+	case errors.Is(err, errImageRequired):
+		h.respondErrorCode(c, http.StatusBadRequest, "image_required", "image file is required")
+		return
+	case errors.Is(err, errUploadTooLarge):
+		h.respondErrorCode(c, http.StatusRequestEntityTooLarge, "upload_too_large", err.Error())
+		return
+	case errors.Is(err, errInvalidUpload):
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_upload", err.Error())
+		return
+	case err != nil:
+		h.respondError(c, http.StatusInternalServerError, "failed to read uploaded file")
+		return
+	}
+	if h.refuseStaleModel(c) {
+		return
+	}
+
+	// As for predictions, everything the client chose is checked before
+	// any inference is spent.
+	wantPNG := c.Query("format") == "png" || strings.Contains(c.GetHeader("Accept"), pngContentType)
+	if f := c.Query("format"); f != "" && f != "json" && f != "png" {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_format", fmt.Sprintf("format must be json or png, got %q", f))
+		return
+	}
+	topK, err := explain.ParseTopK(c.PostForm("top_k"))
+	if err != nil {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_top_k", err.Error())
+		return
+	}
+	method, err := explain.ParseMethod(c.PostForm("method"))
+	if err != nil {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_explain_method", err.Error())
+		return
+	}
+	if method, err = h.explainMethod(method); err != nil {
+		h.respondErrorCode(c, http.StatusUnprocessableEntity, "explanation_unavailable", err.Error())
+		return
+	}
+	img, ok := h.decodeStudy(c, received.image)
+	if !ok {
+		return
+	}
+
+	engine, served := h.ServedModel()
+	score, tiled, err := h.scoreStudy(engine, img)
+	var explanation *models.Explanation
+	if err == nil {
+		explanation, err = h.explain(engine, img, score, tiled, explain.Full, method, topK)
+	}
+	var overlay bytes.Buffer
+	if err == nil {
+		err = png.Encode(&overlay, explain.Overlay(img, explanation.Heatmap))
+	}
+	if err != nil {
+		status, code := classifyError(err)
+		h.respondErrorCode(c, status, code, fmt.Sprintf("explanation failed: %v", err))
+		return
+	}
+
+	threshold := decisionThreshold(served)
+	prediction := models.LabelFor(score, threshold)
+	if wantPNG {
+		c.Header("X-MammoScan-Prediction", prediction)
+		c.Header("X-MammoScan-Confidence", strconv.FormatFloat(score, 'f', -1, 64))
+		c.Header("X-MammoScan-Explain-Method", explanation.Method)
+		c.Data(http.StatusOK, pngContentType, overlay.Bytes())
+		return
+	}
+	renderJSON(c, http.StatusOK, models.ExplainResponse{
+		Prediction:      prediction,
+		ConfidenceScore: score,
+		ModelName:       served.Name,
+		ModelVersion:    served.Version,
+		ModelThreshold:  threshold,
+		Explanation:     explanation,
+		OverlayPNG:      base64.StdEncoding.EncodeToString(overlay.Bytes()),
+	})
+}
+
+// decodeStudy decrypts and decodes an uploaded study for a request that
+// does not record it, and checks that it looks like a mammogram. On
+// failure the error response has been sent.
+func (h *Handler) decodeStudy(c *gin.Context, data []byte) (image.Image, bool) {
+	img, ok := h.openStudy(c, data)
+	if !ok {
+		return nil, false
+	}
+	if err := h.checkInDistribution(img); err != nil {
+		status, code := classifyError(err)
+		h.respondErrorCode(c, status, code, err.Error())
+		return nil, false
+	}
+	return img, true
+}
+
+// openStudy is decodeStudy without the distribution check.
+func (h *Handler) openStudy(c *gin.Context, data []byte) (image.Image, bool) {
+	var err error
+	opts := h.DecodeOptions
+	opts.LateralityHint, err = preprocess.ParseLaterality(c.PostForm("laterality"))
+	if err != nil {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_laterality", err.Error())
+		return nil, false
+	}
+	if envelope.IsJWE(data) {
+		if h.Decryption == nil {
+			h.respondErrorCode(c, http.StatusUnprocessableEntity, "encryption_not_supported", "encrypted uploads are not enabled")
+			return nil, false
+		}
+		if data, err = h.Decryption.Decrypt(c.GetHeader(tenantHeader), data); err != nil {
+			h.respondErrorCode(c, http.StatusBadRequest, "decryption_failed", err.Error())
+			return nil, false
+		}
+		defer clear(data)
+	}
+	img, err := h.decodeImage(data, opts)
+	if err != nil {
+		status, code := classifyError(err)
+		h.respondErrorCode(c, status, code, err.Error())
+		return nil, false
+	}
+	return img, true
+}
+
+// scoreStudy scores img on engine the way predictions are: patch by
+// patch in tiling mode, whole otherwise.
+func (h *Handler) scoreStudy(engine Predictor, img image.Image) (float64, *models.TiledScore, error) {
+	if h.Tiling != nil {
+		return tiling.Run(img, *h.Tiling, func(patch image.Image) (float64, error) {
+			out, err := h.runModel(engine, preprocess.TensorFor(engine, patch))
+			if err != nil {
+				return 0, err
+			}
+			return float64(out[0]), nil
+		})
+	}
+	out, err := h.runModel(engine, preprocess.TensorFor(engine, img))
+	if err != nil {
+		return 0, nil, err
+	}
+	return float64(out[0]), nil, nil
+}
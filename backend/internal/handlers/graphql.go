@@ -0,0 +1,72 @@
+// backend/internal/handlers/graphql.go
+/*
+ * This file serves the GraphQL facade.
+ *
+ *   POST /api/v1/graphql   predictions, feedback and model
+ *   POST /admin/graphql    the same plus background jobs
+ *
+ * Requests use the standard `{"query", "variables", "operationName"}`
+ * JSON body. Tenant scoping and access control are the same as for the
+ * REST routes.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/josephed37/mammoscan-AI/backend/internal/graph"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// graphQLRequest is the standard GraphQL-over-HTTP request body.
+type graphQLRequest struct {
+	Query         string         `json:"query" binding:"required"`
+	Variables     map[string]any `json:"variables"`
+	OperationName string         `json:"operationName"`
+}
+
+// GraphQL returns a handler serving the GraphQL schema. The admin variant
+// also exposes background jobs. The schema is built once, when the route
+// is registered.
+func (h *Handler) GraphQL(admin bool) gin.HandlerFunc {
+	deps := graph.Deps{
+		Find:           h.Store.Find,
+		Get:            h.findPrediction,
+		SubmitFeedback: h.recordFeedback,
+		Model: func() models.ModelInfo {
+			_, served := h.ServedModel()
+			return served
+		},
+	}
+	if admin {
+		deps.Jobs = h.Jobs
+	}
+	schema, err := graph.NewSchema(deps)
+	if err != nil {
+		log.Fatalf("GraphQL schema: %v", err)
+	}
+
+	return func(c *gin.Context) {
+		var req graphQLRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error(), Code: "invalid_request"})
+			return
+		}
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  req.Query,
+			VariableValues: req.Variables,
+			OperationName:  req.OperationName,
+			Context:        graph.WithTenant(c.Request.Context(), c.GetHeader(tenantHeader)),
+		})
+		c.JSON(http.StatusOK, result)
+	}
+}
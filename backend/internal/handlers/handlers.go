@@ -14,98 +14,977 @@
 package handlers
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"image"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/josephed37/mammoscan-AI/backend/internal/access"
+	"github.com/josephed37/mammoscan-AI/backend/internal/billing"
+	"github.com/josephed37/mammoscan-AI/backend/internal/chaos"
+	"github.com/josephed37/mammoscan-AI/backend/internal/dependency"
+	"github.com/josephed37/mammoscan-AI/backend/internal/disclaimer"
+	"github.com/josephed37/mammoscan-AI/backend/internal/ensemble"
+	"github.com/josephed37/mammoscan-AI/backend/internal/envelope"
+	"github.com/josephed37/mammoscan-AI/backend/internal/events"
+	"github.com/josephed37/mammoscan-AI/backend/internal/experiment"
+	"github.com/josephed37/mammoscan-AI/backend/internal/explain"
+	"github.com/josephed37/mammoscan-AI/backend/internal/fairness"
+	"github.com/josephed37/mammoscan-AI/backend/internal/fingerprint"
+	"github.com/josephed37/mammoscan-AI/backend/internal/footprint"
+	"github.com/josephed37/mammoscan-AI/backend/internal/i18n"
 	"github.com/josephed37/mammoscan-AI/backend/internal/inference"
+	"github.com/josephed37/mammoscan-AI/backend/internal/jobs"
+	"github.com/josephed37/mammoscan-AI/backend/internal/journal"
+	"github.com/josephed37/mammoscan-AI/backend/internal/legalhold"
+	"github.com/josephed37/mammoscan-AI/backend/internal/listing"
+	"github.com/josephed37/mammoscan-AI/backend/internal/lockout"
 	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/modelswap"
+	"github.com/josephed37/mammoscan-AI/backend/internal/netacl"
+	"github.com/josephed37/mammoscan-AI/backend/internal/ood"
 	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+	"github.com/josephed37/mammoscan-AI/backend/internal/reports"
+	"github.com/josephed37/mammoscan-AI/backend/internal/residency"
+	"github.com/josephed37/mammoscan-AI/backend/internal/selfcheck"
+	"github.com/josephed37/mammoscan-AI/backend/internal/signing"
+	"github.com/josephed37/mammoscan-AI/backend/internal/spool"
+	"github.com/josephed37/mammoscan-AI/backend/internal/stats"
+	"github.com/josephed37/mammoscan-AI/backend/internal/store"
+	"github.com/josephed37/mammoscan-AI/backend/internal/tenantkey"
+	"github.com/josephed37/mammoscan-AI/backend/internal/tiling"
+	"github.com/josephed37/mammoscan-AI/backend/internal/tta"
+	"github.com/josephed37/mammoscan-AI/backend/internal/uploadtoken"
+	"github.com/josephed37/mammoscan-AI/backend/internal/webhook"
+	"gorgonia.org/tensor"
 )
 
+// Predictor scores a preprocessed image. *inference.ONNXInference is the
+// production implementation; tests substitute a fake.
+type Predictor interface {
+	Predict(input tensor.Tensor) ([]float32, error)
+}
+
 // Handler is a struct that holds dependencies for our API handlers,
 // such as the inference engine. This is a form of dependency injection,
 // which makes our code modular and easier to test.
 type Handler struct {
-	InferenceEngine *inference.ONNXInference
+	// InferenceEngine and Model are set at startup; once the server runs,
+	// read them with ServedModel and replace them with SwapModel.
+	InferenceEngine Predictor
+	modelMu         sync.RWMutex
+	standby         *modelSlot
+	// revertible is set when standby holds the model replaced by the
+	// last switch or swap.
+	revertible bool
+	// retained holds earlier models pushed out of standby, most recent
+	// first (see registry.go).
+	retained []*modelSlot
+
+	// BuildProfile names the build the service was compiled as.
+	BuildProfile string
+
+	// SelfCheck is the report of the startup self-check.
+	SelfCheck *selfcheck.Report
+
+	// readiness is what /ready reports (see ready.go).
+	readiness atomic.Int32
+
+	// Faults injects failures for resilience testing. Only binaries built
+	// with the chaos tag ever set it.
+	Faults *chaos.Injector
+
+	// Model describes the model currently served by InferenceEngine.
+	Model models.ModelInfo
+
+	// OOD, when set, refuses inputs unlike the training mammograms.
+	OOD *ood.Profile
+
+	// Ensemble, when set, scores every study with additional models and
+	// flags those the models disagree on.
+	Ensemble *ensemble.Ensemble
+
+	// Tiling, when set, scores studies patch by patch at full resolution
+	// instead of as one downscaled image. Stream frames are not tiled.
+	Tiling *tiling.Config
+
+	// TTA configures test-time augmentation, run for requests with
+	// `tta=true` (or every request when TTA.Default is set).
+	TTA tta.Config
+
+	// Footprint, when set, estimates the energy use and emissions of
+	// every prediction from its compute time.
+	Footprint *footprint.Estimator
+
+	// Occlusion, when set, offers occlusion explanations, which work
+	// without tiling at the cost of one inference per map cell.
+	Occlusion *explain.Occlusion
+
+	// GradCAM offers Grad-CAM explanations from engines that implement
+	// GradientExplainer. Studies whose engine cannot provide gradients
+	// fall back to occlusion, when that is enabled.
+	GradCAM bool
+
+	// Experiments runs time-boxed A/B tests of candidate models.
+	Experiments *experiment.Manager
+
+	// DecodeOptions controls how uploaded images are decoded.
+	DecodeOptions preprocess.Options
+	// Validation refuses uploads unfit for scoring around decoding (see
+	// preprocess/validate.go).
+	Validation preprocess.Validation
+	// DebugTensors logs the decoded study and input tensor statistics of
+	// every prediction (see tensorstats.go).
+	DebugTensors bool
+
+	// Stats aggregates request outcomes for the admin dashboard.
+	Stats *stats.Collector
+
+	// Store records every prediction for later lookup.
+	Store store.Store
+	// Images, when set, retains the original upload of every prediction.
+	Images store.ImageStore
+	// TenantKeys, when set, is the keyring the history, images, journal
+	// and offline spool are sealed with. Predictions of a tenant without a
+	// key are refused, since they could not be kept.
+	TenantKeys *tenantkey.Keyring
+	// Retention is how long a prediction must be kept after it was made;
+	// younger records can be soft-deleted but not purged.
+	Retention time.Duration
+	// LegalHolds, when set, exempts held predictions from deletion and
+	// enables the legal hold admin API.
+	LegalHolds *legalhold.Registry
+
+	// Fairness controls subgroup disparity detection.
+	Fairness fairness.Config
+
+	// Jobs runs long-running admin work in the background.
+	Jobs *jobs.Manager
+	// PredictionJobs, when set, runs asynchronous prediction jobs on its
+	// own workers.
+	PredictionJobs *jobs.Manager
+	// LoadEngine loads an additional model by reference (local path or
+	// remote URI), e.g. a candidate model for re-scoring.
+	LoadEngine func(ctx context.Context, ref string) (Predictor, error)
+	// ArtifactTime, when set, returns when the model artifact at ref was
+	// produced; LoadModel records it as the model's BuiltAt.
+	ArtifactTime func(ctx context.Context, ref string) (time.Time, error)
+	// ModelThreshold, when set, resolves the decision threshold of the
+	// model at ref; LoadModel records it as the model's Threshold. Without
+	// it every model uses DefaultThreshold.
+	ModelThreshold func(ctx context.Context, ref string, info models.ModelInfo) float64
+	// Batcher, when set, coalesces concurrent predictions on the served
+	// model into batches.
+	Batcher *inference.Batcher
+	// ModelMemory, when set, tracks the memory of the loaded models
+	// against the configured budget.
+	ModelMemory *inference.MemoryBudget
+	// ModelSwaps, when set, swaps in new versions of the served model
+	// during the configured windows.
+	ModelSwaps *modelswap.Scheduler
+	// RetainModels is how many versions replaced in standby stay loaded
+	// for instant activation; 0 keeps only the standby.
+	RetainModels int
+	// MaxModelAge, when set, flags a served model older than the limit
+	// (see staleness.go).
+	MaxModelAge ModelAgeLimit
+	// staleAlerted identifies the last model a ModelStale event was
+	// published for.
+	staleMu      sync.Mutex
+	staleAlerted string
+
+	// Offline, when set, receives a durable copy of every prediction so it
+	// can be forwarded to the central service once connectivity returns.
+	Offline *spool.Queue
+	// OfflineStoreImages controls whether the source image is queued too.
+	OfflineStoreImages bool
+
+	// Journal, when set, records every prediction request until it has
+	// been answered, so studies lost to an outage can be recovered.
+	Journal *journal.Journal
+
+	// Fingerprints, when set, flags studies that were already submitted.
+	Fingerprints *fingerprint.Index
+
+	// Events, when set, receives an event for every completed prediction
+	// and scored stream frame; billing and webhooks subscribe to it.
+	Events *events.Bus
+
+	// Webhooks, when set, enables the tenant webhook management API.
+	Webhooks *webhook.Dispatcher
+
+	// Dependencies are the downstream services reported by /healthz.
+	Dependencies *dependency.Set
+
+	// Billing is the billing emitter, when enabled. It subscribes to
+	// Events; the handler only reports its backlog.
+	Billing *billing.Emitter
+
+	// Reports, when set, compiles the scheduled daily/weekly summaries.
+	Reports *reports.Scheduler
+
+	// Disclaimers supplies the per-tenant regulatory text added to results.
+	Disclaimers *disclaimer.Catalog
+
+	// Uploads bounds the size of uploaded studies and when they are
+	// spooled to disk; zero values select the defaults.
+	Uploads UploadLimits
+
+	// Residency, when set, refuses tenants pinned to another region.
+	Residency *residency.Policy
+
+	// Network, when set, restricts endpoint groups by client address.
+	Network *netacl.Engine
+	// Access, when set, authenticates callers and enforces the RBAC policy.
+	Access *access.Engine
+	// JWT, when set, also authenticates callers by identity provider
+	// tokens; their roles are authorized by Access.
+	JWT *access.JWTVerifier
+	// RequestSigning, when set, verifies the signatures of requests made
+	// with the API keys SigningSecret returns a secret for.
+	RequestSigning *signing.Verifier
+	SigningSecret  func(keyID string) (secret string, ok bool)
+	// Lockouts, when set, locks out client addresses and credentials
+	// after repeated authentication failures (see lockout.go).
+	Lockouts *lockout.Tracker
+	// Audit, when set, writes an event to the audit log and reports
+	// whether it was written; break-glass access is only granted once
+	// recorded there.
+	Audit func(events.Event) error
+	// UploadTokens mints the single-use tokens used by browser uploads.
+	UploadTokens *uploadtoken.Issuer
+
+	// Decryption, when set, opens client-side encrypted (JWE) uploads.
+	Decryption *envelope.Keyring
+
+	// Messages, when set, localizes error messages per Accept-Language.
+	Messages *i18n.Catalog
+
+	// StreamSources lists the URL prefixes the stream endpoint may pull
+	// from; when empty only pushed streams are accepted.
+	StreamSources []string
+	// StreamMaxDuration bounds a single stream session.
+	StreamMaxDuration time.Duration
 }
 
+// DefaultThreshold is the decision threshold chosen during our
+// precision/recall analysis of the champion model. It applies to models
+// with no configured threshold.
+const DefaultThreshold = 0.110593
+
+// tenantHeader carries the submitting organisation's identifier.
+const tenantHeader = "X-Tenant-ID"
+
 // NewHandler is a constructor function that creates a new Handler
 // with its required dependencies.
-func NewHandler(inferenceEngine *inference.ONNXInference) *Handler {
+func NewHandler(inferenceEngine Predictor) *Handler {
 	return &Handler{
 		InferenceEngine: inferenceEngine,
+		Model:           models.ModelInfo{Name: "baseline_cnn_v2"},
+		DecodeOptions:   preprocess.DefaultOptions(),
+		Validation:      preprocess.DefaultValidation(),
+		TTA:             tta.DefaultConfig(),
+		Stats:           stats.New(stats.Config{}),
 	}
 }
 
 // HealthCheck is a simple handler that returns a 200 OK status.
 // It's used by monitoring systems to verify that the service is alive and running.
+// A service serving a stale model is still alive but reports DEGRADED.
 func (h *Handler) HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"status": "OK"})
+	if age := h.ModelAge(time.Now()); age != nil && age.Stale {
+		c.JSON(http.StatusOK, models.HealthStatus{Status: "DEGRADED", Warnings: []string{staleWarning(age)}})
+		return
+	}
+	c.Data(http.StatusOK, jsonContentType, healthyBody)
 }
 
 // Predict is the core handler for our application. It orchestrates the
 // entire process of receiving an image, preprocessing it, running inference,
 // and returning a structured JSON response.
 func (h *Handler) Predict(c *gin.Context) {
+	requestStart := time.Now()
+
+	// A study whose record could not be sealed is refused up front rather
+	// than scored and then left out of the history.
+	if tenant := c.GetHeader(tenantHeader); h.TenantKeys != nil && !h.TenantKeys.Has(tenant) {
+		h.respondErrorCode(c, http.StatusForbidden, "tenant_key_missing", fmt.Sprintf("no encryption key is configured for tenant %q", tenant))
+		return
+	}
+
 	// --- 1. Receive and Validate the Image Upload ---
-	// c.FormFile retrieves the uploaded file from the "image" field of the multipart form.
-	fileHeader, err := c.FormFile("image")
-	if err != nil {
+	// The multipart body is parsed as a stream (see upload.go) so large
+	// studies are size-checked as they arrive and buffered only once. JSON
+	// bodies with a base64 image are accepted too (see jsonupload.go).
+	received, err := h.readUpload(c)
+	switch {
+	case errors.Is(err, errImageRequired):
 		// If no file is found, return a 400 Bad Request error.
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "image file is required"})
+		h.respondErrorCode(c, http.StatusBadRequest, "image_required", "image file is required")
+		return
+	case errors.Is(err, errUploadTooLarge):
+		h.respondErrorCode(c, http.StatusRequestEntityTooLarge, "upload_too_large", err.Error())
+		return
+	case errors.Is(err, errInvalidUpload):
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_upload", err.Error())
+		return
+	case err != nil:
+		h.respondError(c, http.StatusInternalServerError, "failed to read uploaded file")
+		return
+	}
+	imageData := received.image
+	// With the journal enabled the request is recorded before any work is
+	// done, and removed once answered (see journal.go).
+	if finish := h.journalRequest(c, received); finish != nil {
+		defer finish()
+	}
+	if h.refuseStaleModel(c) {
 		return
 	}
 
-	// Open the file to get an io.Reader, which allows us to process the file's contents.
-	file, err := fileHeader.Open()
+	// Optional correlation fields are validated up front so a bad value is
+	// rejected before we spend time on inference.
+	clientRef, accession, err := correlationFields(c)
+	if err != nil {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_correlation_field", err.Error())
+		return
+	}
+	// A known laterality (L/R) spares the flip normalisation a guess.
+	decodeOptions := h.DecodeOptions
+	decodeOptions.LateralityHint, err = preprocess.ParseLaterality(c.PostForm("laterality"))
+	if err != nil {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_laterality", err.Error())
+		return
+	}
+	// The explanation level is checked before inference too: a client
+	// asking for a heatmap we cannot produce should not wait for a score.
+	explainLevel, err := explain.ParseLevel(c.PostForm("explain"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "failed to open uploaded file"})
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_explain", err.Error())
+		return
+	}
+	topK, err := explain.ParseTopK(c.PostForm("top_k"))
+	if err != nil {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_top_k", err.Error())
+		return
+	}
+	explainMethod, err := explain.ParseMethod(c.PostForm("explain_method"))
+	if err != nil {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_explain_method", err.Error())
+		return
+	}
+	if explainLevel != explain.None {
+		if explainMethod, err = h.explainMethod(explainMethod); err != nil {
+			h.respondErrorCode(c, http.StatusUnprocessableEntity, "explanation_unavailable", err.Error())
+			return
+		}
+	}
+	useTTA, err := h.ttaRequested(c)
+	if err != nil {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_tta", err.Error())
+		return
+	}
+	if useTTA && h.Tiling != nil {
+		h.respondErrorCode(c, http.StatusUnprocessableEntity, "tta_unavailable", "test-time augmentation is not available in tiling mode")
 		return
 	}
-	// We use defer to ensure the file is closed when the function exits.
-	defer file.Close()
+
+	// Client-side encrypted uploads are opened in memory only. The
+	// plaintext is never written anywhere, so such studies are excluded
+	// from image retention and offline image spooling below.
+	encrypted := envelope.IsJWE(imageData)
+	if encrypted {
+		if h.Decryption == nil {
+			h.respondErrorCode(c, http.StatusUnprocessableEntity, "encryption_not_supported", "encrypted uploads are not enabled")
+			return
+		}
+		imageData, err = h.Decryption.Decrypt(c.GetHeader(tenantHeader), imageData)
+		if err != nil {
+			h.respondErrorCode(c, http.StatusBadRequest, "decryption_failed", err.Error())
+			return
+		}
+		defer clear(imageData)
+	}
+
+	// The digest identifies the study in the prediction history.
+	imageSum := sha256.Sum256(imageData)
+	imageDigest := hex.EncodeToString(imageSum[:])
 
 	// --- 2. Preprocess the Image ---
 	// We pass the file to our preprocessing pipeline, which decodes, resizes,
 	// and converts the image into the tensor format our model expects.
-	inputTensor, err := preprocess.PreprocessImage(file)
+	img, err := h.decodeImage(imageData, decodeOptions)
+	if err == nil {
+		err = h.Faults.Decode()
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("failed to preprocess image: %v", err)})
+		status, code := classifyError(err)
+		h.respondErrorCode(c, status, code, fmt.Sprintf("failed to preprocess image: %v", err))
+		return
+	}
+	// Inputs that do not look like mammograms are refused: a confident
+	// score for a chest X-ray or a photo is worse than no score.
+	if err := h.checkInDistribution(img); err != nil {
+		status, code := classifyError(err)
+		h.respondErrorCode(c, status, code, err.Error())
 		return
 	}
 
 	// --- 3. Run Inference ---
 	// The preprocessed tensor is passed to our ONNX model's predict method.
-	prediction, err := h.InferenceEngine.Predict(inputTensor)
+	// A study assigned to the candidate arm of a running experiment is
+	// scored by the candidate model instead. The tensor is built the way
+	// the scoring model was trained (see preprocess/profile.go).
+	predictionID := newPredictionID()
+	engine, served := h.ServedModel()
+	modelName, modelVersion, modelThreshold := served.Name, served.Version, decisionThreshold(served)
+	assignment, inExperiment := h.Experiments.Assign(predictionID, c.GetHeader(tenantHeader))
+	if inExperiment && assignment.Arm == experiment.ArmCandidate {
+		engine, modelName, modelVersion, modelThreshold = assignment.Engine, assignment.ModelName, "", assignment.Threshold
+	}
+	inputTensor := preprocess.TensorFor(engine, img)
+	if h.DebugTensors {
+		logTensorStats(c.Request.Context(), predictionID, img, inputTensor, preprocess.ProfileOf(engine))
+	}
+	inferenceStart := time.Now()
+	var confidenceScore float64
+	var tiled *models.TiledScore
+	var augmented *models.TTAScore
+	err = h.Faults.Inference(c.Request.Context())
+	switch {
+	case err != nil:
+		// An injected fault, reported like a real one below.
+	case h.Tiling != nil:
+		// In tiling mode the model sees full-resolution patches and the
+		// study score is their aggregate.
+		confidenceScore, tiled, err = tiling.Run(img, *h.Tiling, func(patch image.Image) (float64, error) {
+			out, err := h.runModel(engine, preprocess.TensorFor(engine, patch))
+			if err != nil {
+				return 0, err
+			}
+			return float64(out[0]), nil
+		})
+	case useTTA:
+		// Every augmented copy is scored on its own and the study score
+		// is their mean (see tta/tta.go).
+		confidenceScore, augmented, err = tta.Run(img, h.TTA.Augmentations, func(variant image.Image) (float64, error) {
+			out, err := h.runModel(engine, preprocess.TensorFor(engine, variant))
+			if err != nil {
+				return 0, err
+			}
+			return float64(out[0]), nil
+		})
+	default:
+		var prediction []float32
+		prediction, err = h.runModel(engine, inputTensor)
+		if err == nil {
+			// The model returns a slice of probabilities, but since we have
+			// one output, we only need the first value.
+			confidenceScore = float64(prediction[0])
+		}
+	}
+	var explanation *models.Explanation
+	if err == nil && explainLevel != explain.None {
+		explanation, err = h.explain(engine, img, unaugmentedScore(confidenceScore, augmented), tiled, explainLevel, explainMethod, topK)
+	}
+	computeTime := time.Since(inferenceStart)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: fmt.Sprintf("prediction failed: %v", err)})
+		status, code := classifyError(err)
+		h.respondErrorCode(c, status, code, fmt.Sprintf("prediction failed: %v", err))
 		return
 	}
 
-	// The model returns a slice of probabilities, but since we have one output,
-	// we only need the first value.
-	confidenceScore := float64(prediction[0])
-
 	// --- 4. Apply Threshold and Format the Response ---
-	// This is where we apply the optimal decision threshold we found during our analysis.
-	const modelThreshold = 0.110593
-	var finalPrediction string
-
-	if confidenceScore > modelThreshold {
-		finalPrediction = "Cancer"
-	} else {
-		finalPrediction = "Non-Cancer"
-	}
+	// This is where we apply the decision threshold configured for the
+	// model that scored the study.
+	finalPrediction := models.LabelFor(confidenceScore, modelThreshold)
 
 	// We populate our response struct with the final results.
 	response := models.PredictionResponse{
+		PredictionID:    predictionID,
 		Prediction:      finalPrediction,
 		ConfidenceScore: confidenceScore,
-		ModelName:       "baseline_cnn_v2",
+		ModelName:       modelName,
+		ModelVersion:    modelVersion,
 		ModelThreshold:  modelThreshold,
+		ClientReference: clientRef,
+		AccessionNumber: accession,
+		Disclaimer:      h.Disclaimers.For(c.GetHeader(tenantHeader)),
+		Tiling:          tiled,
+		TTA:             augmented,
+		Explanation:     explanation,
+	}
+
+	// --- 5. Compare With the Ensemble ---
+	// Unless the ensemble combines its models' scores, the served model's
+	// label stands and the other models only decide whether the study
+	// should also be looked at by a human.
+	if h.Ensemble != nil {
+		served := models.ModelScore{ModelName: modelName, ConfidenceScore: confidenceScore, Prediction: finalPrediction}
+		result, err := h.Ensemble.Evaluate(img, served)
+		if err != nil {
+			// Without every opinion we cannot vouch for agreement, nor
+			// combine them: the served model's label stands.
+			slog.WarnContext(c.Request.Context(), "ensemble scoring failed", "prediction_id", response.PredictionID, "error", err)
+			response.NeedsReview = true
+		} else {
+			response.Ensemble = result.Scores
+			response.Disagreement = &result.Disagreement
+			response.NeedsReview = result.NeedsReview
+			if d := result.Decision; d != nil {
+				response.EnsembleDecision = d
+				response.Prediction, response.ConfidenceScore, response.ModelThreshold = d.Prediction, d.ConfidenceScore, d.Threshold
+			}
+		}
+		computeTime = time.Since(inferenceStart)
+	}
+
+	// The cost of the prediction, including the ensemble, is reported
+	// when the deployment estimates energy use.
+	cost := h.Footprint.Estimate(computeTime)
+	if h.Footprint != nil {
+		response.Compute = &cost
+	}
+
+	// --- 6. Flag Duplicate Submissions ---
+	// A perceptual hash of the image tells us whether this study was
+	// already scored, so statistics can exclude resubmissions.
+	if h.Fingerprints != nil {
+		response.DuplicateOf, response.Duplicate = h.checkDuplicate(c.Request.Context(), img, response.PredictionID, c.GetHeader(tenantHeader))
+	}
+
+	// --- 7. Record the Prediction ---
+	// History is best-effort: a storage failure is logged but never turns
+	// a successful clinical result into an error.
+	if h.Store != nil {
+		rec := models.StoredPrediction{
+			PredictionResponse: response,
+			CreatedAt:          time.Now().UTC(),
+			Tenant:             c.GetHeader(tenantHeader),
+			ImageSHA256:        imageDigest,
+			Subgroups:          subgroupFields(c),
+		}
+		if inExperiment {
+			rec.Experiment, rec.ExperimentArm = assignment.ExperimentID, assignment.Arm
+		}
+		if err := h.putPrediction(c.Request.Context(), rec); err != nil {
+			slog.ErrorContext(c.Request.Context(), "prediction store: save failed", "prediction_id", response.PredictionID, "error", err)
+		}
+	}
+	if h.Images != nil && !encrypted {
+		if err := h.Images.PutImage(c.Request.Context(), c.GetHeader(tenantHeader), response.PredictionID, imageData); err != nil {
+			slog.ErrorContext(c.Request.Context(), "image store: save failed", "prediction_id", response.PredictionID, "error", err)
+		}
+	}
+	// A shadow candidate scores the study once its served result is on
+	// record (see shadow.go).
+	if inExperiment && assignment.Shadow {
+		h.startShadow(c.Request.Context(), assignment, img, response)
 	}
 
+	// --- 8. Publish the Prediction ---
+	// Billing, webhooks and other sinks subscribe to the event bus.
+	h.Events.PublishContext(c.Request.Context(), events.Event{
+		Type:   events.PredictionCompleted,
+		Tenant: c.GetHeader(tenantHeader),
+		Data: events.Prediction{
+			Response:    response,
+			StudyID:     c.DefaultPostForm("study_id", response.PredictionID),
+			ComputeTime: computeTime,
+		},
+	})
+
+	// --- 9. Queue for Offline Sync ---
+	// In store-and-forward mode the result is persisted locally before we
+	// answer, so a power loss cannot drop a study that was already reported.
+	if h.Offline != nil {
+		var blob []byte
+		if h.OfflineStoreImages && !encrypted {
+			blob = imageData
+		}
+		h.queueOffline(c.Request.Context(), c.GetHeader(tenantHeader), response, received.filename, blob)
+	}
+
+	// Drift is tracked on the scoring model's own output, whoever decided.
+	h.Stats.RecordPrediction(confidenceScore, finalPrediction == models.LabelCancer, time.Since(requestStart))
+	h.Stats.RecordCompute(computeTime, cost.EnergyWh, cost.CarbonGrams)
+
 	// Finally, we send the structured JSON response back to the client with a 200 OK status.
-	c.JSON(http.StatusOK, response)
+	recordOutcome(c, response)
+	renderJSON(c, http.StatusOK, response)
+}
+
+// GradientExplainer is implemented by engines that can compute Grad-CAM
+// maps, indexed [row][column] over the model input.
+type GradientExplainer interface {
+	GradCAM(input tensor.Tensor) ([][]float64, error)
+}
+
+// explainMethod resolves the requested explanation method against what
+// the deployment offers; an empty request prefers patch scores, which
+// come for free with tiling, then Grad-CAM, which costs one pass.
+func (h *Handler) explainMethod(requested string) (string, error) {
+	switch {
+	case requested == explain.MethodPatchScores && h.Tiling == nil:
+		return "", errors.New("patch score explanations require tiling mode")
+	case requested == explain.MethodGradCAM && !h.GradCAM:
+		return "", errors.New("Grad-CAM explanations are not enabled")
+	case requested == explain.MethodGradCAM && h.Tiling != nil:
+		// The study score aggregates patches; a map of the downscaled
+		// study would not explain it.
+		return "", errors.New("Grad-CAM explanations are not available in tiling mode")
+	case requested == explain.MethodOcclusion && h.Occlusion == nil:
+		return "", errors.New("occlusion explanations are not enabled")
+	case requested != "":
+		return requested, nil
+	case h.Tiling != nil:
+		return explain.MethodPatchScores, nil
+	case h.GradCAM:
+		return explain.MethodGradCAM, nil
+	case h.Occlusion != nil:
+		return explain.MethodOcclusion, nil
+	}
+	return "", errors.New("explanations require tiling mode, Grad-CAM or occlusion")
+}
+
+// explain builds the explanation of a study img that engine scored at
+// score, using method.
+func (h *Handler) explain(engine Predictor, img image.Image, score float64, tiled *models.TiledScore, level explain.Level, method string, k int) (*models.Explanation, error) {
+	b := img.Bounds()
+	switch method {
+	case explain.MethodPatchScores:
+		return explain.FromTiles(level, tiled, k), nil
+	case explain.MethodGradCAM:
+		err := fmt.Errorf("%w: engine does not support Grad-CAM", inference.ErrNoGradients)
+		if g, ok := engine.(GradientExplainer); ok {
+			var cam [][]float64
+			if cam, err = g.GradCAM(preprocess.TensorFor(engine, img)); err == nil {
+				m := explain.FromActivations(cam, b.Dx(), b.Dy())
+				return explain.FromMap(level, method, m, b.Dx(), b.Dy(), k), nil
+			}
+		}
+		// A model without gradients is still explained, more slowly.
+		if !errors.Is(err, inference.ErrNoGradients) || h.Occlusion == nil {
+			return nil, err
+		}
+		method = explain.MethodOcclusion
+	}
+	// Masked images are scored the way the study was, tiled or whole.
+	m, err := h.Occlusion.Occlude(img, score, func(masked image.Image) (float64, error) {
+		if h.Tiling != nil {
+			s, _, err := tiling.Run(masked, *h.Tiling, func(patch image.Image) (float64, error) {
+				out, err := h.runModel(engine, preprocess.TensorFor(engine, patch))
+				if err != nil {
+					return 0, err
+				}
+				return float64(out[0]), nil
+			})
+			return s, err
+		}
+		out, err := h.runModel(engine, preprocess.TensorFor(engine, masked))
+		if err != nil {
+			return 0, err
+		}
+		return float64(out[0]), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return explain.FromMap(level, method, m, b.Dx(), b.Dy(), k), nil
+}
+
+// ttaRequested reports whether the request asks for test-time
+// augmentation with the `tta` query or form parameter, falling back to
+// the configured default.
+func (h *Handler) ttaRequested(c *gin.Context) (bool, error) {
+	v := c.Query("tta")
+	if v == "" {
+		v = c.PostForm("tta")
+	}
+	if v == "" {
+		return h.TTA.Default, nil
+	}
+	on, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("tta must be true or false, got %q", v)
+	}
+	return on, nil
+}
+
+// unaugmentedScore is the score occlusion explanations compare masked,
+// unaugmented images against: the identity copy's when the study was
+// scored with TTA and one was configured, the study score otherwise.
+func unaugmentedScore(score float64, augmented *models.TTAScore) float64 {
+	if augmented != nil {
+		for _, s := range augmented.Scores {
+			if s.Augmentation == "identity" {
+				return s.ConfidenceScore
+			}
+		}
+	}
+	return score
+}
+
+// runModel scores one input on engine, as part of a batch when batching
+// is enabled and the engine supports it.
+func (h *Handler) runModel(engine Predictor, input tensor.Tensor) ([]float32, error) {
+	if b, ok := engine.(inference.BatchPredictor); ok && h.Batcher != nil {
+		return h.Batcher.Predict(b, input)
+	}
+	return engine.Predict(input)
+}
+
+// EnforceResidency is a middleware that refuses requests from tenants whose
+// data must stay in a region other than the one this deployment runs in.
+func (h *Handler) EnforceResidency(c *gin.Context) {
+	if err := h.Residency.CheckTenant(c.GetHeader(tenantHeader)); err != nil {
+		h.Stats.RecordError(http.StatusForbidden, err.Error())
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{Error: err.Error(), Code: "residency_violation"})
+		return
+	}
+	c.Next()
+}
+
+// respondError writes a standard error response for an unexpected
+// failure and records it so it shows up in the admin overview.
+func (h *Handler) respondError(c *gin.Context, status int, message string) {
+	h.respondErrorCode(c, status, "internal_error", message)
+}
+
+// respondErrorCode is respondError with a machine-readable error code.
+func (h *Handler) respondErrorCode(c *gin.Context, status int, code, message string) {
+	h.Stats.RecordError(status, message)
+	c.JSON(status, models.ErrorResponse{Error: message, Code: code})
+}
+
+// listPage returns the page of items the list parameters of c ask for
+// (see the listing package). A malformed request is answered with a 400
+// and ok is false.
+func listPage[T any](h *Handler, c *gin.Context, items []T, spec listing.Spec[T]) (page listing.Page[T], ok bool) {
+	query, err := listing.Parse(c.Request.URL.Query(), spec)
+	if err == nil {
+		page, err = listing.Apply(items, query, spec)
+	}
+	if err != nil {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_list_parameters", err.Error())
+		return page, false
+	}
+	return page, true
+}
+
+// withCursor adds the cursor of the next page, if any, to a list body.
+func withCursor(body gin.H, next string) gin.H {
+	if next != "" {
+		body["next_cursor"] = next
+	}
+	return body
+}
+
+// errOutOfDistribution marks inputs refused by the OOD guard.
+var errOutOfDistribution = errors.New("image does not look like a mammogram")
+
+// classifyError maps the sentinel errors of the preprocessing and
+// inference packages to an HTTP status and stable error code. Anything
+// else is an internal error.
+func classifyError(err error) (int, string) {
+	switch {
+	case errors.Is(err, preprocess.ErrUnsupportedFormat):
+		return http.StatusUnsupportedMediaType, "unsupported_format"
+	case errors.Is(err, preprocess.ErrMultiFrame):
+		return http.StatusUnprocessableEntity, "multi_frame_input"
+	case errors.Is(err, preprocess.ErrFormatNotAllowed):
+		return http.StatusUnsupportedMediaType, "format_not_allowed"
+	case errors.Is(err, preprocess.ErrDecode):
+		return http.StatusUnprocessableEntity, "invalid_image"
+	case errors.Is(err, preprocess.ErrResolutionTooLow):
+		return http.StatusUnprocessableEntity, "resolution_too_low"
+	case errors.Is(err, preprocess.ErrBlankImage):
+		return http.StatusUnprocessableEntity, "blank_image"
+	case errors.Is(err, preprocess.ErrLowContrast):
+		return http.StatusUnprocessableEntity, "low_contrast"
+	case errors.Is(err, errOutOfDistribution):
+		return http.StatusUnprocessableEntity, "out_of_distribution"
+	case errors.Is(err, tiling.ErrTooManyPatches):
+		return http.StatusUnprocessableEntity, "image_too_large"
+	case errors.Is(err, inference.ErrModelIncompatible):
+		return http.StatusInternalServerError, "model_incompatible"
+	case errors.Is(err, inference.ErrSaturated):
+		return http.StatusServiceUnavailable, "inference_saturated"
+	case errors.Is(err, inference.ErrNoGradients):
+		return http.StatusUnprocessableEntity, "explanation_unavailable"
+	}
+	return http.StatusInternalServerError, "internal_error"
+}
+
+// decodeImage decodes an upload between the validation checks: the format
+// is checked before any decoder runs, the picture once it is upright.
+func (h *Handler) decodeImage(data []byte, opts preprocess.Options) (image.Image, error) {
+	if err := h.Validation.CheckFormat(data); err != nil {
+		return nil, err
+	}
+	img, err := preprocess.DecodeImageBytes(data, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.Validation.CheckImage(img); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// checkInDistribution applies the OOD guard, if configured.
+func (h *Handler) checkInDistribution(img image.Image) error {
+	if h.OOD == nil {
+		return nil
+	}
+	if d, ok := h.OOD.Check(img); !ok {
+		return fmt.Errorf("%w (distance %.2f, limit %.2f)", errOutOfDistribution, d, h.OOD.MaxDistance)
+	}
+	return nil
+}
+
+// maxCorrelationFieldLen bounds client-supplied correlation identifiers.
+const maxCorrelationFieldLen = 128
+
+// correlationFields reads the optional `client_reference` and
+// `accession_number` form fields. They are opaque to us, but must be
+// printable and reasonably short since we store and echo them.
+func correlationFields(c *gin.Context) (string, string, error) {
+	values := [2]string{c.PostForm("client_reference"), c.PostForm("accession_number")}
+	for i, name := range []string{"client_reference", "accession_number"} {
+		v := strings.TrimSpace(values[i])
+		if len(v) > maxCorrelationFieldLen {
+			return "", "", fmt.Errorf("%s exceeds %d characters", name, maxCorrelationFieldLen)
+		}
+		for _, r := range v {
+			if !unicode.IsPrint(r) {
+				return "", "", fmt.Errorf("%s contains non-printable characters", name)
+			}
+		}
+		values[i] = v
+	}
+	return values[0], values[1], nil
+}
+
+// subgroupFields collects the optional demographic form fields used for
+// fairness monitoring. Values are truncated like correlation fields.
+func subgroupFields(c *gin.Context) map[string]string {
+	var out map[string]string
+	for _, dim := range fairness.Dimensions {
+		v := strings.TrimSpace(c.PostForm(dim))
+		if v == "" {
+			continue
+		}
+		if len(v) > maxCorrelationFieldLen {
+			v = v[:maxCorrelationFieldLen]
+		}
+		if out == nil {
+			out = make(map[string]string)
+		}
+		out[dim] = v
+	}
+	return out
+}
+
+// newPredictionID returns a time-ordered UUIDv7. These IDs are generated
+// independently on every device yet never collide, which lets the central
+// service merge records from many offline units without coordination.
+func newPredictionID() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.NewString()
+	}
+	return id.String()
+}
+
+// putPrediction saves rec. When webhooks are relayed from the store's
+// event outbox, the prediction.completed event for the tenant's webhooks
+// is written with it, so the event exists exactly when the prediction
+// does; if that write fails the event is handed to the webhooks directly.
+func (h *Handler) putPrediction(ctx context.Context, rec models.StoredPrediction) error {
+	outbox, ok := h.Store.(store.OutboxStore)
+	if !ok || !h.Webhooks.Relaying() || len(h.Webhooks.Registry().Subscribers(rec.Tenant, webhook.PredictionCompleted)) == 0 {
+		return h.Store.Put(ctx, rec)
+	}
+	ev := webhook.Event{ID: rec.PredictionID, Type: webhook.PredictionCompleted, Tenant: rec.Tenant, CreatedAt: rec.CreatedAt, Data: rec.PredictionResponse}
+	data, err := json.Marshal(ev.Data)
+	if err != nil {
+		return err
+	}
+	err = outbox.PutWithEvents(ctx, rec, store.OutboxEvent{ID: ev.ID, Type: ev.Type, Tenant: ev.Tenant, CreatedAt: ev.CreatedAt, Data: data})
+	if err != nil {
+		h.Webhooks.Notify(ev)
+		return err
+	}
+	h.Webhooks.RelayNow()
+	return nil
+}
+
+// checkDuplicate fingerprints the image, records it, and reports whether
+// this study has been seen before. The ID of the original prediction is
+// returned only when the same tenant submitted it; another tenant's
+// prediction IDs are never disclosed.
+func (h *Handler) checkDuplicate(ctx context.Context, img image.Image, predictionID, tenant string) (duplicateOf string, duplicate bool) {
+	hash := fingerprint.Compute(img)
+
+	if original, ok := h.Fingerprints.Match(hash, tenant); ok {
+		duplicate = true
+		if original.Tenant == tenant {
+			duplicateOf = original.PredictionID
+		}
+	}
+
+	err := h.Fingerprints.Add(fingerprint.Entry{
+		Hash:         hash,
+		PredictionID: predictionID,
+		Tenant:       tenant,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "fingerprint index: add failed", "prediction_id", predictionID, "tenant", tenant, "error", err)
+	}
+	return duplicateOf, duplicate
+}
+
+// queueOffline writes a prediction record (and the image, if blob is
+// non-nil) to the offline spool, sealed with the tenant's key when
+// TenantKeys is set. Failures are logged but never fail the clinical
+// response.
+func (h *Handler) queueOffline(ctx context.Context, tenant string, response models.PredictionResponse, filename string, blob []byte) {
+	payload, err := json.Marshal(models.OfflinePredictionRecord{
+		PredictionResponse: response,
+		Filename:           filename,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "offline queue: encode failed", "prediction_id", response.PredictionID, "error", err)
+		return
+	}
+
+	rec := spool.Record{
+		ID:        response.PredictionID,
+		Kind:      "prediction",
+		CreatedAt: time.Now().UTC(),
+		Payload:   payload,
+	}
+	if h.TenantKeys != nil {
+		if rec, blob, err = spool.Seal(h.TenantKeys, tenant, rec, blob); err != nil {
+			slog.ErrorContext(ctx, "offline queue: encrypt failed", "prediction_id", response.PredictionID, "error", err)
+			return
+		}
+	}
+	if err := h.Offline.Enqueue(rec, blob); err != nil {
+		slog.ErrorContext(ctx, "offline queue: enqueue failed", "prediction_id", response.PredictionID, "error", err)
+	}
 }
@@ -0,0 +1,2865 @@
+package handlers_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/png"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"math"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/gin-gonic/gin"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/josephed37/mammoscan-AI/backend/internal/access"
+	"github.com/josephed37/mammoscan-AI/backend/internal/audit"
+	"github.com/josephed37/mammoscan-AI/backend/internal/dependency"
+	"github.com/josephed37/mammoscan-AI/backend/internal/ensemble"
+	"github.com/josephed37/mammoscan-AI/backend/internal/events"
+	"github.com/josephed37/mammoscan-AI/backend/internal/experiment"
+	"github.com/josephed37/mammoscan-AI/backend/internal/explain"
+	"github.com/josephed37/mammoscan-AI/backend/internal/fingerprint"
+	"github.com/josephed37/mammoscan-AI/backend/internal/grpcapi"
+	"github.com/josephed37/mammoscan-AI/backend/internal/grpcapi/mammoscanv1"
+	"github.com/josephed37/mammoscan-AI/backend/internal/grpcapi/mammoscanv1/mammoscanv1connect"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers"
+	"github.com/josephed37/mammoscan-AI/backend/internal/handlers/handlertest"
+	"github.com/josephed37/mammoscan-AI/backend/internal/inference"
+	"github.com/josephed37/mammoscan-AI/backend/internal/jobs"
+	"github.com/josephed37/mammoscan-AI/backend/internal/journal"
+	"github.com/josephed37/mammoscan-AI/backend/internal/legalhold"
+	"github.com/josephed37/mammoscan-AI/backend/internal/lockout"
+	"github.com/josephed37/mammoscan-AI/backend/internal/logging"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/modelswap"
+	"github.com/josephed37/mammoscan-AI/backend/internal/netacl"
+	"github.com/josephed37/mammoscan-AI/backend/internal/signing"
+	"github.com/josephed37/mammoscan-AI/backend/internal/spool"
+	"github.com/josephed37/mammoscan-AI/backend/internal/store"
+	"github.com/josephed37/mammoscan-AI/backend/internal/tenantkey"
+	"github.com/josephed37/mammoscan-AI/backend/internal/webhook"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gorgonia.org/tensor"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestHandler returns a handler backed by engine and an in-memory store.
+func newTestHandler(t *testing.T, engine handlers.Predictor) *handlers.Handler {
+	t.Helper()
+	h := handlers.NewHandler(engine)
+	s, err := store.NewMemoryStore(store.MemoryConfig{})
+	if err != nil {
+		t.Fatalf("memory store: %v", err)
+	}
+	h.Store = s
+	return h
+}
+
+// newRouter registers the routes under test the same way cmd/api does.
+func newRouter(h *handlers.Handler) *gin.Engine {
+	r := gin.New()
+	r.GET("/healthy", h.RestrictNetwork(handlers.NetworkGroupHealth), h.HealthCheck)
+	r.GET("/ready", h.RestrictNetwork(handlers.NetworkGroupHealth), h.Ready)
+	r.GET("/healthz", h.RestrictNetwork(handlers.NetworkGroupHealth), h.Healthz)
+	api := r.Group("/api/v1", h.RestrictNetwork(handlers.NetworkGroupAPI), h.Authorize)
+	api.GET("/capabilities", h.Capabilities)
+	api.POST("/predict", h.Predict)
+	api.POST("/predict/batch", h.PredictBatch)
+	api.POST("/explain", h.Explain)
+	api.POST("/compare", h.Compare)
+	api.POST("/preprocess/debug", h.PreprocessDebug)
+	api.POST("/jobs", h.SubmitPredictionJob)
+	api.GET("/jobs/:id", h.GetJob)
+	api.GET("/predictions", h.ListPredictions)
+	api.GET("/predictions/:id", h.GetPrediction)
+	api.POST("/predictions/:id/restore", h.RestorePrediction)
+	api.POST("/predictions/:id/feedback", h.SubmitFeedback)
+	api.POST("/webhooks", h.CreateWebhook)
+	api.GET("/webhooks/:id", h.GetWebhook)
+	api.PATCH("/webhooks/:id", h.UpdateWebhook)
+	return r
+}
+
+func TestHealthCheck(t *testing.T) {
+	r := newRouter(newTestHandler(t, &handlertest.FakeEngine{}))
+	rec := handlertest.Do(r, httptest.NewRequest(http.MethodGet, "/healthy", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	handlertest.Golden(t, "healthy", rec.Body.Bytes())
+}
+
+func TestReady(t *testing.T) {
+	h := newTestHandler(t, &handlertest.FakeEngine{})
+	router := newRouter(h)
+
+	steps := []struct {
+		name       string
+		transition func()
+		wantStatus int
+		wantState  string
+	}{
+		{"starting", func() {}, http.StatusServiceUnavailable, handlers.ReadinessStarting},
+		{"ready", h.SetReady, http.StatusOK, handlers.ReadinessReady},
+		{"draining", h.Drain, http.StatusServiceUnavailable, handlers.ReadinessDraining},
+	}
+	for _, step := range steps {
+		step.transition()
+		rec := handlertest.Do(router, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		var resp models.HealthStatus
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: decode: %v", step.name, err)
+		}
+		if rec.Code != step.wantStatus || resp.Status != step.wantState {
+			t.Errorf("%s: got %d %q, want %d %q", step.name, rec.Code, resp.Status, step.wantStatus, step.wantState)
+		}
+		// Liveness does not depend on readiness.
+		if rec := handlertest.Do(router, httptest.NewRequest(http.MethodGet, "/healthy", nil)); rec.Code != http.StatusOK {
+			t.Errorf("%s: /healthy = %d, want 200", step.name, rec.Code)
+		}
+	}
+}
+
+func TestStaleModelGuard(t *testing.T) {
+	built := time.Now().Add(-400 * 24 * time.Hour)
+	engine := &handlertest.FakeEngine{Score: 0.9}
+	h := newTestHandler(t, engine)
+	h.Model.BuiltAt = &built
+	h.MaxModelAge = handlers.ModelAgeLimit{MaxAge: 365 * 24 * time.Hour, Block: true}
+	r := newRouter(h)
+
+	rec := handlertest.Do(r, httptest.NewRequest(http.MethodGet, "/healthy", nil))
+	var health models.HealthStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &health); err != nil {
+		t.Fatalf("decode health: %v", err)
+	}
+	if rec.Code != http.StatusOK || health.Status != "DEGRADED" || len(health.Warnings) != 1 {
+		t.Errorf("health = %d %+v, want 200 DEGRADED with a warning", rec.Code, health)
+	}
+
+	upload := handlertest.Upload{Image: handlertest.PNG(t, 120, 200)}
+	rec = handlertest.Do(r, upload.Request(t, http.MethodPost, "/api/v1/predict"))
+	var resp models.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	if rec.Code != http.StatusServiceUnavailable || resp.Code != "model_stale" {
+		t.Errorf("predict = %d %q, want 503 model_stale", rec.Code, resp.Code)
+	}
+	if engine.Calls() != 0 {
+		t.Errorf("engine calls = %d, want 0", engine.Calls())
+	}
+
+	// Without blocking, the stale model keeps serving.
+	h.MaxModelAge.Block = false
+	rec = handlertest.Do(r, upload.Request(t, http.MethodPost, "/api/v1/predict"))
+	if rec.Code != http.StatusOK {
+		t.Errorf("predict without blocking = %d, want 200; body %s", rec.Code, rec.Body)
+	}
+}
+
+// hungStore is a database that has stopped answering: every call waits
+// for its context.
+type hungStore struct{ store.Store }
+
+func (hungStore) Put(ctx context.Context, _ models.StoredPrediction) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (hungStore) Get(ctx context.Context, _ string) (models.StoredPrediction, error) {
+	<-ctx.Done()
+	return models.StoredPrediction{}, ctx.Err()
+}
+
+func TestSlowStoreCircuit(t *testing.T) {
+	h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+	h.Dependencies = &dependency.Set{}
+	breaker := h.Dependencies.Breaker("prediction_db", dependency.BreakerConfig{Failures: 2, Cooldown: time.Hour}, nil)
+	h.Store = store.Guard(hungStore{}, breaker, 50*time.Millisecond)
+	r := newRouter(h)
+
+	// The prediction does not wait on the store longer than its deadline,
+	// and not at all once the circuit is open.
+	upload := handlertest.Upload{Image: handlertest.PNG(t, 120, 200)}
+	for i := range 3 {
+		start := time.Now()
+		rec := handlertest.Do(r, upload.Request(t, http.MethodPost, "/api/v1/predict"))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("predict %d = %d, want 200; body %s", i, rec.Code, rec.Body)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("predict %d took %s with a hung store", i, elapsed)
+		}
+	}
+	if !breaker.Open() {
+		t.Fatal("breaker closed after the store timed out twice")
+	}
+
+	rec := handlertest.Do(r, httptest.NewRequest(http.MethodGet, "/api/v1/predictions/x", nil))
+	var errResp models.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	if rec.Code != http.StatusServiceUnavailable || errResp.Code != "store_unavailable" {
+		t.Errorf("lookup = %d %q, want 503 store_unavailable", rec.Code, errResp.Code)
+	}
+
+	rec = handlertest.Do(r, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	var health models.HealthStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &health); err != nil {
+		t.Fatalf("decode health: %v", err)
+	}
+	if rec.Code != http.StatusOK || health.Status != "DEGRADED" || len(health.Dependencies) != 1 {
+		t.Fatalf("healthz = %d %+v, want 200 DEGRADED with one dependency", rec.Code, health)
+	}
+	if d := health.Dependencies[0]; d.Name != "prediction_db" || d.State != dependency.StateOpen || d.Healthy || d.LastError == "" {
+		t.Errorf("dependency = %+v, want prediction_db open with its last error", d)
+	}
+}
+
+// downStore is a store whose writes fail while down is set.
+type downStore struct {
+	store.Store
+	down bool
+}
+
+func (s *downStore) Put(ctx context.Context, rec models.StoredPrediction) error {
+	if s.down {
+		return errors.New("connection refused")
+	}
+	return s.Store.Put(ctx, rec)
+}
+
+func TestWriteBehindStore(t *testing.T) {
+	h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+	db := &downStore{Store: h.Store, down: true}
+	queue, err := spool.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("open queue: %v", err)
+	}
+	wb, err := store.NewWriteBehind(db, queue, nil, spool.FlusherConfig{})
+	if err != nil {
+		t.Fatalf("write-behind store: %v", err)
+	}
+	h.Store = wb
+	r := newRouter(h)
+
+	// The database is down: the prediction is answered and queued.
+	upload := handlertest.Upload{Image: handlertest.PNG(t, 120, 200)}
+	rec := handlertest.Do(r, upload.Request(t, http.MethodPost, "/api/v1/predict"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("predict = %d, want 200; body %s", rec.Code, rec.Body)
+	}
+	var resp models.PredictionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode prediction: %v", err)
+	}
+	if _, err := wb.Flusher().FlushOnce(context.Background()); err == nil {
+		t.Fatal("flush succeeded with the database down")
+	}
+	if wb.Pending() != 1 {
+		t.Fatalf("pending = %d, want 1", wb.Pending())
+	}
+	// It can be looked up while queued.
+	rec = handlertest.Do(r, httptest.NewRequest(http.MethodGet, "/api/v1/predictions/"+resp.PredictionID, nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("lookup while queued = %d, want 200", rec.Code)
+	}
+
+	// The database is back: the queue drains into it.
+	db.down = false
+	if n, err := wb.Flusher().FlushOnce(context.Background()); err != nil || n != 1 {
+		t.Fatalf("flush = %d, %v; want 1, nil", n, err)
+	}
+	if wb.Pending() != 0 {
+		t.Errorf("pending = %d after flushing, want 0", wb.Pending())
+	}
+	if _, err := db.Store.Get(context.Background(), resp.PredictionID); err != nil {
+		t.Errorf("database lookup after flushing: %v", err)
+	}
+}
+
+func TestOfflineSync(t *testing.T) {
+	var mu sync.Mutex
+	kinds := map[string]string{}
+	central := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var env struct{ Record spool.Record }
+		if err := json.NewDecoder(r.Body).Decode(&env); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		kinds[env.Record.ID] = env.Record.Kind
+	}))
+	defer central.Close()
+
+	h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+	dir := t.TempDir()
+	queue, err := spool.Open(dir)
+	if err != nil {
+		t.Fatalf("open spool: %v", err)
+	}
+	auditQueue, err := spool.Open(filepath.Join(dir, "audit"))
+	if err != nil {
+		t.Fatalf("open audit spool: %v", err)
+	}
+	h.Offline = queue
+	h.Events = events.NewBus()
+	h.Events.SubscribeSync("audit-sync", audit.NewOffline(auditQueue).Record, events.AuthFailed)
+	r := newRouter(h)
+
+	// A record torn by a power loss sits in front of the prediction.
+	torn := filepath.Join(dir, "00000000-torn.json")
+	if err := os.WriteFile(torn, []byte(`{"id":"00000000-torn","kind":"predi`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rec := handlertest.Do(r, handlertest.Upload{Image: handlertest.PNG(t, 120, 200)}.Request(t, http.MethodPost, "/api/v1/predict"))
+	var resp models.PredictionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode prediction: %v", err)
+	}
+	failed := events.New(events.AuthFailed, "clinic-a", nil)
+	h.Events.Publish(failed)
+
+	for _, q := range []*spool.Queue{queue, auditQueue} {
+		if n, err := spool.NewForwarder(q, spool.ForwarderConfig{Endpoint: central.URL}).SyncOnce(context.Background()); err != nil || n != 1 {
+			t.Errorf("sync %s = %d, %v; want 1, nil", q.Dir(), n, err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if kinds[resp.PredictionID] != "prediction" || kinds[failed.ID] != "audit" || len(kinds) != 2 {
+		t.Errorf("synced %v, want the prediction and the audit entry", kinds)
+	}
+	if _, err := os.Stat(filepath.Join(dir, spool.QuarantineDir, "00000000-torn.json")); err != nil {
+		t.Errorf("torn record not quarantined: %v", err)
+	}
+}
+
+func TestPredict(t *testing.T) {
+	img := handlertest.PNG(t, 120, 200)
+
+	tests := []struct {
+		name       string
+		engine     *handlertest.FakeEngine
+		limits     handlers.UploadLimits
+		formats    []string
+		upload     handlertest.Upload
+		wantStatus int
+		wantCode   string
+		wantLabel  string
+		wantCalls  int
+	}{
+		{
+			name:       "cancer above threshold",
+			engine:     &handlertest.FakeEngine{Score: 0.9},
+			upload:     handlertest.Upload{Image: img},
+			wantStatus: http.StatusOK,
+			wantLabel:  models.LabelCancer,
+			wantCalls:  1,
+		},
+		{
+			name:       "non-cancer below threshold",
+			engine:     &handlertest.FakeEngine{Score: 0.01},
+			upload:     handlertest.Upload{Image: img},
+			wantStatus: http.StatusOK,
+			wantLabel:  models.LabelNonCancer,
+			wantCalls:  1,
+		},
+		{
+			name:       "spooled to disk",
+			engine:     &handlertest.FakeEngine{Score: 0.9},
+			limits:     handlers.UploadLimits{SpoolThreshold: 64, SpoolDir: t.TempDir()},
+			upload:     handlertest.Upload{Image: img},
+			wantStatus: http.StatusOK,
+			wantLabel:  models.LabelCancer,
+			wantCalls:  1,
+		},
+		{
+			name:       "image too large",
+			engine:     &handlertest.FakeEngine{},
+			limits:     handlers.UploadLimits{MaxImageBytes: int64(len(img) - 1), SpoolThreshold: 64},
+			upload:     handlertest.Upload{Image: img},
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   "upload_too_large",
+		},
+		{
+			name:       "missing image",
+			engine:     &handlertest.FakeEngine{},
+			upload:     handlertest.Upload{Fields: map[string]string{"accession_number": "A1"}},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "image_required",
+		},
+		{
+			name:       "unsupported format",
+			engine:     &handlertest.FakeEngine{},
+			upload:     handlertest.Upload{Image: []byte("not an image")},
+			wantStatus: http.StatusUnsupportedMediaType,
+			wantCode:   "unsupported_format",
+		},
+		{
+			name:       "truncated image",
+			engine:     &handlertest.FakeEngine{},
+			upload:     handlertest.Upload{Image: img[:len(img)/2]},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "invalid_image",
+		},
+		{
+			name:       "format not allowed",
+			engine:     &handlertest.FakeEngine{},
+			formats:    []string{"dicom"},
+			upload:     handlertest.Upload{Image: img},
+			wantStatus: http.StatusUnsupportedMediaType,
+			wantCode:   "format_not_allowed",
+		},
+		{
+			name:       "resolution too low",
+			engine:     &handlertest.FakeEngine{},
+			upload:     handlertest.Upload{Image: handlertest.PNG(t, 40, 200)},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "resolution_too_low",
+		},
+		{
+			name:       "blank image",
+			engine:     &handlertest.FakeEngine{},
+			upload:     handlertest.Upload{Image: handlertest.SolidPNG(t, 120, 200, 0)},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "blank_image",
+		},
+		{
+			name:       "invalid laterality",
+			engine:     &handlertest.FakeEngine{},
+			upload:     handlertest.Upload{Image: img, Fields: map[string]string{"laterality": "X"}},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_laterality",
+		},
+		{
+			name:       "incompatible model",
+			engine:     &handlertest.FakeEngine{Err: fmt.Errorf("%w: bad input shape", inference.ErrModelIncompatible)},
+			upload:     handlertest.Upload{Image: img},
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   "model_incompatible",
+			wantCalls:  1,
+		},
+		{
+			name:       "inference workers saturated",
+			engine:     &handlertest.FakeEngine{Err: fmt.Errorf("%w after 2s", inference.ErrSaturated)},
+			upload:     handlertest.Upload{Image: img},
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   "inference_saturated",
+			wantCalls:  1,
+		},
+		{
+			name:       "engine failure",
+			engine:     &handlertest.FakeEngine{Err: errors.New("boom")},
+			upload:     handlertest.Upload{Image: img},
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   "internal_error",
+			wantCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, tt.engine)
+			h.Uploads = tt.limits
+			h.Validation.Formats = tt.formats
+			rec := handlertest.Do(newRouter(h), tt.upload.Request(t, http.MethodPost, "/api/v1/predict"))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d; body %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if got := tt.engine.Calls(); got != tt.wantCalls {
+				t.Errorf("engine calls = %d, want %d", got, tt.wantCalls)
+			}
+			if tt.wantCode != "" {
+				var resp models.ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("decode error response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("code = %q, want %q (%s)", resp.Code, tt.wantCode, resp.Error)
+				}
+				return
+			}
+			var resp models.PredictionResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode prediction: %v", err)
+			}
+			if resp.Prediction != tt.wantLabel {
+				t.Errorf("prediction = %q, want %q", resp.Prediction, tt.wantLabel)
+			}
+		})
+	}
+}
+
+func TestPredictJSON(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(handlertest.PNG(t, 120, 200))
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "base64 image", body: `{"image_base64": "` + encoded + `", "format": "png"}`, wantStatus: http.StatusOK},
+		{name: "data URL", body: `{"image_base64": "data:image/png;base64,` + encoded + `"}`, wantStatus: http.StatusOK},
+		{name: "fields are form fields", body: `{"image_base64": "` + encoded + `", "laterality": "X"}`, wantStatus: http.StatusBadRequest, wantCode: "invalid_laterality"},
+		{name: "missing image", body: `{"format": "png"}`, wantStatus: http.StatusBadRequest, wantCode: "image_required"},
+		{name: "not base64", body: `{"image_base64": "not base64!"}`, wantStatus: http.StatusBadRequest, wantCode: "invalid_upload"},
+		{name: "nested member", body: `{"image_base64": "` + encoded + `", "options": {"top_k": 2}}`, wantStatus: http.StatusBadRequest, wantCode: "invalid_upload"},
+		{name: "malformed JSON", body: `{"image_base64": `, wantStatus: http.StatusBadRequest, wantCode: "invalid_upload"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/predict", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json; charset=utf-8")
+			rec := handlertest.Do(newRouter(h), req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d; body %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantCode != "" {
+				var resp models.ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("decode error response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("code = %q, want %q (%s)", resp.Code, tt.wantCode, resp.Error)
+				}
+				return
+			}
+			var resp models.PredictionResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode prediction: %v", err)
+			}
+			if resp.Prediction != models.LabelCancer {
+				t.Errorf("prediction = %q, want %q", resp.Prediction, models.LabelCancer)
+			}
+		})
+	}
+}
+
+func TestPredictDebugTensors(t *testing.T) {
+	var logged bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logged, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+	h.DebugTensors = true
+	upload := handlertest.Upload{Image: handlertest.PNG(t, 120, 200)}
+	rec := handlertest.Do(newRouter(h), upload.Request(t, http.MethodPost, "/api/v1/predict"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200; body %s", rec.Code, rec.Body)
+	}
+	var resp models.PredictionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode prediction: %v", err)
+	}
+
+	var entry map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logged.String()), "\n") {
+		var e map[string]any
+		if json.Unmarshal([]byte(line), &e) == nil && e["msg"] == "tensor statistics" && e["prediction_id"] == resp.PredictionID {
+			entry = e
+		}
+	}
+	if entry == nil {
+		t.Fatalf("no tensor statistics logged for %s:\n%s", resp.PredictionID, logged.String())
+	}
+	if entry["image"] != "120x200 *image.Gray" {
+		t.Errorf("image = %v, want 120x200 *image.Gray", entry["image"])
+	}
+	if entry["input_shape"] != "(1, 224, 224, 3)" {
+		t.Errorf("input_shape = %v, want (1, 224, 224, 3)", entry["input_shape"])
+	}
+	if ch, _ := entry["channels"].(string); !strings.Contains(ch, "R min 0 max 200") {
+		t.Errorf("channels = %q, want R min 0 max 200", ch)
+	}
+}
+
+func TestPredictTTA(t *testing.T) {
+	engine := &handlertest.FakeEngine{Score: 0.8}
+	h := newTestHandler(t, engine)
+	r := newRouter(h)
+	upload := handlertest.Upload{Image: handlertest.PNG(t, 120, 200)}
+
+	rec := handlertest.Do(r, upload.Request(t, http.MethodPost, "/api/v1/predict?tta=true"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200; body %s", rec.Code, rec.Body)
+	}
+	var resp models.PredictionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode prediction: %v", err)
+	}
+	if resp.TTA == nil || len(resp.TTA.Scores) != len(h.TTA.Augmentations) {
+		t.Fatalf("tta = %+v, want a score per augmentation %v", resp.TTA, h.TTA.Names())
+	}
+	if engine.Calls() != len(h.TTA.Augmentations) {
+		t.Errorf("engine calls = %d, want %d", engine.Calls(), len(h.TTA.Augmentations))
+	}
+	if math.Abs(resp.ConfidenceScore-0.8) > 1e-6 || resp.TTA.StdDev > 1e-6 {
+		t.Errorf("score = %v (std dev %v), want the mean 0.8", resp.ConfidenceScore, resp.TTA.StdDev)
+	}
+
+	// The form field works too, and TTA is off unless asked for.
+	for _, tc := range []struct {
+		name       string
+		fields     map[string]string
+		wantStatus int
+		wantTTA    bool
+	}{
+		{"form field", map[string]string{"tta": "1"}, http.StatusOK, true},
+		{"not asked", nil, http.StatusOK, false},
+		{"invalid", map[string]string{"tta": "maybe"}, http.StatusBadRequest, false},
+	} {
+		upload := handlertest.Upload{Image: upload.Image, Fields: tc.fields}
+		rec := handlertest.Do(r, upload.Request(t, http.MethodPost, "/api/v1/predict"))
+		var resp models.PredictionResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		if rec.Code != tc.wantStatus || (resp.TTA != nil) != tc.wantTTA {
+			t.Errorf("%s: got %d with tta %v, want %d with tta %v", tc.name, rec.Code, resp.TTA != nil, tc.wantStatus, tc.wantTTA)
+		}
+	}
+}
+
+func TestPredictEnsemble(t *testing.T) {
+	upload := handlertest.Upload{Image: handlertest.PNG(t, 120, 200)}
+	// The served model scores 0.9 (Cancer); "low" 0.05 is Non-Cancer by
+	// its own threshold, "mid" 0.4 Cancer by the served model's.
+	members := func(lowWeight, midWeight float64) []ensemble.Member {
+		return []ensemble.Member{
+			{Name: "low", Engine: &handlertest.FakeEngine{Score: 0.05}, Weight: lowWeight, Threshold: 0.1},
+			{Name: "mid", Engine: &handlertest.FakeEngine{Score: 0.4}, Weight: midWeight},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		ensemble   ensemble.Ensemble
+		wantScore  float64
+		wantLabel  string
+		wantReview bool
+		wantMethod string
+	}{
+		{
+			name:      "review only",
+			ensemble:  ensemble.Ensemble{Method: ensemble.MethodReview, MaxDisagreement: 0.9, Members: members(1, 1)},
+			wantScore: 0.9,
+			wantLabel: models.LabelCancer,
+		},
+		{
+			name:       "weighted mean",
+			ensemble:   ensemble.Ensemble{Method: ensemble.MethodWeightedMean, MaxDisagreement: 0.9, ServedWeight: 1, DecisionThreshold: 0.5, Members: members(1, 2)},
+			wantScore:  (0.9 + 0.05 + 2*0.4) / 4,
+			wantLabel:  models.LabelNonCancer,
+			wantMethod: "weighted_mean",
+		},
+		{
+			name:       "majority vote",
+			ensemble:   ensemble.Ensemble{Method: ensemble.MethodMajorityVote, MaxDisagreement: 0.9, ServedWeight: 1, Members: members(1, 2)},
+			wantScore:  0.75,
+			wantLabel:  models.LabelCancer,
+			wantMethod: "majority_vote",
+		},
+		{
+			name:       "tied vote",
+			ensemble:   ensemble.Ensemble{Method: ensemble.MethodMajorityVote, MaxDisagreement: 0.9, ServedWeight: 1, Members: members(2, 1)},
+			wantScore:  0.5,
+			wantLabel:  models.LabelCancer,
+			wantReview: true,
+			wantMethod: "majority_vote",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+			e := tt.ensemble
+			e.Threshold = h.Model.Threshold
+			h.Ensemble = &e
+			rec := handlertest.Do(newRouter(h), upload.Request(t, http.MethodPost, "/api/v1/predict"))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200; body %s", rec.Code, rec.Body)
+			}
+			var resp models.PredictionResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode prediction: %v", err)
+			}
+			if math.Abs(resp.ConfidenceScore-tt.wantScore) > 1e-6 || resp.Prediction != tt.wantLabel || resp.NeedsReview != tt.wantReview {
+				t.Errorf("got %v %s (review %v), want %v %s (review %v)", resp.ConfidenceScore, resp.Prediction, resp.NeedsReview, tt.wantScore, tt.wantLabel, tt.wantReview)
+			}
+			if len(resp.Ensemble) != 3 || math.Abs(resp.Ensemble[0].ConfidenceScore-0.9) > 1e-6 {
+				t.Errorf("ensemble = %+v, want the served model's own score first", resp.Ensemble)
+			}
+			method := ""
+			if resp.EnsembleDecision != nil {
+				method = resp.EnsembleDecision.Method
+			}
+			if method != tt.wantMethod {
+				t.Errorf("decision method = %q, want %q", method, tt.wantMethod)
+			}
+		})
+	}
+}
+
+func TestShadowExperiment(t *testing.T) {
+	h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+	manager, err := experiment.NewManager(experiment.Config{
+		Store: h.Store,
+		Load: func(context.Context, string) (experiment.Predictor, error) {
+			return &handlertest.FakeEngine{Score: 0.05}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("experiments: %v", err)
+	}
+	h.Experiments = manager
+	e, err := manager.Start(experiment.Definition{
+		Name: "challenger", Mode: experiment.ModeShadow, TrafficPercent: 100,
+		ModelRef: "challenger.onnx", Threshold: 0.5, Duration: "1h", SuccessMetric: "sensitivity",
+	})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	waitFor := func(what string, done func() bool) {
+		t.Helper()
+		for deadline := time.Now().Add(5 * time.Second); !done(); time.Sleep(10 * time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+		}
+	}
+	waitFor("the candidate to load", func() bool {
+		got, _ := manager.Get(e.ID)
+		return got.Status == experiment.StatusRunning
+	})
+
+	// The served model answers; the candidate's score is stored alongside.
+	upload := handlertest.Upload{Image: handlertest.PNG(t, 120, 200)}
+	rec := handlertest.Do(newRouter(h), upload.Request(t, http.MethodPost, "/api/v1/predict"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("predict = %d, want 200; body %s", rec.Code, rec.Body)
+	}
+	var resp models.PredictionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode prediction: %v", err)
+	}
+	if resp.Prediction != models.LabelCancer || math.Abs(resp.ConfidenceScore-0.9) > 1e-6 {
+		t.Errorf("response = %v %s, want the served model's 0.9 Cancer", resp.ConfidenceScore, resp.Prediction)
+	}
+	var stored models.StoredPrediction
+	waitFor("the shadow score", func() bool {
+		stored, _ = h.Store.Get(context.Background(), resp.PredictionID)
+		return stored.Shadow != nil
+	})
+	if stored.Experiment != e.ID || stored.Shadow.ModelName != "challenger.onnx" || stored.Shadow.Prediction != models.LabelNonCancer {
+		t.Errorf("stored = experiment %q, shadow %+v; want the challenger's Non-Cancer", stored.Experiment, stored.Shadow)
+	}
+
+	// Both models are tallied on the same study.
+	if _, err := h.Store.Update(context.Background(), resp.PredictionID, func(rec *models.StoredPrediction) error {
+		rec.Feedback = &models.Feedback{GroundTruth: models.LabelCancer}
+		return nil
+	}); err != nil {
+		t.Fatalf("feedback: %v", err)
+	}
+	done, err := manager.Stop(context.Background(), e.ID)
+	if err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if r := done.Results; r == nil || r.Control.TruePositives != 1 || r.Candidate.FalseNegatives != 1 {
+		t.Errorf("results = %+v, want the served model's hit and the candidate's miss", r)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	img := handlertest.PNG(t, 120, 200)
+
+	tests := []struct {
+		name       string
+		occlusion  bool
+		gradCAM    bool
+		target     string
+		upload     handlertest.Upload
+		wantStatus int
+		wantCode   string
+		wantMethod string
+	}{
+		{
+			name:       "occlusion overlay as JSON",
+			occlusion:  true,
+			target:     "/api/v1/explain",
+			upload:     handlertest.Upload{Image: img, Fields: map[string]string{"top_k": "2"}},
+			wantStatus: http.StatusOK,
+			wantMethod: explain.MethodOcclusion,
+		},
+		{
+			name:       "occlusion overlay as PNG",
+			occlusion:  true,
+			target:     "/api/v1/explain?format=png",
+			upload:     handlertest.Upload{Image: img},
+			wantStatus: http.StatusOK,
+			wantMethod: explain.MethodOcclusion,
+		},
+		{
+			name:       "Grad-CAM falls back to occlusion without gradients",
+			occlusion:  true,
+			gradCAM:    true,
+			target:     "/api/v1/explain",
+			upload:     handlertest.Upload{Image: img, Fields: map[string]string{"method": "gradcam"}},
+			wantStatus: http.StatusOK,
+			wantMethod: explain.MethodOcclusion,
+		},
+		{
+			name:       "Grad-CAM without gradients or occlusion",
+			gradCAM:    true,
+			target:     "/api/v1/explain",
+			upload:     handlertest.Upload{Image: img},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "explanation_unavailable",
+		},
+		{
+			name:       "method not enabled",
+			occlusion:  true,
+			target:     "/api/v1/explain",
+			upload:     handlertest.Upload{Image: img, Fields: map[string]string{"method": "gradcam"}},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "explanation_unavailable",
+		},
+		{
+			name:       "unknown method",
+			occlusion:  true,
+			target:     "/api/v1/explain",
+			upload:     handlertest.Upload{Image: img, Fields: map[string]string{"method": "lime"}},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_explain_method",
+		},
+		{
+			name:       "unknown format",
+			occlusion:  true,
+			target:     "/api/v1/explain?format=jpeg",
+			upload:     handlertest.Upload{Image: img},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+			if tt.occlusion {
+				h.Occlusion = &explain.Occlusion{Grid: 4}
+			}
+			h.GradCAM = tt.gradCAM
+			rec := handlertest.Do(newRouter(h), tt.upload.Request(t, http.MethodPost, tt.target))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d; body %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantCode != "" {
+				var resp models.ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("decode error response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("code = %q, want %q (%s)", resp.Code, tt.wantCode, resp.Error)
+				}
+				return
+			}
+
+			overlay := rec.Body.Bytes()
+			method := rec.Header().Get("X-MammoScan-Explain-Method")
+			if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "image/png") {
+				var resp models.ExplainResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("decode explanation: %v", err)
+				}
+				if resp.Prediction != models.LabelCancer || resp.Explanation == nil || resp.Explanation.Heatmap == nil {
+					t.Fatalf("response = %+v, want a cancer prediction with a heatmap", resp)
+				}
+				method = resp.Explanation.Method
+				var err error
+				if overlay, err = base64.StdEncoding.DecodeString(resp.OverlayPNG); err != nil {
+					t.Fatalf("decode overlay: %v", err)
+				}
+			}
+			if method != tt.wantMethod {
+				t.Errorf("method = %q, want %q", method, tt.wantMethod)
+			}
+			decoded, err := png.Decode(bytes.NewReader(overlay))
+			if err != nil {
+				t.Fatalf("overlay is not a PNG: %v", err)
+			}
+			if b := decoded.Bounds(); b.Dx() != 120 || b.Dy() != 200 {
+				t.Errorf("overlay is %dx%d, want 120x200", b.Dx(), b.Dy())
+			}
+		})
+	}
+}
+
+func TestPreprocessDebug(t *testing.T) {
+	img := handlertest.PNG(t, 120, 200)
+
+	tests := []struct {
+		name       string
+		target     string
+		upload     handlertest.Upload
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "stats and image as JSON", target: "/api/v1/preprocess/debug", upload: handlertest.Upload{Image: img}, wantStatus: http.StatusOK},
+		{name: "image as PNG", target: "/api/v1/preprocess/debug?format=png", upload: handlertest.Upload{Image: img}, wantStatus: http.StatusOK},
+		{name: "missing image", target: "/api/v1/preprocess/debug", upload: handlertest.Upload{}, wantStatus: http.StatusBadRequest, wantCode: "image_required"},
+		{name: "unknown format", target: "/api/v1/preprocess/debug?format=tiff", upload: handlertest.Upload{Image: img}, wantStatus: http.StatusBadRequest, wantCode: "invalid_format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := &handlertest.FakeEngine{Score: 0.9}
+			rec := handlertest.Do(newRouter(newTestHandler(t, engine)), tt.upload.Request(t, http.MethodPost, tt.target))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d; body %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if engine.Calls() != 0 {
+				t.Errorf("engine ran %d times, want 0", engine.Calls())
+			}
+			if tt.wantCode != "" {
+				var resp models.ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("decode error response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("code = %q, want %q (%s)", resp.Code, tt.wantCode, resp.Error)
+				}
+				return
+			}
+
+			rendered := rec.Body.Bytes()
+			if ct := rec.Header().Get("Content-Type"); strings.HasPrefix(ct, "image/png") {
+				if got := rec.Header().Get("X-MammoScan-Channel-Max"); got != "200,200,200" {
+					t.Errorf("X-MammoScan-Channel-Max = %q, want 200,200,200", got)
+				}
+			} else {
+				var resp models.PreprocessDebugResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("decode response: %v", err)
+				}
+				if fmt.Sprint(resp.Shape) != "[1 224 224 3]" || len(resp.Channels) != 3 {
+					t.Fatalf("response = %+v, want a 1x224x224x3 tensor with 3 channels", resp)
+				}
+				// The study is grey: every channel holds the same values.
+				for _, ch := range resp.Channels {
+					if ch != (models.ChannelStats{Name: ch.Name, Min: 0, Max: 200, Mean: resp.Channels[0].Mean}) {
+						t.Errorf("channel %+v, want min 0, max 200, mean %v", ch, resp.Channels[0].Mean)
+					}
+				}
+				var err error
+				if rendered, err = base64.StdEncoding.DecodeString(resp.ImagePNG); err != nil {
+					t.Fatalf("decode image: %v", err)
+				}
+			}
+			decoded, err := png.Decode(bytes.NewReader(rendered))
+			if err != nil {
+				t.Fatalf("image is not a PNG: %v", err)
+			}
+			if b := decoded.Bounds(); b.Dx() != 224 || b.Dy() != 224 {
+				t.Errorf("image is %dx%d, want 224x224", b.Dx(), b.Dy())
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	img := handlertest.PNG(t, 120, 200)
+
+	tests := []struct {
+		name       string
+		occlusion  bool
+		fields     map[string]string
+		wantStatus int
+		wantCode   string
+		wantDiff   bool
+	}{
+		{
+			name:       "active against standby with heatmaps",
+			occlusion:  true,
+			fields:     map[string]string{"model_a": "standby", "model_b": "v2"},
+			wantStatus: http.StatusOK,
+			wantDiff:   true,
+		},
+		{
+			name:       "scores only without explanations",
+			fields:     map[string]string{"model_a": "standby", "model_b": "active"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "missing model",
+			fields:     map[string]string{"model_a": "active"},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "model_required",
+		},
+		{
+			name:       "unknown version",
+			fields:     map[string]string{"model_a": "active", "model_b": "v0"},
+			wantStatus: http.StatusNotFound,
+			wantCode:   "model_version_not_loaded",
+		},
+		{
+			name:       "requested method unavailable",
+			fields:     map[string]string{"model_a": "standby", "model_b": "active", "method": "occlusion"},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "explanation_unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.01})
+			h.SwapModel(&handlertest.FakeEngine{Score: 0.9}, models.ModelInfo{Name: "candidate", Version: "v2"})
+			if tt.occlusion {
+				h.Occlusion = &explain.Occlusion{Grid: 4}
+			}
+			upload := handlertest.Upload{Image: img, Fields: tt.fields}
+			rec := handlertest.Do(newRouter(h), upload.Request(t, http.MethodPost, "/api/v1/compare"))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d; body %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantCode != "" {
+				var resp models.ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("decode error response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("code = %q, want %q (%s)", resp.Code, tt.wantCode, resp.Error)
+				}
+				return
+			}
+			var resp models.CompareResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode comparison: %v", err)
+			}
+			if resp.A.ConfidenceScore > resp.B.ConfidenceScore || resp.ScoreDelta <= 0 {
+				t.Errorf("scores %g and %g, delta %g: want the candidate higher", resp.A.ConfidenceScore, resp.B.ConfidenceScore, resp.ScoreDelta)
+			}
+			if resp.LabelsAgree {
+				t.Errorf("labels agree: %q and %q", resp.A.Prediction, resp.B.Prediction)
+			}
+			if got := resp.HeatmapDiff != nil; got != tt.wantDiff {
+				t.Errorf("heatmap diff present = %v, want %v", got, tt.wantDiff)
+			}
+		})
+	}
+}
+
+func TestPredictBatch(t *testing.T) {
+	img := handlertest.PNG(t, 120, 200)
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	for _, name := range []string{"study/a.png", "__MACOSX/study/._a.png", "study/b.png"} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(img)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		part      string
+		files     map[string][]byte
+		wantNames []string
+		wantCodes []int
+	}{
+		{
+			name:      "images",
+			part:      "images",
+			files:     map[string][]byte{"1.png": img, "2.txt": []byte("not an image")},
+			wantNames: []string{"1.png", "2.txt"},
+			wantCodes: []int{http.StatusOK, http.StatusUnsupportedMediaType},
+		},
+		{
+			name:      "archive",
+			part:      "archive",
+			files:     map[string][]byte{"studies.zip": archive.Bytes()},
+			wantNames: []string{"study/a.png", "study/b.png"},
+			wantCodes: []int{http.StatusOK, http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			for _, name := range slices.Sorted(maps.Keys(tt.files)) {
+				w, err := form.CreateFormFile(tt.part, name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				w.Write(tt.files[name])
+			}
+			form.Close()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/predict/batch", &body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+
+			rec := handlertest.Do(newRouter(newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})), req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d; body %s", rec.Code, rec.Body)
+			}
+			var resp models.BatchPredictionResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode batch response: %v", err)
+			}
+			if len(resp.Results) != len(tt.wantNames) {
+				t.Fatalf("got %d results, want %d", len(resp.Results), len(tt.wantNames))
+			}
+			for i, r := range resp.Results {
+				if r.Filename != tt.wantNames[i] || r.Status != tt.wantCodes[i] {
+					t.Errorf("result %d = %s %d, want %s %d", i, r.Filename, r.Status, tt.wantNames[i], tt.wantCodes[i])
+				}
+				if (r.Result != nil) != (r.Status == http.StatusOK) {
+					t.Errorf("result %d: status %d with result %v, error %v", i, r.Status, r.Result, r.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestPredictionJob(t *testing.T) {
+	tests := []struct {
+		name       string
+		image      []byte
+		wantStatus jobs.Status
+	}{
+		{name: "scored", image: handlertest.PNG(t, 120, 200), wantStatus: jobs.StatusSucceeded},
+		{name: "refused", image: []byte("not an image"), wantStatus: jobs.StatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+			h.PredictionJobs = jobs.NewManager(1)
+			r := newRouter(h)
+
+			rec := handlertest.Do(r, handlertest.Upload{Image: tt.image}.Request(t, http.MethodPost, "/api/v1/jobs"))
+			if rec.Code != http.StatusAccepted {
+				t.Fatalf("submit status = %d; body %s", rec.Code, rec.Body)
+			}
+			var job jobs.Job
+			if err := json.Unmarshal(rec.Body.Bytes(), &job); err != nil {
+				t.Fatalf("decode job: %v", err)
+			}
+
+			rec = handlertest.Do(r, httptest.NewRequest(http.MethodGet, "/api/v1/jobs/"+job.ID+"?wait=10s", nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("poll status = %d; body %s", rec.Code, rec.Body)
+			}
+			var done struct {
+				Status jobs.Status                `json:"status"`
+				Result *models.PredictionResponse `json:"result"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &done); err != nil {
+				t.Fatalf("decode job: %v", err)
+			}
+			if done.Status != tt.wantStatus {
+				t.Fatalf("job status = %s, want %s; body %s", done.Status, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus == jobs.StatusSucceeded && (done.Result == nil || done.Result.ConfidenceScore < 0.89) {
+				t.Errorf("job result = %+v, want the prediction", done.Result)
+			}
+		})
+	}
+}
+
+func TestListJobs(t *testing.T) {
+	h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+	h.Jobs, h.PredictionJobs = jobs.NewManager(1), jobs.NewManager(1)
+	r := gin.New()
+	r.GET("/api/v1/jobs", h.ListJobs)
+	noop := func(context.Context, func(float64)) (any, error) { return nil, nil }
+	// Alternating managers, so pages interleave both.
+	var want []string
+	for i := range 5 {
+		m, tenant := h.Jobs, "clinic-a"
+		if i%2 == 1 {
+			m = h.PredictionJobs
+		}
+		if i == 2 {
+			tenant = "clinic-b"
+		}
+		job := m.Submit(context.Background(), "test", tenant, noop)
+		if tenant == "clinic-a" {
+			want = append([]string{job.ID}, want...)
+		}
+	}
+
+	list := func(query string) (models.JobListResponse, int) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs?"+query, nil)
+		req.Header.Set("X-Tenant-ID", "clinic-a")
+		rec := handlertest.Do(r, req)
+		var resp models.JobListResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		return resp, rec.Code
+	}
+	var got []string
+	query := "limit=2"
+	for pages := 0; ; pages++ {
+		resp, code := list(query)
+		if code != http.StatusOK || pages > 3 {
+			t.Fatalf("page %d: status = %d", pages, code)
+		}
+		for _, j := range resp.Jobs {
+			got = append(got, j.ID)
+		}
+		if resp.NextCursor == "" {
+			break
+		}
+		query = "limit=2&cursor=" + url.QueryEscape(resp.NextCursor)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("pages = %v, want the tenant's jobs newest first %v", got, want)
+	}
+	if resp, _ := list("sort=created_at&limit=1"); len(resp.Jobs) != 1 || resp.Jobs[0].ID != want[len(want)-1] {
+		t.Errorf("oldest first = %v, want %s", resp.Jobs, want[len(want)-1])
+	}
+	for _, query := range []string{"sort=progress", "limit=-1", "filter=status:like:queued", "cursor=bm90LWEtY3Vyc29y"} {
+		if _, code := list(query); code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, code)
+		}
+	}
+}
+
+func TestAuthorizeJWT(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: key.Public(), KeyID: "k1", Algorithm: string(jose.ES256), Use: "sig"},
+		}})
+	}))
+	defer jwks.Close()
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: jose.JSONWebKey{Key: key, KeyID: "k1"}}, nil)
+	if err != nil {
+		t.Fatalf("signer: %v", err)
+	}
+	token := func(roles []string, expiry time.Time) string {
+		claims := jwt.Claims{Subject: "dr-who", Issuer: "https://idp.example", Expiry: jwt.NewNumericDate(expiry)}
+		s, err := jwt.Signed(signer).Claims(claims).Claims(map[string]any{"tenant": "clinic-a", "roles": roles}).Serialize()
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		return s
+	}
+
+	hour := time.Now().Add(time.Hour)
+	tests := []struct {
+		name       string
+		token      string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{name: "no credential", method: http.MethodGet, path: "/api/v1/capabilities", wantStatus: http.StatusUnauthorized},
+		{name: "readonly reads", token: token([]string{"readonly"}, hour), method: http.MethodGet, path: "/api/v1/capabilities", wantStatus: http.StatusOK},
+		{name: "readonly cannot predict", token: token([]string{"readonly"}, hour), method: http.MethodPost, path: "/api/v1/predict", wantStatus: http.StatusForbidden},
+		{name: "predict role predicts", token: token([]string{"predict"}, hour), method: http.MethodPost, path: "/api/v1/predict", wantStatus: http.StatusOK},
+		{name: "predict role cannot restore", token: token([]string{"predict"}, hour), method: http.MethodPost, path: "/api/v1/predictions/p1/restore", wantStatus: http.StatusForbidden},
+		{name: "predict role cannot give feedback", token: token([]string{"predict"}, hour), method: http.MethodPost, path: "/api/v1/predictions/p1/feedback", wantStatus: http.StatusForbidden},
+		{name: "expired", token: token([]string{"admin"}, time.Now().Add(-time.Hour)), method: http.MethodGet, path: "/api/v1/capabilities", wantStatus: http.StatusUnauthorized},
+		{name: "garbage", token: "eyJhbGciOiJub25lIn0.e30.x", method: http.MethodGet, path: "/api/v1/capabilities", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.5})
+			if h.Access, err = access.NewEngine(""); err != nil {
+				t.Fatalf("access engine: %v", err)
+			}
+			if h.JWT, err = access.NewJWTVerifier(context.Background(), access.JWTConfig{JWKSURL: jwks.URL, Issuer: "https://idp.example"}); err != nil {
+				t.Fatalf("verifier: %v", err)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.method == http.MethodPost {
+				req = handlertest.Upload{Image: handlertest.PNG(t, 64, 64)}.Request(t, tt.method, tt.path)
+			}
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := handlertest.Do(newRouter(h), req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d; body %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+		})
+	}
+}
+
+func TestSignedRequests(t *testing.T) {
+	const apiKey, secret = "partner-key", "partner-secret"
+	digest := sha256.Sum256([]byte(apiKey))
+	policy := filepath.Join(t.TempDir(), "policy.json")
+	err := os.WriteFile(policy, []byte(fmt.Sprintf(`{"api_keys": [{"id": "partner", "tenant": "t1", "roles": ["readonly"], "sha256": "%x"}]}`, digest)), 0o600)
+	if err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+
+	h := newTestHandler(t, &handlertest.FakeEngine{})
+	if h.Access, err = access.NewEngine(policy); err != nil {
+		t.Fatalf("access engine: %v", err)
+	}
+	h.RequestSigning = signing.NewVerifier(time.Minute)
+	h.SigningSecret = func(keyID string) (string, bool) { return secret, keyID == "partner" }
+	r := newRouter(h)
+
+	emptySum := sha256.Sum256(nil)
+	sign := func(ts time.Time, nonce, key string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/capabilities?x=1", nil)
+		req.Header.Set("X-API-Key", apiKey)
+		sr := signing.Request{Timestamp: fmt.Sprint(ts.Unix()), Nonce: nonce, Method: http.MethodGet, URI: "/api/v1/capabilities?x=1", BodySHA256: emptySum[:]}
+		req.Header.Set(signing.TimestampHeader, sr.Timestamp)
+		req.Header.Set(signing.NonceHeader, nonce)
+		req.Header.Set(signing.SignatureHeader, signing.Sign(key, sr))
+		return req
+	}
+
+	unsigned := httptest.NewRequest(http.MethodGet, "/api/v1/capabilities", nil)
+	unsigned.Header.Set("X-API-Key", apiKey)
+	tests := []struct {
+		name     string
+		req      *http.Request
+		wantCode string
+	}{
+		{name: "valid", req: sign(time.Now(), "n1", secret)},
+		{name: "replayed", req: sign(time.Now(), "n1", secret), wantCode: "signature_replayed"},
+		{name: "skewed", req: sign(time.Now().Add(-2*time.Minute), "n2", secret), wantCode: "signature_expired"},
+		{name: "wrong secret", req: sign(time.Now(), "n3", "other"), wantCode: "invalid_signature"},
+		{name: "unsigned", req: unsigned, wantCode: "signature_required"},
+	}
+	// Cases run in order: the replay reuses the first case's nonce.
+	for _, tt := range tests {
+		rec := handlertest.Do(r, tt.req)
+		if tt.wantCode == "" {
+			if rec.Code != http.StatusOK {
+				t.Errorf("%s: status = %d; body %s", tt.name, rec.Code, rec.Body)
+			}
+			continue
+		}
+		var e models.ErrorResponse
+		json.Unmarshal(rec.Body.Bytes(), &e)
+		if rec.Code != http.StatusUnauthorized || e.Code != tt.wantCode {
+			t.Errorf("%s: status = %d, code %q; want 401 %q", tt.name, rec.Code, e.Code, tt.wantCode)
+		}
+	}
+}
+
+func TestAuthLockout(t *testing.T) {
+	const apiKey = "good-key"
+	digest := sha256.Sum256([]byte(apiKey))
+	policy := filepath.Join(t.TempDir(), "policy.json")
+	err := os.WriteFile(policy, []byte(fmt.Sprintf(`{"api_keys": [{"id": "k1", "tenant": "t1", "roles": ["readonly"], "sha256": "%x"}]}`, digest)), 0o600)
+	if err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+
+	h := newTestHandler(t, &handlertest.FakeEngine{})
+	if h.Access, err = access.NewEngine(policy); err != nil {
+		t.Fatalf("access engine: %v", err)
+	}
+	h.Lockouts = lockout.New(lockout.Config{Threshold: 3, Base: time.Minute})
+	h.Events = events.NewBus()
+	lockouts := make(chan events.Lockout, 4)
+	h.Events.Subscribe("test", 0, func(ev events.Event) { lockouts <- ev.Data.(events.Lockout) }, events.AuthLockedOut)
+	r := newRouter(h)
+
+	call := func(key, remote string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/capabilities", nil)
+		req.Header.Set("X-API-Key", key)
+		req.RemoteAddr = remote
+		return handlertest.Do(r, req)
+	}
+	for i := range 3 {
+		if rec := call(fmt.Sprintf("guess-%d", i), "10.0.0.1:4000"); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("guess %d: status = %d, want 401", i, rec.Code)
+		}
+	}
+
+	// The address is locked out, even with a valid key.
+	rec := call(apiKey, "10.0.0.1:4000")
+	var e models.ErrorResponse
+	json.Unmarshal(rec.Body.Bytes(), &e)
+	if rec.Code != http.StatusTooManyRequests || e.Code != "auth_locked" || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("locked out: status = %d, code %q, Retry-After %q; want 429 auth_locked", rec.Code, e.Code, rec.Header().Get("Retry-After"))
+	}
+	if rec := call(apiKey, "10.0.0.2:4000"); rec.Code != http.StatusOK {
+		t.Errorf("other address: status = %d, want 200", rec.Code)
+	}
+
+	select {
+	case l := <-lockouts:
+		if l.Subject != "ip" || l.ClientIP != "10.0.0.1" || l.Lockouts != 1 {
+			t.Errorf("lockout event = %+v, want first lockout of ip 10.0.0.1", l)
+		}
+	case <-time.After(time.Second):
+		t.Error("no lockout event published")
+	}
+}
+
+func TestAuthLockoutIgnoresForgedForwardedFor(t *testing.T) {
+	const apiKey = "good-key"
+	digest := sha256.Sum256([]byte(apiKey))
+	policy := filepath.Join(t.TempDir(), "policy.json")
+	err := os.WriteFile(policy, []byte(fmt.Sprintf(`{"api_keys": [{"id": "k1", "tenant": "t1", "roles": ["readonly"], "sha256": "%x"}]}`, digest)), 0o600)
+	if err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+
+	h := newTestHandler(t, &handlertest.FakeEngine{})
+	if h.Access, err = access.NewEngine(policy); err != nil {
+		t.Fatalf("access engine: %v", err)
+	}
+	h.Lockouts = lockout.New(lockout.Config{Threshold: 3, Base: time.Minute})
+	r := newRouter(h)
+	// As in production with no trusted proxies configured.
+	if err := r.SetTrustedProxies(nil); err != nil {
+		t.Fatalf("trusted proxies: %v", err)
+	}
+
+	call := func(key, forwarded string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/capabilities", nil)
+		req.Header.Set("X-API-Key", key)
+		req.Header.Set("X-Forwarded-For", forwarded)
+		req.RemoteAddr = "10.0.0.1:4000"
+		return handlertest.Do(r, req)
+	}
+	for i := range 3 {
+		if rec := call(fmt.Sprintf("guess-%d", i), fmt.Sprintf("192.0.2.%d", i+1)); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("guess %d: status = %d, want 401", i, rec.Code)
+		}
+	}
+	// Each guess claimed another address, but all were counted against
+	// the peer, which is now locked out whatever it claims.
+	if rec := call(apiKey, "192.0.2.99"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("forged address after lockout: status = %d, want 429", rec.Code)
+	}
+}
+
+func TestRestrictNetwork(t *testing.T) {
+	policy := filepath.Join(t.TempDir(), "network.json")
+	err := os.WriteFile(policy, []byte(`{"groups": {
+		"default": {"allow": ["10.0.0.0/8", "fd00::/8"], "deny": ["10.6.6.0/24"]},
+		"health": {}
+	}}`), 0o600)
+	if err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	h := newTestHandler(t, &handlertest.FakeEngine{})
+	if h.Network, err = netacl.NewEngine(policy); err != nil {
+		t.Fatalf("network policy: %v", err)
+	}
+	r := newRouter(h)
+
+	tests := []struct {
+		name       string
+		remote     string
+		path       string
+		wantStatus int
+	}{
+		{name: "allowed", remote: "10.1.2.3:5000", path: "/api/v1/capabilities", wantStatus: http.StatusOK},
+		{name: "allowed ipv6", remote: "[fd00::1]:5000", path: "/api/v1/capabilities", wantStatus: http.StatusOK},
+		{name: "outside allowlist", remote: "192.168.1.1:5000", path: "/api/v1/capabilities", wantStatus: http.StatusForbidden},
+		{name: "denied", remote: "10.6.6.6:5000", path: "/api/v1/capabilities", wantStatus: http.StatusForbidden},
+		{name: "health open", remote: "192.168.1.1:5000", path: "/healthy", wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.RemoteAddr = tt.remote
+			if rec := handlertest.Do(r, req); rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d; body %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+		})
+	}
+}
+
+func TestPredictResponseGolden(t *testing.T) {
+	r := newRouter(newTestHandler(t, &handlertest.FakeEngine{Score: 0.75}))
+	upload := handlertest.Upload{
+		Image:  handlertest.PNG(t, 120, 200),
+		Fields: map[string]string{"client_reference": "ref-1", "accession_number": "ACC-42"},
+	}
+	rec := handlertest.Do(r, upload.Request(t, http.MethodPost, "/api/v1/predict"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body %s", rec.Code, rec.Body)
+	}
+	handlertest.Golden(t, "predict", rec.Body.Bytes(), "prediction_id")
+}
+
+func TestPredictionLookupAndFeedback(t *testing.T) {
+	r := newRouter(newTestHandler(t, &handlertest.FakeEngine{Score: 0.5}))
+	upload := handlertest.Upload{Image: handlertest.PNG(t, 120, 200), Header: http.Header{"X-Tenant-Id": {"clinic-a"}}}
+	rec := handlertest.Do(r, upload.Request(t, http.MethodPost, "/api/v1/predict"))
+	var created models.PredictionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode prediction: %v", err)
+	}
+	path := "/api/v1/predictions/" + created.PredictionID
+
+	get := func(tenant string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-Tenant-ID", tenant)
+		return handlertest.Do(r, req)
+	}
+	if rec := get("clinic-b"); rec.Code != http.StatusNotFound {
+		t.Errorf("other tenant: status = %d, want 404", rec.Code)
+	}
+	rec = get("clinic-a")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("own tenant: status = %d, want 200", rec.Code)
+	}
+	handlertest.Golden(t, "prediction", rec.Body.Bytes(), "prediction_id", "created_at")
+
+	feedback := func(body, ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path+"/feedback", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Tenant-ID", "clinic-a")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		return handlertest.Do(r, req)
+	}
+	first := feedback(`{"ground_truth":"Cancer","reviewer":"dr-a"}`, "")
+	if first.Code != http.StatusOK {
+		t.Fatalf("first feedback: status = %d; body %s", first.Code, first.Body)
+	}
+	if rec := feedback(`{"ground_truth":"Non-Cancer"}`, ""); rec.Code != http.StatusPreconditionRequired {
+		t.Errorf("overwrite without If-Match: status = %d, want 428", rec.Code)
+	}
+	if rec := feedback(`{"ground_truth":"Non-Cancer"}`, first.Header().Get("ETag")); rec.Code != http.StatusOK {
+		t.Errorf("overwrite with If-Match: status = %d, want 200; body %s", rec.Code, rec.Body)
+	}
+	if rec := feedback(`{"ground_truth":"Cancer"}`, first.Header().Get("ETag")); rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("stale If-Match: status = %d, want 412", rec.Code)
+	}
+}
+
+func TestDuplicateDetection(t *testing.T) {
+	h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.5})
+	index, err := fingerprint.NewIndex(fingerprint.IndexConfig{MaxDistance: 4, CrossTenant: true})
+	if err != nil {
+		t.Fatalf("fingerprint index: %v", err)
+	}
+	h.Fingerprints = index
+	r := newRouter(h)
+	img := handlertest.PNG(t, 120, 200)
+
+	predict := func(tenant string) models.PredictionResponse {
+		t.Helper()
+		upload := handlertest.Upload{Image: img, Header: http.Header{"X-Tenant-Id": {tenant}}}
+		rec := handlertest.Do(r, upload.Request(t, http.MethodPost, "/api/v1/predict"))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("predict as %s: status = %d; body %s", tenant, rec.Code, rec.Body)
+		}
+		var resp models.PredictionResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode prediction: %v", err)
+		}
+		return resp
+	}
+
+	original := predict("clinic-a")
+	if original.Duplicate || original.DuplicateOf != "" {
+		t.Fatalf("first submission flagged as duplicate: %+v", original)
+	}
+	other := predict("clinic-b")
+	if !other.Duplicate {
+		t.Error("cross-tenant resubmission not flagged as duplicate")
+	}
+	if other.DuplicateOf != "" {
+		t.Errorf("cross-tenant duplicate_of = %q, want another tenant's ID withheld", other.DuplicateOf)
+	}
+	again := predict("clinic-a")
+	if !again.Duplicate || again.DuplicateOf != original.PredictionID {
+		t.Errorf("same-tenant duplicate = %v, %q; want true, %q", again.Duplicate, again.DuplicateOf, original.PredictionID)
+	}
+}
+
+func TestListPredictions(t *testing.T) {
+	h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.5})
+	r := newRouter(h)
+	base := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	for i, label := range []string{models.LabelCancer, models.LabelNonCancer, models.LabelCancer, models.LabelCancer, models.LabelCancer} {
+		var rec models.StoredPrediction
+		rec.PredictionID, rec.Tenant, rec.CreatedAt, rec.Prediction = fmt.Sprintf("p%d", i+1), "clinic-a", base.Add(time.Duration(i)*time.Minute), label
+		if err := h.Store.Put(context.Background(), rec); err != nil {
+			t.Fatalf("put: %v", err)
+		}
+	}
+	list := func(query string) (models.PredictionListResponse, int) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/predictions?"+query, nil)
+		req.Header.Set("X-Tenant-ID", "clinic-a")
+		rec := handlertest.Do(r, req)
+		var resp models.PredictionListResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		return resp, rec.Code
+	}
+	ids := func(resp models.PredictionListResponse) []string {
+		var out []string
+		for _, p := range resp.Predictions {
+			out = append(out, p.PredictionID)
+		}
+		return out
+	}
+
+	// Paging through the Cancer results, newest first.
+	var got []string
+	query := "limit=2&filter=prediction:eq:Cancer"
+	for pages := 0; ; pages++ {
+		resp, code := list(query)
+		if code != http.StatusOK || pages > 3 {
+			t.Fatalf("page %d: status = %d", pages, code)
+		}
+		got = append(got, ids(resp)...)
+		if resp.NextCursor == "" {
+			break
+		}
+		query = "limit=2&filter=prediction:eq:Cancer&cursor=" + url.QueryEscape(resp.NextCursor)
+	}
+	if want := []string{"p5", "p4", "p3", "p1"}; !slices.Equal(got, want) {
+		t.Errorf("pages = %v, want %v", got, want)
+	}
+	if resp, _ := list("sort=created_at&limit=2"); !slices.Equal(ids(resp), []string{"p1", "p2"}) {
+		t.Errorf("oldest first = %v, want [p1 p2]", ids(resp))
+	}
+
+	for _, query := range []string{"sort=confidence_score", "limit=0", "filter=prediction", "cursor=not-a-cursor"} {
+		if _, code := list(query); code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, code)
+		}
+	}
+}
+
+// pausingStore holds up the first call reading a record -- after the
+// read, or before an update starts -- until resume is closed, so another
+// request can write in the meantime.
+type pausingStore struct {
+	store.Store
+	paused, resume chan struct{}
+	used           atomic.Bool
+}
+
+func (s *pausingStore) pause() {
+	if s.used.CompareAndSwap(false, true) {
+		close(s.paused)
+		<-s.resume
+	}
+}
+
+func (s *pausingStore) Get(ctx context.Context, id string) (models.StoredPrediction, error) {
+	rec, err := s.Store.Get(ctx, id)
+	s.pause()
+	return rec, err
+}
+
+func (s *pausingStore) Update(ctx context.Context, id string, fn func(*models.StoredPrediction) error) (models.StoredPrediction, error) {
+	s.pause()
+	return s.Store.Update(ctx, id, fn)
+}
+
+func TestDeleteDuringFeedback(t *testing.T) {
+	h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.5})
+	var rec models.StoredPrediction
+	rec.PredictionID, rec.Tenant, rec.CreatedAt = "p1", "clinic-a", time.Now().UTC()
+	if err := h.Store.Put(context.Background(), rec); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	paused := &pausingStore{Store: h.Store, paused: make(chan struct{}), resume: make(chan struct{})}
+	h.Store = paused
+	r := newRouter(h)
+	r.DELETE("/api/v1/predictions/:id", h.DeletePrediction)
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Tenant-ID", "clinic-a")
+		return handlertest.Do(r, req)
+	}
+
+	deleted := make(chan int)
+	go func() { deleted <- send(http.MethodDelete, "/api/v1/predictions/p1", "").Code }()
+	<-paused.paused
+	// The delete is under way; feedback is written meanwhile.
+	if rec := send(http.MethodPost, "/api/v1/predictions/p1/feedback", `{"ground_truth":"Cancer"}`); rec.Code != http.StatusOK {
+		t.Fatalf("feedback = %d, want 200; body %s", rec.Code, rec.Body)
+	}
+	close(paused.resume)
+	if code := <-deleted; code != http.StatusOK {
+		t.Fatalf("delete = %d, want 200", code)
+	}
+
+	got, err := h.Store.Get(context.Background(), "p1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.DeletedAt == nil {
+		t.Error("prediction not deleted")
+	}
+	if got.Feedback == nil {
+		t.Error("feedback written during the delete was lost")
+	}
+}
+
+func TestCapabilitiesConditionalGet(t *testing.T) {
+	r := newRouter(newTestHandler(t, &handlertest.FakeEngine{}))
+	rec := handlertest.Do(r, httptest.NewRequest(http.MethodGet, "/api/v1/capabilities", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	handlertest.Golden(t, "capabilities", rec.Body.Bytes(), "loaded_at")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/capabilities", nil)
+	req.Header.Set("If-None-Match", rec.Header().Get("ETag"))
+	if rec := handlertest.Do(r, req); rec.Code != http.StatusNotModified {
+		t.Errorf("revalidation: status = %d, want 304", rec.Code)
+	}
+}
+
+func TestWebhookManagement(t *testing.T) {
+	h := newTestHandler(t, &handlertest.FakeEngine{})
+	registry, err := webhook.NewRegistry("")
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	h.Webhooks = webhook.NewDispatcher(registry, webhook.DispatcherConfig{})
+	r := newRouter(h)
+
+	send := func(method, path, tenant, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if tenant != "" {
+			req.Header.Set("X-Tenant-ID", tenant)
+		}
+		return handlertest.Do(r, req)
+	}
+
+	if rec := send(http.MethodPost, "/api/v1/webhooks", "", `{}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("no tenant: status = %d, want 400", rec.Code)
+	}
+	if rec := send(http.MethodPost, "/api/v1/webhooks", "clinic-a", `{"url":"https://ris.example/hook","events":["study.deleted"]}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown event: status = %d, want 400", rec.Code)
+	}
+
+	rec := send(http.MethodPost, "/api/v1/webhooks", "clinic-a", `{"url":"https://ris.example/hook","events":["prediction.completed"]}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: status = %d; body %s", rec.Code, rec.Body)
+	}
+	var created webhook.Endpoint
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode webhook: %v", err)
+	}
+	if created.Secret == "" {
+		t.Error("create did not return the signing secret")
+	}
+	path := "/api/v1/webhooks/" + created.ID
+
+	if rec := send(http.MethodGet, path, "clinic-b", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("other tenant: status = %d, want 404", rec.Code)
+	}
+	rec = send(http.MethodPatch, path, "clinic-a", `{"events":["job.failed","drift.alert"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update: status = %d; body %s", rec.Code, rec.Body)
+	}
+	var updated webhook.Endpoint
+	if err := json.Unmarshal(rec.Body.Bytes(), &updated); err != nil {
+		t.Fatalf("decode webhook: %v", err)
+	}
+	if updated.Secret != "" || updated.URL != created.URL || len(updated.Events) != 2 {
+		t.Errorf("update = %+v, want the same URL, two events and no secret", updated)
+	}
+}
+
+func TestWebhookOutbox(t *testing.T) {
+	// The receiver processes every delivery but the first answer is lost,
+	// so the event is sent twice with the same idempotency key: the
+	// prediction ID.
+	var mu sync.Mutex
+	var keys []string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		keys = append(keys, r.Header.Get(webhook.IdempotencyHeader))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer receiver.Close()
+
+	registry, err := webhook.NewRegistry("")
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	url := receiver.URL
+	if _, err := registry.Create("clinic-a", webhook.Spec{URL: &url, Events: []string{webhook.PredictionCompleted}}); err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	outbox, err := spool.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("open outbox: %v", err)
+	}
+	dispatcher := webhook.NewDispatcher(registry, webhook.DispatcherConfig{Outbox: outbox, OutboxInterval: 10 * time.Millisecond})
+
+	// The event is written with the prediction and relayed from the
+	// store; nothing comes off the bus.
+	h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+	source := h.Store.(store.OutboxStore)
+	dispatcher.RelayFrom(source)
+	h.Webhooks = dispatcher
+	r := newRouter(h)
+
+	req := handlertest.Upload{Image: handlertest.PNG(t, 120, 200)}.Request(t, http.MethodPost, "/api/v1/predict")
+	req.Header.Set("X-Tenant-ID", "clinic-a")
+	rec := handlertest.Do(r, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("predict = %d, want 200; body %s", rec.Code, rec.Body)
+	}
+	var resp models.PredictionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode prediction: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := len(keys)
+		mu.Unlock()
+		if n >= 2 && dispatcher.Pending() == 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := dispatcher.Pending(); n != 0 {
+		t.Fatalf("outbox still holds %d deliveries", n)
+	}
+	if evs, err := source.PendingEvents(context.Background(), 0); err != nil || len(evs) != 0 {
+		t.Errorf("store outbox = %d events (%v), want it relayed", len(evs), err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(keys) != 2 || keys[0] != resp.PredictionID || keys[1] != resp.PredictionID {
+		t.Errorf("idempotency keys = %q, want %q twice", keys, resp.PredictionID)
+	}
+}
+
+func TestWebhookDeadLetter(t *testing.T) {
+	var attempts atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	registry, err := webhook.NewRegistry("")
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	url := receiver.URL
+	if _, err := registry.Create("clinic-a", webhook.Spec{URL: &url, Events: []string{webhook.JobFailed}}); err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	outbox, err := spool.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("open outbox: %v", err)
+	}
+	deadLetter, err := spool.Open(filepath.Join(outbox.Dir(), "dead-letter"))
+	if err != nil {
+		t.Fatalf("open dead letter queue: %v", err)
+	}
+	dispatcher := webhook.NewDispatcher(registry, webhook.DispatcherConfig{
+		Outbox:         outbox,
+		OutboxInterval: 10 * time.Millisecond,
+		OutboxMaxAge:   100 * time.Millisecond,
+		DeadLetter:     deadLetter,
+	})
+	dispatcher.Notify(webhook.Event{ID: "job-1", Type: webhook.JobFailed, Tenant: "clinic-a"})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for dispatcher.DeadLettered() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n, dead := dispatcher.Pending(), dispatcher.DeadLettered(); n != 0 || dead != 1 {
+		t.Fatalf("outbox %d, dead letters %d; want 0 and 1", n, dead)
+	}
+	if attempts.Load() == 0 {
+		t.Error("the delivery was dead-lettered without being tried")
+	}
+	records, err := deadLetter.Pending(0)
+	if err != nil || len(records) != 1 || !strings.HasPrefix(records[0].ID, "job-1.") {
+		t.Errorf("dead letters = %+v (%v), want the job-1 delivery", records, err)
+	}
+}
+
+func TestTenantKeys(t *testing.T) {
+	keyDir, dir := t.TempDir(), t.TempDir()
+	writeKey := func(tenant string) {
+		key := make([]byte, 32)
+		rand.Read(key)
+		if err := os.WriteFile(filepath.Join(keyDir, tenant+".key"), []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeKey(tenantkey.DefaultTenant)
+	writeKey("clinic-a")
+	writeKey("clinic-b")
+	history := filepath.Join(dir, "predictions.jsonl")
+	fallback := false
+	var keys *tenantkey.Keyring
+	var requests *journal.Journal
+	var offline *spool.Queue
+	open := func() *gin.Engine {
+		t.Helper()
+		var err error
+		keys, err = tenantkey.LoadDir(keyDir, tenantkey.Config{DefaultFallback: fallback})
+		if err != nil {
+			t.Fatalf("load keys: %v", err)
+		}
+		h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+		h.TenantKeys = keys
+		if h.Store, err = store.NewMemoryStore(store.MemoryConfig{JournalPath: history, Keys: keys}); err != nil {
+			t.Fatalf("memory store: %v", err)
+		}
+		if h.Images, err = store.NewDirImageStore(filepath.Join(dir, "images"), keys); err != nil {
+			t.Fatalf("image store: %v", err)
+		}
+		if requests, err = journal.Open(filepath.Join(dir, "journal"), keys); err != nil {
+			t.Fatalf("journal: %v", err)
+		}
+		if offline, err = spool.Open(filepath.Join(dir, "offline")); err != nil {
+			t.Fatalf("offline spool: %v", err)
+		}
+		h.Journal, h.Offline, h.OfflineStoreImages = requests, offline, true
+		return newRouter(h)
+	}
+	predict := func(r *gin.Engine, tenant string) (string, int) {
+		t.Helper()
+		upload := handlertest.Upload{Image: handlertest.PNG(t, 120, 200), Header: http.Header{"X-Tenant-Id": {tenant}}}
+		rec := handlertest.Do(r, upload.Request(t, http.MethodPost, "/api/v1/predict"))
+		var resp models.PredictionResponse
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode prediction: %v", err)
+			}
+		}
+		return resp.PredictionID, rec.Code
+	}
+	lookup := func(r *gin.Engine, tenant, id string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/predictions/"+id, nil)
+		req.Header.Set("X-Tenant-ID", tenant)
+		return handlertest.Do(r, req).Code
+	}
+	// plaintext reports whether a file under dir holds any of values.
+	plaintext := func(dir string, values ...string) bool {
+		t.Helper()
+		found := false
+		filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			for _, v := range values {
+				found = found || bytes.Contains(data, []byte(v))
+			}
+			return err
+		})
+		return found
+	}
+
+	r := open()
+	a, _ := predict(r, "clinic-a")
+	b, _ := predict(r, "clinic-b")
+	if plaintext(history, a, "clinic-b") {
+		t.Error("history holds plaintext records")
+	}
+	if plaintext(offline.Dir(), "clinic-a", "baseline_cnn_v2", "IHDR") {
+		t.Error("offline spool holds plaintext records")
+	}
+
+	// The offline spool is decrypted only to be sent.
+	var synced []spool.Record
+	central := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var env struct{ Record spool.Record }
+		json.NewDecoder(r.Body).Decode(&env)
+		synced = append(synced, env.Record)
+	}))
+	defer central.Close()
+	if n, err := spool.NewForwarder(offline, spool.ForwarderConfig{Endpoint: central.URL, Keys: keys}).SyncOnce(context.Background()); err != nil || n != 2 {
+		t.Fatalf("sync = %d, %v; want 2, nil", n, err)
+	}
+	for _, rec := range synced {
+		var got models.OfflinePredictionRecord
+		if err := json.Unmarshal(rec.Payload, &got); err != nil || got.PredictionID != rec.ID {
+			t.Errorf("synced record %s = %s, want the prediction", rec.ID, rec.Payload)
+		}
+	}
+
+	// So is a journal entry left by a request that was never answered.
+	entry, err := requests.Begin("clinic-a", "study.png", map[string]string{"accession_number": "ACC-77"}, []byte("study image"), true)
+	if err != nil {
+		t.Fatalf("journal request: %v", err)
+	}
+	requests.Finish(entry.ID, false)
+	if plaintext(filepath.Join(dir, "journal"), "ACC-77", "study.png", "study image") {
+		t.Error("journal holds plaintext entries")
+	}
+	if pending, err := requests.Pending(); err != nil || len(pending) != 1 || pending[0].Fields["accession_number"] != "ACC-77" {
+		t.Errorf("journal pending = %v, %v; want the entry", pending, err)
+	}
+	if image, err := requests.Image(entry.ID); err != nil || string(image) != "study image" {
+		t.Errorf("journal image = %q, %v; want the image", image, err)
+	}
+
+	// Withdrawing clinic-a's key hides its history from a new process
+	// and leaves clinic-b's alone.
+	keyA, err := os.ReadFile(filepath.Join(keyDir, "clinic-a.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(filepath.Join(keyDir, "clinic-a.key"))
+	r = open()
+	if code := lookup(r, "clinic-a", a); code != http.StatusNotFound {
+		t.Errorf("withdrawn key: lookup = %d, want 404", code)
+	}
+	if code := lookup(r, "clinic-b", b); code != http.StatusOK {
+		t.Errorf("other tenant: lookup = %d, want 200", code)
+	}
+	if pending, err := requests.Pending(); err != nil || len(pending) != 0 {
+		t.Errorf("withdrawn key: journal pending = %v, %v; want none", pending, err)
+	}
+
+	// Nor is a study of the tenant scored meanwhile, since it could not
+	// be kept.
+	if _, code := predict(r, "clinic-a"); code != http.StatusForbidden {
+		t.Errorf("withdrawn key: predict = %d, want 403", code)
+	}
+
+	// Restoring it brings the history back: nothing was destroyed.
+	os.WriteFile(filepath.Join(keyDir, "clinic-a.key"), keyA, 0o600)
+	r = open()
+	if code := lookup(r, "clinic-a", a); code != http.StatusOK {
+		t.Errorf("restored key: lookup = %d, want 200", code)
+	}
+
+	// A tenant without a key shares the default one only when asked to.
+	if _, code := predict(r, "clinic-c"); code != http.StatusForbidden {
+		t.Errorf("tenant without a key: predict = %d, want 403", code)
+	}
+	fallback = true
+	r = open()
+	if c, code := predict(r, "clinic-c"); code != http.StatusOK || lookup(r, "clinic-c", c) != http.StatusOK {
+		t.Errorf("default fallback: predict = %d; want the prediction stored", code)
+	}
+}
+
+func TestLegalHold(t *testing.T) {
+	h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+	path := filepath.Join(t.TempDir(), "holds.json")
+	holds, err := legalhold.NewRegistry(path)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	h.LegalHolds = holds
+	h.Events = events.NewBus()
+	var audited []string
+	h.Events.SubscribeSync("audit", func(ev events.Event) { audited = append(audited, ev.Type) },
+		events.LegalHoldPlaced, events.LegalHoldReleased, events.LegalHoldBlocked)
+	r := newRouter(h)
+	r.DELETE("/api/v1/predictions/:id", h.DeletePrediction)
+	r.DELETE("/admin/predictions/:id", h.PurgePrediction)
+	r.POST("/admin/legal-holds", h.PlaceLegalHold)
+	r.GET("/admin/legal-holds", h.ListLegalHolds)
+	r.POST("/admin/legal-holds/:id/release", h.ReleaseLegalHold)
+
+	upload := handlertest.Upload{Image: handlertest.PNG(t, 120, 200), Header: http.Header{"X-Tenant-Id": {"clinic-a"}}}
+	var pred models.PredictionResponse
+	if err := json.Unmarshal(handlertest.Do(r, upload.Request(t, http.MethodPost, "/api/v1/predict")).Body.Bytes(), &pred); err != nil {
+		t.Fatalf("decode prediction: %v", err)
+	}
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Tenant-ID", "clinic-a")
+		return handlertest.Do(r, req)
+	}
+
+	if rec := send(http.MethodPost, "/admin/legal-holds", `{"tenant":"clinic-a"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("hold without reason = %d, want 400", rec.Code)
+	}
+	rec := send(http.MethodPost, "/admin/legal-holds", `{"prediction_id":"`+pred.PredictionID+`","reason":"litigation","reference":"case 42"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("place hold = %d, want 201; body %s", rec.Code, rec.Body)
+	}
+	var hold legalhold.Hold
+	if err := json.Unmarshal(rec.Body.Bytes(), &hold); err != nil {
+		t.Fatalf("decode hold: %v", err)
+	}
+	if hold.Tenant != "clinic-a" {
+		t.Errorf("hold tenant = %q, want the prediction's", hold.Tenant)
+	}
+
+	// Held: neither deleted nor purged.
+	if rec := send(http.MethodDelete, "/api/v1/predictions/"+pred.PredictionID, ""); rec.Code != http.StatusConflict {
+		t.Errorf("delete held prediction = %d, want 409", rec.Code)
+	}
+	// Released: deleted and purged.
+	if rec := send(http.MethodPost, "/admin/legal-holds/"+hold.ID+"/release", `{"reason":"settled"}`); rec.Code != http.StatusOK {
+		t.Fatalf("release = %d, want 200; body %s", rec.Code, rec.Body)
+	}
+	if rec := send(http.MethodPost, "/admin/legal-holds/"+hold.ID+"/release", ""); rec.Code != http.StatusConflict {
+		t.Errorf("release twice = %d, want 409", rec.Code)
+	}
+	if rec := send(http.MethodDelete, "/api/v1/predictions/"+pred.PredictionID, ""); rec.Code != http.StatusOK {
+		t.Errorf("delete released prediction = %d, want 200", rec.Code)
+	}
+
+	// A tenant hold covers the tenant's deleted predictions too.
+	if rec := send(http.MethodPost, "/admin/legal-holds", `{"tenant":"clinic-a","reason":"audit"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("place tenant hold = %d, want 201", rec.Code)
+	}
+	if rec := send(http.MethodDelete, "/admin/predictions/"+pred.PredictionID, ""); rec.Code != http.StatusConflict {
+		t.Errorf("purge held prediction = %d, want 409", rec.Code)
+	}
+
+	// Listed a page at a time, released holds on request.
+	var page struct {
+		Holds      []legalhold.Hold `json:"legal_holds"`
+		NextCursor string           `json:"next_cursor"`
+	}
+	json.Unmarshal(send(http.MethodGet, "/admin/legal-holds?include_released=true&limit=1", "").Body.Bytes(), &page)
+	if len(page.Holds) != 1 || page.Holds[0].ID != hold.ID || page.NextCursor == "" {
+		t.Fatalf("first page = %+v, want the first hold and a cursor", page)
+	}
+	next := page.NextCursor
+	page.Holds, page.NextCursor = nil, ""
+	json.Unmarshal(send(http.MethodGet, "/admin/legal-holds?include_released=true&limit=1&cursor="+next, "").Body.Bytes(), &page)
+	if len(page.Holds) != 1 || page.Holds[0].Reason != "audit" || page.NextCursor != "" {
+		t.Errorf("second page = %+v, want the tenant hold and no cursor", page)
+	}
+	if rec := send(http.MethodGet, "/admin/legal-holds?filter=reason:eq:audit", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("filter on an undeclared field = %d, want 400", rec.Code)
+	}
+
+	// The holds survive a restart, released ones included.
+	reloaded, err := legalhold.NewRegistry(path)
+	if err != nil {
+		t.Fatalf("reload registry: %v", err)
+	}
+	if active, all := len(reloaded.List(false)), len(reloaded.List(true)); active != 1 || all != 2 {
+		t.Errorf("reloaded %d active of %d holds, want 1 of 2", active, all)
+	}
+	want := []string{events.LegalHoldPlaced, events.LegalHoldBlocked, events.LegalHoldReleased, events.LegalHoldPlaced, events.LegalHoldBlocked}
+	if !slices.Equal(audited, want) {
+		t.Errorf("audited %v, want %v", audited, want)
+	}
+}
+
+func TestRequestLog(t *testing.T) {
+	var logged bytes.Buffer
+	logHandler, err := logging.NewHandler(logging.JSON, &logged)
+	if err != nil {
+		t.Fatalf("log handler: %v", err)
+	}
+	h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+	r := gin.New()
+	r.Use(logging.Requests{Logger: slog.New(logHandler), Annotate: handlers.RequestAttrs}.Middleware())
+	r.POST("/api/v1/predict", h.Predict)
+
+	upload := handlertest.Upload{Image: handlertest.PNG(t, 120, 200), Header: http.Header{
+		"X-Tenant-Id":  {"clinic-a"},
+		"X-Request-Id": {"ris-4711"},
+	}}
+	rec := handlertest.Do(r, upload.Request(t, http.MethodPost, "/api/v1/predict"))
+	if got := rec.Header().Get(logging.RequestIDHeader); got != "ris-4711" {
+		t.Errorf("response request ID = %q, want the caller's", got)
+	}
+	var resp models.PredictionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode prediction: %v", err)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(logged.Bytes(), &entry); err != nil {
+		t.Fatalf("decode log record %q: %v", logged.String(), err)
+	}
+	want := map[string]any{
+		"msg":           "request",
+		"request_id":    "ris-4711",
+		"method":        "POST",
+		"path":          "/api/v1/predict",
+		"status":        float64(http.StatusOK),
+		"tenant":        "clinic-a",
+		"prediction_id": resp.PredictionID,
+		"prediction":    models.LabelCancer,
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("log %s = %v, want %v", k, entry[k], v)
+		}
+	}
+	if _, ok := entry["latency_ms"]; !ok {
+		t.Error("log record has no latency")
+	}
+
+	// A request ID unfit for the log is replaced.
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/predict", nil)
+	req.Header.Set(logging.RequestIDHeader, "forged\nline")
+	if got := handlertest.Do(r, req).Header().Get(logging.RequestIDHeader); got == "" || strings.Contains(got, "\n") {
+		t.Errorf("request ID = %q, want a fresh one", got)
+	}
+}
+
+func TestBreakGlass(t *testing.T) {
+	const apiKey = "er-key"
+	digest := sha256.Sum256([]byte(apiKey))
+	policy := filepath.Join(t.TempDir(), "policy.json")
+	err := os.WriteFile(policy, []byte(fmt.Sprintf(`{
+		"api_keys": [{"id": "dr-ed", "tenant": "t1", "roles": ["clinician"], "sha256": "%x"}],
+		"roles": {"clinician": {"rules": [
+			{"methods": ["GET"], "paths": ["/api/v1/predictions*"]},
+			{"methods": ["GET"], "paths": ["/api/v1/predictions/:id"], "tenants": ["*"], "break_glass": true}
+		]}}
+	}`, digest)), 0o600)
+	if err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	h := newTestHandler(t, &handlertest.FakeEngine{})
+	if h.Access, err = access.NewEngine(policy); err != nil {
+		t.Fatalf("access engine: %v", err)
+	}
+	h.Events = events.NewBus()
+	var granted []events.Event
+	h.Events.SubscribeSync("test", func(ev events.Event) { granted = append(granted, ev) }, events.BreakGlassAccess)
+	r := newRouter(h)
+
+	var rec models.StoredPrediction
+	rec.PredictionID, rec.Tenant, rec.CreatedAt = "p-berlin", "t2", time.Now().UTC()
+	if err := h.Store.Put(context.Background(), rec); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	rec.PredictionID, rec.Tenant = "p-own", "t1"
+	if err := h.Store.Put(context.Background(), rec); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	call := func(method, path, tenant, justification string) (int, string) {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("X-API-Key", apiKey)
+		req.Header.Set("X-Tenant-ID", tenant)
+		if justification != "" {
+			req.Header.Set("X-Break-Glass-Justification", justification)
+		}
+		resp := handlertest.Do(r, req)
+		var e models.ErrorResponse
+		json.Unmarshal(resp.Body.Bytes(), &e)
+		return resp.Code, e.Code
+	}
+
+	if code, errCode := call(http.MethodGet, "/api/v1/predictions/p-berlin", "t2", ""); code != http.StatusForbidden || errCode != "justification_required" {
+		t.Errorf("no justification: %d %s, want 403 justification_required", code, errCode)
+	}
+	if code, _ := call(http.MethodGet, "/api/v1/predictions/p-berlin", "t2", "urgent"); code != http.StatusBadRequest {
+		t.Errorf("short justification: %d, want 400", code)
+	}
+	// No break-glass rule covers feedback.
+	if code, errCode := call(http.MethodPost, "/api/v1/predictions/p-berlin/feedback", "t2", "patient transferred to our ED"); code != http.StatusForbidden || errCode != "forbidden" {
+		t.Errorf("uncovered route: %d %s, want 403 forbidden", code, errCode)
+	}
+	if len(granted) != 0 {
+		t.Fatalf("%d break-glass events before any access was granted", len(granted))
+	}
+
+	if code, _ := call(http.MethodGet, "/api/v1/predictions/p-berlin", "t2", "patient transferred to our ED"); code != http.StatusOK {
+		t.Fatalf("justified: %d, want 200", code)
+	}
+	if len(granted) != 1 {
+		t.Fatalf("%d break-glass events, want 1", len(granted))
+	}
+	bg := granted[0].Data.(events.BreakGlass)
+	if granted[0].Tenant != "t2" || bg.Principal != "dr-ed" || bg.Justification != "patient transferred to our ED" {
+		t.Errorf("event = %+v for tenant %q", bg, granted[0].Tenant)
+	}
+	// Ordinary access is not break-glass, justified or not.
+	if code, _ := call(http.MethodGet, "/api/v1/predictions/p-own", "t1", "patient transferred to our ED"); code != http.StatusOK || len(granted) != 1 {
+		t.Errorf("own tenant: %d with %d events, want 200 and no new event", code, len(granted))
+	}
+
+	// The grant is audited before it is made; one that cannot be is refused.
+	var audited []events.Event
+	h.Audit = func(ev events.Event) error {
+		audited = append(audited, ev)
+		return nil
+	}
+	if code, _ := call(http.MethodGet, "/api/v1/predictions/p-berlin", "t2", "patient transferred to our ED"); code != http.StatusOK {
+		t.Fatalf("audited: %d, want 200", code)
+	}
+	if len(audited) != 1 || len(granted) != 2 || audited[0].ID != granted[1].ID {
+		t.Errorf("audited %d, published %d; want the published event audited", len(audited), len(granted))
+	}
+	h.Audit = func(events.Event) error { return errors.New("disk full") }
+	if code, errCode := call(http.MethodGet, "/api/v1/predictions/p-berlin", "t2", "patient transferred to our ED"); code != http.StatusServiceUnavailable || errCode != "audit_unavailable" {
+		t.Errorf("audit down: %d %s, want 503 audit_unavailable", code, errCode)
+	}
+	if len(granted) != 2 {
+		t.Errorf("%d break-glass events, want no new event for a refused access", len(granted))
+	}
+}
+
+// API keys of the role policy installed by useRolePolicy, one per
+// built-in role.
+const (
+	adminKey    = "admin-key"
+	predictKey  = "predict-key"
+	readonlyKey = "readonly-key"
+)
+
+// useRolePolicy authorizes h's requests with a policy granting each of
+// adminKey, predictKey and readonlyKey the built-in role it names.
+func useRolePolicy(t *testing.T, h *handlers.Handler) {
+	t.Helper()
+	var keys []string
+	for _, k := range []struct{ key, role string }{{adminKey, "admin"}, {predictKey, "predict"}, {readonlyKey, "readonly"}} {
+		keys = append(keys, fmt.Sprintf(`{"id": %q, "tenant": "t1", "roles": [%q], "sha256": "%x"}`, k.role, k.role, sha256.Sum256([]byte(k.key))))
+	}
+	policy := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(policy, []byte(`{"api_keys": [`+strings.Join(keys, ", ")+`]}`), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	var err error
+	if h.Access, err = access.NewEngine(policy); err != nil {
+		t.Fatalf("access engine: %v", err)
+	}
+}
+
+// newAdminRouter is newRouter with the admin routes under test, which
+// cmd/api guards with the access policy.
+func newAdminRouter(h *handlers.Handler) *gin.Engine {
+	r := newRouter(h)
+	admin := r.Group("/admin", h.Authorize)
+	admin.GET("/profile", h.ProfileModel)
+	admin.GET("/model/slots", h.ModelSlots)
+	admin.POST("/model/standby", h.LoadStandbyModel)
+	admin.POST("/model/switch", h.SwitchModel)
+	admin.POST("/model/revert", h.RevertModel)
+	admin.GET("/models", h.ListModels)
+	admin.POST("/models/reload", h.ReloadModel)
+	admin.POST("/models/:version/activate", h.ActivateModel)
+	admin.GET("/jobs/:id", h.GetJob)
+	if h.Journal != nil {
+		admin.GET("/journal", h.ListJournal)
+		admin.POST("/journal/replay", h.ReplayJournal)
+		admin.DELETE("/journal/:id", h.DiscardJournalEntry)
+	}
+	return r
+}
+
+// adminCase is a call to an admin API. The cases of a test run in order
+// against one handler, each seeing the state the previous ones left.
+type adminCase struct {
+	name       string
+	key        string
+	method     string
+	path       string
+	body       string
+	wantStatus int
+	wantCode   string
+	// check, when set, inspects the response body of a successful call.
+	check func(t *testing.T, body []byte)
+}
+
+func runAdminCases(t *testing.T, r http.Handler, cases []adminCase) {
+	t.Helper()
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+			rec := handlertest.Do(r, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d; body %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantCode != "" {
+				var resp models.ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("decode error response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("code = %q, want %q (%s)", resp.Code, tt.wantCode, resp.Error)
+				}
+			}
+			if tt.check != nil && rec.Code < http.StatusMultipleChoices {
+				tt.check(t, rec.Body.Bytes())
+			}
+		})
+	}
+}
+
+// awaitJob polls the admin job accepted in body until it finishes and
+// decodes its result into result.
+func awaitJob(t *testing.T, r http.Handler, body []byte, wantStatus jobs.Status, result any) {
+	t.Helper()
+	var job jobs.Job
+	if err := json.Unmarshal(body, &job); err != nil {
+		t.Fatalf("decode job: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/admin/jobs/"+job.ID+"?wait=10s", nil)
+	req.Header.Set("X-API-Key", adminKey)
+	rec := handlertest.Do(r, req)
+	var done struct {
+		Status jobs.Status     `json:"status"`
+		Error  string          `json:"error"`
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &done); err != nil {
+		t.Fatalf("decode job: %v", err)
+	}
+	if done.Status != wantStatus {
+		t.Fatalf("job status = %s (%s), want %s", done.Status, done.Error, wantStatus)
+	}
+	if result != nil {
+		if err := json.Unmarshal(done.Result, result); err != nil {
+			t.Fatalf("decode job result %s: %v", done.Result, err)
+		}
+	}
+}
+
+// wantSlots checks the active and standby versions of a slots or
+// registry response; an empty standby means none.
+func wantSlots(active, standby string, retained ...string) func(*testing.T, []byte) {
+	return func(t *testing.T, body []byte) {
+		t.Helper()
+		var resp models.ModelRegistry
+		if err := json.Unmarshal(body, &resp); err != nil {
+			t.Fatalf("decode models: %v", err)
+		}
+		gotStandby := ""
+		if resp.Standby != nil {
+			gotStandby = resp.Standby.Version
+		}
+		var gotRetained []string
+		for _, m := range resp.Retained {
+			gotRetained = append(gotRetained, m.Version)
+		}
+		if resp.Active.Version != active || gotStandby != standby || !slices.Equal(gotRetained, retained) {
+			t.Errorf("active %q, standby %q, retained %v; want %q, %q, %v", resp.Active.Version, gotStandby, gotRetained, active, standby, retained)
+		}
+	}
+}
+
+func TestModelSlots(t *testing.T) {
+	h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+	h.Model.Version = "v1"
+	h.Jobs = jobs.NewManager(1)
+	h.LoadEngine = func(_ context.Context, ref string) (handlers.Predictor, error) {
+		if ref == "missing.onnx" {
+			return nil, errors.New("no such model")
+		}
+		return &handlertest.FakeEngine{Score: 0.2}, nil
+	}
+	useRolePolicy(t, h)
+	r := newAdminRouter(h)
+
+	runAdminCases(t, r, []adminCase{
+		{name: "no key", method: http.MethodGet, path: "/admin/model/slots", wantStatus: http.StatusUnauthorized, wantCode: "unauthenticated"},
+		{name: "readonly", key: readonlyKey, method: http.MethodGet, path: "/admin/model/slots", wantStatus: http.StatusForbidden, wantCode: "forbidden"},
+		{name: "predict switches", key: predictKey, method: http.MethodPost, path: "/admin/model/switch", wantStatus: http.StatusForbidden, wantCode: "forbidden"},
+		{name: "readonly loads", key: readonlyKey, method: http.MethodPost, path: "/admin/model/standby", body: `{"model_ref":"v2.onnx"}`, wantStatus: http.StatusForbidden, wantCode: "forbidden"},
+		{name: "slots", key: adminKey, method: http.MethodGet, path: "/admin/model/slots", wantStatus: http.StatusOK, check: wantSlots("v1", "")},
+		{name: "switch without standby", key: adminKey, method: http.MethodPost, path: "/admin/model/switch", wantStatus: http.StatusConflict, wantCode: "no_standby_model"},
+		{name: "load without ref", key: adminKey, method: http.MethodPost, path: "/admin/model/standby", body: `{}`, wantStatus: http.StatusBadRequest, wantCode: "invalid_request"},
+		{name: "load fails", key: adminKey, method: http.MethodPost, path: "/admin/model/standby", body: `{"model_ref":"missing.onnx","version":"v2"}`, wantStatus: http.StatusAccepted,
+			check: func(t *testing.T, body []byte) { awaitJob(t, r, body, jobs.StatusFailed, nil) }},
+		{name: "load", key: adminKey, method: http.MethodPost, path: "/admin/model/standby", body: `{"model_ref":"v2.onnx","version":"v2"}`, wantStatus: http.StatusAccepted,
+			check: func(t *testing.T, body []byte) {
+				var info models.ModelInfo
+				awaitJob(t, r, body, jobs.StatusSucceeded, &info)
+				if info.Version != "v2" || info.Source != "v2.onnx" {
+					t.Errorf("loaded %+v, want v2 from v2.onnx", info)
+				}
+			}},
+		{name: "loaded in standby", key: adminKey, method: http.MethodGet, path: "/admin/model/slots", wantStatus: http.StatusOK, check: wantSlots("v1", "v2")},
+		{name: "revert before a switch", key: adminKey, method: http.MethodPost, path: "/admin/model/revert", wantStatus: http.StatusConflict, wantCode: "nothing_to_revert"},
+		{name: "switch", key: adminKey, method: http.MethodPost, path: "/admin/model/switch", wantStatus: http.StatusOK, check: wantSlots("v2", "v1")},
+		{name: "revert", key: adminKey, method: http.MethodPost, path: "/admin/model/revert", wantStatus: http.StatusOK, check: wantSlots("v1", "v2")},
+	})
+}
+
+func TestModelRegistry(t *testing.T) {
+	h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+	h.Model.Version = "v1"
+	h.Jobs = jobs.NewManager(1)
+	h.RetainModels = 2
+	h.LoadEngine = func(context.Context, string) (handlers.Predictor, error) {
+		return &handlertest.FakeEngine{Score: 0.2}, nil
+	}
+	h.SwapModel(&handlertest.FakeEngine{}, models.ModelInfo{Version: "v2"})
+	h.SwapModel(&handlertest.FakeEngine{}, models.ModelInfo{Version: "v3"})
+	swaps, err := modelswap.NewScheduler(modelswap.Config{
+		Current: "v3",
+		Version: func(context.Context) (string, error) { return "v4", nil },
+		Swap: func(ctx context.Context, version string) error {
+			engine, info, err := h.LoadModel(ctx, version+".onnx", models.ModelInfo{Version: version})
+			if err != nil {
+				return err
+			}
+			h.SwapModel(engine, info)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("scheduler: %v", err)
+	}
+	h.ModelSwaps = swaps
+	useRolePolicy(t, h)
+	r := newAdminRouter(h)
+
+	runAdminCases(t, r, []adminCase{
+		{name: "no key", method: http.MethodGet, path: "/admin/models", wantStatus: http.StatusUnauthorized, wantCode: "unauthenticated"},
+		{name: "readonly", key: readonlyKey, method: http.MethodGet, path: "/admin/models", wantStatus: http.StatusForbidden, wantCode: "forbidden"},
+		{name: "predict activates", key: predictKey, method: http.MethodPost, path: "/admin/models/v1/activate", wantStatus: http.StatusForbidden, wantCode: "forbidden"},
+		{name: "predict reloads", key: predictKey, method: http.MethodPost, path: "/admin/models/reload", wantStatus: http.StatusForbidden, wantCode: "forbidden"},
+		{name: "list", key: adminKey, method: http.MethodGet, path: "/admin/models", wantStatus: http.StatusOK, check: wantSlots("v3", "v2", "v1")},
+		{name: "activate unknown", key: adminKey, method: http.MethodPost, path: "/admin/models/v9/activate", wantStatus: http.StatusNotFound, wantCode: "model_version_not_loaded"},
+		{name: "activate retained", key: adminKey, method: http.MethodPost, path: "/admin/models/v1/activate", wantStatus: http.StatusOK, check: wantSlots("v1", "v3")},
+		{name: "activated", key: adminKey, method: http.MethodGet, path: "/admin/models", wantStatus: http.StatusOK, check: wantSlots("v1", "v3", "v2")},
+		{name: "reload", key: adminKey, method: http.MethodPost, path: "/admin/models/reload", wantStatus: http.StatusAccepted,
+			check: func(t *testing.T, body []byte) {
+				var active models.ModelInfo
+				awaitJob(t, r, body, jobs.StatusSucceeded, &active)
+				if active.Version != "v4" {
+					t.Errorf("reload serves %q, want v4", active.Version)
+				}
+			}},
+		{name: "reloaded", key: adminKey, method: http.MethodGet, path: "/admin/models", wantStatus: http.StatusOK, check: wantSlots("v4", "v1", "v3", "v2")},
+	})
+
+	h.ModelSwaps = nil
+	runAdminCases(t, r, []adminCase{
+		{name: "reload without swaps", key: adminKey, method: http.MethodPost, path: "/admin/models/reload", wantStatus: http.StatusConflict, wantCode: "model_swaps_disabled"},
+	})
+}
+
+// profilingEngine is a FakeEngine that can profile its operators.
+type profilingEngine struct {
+	handlertest.FakeEngine
+}
+
+func (e *profilingEngine) Profile(_ tensor.Tensor, runs int) (*inference.Profile, error) {
+	return &inference.Profile{Runs: runs, Operators: []inference.OperatorTiming{{Op: "conv"}}}, nil
+}
+
+func TestProfileModel(t *testing.T) {
+	tests := []struct {
+		name       string
+		engine     handlers.Predictor
+		key        string
+		query      string
+		wantStatus int
+		wantCode   string
+		wantRuns   int
+	}{
+		{name: "no key", engine: &profilingEngine{}, wantStatus: http.StatusUnauthorized, wantCode: "unauthenticated"},
+		{name: "readonly", engine: &profilingEngine{}, key: readonlyKey, wantStatus: http.StatusForbidden, wantCode: "forbidden"},
+		{name: "predict", engine: &profilingEngine{}, key: predictKey, wantStatus: http.StatusForbidden, wantCode: "forbidden"},
+		{name: "default runs", engine: &profilingEngine{}, key: adminKey, wantStatus: http.StatusOK, wantRuns: 3},
+		{name: "runs", engine: &profilingEngine{}, key: adminKey, query: "?runs=5", wantStatus: http.StatusOK, wantRuns: 5},
+		{name: "too many runs", engine: &profilingEngine{}, key: adminKey, query: fmt.Sprintf("?runs=%d", inference.MaxProfileRuns+1), wantStatus: http.StatusBadRequest, wantCode: "invalid_request"},
+		{name: "no profiler", engine: &handlertest.FakeEngine{}, key: adminKey, wantStatus: http.StatusNotImplemented, wantCode: "profiling_unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, tt.engine)
+			useRolePolicy(t, h)
+			runAdminCases(t, newAdminRouter(h), []adminCase{{
+				name: "profile", key: tt.key, method: http.MethodGet, path: "/admin/profile" + tt.query,
+				wantStatus: tt.wantStatus, wantCode: tt.wantCode,
+				check: func(t *testing.T, body []byte) {
+					var resp struct {
+						Profile inference.Profile `json:"profile"`
+					}
+					if err := json.Unmarshal(body, &resp); err != nil {
+						t.Fatalf("decode profile: %v", err)
+					}
+					if resp.Profile.Runs != tt.wantRuns || len(resp.Profile.Operators) != 1 {
+						t.Errorf("profile = %+v, want %d runs of the engine's operators", resp.Profile, tt.wantRuns)
+					}
+				},
+			}})
+		})
+	}
+}
+
+func TestJournalAdmin(t *testing.T) {
+	h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+	h.Jobs = jobs.NewManager(1)
+	dir := t.TempDir()
+	crashed, err := journal.Open(dir, nil)
+	if err != nil {
+		t.Fatalf("journal: %v", err)
+	}
+	kept, err := crashed.Begin("t1", "kept.png", nil, handlertest.PNG(t, 120, 200), true)
+	if err != nil {
+		t.Fatalf("journal entry: %v", err)
+	}
+	refOnly, err := crashed.Begin("t1", "ref-only.png", map[string]string{"accession_number": "ACC-9"}, handlertest.PNG(t, 120, 200), false)
+	if err != nil {
+		t.Fatalf("journal entry: %v", err)
+	}
+	// The process handling them is gone; a restarted one finds them pending.
+	if h.Journal, err = journal.Open(dir, nil); err != nil {
+		t.Fatalf("reopen journal: %v", err)
+	}
+	useRolePolicy(t, h)
+	r := newAdminRouter(h)
+	wantPending := func(ids ...string) func(*testing.T, []byte) {
+		return func(t *testing.T, body []byte) {
+			var resp models.JournalResponse
+			if err := json.Unmarshal(body, &resp); err != nil {
+				t.Fatalf("decode journal: %v", err)
+			}
+			var got []string
+			for _, e := range resp.Entries {
+				got = append(got, e.ID)
+			}
+			slices.Sort(got)
+			slices.Sort(ids)
+			if !slices.Equal(got, ids) {
+				t.Errorf("pending %v, want %v", got, ids)
+			}
+		}
+	}
+
+	runAdminCases(t, r, []adminCase{
+		{name: "no key", method: http.MethodGet, path: "/admin/journal", wantStatus: http.StatusUnauthorized, wantCode: "unauthenticated"},
+		{name: "readonly", key: readonlyKey, method: http.MethodGet, path: "/admin/journal", wantStatus: http.StatusForbidden, wantCode: "forbidden"},
+		{name: "predict replays", key: predictKey, method: http.MethodPost, path: "/admin/journal/replay", wantStatus: http.StatusForbidden, wantCode: "forbidden"},
+		{name: "readonly discards", key: readonlyKey, method: http.MethodDelete, path: "/admin/journal/" + refOnly.ID, wantStatus: http.StatusForbidden, wantCode: "forbidden"},
+		{name: "list", key: adminKey, method: http.MethodGet, path: "/admin/journal", wantStatus: http.StatusOK, check: wantPending(kept.ID, refOnly.ID)},
+		{name: "replay", key: adminKey, method: http.MethodPost, path: "/admin/journal/replay", wantStatus: http.StatusAccepted,
+			check: func(t *testing.T, body []byte) {
+				var result models.JournalReplayResult
+				awaitJob(t, r, body, jobs.StatusSucceeded, &result)
+				if result.Replayed != 1 || result.Failed != 0 || len(result.Resubmit) != 1 || result.Resubmit[0].ID != refOnly.ID {
+					t.Errorf("replay = %+v, want the kept entry replayed and %s to resubmit", result, refOnly.ID)
+				}
+			}},
+		{name: "replayed", key: adminKey, method: http.MethodGet, path: "/admin/journal", wantStatus: http.StatusOK, check: wantPending(refOnly.ID)},
+		{name: "discard", key: adminKey, method: http.MethodDelete, path: "/admin/journal/" + refOnly.ID, wantStatus: http.StatusNoContent},
+		{name: "discarded", key: adminKey, method: http.MethodGet, path: "/admin/journal", wantStatus: http.StatusOK, check: wantPending()},
+	})
+}
+
+// newPredictionService returns the prediction RPC service scoring through
+// r, as cmd/api wires it.
+func newPredictionService(r http.Handler) *grpcapi.Server {
+	return grpcapi.New(func(ctx context.Context, header http.Header, remoteAddr, filename string, image []byte, fields map[string]string) (int, []byte) {
+		return handlers.ServeUpload(ctx, r, "/api/v1/predict", header, remoteAddr, filename, image, fields)
+	}, 2)
+}
+
+func TestConnectPredictionService(t *testing.T) {
+	h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+	path, svc := newPredictionService(newRouter(h)).ConnectHandler()
+	mux := http.NewServeMux()
+	mux.Handle(path, svc)
+	// Bidirectional streams need HTTP/2.
+	ts := httptest.NewUnstartedServer(mux)
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+	study := handlertest.PNG(t, 64, 64)
+
+	protocols := []struct {
+		name string
+		opts []connect.ClientOption
+	}{
+		{"connect", nil},
+		{"connect json", []connect.ClientOption{connect.WithProtoJSON()}},
+		{"grpc", []connect.ClientOption{connect.WithGRPC()}},
+		{"grpc-web", []connect.ClientOption{connect.WithGRPCWeb()}},
+	}
+	for _, p := range protocols {
+		t.Run(p.name, func(t *testing.T) {
+			client := mammoscanv1connect.NewPredictionServiceClient(ts.Client(), ts.URL, p.opts...)
+			ctx := context.Background()
+
+			resp, err := client.Predict(ctx, connect.NewRequest(&mammoscanv1.PredictRequest{Image: study, ClientReference: "ref-1"}))
+			if err != nil {
+				t.Fatalf("Predict: %v", err)
+			}
+			if resp.Msg.GetPredictionId() == "" || resp.Msg.GetPrediction() != "Cancer" || resp.Msg.GetClientReference() != "ref-1" {
+				t.Errorf("Predict = %+v, want a stored Cancer prediction echoing ref-1", resp.Msg)
+			}
+
+			_, err = client.Predict(ctx, connect.NewRequest(&mammoscanv1.PredictRequest{}))
+			if connect.CodeOf(err) != connect.CodeInvalidArgument || connectReason(err) != "image_required" {
+				t.Errorf("Predict without image: %v (reason %q), want invalid_argument image_required", err, connectReason(err))
+			}
+
+			stream := client.PredictStream(ctx)
+			for i, req := range []*mammoscanv1.PredictRequest{{Image: study}, {}} {
+				if err := stream.Send(&mammoscanv1.PredictStreamRequest{Sequence: int64(i + 1), Study: req}); err != nil {
+					t.Fatalf("send %d: %v", i+1, err)
+				}
+			}
+			if err := stream.CloseRequest(); err != nil {
+				t.Fatalf("close request: %v", err)
+			}
+			answers := make(map[int64]*mammoscanv1.PredictStreamResponse)
+			for range 2 {
+				out, err := stream.Receive()
+				if err != nil {
+					t.Fatalf("receive: %v", err)
+				}
+				answers[out.GetSequence()] = out
+			}
+			if err := stream.CloseResponse(); err != nil {
+				t.Fatalf("close response: %v", err)
+			}
+			if answers[1].GetPrediction().GetPrediction() != "Cancer" {
+				t.Errorf("study 1 = %+v, want a Cancer prediction", answers[1])
+			}
+			if answers[2].GetError().GetCode() != "image_required" || answers[2].GetError().GetStatus() != int32(connect.CodeInvalidArgument) {
+				t.Errorf("study 2 = %+v, want an image_required error", answers[2])
+			}
+		})
+	}
+}
+
+func TestGRPCPredictionService(t *testing.T) {
+	h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+	listener := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	mammoscanv1.RegisterPredictionServiceServer(s, newPredictionService(newRouter(h)))
+	go s.Serve(listener)
+	defer s.Stop()
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	client := mammoscanv1.NewPredictionServiceClient(conn)
+
+	resp, err := client.Predict(context.Background(), &mammoscanv1.PredictRequest{Image: handlertest.PNG(t, 64, 64), AccessionNumber: "ACC-7"})
+	if err != nil {
+		t.Fatalf("Predict: %v", err)
+	}
+	if resp.GetPredictionId() == "" || resp.GetPrediction() != "Cancer" || resp.GetAccessionNumber() != "ACC-7" {
+		t.Errorf("Predict = %+v, want a stored Cancer prediction echoing ACC-7", resp)
+	}
+	_, err = client.Predict(context.Background(), &mammoscanv1.PredictRequest{})
+	if st := status.Convert(err); st.Code() != codes.InvalidArgument {
+		t.Errorf("Predict without image: %v, want InvalidArgument", err)
+	}
+}
+
+func TestGRPCPredictionServiceAccess(t *testing.T) {
+	h := newTestHandler(t, &handlertest.FakeEngine{Score: 0.9})
+	useRolePolicy(t, h)
+	listener := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	mammoscanv1.RegisterPredictionServiceServer(s, newPredictionService(newRouter(h)))
+	go s.Serve(listener)
+	defer s.Stop()
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	client := mammoscanv1.NewPredictionServiceClient(conn)
+	study := handlertest.PNG(t, 64, 64)
+
+	tests := []struct {
+		name     string
+		key      string
+		wantCode codes.Code
+	}{
+		{name: "no key", wantCode: codes.Unauthenticated},
+		{name: "unknown key", key: "not-a-key", wantCode: codes.Unauthenticated},
+		{name: "readonly", key: readonlyKey, wantCode: codes.PermissionDenied},
+		{name: "predict", key: predictKey, wantCode: codes.OK},
+		{name: "admin", key: adminKey, wantCode: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.key != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", tt.key)
+			}
+			resp, err := client.Predict(ctx, &mammoscanv1.PredictRequest{Image: study})
+			if st := status.Convert(err); st.Code() != tt.wantCode {
+				t.Fatalf("Predict: %v, want %s", err, tt.wantCode)
+			}
+			if tt.wantCode == codes.OK && resp.GetPrediction() != "Cancer" {
+				t.Errorf("Predict = %+v, want a Cancer prediction", resp)
+			}
+		})
+	}
+}
+
+// connectReason returns the REST error code carried by a Connect error.
+func connectReason(err error) string {
+	var ce *connect.Error
+	if !errors.As(err, &ce) {
+		return ""
+	}
+	for _, d := range ce.Details() {
+		if v, derr := d.Value(); derr == nil {
+			if info, ok := v.(*errdetails.ErrorInfo); ok && info.GetDomain() == grpcapi.ErrorDomain {
+				return info.GetReason()
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,204 @@
+// backend/internal/handlers/handlertest/handlertest.go
+/*
+ * This package provides helpers for testing the HTTP handlers.
+ *
+ *   - FakeEngine stands in for the ONNX model with a fixed score or error.
+ *   - PNG synthesises a small mammogram-like image.
+ *   - Upload builds multipart predict requests.
+ *   - Golden compares a JSON response with testdata/<name>.golden.json,
+ *     masking values that change between runs. Run the tests with
+ *     -update to (re)write the golden files.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"gorgonia.org/tensor"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// FakeEngine is an inference engine returning Score, or Err if set.
+type FakeEngine struct {
+	Score float32
+	Err   error
+
+	mu    sync.Mutex
+	calls int
+}
+
+// Predict records the call and returns the configured result.
+func (f *FakeEngine) Predict(tensor.Tensor) ([]float32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return []float32{f.Score}, nil
+}
+
+// Calls reports how many times Predict ran.
+func (f *FakeEngine) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+// PNG encodes a grey portrait image with a bright tissue region against
+// the left edge, which passes the decode, orientation and laterality
+// steps the way a real film would.
+func PNG(t testing.TB, width, height int) []byte {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			v := 0
+			if x < width/2 {
+				v = 200 - 150*x/(width/2)
+			}
+			img.SetGray(x, y, color.Gray{Y: uint8(v)})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// SolidPNG encodes a grey image of a single shade.
+func SolidPNG(t testing.TB, width, height int, shade uint8) []byte {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = shade
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// Upload describes a multipart upload. Image is sent in the "image" field
+// unless it is nil.
+type Upload struct {
+	Filename string
+	Image    []byte
+	Fields   map[string]string
+	Header   http.Header
+}
+
+// Request builds the multipart request.
+func (u Upload) Request(t testing.TB, method, target string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for k, v := range u.Fields {
+		if err := w.WriteField(k, v); err != nil {
+			t.Fatalf("write field %s: %v", k, err)
+		}
+	}
+	if u.Image != nil {
+		name := u.Filename
+		if name == "" {
+			name = "study.png"
+		}
+		part, err := w.CreateFormFile("image", name)
+		if err != nil {
+			t.Fatalf("create form file: %v", err)
+		}
+		part.Write(u.Image)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close multipart writer: %v", err)
+	}
+
+	req := httptest.NewRequest(method, target, &body)
+	for k, vs := range u.Header {
+		req.Header[k] = vs
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+// Do serves req and returns the recorded response.
+func Do(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// Golden compares the JSON document body with testdata/<name>.golden.json.
+// Values of the volatile keys (at any depth) are masked on both sides.
+func Golden(t testing.TB, name string, body []byte, volatile ...string) {
+	t.Helper()
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("response is not JSON: %v\n%s", err, body)
+	}
+	mask(doc, volatile)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		t.Fatalf("re-encode response: %v", err)
+	}
+	got := buf.Bytes()
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("response does not match %s (run with -update to accept)\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func mask(v any, keys []string) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if slices.Contains(keys, k) {
+				v[k] = "<volatile>"
+			} else {
+				mask(child, keys)
+			}
+		}
+	case []any:
+		for _, child := range v {
+			mask(child, keys)
+		}
+	}
+}
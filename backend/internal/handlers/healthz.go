@@ -0,0 +1,48 @@
+// backend/internal/handlers/healthz.go
+/*
+ * This file contains the dependency health report.
+ *
+ *   GET /healthz
+ *
+ * lists the downstream dependencies (the prediction database, webhook
+ * endpoints) with the state of their circuit breakers:
+ *
+ *   {"status": "DEGRADED", "dependencies": [{"name": "prediction_db",
+ *    "state": "open", "healthy": false, "consecutive_failures": 5,
+ *    "last_error": "context deadline exceeded", ...}]}
+ *
+ * Predictions do not need any of them, so an unhealthy dependency makes
+ * the service DEGRADED, never down: the answer is always 200 and
+ * orchestrators keep probing /healthy and /ready.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// Healthz reports the service's health with its dependencies.
+func (h *Handler) Healthz(c *gin.Context) {
+	resp := models.HealthStatus{Status: "OK", Dependencies: h.Dependencies.Status()}
+	if age := h.ModelAge(time.Now()); age != nil && age.Stale {
+		resp.Status = "DEGRADED"
+		resp.Warnings = append(resp.Warnings, staleWarning(age))
+	}
+	for _, d := range resp.Dependencies {
+		if !d.Healthy {
+			resp.Status = "DEGRADED"
+			resp.Warnings = append(resp.Warnings, fmt.Sprintf("dependency %s is unhealthy (circuit %s)", d.Name, d.State))
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,108 @@
+// backend/internal/handlers/i18n.go
+/*
+ * This file localizes error responses.
+ *
+ * Handlers always write English messages. The Localize middleware rewrites
+ * the `error` field of JSON error responses into the language negotiated
+ * from Accept-Language, keyed by the error code. The code itself is never
+ * changed, and the English text is kept in `detail`.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"golang.org/x/text/language"
+)
+
+// Localize is a middleware that translates error messages. It is a no-op
+// when no message catalog is configured.
+func (h *Handler) Localize(c *gin.Context) {
+	if h.Messages == nil {
+		c.Next()
+		return
+	}
+	c.Header("Vary", "Accept-Language")
+	w := &localizingWriter{ResponseWriter: c.Writer}
+	c.Writer = w
+	c.Next()
+	if w.buffering {
+		w.flush(h, c.GetHeader("Accept-Language"))
+	}
+}
+
+// localize translates resp into the caller's preferred language.
+func (h *Handler) localize(c *gin.Context, resp models.ErrorResponse) models.ErrorResponse {
+	if h.Messages == nil {
+		return resp
+	}
+	lang := h.Messages.Negotiate(c.GetHeader("Accept-Language"))
+	return translate(h, lang, resp)
+}
+
+func translate(h *Handler, lang language.Tag, resp models.ErrorResponse) models.ErrorResponse {
+	msg, ok := h.Messages.Message(lang, resp.Code)
+	if !ok {
+		return resp
+	}
+	resp.Detail, resp.Error = resp.Error, msg
+	return resp
+}
+
+// localizingWriter holds back JSON error bodies so they can be translated;
+// every other response is passed straight through, which keeps streaming
+// endpoints streaming.
+type localizingWriter struct {
+	gin.ResponseWriter
+	decided   bool
+	buffering bool
+	buf       bytes.Buffer
+}
+
+func (w *localizingWriter) decide() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	w.buffering = w.Status() >= http.StatusBadRequest &&
+		strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+}
+
+func (w *localizingWriter) Write(b []byte) (int, error) {
+	if w.decide(); w.buffering {
+		return w.buf.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *localizingWriter) WriteString(s string) (int, error) {
+	if w.decide(); w.buffering {
+		return w.buf.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *localizingWriter) flush(h *Handler, acceptLanguage string) {
+	body := w.buf.Bytes()
+	var resp models.ErrorResponse
+	if err := json.Unmarshal(body, &resp); err == nil && resp.Code != "" {
+		lang := h.Messages.Negotiate(acceptLanguage)
+		if translated := translate(h, lang, resp); translated != resp {
+			if localized, err := json.Marshal(translated); err == nil {
+				body = localized
+				w.Header().Set("Content-Language", lang.String())
+			}
+		}
+	}
+	w.ResponseWriter.Write(body)
+}
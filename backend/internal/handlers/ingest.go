@@ -0,0 +1,99 @@
+// backend/internal/handlers/ingest.go
+/*
+ * This file lets non-HTTP ingestion paths (drop folders, gRPC, ...)
+ * submit a study through exactly the same pipeline as POST /api/v1/predict.
+ *
+ * The file is sent as a multipart request to a private router that only
+ * serves Predict, so validation, storage, events and the response format
+ * cannot drift from the HTTP API. Authentication is skipped: the caller is
+ * the service itself, acting for the tenant it was configured with.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Ingest scores one file on behalf of tenant and returns the HTTP status
+// and JSON body that POST /api/v1/predict would have produced. fields are
+// sent as additional form fields (e.g. accession_number).
+func (h *Handler) Ingest(ctx context.Context, tenant, filename string, data []byte, fields map[string]string) (int, []byte) {
+	header := make(http.Header)
+	if tenant != "" {
+		header.Set(tenantHeader, tenant)
+	}
+	return h.ingest(ctx, header, filename, data, fields)
+}
+
+// ingest is Ingest with the request headers given, e.g. to carry the
+// caller's tenant and language.
+func (h *Handler) ingest(ctx context.Context, header http.Header, filename string, data []byte, fields map[string]string) (int, []byte) {
+	// Building the router is cheap next to inference, so it is not cached.
+	r := gin.New()
+	r.Use(gin.Recovery(), h.Localize)
+	r.POST("/predict", h.Predict)
+	return ServeUpload(ctx, r, "/predict", header, "", filename, data, fields)
+}
+
+// ServeUpload sends a study to router as a multipart POST to path, from
+// remoteAddr with header, and returns the response's status and body.
+// Other APIs (gRPC) use it to submit studies through the API router, and
+// with it its authentication and access control.
+func ServeUpload(ctx context.Context, router http.Handler, path string, header http.Header, remoteAddr, filename string, data []byte, fields map[string]string) (int, []byte) {
+	body, w := io.Pipe()
+	form := multipart.NewWriter(w)
+	go func() {
+		for k, v := range fields {
+			if err := form.WriteField(k, v); err != nil {
+				w.CloseWithError(err)
+				return
+			}
+		}
+		part, err := form.CreateFormFile("image", filename)
+		if err == nil {
+			_, err = part.Write(data)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		w.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, path, body)
+	if err != nil {
+		body.Close()
+		return http.StatusInternalServerError, nil
+	}
+	req.Header = header.Clone()
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if remoteAddr != "" {
+		req.RemoteAddr = remoteAddr
+	}
+
+	rec := &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+	router.ServeHTTP(rec, req)
+	body.Close()
+	return rec.status, rec.body.Bytes()
+}
+
+// bufferedResponse is a minimal in-memory http.ResponseWriter.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *bufferedResponse) Header() http.Header         { return r.header }
+func (r *bufferedResponse) Write(b []byte) (int, error) { return r.body.Write(b) }
+func (r *bufferedResponse) WriteHeader(status int)      { r.status = status }
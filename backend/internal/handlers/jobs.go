@@ -0,0 +1,228 @@
+// backend/internal/handlers/jobs.go
+/*
+ * This file contains the admin job handlers.
+ *
+ *   POST /admin/jobs/rescore       start a bulk re-scoring job
+ *   POST /admin/jobs/calibration   fit per-site calibration from feedback
+ *   GET  /admin/jobs               list jobs
+ *   GET  /admin/jobs/:id           job status and result (?format=csv for
+ *                                  the re-scoring comparison table)
+ *
+ * Prediction jobs (see predictjobs.go) are listed and polled the same
+ * way. Clients without webhooks or SSE can long-poll a job instead:
+ *
+ *   GET /api/v1/jobs/:id?wait=30s
+ *
+ * blocks until the job finishes or the wait elapses (at most a minute),
+ * then returns its state as usual.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/calibration"
+	"github.com/josephed37/mammoscan-AI/backend/internal/fairness"
+	"github.com/josephed37/mammoscan-AI/backend/internal/jobs"
+	"github.com/josephed37/mammoscan-AI/backend/internal/listing"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/rescore"
+)
+
+// StartRescore launches a job that re-runs a cohort of stored images
+// through a candidate model and compares old and new scores.
+func (h *Handler) StartRescore(c *gin.Context) {
+	if h.Images == nil {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error: "image retention is disabled; there are no stored images to re-score",
+			Code:  "images_not_retained",
+		})
+		return
+	}
+
+	var params rescore.Params
+	if err := c.ShouldBindJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error(), Code: "invalid_request"})
+		return
+	}
+	if params.ModelName == "" {
+		params.ModelName = params.ModelRef
+	}
+	deps := rescore.Deps{Store: h.Store, Images: h.Images, DecodeOptions: h.DecodeOptions}
+	job := h.Jobs.Submit(context.Background(), "rescore", c.GetHeader(tenantHeader), func(ctx context.Context, report func(float64)) (any, error) {
+		// The candidate model is loaded inside the job: multi-GB models
+		// can take minutes to fetch and must not hold the request open.
+		engine, err := h.LoadEngine(ctx, params.ModelRef)
+		if err != nil {
+			return nil, fmt.Errorf("load candidate model: %w", err)
+		}
+		if params.Threshold == 0 {
+			params.Threshold = h.thresholdFor(ctx, params.ModelRef, models.ModelInfo{Name: params.ModelName})
+		}
+		return rescore.Run(ctx, params, engine, deps, report)
+	})
+	c.JSON(http.StatusAccepted, job)
+}
+
+// StartCalibration launches a job that fits a per-site (or per-scanner)
+// calibration correction from reviewer feedback. The job result is a
+// proposal for an admin to review; nothing is applied.
+func (h *Handler) StartCalibration(c *gin.Context) {
+	var params calibration.Params
+	if err := c.ShouldBindJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error(), Code: "invalid_request"})
+		return
+	}
+	if !slices.Contains(fairness.Dimensions, params.Dimension) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: fmt.Sprintf("dimension must be one of %v", fairness.Dimensions),
+			Code:  "invalid_request",
+		})
+		return
+	}
+	_, served := h.ServedModel()
+	if params.ModelName == "" {
+		params.ModelName = served.Name
+	}
+	if params.Threshold == 0 {
+		params.Threshold = decisionThreshold(served)
+	}
+
+	job := h.Jobs.Submit(context.Background(), "calibration", c.GetHeader(tenantHeader), func(ctx context.Context, report func(float64)) (any, error) {
+		return calibration.Run(ctx, params, h.Store, report)
+	})
+	c.JSON(http.StatusAccepted, job)
+}
+
+// jobListing declares the filterable job fields. The managers keep jobs
+// in creation order, the one order they are listed in.
+var jobListing = listing.Spec[jobs.Job]{
+	Fields: map[string]listing.Field[jobs.Job]{
+		"id":         {Kind: listing.String, Get: func(j jobs.Job) any { return j.ID }},
+		"type":       {Kind: listing.String, Get: func(j jobs.Job) any { return j.Type }},
+		"status":     {Kind: listing.String, Get: func(j jobs.Job) any { return string(j.Status) }},
+		"tenant":     {Kind: listing.String, Get: func(j jobs.Job) any { return j.Tenant }},
+		"created_at": {Kind: listing.Time, Get: func(j jobs.Job) any { return j.CreatedAt }},
+		"progress":   {Kind: listing.Number, Get: func(j jobs.Job) any { return j.Progress }},
+	},
+	ID:           func(j jobs.Job) string { return j.ID },
+	Sortable:     []string{"created_at"},
+	DefaultSort:  "-created_at",
+	DefaultLimit: 50,
+	MaxLimit:     100,
+}
+
+// ListJobs lists known jobs using the shared sort/filter/cursor grammar.
+func (h *Handler) ListJobs(c *gin.Context) {
+	query, err := listing.Parse(c.Request.URL.Query(), jobListing)
+	if err != nil {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_list_parameters", err.Error())
+		return
+	}
+	from, fromID, err := listing.From(query, jobListing)
+	if err != nil {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_list_parameters", err.Error())
+		return
+	}
+
+	// Each manager pages its own jobs; one extra job tells whether there
+	// is a next page.
+	q := jobs.Query{
+		Match:       listing.Matcher(query, jobListing),
+		OldestFirst: len(query.Sort) > 0 && !query.Sort[0].Desc,
+		Limit:       query.Limit + 1,
+	}
+	if tenant := c.GetHeader(tenantHeader); tenant != "" {
+		match := q.Match
+		q.Match = func(j jobs.Job) bool { return j.Tenant == tenant && (match == nil || match(j)) }
+	}
+	if len(from) > 0 {
+		q.After = &jobs.Job{CreatedAt: from[0].(time.Time), ID: fromID}
+	}
+	found := h.Jobs.Find(q)
+	if h.PredictionJobs != nil {
+		found = append(found, h.PredictionJobs.Find(q)...)
+		slices.SortFunc(found, func(a, b jobs.Job) int {
+			c := a.CreatedAt.Compare(b.CreatedAt)
+			if c == 0 {
+				c = strings.Compare(a.ID, b.ID)
+			}
+			if !q.OldestFirst {
+				c = -c
+			}
+			return c
+		})
+		if len(found) > q.Limit {
+			found = found[:q.Limit]
+		}
+	}
+	page := listing.PageOf(found, query, jobListing)
+	c.JSON(http.StatusOK, models.JobListResponse{Jobs: page.Items, NextCursor: page.NextCursor})
+}
+
+// maxJobWait caps how long a long-poll request may block.
+const maxJobWait = time.Minute
+
+// GetJob returns a job's status and, once finished, its result. With
+// `?wait=<duration>` it first waits for the job to finish.
+func (h *Handler) GetJob(c *gin.Context) {
+	var wait time.Duration
+	if v := c.Query("wait"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			// A bare number is taken as seconds.
+			secs, convErr := strconv.Atoi(v)
+			if convErr != nil {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "wait must be a duration such as 30s", Code: "invalid_request"})
+				return
+			}
+			d = time.Duration(secs) * time.Second
+		}
+		wait = min(max(d, 0), maxJobWait)
+	}
+
+	manager, job, err := h.findJob(c.Param("id"))
+	// Jobs started for another tenant are not revealed.
+	if tenant := c.GetHeader(tenantHeader); err == nil && tenant != "" && job.Tenant != tenant {
+		err = jobs.ErrNotFound
+	}
+	if err == nil && wait > 0 && !job.Done() {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), wait)
+		job, err = manager.Wait(ctx, job.ID)
+		cancel()
+	}
+	if errors.Is(err, jobs.ErrNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: err.Error(), Code: "job_not_found"})
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		cmp, ok := job.Result.(*rescore.Comparison)
+		if !ok {
+			c.JSON(http.StatusConflict, models.ErrorResponse{Error: "job has no comparison table", Code: "no_csv_result"})
+			return
+		}
+		body, err := cmp.CSV()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error(), Code: "internal_error"})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="rescore-%s.csv"`, job.ID))
+		c.Data(http.StatusOK, "text/csv", body)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,164 @@
+// backend/internal/handlers/journal.go
+/*
+ * This file connects prediction requests to the disaster-recovery
+ * journal and contains its admin APIs.
+ *
+ *   GET    /admin/journal          requests received but never answered
+ *   POST   /admin/journal/replay   re-run those that kept their image (job)
+ *   DELETE /admin/journal/:id      drop an entry handled by hand
+ *
+ * A request is removed from the journal once it has been answered, unless
+ * the answer is a server error: a study that failed on our side is still
+ * to be processed. The image is journaled only when the submitter sets
+ * `journal_consent=true`; other entries list the references needed to
+ * resubmit the study. A journaling failure is logged and never blocks the
+ * prediction.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/journal"
+	"github.com/josephed37/mammoscan-AI/backend/internal/listing"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// replayKey marks, in the request context, a replay of the journal entry
+// whose ID it holds.
+type replayKey struct{}
+
+// journalRequest journals the received upload and returns the function
+// that closes the entry once the response is written; nil without a
+// journal.
+func (h *Handler) journalRequest(c *gin.Context, up *upload) func() {
+	if h.Journal == nil {
+		return nil
+	}
+	id, replay := c.Request.Context().Value(replayKey{}).(string)
+	if !replay {
+		fields := make(map[string]string, len(c.Request.PostForm))
+		for k, v := range c.Request.PostForm {
+			if len(v) > 0 {
+				fields[k] = v[0]
+			}
+		}
+		consent, _ := strconv.ParseBool(fields["journal_consent"])
+		e, err := h.Journal.Begin(c.GetHeader(tenantHeader), up.filename, fields, up.image, consent)
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "journal: begin entry failed", "tenant", c.GetHeader(tenantHeader), "error", err)
+			return nil
+		}
+		id = e.ID
+	}
+	return func() {
+		// A panic unwinds through here before the recovery middleware
+		// writes its 500, so an unwritten response is a failure too.
+		processed := c.Writer.Written() && c.Writer.Status() < http.StatusInternalServerError
+		if err := h.Journal.Finish(id, processed); err != nil {
+			slog.ErrorContext(c.Request.Context(), "journal: close entry failed", "entry_id", id, "error", err)
+		}
+	}
+}
+
+// journalListing declares the sortable and filterable journal fields.
+var journalListing = listing.Spec[journal.Entry]{
+	Fields: map[string]listing.Field[journal.Entry]{
+		"id":          {Kind: listing.String, Get: func(e journal.Entry) any { return e.ID }},
+		"tenant":      {Kind: listing.String, Get: func(e journal.Entry) any { return e.Tenant }},
+		"filename":    {Kind: listing.String, Get: func(e journal.Entry) any { return e.Filename }},
+		"received_at": {Kind: listing.Time, Get: func(e journal.Entry) any { return e.ReceivedAt }},
+		"image_size":  {Kind: listing.Number, Get: func(e journal.Entry) any { return float64(e.ImageSize) }},
+		"has_image":   {Kind: listing.String, Get: func(e journal.Entry) any { return strconv.FormatBool(e.HasImage) }},
+	},
+	ID:           func(e journal.Entry) string { return e.ID },
+	DefaultSort:  "received_at",
+	DefaultLimit: 50,
+	MaxLimit:     100,
+}
+
+// ListJournal lists the requests that were received but never answered,
+// using the shared sort/filter/cursor grammar.
+func (h *Handler) ListJournal(c *gin.Context) {
+	entries, err := h.Journal.Pending()
+	if err != nil {
+		h.respondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	page, ok := listPage(h, c, entries, journalListing)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, models.JournalResponse{Entries: page.Items, NextCursor: page.NextCursor})
+}
+
+// ReplayJournal starts a job that re-runs every pending request whose
+// image was journaled. The result lists the entries that could not be
+// replayed and must be resubmitted.
+func (h *Handler) ReplayJournal(c *gin.Context) {
+	job := h.Jobs.Submit(context.Background(), "journal_replay", c.GetHeader(tenantHeader), func(ctx context.Context, report func(float64)) (any, error) {
+		entries, err := h.Journal.Pending()
+		if err != nil {
+			return nil, err
+		}
+		var result models.JournalReplayResult
+		for i, e := range entries {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			report(float64(i) / float64(len(entries)))
+			if !e.HasImage {
+				result.Resubmit = append(result.Resubmit, e)
+				continue
+			}
+			if !h.Journal.Claim(e.ID) {
+				continue
+			}
+			status, ok := h.replayEntry(ctx, e.ID, e.Tenant, e.Filename, e.Fields)
+			switch {
+			case !ok:
+				result.Resubmit = append(result.Resubmit, e)
+			case status >= http.StatusInternalServerError:
+				result.Failed++
+			default:
+				result.Replayed++
+			}
+		}
+		return result, nil
+	})
+	c.JSON(http.StatusAccepted, job)
+}
+
+// replayEntry runs a claimed entry through the predict pipeline, which
+// closes the entry. ok is false when its image cannot be read.
+func (h *Handler) replayEntry(ctx context.Context, id, tenant, filename string, fields map[string]string) (status int, ok bool) {
+	// Predict normally closes the entry; this only releases the claim if
+	// it never got that far.
+	defer h.Journal.Finish(id, false)
+	data, err := h.Journal.Image(id)
+	if err != nil {
+		slog.WarnContext(ctx, "journal: replay failed, image unreadable", "entry_id", id, "tenant", tenant, "error", err)
+		return 0, false
+	}
+	status, _ = h.Ingest(context.WithValue(ctx, replayKey{}, id), tenant, filename, data, fields)
+	return status, true
+}
+
+// DiscardJournalEntry removes an entry, e.g. once the study has been
+// resubmitted by hand.
+func (h *Handler) DiscardJournalEntry(c *gin.Context) {
+	if err := h.Journal.Discard(c.Param("id")); err != nil {
+		h.respondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,127 @@
+// backend/internal/handlers/jsonupload.go
+/*
+ * This file contains the JSON form of study uploads, for clients that
+ * cannot easily send multipart forms (mobile apps, serverless functions):
+ *
+ *   Content-Type: application/json
+ *   {"image_base64": "iVBORw0KGgo...", "format": "png", "laterality": "L"}
+ *
+ * `image_base64` holds the study, base64 encoded, optionally as a data
+ * URL. `format` names its file type and only serves as the extension of
+ * the upload's file name; the decoder recognises images by content. Every
+ * other member is a form field, as in a multipart upload, so the upload
+ * endpoints take either form and answer the same. Members must be
+ * strings, numbers or booleans, within the multipart field limits.
+ *
+ * The body is held in memory while it is decoded, so very large studies
+ * are better sent as multipart, which is spooled.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errInvalidUpload is returned for a JSON upload that cannot be read.
+var errInvalidUpload = errors.New("invalid upload")
+
+// uploadFormat is the accepted form of the `format` member.
+var uploadFormat = regexp.MustCompile(`^[a-z0-9]{1,10}$`)
+
+// isJSONUpload reports whether the request body is a JSON upload.
+func isJSONUpload(c *gin.Context) bool {
+	mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+// readJSONUpload parses a JSON upload, making its fields available
+// through c.PostForm.
+func (h *Handler) readJSONUpload(c *gin.Context) (*upload, error) {
+	limits := h.uploadLimits()
+	// Base64 takes four bytes for every three.
+	maxBody := base64.StdEncoding.EncodedLen(int(limits.MaxImageBytes)) + maxFormParts*maxFieldBytes
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(maxBody))
+
+	var members map[string]json.RawMessage
+	if err := json.NewDecoder(c.Request.Body).Decode(&members); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, uploadError(err)
+		}
+		return nil, fmt.Errorf("%w: %v", errInvalidUpload, err)
+	}
+	if len(members) > maxFormParts {
+		return nil, fmt.Errorf("%w: more than %d members", errUploadTooLarge, maxFormParts)
+	}
+
+	fields := make(url.Values)
+	var encoded, format string
+	for name, raw := range members {
+		var value string
+		switch {
+		case len(raw) > 0 && raw[0] == '"':
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", errInvalidUpload, name, err)
+			}
+		case string(raw) == "null":
+			continue
+		case len(raw) > 0 && (raw[0] == '{' || raw[0] == '['):
+			return nil, fmt.Errorf("%w: %s must be a string, number or boolean", errInvalidUpload, name)
+		default:
+			value = string(raw)
+		}
+		switch name {
+		case "image_base64":
+			encoded = value
+		case "format":
+			format = strings.ToLower(value)
+		default:
+			if len(value) > maxFieldBytes {
+				return nil, fmt.Errorf("%w: field %s exceeds %d bytes", errUploadTooLarge, name, maxFieldBytes)
+			}
+			fields.Set(name, value)
+		}
+	}
+	c.Request.PostForm = fields
+	c.Request.Form = fields
+
+	if encoded == "" {
+		return nil, errImageRequired
+	}
+	if format != "" && !uploadFormat.MatchString(format) {
+		return nil, fmt.Errorf("%w: format %q is not a file type", errInvalidUpload, format)
+	}
+	// A data URL (data:image/png;base64,...) carries its own prefix.
+	if rest, ok := strings.CutPrefix(encoded, "data:"); ok {
+		if _, data, found := strings.Cut(rest, ";base64,"); found {
+			encoded = data
+		}
+	}
+	image, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: image_base64 is not base64: %v", errInvalidUpload, err)
+	}
+	if int64(len(image)) > limits.MaxImageBytes {
+		return nil, fmt.Errorf("%w: image exceeds %d bytes", errUploadTooLarge, limits.MaxImageBytes)
+	}
+	filename := "upload"
+	if format != "" {
+		filename += "." + format
+	}
+	return &upload{image: image, filename: filename}, nil
+}
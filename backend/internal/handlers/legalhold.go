@@ -0,0 +1,186 @@
+// backend/internal/handlers/legalhold.go
+/*
+ * This file contains the legal hold admin API.
+ *
+ *   POST /admin/legal-holds              place a hold on a prediction or tenant
+ *   GET  /admin/legal-holds              holds in force (?include_released=true)
+ *   GET  /admin/legal-holds/:id          one hold
+ *   POST /admin/legal-holds/:id/release  lift a hold
+ *
+ * A held prediction can be neither soft-deleted nor purged (see
+ * deletion.go); the refusal names the holds. Placing and releasing a hold
+ * and every refused deletion are published for the audit log.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/events"
+	"github.com/josephed37/mammoscan-AI/backend/internal/legalhold"
+	"github.com/josephed37/mammoscan-AI/backend/internal/listing"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/store"
+)
+
+// PlaceLegalHold places a hold. A prediction hold applies to the tenant
+// of the prediction, which must exist.
+func (h *Handler) PlaceLegalHold(c *gin.Context) {
+	var spec legalhold.Spec
+	if err := c.ShouldBindJSON(&spec); err != nil {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	if spec.PredictionID != "" {
+		rec, err := h.Store.Get(c.Request.Context(), spec.PredictionID)
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{Error: err.Error(), Code: "prediction_not_found"})
+			return
+		}
+		if err != nil {
+			h.respondStoreError(c, err, "prediction lookup failed")
+			return
+		}
+		if spec.Tenant != "" && spec.Tenant != rec.Tenant {
+			h.respondErrorCode(c, http.StatusBadRequest, "invalid_legal_hold", "prediction belongs to another tenant")
+			return
+		}
+		spec.Tenant = rec.Tenant
+	}
+	hold, err := h.LegalHolds.Place(spec, actor(c))
+	if err != nil {
+		h.respondLegalHoldError(c, err)
+		return
+	}
+	slog.InfoContext(c.Request.Context(), "legal hold placed",
+		"hold_id", hold.ID, "scope", holdScope(hold), "tenant", hold.Tenant, "placed_by", hold.PlacedBy)
+	h.Events.PublishContext(c.Request.Context(), events.Event{Type: events.LegalHoldPlaced, Tenant: hold.Tenant, Data: hold})
+	c.JSON(http.StatusCreated, hold)
+}
+
+// holdListing declares the sortable and filterable legal hold fields.
+var holdListing = listing.Spec[legalhold.Hold]{
+	Fields: map[string]listing.Field[legalhold.Hold]{
+		"id":            {Kind: listing.String, Get: func(h legalhold.Hold) any { return h.ID }},
+		"tenant":        {Kind: listing.String, Get: func(h legalhold.Hold) any { return h.Tenant }},
+		"prediction_id": {Kind: listing.String, Get: func(h legalhold.Hold) any { return h.PredictionID }},
+		"reference":     {Kind: listing.String, Get: func(h legalhold.Hold) any { return h.Reference }},
+		"placed_by":     {Kind: listing.String, Get: func(h legalhold.Hold) any { return h.PlacedBy }},
+		"placed_at":     {Kind: listing.Time, Get: func(h legalhold.Hold) any { return h.PlacedAt }},
+		"active":        {Kind: listing.String, Get: func(h legalhold.Hold) any { return strconv.FormatBool(h.Active()) }},
+	},
+	ID:           func(h legalhold.Hold) string { return h.ID },
+	DefaultSort:  "placed_at",
+	DefaultLimit: 50,
+	MaxLimit:     100,
+}
+
+// ListLegalHolds lists the holds in force, oldest first, using the shared
+// sort/filter/cursor grammar.
+func (h *Handler) ListLegalHolds(c *gin.Context) {
+	page, ok := listPage(h, c, h.LegalHolds.List(c.Query("include_released") == "true"), holdListing)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, withCursor(gin.H{"legal_holds": page.Items}, page.NextCursor))
+}
+
+// GetLegalHold returns one hold, released or not.
+func (h *Handler) GetLegalHold(c *gin.Context) {
+	hold, err := h.LegalHolds.Get(c.Param("id"))
+	if err != nil {
+		h.respondLegalHoldError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, hold)
+}
+
+// ReleaseLegalHold lifts a hold. The request body may give a reason.
+func (h *Handler) ReleaseLegalHold(c *gin.Context) {
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			h.respondErrorCode(c, http.StatusBadRequest, "invalid_request", err.Error())
+			return
+		}
+	}
+	hold, err := h.LegalHolds.Release(c.Param("id"), actor(c), body.Reason)
+	if err != nil {
+		h.respondLegalHoldError(c, err)
+		return
+	}
+	slog.InfoContext(c.Request.Context(), "legal hold released",
+		"hold_id", hold.ID, "scope", holdScope(hold), "tenant", hold.Tenant, "released_by", hold.ReleasedBy)
+	h.Events.PublishContext(c.Request.Context(), events.Event{Type: events.LegalHoldReleased, Tenant: hold.Tenant, Data: hold})
+	c.JSON(http.StatusOK, hold)
+}
+
+// refuseHeldDeletion responds 409 and reports true when rec is under
+// legal hold. action is "delete" or "purge".
+func (h *Handler) refuseHeldDeletion(c *gin.Context, rec models.StoredPrediction, action string) bool {
+	holds := h.heldBy(rec)
+	if len(holds) == 0 {
+		return false
+	}
+	h.respondHeldDeletion(c, rec, action, holds)
+	return true
+}
+
+// heldBy returns the IDs of the legal holds covering rec.
+func (h *Handler) heldBy(rec models.StoredPrediction) []string {
+	holds := h.LegalHolds.Holding(rec.Tenant, rec.PredictionID)
+	ids := make([]string, len(holds))
+	for i, hold := range holds {
+		ids[i] = hold.ID
+	}
+	return ids
+}
+
+// respondHeldDeletion refuses to delete or purge rec, which the legal
+// holds ids cover, and reports the attempt.
+func (h *Handler) respondHeldDeletion(c *gin.Context, rec models.StoredPrediction, action string, ids []string) {
+	slog.WarnContext(c.Request.Context(), "deletion refused: prediction under legal hold",
+		"action", action, "prediction_id", rec.PredictionID, "tenant", rec.Tenant, "actor", actor(c), "holds", ids)
+	h.Events.PublishContext(c.Request.Context(), events.Event{Type: events.LegalHoldBlocked, Tenant: rec.Tenant, Data: events.HeldDeletion{
+		PredictionID: rec.PredictionID,
+		Action:       action,
+		Actor:        actor(c),
+		Holds:        ids,
+	}})
+	h.respondErrorCode(c, http.StatusConflict, "legal_hold",
+		fmt.Sprintf("prediction is under legal hold (%s)", strings.Join(ids, ", ")))
+}
+
+func (h *Handler) respondLegalHoldError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, legalhold.ErrNotFound):
+		h.respondErrorCode(c, http.StatusNotFound, "legal_hold_not_found", err.Error())
+	case errors.Is(err, legalhold.ErrInvalid):
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_legal_hold", err.Error())
+	case errors.Is(err, legalhold.ErrReleased):
+		h.respondErrorCode(c, http.StatusConflict, "legal_hold_released", err.Error())
+	default:
+		h.respondError(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// holdScope describes what a hold covers, for logs.
+func holdScope(hold legalhold.Hold) string {
+	if hold.PredictionID != "" {
+		return "prediction " + hold.PredictionID
+	}
+	return fmt.Sprintf("tenant %q", hold.Tenant)
+}
@@ -0,0 +1,42 @@
+// backend/internal/handlers/netacl.go
+/*
+ * This file contains the middleware that enforces the network policy.
+ *
+ * Every endpoint group (health, api, admin) is guarded by
+ * RestrictNetwork ahead of authentication, so refused addresses learn
+ * nothing about the service beyond a 403.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// Endpoint groups the network policy restricts.
+const (
+	NetworkGroupHealth = "health"
+	NetworkGroupAPI    = "api"
+	NetworkGroupAdmin  = "admin"
+)
+
+// RestrictNetwork returns a middleware that refuses clients the network
+// policy does not admit to group. It is a no-op when no policy is
+// configured.
+func (h *Handler) RestrictNetwork(group string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.Network == nil || h.Network.Policy().Allowed(group, c.ClientIP()) {
+			c.Next()
+			return
+		}
+		h.Stats.RecordError(http.StatusForbidden, "client address refused by network policy")
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{Error: "not permitted from this network", Code: "network_denied"})
+	}
+}
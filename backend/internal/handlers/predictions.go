@@ -0,0 +1,268 @@
+// backend/internal/handlers/predictions.go
+/*
+ * This file contains the prediction lookup handlers.
+ *
+ * Integrators routinely lose our prediction IDs, so besides fetching by ID
+ * we support lookups by their own identifiers:
+ *
+ *   GET /api/v1/predictions?accession=ACC123
+ *   GET /api/v1/predictions?client_reference=RIS-42
+ *   GET /api/v1/predictions/:id
+ *
+ * The list endpoint also accepts the shared sort/filter/cursor grammar
+ * (see the listing package), e.g.
+ *
+ *   GET /api/v1/predictions?filter=prediction:eq:Cancer&sort=created_at
+ *
+ * Pages are read from the store, which orders by creation time only:
+ * created_at is the one sortable field.
+ *
+ * Reviewers attach the confirmed outcome with
+ *
+ *   POST /api/v1/predictions/:id/feedback
+ *
+ * Feedback is versioned. Reading a prediction returns an ETag for its
+ * current feedback version, and replacing existing feedback requires that
+ * ETag in If-Match, so one reviewer cannot silently overwrite another's
+ * annotation.
+ *
+ * When the request carries an X-Tenant-ID header, results are restricted
+ * to that tenant.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/dependency"
+	"github.com/josephed37/mammoscan-AI/backend/internal/listing"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/store"
+)
+
+// Errors from the feedback version check.
+var (
+	errFeedbackVersionRequired = errors.New("this prediction already has feedback; resend with If-Match set to its current ETag")
+	errFeedbackVersionMismatch = errors.New("feedback was changed by someone else; reload it and reapply your edit")
+)
+
+// maxLookupResults caps how many records a single page returns.
+const maxLookupResults = 100
+
+// predictionListing declares the filterable prediction fields. Listings
+// are paged in the store, which orders by creation time only.
+var predictionListing = listing.Spec[models.StoredPrediction]{
+	Fields: map[string]listing.Field[models.StoredPrediction]{
+		"prediction_id":    {Kind: listing.String, Get: func(r models.StoredPrediction) any { return r.PredictionID }},
+		"created_at":       {Kind: listing.Time, Get: func(r models.StoredPrediction) any { return r.CreatedAt }},
+		"prediction":       {Kind: listing.String, Get: func(r models.StoredPrediction) any { return r.Prediction }},
+		"confidence_score": {Kind: listing.Number, Get: func(r models.StoredPrediction) any { return r.ConfidenceScore }},
+		"model_name":       {Kind: listing.String, Get: func(r models.StoredPrediction) any { return r.ModelName }},
+		"model_version":    {Kind: listing.String, Get: func(r models.StoredPrediction) any { return r.ModelVersion }},
+		"image_sha256":     {Kind: listing.String, Get: func(r models.StoredPrediction) any { return r.ImageSHA256 }},
+		"accession_number": {Kind: listing.String, Get: func(r models.StoredPrediction) any { return r.AccessionNumber }},
+		"client_reference": {Kind: listing.String, Get: func(r models.StoredPrediction) any { return r.ClientReference }},
+		"needs_review":     {Kind: listing.String, Get: func(r models.StoredPrediction) any { return strconv.FormatBool(r.NeedsReview) }},
+		"ground_truth": {Kind: listing.String, Get: func(r models.StoredPrediction) any {
+			if r.Feedback == nil {
+				return ""
+			}
+			return r.Feedback.GroundTruth
+		}},
+	},
+	ID:           func(r models.StoredPrediction) string { return r.PredictionID },
+	Sortable:     []string{"created_at"},
+	DefaultSort:  "-created_at",
+	DefaultLimit: 50,
+	MaxLimit:     maxLookupResults,
+}
+
+// ListPredictions lists the tenant's predictions, optionally filtered by
+// accession number or client reference. Soft-deleted predictions are only
+// included with `?include_deleted=true`.
+func (h *Handler) ListPredictions(c *gin.Context) {
+	values := c.Request.URL.Query()
+	// The original lookup parameters are shorthands for equality filters.
+	if v := values.Get("accession"); v != "" {
+		values.Add("filter", "accession_number:eq:"+v)
+	}
+	if v := values.Get("client_reference"); v != "" {
+		values.Add("filter", "client_reference:eq:"+v)
+	}
+	query, err := listing.Parse(values, predictionListing)
+	if err != nil {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_list_parameters", err.Error())
+		return
+	}
+
+	from, fromID, err := listing.From(query, predictionListing)
+	if err != nil {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_list_parameters", err.Error())
+		return
+	}
+
+	// The store filters, orders and pages; one extra record tells whether
+	// there is a next page.
+	filter := store.Filter{
+		Tenant:          c.GetHeader(tenantHeader),
+		AccessionNumber: query.Equal("accession_number"),
+		ClientReference: query.Equal("client_reference"),
+		Match:           listing.Matcher(query, predictionListing),
+		OldestFirst:     len(query.Sort) > 0 && !query.Sort[0].Desc,
+		Limit:           query.Limit + 1,
+		IncludeDeleted:  c.Query("include_deleted") == "true",
+	}
+	if len(from) > 0 {
+		filter.After = &store.Position{CreatedAt: from[0].(time.Time), ID: fromID}
+	}
+	records, err := h.Store.Find(c.Request.Context(), filter)
+	if err != nil {
+		h.respondStoreError(c, err, "prediction lookup failed")
+		return
+	}
+	page := listing.PageOf(records, query, predictionListing)
+	renderJSON(c, http.StatusOK, models.PredictionListResponse{Predictions: page.Items, NextCursor: page.NextCursor})
+}
+
+// GetPrediction returns a single prediction by its ID.
+func (h *Handler) GetPrediction(c *gin.Context) {
+	rec, err := h.findPrediction(c.Request.Context(), c.GetHeader(tenantHeader), c.Param("id"))
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: err.Error(), Code: "prediction_not_found"})
+		return
+	}
+	if err != nil {
+		h.respondStoreError(c, err, "prediction lookup failed")
+		return
+	}
+	c.Header("ETag", feedbackETag(rec))
+	renderJSON(c, http.StatusOK, rec)
+}
+
+// SubmitFeedback records the confirmed ground truth for a prediction. This
+// is what makes sensitivity/specificity monitoring possible.
+func (h *Handler) SubmitFeedback(c *gin.Context) {
+	var fb models.Feedback
+	if err := c.ShouldBindJSON(&fb); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error(), Code: "invalid_feedback"})
+		return
+	}
+
+	var expected *int
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+		v, ok := parseFeedbackETag(ifMatch)
+		if !ok {
+			c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Error: "If-Match is not a feedback ETag", Code: "feedback_version_mismatch"})
+			return
+		}
+		expected = &v
+	}
+
+	rec, err := h.recordFeedback(c.Request.Context(), c.GetHeader(tenantHeader), c.Param("id"), fb, expected)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: err.Error(), Code: "prediction_not_found"})
+		return
+	case errors.Is(err, errFeedbackVersionRequired):
+		c.JSON(http.StatusPreconditionRequired, models.ErrorResponse{Error: err.Error(), Code: "feedback_version_required"})
+		return
+	case errors.Is(err, errFeedbackVersionMismatch):
+		c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{Error: err.Error(), Code: "feedback_version_mismatch"})
+		return
+	case err != nil:
+		h.respondStoreError(c, err, "failed to save feedback")
+		return
+	}
+	c.Header("ETag", feedbackETag(rec))
+	renderJSON(c, http.StatusOK, rec)
+}
+
+// findPrediction fetches a prediction visible to tenant. Another tenant's
+// records are reported as not found so their existence is not revealed.
+func (h *Handler) findPrediction(ctx context.Context, tenant, id string) (models.StoredPrediction, error) {
+	return h.lookupPrediction(ctx, tenant, id, false)
+}
+
+// lookupPrediction is findPrediction, optionally also returning
+// soft-deleted records.
+func (h *Handler) lookupPrediction(ctx context.Context, tenant, id string, includeDeleted bool) (models.StoredPrediction, error) {
+	rec, err := h.Store.Get(ctx, id)
+	if err != nil {
+		return rec, err
+	}
+	if (tenant != "" && rec.Tenant != tenant) || (rec.DeletedAt != nil && !includeDeleted) {
+		return models.StoredPrediction{}, store.ErrNotFound
+	}
+	return rec, nil
+}
+
+// respondStoreError reports a failed store call: 503 while the database's
+// circuit is open, so clients know to retry later, 500 otherwise.
+func (h *Handler) respondStoreError(c *gin.Context, err error, message string) {
+	if errors.Is(err, dependency.ErrOpen) {
+		h.respondErrorCode(c, http.StatusServiceUnavailable, "store_unavailable", "prediction store unavailable")
+		return
+	}
+	h.respondError(c, http.StatusInternalServerError, message)
+}
+
+// recordFeedback attaches reviewer feedback to a prediction visible to
+// tenant and returns the updated record. expected is the feedback version
+// the reviewer edited (0 for none); it may only be omitted while the
+// prediction has no feedback yet.
+func (h *Handler) recordFeedback(ctx context.Context, tenant, id string, fb models.Feedback, expected *int) (models.StoredPrediction, error) {
+	return h.Store.Update(ctx, id, func(rec *models.StoredPrediction) error {
+		if (tenant != "" && rec.Tenant != tenant) || rec.DeletedAt != nil {
+			return store.ErrNotFound
+		}
+		current := feedbackVersion(*rec)
+		if expected == nil && current > 0 {
+			return errFeedbackVersionRequired
+		}
+		if expected != nil && *expected != current {
+			return errFeedbackVersionMismatch
+		}
+		fb.RecordedAt = time.Now().UTC()
+		fb.Version = current + 1
+		rec.Feedback = &fb
+		return nil
+	})
+}
+
+// feedbackVersion returns the version of rec's feedback, 0 if it has none.
+// Feedback recorded before versioning counts as version 1.
+func feedbackVersion(rec models.StoredPrediction) int {
+	if rec.Feedback == nil {
+		return 0
+	}
+	return max(rec.Feedback.Version, 1)
+}
+
+// feedbackETag renders the ETag for rec's current feedback version.
+func feedbackETag(rec models.StoredPrediction) string {
+	return fmt.Sprintf(`"feedback-%d"`, feedbackVersion(rec))
+}
+
+// parseFeedbackETag extracts the version from an If-Match value produced
+// by feedbackETag. Weak validators are accepted.
+func parseFeedbackETag(tag string) (int, bool) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+	tag, ok := strings.CutPrefix(strings.Trim(tag, `"`), "feedback-")
+	if !ok {
+		return 0, false
+	}
+	v, err := strconv.Atoi(tag)
+	return v, err == nil && v >= 0
+}
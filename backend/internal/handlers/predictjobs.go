@@ -0,0 +1,91 @@
+// backend/internal/handlers/predictjobs.go
+/*
+ * This file contains asynchronous prediction jobs.
+ *
+ * Large images and big batches can outlast a client's or a proxy's
+ * request timeout on the synchronous endpoints. Instead they can be
+ * queued:
+ *
+ *   POST /api/v1/jobs      queue one image, or several as for
+ *                          /predict/batch, and return the job at once
+ *   GET  /api/v1/jobs/:id  the job's status (long-poll with ?wait=) and,
+ *                          once done, its result
+ *
+ * The result of a one-image job is the prediction POST /api/v1/predict
+ * would have returned; a job whose image is refused fails with that
+ * error. A several-image job returns the batch response, and its progress
+ * is the share of images done.
+ *
+ * Prediction jobs run on their own pool of workers, separate from the
+ * admin jobs so a long re-scoring cannot hold them up, and at most
+ * PredictionQueueDepth may wait: beyond that submissions are refused with
+ * 503 rather than queued behind hours of work.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/jobs"
+)
+
+// predictionJobType names prediction jobs.
+const predictionJobType = "prediction"
+
+// SubmitPredictionJob queues the uploaded images for scoring.
+func (h *Handler) SubmitPredictionJob(c *gin.Context) {
+	if h.PredictionJobs == nil {
+		h.respondErrorCode(c, http.StatusNotFound, "async_jobs_disabled", "asynchronous prediction jobs are not enabled")
+		return
+	}
+	if h.refuseStaleModel(c) {
+		return
+	}
+	uploads, err := h.readBatchUpload(c)
+	if err != nil {
+		h.respondBatchUploadError(c, err)
+		return
+	}
+
+	header, fields := forwardedRequest(c)
+	job, err := h.PredictionJobs.TrySubmit(context.Background(), predictionJobType, c.GetHeader(tenantHeader), func(ctx context.Context, report func(float64)) (any, error) {
+		if len(uploads) > 1 {
+			return h.predictUploads(ctx, header, uploads, fields, report), nil
+		}
+		up := uploads[0]
+		status, body := h.ingest(ctx, header, up.filename, up.image, fields)
+		r := batchItemResult(up.filename, status, body)
+		if r.Error != nil {
+			return nil, fmt.Errorf("%s: %s", r.Error.Code, r.Error.Error)
+		}
+		return r.Result, nil
+	})
+	if errors.Is(err, jobs.ErrQueueFull) {
+		c.Header("Retry-After", "30")
+		h.respondErrorCode(c, http.StatusServiceUnavailable, "job_queue_full", err.Error())
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// findJob returns a job, admin or prediction, by ID.
+func (h *Handler) findJob(id string) (*jobs.Manager, jobs.Job, error) {
+	for _, m := range []*jobs.Manager{h.Jobs, h.PredictionJobs} {
+		if m == nil {
+			continue
+		}
+		if job, err := m.Get(id); err == nil {
+			return m, job, nil
+		}
+	}
+	return nil, jobs.Job{}, jobs.ErrNotFound
+}
@@ -0,0 +1,110 @@
+// backend/internal/handlers/preprocessdebug.go
+/*
+ * This file contains the preprocessing debug endpoint.
+ *
+ *   POST /api/v1/preprocess/debug
+ *
+ * decodes and preprocesses one study exactly as a prediction would, then
+ * stops short of inference and returns what the model would have seen: the
+ * input tensor rendered back as a PNG, its shape and the minimum, maximum
+ * and mean of each channel. Comparing these with the training pipeline's
+ * is how a training/serving skew in partner-supplied images is found.
+ *
+ * A study the distribution check would reject is still preprocessed; the
+ * reason is reported in `distribution_warning`. In tiling mode the model
+ * scores patches, but the whole study is shown here.
+ *
+ * The image comes base64 encoded in a JSON body, or as the raw PNG with
+ * `?format=png` or `Accept: image/png`; the channel statistics then
+ * travel in X-MammoScan-Channel-* headers, R, G and B (L for grayscale
+ * models) comma-separated. The input is built, and rendered back, with
+ * the served model's preprocessing profile.
+ * Nothing is stored.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image/png"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+)
+
+// PreprocessDebug returns the model input for a study without scoring it.
+func (h *Handler) PreprocessDebug(c *gin.Context) {
+	received, err := h.readUpload(c)
+	switch {
+	case errors.Is(err, errImageRequired):
+		h.respondErrorCode(c, http.StatusBadRequest, "image_required", "image file is required")
+		return
+	case errors.Is(err, errUploadTooLarge):
+		h.respondErrorCode(c, http.StatusRequestEntityTooLarge, "upload_too_large", err.Error())
+		return
+	case errors.Is(err, errInvalidUpload):
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_upload", err.Error())
+		return
+	case err != nil:
+		h.respondError(c, http.StatusInternalServerError, "failed to read uploaded file")
+		return
+	}
+	wantPNG := c.Query("format") == "png" || strings.Contains(c.GetHeader("Accept"), pngContentType)
+	if f := c.Query("format"); f != "" && f != "json" && f != "png" {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_format", fmt.Sprintf("format must be json or png, got %q", f))
+		return
+	}
+	img, ok := h.openStudy(c, received.image)
+	if !ok {
+		return
+	}
+
+	engine, _ := h.ServedModel()
+	profile := preprocess.ProfileOf(engine)
+	input := profile.Tensor(img)
+	var rendered bytes.Buffer
+	if err := png.Encode(&rendered, profile.Image(input)); err != nil {
+		h.respondError(c, http.StatusInternalServerError, fmt.Sprintf("failed to encode image: %v", err))
+		return
+	}
+	channels := channelStats(input, profile)
+
+	if wantPNG {
+		for _, stat := range []struct {
+			header string
+			value  func(models.ChannelStats) float64
+		}{
+			{"X-MammoScan-Channel-Min", func(s models.ChannelStats) float64 { return s.Min }},
+			{"X-MammoScan-Channel-Max", func(s models.ChannelStats) float64 { return s.Max }},
+			{"X-MammoScan-Channel-Mean", func(s models.ChannelStats) float64 { return s.Mean }},
+		} {
+			values := make([]string, len(channels))
+			for i, ch := range channels {
+				values[i] = strconv.FormatFloat(stat.value(ch), 'f', -1, 64)
+			}
+			c.Header(stat.header, strings.Join(values, ","))
+		}
+		c.Data(http.StatusOK, pngContentType, rendered.Bytes())
+		return
+	}
+	resp := models.PreprocessDebugResponse{
+		Shape:    input.Shape().Clone(),
+		Channels: channels,
+		ImagePNG: base64.StdEncoding.EncodeToString(rendered.Bytes()),
+	}
+	if err := h.checkInDistribution(img); err != nil {
+		resp.DistributionWarning = err.Error()
+	}
+	renderJSON(c, http.StatusOK, resp)
+}
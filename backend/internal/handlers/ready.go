@@ -0,0 +1,59 @@
+// backend/internal/handlers/ready.go
+/*
+ * This file contains the readiness probe.
+ *
+ *   GET /ready
+ *
+ * answers 200 once the service can take traffic, and 503 before (the
+ * model is still downloading or warming up) and after (the service is
+ * draining for shutdown). /healthy answers as long as the process is
+ * alive, so an orchestrator restarts the service on the one and only
+ * routes requests to it on the other.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// Readiness states, as reported by /ready.
+const (
+	ReadinessStarting = "STARTING"
+	ReadinessReady    = "READY"
+	ReadinessDraining = "DRAINING"
+)
+
+var readinessStates = []string{ReadinessStarting, ReadinessReady, ReadinessDraining}
+
+// SetReady reports the service ready to take traffic.
+func (h *Handler) SetReady() {
+	h.readiness.Store(1)
+}
+
+// Drain reports the service as shutting down; it stays so.
+func (h *Handler) Drain() {
+	h.readiness.Store(2)
+}
+
+// Readiness returns the service's readiness state.
+func (h *Handler) Readiness() string {
+	return readinessStates[h.readiness.Load()]
+}
+
+// Ready is the readiness probe.
+func (h *Handler) Ready(c *gin.Context) {
+	state := h.Readiness()
+	if state != ReadinessReady {
+		c.JSON(http.StatusServiceUnavailable, models.HealthStatus{Status: state})
+		return
+	}
+	c.JSON(http.StatusOK, models.HealthStatus{Status: state})
+}
@@ -0,0 +1,146 @@
+// backend/internal/handlers/registry.go
+/*
+ * This file keeps earlier model versions loaded, keyed by version.
+ *
+ * The standby slot remembers one model. With RetainModels set, a model
+ * pushed out of standby is kept loaded instead of being dropped, so an
+ * operator can go back several versions without a download:
+ *
+ *   GET  /admin/models                    every loaded model
+ *   POST /admin/models/reload             check for a new version now
+ *   POST /admin/models/:version/activate  serve a loaded version
+ *
+ * Reload runs the model swap scheduler's check at once, ignoring its
+ * windows, as a background job. Activation is a switch like any other:
+ * the model it replaces moves to standby and can be reverted to. Every
+ * retained model costs its memory, which counts against the model memory
+ * budget, and only versioned models are retained.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+var errUnknownVersion = errors.New("no loaded model has this version")
+
+// retain keeps slot, which is leaving standby, among the retained models.
+// The caller holds modelMu.
+func (h *Handler) retain(slot *modelSlot) {
+	if slot == nil || slot.info.Version == "" || h.RetainModels <= 0 {
+		return
+	}
+	h.retained = slices.DeleteFunc(h.retained, func(r *modelSlot) bool { return r.info.Version == slot.info.Version })
+	h.retained = slices.Insert(h.retained, 0, slot)
+	if len(h.retained) > h.RetainModels {
+		clear(h.retained[h.RetainModels:])
+		h.retained = h.retained[:h.RetainModels]
+	}
+}
+
+// activateVersion serves the loaded model with the given version; the
+// active model moves to standby.
+func (h *Handler) activateVersion(version string) error {
+	h.modelMu.Lock()
+	switch {
+	case h.Model.Version == version:
+		h.modelMu.Unlock()
+		return nil
+	case h.standby != nil && h.standby.info.Version == version:
+		h.modelMu.Unlock()
+		_, _, err := h.switchModel(false)
+		return err
+	}
+	i := slices.IndexFunc(h.retained, func(r *modelSlot) bool { return r.info.Version == version })
+	if i < 0 {
+		h.modelMu.Unlock()
+		return errUnknownVersion
+	}
+	next := h.retained[i]
+	h.retained = slices.Delete(h.retained, i, i+1)
+	h.retain(h.standby)
+	previous := h.Model
+	h.standby = &modelSlot{engine: h.InferenceEngine, info: h.Model}
+	h.InferenceEngine, h.Model = next.engine, next.info
+	h.revertible = true
+	h.modelMu.Unlock()
+
+	h.publishSwap(previous, next.info)
+	return nil
+}
+
+// loadedModel returns a loaded model by identifier: "active", "standby"
+// or the version of any loaded model.
+func (h *Handler) loadedModel(id string) (Predictor, models.ModelInfo, error) {
+	h.modelMu.RLock()
+	defer h.modelMu.RUnlock()
+	switch {
+	case id == "active" || id == h.Model.Version:
+		return h.InferenceEngine, h.Model, nil
+	case h.standby != nil && (id == "standby" || id == h.standby.info.Version):
+		return h.standby.engine, h.standby.info, nil
+	}
+	for _, r := range h.retained {
+		if r.info.Version == id {
+			return r.engine, r.info, nil
+		}
+	}
+	return nil, models.ModelInfo{}, errUnknownVersion
+}
+
+// ListModels reports every loaded model.
+func (h *Handler) ListModels(c *gin.Context) {
+	h.modelMu.RLock()
+	resp := models.ModelRegistry{Active: h.Model}
+	if h.standby != nil {
+		standby := h.standby.info
+		resp.Standby = &standby
+	}
+	for _, r := range h.retained {
+		resp.Retained = append(resp.Retained, r.info)
+	}
+	h.modelMu.RUnlock()
+	c.JSON(http.StatusOK, resp)
+}
+
+// ReloadModel starts a job that checks the model artifact for a new
+// version and, if there is one, loads, validates and serves it.
+func (h *Handler) ReloadModel(c *gin.Context) {
+	if h.ModelSwaps == nil {
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "model swaps are not enabled", Code: "model_swaps_disabled"})
+		return
+	}
+	job := h.Jobs.Submit(context.Background(), "model_reload", c.GetHeader(tenantHeader), func(ctx context.Context, report func(float64)) (any, error) {
+		if _, err := h.ModelSwaps.Reload(ctx); err != nil {
+			return nil, err
+		}
+		_, active := h.ServedModel()
+		return active, nil
+	})
+	c.JSON(http.StatusAccepted, job)
+}
+
+// ActivateModel serves a loaded model by version.
+func (h *Handler) ActivateModel(c *gin.Context) {
+	if err := h.activateVersion(c.Param("version")); err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: err.Error(), Code: "model_version_not_loaded"})
+		return
+	}
+	_, active := h.ServedModel()
+	resp := models.ModelSlots{Active: active}
+	if standby, ok := h.StandbyModel(); ok {
+		resp.Standby = &standby
+	}
+	c.JSON(http.StatusOK, resp)
+}
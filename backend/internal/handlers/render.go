@@ -0,0 +1,38 @@
+// backend/internal/handlers/render.go
+/*
+ * This file contains the response encoding used on the hot paths.
+ *
+ * Prediction and lookup responses are encoded into pooled, pre-sized
+ * buffers (see internal/jsonenc) instead of a fresh slice per request,
+ * with the JSON codec chosen by build tag alongside Gin's.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/jsonenc"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+const jsonContentType = "application/json; charset=utf-8"
+
+// healthyBody is the health check response, encoded once.
+var healthyBody = []byte(`{"status":"OK"}`)
+
+// renderJSON writes v as the JSON response body using a pooled buffer.
+func renderJSON(c *gin.Context, status int, v any) {
+	buf, err := jsonenc.Encode(v)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error(), Code: "internal_error"})
+		return
+	}
+	defer jsonenc.Release(buf)
+	c.Data(status, jsonContentType, buf.Bytes())
+}
@@ -0,0 +1,56 @@
+// backend/internal/handlers/requestlog.go
+/*
+ * This file describes requests for the structured request log.
+ *
+ * The request log (see internal/logging) records every request's method,
+ * path, status and latency; RequestAttrs adds what only the handlers
+ * know: the tenant and principal, and for predictions the model that
+ * scored the study and its outcome. No patient data is logged: the
+ * outcome is the label and score, keyed by prediction ID.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// outcomeKey holds the prediction a request produced.
+const outcomeKey = "prediction_outcome"
+
+// recordOutcome notes the prediction c produced for the request log.
+func recordOutcome(c *gin.Context, response models.PredictionResponse) {
+	c.Set(outcomeKey, response)
+}
+
+// RequestAttrs returns the request log attributes of a handled request.
+func RequestAttrs(c *gin.Context) []slog.Attr {
+	var attrs []slog.Attr
+	if tenant := TenantID(c); tenant != "" {
+		attrs = append(attrs, slog.String("tenant", tenant))
+	}
+	if user := PrincipalID(c); user != "" {
+		attrs = append(attrs, slog.String("user", user))
+	}
+	if v, ok := c.Get(outcomeKey); ok {
+		response := v.(models.PredictionResponse)
+		attrs = append(attrs,
+			slog.String("prediction_id", response.PredictionID),
+			slog.String("model_name", response.ModelName),
+			slog.String("model_version", response.ModelVersion),
+			slog.String("prediction", response.Prediction),
+			slog.Float64("confidence_score", response.ConfidenceScore),
+		)
+		if response.NeedsReview {
+			attrs = append(attrs, slog.Bool("needs_review", true))
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,37 @@
+// backend/internal/handlers/selfcheck.go
+/*
+ * This file contains the startup self-check endpoint.
+ *
+ *   GET /selfcheck
+ *
+ * returns the report produced on boot: 200 when every check passed, 503
+ * otherwise. Like /healthy it needs no credentials, so deploy pipelines
+ * can gate promotion on it.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/selfcheck"
+)
+
+// SelfCheckReport returns the startup self-check report.
+func (h *Handler) SelfCheckReport(c *gin.Context) {
+	if h.SelfCheck == nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{Error: "self-check has not run", Code: "selfcheck_pending"})
+		return
+	}
+	status := http.StatusOK
+	if h.SelfCheck.Status != selfcheck.Pass {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, h.SelfCheck)
+}
@@ -0,0 +1,84 @@
+// backend/internal/handlers/shadow.go
+/*
+ * This file scores studies with the candidate of a shadow experiment.
+ *
+ * The served model's result is answered as usual; the candidate scores
+ * the same study afterwards, in the background, and its score is logged
+ * and stored alongside the served one (StoredPrediction.Shadow) with both
+ * models' names, so the experiment tally and offline analysis can compare
+ * champion and challenger study by study before the challenger is
+ * promoted. A candidate failure or a busy shadow slot costs the study its
+ * shadow score, never its result.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log/slog"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/experiment"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+)
+
+// startShadow scores img with the shadow model of a in the background,
+// once the served result in response has been recorded. What it logs
+// carries the request ID of ctx.
+func (h *Handler) startShadow(ctx context.Context, a experiment.Assignment, img image.Image, response models.PredictionResponse) {
+	release, ok := h.Experiments.ShadowSlot()
+	if !ok {
+		slog.WarnContext(ctx, "shadow: slots busy, prediction not shadowed",
+			"experiment_id", a.ExperimentID, "prediction_id", response.PredictionID)
+		return
+	}
+	// The shadow outlives the request.
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer release()
+		h.scoreShadow(ctx, a, img, response)
+	}()
+}
+
+func (h *Handler) scoreShadow(ctx context.Context, a experiment.Assignment, img image.Image, response models.PredictionResponse) {
+	out, err := a.Engine.Predict(preprocess.TensorFor(a.Engine, img))
+	if err == nil && len(out) == 0 {
+		err = fmt.Errorf("empty output")
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "shadow: scoring failed",
+			"experiment_id", a.ExperimentID, "model", a.ModelName, "prediction_id", response.PredictionID, "error", err)
+		return
+	}
+	score := float64(out[0])
+	shadow := models.ShadowScore{
+		ModelName:       a.ModelName,
+		ConfidenceScore: score,
+		Prediction:      models.LabelFor(score, a.Threshold),
+		Threshold:       a.Threshold,
+		ScoredAt:        time.Now().UTC(),
+	}
+	slog.InfoContext(ctx, "shadow: scored",
+		"experiment_id", a.ExperimentID, "prediction_id", response.PredictionID,
+		"served_model", response.ModelName, "served_version", response.ModelVersion,
+		"served_score", response.ConfidenceScore, "served_prediction", response.Prediction,
+		"candidate_model", shadow.ModelName, "candidate_score", shadow.ConfidenceScore, "candidate_prediction", shadow.Prediction)
+	if h.Store == nil {
+		return
+	}
+	_, err = h.Store.Update(ctx, response.PredictionID, func(rec *models.StoredPrediction) error {
+		rec.Shadow = &shadow
+		return nil
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "shadow: save score failed",
+			"experiment_id", a.ExperimentID, "prediction_id", response.PredictionID, "error", err)
+	}
+}
@@ -0,0 +1,109 @@
+// backend/internal/handlers/staleness.go
+/*
+ * This file guards against silently serving an outdated model.
+ *
+ * With a maximum model age configured, the served model is stale once its
+ * artifact is older than the limit. A stale model turns the health check
+ * DEGRADED (still 200, so orchestrators keep the instance), is listed in
+ * the admin overview, and raises one ModelStale event per loaded model,
+ * which webhooks forward as model.stale. With Block set, predictions are
+ * refused with 503 until a newer model is swapped in; studies refused
+ * this way stay in the journal for replay.
+ *
+ * The age is measured from the artifact's BuiltAt. A model whose artifact
+ * time is unknown is never stale.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/events"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// ModelAgeLimit configures the stale-model guard.
+type ModelAgeLimit struct {
+	// MaxAge is the age past which the served model is stale; zero
+	// disables the guard.
+	MaxAge time.Duration
+	// Block refuses predictions while the served model is stale.
+	Block bool
+}
+
+// ModelAge reports the age of the served model at now; nil when no
+// maximum age is configured.
+func (h *Handler) ModelAge(now time.Time) *models.ModelAge {
+	_, served := h.ServedModel()
+	return h.MaxModelAge.check(served, now)
+}
+
+func (l ModelAgeLimit) check(info models.ModelInfo, now time.Time) *models.ModelAge {
+	if l.MaxAge <= 0 {
+		return nil
+	}
+	age := &models.ModelAge{
+		Model:      info.Name,
+		Version:    info.Version,
+		BuiltAt:    info.BuiltAt,
+		MaxAgeDays: days(l.MaxAge),
+	}
+	if info.BuiltAt != nil {
+		d := now.Sub(*info.BuiltAt)
+		age.AgeDays = days(d)
+		age.Stale = d > l.MaxAge
+		age.Blocking = age.Stale && l.Block
+	}
+	return age
+}
+
+// CheckModelAge checks the served model and, the first time a loaded
+// model is found stale, logs it and publishes ModelStale. It is called
+// periodically.
+func (h *Handler) CheckModelAge(now time.Time) *models.ModelAge {
+	_, served := h.ServedModel()
+	age := h.MaxModelAge.check(served, now)
+	if age == nil || !age.Stale {
+		return age
+	}
+	// A model is identified by where and when it was loaded, so a stale
+	// model switched out and back in is reported again.
+	key := served.Source + "@" + served.LoadedAt.String()
+	h.staleMu.Lock()
+	alert := h.staleAlerted != key
+	h.staleAlerted = key
+	h.staleMu.Unlock()
+	if alert {
+		slog.Warn("model age: "+staleWarning(age), "model", served.Source)
+		h.Events.Publish(events.Event{Type: events.ModelStale, Data: *age})
+	}
+	return age
+}
+
+// refuseStaleModel answers 503 and reports true when predictions are
+// blocked because the served model is stale.
+func (h *Handler) refuseStaleModel(c *gin.Context) bool {
+	age := h.ModelAge(time.Now())
+	if age == nil || !age.Blocking {
+		return false
+	}
+	h.respondErrorCode(c, http.StatusServiceUnavailable, "model_stale", staleWarning(age))
+	return true
+}
+
+func staleWarning(age *models.ModelAge) string {
+	return fmt.Sprintf("model %s is %.0f days old, past the maximum of %.0f days", age.Model, age.AgeDays, age.MaxAgeDays)
+}
+
+func days(d time.Duration) float64 {
+	return d.Hours() / 24
+}
@@ -0,0 +1,194 @@
+// backend/internal/handlers/stream.go
+/*
+ * This file contains the real-time stream scoring endpoint.
+ *
+ *   POST /api/v1/streams/predict?fps=1[&source=<url>][&max_frames=N]
+ *
+ * Frames come either from the request body (MJPEG or concatenated JPEGs,
+ * typically sent with chunked encoding) or, when `source` is given, from
+ * an http(s) MJPEG or rtsp:// URL on the operator's allowlist. Frames are
+ * scored at most `fps` times per second and each result is sent back as a
+ * Server-Sent Event (`prediction`), followed by a final `end` event.
+ *
+ * Stream frames are not stored as individual predictions.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/events"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+	"github.com/josephed37/mammoscan-AI/backend/internal/stream"
+)
+
+// defaultStreamMaxDuration bounds a session when none is configured.
+const defaultStreamMaxDuration = 10 * time.Minute
+
+// PredictStream scores a live frame stream and reports results over SSE.
+func (h *Handler) PredictStream(c *gin.Context) {
+	if h.refuseStaleModel(c) {
+		return
+	}
+	fps, err := strconv.ParseFloat(c.DefaultQuery("fps", "1"), 64)
+	if err != nil || fps < 0 {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_request", "fps must be a non-negative number")
+		return
+	}
+	maxFrames, err := strconv.Atoi(c.DefaultQuery("max_frames", "0"))
+	if err != nil || maxFrames < 0 {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_request", "max_frames must be a non-negative integer")
+		return
+	}
+
+	maxDuration := h.StreamMaxDuration
+	if maxDuration <= 0 {
+		maxDuration = defaultStreamMaxDuration
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), maxDuration)
+	defer cancel()
+
+	var frames stream.FrameReader
+	if source := c.Query("source"); source != "" {
+		if !h.streamSourceAllowed(source) {
+			h.respondErrorCode(c, http.StatusForbidden, "stream_source_not_allowed", "stream source is not on the allowlist")
+			return
+		}
+		if frames, err = stream.Open(ctx, source); err != nil {
+			h.respondErrorCode(c, http.StatusBadGateway, "stream_unavailable", err.Error())
+			return
+		}
+	} else {
+		frames = stream.NewReader(c.Request.Body, c.ContentType())
+	}
+	defer frames.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	sampler := stream.NewSampler(fps)
+	tenant := c.GetHeader(tenantHeader)
+	end := models.StreamSummary{Reason: "completed"}
+
+	for maxFrames == 0 || end.FramesScored < maxFrames {
+		data, err := frames.Next()
+		if err != nil {
+			switch {
+			case errors.Is(err, io.EOF):
+			case ctx.Err() == context.DeadlineExceeded:
+				end.Reason = "max_duration"
+			case ctx.Err() != nil:
+				end.Reason = "cancelled"
+			default:
+				end.Reason = "error"
+				end.Error = err.Error()
+			}
+			break
+		}
+		end.FramesReceived++
+		received := time.Now()
+		if !sampler.Take(received) {
+			continue
+		}
+
+		result, err := h.scoreFrame(data)
+		if err != nil {
+			_, code := classifyError(err)
+			if code == "internal_error" {
+				code = "invalid_frame"
+			}
+			h.Stats.RecordError(http.StatusUnprocessableEntity, err.Error())
+			c.SSEvent("frame_error", h.localize(c, models.ErrorResponse{Error: err.Error(), Code: code}))
+			c.Writer.Flush()
+			continue
+		}
+		result.Frame = end.FramesReceived
+		result.ReceivedAt = received.UTC()
+		end.FramesScored++
+
+		h.Stats.RecordPrediction(result.ConfidenceScore, result.Prediction == models.LabelCancer, time.Since(received))
+		cost := h.Footprint.Estimate(result.computeTime)
+		h.Stats.RecordCompute(result.computeTime, cost.EnergyWh, cost.CarbonGrams)
+		h.Events.PublishContext(ctx, events.Event{
+			Type:   events.FrameScored,
+			Tenant: tenant,
+			Data:   events.Frame{Frame: result.FramePrediction, ComputeTime: result.computeTime},
+		})
+
+		c.SSEvent("prediction", result)
+		c.Writer.Flush()
+	}
+	if maxFrames > 0 && end.FramesScored >= maxFrames {
+		end.Reason = "max_frames"
+	}
+
+	c.SSEvent("end", end)
+	c.Writer.Flush()
+}
+
+// frameResult is a scored frame plus bookkeeping not sent to the client.
+type frameResult struct {
+	models.FramePrediction
+	computeTime time.Duration
+}
+
+// scoreFrame decodes and scores one encoded frame.
+func (h *Handler) scoreFrame(data []byte) (frameResult, error) {
+	img, err := h.decodeImage(data, h.DecodeOptions)
+	if err == nil {
+		err = h.Faults.Decode()
+	}
+	if err != nil {
+		return frameResult{}, err
+	}
+	if err := h.checkInDistribution(img); err != nil {
+		return frameResult{}, err
+	}
+	start := time.Now()
+	if err := h.Faults.Inference(context.Background()); err != nil {
+		return frameResult{}, err
+	}
+	engine, served := h.ServedModel()
+	prediction, err := h.runModel(engine, preprocess.TensorFor(engine, img))
+	if err != nil {
+		return frameResult{}, err
+	}
+	score := float64(prediction[0])
+	return frameResult{
+		FramePrediction: models.FramePrediction{
+			Prediction:      models.LabelFor(score, decisionThreshold(served)),
+			ConfidenceScore: score,
+			ModelName:       served.Name,
+			ModelThreshold:  decisionThreshold(served),
+		},
+		computeTime: time.Since(start),
+	}, nil
+}
+
+// streamSourceAllowed reports whether a source URL matches the allowlist
+// and, if a residency policy is active, stays in region.
+func (h *Handler) streamSourceAllowed(source string) bool {
+	allowed := false
+	for _, prefix := range h.StreamSources {
+		if strings.HasPrefix(source, prefix) {
+			allowed = true
+			break
+		}
+	}
+	return allowed && h.Residency.CheckBackend("stream source", source) == nil
+}
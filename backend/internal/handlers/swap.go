@@ -0,0 +1,237 @@
+// backend/internal/handlers/swap.go
+/*
+ * This file lets the served model be replaced while the service runs.
+ *
+ * The service keeps two engine slots. The active slot serves predictions;
+ * the standby slot holds a model that has been loaded and validated but
+ * receives no traffic. Switching exchanges the two under a lock, so it is
+ * instant and atomic, and the model just replaced stays loaded in standby:
+ * reverting is one more call, with no download or load. Scheduled swaps
+ * go through the same slots. Models pushed out of standby can be kept
+ * loaded by version too (see registry.go).
+ *
+ *   GET  /admin/model/slots     the active and standby models
+ *   POST /admin/model/standby   load a model into standby (background job)
+ *   POST /admin/model/switch    make the standby model active
+ *   POST /admin/model/revert    undo the last switch
+ *
+ * Requests read the engine and its description together, once, so a
+ * request in flight during a switch finishes on the model it started with
+ * and reports that model's name. Keeping the standby loaded costs its
+ * memory, which counts against the model memory budget.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/events"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/selfcheck"
+)
+
+var (
+	errNoStandby       = errors.New("no model is loaded in standby")
+	errNothingToRevert = errors.New("the standby model is not the one replaced by the last switch")
+)
+
+// modelSlot is a loaded model and its description.
+type modelSlot struct {
+	engine Predictor
+	info   models.ModelInfo
+}
+
+// modelDescriber is implemented by engines that report how they were
+// loaded (optimizations, precision, memory).
+type modelDescriber interface {
+	Describe(info *models.ModelInfo)
+}
+
+// ServedModel returns the engine currently serving predictions and its
+// description.
+func (h *Handler) ServedModel() (Predictor, models.ModelInfo) {
+	h.modelMu.RLock()
+	defer h.modelMu.RUnlock()
+	return h.InferenceEngine, h.Model
+}
+
+// StandbyModel returns the description of the standby model; ok is false
+// when the slot is empty.
+func (h *Handler) StandbyModel() (info models.ModelInfo, ok bool) {
+	h.modelMu.RLock()
+	defer h.modelMu.RUnlock()
+	if h.standby == nil {
+		return models.ModelInfo{}, false
+	}
+	return h.standby.info, true
+}
+
+// LoadModel loads the model at ref with LoadEngine and validates it with
+// a sanity inference. info, which names the model, is completed with the
+// source, build and load times and how the engine was loaded.
+func (h *Handler) LoadModel(ctx context.Context, ref string, info models.ModelInfo) (Predictor, models.ModelInfo, error) {
+	engine, err := h.LoadEngine(ctx, ref)
+	if err != nil {
+		return nil, info, fmt.Errorf("load: %w", err)
+	}
+	if _, err := selfcheck.SanityInference(engine)(ctx); err != nil {
+		return nil, info, fmt.Errorf("validate: %w", err)
+	}
+	info.Source = ref
+	info.Path = ""
+	if !strings.Contains(ref, "://") {
+		info.Path = ref
+	}
+	info.LoadedAt = time.Now().UTC()
+	if h.ArtifactTime != nil {
+		if built, err := h.ArtifactTime(ctx, ref); err == nil {
+			built = built.UTC()
+			info.BuiltAt = &built
+		} else {
+			slog.WarnContext(ctx, "model age: build time unknown", "model", ref, "error", err)
+		}
+	}
+	if d, ok := engine.(modelDescriber); ok {
+		d.Describe(&info)
+	}
+	info.Threshold = h.thresholdFor(ctx, ref, info)
+	return engine, info, nil
+}
+
+// thresholdFor resolves the decision threshold of the model at ref.
+func (h *Handler) thresholdFor(ctx context.Context, ref string, info models.ModelInfo) float64 {
+	if h.ModelThreshold == nil {
+		return DefaultThreshold
+	}
+	return h.ModelThreshold(ctx, ref, info)
+}
+
+// decisionThreshold returns the threshold the scores of a loaded model
+// are labelled with.
+func decisionThreshold(info models.ModelInfo) float64 {
+	if info.Threshold == 0 {
+		return DefaultThreshold
+	}
+	return info.Threshold
+}
+
+// SwapModel makes engine the served model and keeps the one it replaces
+// in standby, so the swap can be reverted. It returns the description of
+// the replaced model.
+func (h *Handler) SwapModel(engine Predictor, info models.ModelInfo) models.ModelInfo {
+	h.modelMu.Lock()
+	previous := modelSlot{engine: h.InferenceEngine, info: h.Model}
+	h.InferenceEngine, h.Model = engine, info
+	h.retain(h.standby)
+	h.standby, h.revertible = &previous, true
+	h.modelMu.Unlock()
+
+	h.publishSwap(previous.info, info)
+	return previous.info
+}
+
+// switchModel exchanges the active and standby slots. With revert set it
+// only does so when the standby holds the model the last switch replaced.
+func (h *Handler) switchModel(revert bool) (previous, current models.ModelInfo, err error) {
+	h.modelMu.Lock()
+	switch {
+	case h.standby == nil:
+		err = errNoStandby
+	case revert && !h.revertible:
+		err = errNothingToRevert
+	default:
+		previous = h.Model
+		next := h.standby
+		h.standby = &modelSlot{engine: h.InferenceEngine, info: h.Model}
+		h.InferenceEngine, h.Model = next.engine, next.info
+		h.revertible = true
+		current = h.Model
+	}
+	h.modelMu.Unlock()
+
+	if err == nil {
+		h.publishSwap(previous, current)
+	}
+	return previous, current, err
+}
+
+func (h *Handler) publishSwap(previous, current models.ModelInfo) {
+	h.Events.Publish(events.Event{
+		Type: events.ModelSwapped,
+		Data: events.ModelSwap{Previous: previous, Current: current},
+	})
+}
+
+// ModelSlots reports the active and standby models.
+func (h *Handler) ModelSlots(c *gin.Context) {
+	_, active := h.ServedModel()
+	resp := models.ModelSlots{Active: active}
+	if standby, ok := h.StandbyModel(); ok {
+		resp.Standby = &standby
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// LoadStandbyModel starts a job that loads and validates a model and puts
+// it in the standby slot, replacing whatever was there. Traffic is not
+// affected until the model is switched in.
+func (h *Handler) LoadStandbyModel(c *gin.Context) {
+	var req models.StandbyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error(), Code: "invalid_request"})
+		return
+	}
+	if req.Name == "" {
+		_, active := h.ServedModel()
+		req.Name = active.Name
+	}
+
+	job := h.Jobs.Submit(context.Background(), "model_standby", c.GetHeader(tenantHeader), func(ctx context.Context, report func(float64)) (any, error) {
+		engine, info, err := h.LoadModel(ctx, req.ModelRef, models.ModelInfo{Name: req.Name, Version: req.Version})
+		if err != nil {
+			return nil, err
+		}
+		h.modelMu.Lock()
+		h.retain(h.standby)
+		h.standby, h.revertible = &modelSlot{engine: engine, info: info}, false
+		h.modelMu.Unlock()
+		return info, nil
+	})
+	c.JSON(http.StatusAccepted, job)
+}
+
+// SwitchModel makes the standby model active; the previously active model
+// moves to standby.
+func (h *Handler) SwitchModel(c *gin.Context) {
+	h.respondSwitch(c, false)
+}
+
+// RevertModel undoes the last switch or scheduled swap.
+func (h *Handler) RevertModel(c *gin.Context) {
+	h.respondSwitch(c, true)
+}
+
+func (h *Handler) respondSwitch(c *gin.Context, revert bool) {
+	previous, current, err := h.switchModel(revert)
+	switch {
+	case errors.Is(err, errNoStandby):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: err.Error(), Code: "no_standby_model"})
+		return
+	case errors.Is(err, errNothingToRevert):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: err.Error(), Code: "nothing_to_revert"})
+		return
+	}
+	c.JSON(http.StatusOK, models.ModelSlots{Active: current, Standby: &previous})
+}
@@ -0,0 +1,79 @@
+// backend/internal/handlers/tensorstats.go
+/*
+ * This file summarises model inputs for debugging.
+ *
+ * With DebugTensors set, every prediction logs, under its prediction ID,
+ * the decoded study's size and pixel type and the statistics of each
+ * channel of the input tensor: minimum, maximum, mean and the number of
+ * NaNs. When predictions look wrong, the log then shows whether the model
+ * was given what it was trained on. POST /api/v1/preprocess/debug reports
+ * the same statistics for a single study on demand.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+	"gorgonia.org/tensor"
+)
+
+// logTensorStats logs the decoded study and model input of a prediction
+// at debug level.
+func logTensorStats(ctx context.Context, predictionID string, img image.Image, input tensor.Tensor, profile preprocess.Profile) {
+	b := img.Bounds()
+	var channels []string
+	for _, ch := range channelStats(input, profile) {
+		channels = append(channels, fmt.Sprintf("%s min %g max %g mean %.3f NaN %d", ch.Name, ch.Min, ch.Max, ch.Mean, ch.NaNs))
+	}
+	slog.DebugContext(ctx, "tensor statistics",
+		"prediction_id", predictionID, "image", fmt.Sprintf("%dx%d %T", b.Dx(), b.Dy(), img),
+		"input_shape", fmt.Sprint(input.Shape()), "channels", strings.Join(channels, "; "))
+}
+
+// channelStats computes the statistics of each channel of an input built
+// with profile.
+func channelStats(input tensor.Tensor, profile preprocess.Profile) []models.ChannelStats {
+	data := input.Data().([]float32)
+	names := profile.ChannelNames()
+	stats := make([]models.ChannelStats, len(names))
+	for i, name := range names {
+		stats[i] = models.ChannelStats{Name: name, Min: math.Inf(1), Max: math.Inf(-1)}
+	}
+	pixels := len(data) / len(stats)
+	for i, v := range data {
+		ch := i % len(stats)
+		if profile.Layout == preprocess.LayoutNCHW {
+			ch = i / pixels
+		}
+		s := &stats[ch]
+		f := float64(v)
+		if math.IsNaN(f) {
+			s.NaNs++
+			continue
+		}
+		s.Min = math.Min(s.Min, f)
+		s.Max = math.Max(s.Max, f)
+		s.Mean += f
+	}
+	for i := range stats {
+		if n := len(data)/len(stats) - stats[i].NaNs; n > 0 {
+			stats[i].Mean /= float64(n)
+		} else {
+			// Nothing but NaNs: no meaningful range.
+			stats[i].Min, stats[i].Max = 0, 0
+		}
+	}
+	return stats
+}
@@ -0,0 +1,234 @@
+// backend/internal/handlers/upload.go
+/*
+ * This file contains the streaming multipart parser used for study uploads.
+ *
+ * Gin's default form parsing buffers every part of the request (up to 32
+ * MiB in memory, the rest in temp files) before the handler runs, with no
+ * upper bound on the total. Large-study uploads therefore held several
+ * copies of the image in memory at once. Here the body is read part by
+ * part instead: form fields are capped, only the `image` part is kept, and
+ * it is held in memory only while it stays below the spool threshold.
+ * Larger images are spooled to disk while they arrive and read back into a
+ * single, exactly sized buffer once complete, so a slow client trickling
+ * in a large study does not pin memory for the whole transfer.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UploadLimits bounds how uploads are received.
+type UploadLimits struct {
+	// MaxImageBytes is the largest accepted image part.
+	MaxImageBytes int64
+	// SpoolThreshold is the image size above which the upload is spooled
+	// to disk while it is received.
+	SpoolThreshold int64
+	// SpoolDir holds spooled uploads; empty means the OS temp directory.
+	SpoolDir string
+	// MaxBatchImages and MaxBatchBytes bound a batch upload: the number
+	// of images and their total size, also once unpacked from an archive.
+	MaxBatchImages int
+	MaxBatchBytes  int64
+}
+
+const (
+	// DefaultMaxImageBytes comfortably fits a full-field digital
+	// mammogram exported as 16-bit PNG.
+	DefaultMaxImageBytes = 128 << 20
+	// DefaultSpoolThreshold matches a typical compressed screening image.
+	DefaultSpoolThreshold = 8 << 20
+	// DefaultMaxBatchImages covers the views of a few screening exams.
+	DefaultMaxBatchImages = 32
+	// DefaultMaxBatchBytes bounds the images of one batch.
+	DefaultMaxBatchBytes = 512 << 20
+
+	// maxFieldBytes bounds each non-file form field.
+	maxFieldBytes = 64 << 10
+	// maxFormParts bounds the number of parts in one upload.
+	maxFormParts = 64
+)
+
+// errUploadTooLarge is returned when the image exceeds MaxImageBytes.
+var errUploadTooLarge = errors.New("upload too large")
+
+// errImageRequired is returned when the form has no image part.
+var errImageRequired = errors.New("image file is required")
+
+// upload is a parsed study upload.
+type upload struct {
+	image    []byte
+	filename string
+}
+
+// readUpload parses the multipart request body as a stream, or a JSON
+// upload (see jsonupload.go). The form fields are made available through
+// c.PostForm as usual.
+func (h *Handler) readUpload(c *gin.Context) (*upload, error) {
+	if isJSONUpload(c) {
+		return h.readJSONUpload(c)
+	}
+	limits := h.uploadLimits()
+	var up *upload
+	err := readForm(c, limits.MaxImageBytes, func(part *multipart.Part) error {
+		if part.FormName() != "image" || up != nil {
+			return drainPart(part)
+		}
+		data, err := receiveImage(part, limits)
+		if err != nil {
+			return err
+		}
+		up = &upload{image: data, filename: part.FileName()}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if up == nil {
+		return nil, errImageRequired
+	}
+	return up, nil
+}
+
+// uploadLimits returns h.Uploads with the defaults filled in.
+func (h *Handler) uploadLimits() UploadLimits {
+	limits := h.Uploads
+	if limits.MaxImageBytes <= 0 {
+		limits.MaxImageBytes = DefaultMaxImageBytes
+	}
+	if limits.SpoolThreshold <= 0 {
+		limits.SpoolThreshold = DefaultSpoolThreshold
+	}
+	if limits.MaxBatchImages <= 0 {
+		limits.MaxBatchImages = DefaultMaxBatchImages
+	}
+	if limits.MaxBatchBytes <= 0 {
+		limits.MaxBatchBytes = DefaultMaxBatchBytes
+	}
+	return limits
+}
+
+// readForm reads a multipart body of at most fileBytes of files plus the
+// capped form fields, handing every file part to onFile. The fields are
+// made available through c.PostForm.
+func readForm(c *gin.Context, fileBytes int64, onFile func(*multipart.Part) error) error {
+	// The body can never legitimately exceed the files plus the fields.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, fileBytes+maxFormParts*maxFieldBytes)
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return errImageRequired
+	}
+
+	fields := make(url.Values)
+	for parts := 0; ; parts++ {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return uploadError(err)
+		}
+		if parts == maxFormParts {
+			part.Close()
+			return fmt.Errorf("%w: more than %d form parts", errUploadTooLarge, maxFormParts)
+		}
+
+		if part.FileName() == "" {
+			name := part.FormName()
+			value, err := io.ReadAll(io.LimitReader(part, maxFieldBytes+1))
+			if err != nil {
+				part.Close()
+				return uploadError(err)
+			}
+			if len(value) > maxFieldBytes {
+				part.Close()
+				return fmt.Errorf("%w: field %s exceeds %d bytes", errUploadTooLarge, name, maxFieldBytes)
+			}
+			fields.Add(name, string(value))
+		} else if err := onFile(part); err != nil {
+			part.Close()
+			return err
+		}
+		part.Close()
+	}
+
+	c.Request.PostForm = fields
+	c.Request.Form = fields
+	return nil
+}
+
+// drainPart discards a file part the form does not expect.
+func drainPart(part io.Reader) error {
+	if _, err := io.Copy(io.Discard, part); err != nil {
+		return uploadError(err)
+	}
+	return nil
+}
+
+// receiveImage reads the image part, spooling it to disk once it grows
+// past the threshold.
+func receiveImage(part io.Reader, limits UploadLimits) ([]byte, error) {
+	limited := io.LimitReader(part, limits.MaxImageBytes+1)
+
+	var head bytes.Buffer
+	n, err := head.ReadFrom(io.LimitReader(limited, limits.SpoolThreshold+1))
+	if err != nil {
+		return nil, uploadError(err)
+	}
+	if n > limits.MaxImageBytes {
+		return nil, fmt.Errorf("%w: image exceeds %d bytes", errUploadTooLarge, limits.MaxImageBytes)
+	}
+	if n <= limits.SpoolThreshold {
+		return head.Bytes(), nil
+	}
+
+	f, err := os.CreateTemp(limits.SpoolDir, "upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("spool upload: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if _, err := head.WriteTo(f); err != nil {
+		return nil, fmt.Errorf("spool upload: %w", err)
+	}
+	total, err := io.Copy(f, limited)
+	if err != nil {
+		return nil, uploadError(err)
+	}
+	total += n
+	if total > limits.MaxImageBytes {
+		return nil, fmt.Errorf("%w: image exceeds %d bytes", errUploadTooLarge, limits.MaxImageBytes)
+	}
+
+	data := make([]byte, total)
+	if _, err := f.ReadAt(data, 0); err != nil {
+		return nil, fmt.Errorf("read spooled upload: %w", err)
+	}
+	return data, nil
+}
+
+// uploadError maps a body read failure, turning the MaxBytesReader limit
+// into errUploadTooLarge.
+func uploadError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return fmt.Errorf("%w: request exceeds %d bytes", errUploadTooLarge, tooLarge.Limit)
+	}
+	return fmt.Errorf("read upload: %w", err)
+}
@@ -0,0 +1,184 @@
+// backend/internal/handlers/webhooks.go
+/*
+ * This file contains the tenant webhook management API.
+ *
+ *   GET    /api/v1/webhooks                    list the tenant's webhooks
+ *   POST   /api/v1/webhooks                    register a webhook
+ *   GET    /api/v1/webhooks/:id                one webhook
+ *   PATCH  /api/v1/webhooks/:id                change url, events, ...
+ *   DELETE /api/v1/webhooks/:id                remove a webhook
+ *   POST   /api/v1/webhooks/:id/rotate-secret  issue a new signing secret
+ *
+ * Every call acts on the tenant of the request (X-Tenant-ID, or the
+ * caller's own tenant under an access policy). The signing secret is only
+ * returned by the create and rotate calls.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/josephed37/mammoscan-AI/backend/internal/listing"
+	"github.com/josephed37/mammoscan-AI/backend/internal/webhook"
+)
+
+// webhookListing declares the sortable and filterable webhook fields.
+var webhookListing = listing.Spec[webhook.Endpoint]{
+	Fields: map[string]listing.Field[webhook.Endpoint]{
+		"id":          {Kind: listing.String, Get: func(e webhook.Endpoint) any { return e.ID }},
+		"url":         {Kind: listing.String, Get: func(e webhook.Endpoint) any { return e.URL }},
+		"description": {Kind: listing.String, Get: func(e webhook.Endpoint) any { return e.Description }},
+		"disabled":    {Kind: listing.String, Get: func(e webhook.Endpoint) any { return strconv.FormatBool(e.Disabled) }},
+		"created_at":  {Kind: listing.Time, Get: func(e webhook.Endpoint) any { return e.CreatedAt }},
+		"updated_at":  {Kind: listing.Time, Get: func(e webhook.Endpoint) any { return e.UpdatedAt }},
+	},
+	ID:           func(e webhook.Endpoint) string { return e.ID },
+	DefaultSort:  "created_at",
+	DefaultLimit: 50,
+	MaxLimit:     100,
+}
+
+// ListWebhooks lists the tenant's webhooks using the shared
+// sort/filter/cursor grammar.
+func (h *Handler) ListWebhooks(c *gin.Context) {
+	tenant, ok := h.webhookTenant(c)
+	if !ok {
+		return
+	}
+	page, ok := listPage(h, c, h.Webhooks.Registry().List(tenant), webhookListing)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, withCursor(gin.H{"webhooks": page.Items, "events": webhook.Events}, page.NextCursor))
+}
+
+// CreateWebhook registers a webhook for the tenant.
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	tenant, ok := h.webhookTenant(c)
+	if !ok {
+		return
+	}
+	var spec webhook.Spec
+	if err := c.ShouldBindJSON(&spec); err != nil {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	if !h.webhookURLAllowed(c, spec) {
+		return
+	}
+	e, err := h.Webhooks.Registry().Create(tenant, spec)
+	if err != nil {
+		h.respondWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, e)
+}
+
+// GetWebhook returns one of the tenant's webhooks.
+func (h *Handler) GetWebhook(c *gin.Context) {
+	tenant, ok := h.webhookTenant(c)
+	if !ok {
+		return
+	}
+	e, err := h.Webhooks.Registry().Get(tenant, c.Param("id"))
+	if err != nil {
+		h.respondWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, e)
+}
+
+// UpdateWebhook changes the fields present in the request body.
+func (h *Handler) UpdateWebhook(c *gin.Context) {
+	tenant, ok := h.webhookTenant(c)
+	if !ok {
+		return
+	}
+	var spec webhook.Spec
+	if err := c.ShouldBindJSON(&spec); err != nil {
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	if !h.webhookURLAllowed(c, spec) {
+		return
+	}
+	e, err := h.Webhooks.Registry().Update(tenant, c.Param("id"), spec)
+	if err != nil {
+		h.respondWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, e)
+}
+
+// DeleteWebhook removes one of the tenant's webhooks.
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	tenant, ok := h.webhookTenant(c)
+	if !ok {
+		return
+	}
+	if err := h.Webhooks.Registry().Delete(tenant, c.Param("id")); err != nil {
+		h.respondWebhookError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RotateWebhookSecret replaces a webhook's signing secret. The old secret
+// stops working immediately.
+func (h *Handler) RotateWebhookSecret(c *gin.Context) {
+	tenant, ok := h.webhookTenant(c)
+	if !ok {
+		return
+	}
+	e, err := h.Webhooks.Registry().RotateSecret(tenant, c.Param("id"))
+	if err != nil {
+		h.respondWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, e)
+}
+
+// webhookTenant returns the tenant the request acts for. Webhooks always
+// belong to a tenant, so requests without one are refused.
+func (h *Handler) webhookTenant(c *gin.Context) (string, bool) {
+	tenant := c.GetHeader(tenantHeader)
+	if tenant == "" {
+		h.respondErrorCode(c, http.StatusBadRequest, "tenant_required", "webhooks are managed per tenant; set "+tenantHeader)
+		return "", false
+	}
+	return tenant, true
+}
+
+// webhookURLAllowed refuses destinations outside the deployment's region
+// when a residency policy is active.
+func (h *Handler) webhookURLAllowed(c *gin.Context, spec webhook.Spec) bool {
+	if spec.URL == nil {
+		return true
+	}
+	if err := h.Residency.CheckBackend("webhook", *spec.URL); err != nil {
+		h.respondErrorCode(c, http.StatusForbidden, "residency_violation", err.Error())
+		return false
+	}
+	return true
+}
+
+func (h *Handler) respondWebhookError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, webhook.ErrNotFound):
+		h.respondErrorCode(c, http.StatusNotFound, "webhook_not_found", err.Error())
+	case errors.Is(err, webhook.ErrInvalid):
+		h.respondErrorCode(c, http.StatusBadRequest, "invalid_webhook", err.Error())
+	case errors.Is(err, webhook.ErrLimit):
+		h.respondErrorCode(c, http.StatusConflict, "webhook_limit_reached", err.Error())
+	default:
+		h.respondError(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,125 @@
+// backend/internal/i18n/i18n.go
+/*
+ * This file implements localization of API error messages.
+ *
+ * Error codes are the stable contract; the human-readable message is what
+ * clinical UIs pass through to users, and several sites need it in their
+ * own language. A Catalog maps error codes to translated messages per
+ * language and negotiates the best language for an Accept-Language header.
+ *
+ * English is the source language: it is what the handlers write, so it
+ * needs no catalog. Translations ship embedded (see locales/) and sites can
+ * add languages or override wording with a directory of <lang>.json files.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+//go:embed locales/*.json
+var builtin embed.FS
+
+// Catalog holds translated error messages keyed by language and code.
+// It is built at startup and read-only afterwards.
+type Catalog struct {
+	tags     []language.Tag // tags[0] is always English
+	messages map[language.Tag]map[string]string
+	matcher  language.Matcher
+}
+
+// New returns a catalog containing the built-in translations.
+func New() (*Catalog, error) {
+	c := &Catalog{
+		tags:     []language.Tag{language.English},
+		messages: make(map[language.Tag]map[string]string),
+	}
+	if err := c.load(builtin, "locales"); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// LoadDir merges every <lang>.json file in dir into the catalog. Entries
+// override the built-in wording for the same language and code.
+func (c *Catalog) LoadDir(dir string) error {
+	return c.load(os.DirFS(dir), ".")
+}
+
+func (c *Catalog) load(fsys fs.FS, dir string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(path.Base(file), ".json")
+		tag, err := language.Parse(name)
+		if err != nil {
+			return fmt.Errorf("locale file %s: %w", file, err)
+		}
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("locale file %s: %w", file, err)
+		}
+
+		if _, ok := c.messages[tag]; !ok {
+			c.messages[tag] = make(map[string]string)
+			if tag != language.English {
+				c.tags = append(c.tags, tag)
+			}
+		}
+		maps.Copy(c.messages[tag], messages)
+	}
+	c.matcher = language.NewMatcher(c.tags)
+	return nil
+}
+
+// Languages lists the supported languages, English first.
+func (c *Catalog) Languages() []string {
+	out := make([]string, len(c.tags))
+	for i, t := range c.tags {
+		out[i] = t.String()
+	}
+	return out
+}
+
+// Negotiate picks the supported language that best matches an
+// Accept-Language header, falling back to English.
+func (c *Catalog) Negotiate(acceptLanguage string) language.Tag {
+	if acceptLanguage == "" {
+		return language.English
+	}
+	prefs, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(prefs) == 0 {
+		return language.English
+	}
+	_, i, confidence := c.matcher.Match(prefs...)
+	if confidence == language.No {
+		return language.English
+	}
+	return c.tags[i]
+}
+
+// Message returns the translation of the error code in lang, if any.
+func (c *Catalog) Message(lang language.Tag, code string) (string, bool) {
+	msg, ok := c.messages[lang][code]
+	return msg, ok && msg != ""
+}
@@ -0,0 +1,251 @@
+// backend/internal/inference/batch.go
+/*
+ * This file implements batched inference.
+ *
+ * Scoring several studies in one graph execution amortises its fixed
+ * cost. Concurrent requests are coalesced by a Batcher: while the engine
+ * is busy, new inputs queue up and are scored together once it is free,
+ * so batching costs no latency when the service is idle. With MaxWait set,
+ * a batch also waits that long for company before it starts.
+ *
+ * With TargetP95 set, the batch size and wait are tuned to the load
+ * within MaxSize and MaxWait (see batchtune.go).
+ *
+ * A model can only be run on a batch if its input has a free batch
+ * dimension. The first time a model refuses one, its batches are scored
+ * one study at a time from then on, which is what they cost before.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package inference
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gorgonia.org/tensor"
+)
+
+// BatchPredictor scores several inputs at once; results are in input
+// order.
+type BatchPredictor interface {
+	PredictBatch(inputs []tensor.Tensor) ([][]float32, error)
+}
+
+// PredictBatch scores inputs of identical shape, each with a batch
+// dimension of 1, in one graph execution when the model allows it.
+func (o *ONNXInference) PredictBatch(inputs []tensor.Tensor) ([][]float32, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(inputs) > 1 && !o.fixedBatch && sameShape(inputs) {
+		outputs, err := o.predictBatched(inputs)
+		if err == nil {
+			return outputs, nil
+		}
+		if !errors.Is(err, ErrModelIncompatible) {
+			return nil, err
+		}
+		o.fixedBatch = true
+		log.Printf("Batched inference unavailable, scoring batches one study at a time: %v", err)
+	}
+	outputs := make([][]float32, len(inputs))
+	for i, input := range inputs {
+		out, err := o.predict(input)
+		if err != nil {
+			return nil, err
+		}
+		// The backend reuses its output buffer between runs.
+		outputs[i] = append([]float32(nil), out...)
+	}
+	return outputs, nil
+}
+
+func sameShape(inputs []tensor.Tensor) bool {
+	for _, t := range inputs[1:] {
+		if !t.Shape().Eq(inputs[0].Shape()) {
+			return false
+		}
+	}
+	return true
+}
+
+// predictBatched stacks inputs along the batch dimension and splits the
+// output evenly between them; o.mu must be held.
+func (o *ONNXInference) predictBatched(inputs []tensor.Tensor) ([][]float32, error) {
+	stacked, err := tensor.Concat(0, inputs[0], inputs[1:]...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: stack batch: %w", ErrModelIncompatible, err)
+	}
+	out, err := o.predict(stacked)
+	if err != nil {
+		return nil, err
+	}
+	if len(out)%len(inputs) != 0 {
+		return nil, fmt.Errorf("%w: %d outputs for a batch of %d", ErrModelIncompatible, len(out), len(inputs))
+	}
+	per := len(out) / len(inputs)
+	outputs := make([][]float32, len(inputs))
+	for i := range outputs {
+		outputs[i] = append([]float32(nil), out[i*per:(i+1)*per]...)
+	}
+	return outputs, nil
+}
+
+// BatcherConfig controls a Batcher.
+type BatcherConfig struct {
+	// MaxSize is the largest batch (default DefaultMaxBatchSize).
+	MaxSize int
+	// MaxWait is how long a batch may wait to fill before it starts; zero
+	// starts it as soon as the engine is free.
+	MaxWait time.Duration
+	// TargetP95, when set, tunes the batch size and wait, up to MaxSize
+	// and MaxWait, to keep the 95th percentile latency of batched
+	// predictions under it.
+	TargetP95 time.Duration
+}
+
+// DefaultMaxBatchSize bounds a batch when BatcherConfig.MaxSize is unset.
+const DefaultMaxBatchSize = 8
+
+// Batcher coalesces concurrent predictions on the same engine into
+// batches.
+type Batcher struct {
+	cfg BatcherConfig
+
+	mu     sync.Mutex
+	queues map[batchKey]*batchQueue
+	// size and wait are the limits in force: cfg's, or the tuner's.
+	size  int
+	wait  time.Duration
+	tuner *batchTuner
+}
+
+// batchKey identifies the inputs that can share a batch.
+type batchKey struct {
+	engine BatchPredictor
+	shape  string
+}
+
+// batchQueue holds the inputs waiting for one engine.
+type batchQueue struct {
+	pending []*batchItem
+	running bool
+	timer   *time.Timer
+}
+
+type batchItem struct {
+	input  tensor.Tensor
+	queued time.Time
+	output []float32
+	err    error
+	done   chan struct{}
+}
+
+// NewBatcher returns a batcher.
+func NewBatcher(cfg BatcherConfig) *Batcher {
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = DefaultMaxBatchSize
+	}
+	b := &Batcher{cfg: cfg, queues: make(map[batchKey]*batchQueue), size: cfg.MaxSize, wait: cfg.MaxWait}
+	if cfg.TargetP95 > 0 {
+		// Waiting adds latency; the tuner adds it only where it pays.
+		b.tuner = &batchTuner{target: cfg.TargetP95}
+		b.wait = 0
+	}
+	return b
+}
+
+// Predict scores input on engine as part of a batch.
+func (b *Batcher) Predict(engine BatchPredictor, input tensor.Tensor) ([]float32, error) {
+	item := &batchItem{input: input, queued: time.Now(), done: make(chan struct{})}
+	key := batchKey{engine: engine, shape: fmt.Sprint(input.Shape())}
+
+	b.mu.Lock()
+	q := b.queues[key]
+	if q == nil {
+		q = &batchQueue{}
+		b.queues[key] = q
+	}
+	q.pending = append(q.pending, item)
+	switch {
+	case q.running:
+		// The running batch picks this one up when it finishes.
+	case b.wait <= 0 || len(q.pending) >= b.size:
+		b.start(key, q)
+	case q.timer == nil:
+		q.timer = time.AfterFunc(b.wait, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			q.timer = nil
+			if !q.running && len(q.pending) > 0 {
+				b.start(key, q)
+			}
+		})
+	}
+	b.mu.Unlock()
+
+	<-item.done
+	return item.output, item.err
+}
+
+// start runs the pending batches of q until none are left; b.mu must be
+// held.
+func (b *Batcher) start(key batchKey, q *batchQueue) {
+	if q.timer != nil {
+		q.timer.Stop()
+		q.timer = nil
+	}
+	q.running = true
+	go func() {
+		b.mu.Lock()
+		for len(q.pending) > 0 {
+			n := min(len(q.pending), b.size)
+			batch := q.pending[:n:n]
+			q.pending = q.pending[n:]
+			b.mu.Unlock()
+			run(key.engine, batch)
+			b.mu.Lock()
+			if b.tuner != nil {
+				b.size, b.wait = b.tuner.observe(batch, b.size, b.wait, b.cfg.MaxWait, b.cfg.MaxSize)
+			}
+		}
+		q.running = false
+		delete(b.queues, key)
+		b.mu.Unlock()
+	}()
+}
+
+// run scores one batch and hands each item its result. If the batch
+// fails, its items are retried one by one, so one bad input does not fail
+// the others.
+func run(engine BatchPredictor, batch []*batchItem) {
+	inputs := make([]tensor.Tensor, len(batch))
+	for i, item := range batch {
+		inputs[i] = item.input
+	}
+	outputs, err := engine.PredictBatch(inputs)
+	if err == nil && len(outputs) != len(batch) {
+		err = fmt.Errorf("%w: %d results for a batch of %d", ErrModelIncompatible, len(outputs), len(batch))
+	}
+	if err != nil && len(batch) > 1 {
+		for _, item := range batch {
+			run(engine, []*batchItem{item})
+		}
+		return
+	}
+	for i, item := range batch {
+		if err != nil {
+			item.err = err
+		} else {
+			item.output = outputs[i]
+		}
+		close(item.done)
+	}
+}
@@ -0,0 +1,166 @@
+// backend/internal/inference/batchtune.go
+/*
+ * This file tunes a Batcher to a latency target.
+ *
+ * The best batch size and wait depend on the hardware and the load: a
+ * batch that amortises well on a large CPU host is mostly queueing on a
+ * small one. With BatcherConfig.TargetP95 set, the batcher measures the
+ * latency of every prediction it handles (queueing plus inference) and,
+ * after every window of tuneWindow predictions, adjusts its limits:
+ *
+ *   p95 above the target      the wait is halved; once there is none
+ *                              left, the batch size shrinks by a quarter.
+ *   p95 below 80% of target   the batch size grows by one while batches
+ *                              fill up; otherwise, if predictions do
+ *                              arrive together, the wait grows by an
+ *                              eighth of MaxWait.
+ *
+ * Waiting only pays when the engine is saturated, and smaller batches
+ * only help when it is not: a longer wait that did not raise throughput,
+ * or a smaller batch that did not lower the p95, is undone and not tried
+ * again for holdWindows windows, twice as long after every further
+ * failure. MaxSize and MaxWait bound the tuning.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package inference
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+const (
+	// tuneWindow is the number of predictions each adjustment is based on.
+	tuneWindow = 64
+	// holdWindows is how many windows a kind of change is first held off
+	// after it did not help.
+	holdWindows = 8
+)
+
+// batchTuner holds the measurements of the current window and the
+// outcome of the last one; the Batcher's mutex guards it.
+type batchTuner struct {
+	target time.Duration
+
+	latencies []time.Duration
+	batches   int
+	started   time.Time
+
+	// The last window's figures.
+	p95         time.Duration
+	throughput  float64
+	meanBatch   float64
+	adjustments int
+
+	// The last change, the limits before it, and how long changes of
+	// that kind are held off once one has not helped.
+	last     tuneChange
+	prevSize int
+	prevWait time.Duration
+	hold     map[tuneChange]int
+	backoff  map[tuneChange]int
+}
+
+// tuneChange is a kind of adjustment that may be undone.
+type tuneChange int
+
+const (
+	noChange tuneChange = iota
+	growWait
+	shrinkSize
+)
+
+// observe records a finished batch and, at the end of a window, returns
+// the new limits.
+func (t *batchTuner) observe(batch []*batchItem, size int, wait, maxWait time.Duration, maxSize int) (int, time.Duration) {
+	now := time.Now()
+	if t.started.IsZero() {
+		t.started = now
+	}
+	for _, item := range batch {
+		t.latencies = append(t.latencies, now.Sub(item.queued))
+	}
+	t.batches++
+	if len(t.latencies) < tuneWindow {
+		return size, wait
+	}
+	if t.hold == nil {
+		t.hold, t.backoff = make(map[tuneChange]int), make(map[tuneChange]int)
+	}
+
+	slices.Sort(t.latencies)
+	p95 := t.latencies[(len(t.latencies)*95+99)/100-1]
+	throughput := float64(len(t.latencies)) / max(now.Sub(t.started).Seconds(), 1e-3)
+	meanBatch := float64(len(t.latencies)) / float64(t.batches)
+	newSize, newWait := size, wait
+	change := noChange
+
+	switch {
+	case t.last == growWait && throughput < t.throughput*1.02,
+		t.last == shrinkSize && p95 >= t.p95:
+		// The change did not pay: with the engine saturated, smaller
+		// batches only queue longer, and waiting only helps when it is.
+		newSize, newWait = t.prevSize, t.prevWait
+		t.backoff[t.last] = min(max(2*t.backoff[t.last], holdWindows), 32*holdWindows)
+		t.hold[t.last] = t.backoff[t.last]
+	case t.last != noChange:
+		t.backoff[t.last] = 0
+		fallthrough
+	default:
+		switch {
+		case p95 > t.target:
+			if wait > 0 {
+				if newWait = wait / 2; newWait < time.Millisecond {
+					newWait = 0
+				}
+			} else if size > 1 && t.hold[shrinkSize] == 0 {
+				newSize, change = size-max(1, size/4), shrinkSize
+			}
+		case p95 < t.target*4/5:
+			if meanBatch >= 0.75*float64(size) && size < maxSize {
+				newSize = size + 1
+			} else if meanBatch > 1 && wait < maxWait && t.hold[growWait] == 0 {
+				newWait, change = min(maxWait, wait+max(maxWait/8, time.Millisecond)), growWait
+			}
+		}
+	}
+	for kind := range t.hold {
+		t.hold[kind] = max(0, t.hold[kind]-1)
+	}
+	t.last, t.prevSize, t.prevWait = change, size, wait
+	t.p95, t.throughput, t.meanBatch = p95, throughput, meanBatch
+	t.latencies, t.batches, t.started = t.latencies[:0], 0, now
+
+	if newSize != size || newWait != wait {
+		t.adjustments++
+		log.Printf("Batch tuning: p95 %s (target %s), %.1f predictions/s, mean batch %.1f; batch size %d, wait %s",
+			p95.Round(time.Millisecond), t.target, throughput, meanBatch, newSize, newWait)
+	}
+	return newSize, newWait
+}
+
+// Status reports the batcher's current limits and, when it is tuned, the
+// last window's measurements.
+func (b *Batcher) Status() models.BatchingStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := models.BatchingStatus{
+		MaxSize:       b.size,
+		MaxWaitMillis: float64(b.wait) / float64(time.Millisecond),
+	}
+	if t := b.tuner; t != nil {
+		status.TargetP95Millis = float64(t.target) / float64(time.Millisecond)
+		status.P95Millis = float64(t.p95) / float64(time.Millisecond)
+		status.Throughput = t.throughput
+		status.MeanBatchSize = t.meanBatch
+		status.Adjustments = t.adjustments
+	}
+	return status
+}
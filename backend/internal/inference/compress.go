@@ -0,0 +1,53 @@
+// backend/internal/inference/compress.go
+/*
+ * This file handles compressed model artifacts.
+ *
+ * Models may be published as Zstandard-compressed `.onnx.zst` files, which
+ * are roughly four times smaller than the raw protobuf and so much quicker
+ * to download and cheaper to store. Compression is detected from the frame
+ * magic number rather than the file name, so a compressed model works
+ * whether it arrives from disk, from object storage or from memory.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package inference
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdMagic starts every Zstandard frame.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// maxModelSize bounds the decompressed model so a corrupt or hostile
+// artifact cannot exhaust memory.
+const maxModelSize = 4 << 30
+
+// IsCompressed reports whether data is a Zstandard-compressed artifact.
+func IsCompressed(data []byte) bool {
+	return bytes.HasPrefix(data, zstdMagic)
+}
+
+// decompress returns data unchanged unless it is Zstandard-compressed, in
+// which case it returns the decompressed model.
+func decompress(data []byte) ([]byte, error) {
+	if !IsCompressed(data) {
+		return data, nil
+	}
+	dec, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(maxModelSize))
+	if err != nil {
+		return nil, fmt.Errorf("zstd decoder: %w", err)
+	}
+	defer dec.Close()
+	out, err := dec.DecodeAll(data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress model: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,115 @@
+// backend/internal/inference/engine.go
+/*
+ * This file defines the interface every inference backend implements and
+ * selects the backend a model is loaded with.
+ *
+ *   gorgonnx     the pure-Go backend (onnx.go), always available. It runs
+ *                a limited operator set, but needs nothing beyond the
+ *                binary, so it is the default and the fallback.
+ *   onnxruntime  ONNX Runtime through its C API (ort.go). It runs the
+ *                full operator set of current PyTorch exports and is much
+ *                faster, but needs CGO, the `onnxruntime` build tag and
+ *                the runtime's shared library at run time.
+ *
+ * Optional capabilities (batching, profiling, Grad-CAM) stay separate
+ * interfaces that callers discover by type assertion, so a backend only
+ * implements what it can do.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package inference
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+	"gorgonia.org/tensor"
+)
+
+// ErrBackendUnavailable is wrapped by errors for a backend that this
+// build or host cannot run.
+var ErrBackendUnavailable = errors.New("inference backend unavailable")
+
+// Engine is a loaded model.
+type Engine interface {
+	// Predict scores a preprocessed input.
+	Predict(input tensor.Tensor) ([]float32, error)
+	// Describe fills in how the model was loaded.
+	Describe(info *models.ModelInfo)
+	// MemoryBytes reports the memory held by the model, or 0 when it was
+	// not measured.
+	MemoryBytes() int64
+	// Optimizations reports the graph optimization passes applied at
+	// load, and Verification how the transformed model compared with the
+	// original; both are empty for backends that optimize internally.
+	Optimizations() []models.GraphOptimization
+	Verification() *models.ModelVerification
+	// InputProfile returns the input pipeline of the model, which inputs
+	// are built with (preprocess.TensorFor).
+	InputProfile() preprocess.Profile
+
+	// whenReleased arranges for release(id) to run once the engine is
+	// garbage collected.
+	whenReleased(release func(uint64), id uint64)
+}
+
+// Backend names an inference backend.
+type Backend string
+
+const (
+	BackendGorgonnx    Backend = "gorgonnx"
+	BackendONNXRuntime Backend = "onnxruntime"
+)
+
+// ParseBackend validates a backend name; "" means gorgonnx.
+func ParseBackend(s string) (Backend, error) {
+	switch b := Backend(strings.ToLower(strings.TrimSpace(s))); b {
+	case "":
+		return BackendGorgonnx, nil
+	case BackendGorgonnx, BackendONNXRuntime:
+		return b, nil
+	}
+	return "", fmt.Errorf("unknown inference backend %q (want %s or %s)", s, BackendGorgonnx, BackendONNXRuntime)
+}
+
+// Load loads the model at modelPath with the backend in opts. The file
+// may be Zstandard-compressed (.onnx.zst).
+func Load(modelPath string, opts Options) (Engine, error) {
+	modelData, err := os.ReadFile(modelPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model file: %w", err)
+	}
+	return LoadBytes(modelData, opts)
+}
+
+// LoadBytes loads a model already in memory with the backend in opts.
+func LoadBytes(modelData []byte, opts Options) (Engine, error) {
+	if opts.Backend == BackendONNXRuntime {
+		return newORTInference(modelData, opts)
+	}
+	if opts.Workers > 1 || len(opts.CPUs) > 0 {
+		return newPool(modelData, opts)
+	}
+	engine, err := NewONNXInferenceFromBytes(modelData, opts)
+	if err != nil {
+		return nil, err
+	}
+	return engine, nil
+}
+
+// describeProfile returns the profile to report in a model's description:
+// nil for the champion model's pipeline.
+func describeProfile(p preprocess.Profile) *preprocess.Profile {
+	resolved, err := p.Resolve()
+	if err != nil || resolved.IsDefault() {
+		return nil
+	}
+	return &resolved
+}
@@ -0,0 +1,259 @@
+// backend/internal/inference/gradcam.go
+/*
+ * This file computes Grad-CAM maps: which locations of the model's last
+ * convolutional feature map drove its score.
+ *
+ * Grad-CAM needs the activations A of that feature map and the gradient
+ * of the score with respect to them. Each channel is weighted by its mean
+ * gradient, and the weighted channels are summed and rectified:
+ *
+ *   cam[y][x] = max(0, Σc mean(∂score/∂A[c]) · A[c][y][x])
+ *
+ * then scaled so its peak is 1. The map has the feature map's resolution
+ * (e.g. 7×7 for a 224×224 input) and covers the whole model input.
+ *
+ * Differentiating adds nodes to the graph and changes how every run is
+ * compiled, so it is never done on the graph that serves predictions.
+ * Engines loaded with Options.GradCAM build a second, differentiable copy
+ * of the model at load time, which doubles the memory the model holds.
+ * Not every graph can be differentiated: some backend operators have no
+ * gradient (GlobalAveragePool and Gemm among them). For those models
+ * GradCAM returns ErrNoGradients and callers fall back to a method that
+ * needs only scores.
+ *
+ * The feature map is found, not configured: it is the last 4-D value in
+ * NCHW layout with more than one row and column, ignoring layout-only
+ * operations (reshapes and transposes).
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package inference
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/owulveryck/onnx-go"
+	"github.com/owulveryck/onnx-go/backend/x/gorgonnx"
+	"gorgonia.org/gorgonia"
+	"gorgonia.org/tensor"
+)
+
+// ErrNoGradients is returned when a Grad-CAM map cannot be computed for a
+// model: Grad-CAM was not enabled, the model has no spatial feature map,
+// or an operator between it and the output has no gradient.
+var ErrNoGradients = errors.New("model does not expose gradients")
+
+// gradCAM is the differentiable copy of a served model.
+type gradCAM struct {
+	mu       sync.Mutex
+	model    *onnx.Model
+	vm       gorgonia.VM
+	features *gorgonia.Node
+	grad     *gorgonia.Node
+}
+
+// GradCAM computes the Grad-CAM map of input, indexed [row][column] over
+// the model input, with values from 0 to 1.
+func (o *ONNXInference) GradCAM(input tensor.Tensor) ([][]float64, error) {
+	if err := o.CheckGradCAM(); err != nil {
+		return nil, err
+	}
+	return o.gradCAM.run(input)
+}
+
+// CheckGradCAM reports why GradCAM is unavailable for the model, or nil.
+func (o *ONNXInference) CheckGradCAM() error {
+	switch {
+	case o.gradCAMErr != nil:
+		return o.gradCAMErr
+	case o.gradCAM == nil:
+		return fmt.Errorf("%w: Grad-CAM is not enabled", ErrNoGradients)
+	}
+	return nil
+}
+
+// newGradCAM loads a differentiable copy of the model in modelData.
+// Operators without a gradient are reported as ErrNoGradients; the
+// backend panics on some of them, which is reported the same way.
+func newGradCAM(modelData []byte) (cam *gradCAM, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			cam, err = nil, fmt.Errorf("%w: %v", ErrNoGradients, r)
+		}
+	}()
+
+	backend := gorgonnx.NewGraph()
+	model := onnx.NewModel(backend)
+	if err := model.UnmarshalBinary(modelData); err != nil {
+		return nil, fmt.Errorf("%w: failed to unmarshal ONNX model: %w", ErrModelIncompatible, err)
+	}
+	g, err := backend.GetExprGraph()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	output, err := graphOutput(g)
+	if err != nil {
+		return nil, err
+	}
+	features, err := featureMap(g)
+	if err != nil {
+		return nil, err
+	}
+	if err := differentiable(g, output, features); err != nil {
+		return nil, err
+	}
+
+	// The score is the first output, as for predictions.
+	flat, err := gorgonia.Reshape(output, tensor.Shape{output.Shape().TotalSize()})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNoGradients, err)
+	}
+	score, err := gorgonia.Slice(flat, gorgonia.S(0))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNoGradients, err)
+	}
+	seed := g.AddNode(gorgonia.NewConstant(float32(1)))
+	grads, err := gorgonia.Backpropagate(gorgonia.Nodes{score}, gorgonia.Nodes{seed}, gorgonia.Nodes{features})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNoGradients, err)
+	}
+	return &gradCAM{model: model, vm: gorgonia.NewTapeMachine(g), features: features, grad: grads[0]}, nil
+}
+
+// graphOutput returns the single output of g.
+func graphOutput(g *gorgonia.ExprGraph) (*gorgonia.Node, error) {
+	var output *gorgonia.Node
+	for _, n := range g.Roots() {
+		if n.Op() == nil {
+			continue
+		}
+		if output != nil {
+			return nil, fmt.Errorf("%w: model has several outputs", ErrNoGradients)
+		}
+		output = n
+	}
+	if output == nil {
+		return nil, fmt.Errorf("%w: model has no output", ErrNoGradients)
+	}
+	return output, nil
+}
+
+// featureMap returns the last spatial feature map of g.
+func featureMap(g *gorgonia.ExprGraph) (*gorgonia.Node, error) {
+	// Sort puts the outputs first, so the first match is the last map.
+	sorted, err := gorgonia.Sort(g)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sort graph: %w", err)
+	}
+	for _, n := range sorted {
+		if n.Op() == nil || n.Dims() != 4 || layoutOnly(n.Op()) {
+			continue
+		}
+		s := n.Shape()
+		if s[0] == 1 && s[2] > 1 && s[3] > 1 && s[1] >= s[2] && s[1] >= s[3] {
+			return n, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no spatial feature map found", ErrNoGradients)
+}
+
+// layoutOnly reports whether op only rearranges its input.
+func layoutOnly(op gorgonia.Op) bool {
+	name := op.String()
+	return strings.HasPrefix(name, "Reshape") || strings.HasPrefix(name, "Aᵀ")
+}
+
+// differentiable checks that every operation between features and output
+// has a gradient.
+func differentiable(g *gorgonia.ExprGraph, output, features *gorgonia.Node) error {
+	reaches := make(map[*gorgonia.Node]bool)
+	var walk func(n *gorgonia.Node) bool
+	walk = func(n *gorgonia.Node) bool {
+		if n == features {
+			return true
+		}
+		if r, ok := reaches[n]; ok {
+			return r
+		}
+		reaches[n] = false
+		// Edges run from an operation to its inputs.
+		for inputs := g.From(n.ID()); inputs.Next(); {
+			if walk(inputs.Node().(*gorgonia.Node)) {
+				reaches[n] = true
+			}
+		}
+		return reaches[n]
+	}
+	if !walk(output) {
+		return fmt.Errorf("%w: output does not depend on the feature map", ErrNoGradients)
+	}
+	for n, r := range reaches {
+		if _, ok := n.Op().(gorgonia.SDOp); r && !ok {
+			return fmt.Errorf("%w: operator %s has no gradient", ErrNoGradients, n.Op())
+		}
+	}
+	return nil
+}
+
+// run computes the map of input.
+func (c *gradCAM) run(input tensor.Tensor) ([][]float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.model.SetInput(0, input); err != nil {
+		return nil, fmt.Errorf("%w: failed to set input: %w", ErrModelIncompatible, err)
+	}
+	c.vm.Reset()
+	if err := c.vm.RunAll(); err != nil {
+		return nil, fmt.Errorf("failed to run model: %w", err)
+	}
+
+	s := c.features.Shape()
+	channels, rows, cols := s[1], s[2], s[3]
+	acts, ok := c.features.Value().Data().([]float32)
+	if !ok {
+		return nil, fmt.Errorf("%w: feature map is not float32", ErrNoGradients)
+	}
+	grads, ok := c.grad.Value().Data().([]float32)
+	if !ok || !c.grad.Shape().Eq(s) {
+		return nil, fmt.Errorf("%w: gradient does not match the feature map", ErrNoGradients)
+	}
+
+	plane := rows * cols
+	cam := make([][]float64, rows)
+	for y := range cam {
+		cam[y] = make([]float64, cols)
+	}
+	for ch := 0; ch < channels; ch++ {
+		var weight float64
+		for _, g := range grads[ch*plane : (ch+1)*plane] {
+			weight += float64(g)
+		}
+		weight /= float64(plane)
+		for i, a := range acts[ch*plane : (ch+1)*plane] {
+			cam[i/cols][i%cols] += weight * float64(a)
+		}
+	}
+
+	peak := 0.0
+	for _, row := range cam {
+		for x, v := range row {
+			row[x] = max(v, 0)
+			peak = max(peak, row[x])
+		}
+	}
+	if peak > 0 {
+		for _, row := range cam {
+			for x := range row {
+				row[x] /= peak
+			}
+		}
+	}
+	return cam, nil
+}
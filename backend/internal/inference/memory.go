@@ -0,0 +1,128 @@
+// backend/internal/inference/memory.go
+/*
+ * This file measures the memory held by loaded models and enforces a
+ * budget on it.
+ *
+ * A model's footprint is the weights plus the buffers of its intermediate
+ * values, which the backend allocates on the first run and keeps for the
+ * following ones. It is measured after a warm-up run on the golden set, so
+ * it reflects what the model holds while serving. Multi-model deployments
+ * (ensembles, experiments, re-scoring jobs) admit each model against the
+ * budget and refuse those that do not fit, instead of running out of
+ * memory at an unpredictable moment.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package inference
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/owulveryck/onnx-go/backend/x/gorgonnx"
+)
+
+// ErrMemoryBudget is wrapped by errors for models that do not fit in the
+// memory budget.
+var ErrMemoryBudget = errors.New("model memory budget exceeded")
+
+// MemoryBytes reports the memory held by the model, or 0 when it was not
+// measured (the model has not run yet).
+func (o *ONNXInference) MemoryBytes() int64 {
+	return o.memoryBytes
+}
+
+func (o *ONNXInference) whenReleased(release func(uint64), id uint64) {
+	runtime.AddCleanup(o, release, id)
+}
+
+// measureMemory sums the memory of the values held by the graph. Values
+// that share storage (reshapes, for instance) are counted once.
+func (o *ONNXInference) measureMemory() int64 {
+	g, ok := o.backend.(*gorgonnx.Graph)
+	if !ok {
+		return 0
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	exprgraph, err := g.GetExprGraph()
+	if err != nil {
+		return 0
+	}
+	seen := make(map[uintptr]bool)
+	var total int64
+	for _, n := range exprgraph.AllNodes() {
+		v := n.Value()
+		if v == nil || seen[v.Uintptr()] {
+			continue
+		}
+		seen[v.Uintptr()] = true
+		total += int64(v.MemSize())
+	}
+	return total
+}
+
+// MemoryBudget tracks the memory of the loaded models against a limit.
+// A model's memory is released once its engine is garbage collected.
+type MemoryBudget struct {
+	limit int64
+
+	mu     sync.Mutex
+	used   int64
+	next   uint64
+	models map[uint64]models.ModelMemory
+}
+
+// NewMemoryBudget returns a budget of limit bytes; zero or less only
+// tracks usage.
+func NewMemoryBudget(limit int64) *MemoryBudget {
+	if limit < 0 {
+		limit = 0
+	}
+	return &MemoryBudget{limit: limit, models: make(map[uint64]models.ModelMemory)}
+}
+
+// Admit accounts for engine, loaded from ref. It fails with
+// ErrMemoryBudget when the model does not fit; the caller must then drop
+// the engine.
+func (b *MemoryBudget) Admit(ref string, engine Engine) error {
+	size := engine.MemoryBytes()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.limit > 0 && b.used+size > b.limit {
+		return fmt.Errorf("%w: %s needs %d bytes, %d of %d in use", ErrMemoryBudget, ref, size, b.used, b.limit)
+	}
+	b.next++
+	id := b.next
+	b.models[id] = models.ModelMemory{Ref: ref, Bytes: size, LoadedAt: time.Now().UTC()}
+	b.used += size
+	engine.whenReleased(b.release, id)
+	return nil
+}
+
+func (b *MemoryBudget) release(id uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.used -= b.models[id].Bytes
+	delete(b.models, id)
+}
+
+// Usage reports the admitted models, oldest first.
+func (b *MemoryBudget) Usage() models.MemoryUsage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	u := models.MemoryUsage{BudgetBytes: b.limit, UsedBytes: b.used}
+	for _, m := range b.models {
+		u.Models = append(u.Models, m)
+	}
+	sort.Slice(u.Models, func(i, j int) bool { return u.Models[i].LoadedAt.Before(u.Models[j].LoadedAt) })
+	return u
+}
@@ -14,32 +14,212 @@
 package inference
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"math"
 	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
 	"github.com/owulveryck/onnx-go"
 	"github.com/owulveryck/onnx-go/backend/x/gorgonnx"
 	"gorgonia.org/tensor"
 )
 
+// ErrModelIncompatible is wrapped by errors caused by a model that does
+// not fit this service: operators the backend cannot run, an input shape
+// other than our tensors', or outputs that are not probabilities.
+var ErrModelIncompatible = errors.New("model is incompatible with this service")
+
 // ONNXInference is a struct that holds the loaded model and its backend.
 // This allows us to maintain the model's state in memory throughout the
 // application's lifecycle, avoiding the need to reload it for every request.
+//
+// The backend graph holds the input and intermediate values of a run, so
+// runs are serialised by mu.
 type ONNXInference struct {
+	mu      sync.Mutex
 	model   *onnx.Model
 	backend onnx.Backend
+
+	optimizations []models.GraphOptimization
+	precision     Precision
+	verification  *models.ModelVerification
+	memoryBytes   int64
+	// fixedBatch is set once the model refused a batched input.
+	fixedBatch bool
+	// gradCAM is the differentiable copy of the model, when Grad-CAM is
+	// enabled and the model supports it; gradCAMErr says why not.
+	gradCAM    *gradCAM
+	gradCAMErr error
+	// profile is the model's input pipeline.
+	profile preprocess.Profile
+}
+
+// Precision is the floating-point precision a model is executed in.
+type Precision string
+
+const (
+	PrecisionFP32 Precision = "fp32"
+	PrecisionFP16 Precision = "fp16"
+)
+
+// ErrPrecisionUnsupported is wrapped by errors for a precision the
+// backend cannot execute.
+var ErrPrecisionUnsupported = errors.New("precision not supported")
+
+// SupportedPrecisions lists the precisions backend can execute. Both
+// compute in fp32 only: Gorgonia has no fp16 kernels, and ONNX Runtime
+// runs a model in the precision it was exported in, so an fp16 model must
+// be exported as one.
+func SupportedPrecisions(backend Backend) []Precision {
+	return []Precision{PrecisionFP32}
+}
+
+// CheckPrecision returns an error wrapping ErrPrecisionUnsupported if
+// backend cannot execute p. A model is never silently run in a precision
+// other than the one requested.
+func CheckPrecision(backend Backend, p Precision) error {
+	if p == "" || slices.Contains(SupportedPrecisions(backend), p) {
+		return nil
+	}
+	return fmt.Errorf("%w: the %s backend runs %v only, not %s", ErrPrecisionUnsupported, backend, SupportedPrecisions(backend), p)
+}
+
+// ParsePrecision validates a precision name; "" means fp32.
+func ParsePrecision(s string) (Precision, error) {
+	switch p := Precision(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return PrecisionFP32, nil
+	case PrecisionFP32, PrecisionFP16:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown precision %q (want %s or %s)", s, PrecisionFP32, PrecisionFP16)
+}
+
+// DefaultTolerance is the output deviation allowed on the golden set when
+// Options.Tolerance is zero; folding reorders floating-point arithmetic,
+// so transformed models are rarely bit-identical to the original.
+const DefaultTolerance = 1e-4
+
+// Options controls how a model is loaded.
+type Options struct {
+	// Backend runs the model (default gorgonnx, see engine.go).
+	Backend Backend
+	// RuntimeLibrary is the path of the ONNX Runtime shared library for
+	// the onnxruntime backend; empty uses the system's default search.
+	RuntimeLibrary string
+	// Optimizations lists the graph optimization passes to apply.
+	Optimizations []string
+	// Precision requests an execution precision for an fp32 model. A
+	// precision the backend does not support fails the load (see
+	// CheckPrecision).
+	Precision Precision
+	// Golden is the golden set: inputs scored by both the original and
+	// the transformed (optimized or reduced-precision) model. The original
+	// is served if any output deviates by more than Tolerance.
+	Golden []tensor.Tensor
+	// Tolerance is the largest output deviation accepted on the golden
+	// set (default DefaultTolerance).
+	Tolerance float64
+	// GradCAM also loads a differentiable copy of the served model, for
+	// Grad-CAM explanations (see gradcam.go).
+	GradCAM bool
+	// Workers is the number of independent instances of a gorgonnx model
+	// run concurrently (see pool.go); 0 or 1 loads a single instance.
+	Workers int
+	// WorkerWait is how long a prediction waits for a free worker before
+	// failing with ErrSaturated.
+	WorkerWait time.Duration
+	// CPUs pins inference to these logical CPUs. gorgonnx workers each run
+	// on a thread restricted to an even share of them (see pool.go); ONNX
+	// Runtime spreads its intra-op threads over them.
+	CPUs []int
+	// IntraOpThreads and InterOpThreads size ONNX Runtime's thread pools,
+	// within an operator and across independent operators. 0 uses one
+	// intra-op thread per CPU in CPUs or, without CPUs, per GOMAXPROCS,
+	// rather than ONNX Runtime's default of one per host core.
+	IntraOpThreads int
+	InterOpThreads int
+	// Profile is the input pipeline the model was trained with; callers
+	// build its inputs with preprocess.TensorFor. The zero value is the
+	// champion model's pipeline.
+	Profile preprocess.Profile
 }
 
 // NewONNXInference is a constructor function that loads an ONNX model
-// from the specified file path and initializes the inference engine.
-func NewONNXInference(modelPath string) (*ONNXInference, error) {
+// from the specified file path and initializes the inference engine. The
+// file may be Zstandard-compressed (.onnx.zst).
+func NewONNXInference(modelPath string, opts Options) (*ONNXInference, error) {
 	// --- Step 1: Read the Model File ---
 	// We read the entire .onnx model file into a byte slice.
 	modelData, err := os.ReadFile(modelPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read model file: %w", err)
 	}
+	return NewONNXInferenceFromBytes(modelData, opts)
+}
+
+// NewONNXInferenceFromBytes initializes the inference engine from a model
+// already in memory, e.g. streamed straight from object storage.
+// Compressed artifacts are decompressed first.
+func NewONNXInferenceFromBytes(modelData []byte, opts Options) (*ONNXInference, error) {
+	engine, _, err := loadONNX(modelData, opts)
+	return engine, err
+}
 
+// loadONNX loads the engine and returns it with the model it serves,
+// transformed or not.
+func loadONNX(modelData []byte, opts Options) (*ONNXInference, []byte, error) {
+	modelData, err := decompress(modelData)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := CheckPrecision(BackendGorgonnx, opts.Precision); err != nil {
+		return nil, nil, err
+	}
+	if opts.Precision == "" {
+		opts.Precision = PrecisionFP32
+	}
+	var engine *ONNXInference
+	served := modelData
+	if len(opts.Optimizations) > 0 || opts.Precision != PrecisionFP32 {
+		engine, served = loadTransformed(modelData, opts)
+	}
+	if engine == nil {
+		served = modelData
+		if engine, err = load(modelData); err != nil {
+			return nil, nil, err
+		}
+	}
+	if opts.GradCAM {
+		engine.gradCAM, engine.gradCAMErr = newGradCAM(served)
+	}
+	engine.profile = opts.Profile
+	engine.warmUp(opts.Golden)
+	return engine, served, nil
+}
+
+// warmUp runs the model once, so the backend allocates its buffers before
+// the first request, and measures the memory the model then holds.
+func (o *ONNXInference) warmUp(golden []tensor.Tensor) {
+	if len(golden) == 0 {
+		return
+	}
+	if _, err := o.Predict(golden[0].Clone().(tensor.Tensor)); err != nil {
+		log.Printf("Model warm-up failed: %v", err)
+		return
+	}
+	o.memoryBytes = o.measureMemory()
+}
+
+// load builds the inference engine for an uncompressed model.
+func load(modelData []byte) (*ONNXInference, error) {
 	// --- Step 2: Initialize the Backend and Model ---
 	// Create a new Gorgonia backend, which is the computation engine that will
 	// execute the model's operations.
@@ -50,26 +230,145 @@ func NewONNXInference(modelPath string) (*ONNXInference, error) {
 	// --- Step 3: Decode the Model ---
 	// UnmarshalBinary parses the raw byte data and decodes it into the
 	// structured model object, building the computation graph.
-	err = model.UnmarshalBinary(modelData)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal ONNX model: %w", err)
+	if err := model.UnmarshalBinary(modelData); err != nil {
+		return nil, fmt.Errorf("%w: failed to unmarshal ONNX model: %w", ErrModelIncompatible, err)
 	}
 
 	// Return the ready-to-use inference engine.
 	return &ONNXInference{
-		model:   model,
-		backend: backend,
+		model:     model,
+		backend:   backend,
+		precision: PrecisionFP32,
 	}, nil
 }
 
+// loadTransformed loads the model with opts.Optimizations applied and
+// in opts.Precision, and returns it with the model it was loaded from. It
+// returns nil, after logging why, when the original model should be
+// served instead: a transformation problem must never stop a model that
+// loads from being served.
+func loadTransformed(modelData []byte, opts Options) (*ONNXInference, []byte) {
+	transformed, report, err := Optimize(modelData, opts.Optimizations)
+	if err != nil {
+		log.Printf("Graph optimization skipped: %v", err)
+		return nil, nil
+	}
+	engine, err := load(transformed)
+	if err != nil {
+		log.Printf("Graph optimization skipped: optimized model does not load: %v", err)
+		return nil, nil
+	}
+	engine.optimizations = report
+	engine.precision = opts.Precision
+	rewrites := 0
+	for _, r := range report {
+		rewrites += r.Rewrites
+	}
+	if len(opts.Golden) == 0 || (rewrites == 0 && opts.Precision == PrecisionFP32) {
+		return engine, transformed
+	}
+
+	reference, err := load(modelData)
+	if err != nil {
+		return nil, nil
+	}
+	tolerance := opts.Tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	maxDev, err := deviation(reference, engine, opts.Golden)
+	if err != nil {
+		log.Printf("Model transformation skipped: %v", err)
+		return nil, nil
+	}
+	if maxDev > tolerance {
+		log.Printf("Model transformation skipped: outputs deviate by %g on the golden set (tolerance %g)", maxDev, tolerance)
+		return nil, nil
+	}
+	engine.verification = &models.ModelVerification{
+		Samples:      len(opts.Golden),
+		MaxDeviation: maxDev,
+		Tolerance:    tolerance,
+	}
+	return engine, transformed
+}
+
+// deviation scores every golden input with both engines and returns the
+// largest absolute difference between their outputs.
+func deviation(reference, candidate *ONNXInference, golden []tensor.Tensor) (float64, error) {
+	var max float64
+	for n, input := range golden {
+		want, err := reference.Predict(input.Clone().(tensor.Tensor))
+		if err != nil {
+			return 0, fmt.Errorf("golden input %d: original model: %w", n, err)
+		}
+		got, err := candidate.Predict(input.Clone().(tensor.Tensor))
+		if err != nil {
+			return 0, fmt.Errorf("golden input %d: transformed model: %w", n, err)
+		}
+		if len(got) != len(want) {
+			return 0, fmt.Errorf("golden input %d: %d outputs, want %d", n, len(got), len(want))
+		}
+		for i := range want {
+			max = math.Max(max, math.Abs(float64(got[i]-want[i])))
+		}
+	}
+	return max, nil
+}
+
+// Optimizations reports the graph optimization passes applied at load.
+func (o *ONNXInference) Optimizations() []models.GraphOptimization {
+	return o.optimizations
+}
+
+// Precision reports the precision the model runs in.
+func (o *ONNXInference) Precision() Precision {
+	return o.precision
+}
+
+// Verification reports how the transformed model compared with the
+// original on the golden set; nil when it was not compared.
+func (o *ONNXInference) Verification() *models.ModelVerification {
+	return o.verification
+}
+
+// Describe fills in how the model was loaded.
+func (o *ONNXInference) Describe(info *models.ModelInfo) {
+	info.Backend = string(BackendGorgonnx)
+	info.Optimizations = o.optimizations
+	info.Precision = string(o.precision)
+	info.Verification = o.verification
+	info.MemoryBytes = o.memoryBytes
+	info.Preprocessing = describeProfile(o.profile)
+}
+
+// InputProfile returns the input pipeline of the model.
+func (o *ONNXInference) InputProfile() preprocess.Profile {
+	return o.profile
+}
+
 // Predict runs inference on a preprocessed input tensor.
 func (o *ONNXInference) Predict(inputTensor tensor.Tensor) ([]float32, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	out, err := o.predict(inputTensor)
+	if err != nil {
+		return nil, err
+	}
+	// The backend reuses its output buffer, which the next run would
+	// overwrite once the lock is released.
+	return append([]float32(nil), out...), nil
+}
+
+// predict runs inference; o.mu must be held.
+func (o *ONNXInference) predict(inputTensor tensor.Tensor) ([]float32, error) {
+
 	// --- Step 1: Set the Input ---
 	// We set the input tensor for the model. The '0' indicates that this is
 	// the first (and in our case, only) input to the model.
 	err := o.model.SetInput(0, inputTensor)
 	if err != nil {
-		return nil, fmt.Errorf("failed to set input: %w", err)
+		return nil, fmt.Errorf("%w: failed to set input: %w", ErrModelIncompatible, err)
 	}
 
 	// --- Step 2: Run Inference ---
@@ -91,7 +390,7 @@ func (o *ONNXInference) Predict(inputTensor tensor.Tensor) ([]float32, error) {
 		return nil, fmt.Errorf("failed to get output: %w", err)
 	}
 	if len(outputs) == 0 {
-		return nil, fmt.Errorf("no output tensors found")
+		return nil, fmt.Errorf("%w: no output tensors found", ErrModelIncompatible)
 	}
 
 	// --- Step 4: Extract and Return the Result ---
@@ -99,7 +398,7 @@ func (o *ONNXInference) Predict(inputTensor tensor.Tensor) ([]float32, error) {
 	// which is the raw probability score our application needs.
 	outputData, ok := outputs[0].Data().([]float32)
 	if !ok {
-		return nil, fmt.Errorf("failed to convert output to float32 slice")
+		return nil, fmt.Errorf("%w: output is not a float32 tensor", ErrModelIncompatible)
 	}
 
 	return outputData, nil
@@ -0,0 +1,727 @@
+// backend/internal/inference/optimize.go
+/*
+ * This file contains the graph optimization passes applied when a model
+ * is loaded.
+ *
+ * Exported models routinely carry work that is only needed for training
+ * or is fixed once training is over. Every ONNX node becomes one or more
+ * backend operations per prediction, so rewriting the graph before the
+ * backend sees it is free latency:
+ *
+ *   fold-constants         Constant nodes become initializers.
+ *   eliminate-identity     Identity and Dropout nodes are bypassed.
+ *   fuse-conv-batchnorm    A BatchNormalization that follows a Conv is
+ *                          folded into the convolution's weights and bias.
+ *   prune-unused           Nodes and initializers that no output depends
+ *                          on are removed.
+ *
+ * The backend infers shapes itself when it builds its graph, so there is
+ * no separate shape inference pass. The model is rewritten at the protobuf
+ * level: fields a pass does not touch are copied byte for byte.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package inference
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Graph optimization passes.
+const (
+	PassFoldConstants     = "fold-constants"
+	PassEliminateIdentity = "eliminate-identity"
+	PassFuseConvBatchNorm = "fuse-conv-batchnorm"
+	PassPruneUnused       = "prune-unused"
+)
+
+// Passes lists every pass, in the order they run.
+var Passes = []string{PassFoldConstants, PassEliminateIdentity, PassFuseConvBatchNorm, PassPruneUnused}
+
+// ParsePasses parses a comma-separated list of passes; "all" selects
+// every pass and "none" (or an empty list) disables optimization.
+func ParsePasses(spec string) ([]string, error) {
+	var passes []string
+	for _, name := range strings.Split(spec, ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "", "none":
+		case "all":
+			passes = append(passes, Passes...)
+		default:
+			if !slices.Contains(Passes, name) {
+				return nil, fmt.Errorf("unknown graph optimization %q (want all, none or %s)", name, strings.Join(Passes, ", "))
+			}
+			passes = append(passes, name)
+		}
+	}
+	slices.SortFunc(passes, func(a, b string) int { return slices.Index(Passes, a) - slices.Index(Passes, b) })
+	return slices.Compact(passes), nil
+}
+
+// Optimize applies passes to an ONNX model and returns the rewritten
+// model with the number of rewrites each pass made.
+func Optimize(data []byte, passes []string) ([]byte, []models.GraphOptimization, error) {
+	m, err := parseModel(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrModelIncompatible, err)
+	}
+	g := m.graph
+	var report []models.GraphOptimization
+	for _, pass := range Passes {
+		if !slices.Contains(passes, pass) {
+			continue
+		}
+		var n int
+		switch pass {
+		case PassFoldConstants:
+			n = g.foldConstants()
+		case PassEliminateIdentity:
+			n = g.eliminateIdentity()
+		case PassFuseConvBatchNorm:
+			n = g.fuseConvBatchNorm()
+		case PassPruneUnused:
+			n = g.pruneUnused()
+		}
+		report = append(report, models.GraphOptimization{Pass: pass, Rewrites: n})
+	}
+	return m.encode(), report, nil
+}
+
+// --- Protobuf plumbing ---
+//
+// Field numbers follow onnx.proto: ModelProto.graph = 7; GraphProto.node
+// = 1, initializer = 5, input = 11, output = 12, value_info = 13;
+// NodeProto.input = 1, output = 2, op_type = 4, attribute = 5;
+// AttributeProto.name = 1, f = 2, t = 5; TensorProto.dims = 1,
+// data_type = 2, float_data = 4, name = 8, raw_data = 9,
+// data_location = 14; ValueInfoProto.name = 1.
+
+// pbField is one encoded protobuf field.
+type pbField struct {
+	num protowire.Number
+	typ protowire.Type
+	// raw is the complete encoding, tag included.
+	raw []byte
+	// val is the payload of length-delimited fields.
+	val []byte
+}
+
+func parseFields(b []byte) ([]pbField, error) {
+	var fields []pbField
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		m := protowire.ConsumeFieldValue(num, typ, b[n:])
+		if m < 0 {
+			return nil, protowire.ParseError(m)
+		}
+		f := pbField{num: num, typ: typ, raw: b[:n+m]}
+		if typ == protowire.BytesType {
+			f.val, _ = protowire.ConsumeBytes(b[n:])
+		}
+		fields = append(fields, f)
+		b = b[n+m:]
+	}
+	return fields, nil
+}
+
+func appendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+// onnxModel is a ModelProto with its graph decoded.
+type onnxModel struct {
+	// before and after hold the fields around the graph, verbatim.
+	before, after []byte
+	graph         *onnxGraph
+}
+
+func parseModel(data []byte) (*onnxModel, error) {
+	fields, err := parseFields(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse model: %w", err)
+	}
+	m := &onnxModel{}
+	for _, f := range fields {
+		switch {
+		case f.num == 7 && f.typ == protowire.BytesType && m.graph == nil:
+			if m.graph, err = parseGraph(f.val); err != nil {
+				return nil, err
+			}
+		case m.graph == nil:
+			m.before = append(m.before, f.raw...)
+		default:
+			m.after = append(m.after, f.raw...)
+		}
+	}
+	if m.graph == nil {
+		return nil, fmt.Errorf("model has no graph")
+	}
+	return m, nil
+}
+
+func (m *onnxModel) encode() []byte {
+	out := slices.Clone(m.before)
+	out = appendBytes(out, 7, m.graph.encode())
+	return append(out, m.after...)
+}
+
+// onnxGraph is a GraphProto.
+type onnxGraph struct {
+	nodes []*onnxNode
+	inits []*onnxTensor
+	// inputs are the encoded ValueInfoProtos of the graph inputs, by name.
+	inputs  []namedRaw
+	outputs map[string]bool
+	// valueInfo holds the encoded shape hints for intermediate values.
+	valueInfo []namedRaw
+	// rest holds every other field (outputs included), verbatim.
+	rest []byte
+}
+
+type namedRaw struct {
+	name string
+	raw  []byte
+}
+
+func parseGraph(data []byte) (*onnxGraph, error) {
+	fields, err := parseFields(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse graph: %w", err)
+	}
+	g := &onnxGraph{outputs: make(map[string]bool)}
+	for _, f := range fields {
+		if f.typ != protowire.BytesType {
+			g.rest = append(g.rest, f.raw...)
+			continue
+		}
+		switch f.num {
+		case 1:
+			n, err := parseNode(f.val)
+			if err != nil {
+				return nil, err
+			}
+			g.nodes = append(g.nodes, n)
+		case 5:
+			t, err := parseTensor(f.val)
+			if err != nil {
+				return nil, err
+			}
+			g.inits = append(g.inits, t)
+		case 11:
+			name, err := valueInfoName(f.val)
+			if err != nil {
+				return nil, err
+			}
+			g.inputs = append(g.inputs, namedRaw{name: name, raw: f.raw})
+		case 12:
+			name, err := valueInfoName(f.val)
+			if err != nil {
+				return nil, err
+			}
+			g.outputs[name] = true
+			g.rest = append(g.rest, f.raw...)
+		case 13:
+			name, err := valueInfoName(f.val)
+			if err != nil {
+				return nil, err
+			}
+			g.valueInfo = append(g.valueInfo, namedRaw{name: name, raw: f.raw})
+		default:
+			g.rest = append(g.rest, f.raw...)
+		}
+	}
+	return g, nil
+}
+
+func valueInfoName(data []byte) (string, error) {
+	fields, err := parseFields(data)
+	if err != nil {
+		return "", fmt.Errorf("parse value info: %w", err)
+	}
+	for _, f := range fields {
+		if f.num == 1 && f.typ == protowire.BytesType {
+			return string(f.val), nil
+		}
+	}
+	return "", nil
+}
+
+func (g *onnxGraph) encode() []byte {
+	var out []byte
+	for _, n := range g.nodes {
+		out = appendBytes(out, 1, n.encode())
+	}
+	for _, t := range g.inits {
+		out = appendBytes(out, 5, t.encode())
+	}
+	for _, in := range g.inputs {
+		out = append(out, in.raw...)
+	}
+	// Hints for values a pass removed would leave dangling values behind.
+	produced := make(map[string]bool)
+	for _, n := range g.nodes {
+		for _, o := range n.outputs {
+			produced[o] = true
+		}
+	}
+	for _, vi := range g.valueInfo {
+		if produced[vi.name] {
+			out = append(out, vi.raw...)
+		}
+	}
+	return append(out, g.rest...)
+}
+
+// onnxNode is a NodeProto.
+type onnxNode struct {
+	inputs, outputs []string
+	opType          string
+	attrs           []onnxAttr
+	// rest holds name, domain, doc string..., verbatim.
+	rest []byte
+}
+
+// onnxAttr is an AttributeProto; only the fields the passes read are
+// decoded.
+type onnxAttr struct {
+	name string
+	f    float32
+	t    []byte
+	raw  []byte
+}
+
+func parseNode(data []byte) (*onnxNode, error) {
+	fields, err := parseFields(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse node: %w", err)
+	}
+	n := &onnxNode{}
+	for _, f := range fields {
+		switch {
+		case f.num == 1 && f.typ == protowire.BytesType:
+			n.inputs = append(n.inputs, string(f.val))
+		case f.num == 2 && f.typ == protowire.BytesType:
+			n.outputs = append(n.outputs, string(f.val))
+		case f.num == 4 && f.typ == protowire.BytesType:
+			n.opType = string(f.val)
+		case f.num == 5 && f.typ == protowire.BytesType:
+			a, err := parseAttr(f.val)
+			if err != nil {
+				return nil, err
+			}
+			n.attrs = append(n.attrs, a)
+		default:
+			n.rest = append(n.rest, f.raw...)
+		}
+	}
+	return n, nil
+}
+
+func parseAttr(data []byte) (onnxAttr, error) {
+	fields, err := parseFields(data)
+	if err != nil {
+		return onnxAttr{}, fmt.Errorf("parse attribute: %w", err)
+	}
+	a := onnxAttr{raw: data}
+	for _, f := range fields {
+		switch {
+		case f.num == 1 && f.typ == protowire.BytesType:
+			a.name = string(f.val)
+		case f.num == 2 && f.typ == protowire.Fixed32Type:
+			v, _ := protowire.ConsumeFixed32(f.raw[protowire.SizeTag(2):])
+			a.f = math.Float32frombits(v)
+		case f.num == 5 && f.typ == protowire.BytesType:
+			a.t = f.val
+		}
+	}
+	return a, nil
+}
+
+func (n *onnxNode) attr(name string) (onnxAttr, bool) {
+	for _, a := range n.attrs {
+		if a.name == name {
+			return a, true
+		}
+	}
+	return onnxAttr{}, false
+}
+
+func (n *onnxNode) encode() []byte {
+	var out []byte
+	for _, in := range n.inputs {
+		out = appendString(out, 1, in)
+	}
+	for _, o := range n.outputs {
+		out = appendString(out, 2, o)
+	}
+	out = appendString(out, 4, n.opType)
+	for _, a := range n.attrs {
+		out = appendBytes(out, 5, a.raw)
+	}
+	return append(out, n.rest...)
+}
+
+// onnxTensor is a TensorProto. Float tensors stored inline are decoded so
+// passes can rewrite them; anything else is only carried along.
+type onnxTensor struct {
+	name string
+	dims []int64
+	// floats is set for decoded float tensors.
+	floats []float32
+	// rest holds every field but the name, verbatim, until floats is
+	// changed.
+	rest    []byte
+	changed bool
+}
+
+const (
+	onnxFloat          = 1
+	onnxExternalData   = 1
+	tensorNameField    = 8
+	tensorRawDataField = 9
+)
+
+func parseTensor(data []byte) (*onnxTensor, error) {
+	fields, err := parseFields(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse tensor: %w", err)
+	}
+	t := &onnxTensor{}
+	var dataType, location uint64
+	var raw []byte
+	var floats []float32
+	for _, f := range fields {
+		if f.num == tensorNameField && f.typ == protowire.BytesType {
+			t.name = string(f.val)
+			continue
+		}
+		t.rest = append(t.rest, f.raw...)
+		payload := f.raw[protowire.SizeTag(f.num):]
+		switch {
+		case f.num == 1 && f.typ == protowire.VarintType:
+			v, _ := protowire.ConsumeVarint(payload)
+			t.dims = append(t.dims, int64(v))
+		case f.num == 1 && f.typ == protowire.BytesType:
+			for b := f.val; len(b) > 0; {
+				v, n := protowire.ConsumeVarint(b)
+				if n < 0 {
+					return nil, protowire.ParseError(n)
+				}
+				t.dims = append(t.dims, int64(v))
+				b = b[n:]
+			}
+		case f.num == 2 && f.typ == protowire.VarintType:
+			dataType, _ = protowire.ConsumeVarint(payload)
+		case f.num == 4 && f.typ == protowire.Fixed32Type:
+			v, _ := protowire.ConsumeFixed32(payload)
+			floats = append(floats, math.Float32frombits(v))
+		case f.num == 4 && f.typ == protowire.BytesType:
+			for b := f.val; len(b) >= 4; b = b[4:] {
+				floats = append(floats, math.Float32frombits(binary.LittleEndian.Uint32(b)))
+			}
+		case f.num == tensorRawDataField && f.typ == protowire.BytesType:
+			raw = f.val
+		case f.num == 14 && f.typ == protowire.VarintType:
+			location, _ = protowire.ConsumeVarint(payload)
+		}
+	}
+	if dataType == onnxFloat && location != onnxExternalData {
+		if raw != nil {
+			floats = make([]float32, len(raw)/4)
+			for i := range floats {
+				floats[i] = math.Float32frombits(binary.LittleEndian.Uint32(raw[4*i:]))
+			}
+		}
+		if int64(len(floats)) == elements(t.dims) {
+			t.floats = floats
+		}
+	}
+	return t, nil
+}
+
+func elements(dims []int64) int64 {
+	n := int64(1)
+	for _, d := range dims {
+		n *= d
+	}
+	return n
+}
+
+// newFloatTensor builds a float tensor.
+func newFloatTensor(name string, dims []int64, floats []float32) *onnxTensor {
+	return &onnxTensor{name: name, dims: dims, floats: floats, changed: true}
+}
+
+func (t *onnxTensor) encode() []byte {
+	out := appendString(nil, tensorNameField, t.name)
+	if !t.changed {
+		return append(out, t.rest...)
+	}
+	var dims []byte
+	for _, d := range t.dims {
+		dims = protowire.AppendVarint(dims, uint64(d))
+	}
+	out = appendBytes(out, 1, dims)
+	out = protowire.AppendTag(out, 2, protowire.VarintType)
+	out = protowire.AppendVarint(out, onnxFloat)
+	raw := make([]byte, 4*len(t.floats))
+	for i, v := range t.floats {
+		binary.LittleEndian.PutUint32(raw[4*i:], math.Float32bits(v))
+	}
+	return appendBytes(out, tensorRawDataField, raw)
+}
+
+// --- Graph helpers ---
+
+// consumers counts, per value name, the node inputs that read it.
+func (g *onnxGraph) consumers() map[string]int {
+	counts := make(map[string]int)
+	for _, n := range g.nodes {
+		for _, in := range n.inputs {
+			counts[in]++
+		}
+	}
+	return counts
+}
+
+func (g *onnxGraph) initializer(name string) *onnxTensor {
+	for _, t := range g.inits {
+		if t.name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// removeInitializers drops the named initializers, and the graph inputs
+// that declare them.
+func (g *onnxGraph) removeInitializers(names map[string]bool) {
+	g.inits = slices.DeleteFunc(g.inits, func(t *onnxTensor) bool { return names[t.name] })
+	g.inputs = slices.DeleteFunc(g.inputs, func(in namedRaw) bool { return names[in.name] })
+}
+
+// rename makes every consumer of from read to instead.
+func (g *onnxGraph) rename(from, to string) {
+	for _, n := range g.nodes {
+		for i, in := range n.inputs {
+			if in == from {
+				n.inputs[i] = to
+			}
+		}
+	}
+}
+
+// uniqueName returns base, suffixed if needed, unused by any value.
+func (g *onnxGraph) uniqueName(base string) string {
+	used := make(map[string]bool)
+	for _, n := range g.nodes {
+		for _, v := range append(slices.Clone(n.inputs), n.outputs...) {
+			used[v] = true
+		}
+	}
+	for _, t := range g.inits {
+		used[t.name] = true
+	}
+	name := base
+	for i := 1; used[name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	return name
+}
+
+// --- Passes ---
+
+// foldConstants turns Constant nodes holding a tensor into initializers,
+// which the backend loads once instead of evaluating per run.
+func (g *onnxGraph) foldConstants() int {
+	folded := 0
+	g.nodes = slices.DeleteFunc(g.nodes, func(n *onnxNode) bool {
+		if n.opType != "Constant" || len(n.outputs) != 1 || g.outputs[n.outputs[0]] {
+			return false
+		}
+		value, ok := n.attr("value")
+		if !ok || value.t == nil {
+			return false
+		}
+		t, err := parseTensor(value.t)
+		if err != nil {
+			return false
+		}
+		t.name = n.outputs[0]
+		g.inits = append(g.inits, t)
+		folded++
+		return true
+	})
+	return folded
+}
+
+// eliminateIdentity bypasses Identity nodes, and Dropout nodes, which are
+// identities at inference time.
+func (g *onnxGraph) eliminateIdentity() int {
+	consumers := g.consumers()
+	removed := 0
+	g.nodes = slices.DeleteFunc(g.nodes, func(n *onnxNode) bool {
+		if (n.opType != "Identity" && n.opType != "Dropout") || len(n.inputs) == 0 || len(n.outputs) == 0 {
+			return false
+		}
+		// The dropout mask, if requested, must be unused.
+		for _, o := range n.outputs[1:] {
+			if consumers[o] > 0 || g.outputs[o] {
+				return false
+			}
+		}
+		// Graph outputs keep their name, so the node stays.
+		if g.outputs[n.outputs[0]] {
+			return false
+		}
+		g.rename(n.outputs[0], n.inputs[0])
+		removed++
+		return true
+	})
+	return removed
+}
+
+// fuseConvBatchNorm folds BatchNormalization nodes into the Conv that
+// feeds them: with k = scale / sqrt(var + epsilon), the convolution's
+// weights are scaled by k per output channel and its bias becomes
+// (bias - mean) * k + B.
+func (g *onnxGraph) fuseConvBatchNorm() int {
+	producer := make(map[string]*onnxNode)
+	for _, n := range g.nodes {
+		for _, o := range n.outputs {
+			producer[o] = n
+		}
+	}
+	fused := 0
+	for _, bn := range slices.Clone(g.nodes) {
+		if bn.opType != "BatchNormalization" || len(bn.inputs) != 5 || len(bn.outputs) != 1 {
+			continue
+		}
+		consumers := g.consumers()
+		conv := producer[bn.inputs[0]]
+		if conv == nil || conv.opType != "Conv" || len(conv.outputs) != 1 || len(conv.inputs) < 2 ||
+			consumers[conv.outputs[0]] != 1 || g.outputs[conv.outputs[0]] {
+			continue
+		}
+		// Every parameter must be an inline float initializer, and the
+		// convolution's own ones must not be shared with another node.
+		params := make([]*onnxTensor, 4)
+		ok := true
+		for i, name := range bn.inputs[1:] {
+			if params[i] = g.initializer(name); params[i] == nil || params[i].floats == nil {
+				ok = false
+			}
+		}
+		w := g.initializer(conv.inputs[1])
+		if !ok || w == nil || w.floats == nil || consumers[w.name] != 1 || len(w.dims) == 0 {
+			continue
+		}
+		channels := int(w.dims[0])
+		var bias *onnxTensor
+		if len(conv.inputs) > 2 && conv.inputs[2] != "" {
+			if bias = g.initializer(conv.inputs[2]); bias == nil || bias.floats == nil || consumers[bias.name] != 1 || len(bias.floats) != channels {
+				continue
+			}
+		}
+		for _, p := range params {
+			if len(p.floats) != channels {
+				ok = false
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		epsilon := float32(1e-5)
+		if a, found := bn.attr("epsilon"); found {
+			epsilon = a.f
+		}
+		scale, beta, mean, variance := params[0].floats, params[1].floats, params[2].floats, params[3].floats
+		perChannel := len(w.floats) / channels
+		newBias := make([]float32, channels)
+		for c := 0; c < channels; c++ {
+			k := scale[c] / float32(math.Sqrt(float64(variance[c]+epsilon)))
+			for i := c * perChannel; i < (c+1)*perChannel; i++ {
+				w.floats[i] *= k
+			}
+			b := float32(0)
+			if bias != nil {
+				b = bias.floats[c]
+			}
+			newBias[c] = (b-mean[c])*k + beta[c]
+		}
+		w.changed = true
+		if bias != nil {
+			bias.floats, bias.changed = newBias, true
+		} else {
+			bias = newFloatTensor(g.uniqueName(conv.outputs[0]+"_bias"), []int64{int64(channels)}, newBias)
+			g.inits = append(g.inits, bias)
+		}
+		conv.inputs = append(conv.inputs[:2], bias.name)
+		conv.outputs[0] = bn.outputs[0]
+		g.nodes = slices.DeleteFunc(g.nodes, func(n *onnxNode) bool { return n == bn })
+		producer[bn.outputs[0]] = conv
+
+		// The normalization parameters are dropped unless shared.
+		consumers = g.consumers()
+		unused := make(map[string]bool)
+		for _, p := range params {
+			if consumers[p.name] == 0 {
+				unused[p.name] = true
+			}
+		}
+		g.removeInitializers(unused)
+		fused++
+	}
+	return fused
+}
+
+// pruneUnused removes nodes whose outputs nothing reads, then initializers
+// nothing reads.
+func (g *onnxGraph) pruneUnused() int {
+	pruned := 0
+	for {
+		consumers := g.consumers()
+		before := len(g.nodes)
+		g.nodes = slices.DeleteFunc(g.nodes, func(n *onnxNode) bool {
+			for _, o := range n.outputs {
+				if consumers[o] > 0 || g.outputs[o] {
+					return false
+				}
+			}
+			return true
+		})
+		pruned += before - len(g.nodes)
+		if len(g.nodes) == before {
+			break
+		}
+	}
+	consumers := g.consumers()
+	unused := make(map[string]bool)
+	for _, t := range g.inits {
+		if consumers[t.name] == 0 && !g.outputs[t.name] {
+			unused[t.name] = true
+		}
+	}
+	g.removeInitializers(unused)
+	return pruned + len(unused)
+}
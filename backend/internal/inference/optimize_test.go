@@ -0,0 +1,293 @@
+package inference
+
+import (
+	"encoding/binary"
+	"math"
+	"slices"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"gorgonia.org/tensor"
+)
+
+// Builders for small ONNX models; field numbers as in optimize.go.
+
+func floatTensor(name string, dims []int64, values ...float32) []byte {
+	var b []byte
+	for _, d := range dims {
+		b = protowire.AppendTag(b, 1, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(d))
+	}
+	b = protowire.AppendTag(b, 2, protowire.VarintType)
+	b = protowire.AppendVarint(b, onnxFloat)
+	if name != "" {
+		b = appendString(b, tensorNameField, name)
+	}
+	raw := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(raw[4*i:], math.Float32bits(v))
+	}
+	return appendBytes(b, tensorRawDataField, raw)
+}
+
+func floatAttr(name string, v float32) []byte {
+	b := appendString(nil, 1, name)
+	b = protowire.AppendTag(b, 2, protowire.Fixed32Type)
+	b = protowire.AppendFixed32(b, math.Float32bits(v))
+	b = protowire.AppendTag(b, 20, protowire.VarintType)
+	return protowire.AppendVarint(b, 1)
+}
+
+func intsAttr(name string, vs ...int64) []byte {
+	b := appendString(nil, 1, name)
+	for _, v := range vs {
+		b = protowire.AppendTag(b, 8, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(v))
+	}
+	b = protowire.AppendTag(b, 20, protowire.VarintType)
+	return protowire.AppendVarint(b, 7)
+}
+
+func tensorAttr(name string, t []byte) []byte {
+	b := appendString(nil, 1, name)
+	b = appendBytes(b, 5, t)
+	b = protowire.AppendTag(b, 20, protowire.VarintType)
+	return protowire.AppendVarint(b, 4)
+}
+
+func node(op string, inputs, outputs []string, attrs ...[]byte) []byte {
+	var b []byte
+	for _, in := range inputs {
+		b = appendString(b, 1, in)
+	}
+	for _, o := range outputs {
+		b = appendString(b, 2, o)
+	}
+	b = appendString(b, 4, op)
+	for _, a := range attrs {
+		b = appendBytes(b, 5, a)
+	}
+	return b
+}
+
+func valueInfo(name string, dims ...int64) []byte {
+	var shape []byte
+	for _, d := range dims {
+		dim := protowire.AppendTag(nil, 1, protowire.VarintType)
+		dim = protowire.AppendVarint(dim, uint64(d))
+		shape = appendBytes(shape, 1, dim)
+	}
+	tt := protowire.AppendTag(nil, 1, protowire.VarintType)
+	tt = protowire.AppendVarint(tt, onnxFloat)
+	tt = appendBytes(tt, 2, shape)
+	b := appendString(nil, 1, name)
+	return appendBytes(b, 2, appendBytes(nil, 1, tt))
+}
+
+type testGraph struct {
+	nodes, inits, inputs, outputs [][]byte
+}
+
+func (g testGraph) model() []byte {
+	var graph []byte
+	for _, n := range g.nodes {
+		graph = appendBytes(graph, 1, n)
+	}
+	graph = appendString(graph, 2, "test")
+	for _, t := range g.inits {
+		graph = appendBytes(graph, 5, t)
+	}
+	for _, in := range g.inputs {
+		graph = appendBytes(graph, 11, in)
+	}
+	for _, o := range g.outputs {
+		graph = appendBytes(graph, 12, o)
+	}
+	m := protowire.AppendTag(nil, 1, protowire.VarintType)
+	m = protowire.AppendVarint(m, 7)
+	opset := protowire.AppendTag(nil, 2, protowire.VarintType)
+	opset = protowire.AppendVarint(opset, 13)
+	m = appendBytes(m, 8, opset)
+	return appendBytes(m, 7, graph)
+}
+
+// optimized runs one pass over model and returns the rewritten graph.
+func optimized(t *testing.T, model []byte, pass string) (*onnxGraph, []byte, int) {
+	t.Helper()
+	out, report, err := Optimize(model, []string{pass})
+	if err != nil {
+		t.Fatalf("Optimize: %v", err)
+	}
+	if len(report) != 1 || report[0].Pass != pass {
+		t.Fatalf("report = %+v, want one entry for %s", report, pass)
+	}
+	m, err := parseModel(out)
+	if err != nil {
+		t.Fatalf("optimized model does not parse: %v", err)
+	}
+	return m.graph, out, report[0].Rewrites
+}
+
+func opTypes(g *onnxGraph) []string {
+	var ops []string
+	for _, n := range g.nodes {
+		ops = append(ops, n.opType)
+	}
+	return ops
+}
+
+func TestFoldConstants(t *testing.T) {
+	model := testGraph{
+		nodes: [][]byte{
+			node("Constant", nil, []string{"k"}, tensorAttr("value", floatTensor("", []int64{2}, 1.5, -2))),
+			node("Add", []string{"x", "k"}, []string{"y"}),
+			// A constant graph output keeps its node.
+			node("Constant", nil, []string{"c"}, tensorAttr("value", floatTensor("", []int64{1}, 7))),
+		},
+		inputs:  [][]byte{valueInfo("x", 2)},
+		outputs: [][]byte{valueInfo("y", 2), valueInfo("c", 1)},
+	}.model()
+
+	g, _, n := optimized(t, model, PassFoldConstants)
+	if n != 1 || !slices.Equal(opTypes(g), []string{"Add", "Constant"}) {
+		t.Fatalf("rewrites %d, nodes %v; want 1 and [Add Constant]", n, opTypes(g))
+	}
+	k := g.initializer("k")
+	if k == nil || !slices.Equal(k.floats, []float32{1.5, -2}) || !slices.Equal(k.dims, []int64{2}) {
+		t.Errorf("initializer k = %+v, want [1.5 -2] of shape [2]", k)
+	}
+}
+
+func TestEliminateIdentity(t *testing.T) {
+	model := testGraph{
+		nodes: [][]byte{
+			node("Identity", []string{"x"}, []string{"a"}),
+			node("Dropout", []string{"a"}, []string{"b"}),
+			node("Relu", []string{"b"}, []string{"r"}),
+			// The mask is read, so this dropout stays.
+			node("Dropout", []string{"r"}, []string{"d", "mask"}),
+			node("Add", []string{"d", "mask"}, []string{"s"}),
+			// A graph output keeps its name, and so its node.
+			node("Identity", []string{"s"}, []string{"y"}),
+		},
+		inputs:  [][]byte{valueInfo("x", 2)},
+		outputs: [][]byte{valueInfo("y", 2)},
+	}.model()
+
+	g, _, n := optimized(t, model, PassEliminateIdentity)
+	if n != 2 || !slices.Equal(opTypes(g), []string{"Relu", "Dropout", "Add", "Identity"}) {
+		t.Fatalf("rewrites %d, nodes %v; want 2 and [Relu Dropout Add Identity]", n, opTypes(g))
+	}
+	if relu := g.nodes[0]; !slices.Equal(relu.inputs, []string{"x"}) {
+		t.Errorf("Relu reads %v, want the graph input x", relu.inputs)
+	}
+}
+
+func TestFuseConvBatchNorm(t *testing.T) {
+	const eps = 1e-3
+	model := testGraph{
+		nodes: [][]byte{
+			node("Conv", []string{"x", "w", "b"}, []string{"c"},
+				intsAttr("kernel_shape", 1, 1), intsAttr("strides", 1, 1), intsAttr("pads", 0, 0, 0, 0)),
+			node("BatchNormalization", []string{"c", "scale", "beta", "mean", "var"}, []string{"y"}, floatAttr("epsilon", eps)),
+		},
+		inits: [][]byte{
+			floatTensor("w", []int64{2, 1, 1, 1}, 0.5, -1.25),
+			floatTensor("b", []int64{2}, 0.1, 0.2),
+			floatTensor("scale", []int64{2}, 1.5, 0.8),
+			floatTensor("beta", []int64{2}, -0.3, 0.4),
+			floatTensor("mean", []int64{2}, 0.05, -0.6),
+			floatTensor("var", []int64{2}, 2, 0.25),
+		},
+		inputs:  [][]byte{valueInfo("x", 1, 1, 2, 2)},
+		outputs: [][]byte{valueInfo("y", 1, 2, 2, 2)},
+	}.model()
+
+	g, fused, n := optimized(t, model, PassFuseConvBatchNorm)
+	if n != 1 || !slices.Equal(opTypes(g), []string{"Conv"}) || g.nodes[0].outputs[0] != "y" {
+		t.Fatalf("rewrites %d, nodes %v; want 1 and a Conv writing y", n, opTypes(g))
+	}
+	for _, name := range []string{"scale", "beta", "mean", "var"} {
+		if g.initializer(name) != nil {
+			t.Errorf("normalization parameter %s kept", name)
+		}
+	}
+
+	// The fused convolution computes what the pair did.
+	x := []float32{1, -2, 0.5, 3}
+	w, b := []float32{0.5, -1.25}, []float32{0.1, 0.2}
+	scale, beta, mean, variance := []float32{1.5, 0.8}, []float32{-0.3, 0.4}, []float32{0.05, -0.6}, []float32{2, 0.25}
+	var want []float32
+	for c := range 2 {
+		for _, v := range x {
+			conv := v*w[c] + b[c]
+			want = append(want, (conv-mean[c])/float32(math.Sqrt(float64(variance[c]+eps)))*scale[c]+beta[c])
+		}
+	}
+	fw, fb := g.initializer("w"), g.initializer(g.nodes[0].inputs[2])
+	if fw == nil || fb == nil {
+		t.Fatalf("fused weights or bias missing")
+	}
+	for c := range 2 {
+		for i, v := range x {
+			if got := v*fw.floats[c] + fb.floats[c]; math.Abs(float64(got-want[c*len(x)+i])) > 1e-5 {
+				t.Errorf("channel %d, input %g: fused %g, unfused %g", c, v, got, want[c*len(x)+i])
+			}
+		}
+	}
+
+	// And so does the backend running both models.
+	reference, err := load(model)
+	if err != nil {
+		t.Fatalf("load original: %v", err)
+	}
+	candidate, err := load(fused)
+	if err != nil {
+		t.Fatalf("load fused: %v", err)
+	}
+	input := tensor.New(tensor.WithShape(1, 1, 2, 2), tensor.WithBacking(slices.Clone(x)))
+	dev, err := deviation(reference, candidate, []tensor.Tensor{input})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if dev > 1e-5 {
+		t.Errorf("fused model deviates by %g", dev)
+	}
+}
+
+func TestPruneUnused(t *testing.T) {
+	model := testGraph{
+		nodes: [][]byte{
+			node("Relu", []string{"x"}, []string{"y"}),
+			// A dead branch, two nodes deep.
+			node("Sigmoid", []string{"x"}, []string{"s"}),
+			node("Mul", []string{"s", "unused_k"}, []string{"dead"}),
+			// Nothing reads z, but it is a graph output.
+			node("Neg", []string{"x"}, []string{"z"}),
+		},
+		inits: [][]byte{
+			floatTensor("unused_k", []int64{1}, 2),
+			floatTensor("stray", []int64{1}, 3),
+			// An initializer may itself be a graph output.
+			floatTensor("prior", []int64{1}, 0.5),
+		},
+		inputs:  [][]byte{valueInfo("x", 2)},
+		outputs: [][]byte{valueInfo("y", 2), valueInfo("z", 2), valueInfo("prior", 1)},
+	}.model()
+
+	g, _, n := optimized(t, model, PassPruneUnused)
+	if n != 4 || !slices.Equal(opTypes(g), []string{"Relu", "Neg"}) {
+		t.Fatalf("rewrites %d, nodes %v; want 4 and [Relu Neg]", n, opTypes(g))
+	}
+	if g.initializer("unused_k") != nil || g.initializer("stray") != nil {
+		t.Error("unused initializers kept")
+	}
+	if g.initializer("prior") == nil {
+		t.Error("initializer graph output pruned")
+	}
+	for _, o := range []string{"y", "z", "prior"} {
+		if !g.outputs[o] {
+			t.Errorf("graph output %s lost", o)
+		}
+	}
+}
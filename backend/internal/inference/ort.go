@@ -0,0 +1,214 @@
+//go:build onnxruntime
+
+// backend/internal/inference/ort.go
+/*
+ * This file contains the ONNX Runtime backend.
+ *
+ * It is compiled in with the `onnxruntime` build tag and needs CGO; the
+ * runtime's shared library (libonnxruntime.so, .dylib or .dll) is loaded
+ * when the first model is, from Options.RuntimeLibrary or the system's
+ * library path. The environment is process-wide and initialised once.
+ *
+ * ONNX Runtime applies its own graph optimizations when it creates the
+ * session, so Options.Optimizations do not apply: the model runs as
+ * exported, in fp32, and a request for another precision fails the load. A session may run concurrently, so unlike
+ * the gorgonnx engine, predictions are not serialised.
+ *
+ * ONNX Runtime sizes its intra-op thread pool to the host's cores, which
+ * in a container with a CPU limit means threads far beyond the quota
+ * spinning against each other. Options.IntraOpThreads defaults to
+ * GOMAXPROCS instead, or to the CPUs in Options.CPUs, over which the
+ * intra-op threads are then pinned; the thread calling Run, which takes
+ * part in the work, is not.
+ *
+ * The runtime allocates outside the Go heap and does not report how much,
+ * so a model's memory is approximated by the size of its weights.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package inference
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+	ort "github.com/yalue/onnxruntime_go"
+	"gorgonia.org/tensor"
+)
+
+// ortEnv is the process-wide ONNX Runtime environment.
+var ortEnv struct {
+	once sync.Once
+	err  error
+}
+
+// initORT initialises the environment from the shared library at path,
+// on first use.
+func initORT(path string) error {
+	ortEnv.once.Do(func() {
+		if path != "" {
+			ort.SetSharedLibraryPath(path)
+		}
+		if ortEnv.err = ort.InitializeEnvironment(); ortEnv.err == nil {
+			log.Printf("ONNX Runtime %s initialised", ort.GetVersion())
+		}
+	})
+	return ortEnv.err
+}
+
+// ORTInference is a model loaded in ONNX Runtime.
+type ORTInference struct {
+	session     *ort.DynamicAdvancedSession
+	memoryBytes int64
+	profile     preprocess.Profile
+}
+
+// newORTInference loads modelData in ONNX Runtime. Compressed artifacts
+// are decompressed first.
+func newORTInference(modelData []byte, opts Options) (Engine, error) {
+	modelData, err := decompress(modelData)
+	if err != nil {
+		return nil, err
+	}
+	if err := initORT(opts.RuntimeLibrary); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBackendUnavailable, err)
+	}
+	if err := CheckPrecision(BackendONNXRuntime, opts.Precision); err != nil {
+		return nil, err
+	}
+	if len(opts.Optimizations) > 0 {
+		log.Printf("ONNX Runtime optimizes models itself; MODEL_OPTIMIZATIONS is ignored")
+	}
+
+	inputs, outputs, err := ort.GetInputOutputInfoWithONNXData(modelData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read model: %w", ErrModelIncompatible, err)
+	}
+	if len(inputs) != 1 || len(outputs) == 0 {
+		return nil, fmt.Errorf("%w: model has %d inputs and %d outputs, want 1 and at least 1", ErrModelIncompatible, len(inputs), len(outputs))
+	}
+	options, err := ort.NewSessionOptions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session options: %w", err)
+	}
+	defer options.Destroy()
+	if err := configureThreads(options, opts); err != nil {
+		return nil, fmt.Errorf("failed to configure threads: %w", err)
+	}
+	// The first output is the score, as for the gorgonnx engine.
+	session, err := ort.NewDynamicAdvancedSessionWithONNXData(modelData, []string{inputs[0].Name}, []string{outputs[0].Name}, options)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to create session: %w", ErrModelIncompatible, err)
+	}
+
+	engine := &ORTInference{session: session, memoryBytes: int64(len(modelData)), profile: opts.Profile}
+	runtime.AddCleanup(engine, func(s *ort.DynamicAdvancedSession) { s.Destroy() }, session)
+	if len(opts.Golden) > 0 {
+		if _, err := engine.Predict(opts.Golden[0].Clone().(tensor.Tensor)); err != nil {
+			log.Printf("Model warm-up failed: %v", err)
+		}
+	}
+	return engine, nil
+}
+
+// configureThreads sizes the session's thread pools and pins its intra-op
+// threads to opts.CPUs.
+func configureThreads(options *ort.SessionOptions, opts Options) error {
+	intra := opts.IntraOpThreads
+	if intra == 0 {
+		intra = runtime.GOMAXPROCS(0)
+		if len(opts.CPUs) > 0 {
+			intra = len(opts.CPUs)
+		}
+	}
+	if err := options.SetIntraOpNumThreads(intra); err != nil {
+		return err
+	}
+	if opts.InterOpThreads > 0 {
+		if err := options.SetInterOpNumThreads(opts.InterOpThreads); err != nil {
+			return err
+		}
+	}
+	if len(opts.CPUs) == 0 || intra == 1 {
+		return nil
+	}
+	// One group per pool thread; the first intra-op thread is the caller's.
+	// ONNX Runtime numbers logical processors from 1.
+	groups := make([]string, intra-1)
+	for i := range groups {
+		groups[i] = strconv.Itoa(opts.CPUs[(i+1)%len(opts.CPUs)] + 1)
+	}
+	return options.AddSessionConfigEntry("session.intra_op_thread_affinities", strings.Join(groups, ";"))
+}
+
+// Predict runs inference on a preprocessed input tensor.
+func (o *ORTInference) Predict(inputTensor tensor.Tensor) ([]float32, error) {
+	data, ok := inputTensor.Data().([]float32)
+	if !ok {
+		return nil, fmt.Errorf("%w: input is %T, want []float32", ErrModelIncompatible, inputTensor.Data())
+	}
+	shape := make([]int64, inputTensor.Dims())
+	for i, d := range inputTensor.Shape() {
+		shape[i] = int64(d)
+	}
+	input, err := ort.NewTensor(ort.NewShape(shape...), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create input: %w", err)
+	}
+	defer input.Destroy()
+
+	// A nil output is allocated by the runtime with the shape it produces.
+	outputs := []ort.Value{nil}
+	if err := o.session.Run([]ort.Value{input}, outputs); err != nil {
+		return nil, fmt.Errorf("failed to run model: %w", err)
+	}
+	defer outputs[0].Destroy()
+	output, ok := outputs[0].(*ort.Tensor[float32])
+	if !ok {
+		return nil, fmt.Errorf("%w: output is not a float32 tensor", ErrModelIncompatible)
+	}
+	// The output's memory belongs to the runtime and is freed above.
+	return slices.Clone(output.GetData()), nil
+}
+
+// Describe fills in how the model was loaded.
+func (o *ORTInference) Describe(info *models.ModelInfo) {
+	info.Backend = string(BackendONNXRuntime)
+	info.Precision = string(PrecisionFP32)
+	info.MemoryBytes = o.memoryBytes
+	info.Preprocessing = describeProfile(o.profile)
+}
+
+// InputProfile returns the input pipeline of the model.
+func (o *ORTInference) InputProfile() preprocess.Profile {
+	return o.profile
+}
+
+// MemoryBytes reports the size of the model's weights.
+func (o *ORTInference) MemoryBytes() int64 {
+	return o.memoryBytes
+}
+
+// Optimizations is empty: the runtime optimizes the graph itself.
+func (o *ORTInference) Optimizations() []models.GraphOptimization {
+	return nil
+}
+
+// Verification is nil: the model is served as exported.
+func (o *ORTInference) Verification() *models.ModelVerification {
+	return nil
+}
+
+func (o *ORTInference) whenReleased(release func(uint64), id uint64) {
+	runtime.AddCleanup(o, release, id)
+}
@@ -0,0 +1,15 @@
+//go:build !onnxruntime
+
+// backend/internal/inference/ort_off.go
+/*
+ * ONNX Runtime needs CGO and is compiled in only with the `onnxruntime`
+ * build tag; other builds refuse the backend.
+ */
+
+package inference
+
+import "fmt"
+
+func newORTInference([]byte, Options) (Engine, error) {
+	return nil, fmt.Errorf("%w: this build does not include ONNX Runtime (build with -tags onnxruntime)", ErrBackendUnavailable)
+}
@@ -0,0 +1,278 @@
+// backend/internal/inference/pool.go
+/*
+ * This file runs a gorgonnx model on a pool of independent instances.
+ *
+ * A gorgonnx graph holds the input and intermediate values of a run, so
+ * one instance scores one study at a time and concurrent requests queue
+ * behind it. A Pool loads the model several times (Options.Workers) and
+ * hands each prediction to a free instance. When every worker stays busy
+ * for Options.WorkerWait the prediction fails with ErrSaturated, which the
+ * API returns as 503, rather than letting requests pile up unbounded.
+ *
+ * The first instance is loaded as a single engine would be, optimized and
+ * verified on the golden set; the others load the model it serves, so the
+ * transformations run once. Each instance holds its own buffers, so the
+ * pool's memory is the sum of its workers'. Grad-CAM runs on its own
+ * differentiable copy, which only the first instance loads.
+ *
+ * With Options.CPUs each worker runs its predictions on an OS thread of
+ * its own, restricted to an even share of those CPUs, so workers neither
+ * migrate between cores nor compete for them with each other or with the
+ * API's goroutines on other CPUs. A single worker is pinned too. Shares
+ * never span NUMA nodes (cputune.Place), and each worker loads its
+ * instance on its pinned thread so that, as far as the Go heap allows,
+ * its weights sit in its node's memory.
+ *
+ * Predictions on a pool of several workers are not batched: the Batcher
+ * runs one batch per engine at a time, which would serialise the workers
+ * again.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package inference
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/cputune"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+	"gorgonia.org/tensor"
+)
+
+// ErrSaturated is returned when no inference worker became free in time.
+var ErrSaturated = errors.New("all inference workers are busy")
+
+// DefaultWorkerWait is a reasonable Options.WorkerWait: several runs of a
+// typical model, short of client timeouts.
+const DefaultWorkerWait = 2 * time.Second
+
+// Pool is a model loaded on several workers.
+type Pool struct {
+	all  []*worker
+	free chan *worker
+	wait time.Duration
+}
+
+// worker is one instance of the model in a pool.
+type worker struct {
+	*ONNXInference
+	// calls, when the pool is pinned, feeds the worker's pinned thread.
+	calls chan func()
+}
+
+// newPool loads opts.Workers instances of the model in modelData.
+func newPool(modelData []byte, opts Options) (Engine, error) {
+	workers := max(opts.Workers, 1)
+	p := &Pool{all: make([]*worker, workers), free: make(chan *worker, workers), wait: opts.WorkerWait}
+	for i := range p.all {
+		p.all[i] = &worker{}
+	}
+	if len(opts.CPUs) > 0 {
+		shares, err := cputune.Place(opts.CPUs, workers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to place workers: %w", err)
+		}
+		calls := make([]chan func(), workers)
+		// The pinned threads exit once the pool is dropped.
+		runtime.AddCleanup(p, func(calls []chan func()) {
+			for _, c := range calls {
+				if c != nil {
+					close(c)
+				}
+			}
+		}, calls)
+		for i, cpus := range shares {
+			if err := p.all[i].pin(cpus); err != nil {
+				return nil, fmt.Errorf("worker %d: %w", i, err)
+			}
+			calls[i] = p.all[i].calls
+			log.Printf("Inference worker %d pinned to CPUs %v", i, cpus)
+		}
+	}
+
+	// Each worker loads its instance on its own thread, where the pages
+	// first written land on the worker's NUMA node.
+	first := p.all[0]
+	var served []byte
+	var err error
+	first.run(func() { first.ONNXInference, served, err = loadONNX(modelData, opts) })
+	if err != nil {
+		return nil, err
+	}
+	for i, w := range p.all[1:] {
+		w.run(func() {
+			if w.ONNXInference, err = load(served); err == nil {
+				w.optimizations, w.precision, w.verification = first.optimizations, first.precision, first.verification
+				w.profile = first.profile
+				w.warmUp(opts.Golden)
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("worker %d: %w", i+1, err)
+		}
+	}
+	for _, w := range p.all {
+		p.free <- w
+	}
+	return p, nil
+}
+
+// pin starts the thread that runs the worker's predictions, restricted
+// to cpus.
+func (w *worker) pin(cpus []int) error {
+	calls := make(chan func())
+	pinned := make(chan error, 1)
+	go func() {
+		err := cputune.PinThread(cpus)
+		pinned <- err
+		if err != nil {
+			// The locked thread exits with the goroutine.
+			return
+		}
+		for call := range calls {
+			call()
+		}
+	}()
+	if err := <-pinned; err != nil {
+		return err
+	}
+	w.calls = calls
+	return nil
+}
+
+// run runs f on the worker's pinned thread, or on the caller's goroutine
+// when the pool is not pinned. A panic in f is raised again in the caller,
+// where the server's recovery handles it.
+func (w *worker) run(f func()) {
+	if w.calls == nil {
+		f()
+		return
+	}
+	done := make(chan struct{})
+	var panicked any
+	w.calls <- func() {
+		defer close(done)
+		defer func() { panicked = recover() }()
+		f()
+	}
+	<-done
+	if panicked != nil {
+		panic(panicked)
+	}
+}
+
+// acquire takes a free worker, waiting at most the pool's wait.
+func (p *Pool) acquire() (*worker, error) {
+	select {
+	case w := <-p.free:
+		return w, nil
+	default:
+	}
+	if p.wait <= 0 {
+		return nil, ErrSaturated
+	}
+	timer := time.NewTimer(p.wait)
+	defer timer.Stop()
+	select {
+	case w := <-p.free:
+		return w, nil
+	case <-timer.C:
+		return nil, fmt.Errorf("%w after %s", ErrSaturated, p.wait)
+	}
+}
+
+// Predict runs inference on a free worker.
+func (p *Pool) Predict(input tensor.Tensor) ([]float32, error) {
+	w, err := p.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { p.free <- w }()
+	var scores []float32
+	w.run(func() { scores, err = w.Predict(input) })
+	return scores, err
+}
+
+// PredictBatch scores a batch on a free worker. The Batcher only runs
+// batches on a single-worker pool: one pinned to CPUs.
+func (p *Pool) PredictBatch(inputs []tensor.Tensor) ([][]float32, error) {
+	w, err := p.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { p.free <- w }()
+	var scores [][]float32
+	w.run(func() { scores, err = w.PredictBatch(inputs) })
+	return scores, err
+}
+
+// Profile profiles the model on a free worker.
+func (p *Pool) Profile(input tensor.Tensor, runs int) (*Profile, error) {
+	w, err := p.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { p.free <- w }()
+	var profile *Profile
+	w.run(func() { profile, err = w.Profile(input, runs) })
+	return profile, err
+}
+
+// GradCAM computes a Grad-CAM map on the first worker's differentiable
+// copy, which does not hold up its predictions.
+func (p *Pool) GradCAM(input tensor.Tensor) ([][]float64, error) {
+	return p.all[0].GradCAM(input)
+}
+
+// CheckGradCAM reports why GradCAM is unavailable for the model, or nil.
+func (p *Pool) CheckGradCAM() error {
+	return p.all[0].CheckGradCAM()
+}
+
+// InputProfile returns the input pipeline of the model.
+func (p *Pool) InputProfile() preprocess.Profile {
+	return p.all[0].InputProfile()
+}
+
+// Workers reports the number of workers.
+func (p *Pool) Workers() int {
+	return len(p.all)
+}
+
+// Describe fills in how the model was loaded.
+func (p *Pool) Describe(info *models.ModelInfo) {
+	p.all[0].Describe(info)
+	info.MemoryBytes = p.MemoryBytes()
+	info.Workers = len(p.all)
+}
+
+// MemoryBytes reports the memory held by all workers.
+func (p *Pool) MemoryBytes() int64 {
+	var total int64
+	for _, w := range p.all {
+		total += w.MemoryBytes()
+	}
+	return total
+}
+
+// Optimizations reports the graph optimization passes applied at load.
+func (p *Pool) Optimizations() []models.GraphOptimization {
+	return p.all[0].Optimizations()
+}
+
+// Verification reports the golden-set comparison of the served model.
+func (p *Pool) Verification() *models.ModelVerification {
+	return p.all[0].Verification()
+}
+
+func (p *Pool) whenReleased(release func(uint64), id uint64) {
+	runtime.AddCleanup(p, release, id)
+}
@@ -0,0 +1,287 @@
+// backend/internal/jobs/jobs.go
+/*
+ * This file implements the background job manager.
+ *
+ * Long-running work (bulk re-scoring, batch analysis, ...) must not run
+ * inside an HTTP request. A caller submits a job with a run function; the
+ * manager executes it on a bounded number of goroutines and tracks its
+ * status, progress and result so clients can poll for completion.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status is the lifecycle state of a job.
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+var (
+	// ErrNotFound is returned for unknown job IDs.
+	ErrNotFound = errors.New("job not found")
+	// ErrQueueFull is returned by TrySubmit when MaxQueued jobs are
+	// already waiting.
+	ErrQueueFull = errors.New("job queue is full")
+)
+
+// Job is the externally visible state of a job.
+type Job struct {
+	ID         string     `json:"id"`
+	Type       string     `json:"type"`
+	Tenant     string     `json:"tenant,omitempty"`
+	Status     Status     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// Progress is the fraction of work completed, from 0 to 1.
+	Progress float64 `json:"progress"`
+	Error    string  `json:"error,omitempty"`
+	Result   any     `json:"result,omitempty"`
+}
+
+// Done reports whether the job has reached a terminal state.
+func (j Job) Done() bool {
+	return j.Status == StatusSucceeded || j.Status == StatusFailed
+}
+
+// RunFunc performs the work of a job. It should call report periodically
+// with its progress (0–1) and honour ctx cancellation.
+type RunFunc func(ctx context.Context, report func(progress float64)) (any, error)
+
+// Manager runs and tracks jobs.
+type Manager struct {
+	// OnFinish, when set, is called with the final state of every job.
+	// Set it before submitting jobs.
+	OnFinish func(Job)
+	// MaxQueued, when positive, bounds the jobs waiting for a slot that
+	// TrySubmit accepts. Set it before submitting jobs.
+	MaxQueued int
+
+	slots chan struct{}
+
+	mu   sync.RWMutex
+	jobs map[string]*Job
+	// order holds the jobs oldest first, by creation time then ID, so
+	// pages are read without sorting every job.
+	order []*Job
+	// queued counts the jobs waiting for a slot.
+	queued int
+	// done holds a channel per job that is closed when it finishes.
+	done map[string]chan struct{}
+}
+
+// NewManager creates a manager that runs at most concurrency jobs at once.
+func NewManager(concurrency int) *Manager {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &Manager{
+		slots: make(chan struct{}, concurrency),
+		jobs:  make(map[string]*Job),
+		done:  make(map[string]chan struct{}),
+	}
+}
+
+// Submit queues a job on behalf of tenant ("" for service-wide jobs) and
+// returns its initial state.
+func (m *Manager) Submit(ctx context.Context, jobType, tenant string, run RunFunc) Job {
+	job, _ := m.submit(ctx, jobType, tenant, run, false)
+	return job
+}
+
+// TrySubmit is Submit, but fails with ErrQueueFull instead of queueing
+// beyond MaxQueued.
+func (m *Manager) TrySubmit(ctx context.Context, jobType, tenant string, run RunFunc) (Job, error) {
+	return m.submit(ctx, jobType, tenant, run, true)
+}
+
+func (m *Manager) submit(ctx context.Context, jobType, tenant string, run RunFunc, bounded bool) (Job, error) {
+	job := &Job{
+		ID:     uuid.NewString(),
+		Type:   jobType,
+		Tenant: tenant,
+		Status: StatusQueued,
+	}
+
+	m.mu.Lock()
+	if bounded && m.MaxQueued > 0 && m.queued >= m.MaxQueued {
+		m.mu.Unlock()
+		return Job{}, ErrQueueFull
+	}
+	job.CreatedAt = time.Now().UTC()
+	m.jobs[job.ID] = job
+	// Jobs are created in order; the search only settles equal times.
+	i, _ := slices.BinarySearchFunc(m.order, job, compareJobs)
+	m.order = slices.Insert(m.order, i, job)
+	m.done[job.ID] = make(chan struct{})
+	m.queued++
+	snapshot := *job
+	m.mu.Unlock()
+
+	go m.execute(ctx, job, run)
+	return snapshot, nil
+}
+
+// Get returns the current state of a job.
+func (m *Manager) Get(id string) (Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	return *job, nil
+}
+
+// Wait blocks until the job finishes or ctx is done, whichever comes
+// first, and returns the job's state at that point.
+func (m *Manager) Wait(ctx context.Context, id string) (Job, error) {
+	m.mu.RLock()
+	done, ok := m.done[id]
+	m.mu.RUnlock()
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	return m.Get(id)
+}
+
+// List returns all known jobs, newest first.
+func (m *Manager) List() []Job {
+	return m.Find(Query{})
+}
+
+// Query selects a page of jobs. Jobs are ordered by creation time, then
+// by ID.
+type Query struct {
+	// Match, when set, must accept a job; it is applied before Limit.
+	Match func(Job) bool
+	// OldestFirst lists the oldest jobs first instead of the newest.
+	OldestFirst bool
+	// After, when set, resumes a listing after the job created at
+	// After.CreatedAt with ID After.ID; other fields are ignored.
+	After *Job
+	// Limit caps the number of jobs; zero means no limit.
+	Limit int
+}
+
+// Find returns the jobs q selects. Only the jobs returned are copied.
+func (m *Manager) Find(q Query) []Job {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// start is the index of the first job after q.After, oldest first.
+	start, step := 0, 1
+	if !q.OldestFirst {
+		start, step = len(m.order)-1, -1
+	}
+	if q.After != nil {
+		i, found := slices.BinarySearchFunc(m.order, q.After, compareJobs)
+		if q.OldestFirst {
+			if found {
+				i++
+			}
+			start = i
+		} else {
+			start = i - 1
+		}
+	}
+	var out []Job
+	for i := start; i >= 0 && i < len(m.order); i += step {
+		job := m.order[i]
+		if q.Match != nil && !q.Match(*job) {
+			continue
+		}
+		out = append(out, *job)
+		if q.Limit > 0 && len(out) == q.Limit {
+			break
+		}
+	}
+	return out
+}
+
+// compareJobs orders jobs by creation time, then by ID.
+func compareJobs(a, b *Job) int {
+	if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+		return c
+	}
+	return strings.Compare(a.ID, b.ID)
+}
+
+func (m *Manager) execute(ctx context.Context, job *Job, run RunFunc) {
+	// Wait for a free slot so heavy jobs don't starve live traffic.
+	select {
+	case m.slots <- struct{}{}:
+	case <-ctx.Done():
+		m.update(job, func(*Job) { m.queued-- })
+		m.finish(job, nil, ctx.Err())
+		return
+	}
+	defer func() { <-m.slots }()
+
+	m.update(job, func(j *Job) {
+		m.queued--
+		now := time.Now().UTC()
+		j.Status = StatusRunning
+		j.StartedAt = &now
+	})
+
+	result, err := run(ctx, func(p float64) {
+		m.update(job, func(j *Job) { j.Progress = p })
+	})
+	m.finish(job, result, err)
+}
+
+func (m *Manager) finish(job *Job, result any, err error) {
+	m.update(job, func(j *Job) {
+		now := time.Now().UTC()
+		j.FinishedAt = &now
+		if err != nil {
+			j.Status = StatusFailed
+			j.Error = err.Error()
+			return
+		}
+		j.Status = StatusSucceeded
+		j.Progress = 1
+		j.Result = result
+	})
+
+	m.mu.RLock()
+	close(m.done[job.ID])
+	final := *job
+	m.mu.RUnlock()
+
+	if m.OnFinish != nil {
+		m.OnFinish(final)
+	}
+}
+
+func (m *Manager) update(job *Job, fn func(*Job)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fn(job)
+}
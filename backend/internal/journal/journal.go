@@ -0,0 +1,196 @@
+// backend/internal/journal/journal.go
+/*
+ * This file implements the request journal used for disaster recovery.
+ *
+ * Every prediction request is written to durable storage when it arrives
+ * and removed once it has been answered. Whatever is left after a crash
+ * or an outage is exactly the set of studies that were received but never
+ * processed. An entry records the references and parameters of the
+ * request (tenant, form fields, file name, size and SHA-256 of the
+ * image); the image itself is kept only when the submitter consented, so
+ * most entries identify a study to resubmit rather than carry it.
+ *
+ * Entries live in a spool directory: one atomically written file per
+ * entry, which survives a power loss. With tenant keys, entries and kept
+ * images are sealed with the tenant's key (see spool.Seal); the entries
+ * of a tenant whose key was withdrawn are no longer listed.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package journal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/josephed37/mammoscan-AI/backend/internal/spool"
+	"github.com/josephed37/mammoscan-AI/backend/internal/tenantkey"
+)
+
+// recordKind tags journal records in the spool.
+const recordKind = "request"
+
+// Entry is one journaled request.
+type Entry struct {
+	ID         string    `json:"id"`
+	ReceivedAt time.Time `json:"received_at"`
+	Tenant     string    `json:"tenant,omitempty"`
+	// Fields are the form fields sent with the image.
+	Fields      map[string]string `json:"fields,omitempty"`
+	Filename    string            `json:"filename,omitempty"`
+	ImageSize   int               `json:"image_size"`
+	ImageSHA256 string            `json:"image_sha256"`
+	// HasImage reports whether the image was kept and the entry can be
+	// replayed as is.
+	HasImage bool `json:"has_image"`
+}
+
+// Journal is the durable record of requests not yet answered.
+type Journal struct {
+	queue *spool.Queue
+	keys  *tenantkey.Keyring
+
+	mu sync.Mutex
+	// inFlight holds the entries of requests this process is still
+	// handling; they are not pending recovery.
+	inFlight map[string]bool
+}
+
+// Open opens (creating if needed) the journal in dir, sealing entries
+// with keys when not nil.
+func Open(dir string, keys *tenantkey.Keyring) (*Journal, error) {
+	q, err := spool.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Journal{queue: q, keys: keys, inFlight: make(map[string]bool)}, nil
+}
+
+// Begin journals a request before it is processed and returns the entry.
+// image is always fingerprinted but only stored when keepImage is set.
+func (j *Journal) Begin(tenant, filename string, fields map[string]string, image []byte, keepImage bool) (Entry, error) {
+	sum := sha256.Sum256(image)
+	e := Entry{
+		ID:          newID(),
+		ReceivedAt:  time.Now().UTC(),
+		Tenant:      tenant,
+		Fields:      fields,
+		Filename:    filename,
+		ImageSize:   len(image),
+		ImageSHA256: hex.EncodeToString(sum[:]),
+		HasImage:    keepImage && len(image) > 0,
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return Entry{}, err
+	}
+	var blob []byte
+	if e.HasImage {
+		blob = image
+	}
+	rec := spool.Record{ID: e.ID, Kind: recordKind, CreatedAt: e.ReceivedAt, Payload: payload}
+	if j.keys != nil {
+		if rec, blob, err = spool.Seal(j.keys, tenant, rec, blob); err != nil {
+			return Entry{}, fmt.Errorf("journal request: %w", err)
+		}
+	}
+	j.mu.Lock()
+	j.inFlight[e.ID] = true
+	j.mu.Unlock()
+	if err := j.queue.Enqueue(rec, blob); err != nil {
+		j.release(e.ID)
+		return Entry{}, fmt.Errorf("journal request: %w", err)
+	}
+	return e, nil
+}
+
+// Claim marks a pending entry as being handled by this process, e.g. for
+// a replay. It reports false if the entry is already in flight.
+func (j *Journal) Claim(id string) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.inFlight[id] {
+		return false
+	}
+	j.inFlight[id] = true
+	return true
+}
+
+// Finish ends the handling of an entry. A processed request is removed
+// from the journal; one that failed on our side stays pending recovery.
+func (j *Journal) Finish(id string, processed bool) error {
+	defer j.release(id)
+	if !processed {
+		return nil
+	}
+	return j.queue.Ack(id)
+}
+
+// Discard removes a pending entry, e.g. once the study was resubmitted by
+// hand.
+func (j *Journal) Discard(id string) error {
+	return j.queue.Ack(id)
+}
+
+func (j *Journal) release(id string) {
+	j.mu.Lock()
+	delete(j.inFlight, id)
+	j.mu.Unlock()
+}
+
+// Pending lists the entries of requests that were received but never
+// answered, oldest first. Requests still being handled are left out.
+func (j *Journal) Pending() ([]Entry, error) {
+	records, err := j.queue.Pending(0)
+	if err != nil {
+		return nil, err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var entries []Entry
+	for _, r := range records {
+		if r.Kind != recordKind || j.inFlight[r.ID] {
+			continue
+		}
+		r, err := spool.Unseal(j.keys, r)
+		if errors.Is(err, tenantkey.ErrNoKey) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var e Entry
+		if err := json.Unmarshal(r.Payload, &e); err != nil {
+			return nil, fmt.Errorf("decode entry %s: %w", r.ID, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Image returns the image kept for an entry.
+func (j *Journal) Image(id string) ([]byte, error) {
+	blob, err := j.queue.Blob(id)
+	if err != nil {
+		return nil, err
+	}
+	return spool.UnsealBlob(j.keys, id, blob)
+}
+
+// newID returns a time-ordered UUIDv7, so entries list in arrival order.
+func newID() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.NewString()
+	}
+	return id.String()
+}
@@ -0,0 +1,23 @@
+//go:build go_json && !jsoniter
+
+// backend/internal/jsonenc/gojson.go
+/*
+ * The goccy/go-json encoder (`-tags go_json`).
+ */
+
+package jsonenc
+
+import (
+	"io"
+
+	json "github.com/goccy/go-json"
+)
+
+// Codec names the active encoder.
+const Codec = "go-json"
+
+// Marshal encodes v.
+func Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+// NewEncoder returns an encoder writing to w.
+func NewEncoder(w io.Writer) *json.Encoder { return json.NewEncoder(w) }
@@ -0,0 +1,54 @@
+// backend/internal/jsonenc/jsonenc.go
+/*
+ * This package selects the JSON encoder for response bodies.
+ *
+ * Gin picks its encoder with build tags; this package honours the same
+ * tags so a single flag switches every response the service writes:
+ *
+ *   (default)       encoding/json
+ *   -tags jsoniter  github.com/json-iterator/go
+ *   -tags go_json   github.com/goccy/go-json
+ *
+ * All three produce the same documents for our types. The faster codecs
+ * allocate less per response, which matters at high request rates where
+ * garbage collection dominates CPU.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package jsonenc
+
+import (
+	"bytes"
+	"sync"
+)
+
+// pool holds encode buffers between responses. Buffers that grew past
+// maxPooled (a large listing, say) are dropped rather than kept forever.
+var pool = sync.Pool{New: func() any { return bytes.NewBuffer(make([]byte, 0, 2048)) }}
+
+const maxPooled = 64 << 10
+
+// Encode renders v into a pooled buffer. The caller must pass the buffer
+// to Release once the bytes have been written out.
+func Encode(v any) (*bytes.Buffer, error) {
+	buf := pool.Get().(*bytes.Buffer)
+	if err := NewEncoder(buf).Encode(v); err != nil {
+		Release(buf)
+		return nil, err
+	}
+	// Encoders terminate the document with a newline; responses do not.
+	buf.Truncate(buf.Len() - 1)
+	return buf, nil
+}
+
+// Release returns a buffer obtained from Encode to the pool.
+func Release(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooled {
+		return
+	}
+	buf.Reset()
+	pool.Put(buf)
+}
@@ -0,0 +1,26 @@
+//go:build jsoniter
+
+// backend/internal/jsonenc/jsoniter.go
+/*
+ * The json-iterator encoder (`-tags jsoniter`), in its encoding/json
+ * compatible configuration.
+ */
+
+package jsonenc
+
+import (
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Codec names the active encoder.
+const Codec = "jsoniter"
+
+var api = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// Marshal encodes v.
+func Marshal(v any) ([]byte, error) { return api.Marshal(v) }
+
+// NewEncoder returns an encoder writing to w.
+func NewEncoder(w io.Writer) *jsoniter.Encoder { return api.NewEncoder(w) }
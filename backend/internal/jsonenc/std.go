@@ -0,0 +1,22 @@
+//go:build !jsoniter && !go_json
+
+// backend/internal/jsonenc/std.go
+/*
+ * The default encoder: encoding/json.
+ */
+
+package jsonenc
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Codec names the active encoder.
+const Codec = "encoding/json"
+
+// Marshal encodes v.
+func Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+// NewEncoder returns an encoder writing to w.
+func NewEncoder(w io.Writer) *json.Encoder { return json.NewEncoder(w) }
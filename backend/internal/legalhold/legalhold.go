@@ -0,0 +1,264 @@
+// backend/internal/legalhold/legalhold.go
+/*
+ * This file contains the legal hold registry.
+ *
+ * A legal hold preserves data for litigation: while a hold is in force,
+ * the predictions it covers can be neither deleted nor purged, whatever
+ * the retention period says. A hold covers one prediction or every
+ * prediction of a tenant. Released holds are kept, with who released them
+ * and why, so the registry itself is a record of what was preserved and
+ * for how long. With a path configured the registry is saved to a JSON
+ * file after every change.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package legalhold
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotFound is returned for an unknown hold ID.
+	ErrNotFound = errors.New("legal hold not found")
+	// ErrInvalid wraps validation failures of a hold definition.
+	ErrInvalid = errors.New("invalid legal hold")
+	// ErrReleased is returned when releasing a hold already released.
+	ErrReleased = errors.New("legal hold already released")
+)
+
+// maxTextLen bounds the free-text fields of a hold.
+const maxTextLen = 1024
+
+// Hold is one legal hold.
+type Hold struct {
+	ID string `json:"id"`
+	// Tenant is the tenant whose data is held. With PredictionID set only
+	// that prediction is held, otherwise every prediction of the tenant.
+	Tenant       string `json:"tenant"`
+	PredictionID string `json:"prediction_id,omitempty"`
+	// Reason says why the data is held; Reference names the matter (a
+	// case or ticket number).
+	Reason    string    `json:"reason"`
+	Reference string    `json:"reference,omitempty"`
+	PlacedBy  string    `json:"placed_by,omitempty"`
+	PlacedAt  time.Time `json:"placed_at"`
+
+	ReleasedAt    *time.Time `json:"released_at,omitempty"`
+	ReleasedBy    string     `json:"released_by,omitempty"`
+	ReleaseReason string     `json:"release_reason,omitempty"`
+}
+
+// Active reports whether the hold is in force.
+func (h Hold) Active() bool {
+	return h.ReleasedAt == nil
+}
+
+// Covers reports whether the hold is in force for a prediction of tenant.
+func (h Hold) Covers(tenant, predictionID string) bool {
+	if !h.Active() || h.Tenant != tenant {
+		return false
+	}
+	return h.PredictionID == "" || h.PredictionID == predictionID
+}
+
+// Spec is the client-supplied part of a hold.
+type Spec struct {
+	Tenant       string `json:"tenant"`
+	PredictionID string `json:"prediction_id"`
+	Reason       string `json:"reason"`
+	Reference    string `json:"reference"`
+}
+
+func (s Spec) validate() error {
+	if strings.TrimSpace(s.Reason) == "" {
+		return fmt.Errorf("%w: a reason is required", ErrInvalid)
+	}
+	if s.PredictionID == "" && s.Tenant == "" {
+		return fmt.Errorf("%w: a tenant or a prediction_id is required", ErrInvalid)
+	}
+	if len(s.Reason) > maxTextLen || len(s.Reference) > maxTextLen {
+		return fmt.Errorf("%w: reason and reference are limited to %d characters", ErrInvalid, maxTextLen)
+	}
+	return nil
+}
+
+// Registry holds every legal hold. It is safe for concurrent use.
+type Registry struct {
+	path string
+
+	mu    sync.RWMutex
+	holds map[string]Hold
+}
+
+// NewRegistry creates a registry, loading path if it exists. An empty
+// path keeps holds in memory only.
+func NewRegistry(path string) (*Registry, error) {
+	r := &Registry{path: path, holds: make(map[string]Hold)}
+	if path == "" {
+		return r, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []Hold
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for _, h := range list {
+		r.holds[h.ID] = h
+	}
+	return r, nil
+}
+
+// Place records a new hold. The caller resolves the tenant of a held
+// prediction before placing it.
+func (r *Registry) Place(spec Spec, by string) (Hold, error) {
+	if err := spec.validate(); err != nil {
+		return Hold{}, err
+	}
+	h := Hold{
+		ID:           uuid.NewString(),
+		Tenant:       spec.Tenant,
+		PredictionID: spec.PredictionID,
+		Reason:       spec.Reason,
+		Reference:    spec.Reference,
+		PlacedBy:     by,
+		PlacedAt:     time.Now().UTC(),
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.holds[h.ID] = h
+	if err := r.save(); err != nil {
+		delete(r.holds, h.ID)
+		return Hold{}, err
+	}
+	return h, nil
+}
+
+// Release lifts a hold. The hold stays in the registry, released.
+func (r *Registry) Release(id, by, reason string) (Hold, error) {
+	if len(reason) > maxTextLen {
+		return Hold{}, fmt.Errorf("%w: reason is limited to %d characters", ErrInvalid, maxTextLen)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, ok := r.holds[id]
+	if !ok {
+		return Hold{}, ErrNotFound
+	}
+	if !old.Active() {
+		return Hold{}, ErrReleased
+	}
+	h := old
+	now := time.Now().UTC()
+	h.ReleasedAt, h.ReleasedBy, h.ReleaseReason = &now, by, reason
+	r.holds[id] = h
+	if err := r.save(); err != nil {
+		r.holds[id] = old
+		return Hold{}, err
+	}
+	return h, nil
+}
+
+// Get returns one hold.
+func (r *Registry) Get(id string) (Hold, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	h, ok := r.holds[id]
+	if !ok {
+		return Hold{}, ErrNotFound
+	}
+	return h, nil
+}
+
+// List returns the holds in force, oldest first, and the released ones
+// too when includeReleased is set.
+func (r *Registry) List(includeReleased bool) []Hold {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []Hold
+	for _, h := range r.holds {
+		if h.Active() || includeReleased {
+			list = append(list, h)
+		}
+	}
+	sortHolds(list)
+	return list
+}
+
+// Holding returns the holds in force for a prediction of tenant, oldest
+// first; none means it may be deleted. A nil registry holds nothing.
+func (r *Registry) Holding(tenant, predictionID string) []Hold {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []Hold
+	for _, h := range r.holds {
+		if h.Covers(tenant, predictionID) {
+			list = append(list, h)
+		}
+	}
+	sortHolds(list)
+	return list
+}
+
+func sortHolds(list []Hold) {
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].PlacedAt.Equal(list[j].PlacedAt) {
+			return list[i].PlacedAt.Before(list[j].PlacedAt)
+		}
+		return list[i].ID < list[j].ID
+	})
+}
+
+// save writes the registry atomically. It must be called with r.mu held.
+func (r *Registry) save() error {
+	if r.path == "" {
+		return nil
+	}
+	list := make([]Hold, 0, len(r.holds))
+	for _, h := range r.holds {
+		list = append(list, h)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), ".legal-holds-*")
+	if err != nil {
+		return fmt.Errorf("save legal holds: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("save legal holds: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("save legal holds: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return fmt.Errorf("save legal holds: %w", err)
+	}
+	return nil
+}
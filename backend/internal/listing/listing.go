@@ -0,0 +1,390 @@
+// backend/internal/listing/listing.go
+/*
+ * This file implements the shared pagination, sorting and filtering
+ * grammar used by every list endpoint.
+ *
+ *   ?limit=50
+ *   &sort=-created_at,prediction_id      comma-separated, "-" = descending
+ *   &filter=prediction:eq:Cancer         repeatable; field:op:value
+ *   &filter=created_at:gte:2026-01-01T00:00:00Z
+ *   &cursor=<next_cursor from the previous page>
+ *
+ * Operators: eq, ne, lt, lte, gt, gte, in (values separated by "|") and
+ * prefix. Each endpoint declares which fields can be sorted and filtered
+ * on and how to read them from its records.
+ *
+ * Cursors are keyset-based: they carry the sort key of the last item
+ * returned, so pages stay stable while new records arrive. A cursor is
+ * only valid with the same sort and filters it was issued for.
+ *
+ * Apply lists records held in memory. Endpoints backed by a store push
+ * the query down instead: the store filters with Matcher, orders and
+ * resumes after the position From decodes, and PageOf cuts the page.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package listing
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalid is returned (wrapped) for malformed list parameters.
+var ErrInvalid = errors.New("invalid list parameters")
+
+// Kind is the type of a sortable/filterable field.
+type Kind int
+
+const (
+	String Kind = iota
+	Number
+	Time
+)
+
+// Field describes one sortable/filterable field of T. Get must return a
+// string, float64 or time.Time matching Kind.
+type Field[T any] struct {
+	Kind Kind
+	Get  func(T) any
+}
+
+// Spec declares how an endpoint's records are listed.
+type Spec[T any] struct {
+	Fields map[string]Field[T]
+	// ID returns a unique key, used as the final tie-breaker.
+	ID func(T) string
+	// Sortable, when set, limits sorting to these fields, e.g. those a
+	// store can order by; every field can still be filtered on.
+	Sortable []string
+	// DefaultSort applies when the request has no sort parameter.
+	DefaultSort string
+	// DefaultLimit and MaxLimit bound the page size.
+	DefaultLimit, MaxLimit int
+}
+
+// SortKey orders by one field.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// Filter restricts results on one field.
+type Filter struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// Query is a parsed list request.
+type Query struct {
+	Limit   int
+	Sort    []SortKey
+	Filters []Filter
+	Cursor  string
+
+	sortParam string
+}
+
+// Page is one page of results.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+var operators = []string{"eq", "ne", "lt", "lte", "gt", "gte", "in", "prefix"}
+
+// Parse reads and validates the list parameters against spec.
+func Parse[T any](values url.Values, spec Spec[T]) (Query, error) {
+	q := Query{Limit: spec.DefaultLimit, Cursor: values.Get("cursor")}
+
+	if v := values.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return q, fmt.Errorf("%w: limit must be a positive integer", ErrInvalid)
+		}
+		q.Limit = n
+	}
+	if spec.MaxLimit > 0 && q.Limit > spec.MaxLimit {
+		q.Limit = spec.MaxLimit
+	}
+
+	q.sortParam = values.Get("sort")
+	if strings.Trim(q.sortParam, ", ") == "" {
+		q.sortParam = spec.DefaultSort
+	}
+	for _, term := range strings.Split(q.sortParam, ",") {
+		if term = strings.TrimSpace(term); term == "" {
+			continue
+		}
+		key := SortKey{Field: strings.TrimPrefix(term, "-"), Desc: strings.HasPrefix(term, "-")}
+		if _, ok := spec.Fields[key.Field]; !ok || spec.Sortable != nil && !slices.Contains(spec.Sortable, key.Field) {
+			return q, fmt.Errorf("%w: cannot sort by %q", ErrInvalid, key.Field)
+		}
+		q.Sort = append(q.Sort, key)
+	}
+
+	for _, raw := range values["filter"] {
+		parts := strings.SplitN(raw, ":", 3)
+		if len(parts) != 3 {
+			return q, fmt.Errorf("%w: filter %q must be field:op:value", ErrInvalid, raw)
+		}
+		f := Filter{Field: parts[0], Op: parts[1], Value: parts[2]}
+		field, ok := spec.Fields[f.Field]
+		if !ok {
+			return q, fmt.Errorf("%w: cannot filter by %q", ErrInvalid, f.Field)
+		}
+		if !slices.Contains(operators, f.Op) {
+			return q, fmt.Errorf("%w: unknown operator %q", ErrInvalid, f.Op)
+		}
+		for _, v := range strings.Split(f.Value, "|") {
+			if _, err := parseValue(field.Kind, v); err != nil {
+				return q, fmt.Errorf("%w: filter %q: %v", ErrInvalid, raw, err)
+			}
+		}
+		q.Filters = append(q.Filters, f)
+	}
+	return q, nil
+}
+
+// Equal returns the value of an "eq" filter on field, if present. Callers
+// use it to push simple lookups down to the store.
+func (q Query) Equal(field string) string {
+	for _, f := range q.Filters {
+		if f.Field == field && f.Op == "eq" {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// cursor is the decoded form of a page cursor.
+type cursor struct {
+	Signature string `json:"s"`
+	Values    []any  `json:"v"`
+	ID        string `json:"id"`
+}
+
+// signature binds a cursor to the sort and filters it was issued for.
+func (q Query) signature() string {
+	h := sha256.New()
+	fmt.Fprintln(h, q.sortParam)
+	for _, f := range q.Filters {
+		fmt.Fprintln(h, f.Field, f.Op, f.Value)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// Apply filters, sorts and pages items.
+func Apply[T any](items []T, q Query, spec Spec[T]) (Page[T], error) {
+	out := make([]T, 0, len(items))
+	for _, item := range items {
+		if matchAll(item, q.Filters, spec) {
+			out = append(out, item)
+		}
+	}
+
+	compare := func(a, b T) int {
+		for _, key := range q.Sort {
+			field := spec.Fields[key.Field]
+			if c := compareValues(field.Get(a), field.Get(b)); c != 0 {
+				if key.Desc {
+					return -c
+				}
+				return c
+			}
+		}
+		return strings.Compare(spec.ID(a), spec.ID(b))
+	}
+	sort.SliceStable(out, func(i, j int) bool { return compare(out[i], out[j]) < 0 })
+
+	if q.Cursor != "" {
+		after, err := decodeCursor(q, spec)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		start := sort.Search(len(out), func(i int) bool { return after(out[i]) })
+		out = out[start:]
+	}
+	return PageOf(out, q, spec), nil
+}
+
+// PageOf returns the page of items that were already filtered, sorted and
+// resumed after the cursor, e.g. by a store. Fetching q.Limit+1 items
+// tells whether there is a next page.
+func PageOf[T any](items []T, q Query, spec Spec[T]) Page[T] {
+	page := Page[T]{Items: items}
+	if q.Limit > 0 && len(items) > q.Limit {
+		page.Items = items[:q.Limit]
+		page.NextCursor = encodeCursor(q, page.Items[q.Limit-1], spec)
+	}
+	return page
+}
+
+// Matcher returns a predicate reporting whether an item passes every
+// filter of q, or nil when q has none.
+func Matcher[T any](q Query, spec Spec[T]) func(T) bool {
+	if len(q.Filters) == 0 {
+		return nil
+	}
+	return func(item T) bool { return matchAll(item, q.Filters, spec) }
+}
+
+// From returns the position q's cursor was issued at: the value of each
+// sort key, typed by field kind, and the item ID. values is nil without a
+// cursor.
+func From[T any](q Query, spec Spec[T]) (values []any, id string, err error) {
+	if q.Cursor == "" {
+		return nil, "", nil
+	}
+	return cursorKeys(q, spec)
+}
+
+func encodeCursor[T any](q Query, last T, spec Spec[T]) string {
+	c := cursor{Signature: q.signature(), ID: spec.ID(last)}
+	for _, key := range q.Sort {
+		v := spec.Fields[key.Field].Get(last)
+		if t, ok := v.(time.Time); ok {
+			v = t.Format(time.RFC3339Nano)
+		}
+		c.Values = append(c.Values, v)
+	}
+	body, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(body)
+}
+
+// cursorKeys decodes q's cursor into its sort values and item ID.
+func cursorKeys[T any](q Query, spec Spec[T]) ([]any, string, error) {
+	invalid := fmt.Errorf("%w: cursor is malformed or was issued for a different query", ErrInvalid)
+
+	body, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil {
+		return nil, "", invalid
+	}
+	var c cursor
+	if err := json.Unmarshal(body, &c); err != nil || c.Signature != q.signature() || len(c.Values) != len(q.Sort) {
+		return nil, "", invalid
+	}
+	keys := make([]any, len(c.Values))
+	for i, key := range q.Sort {
+		v, err := parseValue(spec.Fields[key.Field].Kind, fmt.Sprint(c.Values[i]))
+		if err != nil {
+			return nil, "", invalid
+		}
+		keys[i] = v
+	}
+	return keys, c.ID, nil
+}
+
+// decodeCursor returns a predicate reporting whether an item sorts after
+// the position the cursor was issued at.
+func decodeCursor[T any](q Query, spec Spec[T]) (func(T) bool, error) {
+	keys, id, err := cursorKeys(q, spec)
+	if err != nil {
+		return nil, err
+	}
+	return func(item T) bool {
+		for i, key := range q.Sort {
+			cmp := compareValues(spec.Fields[key.Field].Get(item), keys[i])
+			if key.Desc {
+				cmp = -cmp
+			}
+			if cmp != 0 {
+				return cmp > 0
+			}
+		}
+		return spec.ID(item) > id
+	}, nil
+}
+
+func matchAll[T any](item T, filters []Filter, spec Spec[T]) bool {
+	for _, f := range filters {
+		field := spec.Fields[f.Field]
+		if !match(field.Get(item), field.Kind, f) {
+			return false
+		}
+	}
+	return true
+}
+
+func match(v any, kind Kind, f Filter) bool {
+	switch f.Op {
+	case "in":
+		for _, candidate := range strings.Split(f.Value, "|") {
+			want, _ := parseValue(kind, candidate)
+			if compareValues(v, want) == 0 {
+				return true
+			}
+		}
+		return false
+	case "prefix":
+		s, ok := v.(string)
+		return ok && strings.HasPrefix(s, f.Value)
+	}
+
+	want, _ := parseValue(kind, f.Value)
+	cmp := compareValues(v, want)
+	switch f.Op {
+	case "eq":
+		return cmp == 0
+	case "ne":
+		return cmp != 0
+	case "lt":
+		return cmp < 0
+	case "lte":
+		return cmp <= 0
+	case "gt":
+		return cmp > 0
+	case "gte":
+		return cmp >= 0
+	}
+	return false
+}
+
+// parseValue converts a query-string value to the field's kind.
+func parseValue(kind Kind, s string) (any, error) {
+	switch kind {
+	case Number:
+		return strconv.ParseFloat(s, 64)
+	case Time:
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			return t, nil
+		}
+		return time.Parse(time.DateOnly, s)
+	}
+	return s, nil
+}
+
+func compareValues(a, b any) int {
+	switch a := a.(type) {
+	case string:
+		b, _ := b.(string)
+		return strings.Compare(a, b)
+	case float64:
+		b, _ := b.(float64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	case time.Time:
+		b, _ := b.(time.Time)
+		return a.Compare(b)
+	}
+	return 0
+}
@@ -0,0 +1,232 @@
+package listing
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+)
+
+type study struct {
+	ID    string
+	Label string
+	Score float64
+	At    time.Time
+}
+
+var testSpec = Spec[study]{
+	Fields: map[string]Field[study]{
+		"label": {Kind: String, Get: func(s study) any { return s.Label }},
+		"score": {Kind: Number, Get: func(s study) any { return s.Score }},
+		"at":    {Kind: Time, Get: func(s study) any { return s.At }},
+	},
+	ID:           func(s study) string { return s.ID },
+	DefaultSort:  "-at",
+	DefaultLimit: 2,
+	MaxLimit:     5,
+}
+
+var day = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+func studies() []study {
+	return []study{
+		{"s1", "Cancer", 0.9, day},
+		{"s2", "Non-Cancer", 0.1, day.Add(time.Hour)},
+		{"s3", "Cancer", 0.7, day.Add(2 * time.Hour)},
+		{"s4", "Non-Cancer", 0.3, day.Add(time.Hour)},
+		{"s5", "Cancer", 0.7, day.Add(24 * time.Hour)},
+	}
+}
+
+func parse(t *testing.T, raw string) Query {
+	t.Helper()
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := Parse(values, testSpec)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", raw, err)
+	}
+	return q
+}
+
+func ids(items []study) []string {
+	out := make([]string, len(items))
+	for i, s := range items {
+		out[i] = s.ID
+	}
+	return out
+}
+
+func TestParseRejectsMalformedParameters(t *testing.T) {
+	spec := testSpec
+	spec.Sortable = []string{"at"}
+	for _, raw := range []string{
+		"limit=0",
+		"limit=-3",
+		"limit=ten",
+		"sort=name",
+		"sort=-score",
+		"filter=label",
+		"filter=label:eq",
+		"filter=name:eq:x",
+		"filter=label:like:C",
+		"filter=score:gt:high",
+		"filter=score:in:0.1|x",
+		"filter=at:gte:yesterday",
+	} {
+		values, _ := url.ParseQuery(raw)
+		if _, err := Parse(values, spec); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalid", raw, err)
+		}
+	}
+}
+
+func TestParseDefaults(t *testing.T) {
+	q := parse(t, "sort=,%20")
+	if q.Limit != 2 || !slices.Equal(q.Sort, []SortKey{{Field: "at", Desc: true}}) {
+		t.Errorf("defaults = limit %d, sort %v", q.Limit, q.Sort)
+	}
+	if q := parse(t, "limit=500"); q.Limit != 5 {
+		t.Errorf("limit = %d, want the maximum 5", q.Limit)
+	}
+	q = parse(t, "filter=label:eq:Cancer&filter=score:gte:0.5")
+	if got := q.Equal("label"); got != "Cancer" {
+		t.Errorf("Equal(label) = %q", got)
+	}
+	if got := q.Equal("score"); got != "" {
+		t.Errorf("Equal(score) = %q, want none for a gte filter", got)
+	}
+}
+
+func TestApplyFilters(t *testing.T) {
+	cases := map[string][]string{
+		"filter=label:eq:Cancer":                       {"s1", "s3", "s5"},
+		"filter=label:ne:Cancer":                       {"s2", "s4"},
+		"filter=score:lt:0.3":                          {"s2"},
+		"filter=score:lte:0.3":                         {"s2", "s4"},
+		"filter=score:gt:0.7":                          {"s1"},
+		"filter=score:gte:0.7":                         {"s1", "s3", "s5"},
+		"filter=score:in:0.1|0.9":                      {"s1", "s2"},
+		"filter=label:prefix:Non":                      {"s2", "s4"},
+		"filter=at:gte:2026-10-02":                     {"s5"},
+		"filter=at:lt:2026-10-01T01:00:00Z":            {"s1"},
+		"filter=label:eq:Cancer&filter=score:lt:0.8":   {"s3", "s5"},
+		"filter=label:eq:Benign":                       {},
+		"filter=score:prefix:0":                        {},
+		"filter=label:in:Cancer|Non-Cancer":            {"s1", "s2", "s3", "s4", "s5"},
+		"filter=label:eq:Cancer&filter=label:eq:Other": {},
+	}
+	for raw, want := range cases {
+		page, err := Apply(studies(), parse(t, raw+"&limit=5"), testSpec)
+		if err != nil {
+			t.Fatalf("Apply(%q): %v", raw, err)
+		}
+		got := ids(page.Items)
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("Apply(%q) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestApplySortsAndPages(t *testing.T) {
+	cases := map[string][]string{
+		"sort=-at":          {"s5", "s3", "s2", "s4", "s1"},
+		"sort=at":           {"s1", "s2", "s4", "s3", "s5"},
+		"sort=-score,at":    {"s1", "s3", "s5", "s4", "s2"},
+		"sort=label,-score": {"s1", "s3", "s5", "s4", "s2"},
+	}
+	for raw, want := range cases {
+		var got []string
+		q := parse(t, raw)
+		for pages := 0; ; pages++ {
+			if pages > len(want) {
+				t.Fatalf("%s: paging does not terminate", raw)
+			}
+			page, err := Apply(studies(), q, testSpec)
+			if err != nil {
+				t.Fatalf("%s: Apply: %v", raw, err)
+			}
+			got = append(got, ids(page.Items)...)
+			if page.NextCursor == "" {
+				break
+			}
+			q.Cursor = page.NextCursor
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: pages = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestCursorIsBoundToItsQuery(t *testing.T) {
+	first, err := Apply(studies(), parse(t, "filter=label:eq:Cancer&limit=1"), testSpec)
+	if err != nil || first.NextCursor == "" {
+		t.Fatalf("first page = %+v, %v", first, err)
+	}
+	for _, raw := range []string{
+		"filter=label:eq:Non-Cancer&limit=1",
+		"filter=label:eq:Cancer&limit=1&sort=at",
+		"limit=1",
+	} {
+		q := parse(t, raw)
+		q.Cursor = first.NextCursor
+		if _, err := Apply(studies(), q, testSpec); !errors.Is(err, ErrInvalid) {
+			t.Errorf("cursor reused with %q: error = %v, want ErrInvalid", raw, err)
+		}
+	}
+	// The page size may change between pages.
+	q := parse(t, "filter=label:eq:Cancer&limit=5")
+	q.Cursor = first.NextCursor
+	if page, err := Apply(studies(), q, testSpec); err != nil || !slices.Equal(ids(page.Items), []string{"s3", "s1"}) {
+		t.Errorf("next page with a larger limit = %v, %v", ids(page.Items), err)
+	}
+
+	for _, bad := range []string{"%%%", "bm90LWEtY3Vyc29y", "e30", "eyJzIjoiIiwidiI6W10sImlkIjoiIn0"} {
+		q := parse(t, "")
+		q.Cursor = bad
+		if _, err := Apply(studies(), q, testSpec); !errors.Is(err, ErrInvalid) {
+			t.Errorf("cursor %q: error = %v, want ErrInvalid", bad, err)
+		}
+		if _, _, err := From(q, testSpec); !errors.Is(err, ErrInvalid) {
+			t.Errorf("From with cursor %q: error = %v, want ErrInvalid", bad, err)
+		}
+	}
+}
+
+func TestPushdownHelpers(t *testing.T) {
+	q := parse(t, "sort=-score,at&limit=2")
+	if Matcher(q, testSpec) != nil {
+		t.Error("Matcher without filters is not nil")
+	}
+	if values, id, err := From(q, testSpec); values != nil || id != "" || err != nil {
+		t.Errorf("From without a cursor = %v, %q, %v", values, id, err)
+	}
+
+	// A store returning Limit+1 sorted items gets a page and a cursor
+	// resuming after the last item kept.
+	page := PageOf([]study{studies()[0], studies()[2], studies()[4]}, q, testSpec)
+	if !slices.Equal(ids(page.Items), []string{"s1", "s3"}) || page.NextCursor == "" {
+		t.Fatalf("PageOf = %v, %q", ids(page.Items), page.NextCursor)
+	}
+	q.Cursor = page.NextCursor
+	values, id, err := From(q, testSpec)
+	if err != nil || id != "s3" || fmt.Sprint(values) != fmt.Sprint([]any{0.7, day.Add(2 * time.Hour)}) {
+		t.Errorf("From = %v, %q, %v", values, id, err)
+	}
+	if _, ok := values[1].(time.Time); !ok {
+		t.Errorf("From returned %T for a time field", values[1])
+	}
+	if last := PageOf(studies()[:2], q, testSpec); last.NextCursor != "" {
+		t.Error("PageOf without an extra item returned a cursor")
+	}
+
+	match := Matcher(parse(t, "filter=score:gt:0.5"), testSpec)
+	if match == nil || !match(studies()[0]) || match(studies()[1]) {
+		t.Error("Matcher does not apply the filter")
+	}
+}
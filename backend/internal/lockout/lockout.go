@@ -0,0 +1,208 @@
+// backend/internal/lockout/lockout.go
+/*
+ * This file implements the failed-authentication tracker behind the
+ * brute-force protection of the API.
+ *
+ * Failures are counted per subject -- a client address or a presented
+ * credential -- within a sliding window. A subject that reaches the
+ * threshold is locked out for the base duration; every further lockout of
+ * the same subject doubles it, up to the maximum. A subject that stays
+ * quiet for a full window after its lockout ends starts over.
+ *
+ * State is held in memory and bounded: subjects are kept in order of
+ * their last failure, and once the table is full the least recent one not
+ * locked out is evicted to make room, so spraying addresses cannot grow
+ * it without limit. Eviction looks at a few subjects from the old end
+ * only, whatever the size of the table.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package lockout
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Defaults used for unset Config fields.
+const (
+	DefaultThreshold   = 5
+	DefaultWindow      = 15 * time.Minute
+	DefaultBase        = time.Minute
+	DefaultMax         = time.Hour
+	DefaultMaxSubjects = 100_000
+)
+
+// evictScan bounds the subjects makeRoom looks at for one eviction.
+const evictScan = 16
+
+// Config controls when subjects are locked out and for how long.
+type Config struct {
+	// Threshold is the number of failures within Window that locks a
+	// subject out.
+	Threshold int
+	Window    time.Duration
+	// Base is the length of a subject's first lockout; each further one
+	// doubles it, up to Max.
+	Base time.Duration
+	Max  time.Duration
+	// MaxSubjects bounds the number of subjects tracked at once.
+	MaxSubjects int
+}
+
+// Status describes a subject after a failure.
+type Status struct {
+	// Failures is the number of failures in the current window.
+	Failures int
+	// Lockouts is the number of times the subject has been locked out.
+	Lockouts int
+	// Until is the end of the lockout this failure started; zero when it
+	// did not start one.
+	Until time.Time
+}
+
+// subject is the state of one tracked subject.
+type subject struct {
+	key  string
+	elem *list.Element
+
+	windowStart time.Time
+	failures    int
+	lockouts    int
+	until       time.Time
+	last        time.Time
+}
+
+// Tracker counts failed authentications and locks subjects out.
+type Tracker struct {
+	cfg Config
+
+	mu       sync.Mutex
+	subjects map[string]*subject
+	// recent orders the subjects by last failure, most recent first.
+	recent *list.List
+}
+
+// New returns a tracker with the defaults filled in for unset fields.
+func New(cfg Config) *Tracker {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = DefaultThreshold
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultWindow
+	}
+	if cfg.Base <= 0 {
+		cfg.Base = DefaultBase
+	}
+	if cfg.Max < cfg.Base {
+		cfg.Max = max(DefaultMax, cfg.Base)
+	}
+	if cfg.MaxSubjects <= 0 {
+		cfg.MaxSubjects = DefaultMaxSubjects
+	}
+	return &Tracker{cfg: cfg, subjects: make(map[string]*subject), recent: list.New()}
+}
+
+// Locked reports whether key is locked out at now, and until when.
+func (t *Tracker) Locked(key string, now time.Time) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.subjects[key]
+	if !ok || !now.Before(s.until) {
+		return time.Time{}, false
+	}
+	return s.until, true
+}
+
+// Fail records a failed authentication by key at now.
+func (t *Tracker) Fail(key string, now time.Time) Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.subjects[key]
+	if ok && t.expired(s, now) {
+		t.remove(s)
+		ok = false
+	}
+	if !ok {
+		t.makeRoom(now)
+		s = &subject{key: key, windowStart: now}
+		s.elem = t.recent.PushFront(s)
+		t.subjects[key] = s
+	} else {
+		t.recent.MoveToFront(s.elem)
+	}
+	if now.Sub(s.windowStart) >= t.cfg.Window {
+		s.windowStart, s.failures = now, 0
+	}
+	s.failures++
+	s.last = now
+
+	status := Status{Failures: s.failures, Lockouts: s.lockouts}
+	if s.failures >= t.cfg.Threshold && !now.Before(s.until) {
+		s.lockouts++
+		s.until = now.Add(t.duration(s.lockouts))
+		s.windowStart, s.failures = now, 0
+		status.Lockouts, status.Until = s.lockouts, s.until
+	}
+	return status
+}
+
+// Succeed forgets the failures of key, after a successful
+// authentication. A lockout in force is not lifted.
+func (t *Tracker) Succeed(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if s, ok := t.subjects[key]; ok && s.until.IsZero() {
+		t.remove(s)
+	} else if ok {
+		s.failures = 0
+	}
+}
+
+// duration returns the length of a subject's nth lockout.
+func (t *Tracker) duration(n int) time.Duration {
+	d := t.cfg.Base
+	for i := 1; i < n && d < t.cfg.Max; i++ {
+		d *= 2
+	}
+	return min(d, t.cfg.Max)
+}
+
+// expired reports whether s has been quiet long enough to start over.
+func (t *Tracker) expired(s *subject, now time.Time) bool {
+	return now.Sub(s.last) >= t.cfg.Window && now.Sub(s.until) >= t.cfg.Window
+}
+
+// makeRoom ensures there is space for one more subject by evicting the
+// least recent subject not locked out. Locked-out subjects it passes over
+// are moved to the recent end, so later evictions look at others; when
+// every subject it looks at is locked out, the new one is added over the
+// bound.
+func (t *Tracker) makeRoom(now time.Time) {
+	if len(t.subjects) < t.cfg.MaxSubjects {
+		return
+	}
+	for range evictScan {
+		e := t.recent.Back()
+		if e == nil {
+			return
+		}
+		s := e.Value.(*subject)
+		if !now.Before(s.until) {
+			t.remove(s)
+			return
+		}
+		t.recent.MoveToFront(e)
+	}
+}
+
+// remove forgets s.
+func (t *Tracker) remove(s *subject) {
+	t.recent.Remove(s.elem)
+	delete(t.subjects, s.key)
+}
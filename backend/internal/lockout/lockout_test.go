@@ -0,0 +1,134 @@
+package lockout
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+func failN(tr *Tracker, key string, n int, now time.Time) Status {
+	var s Status
+	for range n {
+		s = tr.Fail(key, now)
+	}
+	return s
+}
+
+func TestLockoutAfterThreshold(t *testing.T) {
+	tr := New(Config{Threshold: 3, Window: time.Minute, Base: time.Minute, Max: 3 * time.Minute})
+
+	if s := failN(tr, "10.0.0.1", 2, epoch); !s.Until.IsZero() || s.Failures != 2 {
+		t.Fatalf("below threshold: %+v", s)
+	}
+	if _, locked := tr.Locked("10.0.0.1", epoch); locked {
+		t.Fatal("locked out below the threshold")
+	}
+	s := tr.Fail("10.0.0.1", epoch)
+	if want := epoch.Add(time.Minute); !s.Until.Equal(want) || s.Lockouts != 1 {
+		t.Fatalf("at threshold: %+v, want lockout until %s", s, want)
+	}
+	if until, locked := tr.Locked("10.0.0.1", epoch.Add(59*time.Second)); !locked || !until.Equal(s.Until) {
+		t.Errorf("Locked during lockout = %s, %v", until, locked)
+	}
+	if _, locked := tr.Locked("10.0.0.1", s.Until); locked {
+		t.Error("still locked out when the lockout ends")
+	}
+	if _, locked := tr.Locked("10.0.0.2", epoch); locked {
+		t.Error("unrelated subject locked out")
+	}
+
+	// Failures during a lockout do not extend it.
+	if s := failN(tr, "10.0.0.1", 5, epoch.Add(30*time.Second)); !s.Until.IsZero() {
+		t.Errorf("failure during lockout started another: %+v", s)
+	}
+}
+
+func TestLockoutDoublesUpToMax(t *testing.T) {
+	tr := New(Config{Threshold: 1, Window: time.Hour, Base: time.Minute, Max: 3 * time.Minute})
+	now := epoch
+	for i, want := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute, 3 * time.Minute} {
+		s := tr.Fail("key", now)
+		if got := s.Until.Sub(now); got != want {
+			t.Errorf("lockout %d lasts %s, want %s", i+1, got, want)
+		}
+		now = s.Until
+	}
+}
+
+func TestFailuresOutsideWindowDoNotCount(t *testing.T) {
+	tr := New(Config{Threshold: 3, Window: time.Minute})
+	failN(tr, "key", 2, epoch)
+	if s := tr.Fail("key", epoch.Add(time.Minute)); s.Failures != 1 || !s.Until.IsZero() {
+		t.Errorf("failure after the window: %+v, want a fresh count", s)
+	}
+}
+
+func TestQuietSubjectStartsOver(t *testing.T) {
+	tr := New(Config{Threshold: 1, Window: time.Minute, Base: time.Minute, Max: time.Hour})
+	first := tr.Fail("key", epoch)
+	// Quiet for a full window after the lockout ends: the escalation is
+	// forgotten.
+	s := tr.Fail("key", first.Until.Add(time.Minute))
+	if s.Lockouts != 1 || s.Until.Sub(first.Until.Add(time.Minute)) != time.Minute {
+		t.Errorf("after a quiet window: %+v, want a first lockout again", s)
+	}
+}
+
+func TestSucceed(t *testing.T) {
+	tr := New(Config{Threshold: 3, Window: time.Hour})
+	failN(tr, "key", 2, epoch)
+	tr.Succeed("key")
+	if s := tr.Fail("key", epoch); s.Failures != 1 {
+		t.Errorf("failures after success = %d, want 1", s.Failures)
+	}
+
+	locked := failN(tr, "locked", 3, epoch)
+	tr.Succeed("locked")
+	if _, ok := tr.Locked("locked", epoch); !ok {
+		t.Error("success lifted a lockout in force")
+	}
+	// The escalation survives a success.
+	if s := failN(tr, "locked", 3, locked.Until); s.Lockouts != 2 {
+		t.Errorf("lockouts after success = %d, want 2", s.Lockouts)
+	}
+	tr.Succeed("unknown")
+}
+
+func TestTableIsBounded(t *testing.T) {
+	tr := New(Config{Threshold: 2, Window: time.Hour, MaxSubjects: 4})
+	failN(tr, "locked", 2, epoch)
+	for i := range 100 {
+		tr.Fail(fmt.Sprintf("spray-%d", i), epoch.Add(time.Second))
+	}
+	if got := len(tr.subjects); got != 4 {
+		t.Errorf("tracked %d subjects, want 4", got)
+	}
+	if _, ok := tr.Locked("locked", epoch.Add(time.Second)); !ok {
+		t.Error("locked-out subject evicted by spraying")
+	}
+	if got := tr.recent.Len(); got != len(tr.subjects) {
+		t.Errorf("recency list has %d entries for %d subjects", got, len(tr.subjects))
+	}
+}
+
+func TestTableFullOfLockouts(t *testing.T) {
+	tr := New(Config{Threshold: 1, Window: time.Hour, MaxSubjects: 2})
+	tr.Fail("a", epoch)
+	tr.Fail("b", epoch)
+	tr.Fail("c", epoch)
+	for _, key := range []string{"a", "b", "c"} {
+		if _, ok := tr.Locked(key, epoch); !ok {
+			t.Errorf("%s not locked out", key)
+		}
+	}
+}
+
+func TestNewDefaults(t *testing.T) {
+	tr := New(Config{Base: 2 * time.Hour})
+	want := Config{Threshold: DefaultThreshold, Window: DefaultWindow, Base: 2 * time.Hour, Max: 2 * time.Hour, MaxSubjects: DefaultMaxSubjects}
+	if tr.cfg != want {
+		t.Errorf("cfg = %+v, want %+v", tr.cfg, want)
+	}
+}
@@ -0,0 +1,260 @@
+// backend/internal/logship/forwarder.go
+/*
+ * This file forwards the service's logs to a log collector.
+ *
+ * Some on-prem sites do not allow log collection by scraping container
+ * output, so the service ships its own logs to a syslog server or a
+ * Fluentd forward input. Every line written to a forwarder becomes a
+ * structured record:
+ *
+ *   {"time": "...", "tag": "app", "host": "...", "message": "..."}
+ *
+ * Lines that are JSON objects, such as JSON access log lines, keep their
+ * fields as the record instead of being wrapped in "message".
+ *
+ * Records are queued in a bounded buffer and sent in batches by one
+ * background goroutine, which reconnects with backoff when the collector
+ * goes away. When the buffer is full the forwarder either blocks the
+ * writer (backpressure: no record is lost, but logging slows the service
+ * down) or drops the record and counts it; the count is reported to the
+ * collector once it is reachable again.
+ *
+ * The forwarder never logs through the log package, which may be writing
+ * to it; its own errors go to stderr.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package logship
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Overflow policies.
+const (
+	// Block makes writers wait for room in the buffer.
+	Block = "block"
+	// Drop discards records that do not fit.
+	Drop = "drop"
+)
+
+// Record is one forwarded log line.
+type Record struct {
+	Time time.Time
+	Tag  string
+	// Message is the line itself, unless Fields holds it parsed.
+	Message string
+	// Fields holds the fields of a JSON line.
+	Fields map[string]any
+}
+
+// fields returns the record as a flat map.
+func (r Record) fields(host string) map[string]any {
+	out := make(map[string]any, len(r.Fields)+4)
+	for k, v := range r.Fields {
+		out[k] = v
+	}
+	if r.Fields == nil {
+		out["message"] = r.Message
+	}
+	if _, ok := out["time"]; !ok {
+		out["time"] = r.Time.UTC().Format(time.RFC3339Nano)
+	}
+	out["tag"] = r.Tag
+	out["host"] = host
+	return out
+}
+
+// text returns the record as one line of text.
+func (r Record) text() string {
+	if r.Fields == nil {
+		return r.Message
+	}
+	line, _ := json.Marshal(r.Fields)
+	return string(line)
+}
+
+// Sink sends batches of records to a collector.
+type Sink interface {
+	Send(recs []Record) error
+	Close() error
+}
+
+// Config controls a forwarder.
+type Config struct {
+	// BufferSize is the number of records held while the collector is
+	// slow or unreachable.
+	BufferSize int
+	// BatchSize is the most records sent at once.
+	BatchSize int
+	// FlushInterval is how long a partial batch waits for more records.
+	FlushInterval time.Duration
+	// Overflow is Block or Drop.
+	Overflow string
+	// Backoff is the wait before the first resend; it doubles up to a
+	// minute while the collector stays unreachable.
+	Backoff time.Duration
+}
+
+// Validate checks the configuration.
+func (c Config) Validate() error {
+	if c.Overflow != "" && c.Overflow != Block && c.Overflow != Drop {
+		return fmt.Errorf("overflow policy must be %q or %q, got %q", Block, Drop, c.Overflow)
+	}
+	return nil
+}
+
+// maxBackoff caps the wait between resends.
+const maxBackoff = time.Minute
+
+// Forwarder buffers records and sends them to a sink.
+type Forwarder struct {
+	sink    Sink
+	cfg     Config
+	records chan Record
+	dropped atomic.Int64
+	done    chan struct{}
+
+	closeOnce sync.Once
+}
+
+// New starts a forwarder sending to sink.
+func New(sink Sink, cfg Config) *Forwarder {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 10000
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+	if cfg.Overflow == "" {
+		cfg.Overflow = Block
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = time.Second
+	}
+	f := &Forwarder{sink: sink, cfg: cfg, records: make(chan Record, cfg.BufferSize), done: make(chan struct{})}
+	go f.run()
+	return f
+}
+
+// Writer returns a writer whose lines are forwarded under tag. Each Write
+// should hold whole lines, as the log package's do.
+func (f *Forwarder) Writer(tag string) io.Writer {
+	return writer{f: f, tag: tag}
+}
+
+type writer struct {
+	f   *Forwarder
+	tag string
+}
+
+func (w writer) Write(p []byte) (int, error) {
+	now := time.Now()
+	for _, line := range bytes.Split(bytes.TrimRight(p, "\n"), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		r := Record{Time: now, Tag: w.tag}
+		if line[0] != '{' || json.Unmarshal(line, &r.Fields) != nil {
+			r.Fields, r.Message = nil, string(line)
+		}
+		w.f.enqueue(r)
+	}
+	return len(p), nil
+}
+
+func (f *Forwarder) enqueue(r Record) {
+	if f.cfg.Overflow == Block {
+		f.records <- r
+		return
+	}
+	select {
+	case f.records <- r:
+	default:
+		f.dropped.Add(1)
+	}
+}
+
+// Dropped returns how many records were discarded because the buffer was
+// full.
+func (f *Forwarder) Dropped() int64 {
+	return f.dropped.Load()
+}
+
+// Close sends the buffered records, waiting at most timeout, and closes
+// the sink. Nothing may be written afterwards.
+func (f *Forwarder) Close(timeout time.Duration) error {
+	f.closeOnce.Do(func() { close(f.records) })
+	select {
+	case <-f.done:
+	case <-time.After(timeout):
+		return fmt.Errorf("log forwarding: %d record(s) unsent", len(f.records))
+	}
+	return f.sink.Close()
+}
+
+func (f *Forwarder) run() {
+	defer close(f.done)
+	ticker := time.NewTicker(f.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Record, 0, f.cfg.BatchSize)
+	var reported int64
+	for {
+		select {
+		case r, ok := <-f.records:
+			if !ok {
+				f.send(batch)
+				return
+			}
+			if batch = append(batch, r); len(batch) < f.cfg.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		if dropped := f.dropped.Load(); dropped > reported {
+			batch = append(batch, Record{
+				Time:    time.Now(),
+				Tag:     "logship",
+				Message: fmt.Sprintf("log forwarding buffer full: %d record(s) dropped", dropped-reported),
+			})
+			reported = dropped
+		}
+		f.send(batch)
+		batch = batch[:0]
+	}
+}
+
+// send delivers batch, retrying until it succeeds. While it retries the
+// buffer fills, which is where backpressure or dropping comes from.
+func (f *Forwarder) send(batch []Record) {
+	if len(batch) == 0 {
+		return
+	}
+	backoff := f.cfg.Backoff
+	for {
+		err := f.sink.Send(batch)
+		if err == nil {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "log forwarding: send failed, retrying in %s: %v\n", backoff, err)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
@@ -0,0 +1,216 @@
+// backend/internal/logship/sink.go
+/*
+ * This file implements the collectors logs are forwarded to.
+ *
+ *   syslog://host:514         RFC 5424 syslog over UDP
+ *   syslog+tcp://host:601     RFC 5424 over TCP, octet-counted framing
+ *   syslog+tls://host:6514    as TCP, over TLS (RFC 5425)
+ *   fluentd://host:24224      Fluentd forward protocol, in JSON
+ *   fluentd+tls://host:24224  as fluentd, over TLS
+ *
+ * Syslog messages use the LOCAL0 facility at severity info, with the
+ * record tag as MSGID. Fluentd receives each batch as one forward-mode
+ * message tagged "<prefix>.<record tag>" and acknowledges it, so a batch
+ * counts as sent only once the collector has taken it.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package logship
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ioTimeout bounds connecting to and exchanging one batch with a
+// collector.
+const ioTimeout = 10 * time.Second
+
+// syslogPriority is LOCAL0 (16) × 8 + info (6).
+const syslogPriority = 16*8 + 6
+
+// SinkOptions configure a sink.
+type SinkOptions struct {
+	// App names the service in syslog messages and prefixes Fluentd tags.
+	App string
+	// TLS configures the +tls schemes; nil uses the system roots.
+	TLS *tls.Config
+}
+
+// Open returns the sink for a collector URL.
+func Open(dest string, opts SinkOptions) (Sink, error) {
+	u, err := url.Parse(dest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log forwarding destination %q: %w", dest, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid log forwarding destination %q: no host", dest)
+	}
+	if opts.App == "" {
+		opts.App = "mammoscan"
+	}
+	host, _ := os.Hostname()
+	c := &conn{addr: u.Host}
+	switch u.Scheme {
+	case "syslog", "syslog+udp":
+		c.network = "udp"
+	case "syslog+tcp", "fluentd":
+		c.network = "tcp"
+	case "syslog+tls", "fluentd+tls":
+		c.network = "tcp"
+		c.tls = opts.TLS
+		if c.tls == nil {
+			c.tls = &tls.Config{}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported log forwarding destination %q (want syslog://, syslog+tcp://, syslog+tls://, fluentd:// or fluentd+tls://)", dest)
+	}
+	if strings.HasPrefix(u.Scheme, "fluentd") {
+		return &fluentSink{conn: c, prefix: opts.App, host: host}, nil
+	}
+	return &syslogSink{conn: c, app: opts.App, host: host}, nil
+}
+
+// conn is a connection to a collector, redialled after any error.
+type conn struct {
+	network, addr string
+	tls           *tls.Config
+	c             net.Conn
+	// dec reads Fluentd acknowledgements.
+	dec *json.Decoder
+}
+
+func (c *conn) get() (net.Conn, error) {
+	if c.c != nil {
+		return c.c, nil
+	}
+	d := &net.Dialer{Timeout: ioTimeout}
+	var err error
+	if c.tls != nil {
+		c.c, err = tls.DialWithDialer(d, c.network, c.addr, c.tls)
+	} else {
+		c.c, err = d.Dial(c.network, c.addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.dec = json.NewDecoder(c.c)
+	return c.c, nil
+}
+
+// fail drops the connection after err, so the next send redials.
+func (c *conn) fail(err error) error {
+	c.Close()
+	return err
+}
+
+func (c *conn) Close() error {
+	if c.c == nil {
+		return nil
+	}
+	err := c.c.Close()
+	c.c, c.dec = nil, nil
+	return err
+}
+
+type syslogSink struct {
+	*conn
+	app, host string
+}
+
+func (s *syslogSink) Send(recs []Record) error {
+	nc, err := s.get()
+	if err != nil {
+		return err
+	}
+	nc.SetWriteDeadline(time.Now().Add(ioTimeout))
+	var buf []byte
+	for _, r := range recs {
+		msg := fmt.Sprintf("<%d>1 %s %s %s %d %s - %s",
+			syslogPriority, r.Time.UTC().Format(time.RFC3339Nano), nilValue(s.host), s.app, os.Getpid(), nilValue(r.Tag), r.text())
+		if s.network == "udp" {
+			if _, err := nc.Write([]byte(msg)); err != nil {
+				return s.fail(err)
+			}
+			continue
+		}
+		buf = append(buf, strconv.Itoa(len(msg))...)
+		buf = append(buf, ' ')
+		buf = append(buf, msg...)
+	}
+	if len(buf) > 0 {
+		if _, err := nc.Write(buf); err != nil {
+			return s.fail(err)
+		}
+	}
+	return nil
+}
+
+// nilValue renders an empty syslog header field as "-".
+func nilValue(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.ReplaceAll(s, " ", "_")
+}
+
+type fluentSink struct {
+	*conn
+	prefix, host string
+}
+
+// Send sends one forward-mode message per tag in recs and waits for each
+// to be acknowledged.
+func (s *fluentSink) Send(recs []Record) error {
+	byTag := map[string][]any{}
+	var tags []string
+	for _, r := range recs {
+		if _, ok := byTag[r.Tag]; !ok {
+			tags = append(tags, r.Tag)
+		}
+		byTag[r.Tag] = append(byTag[r.Tag], []any{r.Time.Unix(), r.fields(s.host)})
+	}
+	nc, err := s.get()
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		chunk := newChunkID()
+		msg, err := json.Marshal([]any{s.prefix + "." + tag, byTag[tag], map[string]any{"chunk": chunk}})
+		if err != nil {
+			return err
+		}
+		nc.SetDeadline(time.Now().Add(ioTimeout))
+		if _, err := nc.Write(msg); err != nil {
+			return s.fail(err)
+		}
+		var ack struct {
+			Ack string `json:"ack"`
+		}
+		if err := s.dec.Decode(&ack); err != nil {
+			return s.fail(fmt.Errorf("no acknowledgement: %w", err))
+		}
+		if ack.Ack != chunk {
+			return s.fail(fmt.Errorf("acknowledgement for chunk %q, want %q", ack.Ack, chunk))
+		}
+	}
+	return nil
+}
+
+func newChunkID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return base64.StdEncoding.EncodeToString(b)
+}
@@ -0,0 +1,241 @@
+// backend/internal/mailin/gateway.go
+/*
+ * This file implements the email-in submission gateway.
+ *
+ * Some outreach programs have no integration at all: a technician emails
+ * the scans from the field. The gateway polls a mailbox, scores every
+ * image attached to a message from an authorised sender and replies to
+ * that sender with the results.
+ *
+ * A sender is authorised when its address (or its domain) is on the
+ * allowlist and, unless disabled, the message carries a valid DKIM
+ * signature aligned with the From domain -- the From header alone is
+ * trivial to forge. Messages from anyone else are marked read and ignored
+ * without a reply, so the gateway cannot be used to send backscatter.
+ * Automatic messages (bounces, out-of-office replies) are ignored too, to
+ * avoid mail loops.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package mailin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-msgauth/dkim"
+)
+
+// Message is one message in the mailbox.
+type Message struct {
+	// UID identifies the message in the mailbox.
+	UID uint32
+	// Size is the size of the full message in bytes.
+	Size int64
+	// Raw is the full RFC 5322 message. It is nil when the message exceeds
+	// the mailbox's size limit.
+	Raw []byte
+}
+
+// Mailbox is the inbox being watched.
+type Mailbox interface {
+	// Process calls handle for every unread message, oldest first, and
+	// marks the message read when handle returns true.
+	Process(ctx context.Context, handle func(Message) bool) error
+	// String describes the mailbox for log messages.
+	String() string
+}
+
+// Sender delivers a reply.
+type Sender interface {
+	Send(from string, to []string, msg []byte) error
+}
+
+// ScoreFunc scores one attachment and returns the HTTP status and JSON
+// body of the prediction response.
+type ScoreFunc func(ctx context.Context, filename string, data []byte) (int, []byte)
+
+// Config controls the gateway.
+type Config struct {
+	// Interval is the time between polls.
+	Interval time.Duration
+	// From is the address replies are sent from.
+	From string
+	// Allowed lists the authorised senders: full addresses, or domains
+	// written as "@example.org".
+	Allowed []string
+	// SkipDKIM accepts allowlisted senders without a valid DKIM
+	// signature. Only for mail servers that already enforce it.
+	SkipDKIM bool
+	// MaxAttachments bounds the images scored per message.
+	MaxAttachments int
+	// Disclaimer, when set, ends every reply.
+	Disclaimer string
+}
+
+// DefaultMaxAttachments is used when Config.MaxAttachments is zero.
+const DefaultMaxAttachments = 10
+
+// Gateway polls a mailbox and answers submissions.
+type Gateway struct {
+	mailbox Mailbox
+	sender  Sender
+	cfg     Config
+	score   ScoreFunc
+}
+
+// New creates a gateway. At least one allowed sender is required: an open
+// gateway would score (and reply to) anyone on the internet.
+func New(mailbox Mailbox, sender Sender, cfg Config, score ScoreFunc) (*Gateway, error) {
+	if len(cfg.Allowed) == 0 {
+		return nil, fmt.Errorf("email gateway: no allowed senders configured")
+	}
+	if cfg.From == "" {
+		return nil, fmt.Errorf("email gateway: no reply address configured")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.MaxAttachments <= 0 {
+		cfg.MaxAttachments = DefaultMaxAttachments
+	}
+	for i, a := range cfg.Allowed {
+		cfg.Allowed[i] = strings.ToLower(strings.TrimSpace(a))
+	}
+	return &Gateway{mailbox: mailbox, sender: sender, cfg: cfg, score: score}, nil
+}
+
+// Run polls until ctx is cancelled.
+func (g *Gateway) Run(ctx context.Context) {
+	log.Printf("Email gateway: watching %s every %s", g.mailbox, g.cfg.Interval)
+	ticker := time.NewTicker(g.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := g.Poll(ctx); err != nil {
+			log.Printf("Email gateway: poll %s: %v", g.mailbox, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll handles every unread message once.
+func (g *Gateway) Poll(ctx context.Context) error {
+	return g.mailbox.Process(ctx, func(m Message) bool {
+		if ctx.Err() != nil {
+			return false
+		}
+		return g.handle(ctx, m)
+	})
+}
+
+// handle processes one message. It reports false if the message should
+// be retried on the next poll.
+func (g *Gateway) handle(ctx context.Context, m Message) bool {
+	if m.Raw == nil {
+		// Too large to fetch; without the headers there is no one we can
+		// safely reply to.
+		log.Printf("Email gateway: message %d ignored: %d bytes exceeds the size limit", m.UID, m.Size)
+		return true
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(m.Raw))
+	if err != nil {
+		log.Printf("Email gateway: message %d ignored: %v", m.UID, err)
+		return true
+	}
+	if automatic(msg.Header) {
+		return true
+	}
+	from, err := mail.ParseAddress(msg.Header.Get("From"))
+	if err != nil {
+		log.Printf("Email gateway: message %d ignored: invalid From: %v", m.UID, err)
+		return true
+	}
+	if err := g.authorize(from.Address, m.Raw); err != nil {
+		log.Printf("Email gateway: message %d from %s ignored: %v", m.UID, from.Address, err)
+		return true
+	}
+
+	images, err := attachments(msg, g.cfg.MaxAttachments)
+	if err != nil {
+		log.Printf("Email gateway: message %d from %s: %v", m.UID, from.Address, err)
+	}
+	var results []result
+	for _, img := range images {
+		status, body := g.score(ctx, img.filename, img.data)
+		if status >= http.StatusInternalServerError {
+			log.Printf("Email gateway: message %d: %s failed (%d), will retry: %s", m.UID, img.filename, status, body)
+			return false
+		}
+		results = append(results, newResult(img.filename, status, body))
+	}
+
+	reply, err := composeReply(g.cfg.From, from, msg.Header, results, g.cfg.Disclaimer)
+	if err != nil {
+		log.Printf("Email gateway: message %d: compose reply: %v", m.UID, err)
+		return true
+	}
+	if err := g.sender.Send(g.cfg.From, []string{from.Address}, reply); err != nil {
+		log.Printf("Email gateway: message %d: reply to %s: %v", m.UID, from.Address, err)
+		return false
+	}
+	log.Printf("Email gateway: message %d from %s: %d image(s) scored", m.UID, from.Address, len(results))
+	return true
+}
+
+// authorize checks the sender against the allowlist and, unless disabled,
+// requires a DKIM signature aligned with its domain.
+func (g *Gateway) authorize(address string, raw []byte) error {
+	address = strings.ToLower(address)
+	_, domain, _ := strings.Cut(address, "@")
+	allowed := false
+	for _, a := range g.cfg.Allowed {
+		if a == address || a == "@"+domain {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("sender not on the allowlist")
+	}
+	if g.cfg.SkipDKIM {
+		return nil
+	}
+
+	verifications, err := dkim.Verify(bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("dkim: %w", err)
+	}
+	for _, v := range verifications {
+		d := strings.ToLower(v.Domain)
+		if v.Err == nil && (d == domain || strings.HasSuffix(domain, "."+d)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("no valid DKIM signature for %s", domain)
+}
+
+// automatic reports whether a message was generated by a machine
+// (RFC 3834), which must never be answered.
+func automatic(h mail.Header) bool {
+	if v := strings.ToLower(h.Get("Auto-Submitted")); v != "" && v != "no" {
+		return true
+	}
+	switch strings.ToLower(h.Get("Precedence")) {
+	case "bulk", "junk", "list":
+		return true
+	}
+	return h.Get("List-Id") != ""
+}
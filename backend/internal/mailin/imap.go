@@ -0,0 +1,161 @@
+//go:build !edge
+
+// backend/internal/mailin/imap.go
+/*
+ * This file implements the mailbox on an IMAP server.
+ *
+ * Every poll opens a fresh TLS connection, handles the unread messages and
+ * logs out: polls are minutes apart and long-lived IMAP connections are
+ * routinely dropped by servers. Handled messages are flagged \Seen; ones
+ * that should be retried are left unread. Not built into the edge
+ * profile, which runs disconnected.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package mailin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+)
+
+// IMAPConfig locates and authenticates to an IMAP mailbox.
+type IMAPConfig struct {
+	// Addr is the server as host:port; the connection uses implicit TLS.
+	Addr     string
+	Username string
+	// Password returns the password; it is read for every connection so
+	// a rotated credential is picked up.
+	Password func() string
+	// Mailbox is the folder to watch (default INBOX).
+	Mailbox string
+	// MaxMessageBytes skips larger messages (default 64 MiB).
+	MaxMessageBytes int64
+	// BatchSize bounds the messages handled per poll (default 20).
+	BatchSize int
+}
+
+// IMAP is a mailbox on an IMAP server.
+type IMAP struct {
+	cfg IMAPConfig
+}
+
+// NewIMAP returns an IMAP mailbox. It does not connect until first use.
+func NewIMAP(cfg IMAPConfig) *IMAP {
+	if cfg.Mailbox == "" {
+		cfg.Mailbox = "INBOX"
+	}
+	if cfg.MaxMessageBytes <= 0 {
+		cfg.MaxMessageBytes = 64 << 20
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 20
+	}
+	return &IMAP{cfg: cfg}
+}
+
+// Process implements Mailbox.
+func (m *IMAP) Process(ctx context.Context, handle func(Message) bool) error {
+	c, err := client.DialWithDialerTLS(&net.Dialer{Timeout: 30 * time.Second}, m.cfg.Addr, nil)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer c.Logout()
+	c.Timeout = 5 * time.Minute
+
+	var password string
+	if m.cfg.Password != nil {
+		password = m.cfg.Password()
+	}
+	if err := c.Login(m.cfg.Username, password); err != nil {
+		return fmt.Errorf("login: %w", err)
+	}
+	if _, err := c.Select(m.cfg.Mailbox, false); err != nil {
+		return fmt.Errorf("select %s: %w", m.cfg.Mailbox, err)
+	}
+
+	criteria := imap.NewSearchCriteria()
+	criteria.WithoutFlags = []string{imap.SeenFlag, imap.DeletedFlag}
+	uids, err := c.UidSearch(criteria)
+	if err != nil {
+		return fmt.Errorf("search: %w", err)
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+	if len(uids) > m.cfg.BatchSize {
+		uids = uids[:m.cfg.BatchSize]
+	}
+	if len(uids) == 0 {
+		return nil
+	}
+
+	// Sizes first, so oversized messages are never downloaded.
+	sizes := make(map[uint32]int64)
+	var small []uint32
+	err = fetch(c, uids, []imap.FetchItem{imap.FetchUid, imap.FetchRFC822Size}, func(msg *imap.Message) {
+		sizes[msg.Uid] = int64(msg.Size)
+		if int64(msg.Size) <= m.cfg.MaxMessageBytes {
+			small = append(small, msg.Uid)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	bodies := make(map[uint32][]byte)
+	if len(small) > 0 {
+		section := &imap.BodySectionName{Peek: true}
+		err = fetch(c, small, []imap.FetchItem{imap.FetchUid, section.FetchItem()}, func(msg *imap.Message) {
+			if r := msg.GetBody(section); r != nil {
+				bodies[msg.Uid], _ = io.ReadAll(r)
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, uid := range uids {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !handle(Message{UID: uid, Size: sizes[uid], Raw: bodies[uid]}) {
+			continue
+		}
+		var set imap.SeqSet
+		set.AddNum(uid)
+		flags := []interface{}{imap.SeenFlag}
+		if err := c.UidStore(&set, imap.FormatFlagsOp(imap.AddFlags, true), flags, nil); err != nil {
+			return fmt.Errorf("flag message %d: %w", uid, err)
+		}
+	}
+	return nil
+}
+
+func (m *IMAP) String() string {
+	return fmt.Sprintf("imaps://%s@%s/%s", m.cfg.Username, m.cfg.Addr, m.cfg.Mailbox)
+}
+
+// fetch runs a UID FETCH and calls fn for every returned message.
+func fetch(c *client.Client, uids []uint32, items []imap.FetchItem, fn func(*imap.Message)) error {
+	var set imap.SeqSet
+	set.AddNum(uids...)
+	ch := make(chan *imap.Message, 8)
+	done := make(chan error, 1)
+	go func() { done <- c.UidFetch(&set, items, ch) }()
+	for msg := range ch {
+		fn(msg)
+	}
+	if err := <-done; err != nil {
+		return fmt.Errorf("fetch: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+// backend/internal/mailin/message.go
+/*
+ * This file parses submissions and composes the replies.
+ *
+ * Images are taken from any MIME part whose type is image/* or
+ * application/dicom, or that carries a file name with an image extension
+ * (some mail clients send everything as application/octet-stream). The
+ * reply lists one line per image in plain text and attaches the full
+ * prediction responses as results.json.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package mailin
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/http"
+	"net/mail"
+	"net/textproto"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// maxDepth bounds MIME nesting (forwarded messages in forwarded messages).
+const maxDepth = 8
+
+var imageExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true,
+	".tif": true, ".tiff": true, ".heic": true, ".dcm": true,
+}
+
+// image is one attachment to score.
+type image struct {
+	filename string
+	data     []byte
+}
+
+// attachments extracts up to limit images from a message. Images past the
+// limit are dropped and reported in the error, alongside the ones found.
+func attachments(msg *mail.Message, limit int) ([]image, error) {
+	var images []image
+	skipped := 0
+	var walk func(header textproto.MIMEHeader, body io.Reader, depth int) error
+	walk = func(header textproto.MIMEHeader, body io.Reader, depth int) error {
+		mediaType, params, _ := mime.ParseMediaType(header.Get("Content-Type"))
+		if strings.HasPrefix(mediaType, "multipart/") {
+			if depth >= maxDepth {
+				return fmt.Errorf("MIME structure nested too deeply")
+			}
+			mr := multipart.NewReader(body, params["boundary"])
+			for {
+				part, err := mr.NextRawPart()
+				if err == io.EOF {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				if err := walk(part.Header, part, depth+1); err != nil {
+					return err
+				}
+			}
+		}
+
+		filename := partFilename(header, params)
+		if !strings.HasPrefix(mediaType, "image/") && mediaType != "application/dicom" &&
+			!imageExtensions[strings.ToLower(path.Ext(filename))] {
+			return nil
+		}
+		if len(images) == limit {
+			skipped++
+			return nil
+		}
+		data, err := io.ReadAll(decodeTransfer(header.Get("Content-Transfer-Encoding"), body))
+		if err != nil {
+			return fmt.Errorf("decode %s: %w", filename, err)
+		}
+		if filename == "" {
+			filename = fmt.Sprintf("image-%d", len(images)+1)
+		}
+		images = append(images, image{filename: filename, data: data})
+		return nil
+	}
+
+	err := walk(textproto.MIMEHeader(msg.Header), msg.Body, 0)
+	if err == nil && skipped > 0 {
+		err = fmt.Errorf("%d image(s) past the limit of %d were not scored", skipped, limit)
+	}
+	return images, err
+}
+
+// partFilename returns the attachment's file name, decoding RFC 2047
+// encoded words and dropping any directory part.
+func partFilename(header textproto.MIMEHeader, typeParams map[string]string) string {
+	name := typeParams["name"]
+	if _, params, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		name = params["filename"]
+	}
+	if decoded, err := new(mime.WordDecoder).DecodeHeader(name); err == nil {
+		name = decoded
+	}
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	return name
+}
+
+func decodeTransfer(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	}
+	return r
+}
+
+// result is the outcome for one image, as attached to the reply.
+type result struct {
+	Filename string                     `json:"filename"`
+	Status   int                        `json:"status"`
+	Result   *models.PredictionResponse `json:"result,omitempty"`
+	Error    *models.ErrorResponse      `json:"error,omitempty"`
+}
+
+func newResult(filename string, status int, body []byte) result {
+	r := result{Filename: filename, Status: status}
+	if status == http.StatusOK {
+		r.Result = new(models.PredictionResponse)
+		json.Unmarshal(body, r.Result)
+	} else {
+		r.Error = new(models.ErrorResponse)
+		if json.Unmarshal(body, r.Error) != nil || r.Error.Error == "" {
+			r.Error.Error = http.StatusText(status)
+		}
+	}
+	return r
+}
+
+// line summarises the result in one line of the reply.
+func (r result) line() string {
+	if r.Result == nil {
+		return fmt.Sprintf("%s: not scored: %s", r.Filename, r.Error.Error)
+	}
+	s := fmt.Sprintf("%s: %s (score %.3f, threshold %.3f) - prediction %s",
+		r.Filename, r.Result.Prediction, r.Result.ConfidenceScore, r.Result.ModelThreshold, r.Result.PredictionID)
+	if r.Result.NeedsReview {
+		s += " - models disagree, needs human review"
+	}
+	return s
+}
+
+// composeReply builds the reply to a submission.
+func composeReply(from string, to *mail.Address, original mail.Header, results []result, disclaimer string) ([]byte, error) {
+	var text bytes.Buffer
+	if len(results) == 0 {
+		text.WriteString("No image was found in your message. Attach the scans as PNG, JPEG, TIFF, HEIC or DICOM files.\r\n")
+	} else {
+		fmt.Fprintf(&text, "MammoScan AI results for %d image(s):\r\n\r\n", len(results))
+		for _, r := range results {
+			text.WriteString(r.line() + "\r\n")
+		}
+	}
+	// Every prediction of a tenant carries the same disclaimer; the
+	// configured one is only a fallback for replies without a result.
+	for _, r := range results {
+		if r.Result != nil && r.Result.Disclaimer != "" {
+			disclaimer = r.Result.Disclaimer
+			break
+		}
+	}
+	if disclaimer != "" {
+		text.WriteString("\r\n" + disclaimer + "\r\n")
+	}
+
+	var msg bytes.Buffer
+	mw := multipart.NewWriter(&msg)
+	subject := original.Get("Subject")
+	if decoded, err := new(mime.WordDecoder).DecodeHeader(subject); err == nil {
+		subject = decoded
+	}
+	if !strings.HasPrefix(strings.ToLower(subject), "re:") {
+		subject = strings.TrimSpace("Re: " + subject)
+	}
+	_, domain, _ := strings.Cut(from, "@")
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to.String())
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: <%s@%s>\r\n", uuid.NewString(), domain)
+	if id := original.Get("Message-Id"); id != "" {
+		fmt.Fprintf(&msg, "In-Reply-To: %s\r\n", id)
+		fmt.Fprintf(&msg, "References: %s\r\n", strings.TrimSpace(original.Get("References")+" "+id))
+	}
+	fmt.Fprintf(&msg, "Auto-Submitted: auto-replied\r\n")
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mw.Boundary())
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	qp := quotedprintable.NewWriter(part)
+	qp.Write(text.Bytes())
+	qp.Close()
+
+	if len(results) > 0 {
+		body, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		part, err = mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {"application/json"},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {`attachment; filename="results.json"`},
+		})
+		if err != nil {
+			return nil, err
+		}
+		writeBase64(part, body)
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return msg.Bytes(), nil
+}
+
+// writeBase64 writes data base64-encoded in 76-character lines, as SMTP
+// servers may reject longer lines.
+func writeBase64(w io.Writer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		io.WriteString(w, encoded[:76]+"\r\n")
+		encoded = encoded[76:]
+	}
+	io.WriteString(w, encoded+"\r\n")
+}
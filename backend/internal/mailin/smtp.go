@@ -0,0 +1,43 @@
+// backend/internal/mailin/smtp.go
+/*
+ * This file sends the gateway's replies over SMTP.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package mailin
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+)
+
+// SMTP sends replies through an SMTP server.
+type SMTP struct {
+	// Addr is the server as host:port.
+	Addr     string
+	Username string
+	// Password returns the SMTP password; it is read for every message so
+	// a rotated credential is picked up.
+	Password func() string
+}
+
+// Send implements Sender.
+func (s *SMTP) Send(from string, to []string, msg []byte) error {
+	var auth smtp.Auth
+	if s.Username != "" {
+		host, _, _ := net.SplitHostPort(s.Addr)
+		var password string
+		if s.Password != nil {
+			password = s.Password()
+		}
+		auth = smtp.PlainAuth("", s.Username, password, host)
+	}
+	if err := smtp.SendMail(s.Addr, auth, from, to, msg); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return nil
+}
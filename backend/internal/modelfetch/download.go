@@ -0,0 +1,275 @@
+// backend/internal/modelfetch/download.go
+/*
+ * This file implements resumable model downloads.
+ *
+ * Model artifacts run to several gigabytes. A download is written to a
+ * `.part` file next to its destination and, when the transfer breaks, is
+ * resumed from the bytes already on disk with a ranged read instead of
+ * starting over. Progress is logged as it goes, and the whole transfer
+ * stops as soon as its context is cancelled. The part file is renamed into
+ * place only once complete, so a crash never leaves a truncated model
+ * where the loader would pick it up. Read does the same into memory for
+ * hosts without a writable filesystem.
+ *
+ * A complete transfer can be verified (Options.Verify), typically against
+ * a checksum; one that fails is discarded and fetched again from the
+ * start, as a failed attempt.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package modelfetch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Source is a remote artifact that supports ranged reads.
+type Source interface {
+	// Name identifies the artifact in logs, e.g. its URI.
+	Name() string
+	// Size is the artifact's length in bytes.
+	Size() int64
+	// Version distinguishes revisions of the artifact (for GCS, the
+	// object generation). Parts of different versions are never joined.
+	Version() string
+	// Open reads the artifact from offset to the end.
+	Open(ctx context.Context, offset int64) (io.ReadCloser, error)
+}
+
+// Options tunes a download.
+type Options struct {
+	// Attempts is the number of transfer attempts before giving up.
+	Attempts int
+	// Backoff is the wait before the first retry; it doubles each time.
+	Backoff time.Duration
+	// ProgressInterval is the minimum time between progress log lines.
+	ProgressInterval time.Duration
+	// Verify, if set, checks the complete artifact before it is used.
+	Verify func(io.Reader) error
+}
+
+// DefaultOptions suit a multi-GB artifact over a flaky link.
+func DefaultOptions() Options {
+	return Options{Attempts: 8, Backoff: time.Second, ProgressInterval: 10 * time.Second}
+}
+
+// Download copies src to dest, resuming an earlier partial download of
+// the same version if one exists.
+func Download(ctx context.Context, src Source, dest string, opts Options) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("create model directory: %w", err)
+	}
+	part := fmt.Sprintf("%s.%s.part", dest, src.Version())
+	f, err := os.OpenFile(part, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return fmt.Errorf("open part file: %w", err)
+	}
+	start := time.Now()
+	err = fetch(ctx, src, &fileSink{f}, opts)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(part, dest); err != nil {
+		return fmt.Errorf("move download into place: %w", err)
+	}
+	log.Printf("Downloaded %s to %s (%s in %s)", src.Name(), dest, humanBytes(src.Size()), time.Since(start).Round(time.Second))
+	return nil
+}
+
+// Read downloads src into memory, for hosts where nothing may be written
+// to disk. Interrupted transfers resume from the bytes already received.
+func Read(ctx context.Context, src Source, opts Options) ([]byte, error) {
+	buf := &bufferSink{b: make([]byte, 0, src.Size())}
+	start := time.Now()
+	if err := fetch(ctx, src, buf, opts); err != nil {
+		return nil, err
+	}
+	log.Printf("Read %s into memory (%s in %s)", src.Name(), humanBytes(src.Size()), time.Since(start).Round(time.Second))
+	return buf.b, nil
+}
+
+// sink receives a download. Its length is the resume offset.
+type sink interface {
+	io.Writer
+	Len() (int64, error)
+	Reset() error
+	// Contents reads back everything written.
+	Contents() (io.Reader, error)
+}
+
+// fetch runs transfer attempts with exponential backoff until src has
+// been copied completely into s and verified.
+func fetch(ctx context.Context, src Source, s sink, opts Options) error {
+	return Retry(ctx, "Download of "+src.Name(), opts, func() error {
+		if err := transfer(ctx, src, s, opts.ProgressInterval); err != nil {
+			return err
+		}
+		if opts.Verify == nil {
+			return nil
+		}
+		r, err := s.Contents()
+		if err == nil {
+			err = opts.Verify(r)
+		}
+		if err != nil {
+			if rerr := s.Reset(); rerr != nil {
+				return rerr
+			}
+			return fmt.Errorf("verify %s: %w", src.Name(), err)
+		}
+		return nil
+	})
+}
+
+// Retry calls fn until it succeeds, up to opts.Attempts times with
+// exponential backoff. Errors for something that does not exist
+// (fs.ErrNotExist) are not retried. what describes the operation in logs.
+func Retry(ctx context.Context, what string, opts Options, fn func() error) error {
+	if opts.Attempts <= 0 {
+		opts.Attempts = 1
+	}
+	backoff := opts.Backoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil || errors.Is(err, fs.ErrNotExist) || attempt >= opts.Attempts {
+			return err
+		}
+		log.Printf("%s failed (attempt %d/%d): %v; retrying in %s", what, attempt, opts.Attempts, err, backoff)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// transfer appends the missing tail of src to s.
+func transfer(ctx context.Context, src Source, s sink, interval time.Duration) error {
+	offset, err := s.Len()
+	if err != nil {
+		return err
+	}
+	total := src.Size()
+	if offset > total {
+		// Not a prefix of this artifact after all; start over.
+		if err := s.Reset(); err != nil {
+			return err
+		}
+		offset = 0
+	}
+	if offset == total {
+		return nil
+	}
+	if offset > 0 {
+		log.Printf("Resuming %s at %s of %s", src.Name(), humanBytes(offset), humanBytes(total))
+	}
+
+	rc, err := src.Open(ctx, offset)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", src.Name(), err)
+	}
+	defer rc.Close()
+
+	p := &progress{name: src.Name(), done: offset, total: total, interval: interval, start: time.Now(), last: time.Now()}
+	n, err := io.Copy(io.MultiWriter(s, p), rc)
+	if err != nil {
+		return fmt.Errorf("copy: %w", err)
+	}
+	if offset+n != total {
+		return fmt.Errorf("copy: %w (have %d of %d bytes)", io.ErrUnexpectedEOF, offset+n, total)
+	}
+	return nil
+}
+
+type fileSink struct{ *os.File }
+
+func (f *fileSink) Len() (int64, error) {
+	n, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, fmt.Errorf("seek part file: %w", err)
+	}
+	return n, nil
+}
+
+func (f *fileSink) Contents() (io.Reader, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("seek part file: %w", err)
+	}
+	return f.File, nil
+}
+
+func (f *fileSink) Reset() error {
+	if err := f.Truncate(0); err != nil {
+		return fmt.Errorf("truncate part file: %w", err)
+	}
+	_, err := f.Seek(0, io.SeekStart)
+	return err
+}
+
+type bufferSink struct{ b []byte }
+
+func (s *bufferSink) Write(p []byte) (int, error) {
+	s.b = append(s.b, p...)
+	return len(p), nil
+}
+
+func (s *bufferSink) Len() (int64, error) { return int64(len(s.b)), nil }
+func (s *bufferSink) Reset() error        { s.b = s.b[:0]; return nil }
+
+func (s *bufferSink) Contents() (io.Reader, error) { return bytes.NewReader(s.b), nil }
+
+// progress logs transfer progress at most once per interval.
+type progress struct {
+	name        string
+	done, total int64
+	interval    time.Duration
+	start, last time.Time
+	sinceStart  int64
+}
+
+func (p *progress) Write(b []byte) (int, error) {
+	p.done += int64(len(b))
+	p.sinceStart += int64(len(b))
+	if p.interval > 0 && time.Since(p.last) >= p.interval {
+		p.last = time.Now()
+		rate := float64(p.sinceStart) / time.Since(p.start).Seconds()
+		log.Printf("Downloading %s: %s of %s (%.0f%%, %s/s)", p.name, humanBytes(p.done), humanBytes(p.total),
+			100*float64(p.done)/float64(max(p.total, 1)), humanBytes(int64(rate)))
+	}
+	return len(b), nil
+}
+
+func humanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -13,9 +13,36 @@
 
 package models
 
+import (
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/jobs"
+	"github.com/josephed37/mammoscan-AI/backend/internal/journal"
+	"github.com/josephed37/mammoscan-AI/backend/internal/modelswap"
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+	"github.com/josephed37/mammoscan-AI/backend/internal/stats"
+)
+
+// Classification labels returned in PredictionResponse.Prediction.
+const (
+	LabelCancer    = "Cancer"
+	LabelNonCancer = "Non-Cancer"
+)
+
+// LabelFor applies a decision threshold to a confidence score.
+func LabelFor(score, threshold float64) string {
+	if score > threshold {
+		return LabelCancer
+	}
+	return LabelNonCancer
+}
+
 // PredictionResponse defines the structure for a successful JSON response
 // when a prediction is made.
 type PredictionResponse struct {
+	// A globally unique, time-ordered identifier for this prediction.
+	PredictionID string `json:"prediction_id"`
+
 	// The final classification label (e.g., "Cancer" or "Non-Cancer").
 	// The `json:"..."` tag defines how this field will be named in the JSON output.
 	Prediction string `json:"prediction"`
@@ -26,12 +53,571 @@ type PredictionResponse struct {
 	// The name of the model that produced the prediction.
 	ModelName string `json:"model_name"`
 
+	// The version of that model, when known (see ModelInfo.Version).
+	ModelVersion string `json:"model_version,omitempty"`
+
 	// The specific classification threshold used to make the final prediction.
 	ModelThreshold float64 `json:"model_threshold"`
+
+	// Opaque correlation fields supplied by the client, echoed unchanged so
+	// integrators can match our results to their RIS records.
+	ClientReference string `json:"client_reference,omitempty"`
+	AccessionNumber string `json:"accession_number,omitempty"`
+
+	// Whether this image was already scored, and if it was for the same
+	// tenant, the ID of the original prediction. Another tenant's
+	// prediction IDs are never disclosed.
+	Duplicate   bool   `json:"duplicate,omitempty"`
+	DuplicateOf string `json:"duplicate_of,omitempty"`
+
+	// Regulatory wording required in the submitting tenant's jurisdiction.
+	Disclaimer string `json:"disclaimer,omitempty"`
+
+	// When an ensemble is configured: every model's score (the served
+	// model first), the largest score gap between any two of them, and
+	// whether that gap is large enough for the study to need human review.
+	Ensemble     []ModelScore `json:"ensemble,omitempty"`
+	Disagreement *float64     `json:"disagreement,omitempty"`
+	NeedsReview  bool         `json:"needs_review,omitempty"`
+	// EnsembleDecision is set when the ensemble combines its models'
+	// scores; Prediction, ConfidenceScore and ModelThreshold are then the
+	// ensemble's, and the served model's own score is Ensemble[0].
+	EnsembleDecision *EnsembleDecision `json:"ensemble_decision,omitempty"`
+
+	// Tiling holds the per-patch scores when the study was scored in
+	// tiling mode; ConfidenceScore is then their aggregate.
+	Tiling *TiledScore `json:"tiling,omitempty"`
+
+	// TTA holds the score of every augmented copy when the study was
+	// scored with test-time augmentation; ConfidenceScore is then their
+	// mean.
+	TTA *TTAScore `json:"tta,omitempty"`
+
+	// Explanation is returned when the client asked for one with the
+	// `explain` parameter.
+	Explanation *Explanation `json:"explanation,omitempty"`
+
+	// Compute is the estimated cost of the prediction, when the
+	// deployment estimates energy use.
+	Compute *ComputeCost `json:"compute,omitempty"`
+}
+
+// ComputeCost is the compute time spent on a prediction and its
+// estimated energy use and emissions.
+type ComputeCost struct {
+	ComputeMillis float64 `json:"compute_ms"`
+	EnergyWh      float64 `json:"energy_wh,omitempty"`
+	CarbonGrams   float64 `json:"carbon_g_co2e,omitempty"`
+}
+
+// ExplainResponse is returned by POST /api/v1/explain: the study's score
+// and the explanation of it, with the heatmap drawn over the study.
+type ExplainResponse struct {
+	Prediction      string  `json:"prediction"`
+	ConfidenceScore float64 `json:"confidence_score"`
+	ModelName       string  `json:"model_name"`
+	ModelVersion    string  `json:"model_version,omitempty"`
+	ModelThreshold  float64 `json:"model_threshold"`
+
+	Explanation *Explanation `json:"explanation"`
+
+	// OverlayPNG is the heatmap drawn over the study, as a base64-encoded
+	// PNG.
+	OverlayPNG string `json:"overlay_png"`
+}
+
+// PreprocessDebugResponse is returned by POST /api/v1/preprocess/debug:
+// the input the model would receive for a study, without running it.
+type PreprocessDebugResponse struct {
+	// Shape is the input tensor's shape, batch first.
+	Shape []int `json:"shape"`
+	// Channels holds the statistics of each input channel, R, G and B.
+	Channels []ChannelStats `json:"channels"`
+	// DistributionWarning is why the study would be rejected as unlike
+	// the training data, if it would be.
+	DistributionWarning string `json:"distribution_warning,omitempty"`
+
+	// ImagePNG is the input tensor rendered as a base64-encoded PNG.
+	ImagePNG string `json:"image_png"`
+}
+
+// ChannelStats summarises the values of one input tensor channel.
+type ChannelStats struct {
+	Name string  `json:"name"`
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Mean float64 `json:"mean"`
+	// NaNs counts the values that are not numbers, which the other
+	// statistics leave out.
+	NaNs int `json:"nan_count"`
+}
+
+// CompareResponse is returned by POST /api/v1/compare: one study scored
+// by two loaded models, side by side.
+type CompareResponse struct {
+	A ModelComparison `json:"a"`
+	B ModelComparison `json:"b"`
+	// ScoreDelta is B's score minus A's.
+	ScoreDelta  float64 `json:"score_delta"`
+	LabelsAgree bool    `json:"labels_agree"`
+	// HeatmapDiff is B's heatmap minus A's, cell by cell, so areas B
+	// finds more suspicious are positive; only when both are explained.
+	HeatmapDiff *ProbabilityMap `json:"heatmap_diff,omitempty"`
+}
+
+// ModelComparison is one side of a CompareResponse.
+type ModelComparison struct {
+	ModelName       string       `json:"model_name"`
+	ModelVersion    string       `json:"model_version,omitempty"`
+	ModelThreshold  float64      `json:"model_threshold"`
+	Prediction      string       `json:"prediction"`
+	ConfidenceScore float64      `json:"confidence_score"`
+	Explanation     *Explanation `json:"explanation,omitempty"`
+}
+
+// BatchPredictionResponse holds the results of a batch upload, one per
+// image in upload order.
+type BatchPredictionResponse struct {
+	Results   []BatchItemResult `json:"results"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+}
+
+// BatchItemResult is the outcome of one image of a batch: the status and
+// body POST /api/v1/predict would have returned for it.
+type BatchItemResult struct {
+	Filename string              `json:"filename"`
+	Status   int                 `json:"status"`
+	Result   *PredictionResponse `json:"result,omitempty"`
+	Error    *ErrorResponse      `json:"error,omitempty"`
+}
+
+// Explanation shows which parts of the image drove the score, at the
+// level of detail the client asked for.
+type Explanation struct {
+	Level string `json:"level"`
+	// Method names how the heatmap was obtained, e.g. "patch_scores".
+	Method  string          `json:"method"`
+	Heatmap *ProbabilityMap `json:"heatmap,omitempty"`
+	// Regions are the most suspicious areas, highest score first.
+	Regions []Region `json:"regions,omitempty"`
+}
+
+// Region is a suspicious area of the scored image: its bounding
+// rectangle in pixels, and its peak score.
+type Region struct {
+	X      int     `json:"x"`
+	Y      int     `json:"y"`
+	Width  int     `json:"width"`
+	Height int     `json:"height"`
+	Score  float64 `json:"score"`
+	// MeanScore averages the region's cells.
+	MeanScore float64 `json:"mean_score"`
+	// Area approximates the region's size in pixels, which may be less
+	// than its bounding box; AreaFraction is its share of the image.
+	Area         int     `json:"area_pixels"`
+	AreaFraction float64 `json:"area_fraction"`
+}
+
+// TiledScore details a study scored patch by patch in tiling mode.
+type TiledScore struct {
+	Aggregation string `json:"aggregation"`
+	PatchSize   int    `json:"patch_size"`
+	// Rows and Cols give the patch grid; Width and Height the scored
+	// image in pixels, which patch coordinates refer to.
+	Rows    int          `json:"rows"`
+	Cols    int          `json:"cols"`
+	Width   int          `json:"width"`
+	Height  int          `json:"height"`
+	Patches []PatchScore `json:"patches"`
+
+	// ProbabilityMap stitches the patch scores into a coarse map of the
+	// image, for localisation.
+	ProbabilityMap *ProbabilityMap `json:"probability_map,omitempty"`
+}
+
+// ProbabilityMap is a low-resolution grid of probabilities over an image.
+type ProbabilityMap struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// CellPixel is the side of one cell in image pixels.
+	CellPixel float64 `json:"cell_pixels"`
+	// Values is indexed [row][column], top-left first.
+	Values [][]float64 `json:"values"`
+}
+
+// TTAScore details a study scored with test-time augmentation.
+type TTAScore struct {
+	Aggregation string `json:"aggregation"`
+	// StdDev is the spread of the copies' scores: how much the model's
+	// opinion depends on the augmentations.
+	StdDev float64          `json:"std_dev"`
+	Scores []AugmentedScore `json:"scores"`
+}
+
+// AugmentedScore is the score of one augmented copy of a study.
+type AugmentedScore struct {
+	Augmentation    string  `json:"augmentation"`
+	ConfidenceScore float64 `json:"confidence_score"`
+}
+
+// PatchScore is the score of one patch, in row-major grid order.
+type PatchScore struct {
+	Row    int     `json:"row"`
+	Col    int     `json:"col"`
+	X      int     `json:"x"`
+	Y      int     `json:"y"`
+	Width  int     `json:"width"`
+	Height int     `json:"height"`
+	Score  float64 `json:"score"`
+}
+
+// ModelScore is one model's opinion on a study.
+type ModelScore struct {
+	ModelName       string  `json:"model_name"`
+	ConfidenceScore float64 `json:"confidence_score"`
+	Prediction      string  `json:"prediction"`
+	// Weight is the model's say in an ensemble decision; absent when the
+	// served model decides alone or the model does not count.
+	Weight float64 `json:"weight,omitempty"`
+}
+
+// EnsembleDecision is the decision of an ensemble that combines its
+// models' scores. ConfidenceScore is the weighted mean score, or with
+// majority_vote the weighted share of malignant votes; Threshold labels
+// it.
+type EnsembleDecision struct {
+	Method          string  `json:"method"`
+	ConfidenceScore float64 `json:"confidence_score"`
+	Prediction      string  `json:"prediction"`
+	Threshold       float64 `json:"threshold"`
+}
+
+// OfflinePredictionRecord is the payload queued for store-and-forward sync
+// when the service runs in offline mode. It wraps the response that was
+// returned to the client with the context the central service needs.
+type OfflinePredictionRecord struct {
+	PredictionResponse
+	Filename string `json:"filename,omitempty"`
+}
+
+// StoredPrediction is a prediction as recorded in the prediction store.
+type StoredPrediction struct {
+	PredictionResponse
+	CreatedAt time.Time `json:"created_at"`
+	Tenant    string    `json:"tenant,omitempty"`
+	// ImageSHA256 is the hex SHA-256 of the scored image, identifying it
+	// for audit without retaining it.
+	ImageSHA256 string `json:"image_sha256,omitempty"`
+	// Subgroups holds optional demographic/acquisition metadata (age band,
+	// site, scanner vendor) used for fairness monitoring.
+	Subgroups map[string]string `json:"subgroups,omitempty"`
+	// Experiment and ExperimentArm record which arm of a running model
+	// experiment scored this study.
+	Experiment    string `json:"experiment,omitempty"`
+	ExperimentArm string `json:"experiment_arm,omitempty"`
+	// Shadow is the score of the candidate model of a shadow experiment,
+	// which scored the study without affecting the response.
+	Shadow *ShadowScore `json:"shadow,omitempty"`
+	// Feedback is the reviewer-confirmed outcome, once known.
+	Feedback *Feedback `json:"feedback,omitempty"`
+	// DeletedAt marks a soft-deleted record: hidden from lookups but kept
+	// for audit until it is restored or purged.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy string     `json:"deleted_by,omitempty"`
+}
+
+// ShadowScore is a shadow model's opinion of a study.
+type ShadowScore struct {
+	ModelName       string    `json:"model_name"`
+	ConfidenceScore float64   `json:"confidence_score"`
+	Prediction      string    `json:"prediction"`
+	Threshold       float64   `json:"threshold"`
+	ScoredAt        time.Time `json:"scored_at"`
+}
+
+// Feedback records the ground truth established after a prediction, e.g.
+// by biopsy or radiologist review.
+type Feedback struct {
+	GroundTruth string    `json:"ground_truth" binding:"required,oneof=Cancer Non-Cancer"`
+	Reviewer    string    `json:"reviewer,omitempty"`
+	Notes       string    `json:"notes,omitempty"`
+	RecordedAt  time.Time `json:"recorded_at"`
+	// Version starts at 1 and is incremented by the server on every
+	// update; it backs the ETag clients must echo in If-Match.
+	Version int `json:"version"`
+}
+
+// PredictionListResponse wraps the results of a prediction lookup.
+type PredictionListResponse struct {
+	Predictions []StoredPrediction `json:"predictions"`
+	// NextCursor fetches the next page; it is omitted on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// JobListResponse wraps one page of background jobs.
+type JobListResponse struct {
+	Jobs       []jobs.Job `json:"jobs"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// JournalResponse lists the journaled requests awaiting recovery.
+type JournalResponse struct {
+	Entries    []journal.Entry `json:"entries"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// JournalReplayResult is the result of a journal replay job.
+type JournalReplayResult struct {
+	Replayed int `json:"replayed"`
+	// Failed counts replays answered with a server error; they stay in
+	// the journal.
+	Failed int `json:"failed"`
+	// Resubmit lists the entries without an image, to be resubmitted by
+	// their senders.
+	Resubmit []journal.Entry `json:"resubmit,omitempty"`
+}
+
+// ModelInfo describes the model that is currently being served.
+type ModelInfo struct {
+	Name string `json:"name"`
+	// Version identifies the artifact revision, e.g. the GCS object
+	// generation, or the local file's modification time and size.
+	Version  string    `json:"version,omitempty"`
+	Source   string    `json:"source,omitempty"`
+	Path     string    `json:"path,omitempty"`
+	LoadedAt time.Time `json:"loaded_at"`
+	// Backend is the inference backend running the model, e.g.
+	// "onnxruntime".
+	Backend string `json:"backend,omitempty"`
+	// Optimizations lists the graph optimization passes applied at load
+	// time and how many rewrites each made.
+	Optimizations []GraphOptimization `json:"optimizations,omitempty"`
+	// Precision is the execution precision, e.g. "fp32".
+	Precision string `json:"precision,omitempty"`
+	// Verification reports the golden-set comparison of the transformed
+	// model with the original.
+	Verification *ModelVerification `json:"verification,omitempty"`
+	// MemoryBytes is the memory the model holds while serving.
+	MemoryBytes int64 `json:"memory_bytes,omitempty"`
+	// Workers is the number of instances scoring concurrently, when the
+	// model runs on a worker pool.
+	Workers int `json:"workers,omitempty"`
+	// BuiltAt is when the model artifact was produced; only set when a
+	// maximum model age is configured.
+	BuiltAt *time.Time `json:"built_at,omitempty"`
+	// Threshold is the decision threshold this model's scores are
+	// labelled with.
+	Threshold float64 `json:"threshold,omitempty"`
+	// Preprocessing is the model's input pipeline, when it is not the
+	// champion model's.
+	Preprocessing *preprocess.Profile `json:"preprocessing,omitempty"`
+}
+
+// ModelAge reports the age of the served model against the configured
+// maximum.
+type ModelAge struct {
+	Model   string     `json:"model"`
+	Version string     `json:"version,omitempty"`
+	BuiltAt *time.Time `json:"built_at,omitempty"`
+	// AgeDays is unset when the artifact time is unknown; such a model
+	// is never stale.
+	AgeDays    float64 `json:"age_days,omitempty"`
+	MaxAgeDays float64 `json:"max_age_days"`
+	Stale      bool    `json:"stale"`
+	// Blocking is set when predictions are refused because the model is
+	// stale.
+	Blocking bool `json:"blocking,omitempty"`
+}
+
+// HealthStatus is the health check response of a service that is up but
+// degraded, and of GET /healthz.
+type HealthStatus struct {
+	Status       string             `json:"status"`
+	Warnings     []string           `json:"warnings,omitempty"`
+	Dependencies []DependencyStatus `json:"dependencies,omitempty"`
+}
+
+// DependencyStatus is the health of a downstream dependency (the
+// prediction database, webhook targets) as its circuit breaker sees it.
+type DependencyStatus struct {
+	Name string `json:"name"`
+	// State is "closed" (healthy), "open" (calls refused) or "half_open"
+	// (a probe is in flight).
+	State               string  `json:"state"`
+	Healthy             bool    `json:"healthy"`
+	ConsecutiveFailures int     `json:"consecutive_failures"`
+	LatencyMillis       float64 `json:"latency_ms"`
+	// Detail summarises a dependency made of several targets.
+	Detail        string     `json:"detail,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+}
+
+// ModelVerification compares a transformed model with the original on
+// the golden set.
+type ModelVerification struct {
+	Samples      int     `json:"samples"`
+	MaxDeviation float64 `json:"max_deviation"`
+	Tolerance    float64 `json:"tolerance"`
+}
+
+// ModelSlots reports the active (served) and standby models.
+type ModelSlots struct {
+	Active  ModelInfo  `json:"active"`
+	Standby *ModelInfo `json:"standby,omitempty"`
+}
+
+// ModelRegistry lists the loaded models: the active and standby slots and
+// the versions retained for activation, most recent first.
+type ModelRegistry struct {
+	Active   ModelInfo   `json:"active"`
+	Standby  *ModelInfo  `json:"standby,omitempty"`
+	Retained []ModelInfo `json:"retained,omitempty"`
+}
+
+// StandbyRequest asks for a model to be loaded into the standby slot.
+type StandbyRequest struct {
+	// ModelRef is a local path or remote URI.
+	ModelRef string `json:"model_ref" binding:"required"`
+	// Name defaults to the active model's name.
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// GraphOptimization reports one graph optimization pass.
+type GraphOptimization struct {
+	Pass     string `json:"pass"`
+	Rewrites int    `json:"rewrites"`
+}
+
+// Capabilities describes what this deployment supports, so clients can
+// adapt instead of hardcoding assumptions.
+type Capabilities struct {
+	// BuildProfile is "standard", "edge" or "local".
+	BuildProfile string `json:"build_profile"`
+	// Formats lists the image formats accepted by the predict endpoint.
+	Formats []string `json:"formats"`
+	// MultiFramePolicy is how animated or multi-page images are handled.
+	MultiFramePolicy string `json:"multi_frame_policy"`
+	// Models lists the models that score predictions: the served model
+	// first, then any ensemble members.
+	Models   []ModelInfo      `json:"models"`
+	Limits   CapabilityLimits `json:"limits"`
+	Features map[string]bool  `json:"features"`
+	// Languages lists the languages error messages can be returned in.
+	Languages []string `json:"languages"`
+}
+
+// CapabilityLimits reports the size limits clients must respect. A zero
+// value means no limit is enforced.
+type CapabilityLimits struct {
+	MaxStreamFrameBytes      int     `json:"max_stream_frame_bytes"`
+	MaxStreamDurationSeconds float64 `json:"max_stream_duration_seconds"`
+	MaxCorrelationFieldLen   int     `json:"max_correlation_field_length"`
+	MaxListPageSize          int     `json:"max_list_page_size"`
+	MaxJobWaitSeconds        float64 `json:"max_job_wait_seconds"`
+	MaxBatchImages           int     `json:"max_batch_images"`
+	MinImageWidth            int     `json:"min_image_width"`
+	MinImageHeight           int     `json:"min_image_height"`
+}
+
+// AdminOverview is the single-pane-of-glass view served to the ops dashboard.
+type AdminOverview struct {
+	Service            stats.Snapshot    `json:"service"`
+	Model              ModelInfo         `json:"model"`
+	Queues             QueueDepths       `json:"queues"`
+	DuplicateIndexSize int               `json:"duplicate_index_size,omitempty"`
+	ModelMemory        *MemoryUsage      `json:"model_memory,omitempty"`
+	ModelSwap          *modelswap.Status `json:"model_swap,omitempty"`
+	ModelAge           *ModelAge         `json:"model_age,omitempty"`
+	Batching           *BatchingStatus   `json:"batching,omitempty"`
+}
+
+// BatchingStatus reports the batched inference limits in force and, when
+// they are tuned to a latency target, what the last tuning window saw.
+type BatchingStatus struct {
+	MaxSize         int     `json:"max_size"`
+	MaxWaitMillis   float64 `json:"max_wait_ms"`
+	TargetP95Millis float64 `json:"target_p95_ms,omitempty"`
+	P95Millis       float64 `json:"p95_ms,omitempty"`
+	// Throughput is in predictions per second.
+	Throughput    float64 `json:"throughput,omitempty"`
+	MeanBatchSize float64 `json:"mean_batch_size,omitempty"`
+	Adjustments   int     `json:"adjustments,omitempty"`
+}
+
+// MemoryUsage reports the memory of the loaded models against the budget.
+type MemoryUsage struct {
+	// BudgetBytes is the limit; zero when usage is only tracked.
+	BudgetBytes int64         `json:"budget_bytes,omitempty"`
+	UsedBytes   int64         `json:"used_bytes"`
+	Models      []ModelMemory `json:"models"`
+}
+
+// ModelMemory is the memory held by one loaded model.
+type ModelMemory struct {
+	Ref      string    `json:"ref"`
+	Bytes    int64     `json:"bytes"`
+	LoadedAt time.Time `json:"loaded_at"`
+}
+
+// QueueDepths reports the backlog of each buffering subsystem.
+type QueueDepths struct {
+	OfflinePending int   `json:"offline_pending"`
+	BillingDropped int64 `json:"billing_dropped"`
+	// EventsDropped counts, per event bus subscriber, events discarded
+	// because the subscriber fell behind.
+	EventsDropped   map[string]int64 `json:"events_dropped,omitempty"`
+	WebhooksDropped int64            `json:"webhooks_dropped,omitempty"`
+	// JournalPending counts requests received but never answered.
+	JournalPending int `json:"journal_pending,omitempty"`
+	// StorePending counts prediction writes queued on local disk that
+	// have not reached the database yet.
+	StorePending int `json:"store_pending,omitempty"`
+	// WebhooksPending counts webhook deliveries held in the outbox until
+	// their endpoint acknowledges them.
+	WebhooksPending int `json:"webhooks_pending,omitempty"`
+	// WebhooksDeadLettered counts webhook deliveries moved to the dead
+	// letter queue, never acknowledged within the outbox's maximum age.
+	WebhooksDeadLettered int `json:"webhooks_dead_lettered,omitempty"`
 }
 
 // ErrorResponse defines a standard structure for all error messages
 // returned by the API. This ensures errors are consistent and easy for clients to parse.
 type ErrorResponse struct {
 	Error string `json:"error"`
+	// Code is a stable, machine-readable identifier for the error. It is
+	// never translated, so clients should branch on it rather than Error.
+	Code string `json:"code,omitempty"`
+	// Detail keeps the original English message when Error has been
+	// localized, for support tickets and logs.
+	Detail string `json:"detail,omitempty"`
+}
+
+// UploadToken is a short-lived, single-use credential for one upload.
+type UploadToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// FramePrediction is the result for one scored frame of a live stream.
+type FramePrediction struct {
+	// Frame is the 1-based index of the frame within the stream, counting
+	// frames skipped by the sampler.
+	Frame           int       `json:"frame"`
+	ReceivedAt      time.Time `json:"received_at"`
+	Prediction      string    `json:"prediction"`
+	ConfidenceScore float64   `json:"confidence_score"`
+	ModelName       string    `json:"model_name"`
+	ModelThreshold  float64   `json:"model_threshold"`
+}
+
+// StreamSummary is sent as the final event of a stream session.
+type StreamSummary struct {
+	FramesReceived int    `json:"frames_received"`
+	FramesScored   int    `json:"frames_scored"`
+	Reason         string `json:"reason"`
+	Error          string `json:"error,omitempty"`
 }
@@ -0,0 +1,226 @@
+// backend/internal/modelstore/store.go
+/*
+ * This file contains the model store, which fetches model artifacts from
+ * wherever they are published, chosen by the scheme of their URI:
+ *
+ *   gs://bucket/path/model.onnx             Google Cloud Storage (gcs.go)
+ *   s3://bucket/path/model.onnx             Amazon S3 or compatible (s3.go)
+ *   azblob://account/container/model.onnx   Azure Blob Storage (azure.go)
+ *   https://host/path/model.onnx            any HTTPS server (http.go)
+ *   file:///models/model.onnx, or a path    the local filesystem (local.go)
+ *
+ * Each scheme is served by a Fetcher, which looks up the current version
+ * of an artifact; the transfer itself, with its resumption, retries and
+ * progress logging, is internal/modelfetch's. Lookups are retried with
+ * the same backoff as transfers, except for artifacts that do not exist.
+ *
+ * Every artifact is verified before use against the checksums its store
+ * records (an MD5 for GCS and Azure, a full-object SHA-256 for S3 when
+ * the uploader set one) and against an expected SHA-256 when the caller
+ * has one. A corrupted transfer is discarded and fetched again.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package modelstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/modelfetch"
+)
+
+// ErrChecksumMismatch is wrapped by errors for an artifact whose content
+// does not match its checksum.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Object is one version of a stored artifact. Open reads that version
+// only, so a resumed transfer never splices two versions together.
+type Object interface {
+	modelfetch.Source
+	// Modified is when this version was produced.
+	Modified() time.Time
+	// Checksums are the digests the store records for this version.
+	Checksums() Checksums
+}
+
+// Checksums are digests of an artifact; nil ones are unknown.
+type Checksums struct {
+	MD5    []byte
+	SHA256 []byte
+}
+
+// Fetcher looks up artifacts in one kind of store. Lookups of artifacts
+// that do not exist return an error wrapping fs.ErrNotExist.
+type Fetcher interface {
+	Stat(ctx context.Context, u *url.URL) (Object, error)
+}
+
+// Store fetches artifacts with the fetcher for their URI's scheme. Local
+// paths and file:// URIs are always understood.
+type Store struct {
+	fetchers map[string]Fetcher
+	opts     modelfetch.Options
+}
+
+// New creates a store with fetchers keyed by URI scheme (e.g. "gs",
+// "s3"), transferring with opts.
+func New(fetchers map[string]Fetcher, opts modelfetch.Options) *Store {
+	all := map[string]Fetcher{"file": Local{}}
+	for scheme, f := range fetchers {
+		all[scheme] = f
+	}
+	return &Store{fetchers: all, opts: opts}
+}
+
+// LocalPath returns the filesystem path of a local artifact; ok is false
+// for any other URI.
+func LocalPath(uri string) (path string, ok bool) {
+	if p, found := strings.CutPrefix(uri, "file://"); found {
+		return p, true
+	}
+	return uri, !strings.Contains(uri, "://")
+}
+
+// Redact returns uri for logs, without a query string, where pre-signed
+// URLs carry their credentials.
+func Redact(uri string) string {
+	if _, local := LocalPath(uri); local {
+		return uri
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "(invalid URI)"
+	}
+	return displayURL(u)
+}
+
+// Stat looks up the current version of the artifact at uri.
+func (s *Store) Stat(ctx context.Context, uri string) (Object, error) {
+	var u *url.URL
+	var err error
+	if path, local := LocalPath(uri); local {
+		u = &url.URL{Scheme: "file", Path: path}
+	} else if u, err = url.Parse(uri); err != nil {
+		return nil, errors.New("invalid model URI")
+	}
+	f, ok := s.fetchers[u.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("unsupported model URI %s: no %s:// store in this build", Redact(uri), u.Scheme)
+	}
+	var obj Object
+	err = modelfetch.Retry(ctx, "Lookup of "+Redact(uri), s.opts, func() (err error) {
+		obj, err = f.Stat(ctx, u)
+		return err
+	})
+	return obj, err
+}
+
+// Fetch makes the artifact at uri available as a local file and returns
+// its path: a local artifact is verified where it is, a remote one
+// downloaded to dest. want is the expected SHA-256 in hex, or "".
+func (s *Store) Fetch(ctx context.Context, uri, dest, want string) (string, error) {
+	obj, err := s.Stat(ctx, uri)
+	if err != nil {
+		return "", err
+	}
+	opts := s.opts
+	if opts.Verify, err = verifier(obj.Checksums(), want); err != nil {
+		return "", err
+	}
+	if path, local := LocalPath(uri); local {
+		f, err := os.Open(path)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		if opts.Verify == nil {
+			return path, nil
+		}
+		if err := opts.Verify(f); err != nil {
+			return "", fmt.Errorf("verify %s: %w", path, err)
+		}
+		return path, nil
+	}
+	return dest, modelfetch.Download(ctx, obj, dest, opts)
+}
+
+// Read reads the artifact at uri into memory, verified like Fetch.
+func (s *Store) Read(ctx context.Context, uri, want string) ([]byte, error) {
+	obj, err := s.Stat(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	opts := s.opts
+	if opts.Verify, err = verifier(obj.Checksums(), want); err != nil {
+		return nil, err
+	}
+	if path, local := LocalPath(uri); local {
+		data, err := os.ReadFile(path)
+		if err == nil && opts.Verify != nil {
+			if err = opts.Verify(bytes.NewReader(data)); err != nil {
+				err = fmt.Errorf("verify %s: %w", path, err)
+			}
+		}
+		return data, err
+	}
+	return modelfetch.Read(ctx, obj, opts)
+}
+
+// verifier returns a check of an artifact's content against the digests
+// its store records and the expected SHA-256 in hex, if any; nil when
+// there is nothing to check against.
+func verifier(known Checksums, want string) (func(io.Reader) error, error) {
+	if want != "" {
+		sum, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(want), "sha256:"))
+		if err != nil || len(sum) != sha256.Size {
+			return nil, fmt.Errorf("invalid SHA-256 checksum %q", want)
+		}
+		if known.SHA256 != nil && !bytes.Equal(known.SHA256, sum) {
+			return nil, fmt.Errorf("%w: the store records SHA-256 %x, expected %x", ErrChecksumMismatch, known.SHA256, sum)
+		}
+		known.SHA256 = sum
+	}
+	if known.MD5 == nil && known.SHA256 == nil {
+		return nil, nil
+	}
+	return func(r io.Reader) error {
+		md5h, sha := md5.New(), sha256.New()
+		if _, err := io.Copy(io.MultiWriter(md5h, sha), r); err != nil {
+			return err
+		}
+		if known.MD5 != nil && !bytes.Equal(md5h.Sum(nil), known.MD5) {
+			return fmt.Errorf("%w: MD5 %x, the store records %x", ErrChecksumMismatch, md5h.Sum(nil), known.MD5)
+		}
+		if known.SHA256 != nil && !bytes.Equal(sha.Sum(nil), known.SHA256) {
+			return fmt.Errorf("%w: SHA-256 %x, expected %x", ErrChecksumMismatch, sha.Sum(nil), known.SHA256)
+		}
+		return nil
+	}, nil
+}
+
+// versionTag makes a store's version identifier (an ETag, say) safe for
+// the part file names modelfetch derives from it.
+func versionTag(v string) string {
+	v = strings.Trim(strings.TrimPrefix(v, "W/"), `"`)
+	for _, r := range v {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			sum := sha256.Sum256([]byte(v))
+			return hex.EncodeToString(sum[:8])
+		}
+	}
+	return v
+}
@@ -0,0 +1,150 @@
+// backend/internal/modelswap/cron.go
+/*
+ * This file parses the cron-style expressions that define swap windows.
+ *
+ * An expression has the five classic fields -- minute, hour, day of
+ * month, month, day of week -- each a "*", a number, a range "a-b", a
+ * stepped range "a-b/n" (where "*" may stand for the range), or a
+ * comma-separated list of those. Unlike cron, which fires at the matching
+ * minutes, a window is open during every matching minute: "* 1-4 * * 1-5"
+ * is the window from 01:00 to 04:59 on weekdays. As in cron, when both the
+ * day of month and the day of week are restricted, a day matching either
+ * one is in the window. Days of the week run from 0 (Sunday) to 6; 7 is
+ * also Sunday.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package modelswap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Window is a parsed cron-style expression.
+type Window struct {
+	spec                         string
+	minute, hour, dom, month     uint64
+	dow                          uint64
+	domRestricted, dowRestricted bool
+}
+
+// field bounds, in expression order.
+var fields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// ParseWindow parses a five-field cron-style expression.
+func ParseWindow(spec string) (*Window, error) {
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("window %q: want %d fields, got %d", spec, len(fields), len(parts))
+	}
+	sets := make([]uint64, len(fields))
+	for i, part := range parts {
+		set, err := parseField(part, fields[i].min, fields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("window %q: %s: %w", spec, fields[i].name, err)
+		}
+		sets[i] = set
+	}
+	w := &Window{
+		spec:          strings.Join(parts, " "),
+		minute:        sets[0],
+		hour:          sets[1],
+		dom:           sets[2],
+		month:         sets[3],
+		dow:           sets[4],
+		domRestricted: parts[2] != "*",
+		dowRestricted: parts[4] != "*",
+	}
+	if w.dow&(1<<7) != 0 {
+		w.dow |= 1 // 7 is Sunday too
+	}
+	return w, nil
+}
+
+// parseField returns the set of values matched by one field as a bitmask.
+func parseField(s string, min, max int) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(s, ",") {
+		rng, stepStr, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+			step = n
+		}
+		lo, hi := min, max
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(a); err != nil {
+				return 0, fmt.Errorf("invalid value %q", a)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(b); err != nil {
+					return 0, fmt.Errorf("invalid value %q", b)
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max {
+			return 0, fmt.Errorf("%q is out of range %d-%d", item, min, max)
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("invalid range %q", rng)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// Contains reports whether t falls in the window, in t's location.
+func (w *Window) Contains(t time.Time) bool {
+	if w.minute&(1<<uint(t.Minute())) == 0 || w.hour&(1<<uint(t.Hour())) == 0 ||
+		w.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	domMatch := w.dom&(1<<uint(t.Day())) != 0
+	dowMatch := w.dow&(1<<uint(t.Weekday())) != 0
+	if w.domRestricted && w.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// Next returns the start of the first minute at or after t that is in the
+// window, looking at most a year ahead; ok is false if there is none.
+func (w *Window) Next(t time.Time) (next time.Time, ok bool) {
+	t = t.Truncate(time.Minute)
+	end := t.AddDate(1, 0, 1)
+	for ; t.Before(end); t = t.Add(time.Minute) {
+		if w.Contains(t) {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (w *Window) String() string {
+	return w.spec
+}
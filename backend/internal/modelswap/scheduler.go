@@ -0,0 +1,210 @@
+// backend/internal/modelswap/scheduler.go
+/*
+ * This file schedules model swaps into low-traffic windows.
+ *
+ * The scheduler checks the model artifact for a new version at a fixed
+ * interval. A new version is not loaded the moment it appears: loading a
+ * large model competes with inference for CPU and memory, and swapping
+ * during clinic hours caused latency spikes. The version is held as
+ * pending until a configured window opens; it is then loaded, validated
+ * and put in service. A version that fails to load or validate is not
+ * retried until the artifact changes again. Reload checks at once and
+ * ignores the windows, for operators who cannot wait.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package modelswap
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Config controls a Scheduler.
+type Config struct {
+	// Interval is the time between checks for a new version (default 10
+	// minutes).
+	Interval time.Duration
+	// Windows are the times a swap may start; none means any time.
+	Windows []*Window
+	// Location is the time zone of the windows (default UTC).
+	Location *time.Location
+	// Current is the version being served. When empty, the first version
+	// seen is taken as the served one.
+	Current string
+	// Version returns the current version of the artifact.
+	Version func(ctx context.Context) (string, error)
+	// Swap loads and validates the given version and puts it in service.
+	// On error the served model must be left as it was.
+	Swap func(ctx context.Context, version string) error
+}
+
+// Status reports the scheduler's state.
+type Status struct {
+	Current string `json:"current_version,omitempty"`
+	// Pending is a new version waiting for a window.
+	Pending string `json:"pending_version,omitempty"`
+	// Failed is the last version that could not be swapped in.
+	Failed     string     `json:"failed_version,omitempty"`
+	LastError  string     `json:"last_error,omitempty"`
+	Windows    []string   `json:"windows,omitempty"`
+	NextWindow *time.Time `json:"next_window,omitempty"`
+	LastCheck  *time.Time `json:"last_check,omitempty"`
+	LastSwap   *time.Time `json:"last_swap,omitempty"`
+}
+
+// Scheduler swaps in new model versions during the configured windows.
+type Scheduler struct {
+	cfg Config
+
+	// checkMu serialises checks, so a reload never races a scheduled
+	// swap of the same version.
+	checkMu sync.Mutex
+
+	mu     sync.Mutex
+	status Status
+}
+
+// NewScheduler validates cfg and returns a scheduler; call Run to start it.
+func NewScheduler(cfg Config) (*Scheduler, error) {
+	if cfg.Version == nil || cfg.Swap == nil {
+		return nil, fmt.Errorf("model swap: Version and Swap are required")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 10 * time.Minute
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	s := &Scheduler{cfg: cfg, status: Status{Current: cfg.Current}}
+	for _, w := range cfg.Windows {
+		s.status.Windows = append(s.status.Windows, w.String())
+	}
+	return s, nil
+}
+
+// Run checks for new versions until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	log.Printf("Model swap: checking every %s, swapping %s", s.cfg.Interval, s.describeWindows())
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		s.Check(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check looks for a new version once and swaps it in if now is inside a
+// window.
+func (s *Scheduler) Check(ctx context.Context, now time.Time) {
+	s.check(ctx, now, false)
+}
+
+// Reload looks for a new version now and swaps it in whatever the
+// windows say; a version that failed before is tried again. It returns
+// the version served afterwards.
+func (s *Scheduler) Reload(ctx context.Context) (string, error) {
+	err := s.check(ctx, time.Now(), true)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status.Current, err
+}
+
+// check is Check; with force set it ignores the windows and earlier
+// failures and reports why no swap happened.
+func (s *Scheduler) check(ctx context.Context, now time.Time, force bool) error {
+	s.checkMu.Lock()
+	defer s.checkMu.Unlock()
+	version, err := s.cfg.Version(ctx)
+
+	s.mu.Lock()
+	checked := now.UTC()
+	s.status.LastCheck = &checked
+	if err != nil {
+		s.status.LastError = fmt.Sprintf("check version: %v", err)
+		s.mu.Unlock()
+		log.Printf("Model swap: %s", s.status.LastError)
+		return fmt.Errorf("check version: %w", err)
+	}
+	if version == s.status.Current || (version == s.status.Failed && !force) {
+		s.status.Pending = ""
+		s.mu.Unlock()
+		return nil
+	}
+	if s.status.Current == "" {
+		s.status.Current = version
+		s.mu.Unlock()
+		return nil
+	}
+	if s.status.Pending != version {
+		s.status.Pending = version
+		log.Printf("Model swap: version %s available", version)
+	}
+	open := force || s.inWindow(now)
+	s.mu.Unlock()
+	if !open {
+		return nil
+	}
+
+	log.Printf("Model swap: loading version %s", version)
+	err = s.cfg.Swap(ctx, version)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status.Pending = ""
+	if err != nil {
+		s.status.Failed, s.status.LastError = version, err.Error()
+		log.Printf("Model swap: version %s rejected, keeping %s: %v", version, s.status.Current, err)
+		return fmt.Errorf("version %s: %w", version, err)
+	}
+	swapped := time.Now().UTC()
+	s.status.Current, s.status.LastSwap, s.status.LastError = version, &swapped, ""
+	log.Printf("Model swap: now serving version %s", version)
+	return nil
+}
+
+// inWindow reports whether a swap may start at t.
+func (s *Scheduler) inWindow(t time.Time) bool {
+	if len(s.cfg.Windows) == 0 {
+		return true
+	}
+	t = t.In(s.cfg.Location)
+	for _, w := range s.cfg.Windows {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Status returns a snapshot of the scheduler's state.
+func (s *Scheduler) Status() Status {
+	s.mu.Lock()
+	status := s.status
+	s.mu.Unlock()
+
+	now := time.Now().In(s.cfg.Location)
+	for _, w := range s.cfg.Windows {
+		if next, ok := w.Next(now); ok && (status.NextWindow == nil || next.Before(*status.NextWindow)) {
+			status.NextWindow = &next
+		}
+	}
+	return status
+}
+
+func (s *Scheduler) describeWindows() string {
+	if len(s.cfg.Windows) == 0 {
+		return "as soon as a new version appears"
+	}
+	return fmt.Sprintf("during %v (%s)", s.status.Windows, s.cfg.Location)
+}
@@ -0,0 +1,176 @@
+// backend/internal/netacl/netacl.go
+/*
+ * This file contains network-level access control.
+ *
+ * Some on-prem installs have no capable firewall in front of the service,
+ * so it restricts by client address itself, per endpoint group, as
+ * defence in depth under authentication. The policy is a JSON document:
+ *
+ *   {
+ *     "trusted_proxies": ["10.0.0.5/32"],
+ *     "groups": {
+ *       "default": {"allow": ["10.0.0.0/8"]},
+ *       "admin":   {"allow": ["10.9.0.0/24"], "deny": ["10.9.0.66/32"]},
+ *       "health":  {}
+ *     }
+ *   }
+ *
+ * A group with an allowlist only admits addresses in it; a denylist
+ * refuses addresses in it whatever the allowlist says. Groups without an
+ * entry fall back to "default"; a group defined empty admits everyone.
+ * Entries are CIDR blocks or single addresses, IPv4 or IPv6.
+ *
+ * The client address is the connection's peer, or the address forwarded
+ * by a proxy listed in trusted_proxies: forwarding headers from anyone
+ * else are ignored, so they cannot be used to get past the lists.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package netacl
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultGroup applies to groups the policy does not name.
+const DefaultGroup = "default"
+
+// Rules restrict one endpoint group.
+type Rules struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+
+	allow, deny []netip.Prefix
+}
+
+// Policy is a loaded network policy.
+type Policy struct {
+	TrustedProxies []string         `json:"trusted_proxies"`
+	Groups         map[string]Rules `json:"groups"`
+}
+
+// Load reads and validates a policy file.
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := p.compile(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &p, nil
+}
+
+func (p *Policy) compile() error {
+	if _, err := parsePrefixes(p.TrustedProxies); err != nil {
+		return fmt.Errorf("trusted_proxies: %w", err)
+	}
+	for name, r := range p.Groups {
+		var err error
+		if r.allow, err = parsePrefixes(r.Allow); err != nil {
+			return fmt.Errorf("group %s allow: %w", name, err)
+		}
+		if r.deny, err = parsePrefixes(r.Deny); err != nil {
+			return fmt.Errorf("group %s deny: %w", name, err)
+		}
+		p.Groups[name] = r
+	}
+	return nil
+}
+
+// parsePrefixes parses CIDR blocks and single addresses.
+func parsePrefixes(entries []string) ([]netip.Prefix, error) {
+	out := make([]netip.Prefix, 0, len(entries))
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if !strings.Contains(e, "/") {
+			addr, err := netip.ParseAddr(e)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(e)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, prefix.Masked())
+	}
+	return out, nil
+}
+
+// Allowed reports whether addr may reach the endpoints of group. Unparsable
+// addresses are refused wherever a list applies.
+func (p *Policy) Allowed(group, addr string) bool {
+	r, ok := p.Groups[group]
+	if !ok {
+		if r, ok = p.Groups[DefaultGroup]; !ok {
+			return true
+		}
+	}
+	if len(r.allow) == 0 && len(r.deny) == 0 {
+		return true
+	}
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return false
+	}
+	ip = ip.Unmap()
+	if contains(r.deny, ip) {
+		return false
+	}
+	return len(r.allow) == 0 || contains(r.allow, ip)
+}
+
+func contains(prefixes []netip.Prefix, ip netip.Addr) bool {
+	for _, p := range prefixes {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Engine serves the current policy and reloads it from disk on demand.
+type Engine struct {
+	path    string
+	current atomic.Pointer[Policy]
+}
+
+// NewEngine loads the policy at path.
+func NewEngine(path string) (*Engine, error) {
+	e := &Engine{path: path}
+	if err := e.Reload(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Reload re-reads the policy file. On error the previous policy stays in
+// force. Trusted proxies are only read at startup.
+func (e *Engine) Reload() error {
+	p, err := Load(e.path)
+	if err != nil {
+		return err
+	}
+	e.current.Store(p)
+	return nil
+}
+
+// Policy returns the policy currently in force.
+func (e *Engine) Policy() *Policy {
+	return e.current.Load()
+}
@@ -0,0 +1,101 @@
+package netacl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePolicy(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "network.json")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	return path
+}
+
+func TestAllowed(t *testing.T) {
+	p, err := Load(writePolicy(t, `{
+		"groups": {
+			"default": {"allow": ["10.0.0.0/8", "fd00::/8"]},
+			"admin":   {"allow": ["10.9.0.0/24"], "deny": [" 10.9.0.66 "]},
+			"partner": {"deny": ["192.0.2.0/24"]},
+			"health":  {}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	cases := []struct {
+		group, addr string
+		want        bool
+	}{
+		{"api", "10.1.2.3", true},
+		{"api", "192.168.1.1", false},
+		{"api", "fd12::1", true},
+		{"api", "2001:db8::1", false},
+		{"api", "::ffff:10.1.2.3", true},
+		{"api", "", false},
+		{"api", "not-an-ip", false},
+		{"api", "10.1.2.3:443", false},
+		{"admin", "10.9.0.1", true},
+		{"admin", "10.9.0.66", false},
+		{"admin", "10.1.2.3", false},
+		{"partner", "203.0.113.9", true},
+		{"partner", "192.0.2.7", false},
+		{"partner", "garbage", false},
+		{"health", "192.168.1.1", true},
+		{"health", "garbage", true},
+	}
+	for _, tc := range cases {
+		if got := p.Allowed(tc.group, tc.addr); got != tc.want {
+			t.Errorf("Allowed(%s, %q) = %v, want %v", tc.group, tc.addr, got, tc.want)
+		}
+	}
+
+	open := &Policy{}
+	if !open.Allowed("admin", "192.168.1.1") {
+		t.Error("policy without groups refused an address")
+	}
+}
+
+func TestLoadRejectsMalformedPolicies(t *testing.T) {
+	cases := []struct {
+		name, body, want string
+	}{
+		{"not json", `{"groups":`, "parse"},
+		{"bad allow", `{"groups": {"admin": {"allow": ["10.0.0.0/33"]}}}`, "group admin allow"},
+		{"bad deny", `{"groups": {"admin": {"deny": ["example.com"]}}}`, "group admin deny"},
+		{"bad proxy", `{"trusted_proxies": ["10.0.0.300"]}`, "trusted_proxies"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(writePolicy(t, tc.body))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Load error = %v, want one mentioning %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestEngineReloadKeepsPolicyOnError(t *testing.T) {
+	path := writePolicy(t, `{"groups": {"default": {"allow": ["10.0.0.0/8"]}}}`)
+	e, err := NewEngine(path)
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{"groups": {"default": {"allow": ["nope"]}}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Reload(); err == nil {
+		t.Fatal("Reload of an invalid policy succeeded")
+	}
+	if e.Policy().Allowed("api", "192.168.1.1") || !e.Policy().Allowed("api", "10.0.0.1") {
+		t.Error("previous policy not kept after a failed reload")
+	}
+	if _, err := NewEngine(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("NewEngine with a missing policy file succeeded")
+	}
+}
@@ -0,0 +1,210 @@
+// backend/internal/ood/ood.go
+/*
+ * This file implements the out-of-distribution input guard.
+ *
+ * The classifier will happily return a confident score for a chest X-ray
+ * or a photo of a cat. Before inference we compute a handful of cheap
+ * image statistics and compare them with a profile fitted on the training
+ * mammograms. Inputs too far from that profile are refused rather than
+ * scored.
+ *
+ * Mammograms are grayscale, mostly dark background with a bright breast
+ * region and soft edges; the features below capture exactly those traits.
+ * Distance is the root-mean-square z-score across features, so one wildly
+ * wrong feature (colour in a photo) is enough to trip the guard.
+ *
+ * Profiles are produced with `go run ./cmd/oodprofile`, which uses this
+ * same code, so training and serving features cannot drift apart.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package ood
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"math"
+	"os"
+	"slices"
+
+	"github.com/nfnt/resize"
+)
+
+// FeatureNames lists the features in the order Features returns them.
+var FeatureNames = []string{
+	"mean_luminance",
+	"std_luminance",
+	"dark_fraction",
+	"bright_fraction",
+	"colorfulness",
+	"edge_density",
+	"histogram_entropy",
+}
+
+// sampleSize is the side of the thumbnail features are computed on.
+const sampleSize = 64
+
+// minStd keeps features that barely vary in training (colorfulness of
+// grayscale films) from dividing by zero while still making any real
+// deviation stand out.
+const minStd = 0.01
+
+// Features computes the guard's statistics for img.
+func Features(img image.Image) []float64 {
+	thumb := resize.Resize(sampleSize, sampleSize, img, resize.Bilinear)
+	b := thumb.Bounds()
+
+	lum := make([]float64, 0, sampleSize*sampleSize)
+	var chroma float64
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, _ := thumb.At(x, y).RGBA()
+			rf, gf, bf := float64(r)/0xffff, float64(g)/0xffff, float64(bl)/0xffff
+			chroma += max(rf, gf, bf) - min(rf, gf, bf)
+			lum = append(lum, 0.299*rf+0.587*gf+0.114*bf)
+		}
+	}
+	n := float64(len(lum))
+
+	var mean, dark, bright float64
+	hist := make([]float64, 32)
+	for _, l := range lum {
+		mean += l
+		if l < 0.1 {
+			dark++
+		}
+		if l > 0.9 {
+			bright++
+		}
+		hist[min(int(l*32), 31)]++
+	}
+	mean /= n
+
+	var variance float64
+	for _, l := range lum {
+		variance += (l - mean) * (l - mean)
+	}
+
+	// Mean absolute luminance difference between neighbouring pixels.
+	w, h := b.Dx(), b.Dy()
+	var edges float64
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			if x+1 < w {
+				edges += math.Abs(lum[i+1] - lum[i])
+			}
+			if y+1 < h {
+				edges += math.Abs(lum[i+w] - lum[i])
+			}
+		}
+	}
+	edges /= float64(2*w*h - w - h)
+
+	var entropy float64
+	for _, c := range hist {
+		if c > 0 {
+			p := c / n
+			entropy -= p * math.Log2(p)
+		}
+	}
+
+	return []float64{
+		mean,
+		math.Sqrt(variance / n),
+		dark / n,
+		bright / n,
+		chroma / n,
+		edges,
+		entropy / math.Log2(float64(len(hist))),
+	}
+}
+
+// Profile describes the training distribution.
+type Profile struct {
+	Features []string  `json:"features"`
+	Mean     []float64 `json:"mean"`
+	Std      []float64 `json:"std"`
+	// MaxDistance is the largest distance accepted as in-distribution.
+	MaxDistance float64 `json:"max_distance"`
+	// Samples is the number of images the profile was fitted on.
+	Samples int `json:"samples"`
+}
+
+// Fit builds a profile from the features of training images. The cutoff
+// is the given quantile (e.g. 0.995) of the training distances, scaled by
+// margin to leave headroom for legitimate variation.
+func Fit(features [][]float64, quantile, margin float64) (*Profile, error) {
+	if len(features) < 2 {
+		return nil, fmt.Errorf("need at least 2 images, got %d", len(features))
+	}
+	k := len(FeatureNames)
+	p := &Profile{
+		Features: FeatureNames,
+		Mean:     make([]float64, k),
+		Std:      make([]float64, k),
+		Samples:  len(features),
+	}
+	for _, f := range features {
+		for i, v := range f {
+			p.Mean[i] += v / float64(len(features))
+		}
+	}
+	for _, f := range features {
+		for i, v := range f {
+			p.Std[i] += (v - p.Mean[i]) * (v - p.Mean[i])
+		}
+	}
+	for i := range p.Std {
+		p.Std[i] = math.Sqrt(p.Std[i] / float64(len(features)-1))
+	}
+
+	distances := make([]float64, len(features))
+	for i, f := range features {
+		distances[i] = p.Distance(f)
+	}
+	slices.Sort(distances)
+	idx := min(int(math.Ceil(quantile*float64(len(distances))))-1, len(distances)-1)
+	p.MaxDistance = distances[max(idx, 0)] * margin
+	return p, nil
+}
+
+// Load reads a profile written by Fit.
+func Load(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read OOD profile: %w", err)
+	}
+	var p Profile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse OOD profile %s: %w", path, err)
+	}
+	if !slices.Equal(p.Features, FeatureNames) || len(p.Mean) != len(FeatureNames) || len(p.Std) != len(FeatureNames) {
+		return nil, fmt.Errorf("OOD profile %s was fitted on different features; refit it", path)
+	}
+	if p.MaxDistance <= 0 {
+		return nil, fmt.Errorf("OOD profile %s has no max_distance", path)
+	}
+	return &p, nil
+}
+
+// Distance is the RMS z-score of features against the profile.
+func (p *Profile) Distance(features []float64) float64 {
+	var sum float64
+	for i, v := range features {
+		z := (v - p.Mean[i]) / max(p.Std[i], minStd)
+		sum += z * z
+	}
+	return math.Sqrt(sum / float64(len(features)))
+}
+
+// Check reports whether img looks like the training data, and its
+// distance from it.
+func (p *Profile) Check(img image.Image) (float64, bool) {
+	d := p.Distance(Features(img))
+	return d, d <= p.MaxDistance
+}
@@ -0,0 +1,639 @@
+// backend/internal/preprocess/dicom.go
+/*
+ * This file registers DICOM decoding with the standard `image` package.
+ *
+ * Mammograms leave the modality as DICOM files: a header of tagged
+ * elements followed by the pixel data, typically 12 to 16 bits of
+ * grey per pixel. Those values are not display values. To get the image a
+ * radiologist sees, and the model was trained on, the stored values go
+ * through the modality LUT (rescale slope and intercept), then the VOI LUT
+ * (the LUT in the header, else the window centre and width, else the full
+ * range of the image), and MONOCHROME1 images, where a low value is white,
+ * are inverted. The result is a 16-bit grey image that continues through
+ * the usual resize and tensor conversion.
+ *
+ * Supported are the uncompressed transfer syntaxes (implicit and explicit
+ * VR little endian, explicit VR big endian, deflated) and baseline JPEG.
+ * Other compressed syntaxes (JPEG lossless, JPEG 2000) are refused as
+ * unsupported and have to be converted upstream. Multi-frame files (e.g.
+ * tomosynthesis) are subject to the multi-frame policy; their first frame
+ * is scored. The header's image laterality is used when the client sends
+ * none.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package preprocess
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// dicomMagic is the 128-byte preamble, which may hold anything, followed
+// by the DICOM prefix.
+var dicomMagic = strings.Repeat("?", 128) + "DICM"
+
+func init() {
+	image.RegisterFormat("dicom", dicomMagic, DecodeDICOM, DecodeDICOMConfig)
+}
+
+// Transfer syntax UIDs.
+const (
+	syntaxImplicitLE = "1.2.840.10008.1.2"
+	syntaxExplicitLE = "1.2.840.10008.1.2.1"
+	syntaxDeflatedLE = "1.2.840.10008.1.2.1.99"
+	syntaxExplicitBE = "1.2.840.10008.1.2.2"
+	syntaxJPEGBase   = "1.2.840.10008.1.2.4.50"
+)
+
+// Tags, as group<<16 | element.
+const (
+	tagTransferSyntax      = 0x00020010
+	tagLaterality          = 0x00200060
+	tagImageLaterality     = 0x00200062
+	tagSamplesPerPixel     = 0x00280002
+	tagPhotometric         = 0x00280004
+	tagPlanarConfig        = 0x00280006
+	tagNumberOfFrames      = 0x00280008
+	tagRows                = 0x00280010
+	tagColumns             = 0x00280011
+	tagBitsAllocated       = 0x00280100
+	tagBitsStored          = 0x00280101
+	tagPixelRepresentation = 0x00280103
+	tagWindowCenter        = 0x00281050
+	tagWindowWidth         = 0x00281051
+	tagRescaleIntercept    = 0x00281052
+	tagRescaleSlope        = 0x00281053
+	tagVOILUTFunction      = 0x00281056
+	tagLUTDescriptor       = 0x00283002
+	tagLUTData             = 0x00283006
+	tagVOILUTSequence      = 0x00283010
+	tagPixelData           = 0x7FE00010
+
+	tagItem          = 0xFFFEE000
+	tagItemDelim     = 0xFFFEE00D
+	tagSequenceDelim = 0xFFFEE0DD
+)
+
+// undefinedLength marks sequences and items closed by a delimiter.
+const undefinedLength = 0xFFFFFFFF
+
+// maxDICOMDepth bounds sequence nesting so a malicious file cannot
+// exhaust the stack.
+const maxDICOMDepth = 16
+
+// maxInflatedDICOM bounds a deflated data set so a small upload cannot
+// inflate into gigabytes.
+const maxInflatedDICOM = 512 << 20
+
+// dicomElement is a parsed data element.
+type dicomElement struct {
+	vr    string
+	value []byte
+	items []dicomDataset
+}
+
+// dicomDataset maps tags to elements.
+type dicomDataset map[uint32]dicomElement
+
+// dicomFile is a parsed DICOM file.
+type dicomFile struct {
+	syntax string
+	order  binary.ByteOrder
+	ds     dicomDataset
+	// pixels holds native pixel data; fragments the encapsulated
+	// (compressed) fragments.
+	pixels    []byte
+	fragments [][]byte
+}
+
+// DecodeDICOM decodes the first frame of a DICOM file into a display
+// image: 16-bit grey for monochrome files, RGBA for colour ones.
+func DecodeDICOM(r io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	f, err := parseDICOM(data, true)
+	if err != nil {
+		return nil, err
+	}
+	return f.image()
+}
+
+// DecodeDICOMConfig returns the dimensions and colour model of a DICOM
+// file without decoding its pixels.
+func DecodeDICOMConfig(r io.Reader) (image.Config, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return image.Config{}, err
+	}
+	f, err := parseDICOM(data, false)
+	if err != nil {
+		return image.Config{}, err
+	}
+	model := color.Gray16Model
+	if f.uint(tagSamplesPerPixel, 1) == 3 {
+		model = color.RGBAModel
+	}
+	return image.Config{ColorModel: model, Width: f.uint(tagColumns, 0), Height: f.uint(tagRows, 0)}, nil
+}
+
+// dicomFrames returns the number of frames of a DICOM file.
+func dicomFrames(data []byte) int {
+	f, err := parseDICOM(data, false)
+	if err != nil {
+		return 1
+	}
+	return max(f.uint(tagNumberOfFrames, 1), 1)
+}
+
+// dicomLaterality returns the laterality recorded in a DICOM header ("L"
+// or "R"), or "" if there is none.
+func dicomLaterality(data []byte) string {
+	f, err := parseDICOM(data, false)
+	if err != nil {
+		return ""
+	}
+	for _, tag := range []uint32{tagImageLaterality, tagLaterality} {
+		switch f.string(tag) {
+		case Left:
+			return Left
+		case Right:
+			return Right
+		}
+	}
+	return ""
+}
+
+// parseDICOM parses the file meta information and the data set, up to and
+// (with pixels set) including the pixel data.
+func parseDICOM(data []byte, pixels bool) (*dicomFile, error) {
+	if len(data) < 132 || string(data[128:132]) != "DICM" {
+		return nil, errors.New("dicom: missing DICM prefix")
+	}
+	// The file meta information is always explicit VR little endian.
+	meta := &dicomParser{data: data, pos: 132, order: binary.LittleEndian, explicit: true}
+	metaSet := make(dicomDataset)
+	for meta.pos+4 <= len(data) && meta.order.Uint16(data[meta.pos:]) == 0x0002 {
+		tag, el, err := meta.element(0)
+		if err != nil {
+			return nil, err
+		}
+		metaSet[tag] = el
+	}
+
+	f := &dicomFile{syntax: strings.TrimRight(string(metaSet[tagTransferSyntax].value), "\x00 ")}
+	p := &dicomParser{data: data, pos: meta.pos, order: binary.LittleEndian, explicit: true}
+	switch f.syntax {
+	case syntaxImplicitLE:
+		p.explicit = false
+	case syntaxExplicitLE, syntaxJPEGBase:
+	case syntaxExplicitBE:
+		p.order = binary.BigEndian
+	case syntaxDeflatedLE:
+		inflated, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(data[meta.pos:])), maxInflatedDICOM+1))
+		if err != nil {
+			return nil, fmt.Errorf("dicom: inflate data set: %w", err)
+		}
+		if len(inflated) > maxInflatedDICOM {
+			return nil, fmt.Errorf("dicom: deflated data set exceeds %d bytes", maxInflatedDICOM)
+		}
+		p.data, p.pos = inflated, 0
+	case "":
+		return nil, errors.New("dicom: no transfer syntax")
+	default:
+		return nil, fmt.Errorf("%w: DICOM transfer syntax %s (convert to uncompressed or baseline JPEG)", ErrUnsupportedFormat, f.syntax)
+	}
+	f.order = p.order
+
+	ds, err := p.dataset(len(p.data), 0, func(tag uint32, length uint32) (bool, error) {
+		if tag != tagPixelData {
+			return false, nil
+		}
+		if !pixels {
+			return true, nil
+		}
+		if length == undefinedLength {
+			frags, err := p.fragments()
+			f.fragments = frags
+			return true, err
+		}
+		if uint64(p.pos)+uint64(length) > uint64(len(p.data)) {
+			return true, errors.New("dicom: pixel data truncated")
+		}
+		f.pixels = p.data[p.pos : p.pos+int(length)]
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	f.ds = ds
+	return f, nil
+}
+
+// dicomParser reads data elements.
+type dicomParser struct {
+	data     []byte
+	pos      int
+	order    binary.ByteOrder
+	explicit bool
+}
+
+// longVRs have a 4-byte length in explicit VR encoding.
+var longVRs = map[string]bool{"OB": true, "OD": true, "OF": true, "OL": true, "OV": true, "OW": true, "SQ": true, "UC": true, "UN": true, "UR": true, "UT": true, "SV": true, "UV": true}
+
+// header reads an element header.
+func (p *dicomParser) header() (tag uint32, vr string, length uint32, err error) {
+	if p.pos+8 > len(p.data) {
+		return 0, "", 0, errors.New("dicom: truncated element")
+	}
+	d := p.data[p.pos:]
+	tag = uint32(p.order.Uint16(d))<<16 | uint32(p.order.Uint16(d[2:]))
+	// Items and delimiters are never VR-encoded.
+	if !p.explicit || tag>>16 == 0xFFFE {
+		p.pos += 8
+		return tag, "", p.order.Uint32(d[4:]), nil
+	}
+	vr = string(d[4:6])
+	if longVRs[vr] {
+		if p.pos+12 > len(p.data) {
+			return 0, "", 0, errors.New("dicom: truncated element")
+		}
+		p.pos += 12
+		return tag, vr, p.order.Uint32(d[8:]), nil
+	}
+	p.pos += 8
+	return tag, vr, uint32(p.order.Uint16(d[6:])), nil
+}
+
+// element reads one element, with its sequence items.
+func (p *dicomParser) element(depth int) (uint32, dicomElement, error) {
+	tag, vr, length, err := p.header()
+	if err != nil {
+		return 0, dicomElement{}, err
+	}
+	return p.body(tag, vr, length, depth)
+}
+
+// body reads the value of an element whose header was just read.
+func (p *dicomParser) body(tag uint32, vr string, length uint32, depth int) (uint32, dicomElement, error) {
+	el := dicomElement{vr: vr}
+	// Undefined lengths only occur on sequences (and pixel data, handled
+	// by the caller), whatever the VR says in implicit files.
+	if vr == "SQ" || length == undefinedLength || (!p.explicit && tag == tagVOILUTSequence) {
+		items, err := p.sequence(length, depth+1)
+		el.items = items
+		return tag, el, err
+	}
+	if uint64(p.pos)+uint64(length) > uint64(len(p.data)) {
+		return 0, dicomElement{}, fmt.Errorf("dicom: element (%04X,%04X) truncated", tag>>16, tag&0xFFFF)
+	}
+	el.value = p.data[p.pos : p.pos+int(length)]
+	p.pos += int(length)
+	return tag, el, nil
+}
+
+// sequence reads the items of a sequence.
+func (p *dicomParser) sequence(length uint32, depth int) ([]dicomDataset, error) {
+	if depth > maxDICOMDepth {
+		return nil, errors.New("dicom: sequences nested too deeply")
+	}
+	end := len(p.data)
+	if length != undefinedLength {
+		if uint64(p.pos)+uint64(length) > uint64(len(p.data)) {
+			return nil, errors.New("dicom: sequence truncated")
+		}
+		end = p.pos + int(length)
+	}
+	var items []dicomDataset
+	for p.pos < end {
+		tag, _, itemLength, err := p.header()
+		if err != nil {
+			return nil, err
+		}
+		switch tag {
+		case tagSequenceDelim:
+			return items, nil
+		case tagItem:
+		default:
+			return nil, fmt.Errorf("dicom: unexpected (%04X,%04X) in sequence", tag>>16, tag&0xFFFF)
+		}
+		itemEnd := end
+		if itemLength != undefinedLength {
+			if uint64(p.pos)+uint64(itemLength) > uint64(end) {
+				return nil, errors.New("dicom: item truncated")
+			}
+			itemEnd = p.pos + int(itemLength)
+		}
+		item, err := p.dataset(itemEnd, depth, nil)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// dataset reads elements until end or an item delimiter. stop, when set,
+// is offered every element header first; it reports true to end the data
+// set there, having consumed the element itself.
+func (p *dicomParser) dataset(end int, depth int, stop func(tag, length uint32) (bool, error)) (dicomDataset, error) {
+	ds := make(dicomDataset)
+	for p.pos < end {
+		tag, vr, length, err := p.header()
+		if err != nil {
+			return nil, err
+		}
+		if tag == tagItemDelim {
+			break
+		}
+		if stop != nil {
+			if done, err := stop(tag, length); done || err != nil {
+				return ds, err
+			}
+		}
+		_, el, err := p.body(tag, vr, length, depth)
+		if err != nil {
+			return nil, err
+		}
+		ds[tag] = el
+	}
+	return ds, nil
+}
+
+// fragments reads encapsulated pixel data: a basic offset table item,
+// then one item per fragment.
+func (p *dicomParser) fragments() ([][]byte, error) {
+	var frags [][]byte
+	for first := true; ; first = false {
+		tag, _, length, err := p.header()
+		if err != nil {
+			return nil, err
+		}
+		if tag == tagSequenceDelim {
+			return frags, nil
+		}
+		if tag != tagItem || length == undefinedLength || uint64(p.pos)+uint64(length) > uint64(len(p.data)) {
+			return nil, errors.New("dicom: invalid pixel data fragment")
+		}
+		if !first {
+			frags = append(frags, p.data[p.pos:p.pos+int(length)])
+		}
+		p.pos += int(length)
+	}
+}
+
+// string returns a text value, trimmed of padding; the first one when it
+// is multi-valued.
+func (f *dicomFile) string(tag uint32) string {
+	v, _, _ := strings.Cut(string(f.ds[tag].value), `\`)
+	return strings.TrimSpace(strings.TrimRight(v, "\x00"))
+}
+
+// uint returns an unsigned short value (US), or def.
+func (f *dicomFile) uint(tag uint32, def int) int {
+	el, ok := f.ds[tag]
+	if !ok {
+		return def
+	}
+	// Number of Frames is an integer string; the rest are US.
+	if tag == tagNumberOfFrames {
+		if n, err := strconv.Atoi(f.string(tag)); err == nil {
+			return n
+		}
+		return def
+	}
+	if len(el.value) < 2 {
+		return def
+	}
+	return int(f.order.Uint16(el.value))
+}
+
+// float returns a decimal string value (DS), or def.
+func (f *dicomFile) float(tag uint32, def float64) float64 {
+	if v, err := strconv.ParseFloat(f.string(tag), 64); err == nil {
+		return v
+	}
+	return def
+}
+
+// image decodes the first frame.
+func (f *dicomFile) image() (image.Image, error) {
+	rows, cols := f.uint(tagRows, 0), f.uint(tagColumns, 0)
+	if rows == 0 || cols == 0 {
+		return nil, errors.New("dicom: no image dimensions")
+	}
+	photometric := f.string(tagPhotometric)
+	samples := f.uint(tagSamplesPerPixel, 1)
+
+	if f.syntax == syntaxJPEGBase {
+		return f.decodeJPEG(photometric)
+	}
+	if f.pixels == nil {
+		return nil, errors.New("dicom: no pixel data")
+	}
+	switch {
+	case samples == 1 && (photometric == "MONOCHROME1" || photometric == "MONOCHROME2"):
+		values, err := f.monochrome(rows, cols)
+		if err != nil {
+			return nil, err
+		}
+		return f.display(values, cols, rows, photometric == "MONOCHROME1"), nil
+	case samples == 3 && photometric == "RGB" && f.uint(tagBitsAllocated, 0) == 8:
+		return f.rgb(rows, cols)
+	}
+	return nil, fmt.Errorf("%w: DICOM photometric interpretation %q with %d sample(s)", ErrUnsupportedFormat, photometric, samples)
+}
+
+// monochrome reads the stored values of the first frame and applies the
+// modality LUT.
+func (f *dicomFile) monochrome(rows, cols int) ([]float64, error) {
+	allocated := f.uint(tagBitsAllocated, 0)
+	stored := f.uint(tagBitsStored, allocated)
+	signed := f.uint(tagPixelRepresentation, 0) == 1
+	if (allocated != 8 && allocated != 16) || stored <= 0 || stored > allocated {
+		return nil, fmt.Errorf("%w: DICOM with %d of %d bits allocated", ErrUnsupportedFormat, stored, allocated)
+	}
+	n := rows * cols
+	if len(f.pixels) < n*allocated/8 {
+		return nil, errors.New("dicom: pixel data shorter than one frame")
+	}
+	slope := f.float(tagRescaleSlope, 1)
+	intercept := f.float(tagRescaleIntercept, 0)
+	mask := uint32(1)<<stored - 1
+	signBit := uint32(1) << (stored - 1)
+
+	values := make([]float64, n)
+	for i := range values {
+		var raw uint32
+		if allocated == 8 {
+			raw = uint32(f.pixels[i])
+		} else {
+			raw = uint32(f.order.Uint16(f.pixels[2*i:]))
+		}
+		raw &= mask
+		v := float64(raw)
+		if signed && raw&signBit != 0 {
+			v -= float64(mask) + 1
+		}
+		values[i] = v*slope + intercept
+	}
+	return values, nil
+}
+
+// display applies the VOI LUT to modality values and returns the display
+// image.
+func (f *dicomFile) display(values []float64, width, height int, invert bool) *image.Gray16 {
+	voi := f.voi(values)
+	img := image.NewGray16(image.Rect(0, 0, width, height))
+	for i, v := range values {
+		y := voi(v)
+		if invert {
+			y = 1 - y
+		}
+		g := uint16(math.Round(min(max(y, 0), 1) * 0xFFFF))
+		img.Pix[2*i], img.Pix[2*i+1] = uint8(g>>8), uint8(g)
+	}
+	return img
+}
+
+// voi returns the VOI transformation into [0, 1]: the header's VOI LUT,
+// else its window, else the full range of the values.
+func (f *dicomFile) voi(values []float64) func(float64) float64 {
+	if lut := f.voiLUT(); lut != nil {
+		return lut
+	}
+	center, width := f.float(tagWindowCenter, math.NaN()), f.float(tagWindowWidth, math.NaN())
+	if !math.IsNaN(center) && !math.IsNaN(width) && width > 0 {
+		switch f.string(tagVOILUTFunction) {
+		case "SIGMOID":
+			return func(x float64) float64 { return 1 / (1 + math.Exp(-4*(x-center)/width)) }
+		case "LINEAR_EXACT":
+			return func(x float64) float64 { return (x-center)/width + 0.5 }
+		default:
+			// PS3.3 C.11.2.1.2.1; the function is undefined below a
+			// width of 1.
+			width = max(width, 1)
+			return func(x float64) float64 {
+				if width == 1 {
+					if x <= center-0.5 {
+						return 0
+					}
+					return 1
+				}
+				return (x-(center-0.5))/(width-1) + 0.5
+			}
+		}
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	if hi <= lo {
+		return func(float64) float64 { return 0 }
+	}
+	return func(x float64) float64 { return (x - lo) / (hi - lo) }
+}
+
+// voiLUT returns the first VOI LUT of the header as a transformation into
+// [0, 1], or nil.
+func (f *dicomFile) voiLUT() func(float64) float64 {
+	seq := f.ds[tagVOILUTSequence].items
+	if len(seq) == 0 {
+		return nil
+	}
+	item := &dicomFile{order: f.order, ds: seq[0]}
+	desc, data := item.ds[tagLUTDescriptor].value, item.ds[tagLUTData].value
+	if len(desc) < 6 || len(data) < 2 {
+		return nil
+	}
+	entries := int(f.order.Uint16(desc))
+	if entries == 0 {
+		entries = 1 << 16
+	}
+	// The first mapped value is signed when the pixels are.
+	first := float64(f.order.Uint16(desc[2:]))
+	if f.uint(tagPixelRepresentation, 0) == 1 {
+		first = float64(int16(f.order.Uint16(desc[2:])))
+	}
+	bits := int(f.order.Uint16(desc[4:]))
+	if bits <= 0 || bits > 16 {
+		return nil
+	}
+	entries = min(entries, len(data)/2)
+	lut := make([]float64, entries)
+	top := float64(uint32(1)<<bits - 1)
+	for i := range lut {
+		lut[i] = float64(f.order.Uint16(data[2*i:])) / top
+	}
+	return func(x float64) float64 {
+		i := int(math.Round(x - first))
+		return lut[min(max(i, 0), len(lut)-1)]
+	}
+}
+
+// rgb reads an 8-bit colour frame.
+func (f *dicomFile) rgb(rows, cols int) (image.Image, error) {
+	n := rows * cols
+	if len(f.pixels) < 3*n {
+		return nil, errors.New("dicom: pixel data shorter than one frame")
+	}
+	planar := f.uint(tagPlanarConfig, 0) == 1
+	img := image.NewRGBA(image.Rect(0, 0, cols, rows))
+	for i := range n {
+		for c := range 3 {
+			if planar {
+				img.Pix[4*i+c] = f.pixels[c*n+i]
+			} else {
+				img.Pix[4*i+c] = f.pixels[3*i+c]
+			}
+		}
+		img.Pix[4*i+3] = 0xFF
+	}
+	return img, nil
+}
+
+// decodeJPEG decodes the first frame of baseline JPEG pixel data. Grey
+// frames go through the VOI LUT like native ones.
+func (f *dicomFile) decodeJPEG(photometric string) (image.Image, error) {
+	if len(f.fragments) == 0 {
+		return nil, errors.New("dicom: no pixel data")
+	}
+	// A single frame may span several fragments; with several frames,
+	// each frame starts a fragment.
+	frame := f.fragments[0]
+	if f.uint(tagNumberOfFrames, 1) <= 1 {
+		frame = bytes.Join(f.fragments, nil)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(frame))
+	if err != nil {
+		return nil, fmt.Errorf("dicom: %w", err)
+	}
+	gray, ok := img.(*image.Gray)
+	if !ok {
+		return img, nil
+	}
+	b := gray.Bounds()
+	values := make([]float64, 0, b.Dx()*b.Dy())
+	slope, intercept := f.float(tagRescaleSlope, 1), f.float(tagRescaleIntercept, 0)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for _, v := range gray.Pix[(y-b.Min.Y)*gray.Stride : (y-b.Min.Y)*gray.Stride+b.Dx()] {
+			values = append(values, float64(v)*slope+intercept)
+		}
+	}
+	return f.display(values, b.Dx(), b.Dy(), photometric == "MONOCHROME1"), nil
+}
@@ -0,0 +1,370 @@
+package preprocess
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"image"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// dicomBuilder encodes data elements in one transfer syntax.
+type dicomBuilder struct {
+	order    binary.AppendByteOrder
+	explicit bool
+	buf      []byte
+}
+
+func newDICOMBuilder(syntax string) *dicomBuilder {
+	switch syntax {
+	case syntaxImplicitLE:
+		return &dicomBuilder{order: binary.LittleEndian}
+	case syntaxExplicitBE:
+		return &dicomBuilder{order: binary.BigEndian, explicit: true}
+	}
+	return &dicomBuilder{order: binary.LittleEndian, explicit: true}
+}
+
+// header writes an element header; items and delimiters take no VR.
+func (b *dicomBuilder) header(tag uint32, vr string, length uint32) *dicomBuilder {
+	b.buf = b.order.AppendUint16(b.buf, uint16(tag>>16))
+	b.buf = b.order.AppendUint16(b.buf, uint16(tag))
+	if !b.explicit || tag>>16 == 0xFFFE {
+		b.buf = b.order.AppendUint32(b.buf, length)
+		return b
+	}
+	b.buf = append(b.buf, vr...)
+	if longVRs[vr] {
+		b.buf = append(b.buf, 0, 0)
+		b.buf = b.order.AppendUint32(b.buf, length)
+	} else {
+		b.buf = b.order.AppendUint16(b.buf, uint16(length))
+	}
+	return b
+}
+
+func (b *dicomBuilder) el(tag uint32, vr string, value []byte) *dicomBuilder {
+	if len(value)%2 == 1 {
+		value = append(slices.Clone(value), ' ')
+	}
+	b.header(tag, vr, uint32(len(value)))
+	b.buf = append(b.buf, value...)
+	return b
+}
+
+func (b *dicomBuilder) str(tag uint32, vr, value string) *dicomBuilder {
+	return b.el(tag, vr, []byte(value))
+}
+
+func (b *dicomBuilder) us(tag uint32, v uint16) *dicomBuilder {
+	return b.el(tag, "US", b.order.AppendUint16(nil, v))
+}
+
+func (b *dicomBuilder) words(vs ...uint16) []byte {
+	var out []byte
+	for _, v := range vs {
+		out = b.order.AppendUint16(out, v)
+	}
+	return out
+}
+
+// seq writes a sequence of undefined length holding items.
+func (b *dicomBuilder) seq(tag uint32, items ...func(*dicomBuilder)) *dicomBuilder {
+	b.header(tag, "SQ", undefinedLength)
+	for _, item := range items {
+		b.header(tagItem, "", undefinedLength)
+		item(b)
+		b.header(tagItemDelim, "", 0)
+	}
+	return b.header(tagSequenceDelim, "", 0)
+}
+
+// dicomBytes wraps a data set into a file with the given transfer syntax.
+func dicomBytes(syntax string, dataset []byte) []byte {
+	meta := newDICOMBuilder(syntaxExplicitLE)
+	if syntax != "" {
+		meta.str(tagTransferSyntax, "UI", syntax+"\x00")
+	}
+	return slices.Concat(make([]byte, 128), []byte("DICM"), meta.buf, dataset)
+}
+
+// monoDataset returns a 2x2 MONOCHROME2 16-bit data set with the given
+// pixels, after the extra elements.
+func monoDataset(syntax string, pixels []uint16, extra func(*dicomBuilder)) []byte {
+	b := newDICOMBuilder(syntax)
+	b.us(tagSamplesPerPixel, 1).str(tagPhotometric, "CS", "MONOCHROME2")
+	b.us(tagRows, 2).us(tagColumns, 2).us(tagBitsAllocated, 16).us(tagBitsStored, 16)
+	if extra != nil {
+		extra(b)
+	}
+	return b.el(tagPixelData, "OW", b.words(pixels...)).buf
+}
+
+func decodeGray16(t *testing.T, data []byte) []uint16 {
+	t.Helper()
+	img, err := DecodeDICOM(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("DecodeDICOM: %v", err)
+	}
+	gray, ok := img.(*image.Gray16)
+	if !ok {
+		t.Fatalf("DecodeDICOM returned %T, want *image.Gray16", img)
+	}
+	out := make([]uint16, len(gray.Pix)/2)
+	for i := range out {
+		out[i] = binary.BigEndian.Uint16(gray.Pix[2*i:])
+	}
+	return out
+}
+
+func TestDecodeDICOMVOI(t *testing.T) {
+	window := func(fn string) func(*dicomBuilder) {
+		return func(b *dicomBuilder) {
+			b.str(tagWindowCenter, "DS", "1000").str(tagWindowWidth, "DS", "2000")
+			if fn != "" {
+				b.str(tagVOILUTFunction, "CS", fn)
+			}
+		}
+	}
+	cases := []struct {
+		name   string
+		pixels []uint16
+		extra  func(*dicomBuilder)
+		want   []uint16
+	}{
+		{"full range", []uint16{0, 1, 2, 3}, nil, []uint16{0, 21845, 43690, 65535}},
+		{"linear exact window", []uint16{0, 1000, 2000, 4000}, window("LINEAR_EXACT"), []uint16{0, 32768, 65535, 65535}},
+		{"linear window clips", []uint16{0, 1000, 4000, 0}, window(""), []uint16{0, 32784, 65535, 0}},
+		{"sigmoid window", []uint16{1000, 1000, 1000, 1000}, window("SIGMOID"), []uint16{32768, 32768, 32768, 32768}},
+		{"rescale", []uint16{0, 1, 2, 3}, func(b *dicomBuilder) {
+			b.str(tagRescaleSlope, "DS", "2").str(tagRescaleIntercept, "DS", "-1024")
+			b.str(tagWindowCenter, "DS", "-1022").str(tagWindowWidth, "DS", "4").str(tagVOILUTFunction, "CS", "LINEAR_EXACT")
+		}, []uint16{0, 32768, 65535, 65535}},
+		{"VOI LUT", []uint16{0, 1, 2, 9}, func(b *dicomBuilder) {
+			b.str(tagWindowCenter, "DS", "1").str(tagWindowWidth, "DS", "1")
+			b.seq(tagVOILUTSequence, func(b *dicomBuilder) {
+				b.el(tagLUTDescriptor, "US", b.words(3, 0, 16)).el(tagLUTData, "OW", b.words(100, 200, 65535))
+			})
+		}, []uint16{100, 200, 65535, 65535}},
+		{"flat image", []uint16{7, 7, 7, 7}, nil, []uint16{0, 0, 0, 0}},
+	}
+	for _, tc := range cases {
+		got := decodeGray16(t, dicomBytes(syntaxExplicitLE, monoDataset(syntaxExplicitLE, tc.pixels, tc.extra)))
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%s: pixels = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestDecodeDICOMSignedMonochrome1(t *testing.T) {
+	// 12 of 16 bits, signed: 0x800 is -2048 and 0xFFF is -1. Bits above
+	// the stored ones are ignored.
+	ds := newDICOMBuilder(syntaxExplicitLE)
+	ds.us(tagSamplesPerPixel, 1).str(tagPhotometric, "CS", "MONOCHROME1")
+	ds.us(tagRows, 1).us(tagColumns, 4).us(tagBitsAllocated, 16).us(tagBitsStored, 12).us(tagPixelRepresentation, 1)
+	ds.el(tagPixelData, "OW", ds.words(0x800, 0xFFFF, 0x0000, 0x07FF))
+	got := decodeGray16(t, dicomBytes(syntaxExplicitLE, ds.buf))
+
+	values := []float64{-2048, -1, 0, 2047}
+	want := make([]uint16, len(values))
+	for i, v := range values {
+		// Inverted: the lowest value is white.
+		want[i] = uint16(math.Round((1 - (v+2048)/4095) * 0xFFFF))
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("pixels = %v, want %v", got, want)
+	}
+}
+
+func TestDecodeDICOMTransferSyntaxes(t *testing.T) {
+	pixels := []uint16{10, 400, 3000, 4095}
+	extra := func(b *dicomBuilder) {
+		b.str(tagImageLaterality, "CS", "R")
+		b.seq(0x00081140, func(b *dicomBuilder) { b.str(0x00081150, "UI", "1.2.3") })
+	}
+	want := decodeGray16(t, dicomBytes(syntaxExplicitLE, monoDataset(syntaxExplicitLE, pixels, extra)))
+
+	var deflated bytes.Buffer
+	w, _ := flate.NewWriter(&deflated, flate.BestCompression)
+	w.Write(monoDataset(syntaxExplicitLE, pixels, extra))
+	w.Close()
+	files := map[string][]byte{
+		"implicit LE": dicomBytes(syntaxImplicitLE, monoDataset(syntaxImplicitLE, pixels, extra)),
+		"explicit BE": dicomBytes(syntaxExplicitBE, monoDataset(syntaxExplicitBE, pixels, extra)),
+		"deflated":    dicomBytes(syntaxDeflatedLE, deflated.Bytes()),
+	}
+	for name, data := range files {
+		if got := decodeGray16(t, data); !slices.Equal(got, want) {
+			t.Errorf("%s: pixels = %v, want %v", name, got, want)
+		}
+		if got := dicomLaterality(data); got != Right {
+			t.Errorf("%s: laterality = %q, want R", name, got)
+		}
+	}
+
+	img, err := DecodeImageBytes(files["implicit LE"], Options{Color: ConvertICC})
+	if err != nil {
+		t.Fatalf("DecodeImageBytes: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 2 || b.Dy() != 2 {
+		t.Errorf("decoded bounds = %v", b)
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(files["explicit BE"]))
+	if err != nil || format != "dicom" || cfg.Width != 2 || cfg.Height != 2 {
+		t.Errorf("DecodeConfig = %+v, %q, %v", cfg, format, err)
+	}
+}
+
+func TestDecodeDICOMColour(t *testing.T) {
+	for _, planar := range []bool{false, true} {
+		ds := newDICOMBuilder(syntaxExplicitLE)
+		ds.us(tagSamplesPerPixel, 3).str(tagPhotometric, "CS", "RGB")
+		ds.us(tagRows, 1).us(tagColumns, 2).us(tagBitsAllocated, 8)
+		pixels := []byte{1, 2, 3, 4, 5, 6}
+		if planar {
+			ds.us(tagPlanarConfig, 1)
+			pixels = []byte{1, 4, 2, 5, 3, 6}
+		}
+		ds.el(tagPixelData, "OB", pixels)
+		img, err := DecodeDICOM(bytes.NewReader(dicomBytes(syntaxExplicitLE, ds.buf)))
+		if err != nil {
+			t.Fatalf("planar %v: %v", planar, err)
+		}
+		rgba := img.(*image.RGBA)
+		if want := []byte{1, 2, 3, 255, 4, 5, 6, 255}; !bytes.Equal(rgba.Pix, want) {
+			t.Errorf("planar %v: pixels = %v, want %v", planar, rgba.Pix, want)
+		}
+	}
+}
+
+func TestDecodeDICOMRejectsMalformedFiles(t *testing.T) {
+	valid := monoDataset(syntaxExplicitLE, []uint16{1, 2, 3, 4}, nil)
+	mono := func(extra func(*dicomBuilder)) []byte {
+		return dicomBytes(syntaxExplicitLE, monoDataset(syntaxExplicitLE, []uint16{1, 2, 3, 4}, extra))
+	}
+	nested := func(depth int) []byte {
+		b := newDICOMBuilder(syntaxExplicitLE)
+		var item func(*dicomBuilder)
+		level := 0
+		item = func(b *dicomBuilder) {
+			if level++; level < depth {
+				b.seq(tagVOILUTSequence, item)
+			}
+		}
+		b.seq(tagVOILUTSequence, item)
+		return dicomBytes(syntaxExplicitLE, slices.Concat(b.buf, valid))
+	}
+	withPixels := func(pixels []byte, header func(*dicomBuilder)) []byte {
+		b := newDICOMBuilder(syntaxExplicitLE)
+		b.us(tagSamplesPerPixel, 1).str(tagPhotometric, "CS", "MONOCHROME2").us(tagRows, 2).us(tagColumns, 2)
+		header(b)
+		b.buf = append(b.buf, pixels...)
+		return dicomBytes(syntaxExplicitLE, b.buf)
+	}
+	bits16 := func(b *dicomBuilder) { b.us(tagBitsAllocated, 16) }
+	badSequence := newDICOMBuilder(syntaxExplicitLE)
+	badSequence.header(tagVOILUTSequence, "SQ", undefinedLength).header(tagRows, "", 0)
+
+	cases := []struct {
+		name        string
+		data        []byte
+		unsupported bool
+	}{
+		{"empty", nil, false},
+		{"no prefix", slices.Concat(make([]byte, 128), []byte("DICX"), valid), false},
+		{"no transfer syntax", dicomBytes("", valid), false},
+		{"JPEG 2000", dicomBytes("1.2.840.10008.1.2.4.90", valid), true},
+		{"JPEG lossless", dicomBytes("1.2.840.10008.1.2.4.70", valid), true},
+		{"not deflated", dicomBytes(syntaxDeflatedLE, valid), false},
+		{"truncated element", dicomBytes(syntaxExplicitLE, valid[:len(valid)-20]), false},
+		{"truncated header", dicomBytes(syntaxExplicitLE, valid[:5]), false},
+		{"truncated pixel data", withPixels(newDICOMBuilder(syntaxExplicitLE).header(tagPixelData, "OW", 100).buf, bits16), false},
+		{"short frame", withPixels(newDICOMBuilder(syntaxExplicitLE).el(tagPixelData, "OW", []byte{1, 2}).buf, bits16), false},
+		{"no pixel data", dicomBytes(syntaxExplicitLE, valid[:len(valid)-16]), false},
+		{"no dimensions", dicomBytes(syntaxExplicitLE, newDICOMBuilder(syntaxExplicitLE).el(tagPixelData, "OW", make([]byte, 8)).buf), false},
+		{"12 bits allocated", mono(func(b *dicomBuilder) { b.us(tagBitsAllocated, 12) }), true},
+		{"more bits stored than allocated", mono(func(b *dicomBuilder) { b.us(tagBitsAllocated, 8).us(tagBitsStored, 12) }), true},
+		{"palette colour", mono(func(b *dicomBuilder) { b.str(tagPhotometric, "CS", "PALETTE COLOR") }), true},
+		{"16-bit RGB", mono(func(b *dicomBuilder) { b.us(tagSamplesPerPixel, 3).str(tagPhotometric, "CS", "RGB") }), true},
+		{"nested too deeply", nested(maxDICOMDepth + 2), false},
+		{"foreign element in sequence", dicomBytes(syntaxExplicitLE, badSequence.buf), false},
+		{"item longer than sequence", dicomBytes(syntaxExplicitLE, newDICOMBuilder(syntaxExplicitLE).header(tagVOILUTSequence, "SQ", 8).header(tagItem, "", 100).buf), false},
+		{"bad fragment", dicomBytes(syntaxJPEGBase, slices.Concat(
+			newDICOMBuilder(syntaxExplicitLE).us(tagRows, 2).us(tagColumns, 2).header(tagPixelData, "OB", undefinedLength).header(tagItem, "", 0).header(tagRows, "", 4).buf,
+			[]byte{0, 0, 0, 0})), false},
+		{"no fragments", dicomBytes(syntaxJPEGBase, newDICOMBuilder(syntaxExplicitLE).us(tagRows, 2).us(tagColumns, 2).
+			header(tagPixelData, "OB", undefinedLength).header(tagItem, "", 0).header(tagSequenceDelim, "", 0).buf), false},
+		{"corrupt JPEG", dicomBytes(syntaxJPEGBase, newDICOMBuilder(syntaxExplicitLE).us(tagRows, 2).us(tagColumns, 2).
+			header(tagPixelData, "OB", undefinedLength).header(tagItem, "", 0).el(tagItem, "", []byte("not a jpeg")).header(tagSequenceDelim, "", 0).buf), false},
+	}
+	for _, tc := range cases {
+		_, err := DecodeDICOM(bytes.NewReader(tc.data))
+		if err == nil {
+			t.Errorf("%s: DecodeDICOM succeeded", tc.name)
+			continue
+		}
+		if got := errors.Is(err, ErrUnsupportedFormat); got != tc.unsupported {
+			t.Errorf("%s: error %q, unsupported format = %v, want %v", tc.name, err, got, tc.unsupported)
+		}
+	}
+}
+
+func TestDecodeDICOMInflateLimit(t *testing.T) {
+	var bomb bytes.Buffer
+	w, _ := flate.NewWriter(&bomb, flate.BestCompression)
+	zeros := make([]byte, 1<<20)
+	for range maxInflatedDICOM/len(zeros) + 1 {
+		w.Write(zeros)
+	}
+	w.Close()
+	_, err := DecodeDICOM(bytes.NewReader(dicomBytes(syntaxDeflatedLE, bomb.Bytes())))
+	if err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Errorf("DecodeDICOM of a deflate bomb: error = %v, want size limit", err)
+	}
+}
+
+func TestDecodeDICOMTruncatedAnywhere(t *testing.T) {
+	ds := monoDataset(syntaxExplicitLE, []uint16{1, 2, 3, 4}, func(b *dicomBuilder) {
+		b.seq(tagVOILUTSequence, func(b *dicomBuilder) {
+			b.el(tagLUTDescriptor, "US", b.words(4, 0, 16)).el(tagLUTData, "OW", b.words(1, 2, 3, 4))
+		})
+	})
+	data := dicomBytes(syntaxExplicitLE, ds)
+	// Every prefix of a valid file decodes or fails; none panics.
+	for n := range len(data) {
+		DecodeDICOM(bytes.NewReader(data[:n]))
+		dicomFrames(data[:n])
+		dicomLaterality(data[:n])
+	}
+	if _, err := DecodeDICOM(bytes.NewReader(data)); err != nil {
+		t.Errorf("DecodeDICOM of the whole file: %v", err)
+	}
+}
+
+func TestDICOMFrames(t *testing.T) {
+	multi := func(frames string) []byte {
+		return dicomBytes(syntaxExplicitLE, monoDataset(syntaxExplicitLE, []uint16{1, 2, 3, 4, 5, 6, 7, 8}, func(b *dicomBuilder) {
+			b.str(tagNumberOfFrames, "IS", frames)
+		}))
+	}
+	for frames, want := range map[string]int{"2": 2, "1": 1, "0": 1, "many": 1} {
+		if got := dicomFrames(multi(frames)); got != want {
+			t.Errorf("frames %q: dicomFrames = %d, want %d", frames, got, want)
+		}
+	}
+	if got := dicomFrames([]byte("not dicom")); got != 1 {
+		t.Errorf("dicomFrames(not dicom) = %d, want 1", got)
+	}
+
+	opts := DefaultOptions()
+	if _, err := DecodeImageBytes(multi("2"), opts); !errors.Is(err, ErrMultiFrame) {
+		t.Errorf("multi-frame DICOM under the reject policy: error = %v, want ErrMultiFrame", err)
+	}
+	if !strings.HasPrefix(dicomMagic, strings.Repeat("?", 128)) {
+		t.Error("DICOM magic does not skip the preamble")
+	}
+}
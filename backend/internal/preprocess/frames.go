@@ -0,0 +1,104 @@
+// backend/internal/preprocess/frames.go
+/*
+ * This file detects multi-frame inputs (animated GIFs, multi-page TIFFs,
+ * multi-frame DICOM).
+ *
+ * A mammogram is a single still image. When a client uploads something with
+ * several frames we must not guess silently: by default the upload is
+ * rejected with ErrMultiFrame, or — if the deployment opts in — only the
+ * first frame is scored.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package preprocess
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image/gif"
+)
+
+// ErrMultiFrame is returned when an upload contains more than one frame
+// and the MultiFrame policy is RejectMultiFrame.
+var ErrMultiFrame = errors.New("multi-frame image")
+
+// MultiFramePolicy decides what to do with multi-frame uploads.
+type MultiFramePolicy string
+
+const (
+	// RejectMultiFrame refuses multi-frame uploads (the default).
+	RejectMultiFrame MultiFramePolicy = "reject"
+	// FirstFrame scores only the first frame/page.
+	FirstFrame MultiFramePolicy = "first"
+)
+
+// maxTIFFPages bounds the IFD walk so a malicious offset loop terminates.
+const maxTIFFPages = 1024
+
+// checkFrames enforces the multi-frame policy for formats that can carry
+// several frames. Single-frame formats are always accepted.
+func checkFrames(data []byte, format string, policy MultiFramePolicy) error {
+	if policy == FirstFrame {
+		return nil
+	}
+
+	var frames int
+	switch format {
+	case "gif":
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrDecode, err)
+		}
+		frames = len(g.Image)
+	case "tiff":
+		frames = countTIFFPages(data)
+	case "dicom":
+		frames = dicomFrames(data)
+	default:
+		return nil
+	}
+
+	if frames > 1 {
+		return fmt.Errorf("%w: %s with %d frames; upload a single still image", ErrMultiFrame, format, frames)
+	}
+	return nil
+}
+
+// countTIFFPages walks the chain of Image File Directories (one per page)
+// without decoding any pixel data.
+func countTIFFPages(data []byte) int {
+	if len(data) < 8 {
+		return 0
+	}
+
+	var order binary.ByteOrder
+	switch string(data[:4]) {
+	case "II*\x00":
+		order = binary.LittleEndian
+	case "MM\x00*":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	pages := 0
+	offset := order.Uint32(data[4:8])
+	for offset != 0 && pages < maxTIFFPages {
+		if int(offset)+2 > len(data) {
+			break
+		}
+		entries := int(order.Uint16(data[offset : offset+2]))
+		next := int(offset) + 2 + entries*12
+		if next+4 > len(data) {
+			break
+		}
+		pages++
+		offset = order.Uint32(data[next : next+4])
+	}
+	return pages
+}
@@ -0,0 +1,32 @@
+// backend/internal/preprocess/heic.go
+/*
+ * This file registers HEIC/HEIF decoding with the standard `image` package.
+ *
+ * iPhones save photos as HEIC by default, so clinicians photographing films
+ * would otherwise hit "failed to decode image". The decoder is libheif
+ * compiled to WebAssembly and executed by a pure-Go runtime, so it works in
+ * our CGO_ENABLED=0 builds without any system libraries.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package preprocess
+
+import (
+	"image"
+
+	"github.com/gen2brain/heic"
+)
+
+// heifBrands lists the ISO-BMFF `ftyp` brands used by HEIC/HEIF stills.
+// The decoder package only registers the "heic" brand itself; devices in
+// the wild also emit these variants for the same container format.
+var heifBrands = []string{"heix", "hevc", "hevx", "heim", "heis", "mif1", "msf1"}
+
+func init() {
+	for _, brand := range heifBrands {
+		image.RegisterFormat("heic", "????ftyp"+brand, heic.Decode, heic.DecodeConfig)
+	}
+}
@@ -0,0 +1,406 @@
+// backend/internal/preprocess/icc.go
+/*
+ * This file handles embedded ICC colour profiles.
+ *
+ * Go's JPEG and PNG decoders return the stored pixel values and ignore any
+ * embedded ICC profile. Two uploads that look identical on screen (say, an
+ * sRGB export and a Display P3 export of the same film) therefore reach the
+ * model as different tensors. We extract the profile (JPEG APP2 or PNG
+ * iCCP) and, for matrix/TRC profiles — which covers virtually every RGB and
+ * grayscale profile seen in practice — convert the pixels to sRGB, the
+ * colour space our training images were in. LUT-based profiles are left
+ * untouched, exactly as if the profile had been stripped.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package preprocess
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"sort"
+)
+
+// ColorPolicy decides how embedded ICC profiles are treated.
+type ColorPolicy string
+
+const (
+	// ConvertICC converts pixels from the embedded profile to sRGB.
+	ConvertICC ColorPolicy = "convert"
+	// StripICC ignores embedded profiles and uses the raw pixel values.
+	StripICC ColorPolicy = "strip"
+)
+
+var errUnsupportedProfile = errors.New("unsupported ICC profile")
+
+// --- Profile extraction ---
+
+// extractICC returns the raw ICC profile embedded in a JPEG or PNG file,
+// or nil if there is none.
+func extractICC(data []byte, format string) []byte {
+	switch format {
+	case "jpeg":
+		return extractJPEGICC(data)
+	case "png":
+		return extractPNGICC(data)
+	}
+	return nil
+}
+
+// extractJPEGICC reassembles the profile from its APP2 "ICC_PROFILE"
+// segments, which may be split across several markers.
+func extractJPEGICC(data []byte) []byte {
+	const sig = "ICC_PROFILE\x00"
+	type chunk struct {
+		seq  byte
+		data []byte
+	}
+	var chunks []chunk
+
+	i := 2 // skip SOI
+	for i+4 <= len(data) && data[i] == 0xFF {
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 { // start of scan / end of image
+			break
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if length < 2 || i+2+length > len(data) {
+			break
+		}
+		seg := data[i+4 : i+2+length]
+		if marker == 0xE2 && len(seg) > len(sig)+2 && string(seg[:len(sig)]) == sig {
+			chunks = append(chunks, chunk{seq: seg[len(sig)], data: seg[len(sig)+2:]})
+		}
+		i += 2 + length
+	}
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	sort.Slice(chunks, func(a, b int) bool { return chunks[a].seq < chunks[b].seq })
+	var profile []byte
+	for _, c := range chunks {
+		profile = append(profile, c.data...)
+	}
+	return profile
+}
+
+// extractPNGICC decompresses the profile stored in the iCCP chunk.
+func extractPNGICC(data []byte) []byte {
+	i := 8 // skip PNG signature
+	for i+8 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[i : i+4]))
+		typ := string(data[i+4 : i+8])
+		if length < 0 || i+12+length > len(data) {
+			return nil
+		}
+		body := data[i+8 : i+8+length]
+		switch typ {
+		case "iCCP":
+			// Profile name, NUL, compression method (always zlib), profile.
+			nul := bytes.IndexByte(body, 0)
+			if nul < 0 || nul+2 > len(body) {
+				return nil
+			}
+			zr, err := zlib.NewReader(bytes.NewReader(body[nul+2:]))
+			if err != nil {
+				return nil
+			}
+			defer zr.Close()
+			profile, err := io.ReadAll(io.LimitReader(zr, 4<<20))
+			if err != nil {
+				return nil
+			}
+			return profile
+		case "IDAT", "IEND":
+			return nil
+		}
+		i += 12 + length
+	}
+	return nil
+}
+
+// --- Profile parsing ---
+
+// toneCurve maps an encoded channel value in [0, 1] to linear light.
+type toneCurve func(float64) float64
+
+// iccProfile is the subset of a matrix/TRC profile we need.
+type iccProfile struct {
+	gray   bool
+	matrix [3][3]float64 // columns are the red, green and blue XYZ (D50)
+	trc    [3]toneCurve
+}
+
+func parseICC(p []byte) (*iccProfile, error) {
+	if len(p) < 132 || string(p[36:40]) != "acsp" {
+		return nil, errUnsupportedProfile
+	}
+	space := string(p[16:20])
+	if string(p[20:24]) != "XYZ " {
+		return nil, errUnsupportedProfile
+	}
+
+	tags := map[string][]byte{}
+	count := int(binary.BigEndian.Uint32(p[128:132]))
+	for i := 0; i < count; i++ {
+		off := 132 + i*12
+		if off+12 > len(p) {
+			return nil, errUnsupportedProfile
+		}
+		sig := string(p[off : off+4])
+		start := int(binary.BigEndian.Uint32(p[off+4 : off+8]))
+		size := int(binary.BigEndian.Uint32(p[off+8 : off+12]))
+		if start < 0 || size < 0 || start+size > len(p) {
+			return nil, errUnsupportedProfile
+		}
+		tags[sig] = p[start : start+size]
+	}
+
+	prof := &iccProfile{}
+	switch space {
+	case "GRAY":
+		curve, err := parseCurve(tags["kTRC"])
+		if err != nil {
+			return nil, err
+		}
+		prof.gray = true
+		prof.trc = [3]toneCurve{curve, curve, curve}
+	case "RGB ":
+		for col, name := range []string{"rXYZ", "gXYZ", "bXYZ"} {
+			xyz, err := parseXYZ(tags[name])
+			if err != nil {
+				return nil, err
+			}
+			for row := 0; row < 3; row++ {
+				prof.matrix[row][col] = xyz[row]
+			}
+		}
+		for ch, name := range []string{"rTRC", "gTRC", "bTRC"} {
+			curve, err := parseCurve(tags[name])
+			if err != nil {
+				return nil, err
+			}
+			prof.trc[ch] = curve
+		}
+	default:
+		return nil, errUnsupportedProfile
+	}
+	return prof, nil
+}
+
+func s15Fixed16(b []byte) float64 {
+	return float64(int32(binary.BigEndian.Uint32(b))) / 65536
+}
+
+func parseXYZ(tag []byte) ([3]float64, error) {
+	if len(tag) < 20 || string(tag[:4]) != "XYZ " {
+		return [3]float64{}, errUnsupportedProfile
+	}
+	return [3]float64{s15Fixed16(tag[8:]), s15Fixed16(tag[12:]), s15Fixed16(tag[16:])}, nil
+}
+
+// parseCurve decodes a `curv` (gamma or sampled table) or `para`
+// (parametric) tone reproduction curve.
+func parseCurve(tag []byte) (toneCurve, error) {
+	if len(tag) < 12 {
+		return nil, errUnsupportedProfile
+	}
+	switch string(tag[:4]) {
+	case "curv":
+		n := int(binary.BigEndian.Uint32(tag[8:12]))
+		if len(tag) < 12+2*n {
+			return nil, errUnsupportedProfile
+		}
+		switch n {
+		case 0:
+			return func(x float64) float64 { return x }, nil
+		case 1:
+			g := float64(binary.BigEndian.Uint16(tag[12:14])) / 256
+			return func(x float64) float64 { return math.Pow(x, g) }, nil
+		}
+		table := make([]float64, n)
+		for i := range table {
+			table[i] = float64(binary.BigEndian.Uint16(tag[12+2*i:])) / 65535
+		}
+		return func(x float64) float64 {
+			pos := x * float64(n-1)
+			lo := int(pos)
+			if lo >= n-1 {
+				return table[n-1]
+			}
+			frac := pos - float64(lo)
+			return table[lo]*(1-frac) + table[lo+1]*frac
+		}, nil
+	case "para":
+		fn := binary.BigEndian.Uint16(tag[8:10])
+		nParams := map[uint16]int{0: 1, 1: 3, 2: 4, 3: 5, 4: 7}[fn]
+		if nParams == 0 || len(tag) < 12+4*nParams {
+			return nil, errUnsupportedProfile
+		}
+		v := make([]float64, 7)
+		for i := 0; i < nParams; i++ {
+			v[i] = s15Fixed16(tag[12+4*i:])
+		}
+		g, a, b, c, d, e, f := v[0], v[1], v[2], v[3], v[4], v[5], v[6]
+		return func(x float64) float64 {
+			switch fn {
+			case 0:
+				return math.Pow(x, g)
+			case 1:
+				if x >= -b/a {
+					return math.Pow(a*x+b, g)
+				}
+				return 0
+			case 2:
+				if x >= -b/a {
+					return math.Pow(a*x+b, g) + c
+				}
+				return c
+			case 3:
+				if x >= d {
+					return math.Pow(a*x+b, g)
+				}
+				return c * x
+			default:
+				if x >= d {
+					return math.Pow(a*x+b, g) + e
+				}
+				return c*x + f
+			}
+		}, nil
+	}
+	return nil, errUnsupportedProfile
+}
+
+// --- Conversion to sRGB ---
+
+// srgbD50 is the sRGB primaries matrix adapted to the D50 PCS white point
+// (Bradford), i.e. what a well-formed sRGB profile stores in rXYZ/gXYZ/bXYZ.
+var srgbD50 = [3][3]float64{
+	{0.4360747, 0.3850649, 0.1430804},
+	{0.2225045, 0.7168786, 0.0606169},
+	{0.0139322, 0.0971045, 0.7141733},
+}
+
+// xyzD50ToSRGB is the inverse of srgbD50.
+var xyzD50ToSRGB = [3][3]float64{
+	{3.1338561, -1.6168667, -0.4906146},
+	{-0.9787684, 1.9161415, 0.0334540},
+	{0.0719453, -0.2289914, 1.4052427},
+}
+
+func srgbDecode(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func srgbEncode(v float64) float64 {
+	if v <= 0.0031308 {
+		return 12.92 * v
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+// isSRGB reports whether the profile is (numerically) sRGB, in which case
+// converting would only introduce rounding noise.
+func (p *iccProfile) isSRGB() bool {
+	if p.gray {
+		return false
+	}
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			if math.Abs(p.matrix[r][c]-srgbD50[r][c]) > 0.002 {
+				return false
+			}
+		}
+	}
+	for _, curve := range p.trc {
+		for i := 0; i <= 16; i++ {
+			x := float64(i) / 16
+			if math.Abs(curve(x)-srgbDecode(x)) > 0.5/255 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// applyICC converts img from the profile's colour space to sRGB. It
+// returns errUnsupportedProfile for profiles we cannot interpret.
+func applyICC(img image.Image, raw []byte) (image.Image, error) {
+	prof, err := parseICC(raw)
+	if err != nil {
+		return nil, err
+	}
+	if prof.isSRGB() {
+		return img, nil
+	}
+
+	// Pre-compute 8-bit linearisation tables for each channel, and the
+	// combined matrix from the source primaries straight to linear sRGB.
+	var lin [3][256]float64
+	for ch := 0; ch < 3; ch++ {
+		for i := 0; i < 256; i++ {
+			lin[ch][i] = clamp01(prof.trc[ch](float64(i) / 255))
+		}
+	}
+	m := mul3(xyzD50ToSRGB, prof.matrix)
+
+	b := img.Bounds()
+	out := image.NewNRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			var r, g, bl float64
+			if prof.gray {
+				// A gray profile only defines luminance; neutral stays neutral.
+				v := lin[0][c.R]
+				r, g, bl = v, v, v
+			} else {
+				lr, lg, lb := lin[0][c.R], lin[1][c.G], lin[2][c.B]
+				r = m[0][0]*lr + m[0][1]*lg + m[0][2]*lb
+				g = m[1][0]*lr + m[1][1]*lg + m[1][2]*lb
+				bl = m[2][0]*lr + m[2][1]*lg + m[2][2]*lb
+			}
+			out.SetNRGBA(x, y, color.NRGBA{
+				R: to8(srgbEncode(clamp01(r))),
+				G: to8(srgbEncode(clamp01(g))),
+				B: to8(srgbEncode(clamp01(bl))),
+				A: c.A,
+			})
+		}
+	}
+	return out, nil
+}
+
+func mul3(a, b [3][3]float64) [3][3]float64 {
+	var out [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				out[i][j] += a[i][k] * b[k][j]
+			}
+		}
+	}
+	return out
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+func to8(v float64) uint8 {
+	return uint8(math.Round(v * 255))
+}
@@ -0,0 +1,230 @@
+package preprocess
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"slices"
+	"testing"
+)
+
+// Builders for small ICC profiles.
+
+func iccBytes(space string, tags map[string][]byte) []byte {
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	header := make([]byte, 128)
+	copy(header[16:], space)
+	copy(header[20:], "XYZ ")
+	copy(header[36:], "acsp")
+	table := binary.BigEndian.AppendUint32(nil, uint32(len(names)))
+	var data []byte
+	offset := len(header) + 4 + 12*len(names)
+	for _, name := range names {
+		table = append(table, name...)
+		table = binary.BigEndian.AppendUint32(table, uint32(offset+len(data)))
+		table = binary.BigEndian.AppendUint32(table, uint32(len(tags[name])))
+		data = append(data, tags[name]...)
+	}
+	return slices.Concat(header, table, data)
+}
+
+func s15(v float64) []byte {
+	return binary.BigEndian.AppendUint32(nil, uint32(int32(v*65536)))
+}
+
+func xyzTag(x, y, z float64) []byte {
+	return slices.Concat([]byte("XYZ \x00\x00\x00\x00"), s15(x), s15(y), s15(z))
+}
+
+func gammaTag(g float64) []byte {
+	b := binary.BigEndian.AppendUint32([]byte("curv\x00\x00\x00\x00"), 1)
+	return binary.BigEndian.AppendUint16(b, uint16(g*256))
+}
+
+func paraTag(fn uint16, params ...float64) []byte {
+	b := binary.BigEndian.AppendUint16([]byte("para\x00\x00\x00\x00"), fn)
+	b = append(b, 0, 0)
+	for _, p := range params {
+		b = append(b, s15(p)...)
+	}
+	return b
+}
+
+// rgbProfile is a matrix/TRC profile with sRGB primaries and curve trc.
+func rgbProfile(trc []byte) []byte {
+	tags := map[string][]byte{"rTRC": trc, "gTRC": trc, "bTRC": trc}
+	for col, name := range []string{"rXYZ", "gXYZ", "bXYZ"} {
+		tags[name] = xyzTag(srgbD50[0][col], srgbD50[1][col], srgbD50[2][col])
+	}
+	return iccBytes("RGB ", tags)
+}
+
+var srgbCurve = paraTag(3, 2.4, 1/1.055, 0.055/1.055, 1/12.92, 0.04045)
+
+// pngWithICCP returns a uniform gray PNG carrying an iCCP chunk with the
+// given compressed profile.
+func pngWithICCP(t *testing.T, value uint8, compressed []byte) []byte {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, 4, 8))
+	for i := range img.Pix {
+		img.Pix[i] = value
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	if compressed == nil {
+		return buf.Bytes()
+	}
+	body := slices.Concat([]byte("test\x00\x00"), compressed)
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(body)))
+	chunk = append(chunk, "iCCP"...)
+	chunk = append(chunk, body...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+	// After the signature and the 25-byte IHDR chunk.
+	data := buf.Bytes()
+	return slices.Concat(data[:33], chunk, data[33:])
+}
+
+func deflate(p []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(p)
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestParseICCRejectsMalformedProfiles(t *testing.T) {
+	valid := rgbProfile(gammaTag(1.8))
+	if _, err := parseICC(valid); err != nil {
+		t.Fatalf("parseICC(valid): %v", err)
+	}
+	edit := func(fn func(p []byte) []byte) []byte { return fn(slices.Clone(valid)) }
+	cases := map[string][]byte{
+		"empty":         nil,
+		"short":         valid[:100],
+		"no signature":  edit(func(p []byte) []byte { copy(p[36:], "xxxx"); return p }),
+		"Lab PCS":       edit(func(p []byte) []byte { copy(p[20:], "Lab "); return p }),
+		"CMYK":          edit(func(p []byte) []byte { copy(p[16:], "CMYK"); return p }),
+		"tag table cut": valid[:140],
+		"tag past end":  edit(func(p []byte) []byte { binary.BigEndian.PutUint32(p[132+8:], 1<<20); return p }),
+		"tag count lie": edit(func(p []byte) []byte { binary.BigEndian.PutUint32(p[128:], 1<<30); return p }),
+		"missing TRC":   iccBytes("GRAY", map[string][]byte{"rTRC": gammaTag(1)}),
+		"missing XYZ":   iccBytes("RGB ", map[string][]byte{"rTRC": gammaTag(1), "gTRC": gammaTag(1), "bTRC": gammaTag(1)}),
+		"bad XYZ":       iccBytes("RGB ", map[string][]byte{"rXYZ": []byte("XYZ \x00"), "gXYZ": xyzTag(0, 0, 0), "bXYZ": xyzTag(0, 0, 0)}),
+		"LUT curve":     iccBytes("GRAY", map[string][]byte{"kTRC": []byte("mft2\x00\x00\x00\x00\x00\x00\x00\x00")}),
+		"short table":   iccBytes("GRAY", map[string][]byte{"kTRC": binary.BigEndian.AppendUint32([]byte("curv\x00\x00\x00\x00"), 1000)}),
+		"unknown para":  iccBytes("GRAY", map[string][]byte{"kTRC": paraTag(9, 1)}),
+		"short para":    iccBytes("GRAY", map[string][]byte{"kTRC": paraTag(4, 2.2, 1)}),
+	}
+	for name, p := range cases {
+		if _, err := parseICC(p); err == nil {
+			t.Errorf("%s: parseICC succeeded", name)
+		}
+	}
+}
+
+func TestApplyICC(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 2, 2))
+	for i := range gray.Pix {
+		gray.Pix[i] = 128
+	}
+
+	// An sRGB profile leaves the image as it is.
+	if out, err := applyICC(gray, rgbProfile(srgbCurve)); err != nil || out != image.Image(gray) {
+		t.Errorf("sRGB profile: image converted (%v)", err)
+	}
+
+	// Linear-light encodings are re-encoded with the sRGB curve: 128 is
+	// 50% light, which sRGB encodes as 188.
+	for name, profile := range map[string][]byte{
+		"linear RGB":  rgbProfile(gammaTag(1)),
+		"linear gray": iccBytes("GRAY", map[string][]byte{"kTRC": gammaTag(1)}),
+		"sampled":     iccBytes("GRAY", map[string][]byte{"kTRC": slices.Concat(binary.BigEndian.AppendUint32([]byte("curv\x00\x00\x00\x00"), 2), []byte{0, 0, 0xFF, 0xFF})}),
+		"identity":    iccBytes("GRAY", map[string][]byte{"kTRC": binary.BigEndian.AppendUint32([]byte("curv\x00\x00\x00\x00"), 0)}),
+	} {
+		out, err := applyICC(gray, profile)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		c := color.NRGBAModel.Convert(out.At(1, 1)).(color.NRGBA)
+		if c.R < 187 || c.R > 189 || c.G != c.R || c.B != c.R || c.A != 255 {
+			t.Errorf("%s: pixel = %v, want neutral 188", name, c)
+		}
+	}
+
+	if _, err := applyICC(gray, []byte("not a profile")); err == nil {
+		t.Error("applyICC accepted a malformed profile")
+	}
+}
+
+func TestExtractICC(t *testing.T) {
+	profile := rgbProfile(gammaTag(1))
+	if got := extractICC(pngWithICCP(t, 128, deflate(profile)), "png"); !bytes.Equal(got, profile) {
+		t.Errorf("PNG profile = %d bytes, want %d", len(got), len(profile))
+	}
+	for name, data := range map[string][]byte{
+		"no iCCP":      pngWithICCP(t, 128, nil),
+		"corrupt zlib": pngWithICCP(t, 128, []byte("not zlib")),
+		"truncated":    pngWithICCP(t, 128, deflate(profile))[:40],
+	} {
+		if got := extractICC(data, "png"); got != nil {
+			t.Errorf("%s: extracted %d bytes", name, len(got))
+		}
+	}
+
+	// JPEG profiles may be split across APP2 segments, in any order.
+	app2 := func(seq byte, body string) []byte {
+		seg := slices.Concat([]byte("ICC_PROFILE\x00"), []byte{seq, 2}, []byte(body))
+		return slices.Concat([]byte{0xFF, 0xE2}, binary.BigEndian.AppendUint16(nil, uint16(len(seg)+2)), seg)
+	}
+	soi, sos := []byte{0xFF, 0xD8}, []byte{0xFF, 0xDA, 0, 2}
+	jpeg := slices.Concat(soi, app2(2, "world"), app2(1, "hello "), sos, app2(3, "!"))
+	if got := string(extractICC(jpeg, "jpeg")); got != "hello world" {
+		t.Errorf("JPEG profile = %q, want the segments in order up to the scan", got)
+	}
+	overrun := slices.Concat(soi, app2(1, "hello"))
+	binary.BigEndian.PutUint16(overrun[4:], 0xFFFF)
+	if got := extractICC(overrun, "jpeg"); got != nil {
+		t.Errorf("overrunning segment: extracted %q", got)
+	}
+	if got := extractICC(jpeg, "gif"); got != nil {
+		t.Errorf("GIF: extracted %q", got)
+	}
+}
+
+func TestDecodeImageAppliesICC(t *testing.T) {
+	linear := deflate(iccBytes("GRAY", map[string][]byte{"kTRC": gammaTag(1)}))
+	cases := []struct {
+		name   string
+		data   []byte
+		policy ColorPolicy
+		want   uint8
+	}{
+		{"converted", pngWithICCP(t, 128, linear), ConvertICC, 188},
+		{"stripped", pngWithICCP(t, 128, linear), StripICC, 128},
+		{"unsupported profile", pngWithICCP(t, 128, deflate(iccBytes("CMYK", nil))), ConvertICC, 128},
+		{"corrupt profile", pngWithICCP(t, 128, []byte("not zlib")), ConvertICC, 128},
+	}
+	for _, tc := range cases {
+		opts := DefaultOptions()
+		opts.Color = tc.policy
+		img, err := DecodeImageBytes(tc.data, opts)
+		if err != nil {
+			t.Fatalf("%s: decode: %v", tc.name, err)
+		}
+		r, _, _, _ := img.At(0, 0).RGBA()
+		if got := uint8(r >> 8); got < tc.want-1 || got > tc.want+1 {
+			t.Errorf("%s: pixel = %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
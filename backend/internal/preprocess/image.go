@@ -15,54 +15,162 @@
 package preprocess
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"image"
 
 	// We must perform a blank import of the image formats we want to support.
 	// This registers the decoders with the `image` package, allowing it to
-	// automatically detect and decode JPEG and PNG files.
+	// automatically detect and decode JPEG, PNG, GIF and TIFF files.
+	_ "image/gif"
 	_ "image/jpeg"
 	_ "image/png"
 	"io"
 
-	"github.com/nfnt/resize"
+	_ "golang.org/x/image/tiff"
 	"gorgonia.org/tensor"
 )
 
+// Decode failures are reported wrapped around one of these, so callers
+// can tell a bad upload from a server fault with errors.Is.
+var (
+	// ErrUnsupportedFormat means the data is not in any registered format.
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	// ErrDecode means the data is in a known format but is corrupt or
+	// truncated.
+	ErrDecode = errors.New("invalid image data")
+)
+
+// Formats lists the image formats registered above (and in heic.go and
+// dicom.go), as advertised to clients.
+var Formats = []string{"jpeg", "png", "gif", "tiff", "heic", "dicom"}
+
+// Options tunes how uploads are decoded.
+type Options struct {
+	// MultiFrame decides how animated GIFs and multi-page TIFFs are handled.
+	MultiFrame MultiFramePolicy
+	// Color decides whether embedded ICC profiles are applied or ignored.
+	Color ColorPolicy
+	// Orientation decides how rotated uploads are corrected.
+	Orientation OrientationPolicy
+	// Laterality decides whether right breasts are mirrored.
+	Laterality LateralityPolicy
+	// LateralityHint is the laterality of this particular image ("L" or
+	// "R") when the client knows it; empty means detect it.
+	LateralityHint string
+}
+
+// DefaultOptions returns the conservative decode settings.
+func DefaultOptions() Options {
+	return Options{MultiFrame: RejectMultiFrame, Color: ConvertICC, Orientation: OrientAuto, Laterality: LateralityNone}
+}
+
 // PreprocessImage orchestrates the entire image transformation pipeline.
 // It takes an io.Reader (like an uploaded file), decodes it into an image object,
 // resizes it to the model's required input dimensions, and finally converts it
-// into a multi-dimensional tensor.
-func PreprocessImage(file io.Reader) (tensor.Tensor, error) {
+// into a multi-dimensional tensor laid out as the model's profile describes.
+func PreprocessImage(file io.Reader, profile Profile) (tensor.Tensor, error) {
+	img, err := DecodeImage(file)
+	if err != nil {
+		return nil, err
+	}
+	return profile.Tensor(img), nil
+}
+
+// DecodeImage reads the raw bytes from the reader and decodes them into a
+// generic `image.Image` using DefaultOptions. It is exposed separately so
+// callers that need the decoded image for other purposes (e.g.
+// fingerprinting) only decode once.
+func DecodeImage(file io.Reader) (image.Image, error) {
+	return DecodeImageWithOptions(file, DefaultOptions())
+}
+
+// DecodeImageWithOptions is DecodeImage with explicit decode settings.
+func DecodeImageWithOptions(file io.Reader, opts Options) (image.Image, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	return DecodeImageBytes(data, opts)
+}
+
+// DecodeImageBytes is DecodeImageWithOptions for an upload already in
+// memory, sparing callers that hold the bytes a second copy.
+func DecodeImageBytes(data []byte, opts Options) (image.Image, error) {
 	// --- Step 1: Decode the Image ---
-	// The `image.Decode` function reads the raw bytes from the file reader and,
-	// thanks to our blank imports, automatically determines the correct format
-	// (e.g., JPEG, PNG) and decodes it into a generic `image.Image` object.
-	img, _, err := image.Decode(file)
+	// The `image.Decode` function reads the raw bytes and, thanks to our
+	// blank imports, automatically determines the correct format (e.g.,
+	// JPEG, PNG) and decodes it into a generic `image.Image` object. For
+	// multi-frame formats this is always the first frame.
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		return nil, fmt.Errorf("%w (supported: %v)", ErrUnsupportedFormat, Formats)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode image: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrDecode, err)
 	}
+	if err := checkFrames(data, format, opts.MultiFrame); err != nil {
+		return nil, err
+	}
+
+	// --- Step 1b: Normalise Colour ---
+	// Bring images with an embedded ICC profile into sRGB so the same film
+	// yields the same tensor regardless of the exporting device's profile.
+	if opts.Color == ConvertICC {
+		if profile := extractICC(data, format); profile != nil {
+			if converted, err := applyICC(img, profile); err == nil {
+				img = converted
+			}
+		}
+	}
+
+	// --- Step 1c: Normalise Orientation ---
+	// Rotate the image upright and into the portrait layout used in training.
+	img = normaliseOrientation(img, data, format, opts.Orientation)
 
-	// --- Step 2: Resize the Image ---
-	// Our neural network expects a fixed input size of 224x224 pixels.
-	// We use the `resize.Resize` function to downscale or upscale the image.
-	// `resize.Lanczos3` is a high-quality interpolation algorithm that produces
-	// a clear image with minimal artifacts.
-	resizedImg := resize.Resize(224, 224, img, resize.Lanczos3)
+	// --- Step 1d: Normalise Laterality ---
+	// Mirror right breasts so the chest wall is on the left, as in training.
+	// A DICOM header knows the laterality when the client does not.
+	hint := opts.LateralityHint
+	if hint == "" && format == "dicom" {
+		hint = dicomLaterality(data)
+	}
+	img = normaliseLaterality(img, opts.Laterality, hint)
+	return img, nil
+}
+
+// ImageToTensor resizes a decoded image to the champion model's input
+// size and converts it into the 4D tensor that model expects. Models with
+// another pipeline take Profile.Tensor.
+func ImageToTensor(img image.Image) tensor.Tensor {
+	return DefaultProfile().Tensor(img)
+}
 
-	// --- Step 3: Convert Image to Tensor ---
-	// The ONNX model requires the input data to be in a specific tensor format:
-	// a 4D tensor with shape [batch_size, height, width, channels] and float32 values.
+// rgbValues reads a resized image into a flat slice of 0-255 RGB values
+// in HWC order.
+func rgbValues(resizedImg image.Image) []float32 {
+	// --- Convert Image to Tensor Data ---
+	// The model requires the input data as float32 values; we read them
+	// in "channels-last" order and lay them out per the model's profile.
 	height := resizedImg.Bounds().Dy()
 	width := resizedImg.Bounds().Dx()
 	// We create a flat slice to hold all the pixel data.
-	tensorData := make([]float32, 1*height*width*3) // batch_size=1, channels=3 (R,G,B)
+	tensorData := make([]float32, height*width*3) // channels=3 (R,G,B)
+
+	// The resizer returns one of a few concrete image types. Reading their
+	// pixels directly avoids boxing a color.Color per pixel, which was
+	// most of the allocations of a request; the values are identical.
+	if fillTensor(tensorData, resizedImg) {
+		return tensorData
+	}
 
 	// This loop iterates through each pixel of the resized image.
+	b := resizedImg.Bounds()
 	for y := 0; y < height; y++ {
 		for x := 0; x < width; x++ {
 			// The `At(x, y).RGBA()` method returns the color of a pixel.
-			r, g, b, _ := resizedImg.At(x, y).RGBA()
+			r, g, bl, _ := resizedImg.At(b.Min.X+x, b.Min.Y+y).RGBA()
 
 			// The returned RGBA values are 16-bit (0-65535). Our model was trained
 			// on 8-bit values (0-255). The `>> 8` bit-shift operation is an
@@ -70,18 +178,57 @@ func PreprocessImage(file io.Reader) (tensor.Tensor, error) {
 			// The baseIndex calculation ensures we place the R, G, B values
 			// sequentially in the flat slice, matching the "channels-last" format (HWC).
 			baseIndex := (y*width + x) * 3
-			tensorData[baseIndex+0] = float32(r >> 8) // Red channel
-			tensorData[baseIndex+1] = float32(g >> 8) // Green channel
-			tensorData[baseIndex+2] = float32(b >> 8) // Blue channel
+			tensorData[baseIndex+0] = float32(r >> 8)  // Red channel
+			tensorData[baseIndex+1] = float32(g >> 8)  // Green channel
+			tensorData[baseIndex+2] = float32(bl >> 8) // Blue channel
 		}
 	}
+	return tensorData
+}
 
-	// Finally, we create a Gorgonia tensor object, wrapping our flat slice
-	// of pixel data and applying the correct 4D shape that our model requires.
-	inputTensor := tensor.New(
-		tensor.WithShape(1, height, width, 3),
-		tensor.WithBacking(tensorData),
-	)
-
-	return inputTensor, nil
+// fillTensor is the allocation-free path of rgbValues for the image
+// types the resizer produces. It reports false for any other type.
+func fillTensor(dst []float32, img image.Image) bool {
+	b := img.Bounds()
+	i := 0
+	switch m := img.(type) {
+	case *image.RGBA:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			row := m.Pix[m.PixOffset(b.Min.X, y):]
+			for x := 0; x < b.Dx(); x++ {
+				dst[i], dst[i+1], dst[i+2] = float32(row[x*4]), float32(row[x*4+1]), float32(row[x*4+2])
+				i += 3
+			}
+		}
+	case *image.RGBA64:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			row := m.Pix[m.PixOffset(b.Min.X, y):]
+			for x := 0; x < b.Dx(); x++ {
+				// The high byte of each big-endian 16-bit sample.
+				dst[i], dst[i+1], dst[i+2] = float32(row[x*8]), float32(row[x*8+2]), float32(row[x*8+4])
+				i += 3
+			}
+		}
+	case *image.Gray:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			row := m.Pix[m.PixOffset(b.Min.X, y):]
+			for x := 0; x < b.Dx(); x++ {
+				v := float32(row[x])
+				dst[i], dst[i+1], dst[i+2] = v, v, v
+				i += 3
+			}
+		}
+	case *image.Gray16:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			row := m.Pix[m.PixOffset(b.Min.X, y):]
+			for x := 0; x < b.Dx(); x++ {
+				v := float32(row[x*2])
+				dst[i], dst[i+1], dst[i+2] = v, v, v
+				i += 3
+			}
+		}
+	default:
+		return false
+	}
+	return true
 }
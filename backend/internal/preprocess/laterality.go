@@ -0,0 +1,97 @@
+// backend/internal/preprocess/laterality.go
+/*
+ * This file normalises breast laterality before inference.
+ *
+ * In the standard radiological display a left breast has its chest wall
+ * on the left edge of the image and a right breast on the right edge. Our
+ * training set was flip-normalised so every breast has the chest wall on
+ * the left; serving unflipped right-breast views puts them outside what
+ * the model learned. With the "flip" policy, right-facing images are
+ * mirrored to match.
+ *
+ * Laterality comes from the client when it knows it (the DICOM
+ * ImageLaterality "L"/"R"); otherwise it is detected the same way the
+ * orientation heuristic finds the chest wall: it is the brighter edge.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package preprocess
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/nfnt/resize"
+)
+
+// LateralityPolicy decides whether images are flip-normalised.
+type LateralityPolicy string
+
+const (
+	// LateralityNone leaves left/right as uploaded.
+	LateralityNone LateralityPolicy = "none"
+	// LateralityFlip mirrors right breasts so the chest wall is on the left.
+	LateralityFlip LateralityPolicy = "flip"
+)
+
+// Laterality values, as in DICOM ImageLaterality.
+const (
+	Left  = "L"
+	Right = "R"
+)
+
+// ParseLaterality normalises a client-supplied laterality ("L", "left",
+// "R", "right", any case). An empty value means unknown.
+func ParseLaterality(s string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return "", nil
+	case "l", "left":
+		return Left, nil
+	case "r", "right":
+		return Right, nil
+	}
+	return "", fmt.Errorf("laterality must be L or R, got %q", s)
+}
+
+// normaliseLaterality applies the laterality policy. hint is the known
+// laterality, or empty to detect it.
+func normaliseLaterality(img image.Image, policy LateralityPolicy, hint string) image.Image {
+	if policy != LateralityFlip {
+		return img
+	}
+	laterality := hint
+	if laterality == "" {
+		laterality = detectLaterality(img)
+	}
+	if laterality == Right {
+		return flipHorizontal(img)
+	}
+	return img
+}
+
+// detectLaterality compares the brightness of the left and right bands:
+// tissue is bright against a dark background and widest at the chest
+// wall, so the brighter band is the chest wall.
+func detectLaterality(img image.Image) string {
+	small := resize.Resize(0, 64, img, resize.Bilinear)
+	b := small.Bounds()
+	band := max(b.Dx()/10, 1)
+
+	var left, right uint64
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for i := 0; i < band; i++ {
+			left += uint64(color.GrayModel.Convert(small.At(b.Min.X+i, y)).(color.Gray).Y)
+			right += uint64(color.GrayModel.Convert(small.At(b.Max.X-1-i, y)).(color.Gray).Y)
+		}
+	}
+	if right > left {
+		return Right
+	}
+	return Left
+}
@@ -0,0 +1,221 @@
+// backend/internal/preprocess/orientation.go
+/*
+ * This file normalises image orientation before inference.
+ *
+ * Our training mammograms are portrait, with the chest wall running down
+ * one vertical edge. Uploads arrive rotated for two reasons: phones and
+ * scanners record the rotation in an EXIF tag instead of rotating pixels,
+ * and some digitizers simply output landscape images. We first honour the
+ * EXIF orientation tag, then (in "auto" mode) rotate any remaining
+ * landscape image so that the chest wall — the edge with the most dense
+ * tissue along it — ends up on the left, matching the training layout.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package preprocess
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+
+	"github.com/nfnt/resize"
+)
+
+// OrientationPolicy decides how much orientation correction is applied.
+type OrientationPolicy string
+
+const (
+	// OrientNone leaves pixels exactly as decoded.
+	OrientNone OrientationPolicy = "none"
+	// OrientEXIF applies only the EXIF orientation tag.
+	OrientEXIF OrientationPolicy = "exif"
+	// OrientAuto applies EXIF and then the portrait/chest-wall heuristic.
+	OrientAuto OrientationPolicy = "auto"
+)
+
+// landscapeRatio is how much wider than tall an image must be before the
+// heuristic treats it as rotated.
+const landscapeRatio = 1.1
+
+// normaliseOrientation applies the configured orientation policy.
+func normaliseOrientation(img image.Image, data []byte, format string, policy OrientationPolicy) image.Image {
+	if policy == OrientNone || policy == "" {
+		return img
+	}
+
+	if o := exifOrientation(data, format); o > 1 {
+		img = applyEXIFOrientation(img, o)
+	}
+
+	if policy == OrientAuto {
+		b := img.Bounds()
+		if float64(b.Dx()) > float64(b.Dy())*landscapeRatio {
+			if chestWallOnTop(img) {
+				img = rotate(img, 270) // top edge → left edge
+			} else {
+				img = rotate(img, 90) // bottom edge → left edge
+			}
+		}
+	}
+	return img
+}
+
+// chestWallOnTop compares the brightness of the top and bottom bands of a
+// landscape image. Breast tissue is bright against a dark background and is
+// widest at the chest wall, so the brighter band is the chest wall.
+func chestWallOnTop(img image.Image) bool {
+	small := resize.Resize(64, 0, img, resize.Bilinear)
+	b := small.Bounds()
+	band := b.Dy() / 10
+	if band < 1 {
+		band = 1
+	}
+
+	var top, bottom uint64
+	for x := b.Min.X; x < b.Max.X; x++ {
+		for i := 0; i < band; i++ {
+			top += uint64(color.GrayModel.Convert(small.At(x, b.Min.Y+i)).(color.Gray).Y)
+			bottom += uint64(color.GrayModel.Convert(small.At(x, b.Max.Y-1-i)).(color.Gray).Y)
+		}
+	}
+	return top >= bottom
+}
+
+// --- EXIF parsing ---
+
+// exifOrientation returns the EXIF orientation (1–8) or 0 if absent.
+func exifOrientation(data []byte, format string) int {
+	switch format {
+	case "jpeg":
+		return jpegEXIFOrientation(data)
+	case "tiff":
+		return tiffOrientation(data)
+	}
+	return 0
+}
+
+func jpegEXIFOrientation(data []byte) int {
+	i := 2
+	for i+4 <= len(data) && data[i] == 0xFF {
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 {
+			break
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if length < 2 || i+2+length > len(data) {
+			break
+		}
+		seg := data[i+4 : i+2+length]
+		if marker == 0xE1 && len(seg) > 6 && string(seg[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(seg[6:])
+		}
+		i += 2 + length
+	}
+	return 0
+}
+
+// tiffOrientation reads tag 0x0112 from IFD0 of a TIFF structure (either a
+// TIFF file or the TIFF block inside a JPEG EXIF segment).
+func tiffOrientation(t []byte) int {
+	if len(t) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(t[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	ifd := int(order.Uint32(t[4:8]))
+	if ifd+2 > len(t) {
+		return 0
+	}
+	n := int(order.Uint16(t[ifd : ifd+2]))
+	for i := 0; i < n; i++ {
+		e := ifd + 2 + i*12
+		if e+12 > len(t) {
+			return 0
+		}
+		if order.Uint16(t[e:e+2]) == 0x0112 {
+			v := int(order.Uint16(t[e+8 : e+10]))
+			if v >= 1 && v <= 8 {
+				return v
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// --- Pixel transforms ---
+
+// applyEXIFOrientation maps the eight EXIF orientations onto rotations and
+// mirror flips so the result displays upright.
+func applyEXIFOrientation(img image.Image, o int) image.Image {
+	switch o {
+	case 2:
+		return flipHorizontal(img)
+	case 3:
+		return rotate(img, 180)
+	case 4:
+		return flipHorizontal(rotate(img, 180))
+	case 5:
+		return flipHorizontal(rotate(img, 90))
+	case 6:
+		return rotate(img, 90)
+	case 7:
+		return flipHorizontal(rotate(img, 270))
+	case 8:
+		return rotate(img, 270)
+	}
+	return img
+}
+
+// rotate turns img clockwise by 90, 180 or 270 degrees.
+func rotate(img image.Image, degrees int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	var out *image.NRGBA64
+	if degrees == 180 {
+		out = image.NewNRGBA64(image.Rect(0, 0, w, h))
+	} else {
+		out = image.NewNRGBA64(image.Rect(0, 0, h, w))
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := img.At(b.Min.X+x, b.Min.Y+y)
+			switch degrees {
+			case 90:
+				out.Set(h-1-y, x, c)
+			case 180:
+				out.Set(w-1-x, h-1-y, c)
+			case 270:
+				out.Set(y, w-1-x, c)
+			}
+		}
+	}
+	return out
+}
+
+// flipHorizontal mirrors img left-to-right.
+func flipHorizontal(img image.Image) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	out := image.NewNRGBA64(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			out.Set(w-1-x, y, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return out
+}
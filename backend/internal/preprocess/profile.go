@@ -0,0 +1,314 @@
+// backend/internal/preprocess/profile.go
+/*
+ * This file describes the input pipeline a model was trained with.
+ *
+ * Exported models disagree about their input: PyTorch exports take NCHW
+ * tensors scaled to 0-1 and normalised with the ImageNet mean and
+ * standard deviation, our Keras champion takes NHWC tensors of raw 0-255
+ * values, and some models read a single grayscale channel. A Profile
+ * records these choices so a model can be swapped for one with a
+ * different pipeline without a code change. It is shipped in the
+ * "preprocessing" object of the model's metadata sidecar:
+ *
+ *   {"preprocessing": {"layout": "NCHW", "width": 224, "height": 224,
+ *                      "channels": 3, "range": "0-1",
+ *                      "normalization": "imagenet"}}
+ *
+ * Omitted fields keep the champion's pipeline (DefaultProfile). Mean and
+ * std may be given per channel instead of a named normalization; they are
+ * in the units of the range.
+ *
+ * The engine a model is loaded into keeps its profile, and TensorFor
+ * builds the input of whichever engine will score it.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package preprocess
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"math"
+	"slices"
+
+	"github.com/nfnt/resize"
+	"gorgonia.org/tensor"
+)
+
+// Layout is the dimension order of a model input.
+type Layout string
+
+const (
+	// LayoutNHWC is batch, height, width, channels (Keras, TensorFlow).
+	LayoutNHWC Layout = "NHWC"
+	// LayoutNCHW is batch, channels, height, width (PyTorch).
+	LayoutNCHW Layout = "NCHW"
+)
+
+// Pixel value ranges before normalization.
+const (
+	Range255  = "0-255"
+	RangeUnit = "0-1"
+)
+
+// NormalizationImageNet names the ImageNet channel mean and standard
+// deviation used by torchvision and most pretrained backbones.
+const NormalizationImageNet = "imagenet"
+
+// imageNetMean and imageNetStd are the ImageNet statistics on the 0-1
+// scale, in RGB order.
+var (
+	imageNetMean = []float64{0.485, 0.456, 0.406}
+	imageNetStd  = []float64{0.229, 0.224, 0.225}
+)
+
+// Profile is the input pipeline of a model.
+type Profile struct {
+	Layout Layout `json:"layout,omitempty"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+	// Channels is 3 for RGB or 1 for grayscale (luminance).
+	Channels int `json:"channels,omitempty"`
+	// Range is the scale of pixel values: Range255 or RangeUnit.
+	Range string `json:"range,omitempty"`
+	// Normalization names a standard mean and std ("imagenet"); Mean and
+	// Std give them explicitly, one value per channel or one for all.
+	Normalization string    `json:"normalization,omitempty"`
+	Mean          []float64 `json:"mean,omitempty"`
+	Std           []float64 `json:"std,omitempty"`
+}
+
+// DefaultProfile returns the champion model's pipeline: 224x224 RGB in
+// NHWC order with raw 0-255 values.
+func DefaultProfile() Profile {
+	return Profile{Layout: LayoutNHWC, Width: 224, Height: 224, Channels: 3, Range: Range255}
+}
+
+// ParseProfile reads the profile in a model metadata sidecar; ok is false
+// when the sidecar has none.
+func ParseProfile(data []byte) (p Profile, ok bool, err error) {
+	var meta struct {
+		Preprocessing *Profile `json:"preprocessing"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return Profile{}, false, fmt.Errorf("model metadata: %w", err)
+	}
+	if meta.Preprocessing == nil {
+		return DefaultProfile(), false, nil
+	}
+	if p, err = meta.Preprocessing.Resolve(); err != nil {
+		return Profile{}, false, fmt.Errorf("model metadata: %w", err)
+	}
+	return p, true, nil
+}
+
+// Resolve fills in the omitted fields from DefaultProfile, expands a
+// named normalization into Mean and Std, and validates the result.
+func (p Profile) Resolve() (Profile, error) {
+	d := DefaultProfile()
+	if p.Layout == "" {
+		p.Layout = d.Layout
+	}
+	if p.Width == 0 {
+		p.Width = d.Width
+	}
+	if p.Height == 0 {
+		p.Height = d.Height
+	}
+	if p.Channels == 0 {
+		p.Channels = d.Channels
+	}
+	if p.Range == "" {
+		p.Range = d.Range
+	}
+	switch {
+	case p.Layout != LayoutNHWC && p.Layout != LayoutNCHW:
+		return p, fmt.Errorf("unknown layout %q (want %s or %s)", p.Layout, LayoutNHWC, LayoutNCHW)
+	case p.Width < 1 || p.Height < 1:
+		return p, fmt.Errorf("invalid input size %dx%d", p.Width, p.Height)
+	case p.Channels != 1 && p.Channels != 3:
+		return p, fmt.Errorf("invalid channel count %d (want 1 or 3)", p.Channels)
+	case p.Range != Range255 && p.Range != RangeUnit:
+		return p, fmt.Errorf("unknown range %q (want %s or %s)", p.Range, Range255, RangeUnit)
+	}
+
+	switch p.Normalization {
+	case "":
+	case NormalizationImageNet:
+		if p.Mean != nil || p.Std != nil {
+			return p, fmt.Errorf("normalization %q and an explicit mean or std are exclusive", p.Normalization)
+		}
+		p.Mean, p.Std = slices.Clone(imageNetMean), slices.Clone(imageNetStd)
+		if p.Channels == 1 {
+			// Grayscale backbones use the luminance of the RGB statistics.
+			p.Mean, p.Std = []float64{luminance(p.Mean)}, []float64{luminance(p.Std)}
+		}
+		if p.Range == Range255 {
+			for i := range p.Mean {
+				p.Mean[i] *= 255
+				p.Std[i] *= 255
+			}
+		}
+		p.Normalization = ""
+	default:
+		return p, fmt.Errorf("unknown normalization %q (want %s)", p.Normalization, NormalizationImageNet)
+	}
+	for name, values := range map[string][]float64{"mean": p.Mean, "std": p.Std} {
+		if len(values) != 0 && len(values) != 1 && len(values) != p.Channels {
+			return p, fmt.Errorf("%s has %d values for %d channels", name, len(values), p.Channels)
+		}
+	}
+	for _, s := range p.Std {
+		if s <= 0 {
+			return p, fmt.Errorf("std must be positive, got %g", s)
+		}
+	}
+	return p, nil
+}
+
+// IsDefault reports whether p is the champion model's pipeline.
+func (p Profile) IsDefault() bool {
+	d := DefaultProfile()
+	return p.Layout == d.Layout && p.Width == d.Width && p.Height == d.Height &&
+		p.Channels == d.Channels && p.Range == d.Range && p.Normalization == "" && p.Mean == nil && p.Std == nil
+}
+
+// ChannelNames names the channels of the input, in order.
+func (p Profile) ChannelNames() []string {
+	if p.Channels == 1 {
+		return []string{"L"}
+	}
+	return []string{"R", "G", "B"}
+}
+
+// Shape is the shape of an input of batch size one.
+func (p Profile) Shape() tensor.Shape {
+	if p.Layout == LayoutNCHW {
+		return tensor.Shape{1, p.Channels, p.Height, p.Width}
+	}
+	return tensor.Shape{1, p.Height, p.Width, p.Channels}
+}
+
+// Tensor resizes img and converts it into the input the profile
+// describes. An unresolved profile is resolved first; an invalid one
+// falls back to DefaultProfile.
+func (p Profile) Tensor(img image.Image) tensor.Tensor {
+	p, err := p.Resolve()
+	if err != nil {
+		p = DefaultProfile()
+	}
+	// --- Resize the Image ---
+	// `resize.Lanczos3` is a high-quality interpolation algorithm that
+	// produces a clear image with minimal artifacts. The pixels are read
+	// as 0-255 RGB in HWC order, exactly as the champion model takes
+	// them; the other pipelines are derived from that.
+	rgb := rgbValues(resize.Resize(uint(p.Width), uint(p.Height), img, resize.Lanczos3))
+	if p.Channels == 3 && p.Range == Range255 && p.Layout == LayoutNHWC && p.Mean == nil && p.Std == nil {
+		return tensor.New(tensor.WithShape(p.Shape()...), tensor.WithBacking(rgb))
+	}
+
+	// --- Convert Channels, Scale and Normalize ---
+	pixels := p.Width * p.Height
+	data := make([]float32, pixels*p.Channels)
+	scale := 1.0
+	if p.Range == RangeUnit {
+		scale = 1.0 / 255
+	}
+	for i := range pixels {
+		r, g, b := float64(rgb[i*3]), float64(rgb[i*3+1]), float64(rgb[i*3+2])
+		values := [3]float64{r, g, b}
+		if p.Channels == 1 {
+			values[0] = luminance([]float64{r, g, b})
+		}
+		for ch := range p.Channels {
+			v := values[ch] * scale
+			if p.Mean != nil {
+				v -= p.Mean[min(ch, len(p.Mean)-1)]
+			}
+			if p.Std != nil {
+				v /= p.Std[min(ch, len(p.Std)-1)]
+			}
+			if p.Layout == LayoutNCHW {
+				data[ch*pixels+i] = float32(v)
+			} else {
+				data[i*p.Channels+ch] = float32(v)
+			}
+		}
+	}
+	return tensor.New(tensor.WithShape(p.Shape()...), tensor.WithBacking(data))
+}
+
+// Image renders an input built with the profile back as an image,
+// undoing the normalization, so it can be inspected.
+func (p Profile) Image(input tensor.Tensor) *image.RGBA {
+	p, err := p.Resolve()
+	if err != nil {
+		p = DefaultProfile()
+	}
+	data := input.Data().([]float32)
+	pixels := p.Width * p.Height
+	img := image.NewRGBA(image.Rect(0, 0, p.Width, p.Height))
+	scale := 1.0
+	if p.Range == RangeUnit {
+		scale = 255
+	}
+	for i := range pixels {
+		var values [3]float64
+		for ch := range p.Channels {
+			idx := i*p.Channels + ch
+			if p.Layout == LayoutNCHW {
+				idx = ch*pixels + i
+			}
+			v := float64(data[idx])
+			if p.Std != nil {
+				v *= p.Std[min(ch, len(p.Std)-1)]
+			}
+			if p.Mean != nil {
+				v += p.Mean[min(ch, len(p.Mean)-1)]
+			}
+			values[ch] = v * scale
+		}
+		if p.Channels == 1 {
+			values[1], values[2] = values[0], values[0]
+		}
+		for ch, v := range values {
+			img.Pix[i*4+ch] = uint8(math.Round(math.Max(0, math.Min(255, v))))
+		}
+		img.Pix[i*4+3] = 0xff
+	}
+	return img
+}
+
+// Profiled is implemented by engines that know the input pipeline of the
+// model they run.
+type Profiled interface {
+	InputProfile() Profile
+}
+
+// TensorFor builds the input of img for engine: with the engine's profile
+// when it has one, with DefaultProfile otherwise.
+func TensorFor(engine any, img image.Image) tensor.Tensor {
+	if p, ok := engine.(Profiled); ok {
+		return p.InputProfile().Tensor(img)
+	}
+	return ImageToTensor(img)
+}
+
+// ProfileOf returns the input pipeline of engine.
+func ProfileOf(engine any) Profile {
+	if p, ok := engine.(Profiled); ok {
+		if resolved, err := p.InputProfile().Resolve(); err == nil {
+			return resolved
+		}
+	}
+	return DefaultProfile()
+}
+
+// luminance is the Rec. 601 luma of RGB values.
+func luminance(rgb []float64) float64 {
+	return 0.299*rgb[0] + 0.587*rgb[1] + 0.114*rgb[2]
+}
@@ -0,0 +1,141 @@
+// backend/internal/reports/delivery.go
+/*
+ * This file contains the report delivery channels: an HTTP webhook (JSON
+ * or HTML payload) and SMTP email (HTML body with the JSON attached).
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package reports
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Deliverer sends a compiled summary somewhere.
+type Deliverer interface {
+	Deliver(ctx context.Context, s Summary) error
+}
+
+// Webhook POSTs the summary to a URL.
+type Webhook struct {
+	URL string
+	// Format is "json" (default) or "html".
+	Format string
+	Client *http.Client
+}
+
+// Deliver implements Deliverer.
+func (w *Webhook) Deliver(ctx context.Context, s Summary) error {
+	body, contentType, err := render(s, w.Format)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("report webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("report webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Email sends the summary over SMTP.
+type Email struct {
+	// Addr is the SMTP server as host:port.
+	Addr     string
+	Username string
+	// Password returns the SMTP password; it is read for every message so
+	// a rotated credential is picked up.
+	Password func() string
+	From     string
+	To       []string
+}
+
+// Deliver implements Deliverer.
+func (e *Email) Deliver(_ context.Context, s Summary) error {
+	htmlBody, err := s.HTML()
+	if err != nil {
+		return err
+	}
+	jsonBody, err := s.JSON()
+	if err != nil {
+		return err
+	}
+
+	// --- Build a multipart/mixed message: HTML body + JSON attachment ---
+	var msg bytes.Buffer
+	mw := multipart.NewWriter(&msg)
+	fmt.Fprintf(&msg, "From: %s\r\n", e.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.To, ", "))
+	fmt.Fprintf(&msg, "Subject: MammoScan AI %s summary (%s)\r\n", s.Period, s.To.Format("2006-01-02"))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mw.Boundary())
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/html; charset=utf-8"}})
+	if err != nil {
+		return err
+	}
+	part.Write(htmlBody)
+
+	part, err = mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"application/json"},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition":       {fmt.Sprintf(`attachment; filename="summary-%s.json"`, s.To.Format("2006-01-02"))},
+	})
+	if err != nil {
+		return err
+	}
+	part.Write([]byte(base64.StdEncoding.EncodeToString(jsonBody)))
+	mw.Close()
+
+	var auth smtp.Auth
+	if e.Username != "" {
+		host, _, _ := net.SplitHostPort(e.Addr)
+		var password string
+		if e.Password != nil {
+			password = e.Password()
+		}
+		auth = smtp.PlainAuth("", e.Username, password, host)
+	}
+	if err := smtp.SendMail(e.Addr, auth, e.From, e.To, msg.Bytes()); err != nil {
+		return fmt.Errorf("report email: %w", err)
+	}
+	return nil
+}
+
+// render encodes a summary in the requested format.
+func render(s Summary, format string) ([]byte, string, error) {
+	if format == "html" {
+		body, err := s.HTML()
+		return body, "text/html; charset=utf-8", err
+	}
+	body, err := s.JSON()
+	return body, "application/json", err
+}
@@ -0,0 +1,122 @@
+// backend/internal/reports/scheduler.go
+/*
+ * This file contains the report scheduler.
+ *
+ * Each configured period ("daily", "weekly") gets its own goroutine that
+ * sleeps until the next delivery time, compiles a Summary covering the time
+ * since the previous delivery, and hands it to every Deliverer.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package reports
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/stats"
+)
+
+// ScheduleConfig controls when reports are sent.
+type ScheduleConfig struct {
+	// Periods lists the reports to produce: "daily" and/or "weekly".
+	Periods []string
+	// Hour and Minute give the local delivery time.
+	Hour, Minute int
+	// Weekday is the delivery day for weekly reports.
+	Weekday time.Weekday
+	// Location is the time zone for delivery times.
+	Location *time.Location
+	// Disclaimer is the regulatory text appended to every report.
+	Disclaimer string
+}
+
+// Scheduler periodically compiles and delivers summaries.
+type Scheduler struct {
+	cfg        ScheduleConfig
+	collector  *stats.Collector
+	deliverers []Deliverer
+}
+
+// NewScheduler validates the configuration and creates a scheduler.
+func NewScheduler(cfg ScheduleConfig, collector *stats.Collector, deliverers ...Deliverer) (*Scheduler, error) {
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	for _, p := range cfg.Periods {
+		if p != "daily" && p != "weekly" {
+			return nil, fmt.Errorf("unknown report period %q", p)
+		}
+	}
+	if cfg.Hour < 0 || cfg.Hour > 23 || cfg.Minute < 0 || cfg.Minute > 59 {
+		return nil, fmt.Errorf("invalid report time %02d:%02d", cfg.Hour, cfg.Minute)
+	}
+	return &Scheduler{cfg: cfg, collector: collector, deliverers: deliverers}, nil
+}
+
+// Start launches one goroutine per configured period.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, period := range s.cfg.Periods {
+		go s.loop(ctx, period)
+	}
+}
+
+// Preview compiles the summary for a period as if it were delivered now,
+// covering everything since the service started.
+func (s *Scheduler) Preview(period string) Summary {
+	end := s.collector.Snapshot()
+	summary := Build(period, end.StartedAt, stats.Snapshot{}, end)
+	summary.Disclaimer = s.cfg.Disclaimer
+	return summary
+}
+
+func (s *Scheduler) loop(ctx context.Context, period string) {
+	from := time.Now().UTC()
+	start := s.collector.Snapshot()
+
+	for {
+		next := s.next(period, time.Now())
+		log.Printf("Next %s report at %s", period, next.Format(time.RFC3339))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+		}
+
+		end := s.collector.Snapshot()
+		summary := Build(period, from, start, end)
+		summary.Disclaimer = s.cfg.Disclaimer
+		for _, d := range s.deliverers {
+			if err := d.Deliver(ctx, summary); err != nil {
+				log.Printf("%s report delivery failed: %v", period, err)
+			}
+		}
+		from, start = summary.To, end
+	}
+}
+
+// next returns the next delivery time strictly after now.
+func (s *Scheduler) next(period string, now time.Time) time.Time {
+	now = now.In(s.cfg.Location)
+	t := time.Date(now.Year(), now.Month(), now.Day(), s.cfg.Hour, s.cfg.Minute, 0, 0, s.cfg.Location)
+
+	if period == "weekly" {
+		days := (int(s.cfg.Weekday) - int(t.Weekday()) + 7) % 7
+		t = t.AddDate(0, 0, days)
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 7)
+		}
+		return t
+	}
+
+	if !t.After(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
@@ -0,0 +1,116 @@
+// backend/internal/reports/summary.go
+/*
+ * This file builds and renders the periodic service summary.
+ *
+ * A Summary covers one reporting period (a day or a week). Volumes are the
+ * difference between the stats collector's totals at the start and end of
+ * the period; latency and drift reflect the collector's rolling window at
+ * the time the report is compiled.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package reports
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/stats"
+)
+
+// Summary is the content of one scheduled report.
+type Summary struct {
+	Period         string             `json:"period"`
+	From           time.Time          `json:"from"`
+	To             time.Time          `json:"to"`
+	Predictions    int64              `json:"predictions"`
+	Positives      int64              `json:"positives"`
+	PositivityRate float64            `json:"positivity_rate"`
+	Errors         int64              `json:"errors"`
+	Latency        stats.LatencyStats `json:"latency_ms"`
+	DriftStatus    string             `json:"drift_status"`
+	DriftPSI       float64            `json:"drift_psi"`
+	DriftFlagged   bool               `json:"drift_flagged"`
+	// Compute totals the compute spent in the period and its estimated
+	// energy use and emissions, for sustainability reporting.
+	Compute    stats.ComputeStats `json:"compute"`
+	Disclaimer string             `json:"disclaimer,omitempty"`
+}
+
+// Build compiles a summary from the snapshots taken at the start and end
+// of the period.
+func Build(period string, from time.Time, start, end stats.Snapshot) Summary {
+	s := Summary{
+		Period:      period,
+		From:        from,
+		To:          time.Now().UTC(),
+		Predictions: end.Predictions - start.Predictions,
+		Positives:   end.Positives - start.Positives,
+		Errors:      end.Errors - start.Errors,
+		Latency:     end.Latency,
+		DriftStatus: end.Drift.Status,
+		DriftPSI:    end.Drift.PSI,
+	}
+	s.Compute = stats.ComputeStats{
+		Predictions: end.Compute.Predictions - start.Compute.Predictions,
+		Seconds:     end.Compute.Seconds - start.Compute.Seconds,
+		EnergyWh:    end.Compute.EnergyWh - start.Compute.EnergyWh,
+		CarbonGrams: end.Compute.CarbonGrams - start.Compute.CarbonGrams,
+	}
+	// If the process restarted mid-period the counters were reset, so the
+	// end snapshot alone is the best information we have.
+	if s.Predictions < 0 || s.Errors < 0 || s.Compute.Predictions < 0 {
+		s.Predictions, s.Positives, s.Errors = end.Predictions, end.Positives, end.Errors
+		s.Compute = end.Compute
+		s.From = end.StartedAt
+	}
+	if s.Predictions > 0 {
+		s.PositivityRate = float64(s.Positives) / float64(s.Predictions)
+	}
+	s.DriftFlagged = end.Drift.Status == "moderate" || end.Drift.Status == "significant"
+	return s
+}
+
+// JSON renders the summary as indented JSON.
+func (s Summary) JSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// HTML renders the summary as a self-contained HTML document suitable for
+// an email body.
+func (s Summary) HTML() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, s); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var htmlTemplate = template.Must(template.New("summary").Funcs(template.FuncMap{
+	"pct":  formatPercent,
+	"date": func(t time.Time) string { return t.Format("2006-01-02 15:04 MST") },
+}).Parse(`<!DOCTYPE html>
+<html><body style="font-family: sans-serif;">
+<h2>MammoScan AI — {{.Period}} summary</h2>
+<p>{{date .From}} → {{date .To}}</p>
+<table cellpadding="6" style="border-collapse: collapse;">
+<tr><td>Predictions</td><td><b>{{.Predictions}}</b></td></tr>
+<tr><td>Positivity rate</td><td><b>{{pct .PositivityRate}}</b></td></tr>
+<tr><td>Errors</td><td><b>{{.Errors}}</b></td></tr>
+<tr><td>Latency p50 / p95 / p99</td><td>{{.Latency.P50}} / {{.Latency.P95}} / {{.Latency.P99}} ms</td></tr>
+<tr><td>Compute</td><td>{{printf "%.1f" .Compute.Seconds}} s · {{printf "%.2f" .Compute.EnergyWh}} Wh{{if .Compute.CarbonGrams}} · {{printf "%.1f" .Compute.CarbonGrams}} gCO2e{{end}}</td></tr>
+<tr><td>Score drift</td><td>{{if .DriftFlagged}}<b style="color:#c62828;">{{.DriftStatus}}</b>{{else}}{{.DriftStatus}}{{end}} (PSI {{printf "%.3f" .DriftPSI}})</td></tr>
+</table>
+{{with .Disclaimer}}<p style="color:#666; font-size: small;">{{.}}</p>{{end}}
+</body></html>
+`))
+
+func formatPercent(v float64) string {
+	return fmt.Sprintf("%.1f%%", v*100)
+}
@@ -0,0 +1,207 @@
+// backend/internal/rescore/rescore.go
+/*
+ * This file implements bulk re-scoring of historical predictions.
+ *
+ * Before every model promotion we re-run a cohort of stored images through
+ * the candidate model and compare its scores with what the serving model
+ * originally returned. The result is a comparison table (one row per
+ * study) plus a summary of score deltas and label flips.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package rescore
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+	"github.com/josephed37/mammoscan-AI/backend/internal/store"
+	"gorgonia.org/tensor"
+)
+
+// Cohort selects the stored predictions to re-score.
+type Cohort struct {
+	Tenant        string    `json:"tenant,omitempty"`
+	From          time.Time `json:"from,omitempty"`
+	To            time.Time `json:"to,omitempty"`
+	PredictionIDs []string  `json:"prediction_ids,omitempty"`
+	Limit         int       `json:"limit,omitempty"`
+}
+
+// Params describes a re-scoring run.
+type Params struct {
+	// ModelRef locates the candidate model (local path or gs:// URI).
+	ModelRef  string  `json:"model_ref" binding:"required"`
+	ModelName string  `json:"model_name"`
+	Threshold float64 `json:"threshold"`
+	Cohort    Cohort  `json:"cohort"`
+}
+
+// Row compares one study under the old and new model.
+type Row struct {
+	PredictionID string    `json:"prediction_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	OldModel     string    `json:"old_model"`
+	OldScore     float64   `json:"old_score"`
+	OldLabel     string    `json:"old_label"`
+	NewScore     float64   `json:"new_score"`
+	NewLabel     string    `json:"new_label"`
+	Delta        float64   `json:"delta"`
+	Flipped      bool      `json:"flipped"`
+}
+
+// Summary aggregates a comparison.
+type Summary struct {
+	CohortSize     int     `json:"cohort_size"`
+	Scored         int     `json:"scored"`
+	SkippedNoImage int     `json:"skipped_no_image"`
+	Failed         int     `json:"failed"`
+	Flips          int     `json:"flips"`
+	NegativeToPos  int     `json:"negative_to_positive"`
+	PositiveToNeg  int     `json:"positive_to_negative"`
+	MeanAbsDelta   float64 `json:"mean_abs_delta"`
+	MaxAbsDelta    float64 `json:"max_abs_delta"`
+	OldPositivity  float64 `json:"old_positivity_rate"`
+	NewPositivity  float64 `json:"new_positivity_rate"`
+}
+
+// Comparison is the result of a re-scoring job.
+type Comparison struct {
+	NewModel     string  `json:"new_model"`
+	NewThreshold float64 `json:"new_threshold"`
+	Summary      Summary `json:"summary"`
+	Rows         []Row   `json:"rows"`
+}
+
+// Predictor is the candidate model.
+type Predictor interface {
+	Predict(input tensor.Tensor) ([]float32, error)
+}
+
+// Deps are the services a run needs.
+type Deps struct {
+	Store         store.Store
+	Images        store.ImageStore
+	DecodeOptions preprocess.Options
+}
+
+// Run re-scores the cohort with engine and builds the comparison.
+func Run(ctx context.Context, p Params, engine Predictor, deps Deps, report func(float64)) (*Comparison, error) {
+	records, err := deps.Store.Find(ctx, store.Filter{
+		Tenant:      p.Cohort.Tenant,
+		IDs:         p.Cohort.PredictionIDs,
+		CreatedFrom: p.Cohort.From,
+		CreatedTo:   p.Cohort.To,
+		Limit:       p.Cohort.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("select cohort: %w", err)
+	}
+
+	cmp := &Comparison{NewModel: p.ModelName, NewThreshold: p.Threshold}
+	cmp.Summary.CohortSize = len(records)
+
+	var sumAbs float64
+	var oldPos, newPos int
+	for i, rec := range records {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		report(float64(i) / float64(len(records)))
+
+		data, err := deps.Images.GetImage(ctx, rec.PredictionID)
+		if errors.Is(err, store.ErrNotFound) {
+			cmp.Summary.SkippedNoImage++
+			continue
+		}
+		if err != nil {
+			cmp.Summary.Failed++
+			continue
+		}
+
+		img, err := preprocess.DecodeImageBytes(data, deps.DecodeOptions)
+		if err != nil {
+			cmp.Summary.Failed++
+			continue
+		}
+		out, err := engine.Predict(preprocess.TensorFor(engine, img))
+		if err != nil {
+			cmp.Summary.Failed++
+			continue
+		}
+
+		newScore := float64(out[0])
+		row := Row{
+			PredictionID: rec.PredictionID,
+			CreatedAt:    rec.CreatedAt,
+			OldModel:     rec.ModelName,
+			OldScore:     rec.ConfidenceScore,
+			OldLabel:     rec.Prediction,
+			NewScore:     newScore,
+			NewLabel:     models.LabelFor(newScore, p.Threshold),
+			Delta:        newScore - rec.ConfidenceScore,
+		}
+		row.Flipped = row.NewLabel != row.OldLabel
+		cmp.Rows = append(cmp.Rows, row)
+
+		// --- Accumulate the summary ---
+		cmp.Summary.Scored++
+		abs := math.Abs(row.Delta)
+		sumAbs += abs
+		cmp.Summary.MaxAbsDelta = math.Max(cmp.Summary.MaxAbsDelta, abs)
+		if row.OldLabel == models.LabelCancer {
+			oldPos++
+		}
+		if row.NewLabel == models.LabelCancer {
+			newPos++
+		}
+		if row.Flipped {
+			cmp.Summary.Flips++
+			if row.NewLabel == models.LabelCancer {
+				cmp.Summary.NegativeToPos++
+			} else {
+				cmp.Summary.PositiveToNeg++
+			}
+		}
+	}
+
+	if n := cmp.Summary.Scored; n > 0 {
+		cmp.Summary.MeanAbsDelta = sumAbs / float64(n)
+		cmp.Summary.OldPositivity = float64(oldPos) / float64(n)
+		cmp.Summary.NewPositivity = float64(newPos) / float64(n)
+	}
+	return cmp, nil
+}
+
+// CSV renders the comparison table for spreadsheets.
+func (c *Comparison) CSV() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"prediction_id", "created_at", "old_model", "old_score", "old_label", "new_score", "new_label", "delta", "flipped"})
+	for _, r := range c.Rows {
+		w.Write([]string{
+			r.PredictionID,
+			r.CreatedAt.Format(time.RFC3339),
+			r.OldModel,
+			strconv.FormatFloat(r.OldScore, 'f', 6, 64),
+			r.OldLabel,
+			strconv.FormatFloat(r.NewScore, 'f', 6, 64),
+			r.NewLabel,
+			strconv.FormatFloat(r.Delta, 'f', 6, 64),
+			strconv.FormatBool(r.Flipped),
+		})
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,120 @@
+// backend/internal/residency/residency.go
+/*
+ * This file contains the data-residency policy.
+ *
+ * Some tenants (e.g. EU hospitals) must not have data leave their region.
+ * A deployment declares the region it runs in, the region each pinned
+ * tenant belongs to, and which remote endpoints count as "in" each region:
+ *
+ *   {
+ *     "region": "eu",
+ *     "tenants": {"clinic-berlin": "eu", "acme-boston": "us"},
+ *     "endpoints": {
+ *       "eu": ["gs://mammoscan-eu-models/", "https://sync.eu.mammoscan.example/"]
+ *     }
+ *   }
+ *
+ * Every backend the deployment writes to or loads from must resolve to the
+ * deployment's region, and requests from tenants pinned elsewhere are
+ * refused. Local paths are always in-region.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package residency
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrViolation is returned (wrapped) for any residency breach.
+var ErrViolation = errors.New("data residency violation")
+
+// Policy is a loaded residency configuration. A nil Policy allows
+// everything, so callers need not check whether one is configured.
+type Policy struct {
+	// Region is where this deployment runs.
+	Region string `json:"region"`
+	// Tenants maps a tenant ID to the region its data must stay in.
+	Tenants map[string]string `json:"tenants"`
+	// Endpoints maps a region to the URI prefixes located in it.
+	Endpoints map[string][]string `json:"endpoints"`
+}
+
+// Load reads and validates a policy file.
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if p.Region == "" {
+		return nil, fmt.Errorf("%s: region is required", path)
+	}
+	for tenant, region := range p.Tenants {
+		if region == "" {
+			return nil, fmt.Errorf("%s: tenant %q has no region", path, tenant)
+		}
+	}
+	return &p, nil
+}
+
+// RegionOf returns the region a backend URI is located in, or "" if it
+// matches no configured endpoint. Local paths, file:// URIs and stdout are
+// in the deployment's own region.
+func (p *Policy) RegionOf(uri string) string {
+	if uri == "stdout" || strings.HasPrefix(uri, "file://") || !strings.Contains(uri, "://") {
+		return p.Region
+	}
+	for region, prefixes := range p.Endpoints {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(uri, prefix) {
+				return region
+			}
+		}
+	}
+	return ""
+}
+
+// CheckBackend verifies that a backend (named for error messages) stays
+// in the deployment's region. Empty URIs are unconfigured and allowed.
+func (p *Policy) CheckBackend(name, uri string) error {
+	if p == nil || uri == "" {
+		return nil
+	}
+	region := p.RegionOf(uri)
+	if region == "" {
+		return fmt.Errorf("%w: %s %s is not in any configured region", ErrViolation, name, redact(uri))
+	}
+	if region != p.Region {
+		return fmt.Errorf("%w: %s %s is in region %q, deployment is in %q", ErrViolation, name, redact(uri), region, p.Region)
+	}
+	return nil
+}
+
+// CheckTenant verifies that this deployment may process a tenant's data.
+// Tenants without a pinned region are always allowed.
+func (p *Policy) CheckTenant(tenant string) error {
+	if p == nil {
+		return nil
+	}
+	if region, ok := p.Tenants[tenant]; ok && region != p.Region {
+		return fmt.Errorf("%w: tenant %q is pinned to region %q, this deployment is in %q", ErrViolation, tenant, region, p.Region)
+	}
+	return nil
+}
+
+// redact strips any query string, which may carry credentials.
+func redact(uri string) string {
+	uri, _, _ = strings.Cut(uri, "?")
+	return uri
+}
@@ -0,0 +1,78 @@
+// backend/internal/secrets/gcp.go
+/*
+ * This file contains the GCP Secret Manager provider.
+ *
+ * References name a secret, optionally with a version:
+ * `projects/<project>/secrets/<name>[/versions/<version>]`. The latest
+ * version is used when none is given, so rotating in Secret Manager is
+ * picked up on the next refresh.
+ */
+
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// GCPSecretManager reads secrets over the Secret Manager REST API.
+type GCPSecretManager struct {
+	// AccessToken returns an OAuth2 access token with the cloud-platform
+	// scope. The build profile supplies it from Application Default
+	// Credentials, so this package does not link the Google SDK.
+	AccessToken func(ctx context.Context) (string, error)
+
+	Client *http.Client
+}
+
+// Fetch accesses a secret version and returns its payload.
+func (g *GCPSecretManager) Fetch(ctx context.Context, ref string) (string, error) {
+	if !strings.HasPrefix(ref, "projects/") || !strings.Contains(ref, "/secrets/") {
+		return "", fmt.Errorf("invalid secret manager reference %q", ref)
+	}
+	if !strings.Contains(ref, "/versions/") {
+		ref += "/versions/latest"
+	}
+	token, err := g.AccessToken(ctx)
+	if err != nil {
+		return "", fmt.Errorf("gcp credentials: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://secretmanager.googleapis.com/v1/"+ref+":access", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	client := g.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("secret manager returned %s for %s", resp.Status, ref)
+	}
+
+	var body struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("decode secret manager response: %w", err)
+	}
+	value, err := base64.StdEncoding.DecodeString(body.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("decode secret payload: %w", err)
+	}
+	return string(value), nil
+}
@@ -0,0 +1,157 @@
+// backend/internal/secrets/secrets.go
+/*
+ * This file contains the secret manager.
+ *
+ * Secrets are configured through the usual environment variables, but the
+ * value may be a reference to an external secret store instead of the
+ * secret itself:
+ *
+ *   ADMIN_TOKEN=vault:secret/data/mammoscan#admin_token
+ *   SYNC_AUTH_TOKEN=gcpsm:projects/mammoscan/secrets/sync-token
+ *
+ * Plain values are still accepted for local development. Referenced
+ * secrets are fetched at startup and re-fetched periodically; consumers
+ * hold a getter rather than the value so rotated secrets take effect
+ * without a restart.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Provider fetches a secret from an external store. The reference is the
+// part of the configured value after the "<scheme>:" prefix.
+type Provider interface {
+	Fetch(ctx context.Context, ref string) (string, error)
+}
+
+// Schemes lists the reference prefixes understood by this package. A value
+// using one of them must resolve through a configured provider; it is
+// never mistaken for a literal secret.
+var Schemes = []string{"vault", "gcpsm"}
+
+// Getter returns the current value of a secret.
+type Getter func() string
+
+// Static returns a Getter for a fixed value.
+func Static(value string) Getter {
+	return func() string { return value }
+}
+
+// Manager resolves secret references and keeps them up to date.
+type Manager struct {
+	providers map[string]Provider
+
+	mu      sync.RWMutex
+	values  map[string]string
+	sources map[string]source
+}
+
+type source struct {
+	provider Provider
+	scheme   string
+	ref      string
+}
+
+// NewManager creates a manager with providers keyed by scheme (e.g.
+// "vault", "gcpsm").
+func NewManager(providers map[string]Provider) *Manager {
+	return &Manager{
+		providers: providers,
+		values:    make(map[string]string),
+		sources:   make(map[string]source),
+	}
+}
+
+// Resolve returns a Getter for the named secret. A raw value with a known
+// scheme prefix is fetched from that provider; anything else is used as is.
+func (m *Manager) Resolve(ctx context.Context, name, raw string) (Getter, error) {
+	scheme, ref, ok := strings.Cut(raw, ":")
+	if !ok || !slices.Contains(Schemes, scheme) {
+		return Static(raw), nil
+	}
+	provider, configured := m.providers[scheme]
+	if !configured {
+		return nil, fmt.Errorf("secret %s references %s but no %s provider is configured", name, scheme, scheme)
+	}
+
+	value, err := provider.Fetch(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("secret %s (%s): %w", name, scheme, err)
+	}
+
+	m.mu.Lock()
+	m.values[name] = value
+	m.sources[name] = source{provider: provider, scheme: scheme, ref: ref}
+	m.mu.Unlock()
+
+	return func() string {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		return m.values[name]
+	}, nil
+}
+
+// Refresh re-fetches every referenced secret. A secret that fails to
+// refresh keeps its previous value.
+func (m *Manager) Refresh(ctx context.Context) error {
+	m.mu.RLock()
+	sources := make(map[string]source, len(m.sources))
+	for name, s := range m.sources {
+		sources[name] = s
+	}
+	m.mu.RUnlock()
+
+	var failed []string
+	for name, s := range sources {
+		value, err := s.provider.Fetch(ctx, s.ref)
+		if err != nil {
+			log.Printf("secrets: refresh %s (%s): %v", name, s.scheme, err)
+			failed = append(failed, name)
+			continue
+		}
+		m.mu.Lock()
+		if m.values[name] != value {
+			log.Printf("secrets: %s rotated", name)
+			m.values[name] = value
+		}
+		m.mu.Unlock()
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("refresh failed for %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// Len reports how many secrets are sourced from external stores.
+func (m *Manager) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.sources)
+}
+
+// Run refreshes all secrets every interval until ctx is cancelled.
+func (m *Manager) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Refresh(ctx)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// backend/internal/secrets/vault.go
+/*
+ * This file contains the HashiCorp Vault provider.
+ *
+ * References have the form `<path>#<field>`, e.g.
+ * `secret/data/mammoscan#admin_token`. Both KV v1 and KV v2 responses are
+ * understood. Authentication uses a Vault token.
+ */
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Vault reads secrets over Vault's HTTP API.
+type Vault struct {
+	// Addr is the Vault server address, e.g. https://vault.internal:8200.
+	Addr string
+	// Token returns the Vault token to authenticate with. It is a function
+	// so a token file renewed by a sidecar agent is re-read on each fetch.
+	Token func() (string, error)
+	// Namespace is the optional Vault Enterprise namespace.
+	Namespace string
+
+	Client *http.Client
+}
+
+// Fetch reads one field of a Vault secret.
+func (v *Vault) Fetch(ctx context.Context, ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || field == "" {
+		return "", fmt.Errorf("invalid vault reference %q (want path#field)", ref)
+	}
+	token, err := v.Token()
+	if err != nil {
+		return "", fmt.Errorf("vault token: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(v.Addr, "/")+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if v.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+
+	client := v.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned %s for %s", resp.Status, path)
+	}
+
+	var body struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("decode vault response: %w", err)
+	}
+	fields := body.Data
+	// KV v2 nests the secret under data.data.
+	if nested, ok := body.Data["data"]; ok {
+		var inner map[string]json.RawMessage
+		if json.Unmarshal(nested, &inner) == nil {
+			fields = inner
+		}
+	}
+
+	raw, ok := fields[field]
+	if !ok {
+		return "", fmt.Errorf("field %q not found at %s", field, path)
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", fmt.Errorf("field %q at %s is not a string", field, path)
+	}
+	return value, nil
+}
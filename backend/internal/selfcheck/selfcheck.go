@@ -0,0 +1,103 @@
+// backend/internal/selfcheck/selfcheck.go
+/*
+ * This file implements the startup self-check.
+ *
+ * Before the server accepts traffic it runs a fixed list of checks (model
+ * loaded, a sanity inference on a synthetic image, storage reachable,
+ * configuration valid) and produces a machine-readable report. Deploy
+ * pipelines read the report from the log or from GET /selfcheck and gate
+ * promotion on its status.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package selfcheck
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"math"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/preprocess"
+	"gorgonia.org/tensor"
+)
+
+// Check outcomes.
+const (
+	Pass = "pass"
+	Fail = "fail"
+)
+
+// Check is one named step. Run returns a short human-readable detail on
+// success, or the reason for failure.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) (string, error)
+}
+
+// Result is the outcome of one check.
+type Result struct {
+	Name           string `json:"name"`
+	Status         string `json:"status"`
+	Detail         string `json:"detail,omitempty"`
+	DurationMillis int64  `json:"duration_ms"`
+}
+
+// Report is the outcome of a self-check run. Status is Pass only if every
+// check passed.
+type Report struct {
+	Status       string    `json:"status"`
+	BuildProfile string    `json:"build_profile"`
+	StartedAt    time.Time `json:"started_at"`
+	Checks       []Result  `json:"checks"`
+}
+
+// Run executes the checks in order. A failing check does not stop the
+// run: the report should show everything that is wrong at once.
+func Run(ctx context.Context, buildProfile string, checks []Check) Report {
+	report := Report{Status: Pass, BuildProfile: buildProfile, StartedAt: time.Now().UTC()}
+	for _, check := range checks {
+		start := time.Now()
+		detail, err := check.Run(ctx)
+		result := Result{Name: check.Name, Status: Pass, Detail: detail, DurationMillis: time.Since(start).Milliseconds()}
+		if err != nil {
+			result.Status, result.Detail = Fail, err.Error()
+			report.Status = Fail
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
+
+// Predictor is the part of an inference engine the sanity check uses.
+type Predictor interface {
+	Predict(input tensor.Tensor) ([]float32, error)
+}
+
+// SanityInference scores a synthetic mid-grey image and checks that the
+// engine returns a probability. It catches models that load but cannot
+// run on this backend (wrong input shape, unsupported operators).
+func SanityInference(p Predictor) func(context.Context) (string, error) {
+	return func(context.Context) (string, error) {
+		img := image.NewGray(image.Rect(0, 0, 256, 256))
+		for i := range img.Pix {
+			img.Pix[i] = 128
+		}
+		out, err := p.Predict(preprocess.TensorFor(p, img))
+		if err != nil {
+			return "", err
+		}
+		if len(out) == 0 {
+			return "", fmt.Errorf("model returned no output")
+		}
+		score := float64(out[0])
+		if math.IsNaN(score) || score < 0 || score > 1 {
+			return "", fmt.Errorf("model returned %v, not a probability", score)
+		}
+		return fmt.Sprintf("synthetic image scored %.4f", score), nil
+	}
+}
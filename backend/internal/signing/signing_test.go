@@ -0,0 +1,113 @@
+package signing
+
+import (
+	"crypto/sha256"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func signedRequest(now time.Time, nonce string) Request {
+	body := sha256.Sum256([]byte(`{"study":"a"}`))
+	return Request{
+		Timestamp:  strconv.FormatInt(now.Unix(), 10),
+		Nonce:      nonce,
+		Method:     "POST",
+		URI:        "/api/v1/predict?async=true",
+		BodySHA256: body[:],
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1_790_000_000, 0)
+	r := signedRequest(now, "n-1")
+	sig := Sign("secret", r)
+
+	tampered := func(edit func(*Request)) string {
+		c := r
+		edit(&c)
+		return Sign("secret", c)
+	}
+	cases := []struct {
+		name      string
+		req       Request
+		signature string
+		now       time.Time
+		want      error
+	}{
+		{"no signature", r, "", now, ErrMissing},
+		{"no timestamp", Request{Nonce: "n", Method: "GET", URI: "/"}, sig, now, ErrMissing},
+		{"no nonce", Request{Timestamp: r.Timestamp, Method: "GET", URI: "/"}, sig, now, ErrMissing},
+		{"long nonce", signedRequest(now, strings.Repeat("n", maxNonce+1)), sig, now, ErrInvalid},
+		{"malformed timestamp", Request{Timestamp: "yesterday", Nonce: "n"}, sig, now, ErrInvalid},
+		{"too old", r, sig, now.Add(DefaultSkew + time.Second), ErrSkew},
+		{"from the future", r, sig, now.Add(-DefaultSkew - time.Second), ErrSkew},
+		{"wrong secret", r, Sign("other", r), now, ErrInvalid},
+		{"other method", r, tampered(func(c *Request) { c.Method = "GET" }), now, ErrInvalid},
+		{"other query", r, tampered(func(c *Request) { c.URI = "/api/v1/predict" }), now, ErrInvalid},
+		{"other body", r, tampered(func(c *Request) { c.BodySHA256 = make([]byte, 32) }), now, ErrInvalid},
+		{"unknown version", r, "v2=" + strings.TrimPrefix(sig, "v1="), now, ErrInvalid},
+		{"not hex", r, "v1=zz", now, ErrInvalid},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewVerifier(0).Verify("key-1", "secret", tc.req, tc.signature, tc.now)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("Verify error = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestVerifyRotatedSecret(t *testing.T) {
+	now := time.Unix(1_790_000_000, 0)
+	r := signedRequest(now, "n-1")
+	sig := Sign("old", r) + ", " + Sign("new", r)
+	if err := NewVerifier(0).Verify("key-1", "new", r, sig, now); err != nil {
+		t.Errorf("Verify with the new secret among several signatures: %v", err)
+	}
+}
+
+func TestVerifyRejectsReplays(t *testing.T) {
+	now := time.Unix(1_790_000_000, 0)
+	v := NewVerifier(time.Minute)
+	r := signedRequest(now, "n-1")
+	sig := Sign("secret", r)
+
+	if err := v.Verify("key-1", "secret", r, sig, now); err != nil {
+		t.Fatalf("first Verify: %v", err)
+	}
+	if err := v.Verify("key-1", "secret", r, sig, now.Add(30*time.Second)); !errors.Is(err, ErrReplay) {
+		t.Errorf("replay within the window: error = %v, want ErrReplay", err)
+	}
+	// Nonces are scoped to the key.
+	if err := v.Verify("key-2", "secret", r, sig, now); err != nil {
+		t.Errorf("same nonce from another key: %v", err)
+	}
+	// A failed signature does not burn the nonce.
+	fresh := signedRequest(now, "n-2")
+	if err := v.Verify("key-1", "secret", fresh, Sign("wrong", fresh), now); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("bad signature: error = %v, want ErrInvalid", err)
+	}
+	if err := v.Verify("key-1", "secret", fresh, Sign("secret", fresh), now); err != nil {
+		t.Errorf("nonce after a failed attempt: %v", err)
+	}
+
+	// Past the window the replay fails the timestamp check instead, and
+	// the nonce is swept.
+	later := now.Add(2 * time.Minute)
+	if err := v.Verify("key-1", "secret", r, sig, later); !errors.Is(err, ErrSkew) {
+		t.Errorf("replay after the window: error = %v, want ErrSkew", err)
+	}
+	next := signedRequest(later, "n-3")
+	if err := v.Verify("key-1", "secret", next, Sign("secret", next), later); err != nil {
+		t.Fatalf("Verify after the window: %v", err)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, ok := v.seen["key-1/n-1"]; ok {
+		t.Error("expired nonce not swept")
+	}
+}
@@ -0,0 +1,156 @@
+// backend/internal/spool/flusher.go
+/*
+ * This file contains the write-behind flush loop.
+ *
+ * A Flusher drains a Queue into a local sink (the prediction database,
+ * the audit log) the way the Forwarder drains one to the central
+ * service: records are applied oldest first and only removed once
+ * applied, so a sink that is down loses nothing, it only falls behind.
+ * Writers Wake the flusher after queueing so records normally land within
+ * moments; while the sink fails the loop backs off.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package spool
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// FlusherConfig controls how often a queue is drained.
+type FlusherConfig struct {
+	// Name identifies the sink in logs.
+	Name string
+	// Interval is the delay between passes when nobody wakes the flusher.
+	Interval time.Duration
+	// MaxBackoff caps the delay between attempts while the sink fails.
+	MaxBackoff time.Duration
+	// BatchSize limits how many records are applied per pass.
+	BatchSize int
+}
+
+// ApplyFunc writes one record to the sink.
+type ApplyFunc func(ctx context.Context, rec Record) error
+
+// Flusher applies queued records to a sink in the background.
+type Flusher struct {
+	queue *Queue
+	apply ApplyFunc
+	cfg   FlusherConfig
+	wake  chan struct{}
+
+	mu        sync.Mutex
+	lastFlush time.Time
+	lastErr   error
+}
+
+// NewFlusher creates a Flusher applying the records of queue with apply.
+func NewFlusher(queue *Queue, apply ApplyFunc, cfg FlusherConfig) *Flusher {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = time.Minute
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	return &Flusher{queue: queue, apply: apply, cfg: cfg, wake: make(chan struct{}, 1)}
+}
+
+// Wake asks for a pass as soon as possible. It never blocks.
+func (f *Flusher) Wake() {
+	select {
+	case f.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run flushes until ctx is cancelled, starting with whatever a previous
+// process left in the queue. It is meant to be started in its own
+// goroutine.
+func (f *Flusher) Run(ctx context.Context) {
+	if n := f.queue.Len(); n > 0 {
+		log.Printf("%s: %d queued write(s) left from a previous run", f.cfg.Name, n)
+	}
+	delay := time.Duration(0)
+	failing := false
+	for {
+		wake := f.wake
+		if failing {
+			// Writers do not hurry a sink that is down.
+			wake = nil
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		case <-wake:
+		}
+
+		applied, err := f.FlushOnce(ctx)
+		switch {
+		case err != nil:
+			// Exponential backoff while the sink is unavailable.
+			delay = min(max(2*delay, f.cfg.Interval), f.cfg.MaxBackoff)
+			if !failing {
+				log.Printf("%s: writes queued on disk (%d pending, retry in %s): %v", f.cfg.Name, f.queue.Len(), delay, err)
+			}
+			failing = true
+		case applied == f.cfg.BatchSize:
+			// More may be waiting; go again at once.
+			delay = 0
+		default:
+			if failing {
+				log.Printf("%s: writes flowing again, %d pending", f.cfg.Name, f.queue.Len())
+			}
+			failing = false
+			delay = f.cfg.Interval
+		}
+	}
+}
+
+// FlushOnce applies one batch of pending records and returns how many
+// were applied. It stops at the first failure so ordering is preserved.
+func (f *Flusher) FlushOnce(ctx context.Context) (int, error) {
+	records, err := f.queue.Pending(f.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	applied := 0
+	for _, rec := range records {
+		if err := f.apply(ctx, rec); err != nil {
+			f.mu.Lock()
+			f.lastErr = err
+			f.mu.Unlock()
+			return applied, err
+		}
+		if err := f.queue.Ack(rec.ID); err != nil {
+			return applied, err
+		}
+		applied++
+	}
+	f.mu.Lock()
+	f.lastFlush = time.Now().UTC()
+	f.lastErr = nil
+	f.mu.Unlock()
+	return applied, nil
+}
+
+// Status reports the last complete flush and the last error, if any.
+func (f *Flusher) Status() (time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lastFlush, f.lastErr
+}
+
+// Pending returns the number of records not yet applied.
+func (f *Flusher) Pending() int {
+	return f.queue.Len()
+}
@@ -0,0 +1,199 @@
+// backend/internal/spool/forwarder.go
+/*
+ * This file contains the store-and-forward sync loop.
+ *
+ * The Forwarder periodically drains a Queue by POSTing each record to the
+ * central service. Delivery is at-least-once: a record is only removed
+ * after a 2xx (or 409 "already have it") response, and the record ID is
+ * sent as an idempotency key so the central service can discard replays.
+ * When the network is down the loop backs off and retries later; nothing
+ * is lost in the meantime.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package spool
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/tenantkey"
+)
+
+// ForwarderConfig controls where and how often records are synced.
+type ForwarderConfig struct {
+	// Endpoint is the central service URL that receives records.
+	Endpoint string
+	// AuthToken, if set, returns the bearer token to send. It is called
+	// for every request so rotated credentials are picked up.
+	AuthToken func() string
+	// DeviceID identifies this unit to the central service.
+	DeviceID string
+	// Interval is the delay between sync passes while online.
+	Interval time.Duration
+	// MaxBackoff caps the delay between attempts while offline.
+	MaxBackoff time.Duration
+	// BatchSize limits how many records are sent per pass.
+	BatchSize int
+	// Keys, when set, opens records sealed at rest (see sealed.go) before
+	// they are sent.
+	Keys *tenantkey.Keyring
+}
+
+// Forwarder delivers queued records to the central service.
+type Forwarder struct {
+	queue  *Queue
+	cfg    ForwarderConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	lastSync time.Time
+	lastErr  error
+}
+
+// envelope is the wire format posted to the central service.
+type envelope struct {
+	DeviceID   string `json:"device_id,omitempty"`
+	Record     Record `json:"record"`
+	BlobBase64 string `json:"blob_base64,omitempty"`
+}
+
+// NewForwarder creates a Forwarder for the given queue.
+func NewForwarder(queue *Queue, cfg ForwarderConfig) *Forwarder {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 10 * time.Minute
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 50
+	}
+	return &Forwarder{
+		queue:  queue,
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Run syncs until ctx is cancelled. It is meant to be started in its own
+// goroutine.
+func (f *Forwarder) Run(ctx context.Context) {
+	delay := f.cfg.Interval
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		sent, err := f.SyncOnce(ctx)
+		if err != nil {
+			// Exponential backoff while the central service is unreachable.
+			delay *= 2
+			if delay > f.cfg.MaxBackoff {
+				delay = f.cfg.MaxBackoff
+			}
+			log.Printf("Offline sync paused (%d pending, retry in %s): %v", f.queue.Len(), delay, err)
+			continue
+		}
+		if sent > 0 {
+			log.Printf("Offline sync delivered %d record(s), %d pending", sent, f.queue.Len())
+		}
+		delay = f.cfg.Interval
+	}
+}
+
+// SyncOnce sends one batch of pending records and returns how many were
+// delivered. It stops at the first failure so ordering is preserved.
+func (f *Forwarder) SyncOnce(ctx context.Context) (int, error) {
+	records, err := f.queue.Pending(f.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, rec := range records {
+		if err := f.send(ctx, rec); err != nil {
+			f.mu.Lock()
+			f.lastErr = err
+			f.mu.Unlock()
+			return sent, err
+		}
+		if err := f.queue.Ack(rec.ID); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	f.mu.Lock()
+	f.lastSync = time.Now().UTC()
+	f.lastErr = nil
+	f.mu.Unlock()
+	return sent, nil
+}
+
+// Status reports the last successful sync time and the last error, if any.
+func (f *Forwarder) Status() (time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lastSync, f.lastErr
+}
+
+func (f *Forwarder) send(ctx context.Context, rec Record) error {
+	rec, err := Unseal(f.cfg.Keys, rec)
+	if err != nil {
+		return err
+	}
+	env := envelope{DeviceID: f.cfg.DeviceID, Record: rec}
+	if rec.HasBlob {
+		blob, err := f.queue.Blob(rec.ID)
+		if err != nil {
+			return fmt.Errorf("read blob for %s: %w", rec.ID, err)
+		}
+		if blob, err = UnsealBlob(f.cfg.Keys, rec.ID, blob); err != nil {
+			return err
+		}
+		env.BlobBase64 = base64.StdEncoding.EncodeToString(blob)
+	}
+
+	body, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("encode envelope: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", rec.ID)
+	if f.cfg.AuthToken != nil {
+		if token := f.cfg.AuthToken(); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post record %s: %w", rec.ID, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	// 409 Conflict means the central service already holds this record.
+	if resp.StatusCode/100 == 2 || resp.StatusCode == http.StatusConflict {
+		return nil
+	}
+	return fmt.Errorf("post record %s: unexpected status %d", rec.ID, resp.StatusCode)
+}
@@ -0,0 +1,236 @@
+// backend/internal/spool/spool.go
+/*
+ * This file implements a small durable, directory-backed queue.
+ *
+ * Edge deployments (mobile screening vans) are offline for hours at a time.
+ * Every record that must eventually reach the central service is written
+ * to local disk first and only removed once the central service has
+ * acknowledged it. Because each record is a separate file that is written
+ * atomically (temp file + rename), a crash or power loss never leaves a
+ * half-written record behind. A record file that cannot be decoded all the
+ * same (a disk fault, a filesystem that lost the rename's data on power
+ * loss) is moved to the quarantine subdirectory and logged, so one bad
+ * file never stops the records queued behind it.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package spool
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	recordSuffix = ".json"
+	blobSuffix   = ".bin"
+)
+
+// QuarantineDir is the subdirectory of a queue holding the record files
+// that could not be decoded.
+const QuarantineDir = "quarantine"
+
+// Record is a single unit of work waiting to be forwarded. The ID is chosen
+// by the producer and must be globally unique (we use UUIDv7) so that the
+// central service can deduplicate replays from any number of devices.
+type Record struct {
+	ID        string          `json:"id"`
+	Kind      string          `json:"kind"`
+	CreatedAt time.Time       `json:"created_at"`
+	Payload   json.RawMessage `json:"payload"`
+	// HasBlob reports whether a binary attachment (e.g. the source image)
+	// is stored alongside the record.
+	HasBlob bool `json:"has_blob"`
+}
+
+// Queue is a FIFO of Records persisted in a single directory.
+type Queue struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// Open creates (if needed) and opens a queue rooted at dir.
+func Open(dir string) (*Queue, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create spool dir: %w", err)
+	}
+	return &Queue{dir: dir}, nil
+}
+
+// Dir returns the directory backing the queue.
+func (q *Queue) Dir() string {
+	return q.dir
+}
+
+// Enqueue durably stores a record and its optional binary attachment.
+// The blob is written before the record so that a visible record always
+// has its attachment available.
+func (q *Queue) Enqueue(rec Record, blob []byte) error {
+	if rec.ID == "" || strings.ContainsAny(rec.ID, `/\`) {
+		return fmt.Errorf("invalid record id %q", rec.ID)
+	}
+	if rec.CreatedAt.IsZero() {
+		rec.CreatedAt = time.Now().UTC()
+	}
+	rec.HasBlob = len(blob) > 0
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if rec.HasBlob {
+		if err := writeAtomic(q.path(rec.ID, blobSuffix), blob); err != nil {
+			return fmt.Errorf("write blob: %w", err)
+		}
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode record: %w", err)
+	}
+	if err := writeAtomic(q.path(rec.ID, recordSuffix), data); err != nil {
+		return fmt.Errorf("write record: %w", err)
+	}
+	return nil
+}
+
+// Pending returns up to limit queued records, oldest first. A limit of
+// zero or less returns everything. Undecodable records are quarantined
+// and left out.
+func (q *Queue) Pending(limit int) ([]Record, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	names, err := q.recordNames()
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for _, name := range names {
+		if limit > 0 && len(records) >= limit {
+			break
+		}
+		data, err := os.ReadFile(filepath.Join(q.dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("read record: %w", err)
+		}
+		var rec Record
+		if err := json.Unmarshal(data, &rec); err != nil {
+			q.quarantine(name, err)
+			continue
+		}
+		if rec.ID+recordSuffix != name {
+			q.quarantine(name, fmt.Errorf("record names id %q", rec.ID))
+			continue
+		}
+		records = append(records, rec)
+	}
+
+	// Sort by creation time, falling back to ID (UUIDv7 is time-ordered).
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].CreatedAt.Equal(records[j].CreatedAt) {
+			return records[i].ID < records[j].ID
+		}
+		return records[i].CreatedAt.Before(records[j].CreatedAt)
+	})
+	return records, nil
+}
+
+// Blob returns the binary attachment stored for a record.
+func (q *Queue) Blob(id string) ([]byte, error) {
+	return os.ReadFile(q.path(id, blobSuffix))
+}
+
+// Ack removes a record (and its attachment) once it has been delivered.
+func (q *Queue) Ack(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := os.Remove(q.path(id, recordSuffix)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove record: %w", err)
+	}
+	if err := os.Remove(q.path(id, blobSuffix)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove blob: %w", err)
+	}
+	return nil
+}
+
+// Len returns the number of records waiting in the queue.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	names, err := q.recordNames()
+	if err != nil {
+		return 0
+	}
+	return len(names)
+}
+
+// quarantine moves the record file name, and its attachment if any, out
+// of the queue; callers must hold q.mu.
+func (q *Queue) quarantine(name string, cause error) {
+	dir := filepath.Join(q.dir, QuarantineDir)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		log.Printf("spool: cannot quarantine unreadable record %s in %s: %v", name, q.dir, err)
+		return
+	}
+	id := strings.TrimSuffix(name, recordSuffix)
+	for _, file := range []string{name, id + blobSuffix} {
+		if err := os.Rename(filepath.Join(q.dir, file), filepath.Join(dir, file)); err != nil && !os.IsNotExist(err) {
+			log.Printf("spool: cannot quarantine %s in %s: %v", file, q.dir, err)
+		}
+	}
+	log.Printf("spool: unreadable record %s moved to %s: %v", name, dir, cause)
+}
+
+func (q *Queue) path(id, suffix string) string {
+	return filepath.Join(q.dir, id+suffix)
+}
+
+func (q *Queue) recordNames() ([]string, error) {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return nil, fmt.Errorf("list spool dir: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), recordSuffix) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// writeAtomic writes data to a temporary file in the same directory and
+// renames it into place, so readers never observe a partial file.
+func writeAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,273 @@
+// backend/internal/stats/stats.go
+/*
+ * This file implements the in-process service statistics collector.
+ *
+ * Every prediction (successful or not) is recorded here. The collector keeps
+ * cheap running totals plus bounded windows of recent latencies, scores and
+ * errors, which is enough to answer the questions an ops dashboard asks
+ * ("how busy are we, how slow, what broke recently, are scores drifting?")
+ * without an external metrics stack.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package stats
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Config sizes the rolling windows kept by the collector.
+type Config struct {
+	// Window is the number of recent predictions used for latency and
+	// drift statistics.
+	Window int
+	// ReferenceSize is the number of predictions used to establish the
+	// reference score distribution when no baseline is supplied.
+	ReferenceSize int
+	// MaxErrors is the number of recent errors retained.
+	MaxErrors int
+	// Baseline is an optional reference score histogram (see Bins).
+	Baseline []float64
+	// Encoder names the JSON codec, reported alongside memory statistics.
+	Encoder string
+	// OnDrift, when set, is called whenever the drift status becomes
+	// "significant". It runs on the recording goroutine and must not block.
+	OnDrift func(DriftStats)
+}
+
+// driftCheckInterval is how many predictions pass between drift checks
+// for OnDrift.
+const driftCheckInterval = 25
+
+// ErrorEntry is one recently failed request.
+type ErrorEntry struct {
+	Time    time.Time `json:"time"`
+	Status  int       `json:"status"`
+	Message string    `json:"message"`
+}
+
+// Collector aggregates prediction outcomes. It is safe for concurrent use.
+type Collector struct {
+	cfg     Config
+	started time.Time
+	memBase memoryCounters
+
+	mu          sync.Mutex
+	total       int64
+	positives   int64
+	failures    int64
+	lastSuccess time.Time
+	latencies   *ring
+	scores      *ring
+	reference   []float64
+	refCounts   []float64
+	errors      []ErrorEntry
+	driftStatus string
+	compute     ComputeStats
+}
+
+// New creates a collector.
+func New(cfg Config) *Collector {
+	if cfg.Window <= 0 {
+		cfg.Window = 1000
+	}
+	if cfg.ReferenceSize <= 0 {
+		cfg.ReferenceSize = 500
+	}
+	if cfg.MaxErrors <= 0 {
+		cfg.MaxErrors = 50
+	}
+	c := &Collector{
+		cfg:       cfg,
+		started:   time.Now().UTC(),
+		memBase:   readMemory(),
+		latencies: newRing(cfg.Window),
+		scores:    newRing(cfg.Window),
+	}
+	if len(cfg.Baseline) == Bins {
+		c.refCounts = append([]float64(nil), cfg.Baseline...)
+	}
+	return c
+}
+
+// RecordPrediction records a successful prediction.
+func (c *Collector) RecordPrediction(score float64, positive bool, latency time.Duration) {
+	if alert, ok := c.record(score, positive, latency); ok {
+		c.cfg.OnDrift(alert)
+	}
+}
+
+// record updates the totals and, every driftCheckInterval predictions,
+// reports a drift status that has just turned significant.
+func (c *Collector) record(score float64, positive bool, latency time.Duration) (DriftStats, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.total++
+	if positive {
+		c.positives++
+	}
+	c.lastSuccess = time.Now().UTC()
+	c.latencies.add(float64(latency.Milliseconds()))
+	c.scores.add(score)
+
+	// The first ReferenceSize scores become the drift reference unless an
+	// explicit baseline histogram was configured.
+	if c.refCounts == nil {
+		c.reference = append(c.reference, score)
+		if len(c.reference) >= c.cfg.ReferenceSize {
+			c.refCounts = histogram(c.reference)
+			c.reference = nil
+		}
+	}
+
+	if c.cfg.OnDrift == nil || c.total%driftCheckInterval != 0 {
+		return DriftStats{}, false
+	}
+	d := c.drift()
+	previous := c.driftStatus
+	c.driftStatus = d.Status
+	return d, d.Status == "significant" && previous != "significant"
+}
+
+// RecordCompute adds the compute time spent on one prediction and its
+// estimated energy (Wh) and emissions (gCO2e) to the totals.
+func (c *Collector) RecordCompute(d time.Duration, energyWh, carbonGrams float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.compute.Predictions++
+	c.compute.Seconds += d.Seconds()
+	c.compute.EnergyWh += energyWh
+	c.compute.CarbonGrams += carbonGrams
+}
+
+// RecordError records a failed request.
+func (c *Collector) RecordError(status int, message string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failures++
+	c.errors = append(c.errors, ErrorEntry{Time: time.Now().UTC(), Status: status, Message: message})
+	if len(c.errors) > c.cfg.MaxErrors {
+		c.errors = c.errors[len(c.errors)-c.cfg.MaxErrors:]
+	}
+}
+
+// Snapshot is a point-in-time view of the collector.
+type Snapshot struct {
+	StartedAt      time.Time    `json:"started_at"`
+	UptimeSeconds  int64        `json:"uptime_seconds"`
+	Predictions    int64        `json:"predictions"`
+	Positives      int64        `json:"positives"`
+	Errors         int64        `json:"errors"`
+	PositivityRate float64      `json:"positivity_rate"`
+	ErrorRate      float64      `json:"error_rate"`
+	LastSuccess    *time.Time   `json:"last_success,omitempty"`
+	Latency        LatencyStats `json:"latency_ms"`
+	Drift          DriftStats   `json:"drift"`
+	Memory         MemoryStats  `json:"memory"`
+	Compute        ComputeStats `json:"compute"`
+	RecentErrors   []ErrorEntry `json:"recent_errors"`
+}
+
+// ComputeStats totals the compute spent on predictions since start, with
+// its estimated energy use and emissions.
+type ComputeStats struct {
+	Predictions int64   `json:"predictions"`
+	Seconds     float64 `json:"seconds"`
+	EnergyWh    float64 `json:"energy_wh"`
+	CarbonGrams float64 `json:"carbon_g_co2e"`
+}
+
+// LatencyStats summarises recent request latency.
+type LatencyStats struct {
+	Samples int     `json:"samples"`
+	P50     float64 `json:"p50"`
+	P95     float64 `json:"p95"`
+	P99     float64 `json:"p99"`
+	Max     float64 `json:"max"`
+}
+
+// Snapshot returns the current statistics.
+func (c *Collector) Snapshot() Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := Snapshot{
+		StartedAt:     c.started,
+		UptimeSeconds: int64(time.Since(c.started).Seconds()),
+		Predictions:   c.total,
+		Positives:     c.positives,
+		Errors:        c.failures,
+		Compute:       c.compute,
+		RecentErrors:  append([]ErrorEntry{}, c.errors...),
+	}
+	if c.total > 0 {
+		s.PositivityRate = float64(c.positives) / float64(c.total)
+	}
+	if attempts := c.total + c.failures; attempts > 0 {
+		s.ErrorRate = float64(c.failures) / float64(attempts)
+	}
+	if !c.lastSuccess.IsZero() {
+		t := c.lastSuccess
+		s.LastSuccess = &t
+	}
+
+	lat := c.latencies.values()
+	sort.Float64s(lat)
+	s.Latency = LatencyStats{
+		Samples: len(lat),
+		P50:     percentile(lat, 0.50),
+		P95:     percentile(lat, 0.95),
+		P99:     percentile(lat, 0.99),
+	}
+	if len(lat) > 0 {
+		s.Latency.Max = lat[len(lat)-1]
+	}
+
+	s.Drift = c.drift()
+	s.Memory = readMemory().since(c.memBase, c.total)
+	s.Memory.Encoder = c.cfg.Encoder
+	return s
+}
+
+// percentile returns the p-th percentile of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(float64(len(sorted)-1) * p)
+	return sorted[idx]
+}
+
+// ring is a fixed-size circular buffer of float64 samples.
+type ring struct {
+	buf  []float64
+	next int
+	full bool
+}
+
+func newRing(size int) *ring {
+	return &ring{buf: make([]float64, size)}
+}
+
+func (r *ring) add(v float64) {
+	r.buf[r.next] = v
+	r.next = (r.next + 1) % len(r.buf)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+func (r *ring) values() []float64 {
+	if r.full {
+		return append([]float64(nil), r.buf...)
+	}
+	return append([]float64(nil), r.buf[:r.next]...)
+}
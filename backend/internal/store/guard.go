@@ -0,0 +1,108 @@
+// backend/internal/store/guard.go
+/*
+ * This file bounds how long callers wait on a store.
+ *
+ * Recording a prediction is best-effort, but a database that has slowed
+ * to a crawl used to hold every prediction response until the driver gave
+ * up. Guard puts a deadline on each call and a circuit breaker in front
+ * of the store: once the database keeps failing, calls fail at once with
+ * dependency.ErrOpen until a probe finds it healthy again.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package store
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/dependency"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// guarded is a Store behind a breaker and a per-call deadline.
+type guarded struct {
+	s       Store
+	breaker *dependency.Breaker
+	timeout time.Duration
+}
+
+// Guard wraps s so each call runs through breaker and, when timeout is
+// positive, gives up after timeout. Close is passed through, and so is
+// the event outbox of an OutboxStore.
+func Guard(s Store, breaker *dependency.Breaker, timeout time.Duration) Store {
+	g := &guarded{s: s, breaker: breaker, timeout: timeout}
+	if o, ok := s.(OutboxStore); ok {
+		return &guardedOutbox{guarded: g, o: o}
+	}
+	return g
+}
+
+// do runs fn under the breaker with the call's deadline. ErrNotFound, and
+// errors answer recognises, are answers rather than failures of the
+// database and do not count against it.
+func (g *guarded) do(ctx context.Context, fn func(ctx context.Context) error, answer func(error) bool) error {
+	if g.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.timeout)
+		defer cancel()
+	}
+	return g.breaker.Do(func() error { return fn(ctx) }, func(err error) bool {
+		return !errors.Is(err, ErrNotFound) && (answer == nil || !answer(err))
+	})
+}
+
+// Put implements Store.
+func (g *guarded) Put(ctx context.Context, rec models.StoredPrediction) error {
+	return g.do(ctx, func(ctx context.Context) error { return g.s.Put(ctx, rec) }, nil)
+}
+
+// Get implements Store.
+func (g *guarded) Get(ctx context.Context, id string) (rec models.StoredPrediction, err error) {
+	err = g.do(ctx, func(ctx context.Context) error {
+		rec, err = g.s.Get(ctx, id)
+		return err
+	}, nil)
+	return rec, err
+}
+
+// Update implements Store. An error from fn, such as a conflict, is the
+// caller's answer and does not count against the database.
+func (g *guarded) Update(ctx context.Context, id string, fn func(*models.StoredPrediction) error) (rec models.StoredPrediction, err error) {
+	var fnErr error
+	err = g.do(ctx, func(ctx context.Context) error {
+		rec, err = g.s.Update(ctx, id, func(r *models.StoredPrediction) error {
+			fnErr = fn(r)
+			return fnErr
+		})
+		return err
+	}, func(err error) bool { return fnErr != nil && errors.Is(err, fnErr) })
+	return rec, err
+}
+
+// Find implements Store.
+func (g *guarded) Find(ctx context.Context, f Filter) (recs []models.StoredPrediction, err error) {
+	err = g.do(ctx, func(ctx context.Context) error {
+		recs, err = g.s.Find(ctx, f)
+		return err
+	}, nil)
+	return recs, err
+}
+
+// Delete implements Store.
+func (g *guarded) Delete(ctx context.Context, id string) error {
+	return g.do(ctx, func(ctx context.Context) error { return g.s.Delete(ctx, id) }, nil)
+}
+
+// Close closes the wrapped store if it has anything to close.
+func (g *guarded) Close() error {
+	if closer, ok := g.s.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/dependency"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// stallingStore is a Store whose Find blocks until its context is done.
+type stallingStore struct{ Store }
+
+func (stallingStore) Find(ctx context.Context, _ Filter) ([]models.StoredPrediction, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestGuardOpensOnFailuresOnly(t *testing.T) {
+	ctx := context.Background()
+	mem, err := NewMemoryStore(MemoryConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	breaker := dependency.NewBreaker("predictions", dependency.BreakerConfig{Failures: 2, Cooldown: time.Hour})
+	s := Guard(stallingStore{mem}, breaker, 10*time.Millisecond)
+	if err := s.Put(ctx, testRecord("p1", "clinic-a", time.Now())); err != nil {
+		t.Fatal(err)
+	}
+
+	// Answers are not failures of the database.
+	conflict := errors.New("conflict")
+	for range 3 {
+		if _, err := s.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("get missing: error = %v, want ErrNotFound", err)
+		}
+		if _, err := s.Update(ctx, "p1", func(*models.StoredPrediction) error { return conflict }); !errors.Is(err, conflict) {
+			t.Fatalf("refused update: error = %v, want the callback's error", err)
+		}
+	}
+	if breaker.Open() {
+		t.Fatal("breaker opened on answers")
+	}
+
+	for range 2 {
+		if _, err := s.Find(ctx, Filter{}); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("stalled find: error = %v, want the deadline", err)
+		}
+	}
+	if !breaker.Open() {
+		t.Fatal("breaker still closed after repeated timeouts")
+	}
+	if _, err := s.Get(ctx, "p1"); !errors.Is(err, dependency.ErrOpen) {
+		t.Errorf("get with the breaker open: error = %v, want ErrOpen", err)
+	}
+}
+
+func TestGuardPassesThroughOutboxAndClose(t *testing.T) {
+	ctx := context.Background()
+	breaker := dependency.NewBreaker("predictions", dependency.BreakerConfig{})
+	sqlite := newSQLiteStore(t, nil)
+	s := Guard(sqlite, breaker, time.Second)
+	o, ok := s.(OutboxStore)
+	if !ok {
+		t.Fatal("guarded SQL store lost its outbox")
+	}
+	ev := OutboxEvent{ID: "e1", Type: "prediction.completed", CreatedAt: time.Now(), Data: []byte(`{}`)}
+	if err := o.PutWithEvents(ctx, testRecord("p1", "clinic-a", time.Now()), ev); err != nil {
+		t.Fatalf("put with events: %v", err)
+	}
+	if evs, err := o.PendingEvents(ctx, 10); err != nil || len(evs) != 1 {
+		t.Errorf("pending events = %v, %v", evs, err)
+	}
+
+	if _, ok := Guard(stallingStore{sqlite}, breaker, 0).(OutboxStore); ok {
+		t.Error("store without an outbox gained one")
+	}
+	if err := s.(interface{ Close() error }).Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if err := sqlite.Ping(ctx); err == nil {
+		t.Error("close was not passed through")
+	}
+}
@@ -0,0 +1,114 @@
+// backend/internal/store/images.go
+/*
+ * This file implements retention of source images.
+ *
+ * Some analyses (re-scoring history with a new model, for instance) need
+ * the original pixels, not just the scores. When image retention is
+ * enabled the upload is kept on local disk, keyed by prediction ID.
+ *
+ * With tenant keys each image is encrypted with its tenant's key, bound to
+ * its prediction ID. An image whose key has been withdrawn is kept on disk
+ * but reads as not found.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/tenantkey"
+)
+
+// ImageStore keeps the original bytes of scored images.
+type ImageStore interface {
+	PutImage(ctx context.Context, tenant, predictionID string, data []byte) error
+	GetImage(ctx context.Context, predictionID string) ([]byte, error)
+	// DeleteImage removes a retained image; it is not an error if none
+	// was kept.
+	DeleteImage(ctx context.Context, predictionID string) error
+}
+
+// DirImageStore stores each image as a file in a directory.
+type DirImageStore struct {
+	dir  string
+	keys *tenantkey.Keyring
+}
+
+// NewDirImageStore creates the directory if needed. Images are encrypted
+// with keys when not nil.
+func NewDirImageStore(dir string, keys *tenantkey.Keyring) (*DirImageStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create image store dir: %w", err)
+	}
+	return &DirImageStore{dir: dir, keys: keys}, nil
+}
+
+// PutImage implements ImageStore.
+func (s *DirImageStore) PutImage(_ context.Context, tenant, predictionID string, data []byte) error {
+	path, err := s.path(predictionID)
+	if err != nil {
+		return err
+	}
+	if s.keys != nil {
+		if data, err = s.keys.Seal(tenant, data, []byte(predictionID)); err != nil {
+			return fmt.Errorf("encrypt image: %w", err)
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o640); err != nil {
+		return fmt.Errorf("write image: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// GetImage implements ImageStore. It returns ErrNotFound if the image was
+// never retained.
+func (s *DirImageStore) GetImage(_ context.Context, predictionID string) ([]byte, error) {
+	path, err := s.path(predictionID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil || !tenantkey.IsSealed(data) {
+		return data, err
+	}
+	if s.keys == nil {
+		return nil, fmt.Errorf("image %s is encrypted and no tenant keys are configured", predictionID)
+	}
+	data, err = s.keys.Open(data, []byte(predictionID))
+	if errors.Is(err, tenantkey.ErrNoKey) {
+		return nil, fmt.Errorf("%w: image %s: %w", ErrNotFound, predictionID, err)
+	}
+	return data, err
+}
+
+// DeleteImage implements ImageStore.
+func (s *DirImageStore) DeleteImage(_ context.Context, predictionID string) error {
+	path, err := s.path(predictionID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("delete image: %w", err)
+	}
+	return nil
+}
+
+func (s *DirImageStore) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return "", fmt.Errorf("invalid prediction id %q", id)
+	}
+	return filepath.Join(s.dir, id+".img"), nil
+}
@@ -0,0 +1,323 @@
+// backend/internal/store/memory.go
+/*
+ * This file implements the in-process prediction store.
+ *
+ * Records live in memory. When a journal path is configured every write is
+ * also appended to a JSON Lines file, which is replayed on startup, so the
+ * history survives restarts without needing a database. Without a journal
+ * the store keeps only the most recent MaxRecords predictions.
+ *
+ * Purging a record rewrites the journal without it, so a purged prediction
+ * does not linger in earlier journal lines.
+ *
+ * With tenant keys (MemoryConfig.Keys) each journal line is encrypted with
+ * its tenant's key. Lines whose key has been withdrawn are not loaded but
+ * are kept, as they are, when the journal is rewritten: withdrawing a key
+ * hides a tenant's history, it does not destroy it.
+ *
+ * The store keeps an event outbox (see outbox.go) in memory, under the
+ * lock of the records. It is not journaled: events written before a
+ * restart are lost, so a journaled store is no better than publishing on
+ * the bus. Use the SQL store where the outbox must survive restarts.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package store
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/tenantkey"
+)
+
+// MemoryConfig configures a MemoryStore.
+type MemoryConfig struct {
+	// JournalPath, if set, persists records to a JSON Lines file.
+	JournalPath string
+	// MaxRecords bounds an unjournaled store; older records are evicted.
+	MaxRecords int
+	// Keys, when set, encrypts each journal line with its tenant's key.
+	Keys *tenantkey.Keyring
+}
+
+// MemoryStore is a Store held in memory with an optional journal.
+type MemoryStore struct {
+	cfg     MemoryConfig
+	mu      sync.RWMutex
+	records map[string]models.StoredPrediction
+	order   []string // insertion order, for eviction
+	journal *os.File
+	// withheld are journal lines whose key has been withdrawn.
+	withheld [][]byte
+	outbox   []OutboxEvent
+}
+
+// NewMemoryStore creates a store, replaying the journal if one exists.
+func NewMemoryStore(cfg MemoryConfig) (*MemoryStore, error) {
+	if cfg.MaxRecords <= 0 {
+		cfg.MaxRecords = 10000
+	}
+	s := &MemoryStore{cfg: cfg, records: make(map[string]models.StoredPrediction)}
+	if cfg.JournalPath == "" {
+		return s, nil
+	}
+
+	if err := s.replay(); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(cfg.JournalPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("open prediction journal: %w", err)
+	}
+	s.journal = f
+	return s, nil
+}
+
+// Put implements Store.
+func (s *MemoryStore) Put(_ context.Context, rec models.StoredPrediction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.write(rec)
+}
+
+// PutWithEvents implements OutboxStore.
+func (s *MemoryStore) PutWithEvents(_ context.Context, rec models.StoredPrediction, evs ...OutboxEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.write(rec); err != nil {
+		return err
+	}
+	for _, ev := range evs {
+		if !slices.ContainsFunc(s.outbox, func(o OutboxEvent) bool { return o.ID == ev.ID }) {
+			s.outbox = append(s.outbox, ev)
+		}
+	}
+	return nil
+}
+
+// PendingEvents implements OutboxStore.
+func (s *MemoryStore) PendingEvents(_ context.Context, limit int) ([]OutboxEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := slices.Clone(s.outbox)
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// AckEvents implements OutboxStore.
+func (s *MemoryStore) AckEvents(_ context.Context, ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.outbox = slices.DeleteFunc(s.outbox, func(o OutboxEvent) bool { return slices.Contains(ids, o.ID) })
+	return nil
+}
+
+// Update implements Store.
+func (s *MemoryStore) Update(_ context.Context, id string, fn func(*models.StoredPrediction) error) (models.StoredPrediction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.records[id]
+	if !ok {
+		return models.StoredPrediction{}, ErrNotFound
+	}
+	if err := fn(&rec); err != nil {
+		return models.StoredPrediction{}, err
+	}
+	if err := s.write(rec); err != nil {
+		return models.StoredPrediction{}, err
+	}
+	return rec, nil
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(_ context.Context, id string) (models.StoredPrediction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.records[id]
+	if !ok {
+		return models.StoredPrediction{}, ErrNotFound
+	}
+	return rec, nil
+}
+
+// Find implements Store.
+func (s *MemoryStore) Find(_ context.Context, f Filter) ([]models.StoredPrediction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []models.StoredPrediction
+	for _, rec := range s.records {
+		if f.matches(rec) {
+			out = append(out, rec)
+		}
+	}
+	return f.sortRecords(out), nil
+}
+
+// Delete implements Store. A journaled store is compacted so no copy of
+// the record remains on disk.
+func (s *MemoryStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.records, id)
+	s.order = slices.DeleteFunc(s.order, func(o string) bool { return o == id })
+	if s.journal == nil {
+		return nil
+	}
+	return s.compact()
+}
+
+// compact rewrites the journal with one line per live record, replacing
+// the old file atomically; callers must hold s.mu.
+func (s *MemoryStore) compact() error {
+	tmp := s.cfg.JournalPath + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("compact prediction journal: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	for _, line := range s.withheld {
+		w.Write(append(line, '\n'))
+	}
+	for _, id := range s.order {
+		line, err := s.encode(s.records[id])
+		if err != nil {
+			f.Close()
+			return err
+		}
+		w.Write(append(line, '\n'))
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("compact prediction journal: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("compact prediction journal: %w", err)
+	}
+	f.Close()
+	if err := os.Rename(tmp, s.cfg.JournalPath); err != nil {
+		return fmt.Errorf("compact prediction journal: %w", err)
+	}
+
+	// Reopen so later appends go to the new file, not the unlinked one.
+	journal, err := os.OpenFile(s.cfg.JournalPath, os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("open prediction journal: %w", err)
+	}
+	s.journal.Close()
+	s.journal = journal
+	return nil
+}
+
+// write journals rec, if journaling, and applies it; callers must hold s.mu.
+func (s *MemoryStore) write(rec models.StoredPrediction) error {
+	if s.journal != nil {
+		line, err := s.encode(rec)
+		if err != nil {
+			return err
+		}
+		if _, err := s.journal.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("write prediction journal: %w", err)
+		}
+	}
+	s.apply(rec)
+	return nil
+}
+
+// apply stores rec in memory; callers must hold s.mu.
+func (s *MemoryStore) apply(rec models.StoredPrediction) {
+	if _, exists := s.records[rec.PredictionID]; !exists {
+		s.order = append(s.order, rec.PredictionID)
+	}
+	s.records[rec.PredictionID] = rec
+
+	// Only an unjournaled store is bounded; a journaled store is the
+	// system of record and must keep everything.
+	if s.cfg.JournalPath == "" && len(s.order) > s.cfg.MaxRecords {
+		evict := s.order[0]
+		s.order = s.order[1:]
+		delete(s.records, evict)
+	}
+}
+
+func (s *MemoryStore) replay() error {
+	f, err := os.Open(s.cfg.JournalPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open prediction journal: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	withheld := 0
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if tenantkey.IsSealedText(string(line)) {
+			if s.cfg.Keys == nil {
+				return fmt.Errorf("prediction journal is encrypted and no tenant keys are configured")
+			}
+			opened, err := s.cfg.Keys.OpenText(string(line), nil)
+			if errors.Is(err, tenantkey.ErrNoKey) {
+				s.withheld = append(s.withheld, slices.Clone(line))
+				withheld++
+				continue
+			}
+			if err != nil {
+				// A torn final line from a crash mid-write is skipped.
+				continue
+			}
+			line = opened
+		}
+		var rec models.StoredPrediction
+		if err := json.Unmarshal(line, &rec); err != nil {
+			// A torn final line from a crash mid-write is skipped.
+			continue
+		}
+		s.apply(rec)
+	}
+	if withheld > 0 {
+		log.Printf("prediction journal: %d line(s) kept but not loaded, their tenant key is not configured", withheld)
+	}
+	return scanner.Err()
+}
+
+// encode returns the journal line of rec, encrypted when tenant keys are
+// configured.
+func (s *MemoryStore) encode(rec models.StoredPrediction) ([]byte, error) {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return nil, fmt.Errorf("encode prediction: %w", err)
+	}
+	if s.cfg.Keys == nil {
+		return line, nil
+	}
+	sealed, err := s.cfg.Keys.SealText(rec.Tenant, line, nil)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt prediction: %w", err)
+	}
+	return []byte(sealed), nil
+}
@@ -0,0 +1,244 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/tenantkey"
+)
+
+// testStoreContract checks the behaviour every Store shares. The store
+// must be empty and accept records of clinic-a and clinic-b.
+func testStoreContract(t *testing.T, s Store) {
+	t.Helper()
+	ctx := context.Background()
+	base := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+
+	if _, err := s.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Update(ctx, "missing", func(*models.StoredPrediction) error { return nil }); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Update(missing) error = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete(missing) error = %v, want ErrNotFound", err)
+	}
+
+	for i, tenant := range []string{"clinic-a", "clinic-a", "clinic-b"} {
+		rec := testRecord([]string{"p1", "p2", "p3"}[i], tenant, base.Add(time.Duration(i)*time.Hour))
+		rec.ClientReference = "ref-" + rec.PredictionID
+		if err := s.Put(ctx, rec); err != nil {
+			t.Fatalf("Put %s: %v", rec.PredictionID, err)
+		}
+	}
+	replaced := testRecord("p1", "clinic-a", base)
+	replaced.ClientReference, replaced.Prediction = "ref-p1", models.LabelNonCancer
+	if err := s.Put(ctx, replaced); err != nil {
+		t.Fatalf("Put replacement: %v", err)
+	}
+	if got, err := s.Get(ctx, "p1"); err != nil || got.Prediction != models.LabelNonCancer {
+		t.Errorf("Get after replacement = %q, %v", got.Prediction, err)
+	}
+
+	refused := errors.New("refused")
+	if _, err := s.Update(ctx, "p2", func(r *models.StoredPrediction) error {
+		r.Prediction = models.LabelNonCancer
+		return refused
+	}); err != refused {
+		t.Errorf("Update error = %v, want the callback's error as is", err)
+	}
+	if got, _ := s.Get(ctx, "p2"); got.Prediction != models.LabelCancer {
+		t.Error("a refused Update was saved")
+	}
+
+	find := func(name string, f Filter, want ...string) {
+		t.Helper()
+		recs, err := s.Find(ctx, f)
+		if err != nil {
+			t.Fatalf("Find %s: %v", name, err)
+		}
+		got := []string{}
+		for _, r := range recs {
+			got = append(got, r.PredictionID)
+		}
+		if want == nil {
+			want = []string{}
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("Find %s = %v, want %v", name, got, want)
+		}
+	}
+	find("all", Filter{}, "p3", "p2", "p1")
+	find("tenant", Filter{Tenant: "clinic-a"}, "p2", "p1")
+	find("unknown tenant", Filter{Tenant: "clinic-z"})
+	find("accession", Filter{Tenant: "clinic-a", AccessionNumber: "ACC-p2"}, "p2")
+	find("accession of another tenant", Filter{Tenant: "clinic-a", AccessionNumber: "ACC-p3"})
+	find("client reference", Filter{Tenant: "clinic-b", ClientReference: "ref-p3"}, "p3")
+	find("ids", Filter{IDs: []string{"p1", "p3", "missing"}}, "p3", "p1")
+	find("created range", Filter{CreatedFrom: base.Add(time.Hour), CreatedTo: base.Add(2 * time.Hour)}, "p2")
+
+	if _, err := s.Update(ctx, "p2", func(r *models.StoredPrediction) error {
+		now := base.Add(time.Hour)
+		r.DeletedAt = &now
+		return nil
+	}); err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+	find("without soft-deleted", Filter{Tenant: "clinic-a"}, "p1")
+	find("with soft-deleted", Filter{Tenant: "clinic-a", IncludeDeleted: true}, "p2", "p1")
+	if _, err := s.Get(ctx, "p2"); err != nil {
+		t.Errorf("Get of a soft-deleted record: %v", err)
+	}
+
+	if err := s.Delete(ctx, "p2"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get(ctx, "p2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete error = %v, want ErrNotFound", err)
+	}
+	find("after delete", Filter{Tenant: "clinic-a", IncludeDeleted: true}, "p1")
+}
+
+func TestMemoryStoreContract(t *testing.T) {
+	keys := testKeyring(t, "clinic-a", "clinic-b")
+	for name, cfg := range map[string]MemoryConfig{
+		"unjournaled": {},
+		"journaled":   {JournalPath: filepath.Join(t.TempDir(), "predictions.jsonl")},
+		"sealed":      {JournalPath: filepath.Join(t.TempDir(), "predictions.jsonl"), Keys: keys},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s, err := NewMemoryStore(cfg)
+			if err != nil {
+				t.Fatalf("NewMemoryStore: %v", err)
+			}
+			testStoreContract(t, s)
+		})
+	}
+}
+
+func TestMemoryStoreEvictsWithoutJournal(t *testing.T) {
+	s, err := NewMemoryStore(MemoryConfig{MaxRecords: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, id := range []string{"p1", "p2", "p1", "p3"} {
+		if err := s.Put(ctx, testRecord(id, "clinic-a", time.Now())); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Get(ctx, "p1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("oldest record kept past MaxRecords: %v", err)
+	}
+	for _, id := range []string{"p2", "p3"} {
+		if _, err := s.Get(ctx, id); err != nil {
+			t.Errorf("Get(%s): %v", id, err)
+		}
+	}
+}
+
+func TestMemoryStoreJournalReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "predictions.jsonl")
+	ctx := context.Background()
+	s, err := NewMemoryStore(MemoryConfig{JournalPath: path, MaxRecords: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"p1", "p2", "p3"} {
+		if err := s.Put(ctx, testRecord(id, "clinic-a", time.Now())); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Delete(ctx, "p2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(ctx, testRecord("p4", "clinic-a", time.Now())); err != nil {
+		t.Fatalf("Put after compaction: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), `"p2"`) {
+		t.Error("purged record left in the journal")
+	}
+	// A torn final line from a crash is skipped.
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString(`{"prediction_id":"p5","tena`)
+	f.Close()
+
+	reopened, err := NewMemoryStore(MemoryConfig{JournalPath: path, MaxRecords: 1})
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	recs, _ := reopened.Find(ctx, Filter{OldestFirst: true})
+	var got []string
+	for _, r := range recs {
+		got = append(got, r.PredictionID)
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, []string{"p1", "p3", "p4"}) {
+		t.Errorf("replayed %v, want every journaled record regardless of MaxRecords", got)
+	}
+}
+
+func TestMemoryStoreSealedJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "predictions.jsonl")
+	ctx := context.Background()
+	keyDir := testKeyDir(t, "clinic-a", "clinic-b")
+	keys := loadKeys(t, keyDir)
+	s, err := NewMemoryStore(MemoryConfig{JournalPath: path, Keys: keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range []models.StoredPrediction{testRecord("p1", "clinic-a", time.Now()), testRecord("p2", "clinic-b", time.Now())} {
+		if err := s.Put(ctx, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Put(ctx, testRecord("p3", "clinic-c", time.Now())); !errors.Is(err, tenantkey.ErrNoKey) {
+		t.Errorf("Put for a tenant without a key: error = %v, want ErrNoKey", err)
+	}
+	if _, err := s.Get(ctx, "p3"); !errors.Is(err, ErrNotFound) {
+		t.Error("a record that could not be sealed was kept")
+	}
+	data, _ := os.ReadFile(path)
+	for _, plain := range []string{"ACC-p1", "baseline_cnn_v2", "clinic-a"} {
+		if strings.Contains(string(data), plain) {
+			t.Errorf("journal contains %q in plaintext", plain)
+		}
+	}
+
+	if _, err := NewMemoryStore(MemoryConfig{JournalPath: path}); err == nil {
+		t.Error("opened a sealed journal without keys")
+	}
+
+	// Withdraw clinic-b's key: its line is hidden but survives compaction.
+	if err := os.Remove(filepath.Join(keyDir, "clinic-b.key")); err != nil {
+		t.Fatal(err)
+	}
+	withdrawn, err := NewMemoryStore(MemoryConfig{JournalPath: path, Keys: loadKeys(t, keyDir)})
+	if err != nil {
+		t.Fatalf("reopen with a withdrawn key: %v", err)
+	}
+	if _, err := withdrawn.Get(ctx, "p2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("record of a withdrawn key loaded: %v", err)
+	}
+	if err := withdrawn.Delete(ctx, "p1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	restored, err := NewMemoryStore(MemoryConfig{JournalPath: path, Keys: keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := restored.Get(ctx, "p2"); err != nil {
+		t.Errorf("withheld record lost by compaction: %v", err)
+	}
+	if _, err := restored.Get(ctx, "p1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleted record came back: %v", err)
+	}
+}
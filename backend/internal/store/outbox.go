@@ -0,0 +1,78 @@
+// backend/internal/store/outbox.go
+/*
+ * This file defines the event outbox of a store.
+ *
+ * An event announcing a prediction (the prediction.completed webhook)
+ * must exist exactly when the prediction does. Written separately, a
+ * crash between the two writes loses the event or announces a prediction
+ * that was never saved. A store with an outbox writes the events of a
+ * prediction in the same write as the prediction itself -- one
+ * transaction in SQL -- and a relay reads them back and acknowledges
+ * each once it has passed it on (see webhook.Dispatcher.RelayFrom).
+ *
+ * Event data is encrypted with the tenant's key like the record, when
+ * tenant keys are configured.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// OutboxEvent is an event written with a prediction.
+type OutboxEvent struct {
+	// ID identifies the event to its receivers; it is unique in the
+	// outbox, so writing the same event twice stores it once.
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Tenant    string          `json:"tenant,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// OutboxStore is a Store keeping an event outbox.
+type OutboxStore interface {
+	Store
+	// PutWithEvents is Put, also adding evs to the outbox in the same
+	// write: either the record and its events are saved, or neither is.
+	PutWithEvents(ctx context.Context, rec models.StoredPrediction, evs ...OutboxEvent) error
+	// PendingEvents returns up to limit events of the outbox, oldest
+	// first.
+	PendingEvents(ctx context.Context, limit int) ([]OutboxEvent, error)
+	// AckEvents removes events from the outbox.
+	AckEvents(ctx context.Context, ids ...string) error
+}
+
+// guardedOutbox is a guarded OutboxStore.
+type guardedOutbox struct {
+	*guarded
+	o OutboxStore
+}
+
+// PutWithEvents implements OutboxStore.
+func (g *guardedOutbox) PutWithEvents(ctx context.Context, rec models.StoredPrediction, evs ...OutboxEvent) error {
+	return g.do(ctx, func(ctx context.Context) error { return g.o.PutWithEvents(ctx, rec, evs...) }, nil)
+}
+
+// PendingEvents implements OutboxStore.
+func (g *guardedOutbox) PendingEvents(ctx context.Context, limit int) (evs []OutboxEvent, err error) {
+	err = g.do(ctx, func(ctx context.Context) error {
+		evs, err = g.o.PendingEvents(ctx, limit)
+		return err
+	}, nil)
+	return evs, err
+}
+
+// AckEvents implements OutboxStore.
+func (g *guardedOutbox) AckEvents(ctx context.Context, ids ...string) error {
+	return g.do(ctx, func(ctx context.Context) error { return g.o.AckEvents(ctx, ids...) }, nil)
+}
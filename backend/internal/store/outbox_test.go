@@ -0,0 +1,75 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/tenantkey"
+)
+
+func TestOutbox(t *testing.T) {
+	mem, err := NewMemoryStore(MemoryConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stores := map[string]OutboxStore{
+		"memory": mem,
+		"sqlite": newSQLiteStore(t, testKeyring(t, "clinic-a")),
+	}
+	ctx := context.Background()
+	base := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	event := func(id string, at time.Duration) OutboxEvent {
+		return OutboxEvent{ID: id, Type: "prediction.completed", Tenant: "clinic-a", CreatedAt: base.Add(at), Data: []byte(`{"id":"` + id + `"}`)}
+	}
+	pending := func(s OutboxStore, limit int) []string {
+		t.Helper()
+		evs, err := s.PendingEvents(ctx, limit)
+		if err != nil {
+			t.Fatalf("pending events: %v", err)
+		}
+		var ids []string
+		for _, ev := range evs {
+			ids = append(ids, ev.ID)
+		}
+		return ids
+	}
+
+	for name, s := range stores {
+		if err := s.PutWithEvents(ctx, testRecord("p1", "clinic-a", base), event("e1", 0), event("e2", time.Second)); err != nil {
+			t.Fatalf("%s: put with events: %v", name, err)
+		}
+		// Writing the same event again stores it once.
+		if err := s.PutWithEvents(ctx, testRecord("p1", "clinic-a", base), event("e1", 0), event("e3", 2*time.Second)); err != nil {
+			t.Fatalf("%s: put again: %v", name, err)
+		}
+		if got := pending(s, 0); !slices.Equal(got, []string{"e1", "e2", "e3"}) {
+			t.Errorf("%s: pending = %v, want e1 e2 e3", name, got)
+		}
+		if got := pending(s, 2); !slices.Equal(got, []string{"e1", "e2"}) {
+			t.Errorf("%s: pending with limit = %v", name, got)
+		}
+		if err := s.AckEvents(ctx, "e1", "e3", "unknown"); err != nil {
+			t.Fatalf("%s: ack: %v", name, err)
+		}
+		if got := pending(s, 0); !slices.Equal(got, []string{"e2"}) {
+			t.Errorf("%s: pending after ack = %v, want e2", name, got)
+		}
+	}
+
+	// Either the record and its events are saved, or neither is.
+	sqlite := stores["sqlite"]
+	foreign := event("e4", 3*time.Second)
+	foreign.Tenant = "clinic-b"
+	if err := sqlite.PutWithEvents(ctx, testRecord("p2", "clinic-a", base), foreign); !errors.Is(err, tenantkey.ErrNoKey) {
+		t.Fatalf("put with an event that cannot be sealed: error = %v, want ErrNoKey", err)
+	}
+	if _, err := sqlite.Get(ctx, "p2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("record saved without its events: %v", err)
+	}
+	if got := pending(sqlite, 0); !slices.Equal(got, []string{"e2"}) {
+		t.Errorf("pending after a failed put = %v, want e2", got)
+	}
+}
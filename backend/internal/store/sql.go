@@ -0,0 +1,479 @@
+// backend/internal/store/sql.go
+/*
+ * This file implements the SQL prediction store.
+ *
+ * Installs that must keep a durable, queryable audit trail of every
+ * result record predictions in a database: SQLite for a single node,
+ * Postgres when several replicas share one history. Each row holds the
+ * fields auditors query on as columns:
+ *
+ *   prediction_id     the anonymous request ID returned to the client
+ *   created_at        when the prediction was made (UTC)
+ *   model_name, model_version
+ *   confidence_score, threshold, prediction (the final label)
+ *   image_sha256      digest of the scored image, never the image itself
+ *
+ * plus the tenant, correlation identifiers and deletion mark, and the full
+ * record as JSON so nothing the API returns is lost. The table is created
+ * on first use. The caller registers the database/sql driver.
+ *
+ * With tenant keys (SQLConfig.Keys) the record column is encrypted with
+ * the tenant's key and the correlation identifiers are stored as blind
+ * indexes, so they can still be looked up within a tenant. The result
+ * columns (model, score, threshold, label and image digest) are left
+ * empty: the result is only in the sealed record, where it is read from.
+ * Rows written before keys were configured stay readable. A row whose key
+ * has been withdrawn reads as not found and is left out of lookups.
+ *
+ * The store keeps an event outbox (see outbox.go) in the outbox_events
+ * table, written in the transaction that writes the prediction.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/tenantkey"
+)
+
+// SQL dialects.
+const (
+	SQLite   = "sqlite"
+	Postgres = "postgres"
+)
+
+// sqliteTime is a fixed-width layout, so SQLite's text timestamps sort
+// chronologically.
+const sqliteTime = "2006-01-02T15:04:05.000000000Z"
+
+// SQLConfig configures a SQLStore.
+type SQLConfig struct {
+	// Dialect is SQLite or Postgres.
+	Dialect string
+	// Driver is the registered database/sql driver name.
+	Driver string
+	// DSN is the driver's data source name.
+	DSN string
+	// MaxOpenConns and MaxIdleConns size the connection pool (zero keeps
+	// the database/sql defaults). SQLite always uses one connection.
+	MaxOpenConns, MaxIdleConns int
+	// ConnMaxLifetime and ConnMaxIdleTime recycle pooled connections, so
+	// connections a proxy or failover silently dropped are replaced.
+	ConnMaxLifetime, ConnMaxIdleTime time.Duration
+	// Keys, when set, encrypts each record with its tenant's key.
+	Keys *tenantkey.Keyring
+}
+
+// SQLStore is a Store backed by a SQL database.
+type SQLStore struct {
+	db      *sql.DB
+	dialect string
+	keys    *tenantkey.Keyring
+}
+
+// NewSQLStore opens the database and creates the table if needed.
+func NewSQLStore(ctx context.Context, cfg SQLConfig) (*SQLStore, error) {
+	if cfg.Dialect != SQLite && cfg.Dialect != Postgres {
+		return nil, fmt.Errorf("unsupported SQL dialect %q", cfg.Dialect)
+	}
+	db, err := sql.Open(cfg.Driver, cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Dialect == SQLite {
+		// SQLite allows one writer; a single connection keeps Update's
+		// read-modify-write from failing with SQLITE_BUSY.
+		db.SetMaxOpenConns(1)
+	} else {
+		if cfg.MaxOpenConns > 0 {
+			db.SetMaxOpenConns(cfg.MaxOpenConns)
+		}
+		if cfg.MaxIdleConns > 0 {
+			db.SetMaxIdleConns(cfg.MaxIdleConns)
+		}
+	}
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	s := &SQLStore{db: db, dialect: cfg.Dialect, keys: cfg.Keys}
+	if err := s.migrate(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("prepare prediction table: %w", err)
+	}
+	return s, nil
+}
+
+// Close closes the database.
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+// Ping checks that the database answers.
+func (s *SQLStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *SQLStore) migrate(ctx context.Context) error {
+	timeType, floatType := "TIMESTAMPTZ", "DOUBLE PRECISION"
+	if s.dialect == SQLite {
+		timeType, floatType = "TEXT", "REAL"
+	}
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS predictions (
+			prediction_id    TEXT PRIMARY KEY,
+			tenant           TEXT NOT NULL DEFAULT '',
+			created_at       ` + timeType + ` NOT NULL,
+			model_name       TEXT NOT NULL DEFAULT '',
+			model_version    TEXT NOT NULL DEFAULT '',
+			confidence_score ` + floatType + ` NOT NULL,
+			threshold        ` + floatType + ` NOT NULL,
+			prediction       TEXT NOT NULL,
+			image_sha256     TEXT NOT NULL DEFAULT '',
+			accession_number TEXT NOT NULL DEFAULT '',
+			client_reference TEXT NOT NULL DEFAULT '',
+			deleted_at       ` + timeType + `,
+			record           TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS predictions_tenant_created ON predictions (tenant, created_at)`,
+		`CREATE INDEX IF NOT EXISTS predictions_accession ON predictions (accession_number)`,
+		`CREATE INDEX IF NOT EXISTS predictions_client_reference ON predictions (client_reference)`,
+		`CREATE TABLE IF NOT EXISTS outbox_events (
+			event_id   TEXT PRIMARY KEY,
+			tenant     TEXT NOT NULL DEFAULT '',
+			created_at ` + timeType + ` NOT NULL,
+			event      TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS outbox_events_created ON outbox_events (created_at)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// timeArg converts t for the dialect.
+func (s *SQLStore) timeArg(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	if s.dialect == SQLite {
+		return t.UTC().Format(sqliteTime)
+	}
+	// Postgres keeps microseconds; a listing position must compare equal
+	// to the column it was read from.
+	return t.UTC().Truncate(time.Microsecond)
+}
+
+// execer is satisfied by *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Put implements Store.
+func (s *SQLStore) Put(ctx context.Context, rec models.StoredPrediction) error {
+	return s.put(ctx, s.db, rec)
+}
+
+func (s *SQLStore) put(ctx context.Context, db execer, rec models.StoredPrediction) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	record, accession, clientRef := string(data), rec.AccessionNumber, rec.ClientReference
+	// The result columns, empty when the record is sealed.
+	result := rec
+	if s.keys != nil {
+		if record, err = s.keys.SealText(rec.Tenant, data, []byte(rec.PredictionID)); err != nil {
+			return fmt.Errorf("encrypt prediction record: %w", err)
+		}
+		accession, clientRef = s.blindIndex(rec.Tenant, accession), s.blindIndex(rec.Tenant, clientRef)
+		result = models.StoredPrediction{}
+	}
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO predictions (prediction_id, tenant, created_at, model_name, model_version,
+			confidence_score, threshold, prediction, image_sha256, accession_number,
+			client_reference, deleted_at, record)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (prediction_id) DO UPDATE SET
+			tenant = excluded.tenant, created_at = excluded.created_at,
+			model_name = excluded.model_name, model_version = excluded.model_version,
+			confidence_score = excluded.confidence_score, threshold = excluded.threshold,
+			prediction = excluded.prediction, image_sha256 = excluded.image_sha256,
+			accession_number = excluded.accession_number, client_reference = excluded.client_reference,
+			deleted_at = excluded.deleted_at, record = excluded.record`,
+		rec.PredictionID, rec.Tenant, s.timeArg(&rec.CreatedAt), result.ModelName, result.ModelVersion,
+		result.ConfidenceScore, result.ModelThreshold, result.Prediction, result.ImageSHA256, accession,
+		clientRef, s.timeArg(rec.DeletedAt), record)
+	return err
+}
+
+// PutWithEvents implements OutboxStore.
+func (s *SQLStore) PutWithEvents(ctx context.Context, rec models.StoredPrediction, evs ...OutboxEvent) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := s.put(ctx, tx, rec); err != nil {
+		return err
+	}
+	for _, ev := range evs {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		event := string(data)
+		if s.keys != nil {
+			if event, err = s.keys.SealText(ev.Tenant, data, []byte(ev.ID)); err != nil {
+				return fmt.Errorf("encrypt outbox event: %w", err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO outbox_events (event_id, tenant, created_at, event)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (event_id) DO NOTHING`,
+			ev.ID, ev.Tenant, s.timeArg(&ev.CreatedAt), event); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// PendingEvents implements OutboxStore. Events whose tenant key has been
+// withdrawn are left in the outbox and out of the result.
+func (s *SQLStore) PendingEvents(ctx context.Context, limit int) ([]OutboxEvent, error) {
+	query := `SELECT event_id, event FROM outbox_events ORDER BY created_at, event_id`
+	if limit > 0 {
+		query += fmt.Sprintf(` LIMIT %d`, limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []OutboxEvent
+	for rows.Next() {
+		var id, event string
+		if err := rows.Scan(&id, &event); err != nil {
+			return nil, err
+		}
+		data, err := openRecord(s.keys, event, id)
+		if errors.Is(err, tenantkey.ErrNoKey) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var ev OutboxEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return nil, fmt.Errorf("decode outbox event %s: %w", id, err)
+		}
+		out = append(out, ev)
+	}
+	return out, rows.Err()
+}
+
+// AckEvents implements OutboxStore.
+func (s *SQLStore) AckEvents(ctx context.Context, ids ...string) error {
+	for _, id := range ids {
+		if _, err := s.db.ExecContext(ctx, `DELETE FROM outbox_events WHERE event_id = $1`, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blindIndex returns the current blind index of a looked-up value.
+func (s *SQLStore) blindIndex(tenant, value string) string {
+	if idx := s.keys.Index(tenant, value); len(idx) > 0 {
+		return idx[0]
+	}
+	return ""
+}
+
+// Get implements Store.
+func (s *SQLStore) Get(ctx context.Context, id string) (models.StoredPrediction, error) {
+	return s.scanRecord(s.db.QueryRowContext(ctx, `SELECT prediction_id, record FROM predictions WHERE prediction_id = $1`, id))
+}
+
+// Update implements Store.
+func (s *SQLStore) Update(ctx context.Context, id string, fn func(*models.StoredPrediction) error) (models.StoredPrediction, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.StoredPrediction{}, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT prediction_id, record FROM predictions WHERE prediction_id = $1`
+	if s.dialect == Postgres {
+		query += ` FOR UPDATE`
+	}
+	rec, err := s.scanRecord(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return models.StoredPrediction{}, err
+	}
+	if err := fn(&rec); err != nil {
+		return models.StoredPrediction{}, err
+	}
+	if err := s.put(ctx, tx, rec); err != nil {
+		return models.StoredPrediction{}, err
+	}
+	return rec, tx.Commit()
+}
+
+// Find implements Store.
+func (s *SQLStore) Find(ctx context.Context, f Filter) ([]models.StoredPrediction, error) {
+	var where []string
+	var args []any
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if !f.IncludeDeleted {
+		where = append(where, "deleted_at IS NULL")
+	}
+	if f.Tenant != "" {
+		add("tenant = $%d", f.Tenant)
+	}
+	in := func(column string, values []string) {
+		marks := make([]string, len(values))
+		for i, v := range values {
+			args = append(args, v)
+			marks[i] = fmt.Sprintf("$%d", len(args))
+		}
+		where = append(where, column+" IN ("+strings.Join(marks, ", ")+")")
+	}
+	if f.AccessionNumber != "" {
+		in("accession_number", s.lookupValues(f.Tenant, f.AccessionNumber))
+	}
+	if f.ClientReference != "" {
+		in("client_reference", s.lookupValues(f.Tenant, f.ClientReference))
+	}
+	if len(f.IDs) > 0 {
+		in("prediction_id", f.IDs)
+	}
+	if !f.CreatedFrom.IsZero() {
+		add("created_at >= $%d", s.timeArg(&f.CreatedFrom))
+	}
+	if !f.CreatedTo.IsZero() {
+		add("created_at < $%d", s.timeArg(&f.CreatedTo))
+	}
+	order, cmp := "DESC", "<"
+	if f.OldestFirst {
+		order, cmp = "ASC", ">"
+	}
+	if f.After != nil {
+		args = append(args, s.timeArg(&f.After.CreatedAt), f.After.ID)
+		where = append(where, fmt.Sprintf("(created_at %[1]s $%[2]d OR (created_at = $%[2]d AND prediction_id %[1]s $%[3]d))", cmp, len(args)-1, len(args)))
+	}
+
+	query := `SELECT prediction_id, record FROM predictions`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	query += ` ORDER BY created_at ` + order + `, prediction_id ` + order
+	// With a Match, rows are read until enough of them pass.
+	if f.Limit > 0 && f.Match == nil {
+		query += fmt.Sprintf(` LIMIT %d`, f.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []models.StoredPrediction
+	for rows.Next() {
+		rec, err := s.scanRecord(rows)
+		if errors.Is(err, tenantkey.ErrNoKey) {
+			// Its tenant's key has been withdrawn.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if f.Match != nil && !f.Match(rec) {
+			continue
+		}
+		out = append(out, rec)
+		if f.Limit > 0 && len(out) == f.Limit {
+			break
+		}
+	}
+	return out, rows.Err()
+}
+
+// lookupValues returns what a looked-up column may hold for value: the
+// value itself, as written before tenant keys were configured, and its
+// blind index under each of the tenant's keys.
+func (s *SQLStore) lookupValues(tenant, value string) []string {
+	if s.keys == nil {
+		return []string{value}
+	}
+	return append([]string{value}, s.keys.Index(tenant, value)...)
+}
+
+// Delete implements Store.
+func (s *SQLStore) Delete(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM predictions WHERE prediction_id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// scanRecord decodes the prediction_id and record columns of row. A
+// record whose key has been withdrawn is not found.
+func (s *SQLStore) scanRecord(row interface{ Scan(...any) error }) (models.StoredPrediction, error) {
+	var id, record string
+	if err := row.Scan(&id, &record); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.StoredPrediction{}, ErrNotFound
+		}
+		return models.StoredPrediction{}, err
+	}
+	data, err := openRecord(s.keys, record, id)
+	if err != nil {
+		return models.StoredPrediction{}, err
+	}
+	var rec models.StoredPrediction
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return models.StoredPrediction{}, fmt.Errorf("decode prediction record: %w", err)
+	}
+	return rec, nil
+}
+
+// openRecord returns the JSON of a stored record, decrypting it if it was
+// sealed. A record whose key is not in keys is not found.
+func openRecord(keys *tenantkey.Keyring, record, id string) ([]byte, error) {
+	if !tenantkey.IsSealedText(record) {
+		return []byte(record), nil
+	}
+	if keys == nil {
+		return nil, fmt.Errorf("%w: prediction %s is encrypted: %w", ErrNotFound, id, tenantkey.ErrNoKey)
+	}
+	data, err := keys.OpenText(record, []byte(id))
+	if errors.Is(err, tenantkey.ErrNoKey) {
+		return nil, fmt.Errorf("%w: prediction %s: %w", ErrNotFound, id, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decrypt prediction %s: %w", id, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,237 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/tenantkey"
+	_ "modernc.org/sqlite"
+)
+
+// testKeyDir writes a fresh key for each tenant and the default key, and
+// returns the directory holding them.
+func testKeyDir(t *testing.T, tenants ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, tenant := range append(tenants, tenantkey.DefaultTenant) {
+		key := make([]byte, 32)
+		rand.Read(key)
+		if err := os.WriteFile(filepath.Join(dir, tenant+".key"), []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0o600); err != nil {
+			t.Fatalf("write key: %v", err)
+		}
+	}
+	return dir
+}
+
+// loadKeys loads the keyring in dir.
+func loadKeys(t *testing.T, dir string) *tenantkey.Keyring {
+	t.Helper()
+	keys, err := tenantkey.LoadDir(dir, tenantkey.Config{})
+	if err != nil {
+		t.Fatalf("load keys: %v", err)
+	}
+	return keys
+}
+
+// testKeyring returns a keyring with a fresh key for each tenant and the
+// default key.
+func testKeyring(t *testing.T, tenants ...string) *tenantkey.Keyring {
+	t.Helper()
+	return loadKeys(t, testKeyDir(t, tenants...))
+}
+
+// newSQLiteStore opens a SQL store on a fresh SQLite database.
+func newSQLiteStore(t *testing.T, keys *tenantkey.Keyring) *SQLStore {
+	t.Helper()
+	s, err := NewSQLStore(context.Background(), SQLConfig{
+		Dialect: SQLite,
+		Driver:  "sqlite",
+		DSN:     filepath.Join(t.TempDir(), "predictions.db"),
+		Keys:    keys,
+	})
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// testRecord returns a prediction of tenant with a recognisable result.
+func testRecord(id, tenant string, created time.Time) models.StoredPrediction {
+	var rec models.StoredPrediction
+	rec.PredictionID, rec.Tenant, rec.CreatedAt = id, tenant, created.UTC()
+	rec.Prediction = models.LabelCancer
+	rec.ConfidenceScore, rec.ModelThreshold = 0.8125, 0.5
+	rec.ModelName, rec.ModelVersion = "baseline_cnn_v2", "v7"
+	rec.ImageSHA256 = strings.Repeat("ab", 32)
+	rec.AccessionNumber = "ACC-" + id
+	return rec
+}
+
+func TestSQLSealedRowHoldsNoResult(t *testing.T) {
+	s := newSQLiteStore(t, testKeyring(t, "clinic-a"))
+	ctx := context.Background()
+	rec := testRecord("p1", "clinic-a", time.Now())
+	if err := s.Put(ctx, rec); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	var modelName, modelVersion, label, digest, accession, record string
+	var score, threshold float64
+	err := s.db.QueryRowContext(ctx, `SELECT model_name, model_version, confidence_score, threshold,
+		prediction, image_sha256, accession_number, record FROM predictions WHERE prediction_id = $1`, rec.PredictionID).
+		Scan(&modelName, &modelVersion, &score, &threshold, &label, &digest, &accession, &record)
+	if err != nil {
+		t.Fatalf("read row: %v", err)
+	}
+	if modelName != "" || modelVersion != "" || score != 0 || threshold != 0 || label != "" || digest != "" {
+		t.Errorf("row holds the result in plaintext: %q %q %v %v %q %q", modelName, modelVersion, score, threshold, label, digest)
+	}
+	if accession == rec.AccessionNumber {
+		t.Error("row holds the accession number in plaintext")
+	}
+	for _, plain := range []string{rec.Prediction, rec.ModelName, rec.ImageSHA256, rec.AccessionNumber, "0.8125"} {
+		if strings.Contains(record, plain) {
+			t.Errorf("record column contains %q", plain)
+		}
+	}
+
+	got, err := s.Get(ctx, rec.PredictionID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Prediction != rec.Prediction || got.ConfidenceScore != rec.ConfidenceScore || got.ModelThreshold != rec.ModelThreshold ||
+		got.ModelVersion != rec.ModelVersion || got.ImageSHA256 != rec.ImageSHA256 {
+		t.Errorf("get = %+v, want the result read back from the sealed record", got)
+	}
+	found, err := s.Find(ctx, Filter{Tenant: "clinic-a", AccessionNumber: rec.AccessionNumber})
+	if err != nil || len(found) != 1 || found[0].Prediction != rec.Prediction {
+		t.Errorf("find by accession = %v, %v; want the record", found, err)
+	}
+}
+
+func TestFindPages(t *testing.T) {
+	ctx := context.Background()
+	mem, err := NewMemoryStore(MemoryConfig{})
+	if err != nil {
+		t.Fatalf("memory store: %v", err)
+	}
+	stores := map[string]Store{"memory": mem, "sqlite": newSQLiteStore(t, testKeyring(t, "clinic-a", "clinic-b"))}
+	base := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	// p2 and p3 share a creation time; p5 is another tenant's.
+	created := map[string]time.Time{"p1": base, "p2": base.Add(time.Minute), "p3": base.Add(time.Minute), "p4": base.Add(2 * time.Minute), "p5": base}
+	for name, s := range stores {
+		for id, at := range created {
+			rec := testRecord(id, "clinic-a", at)
+			if id == "p5" {
+				rec.Tenant = "clinic-b"
+			}
+			if id == "p3" {
+				rec.Prediction = models.LabelNonCancer
+			}
+			if err := s.Put(ctx, rec); err != nil {
+				t.Fatalf("%s: put %s: %v", name, id, err)
+			}
+		}
+	}
+	cancer := func(r models.StoredPrediction) bool { return r.Prediction == models.LabelCancer }
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"newest first", Filter{Tenant: "clinic-a"}, []string{"p4", "p3", "p2", "p1"}},
+		{"oldest first", Filter{Tenant: "clinic-a", OldestFirst: true}, []string{"p1", "p2", "p3", "p4"}},
+		{"limit", Filter{Tenant: "clinic-a", Limit: 2}, []string{"p4", "p3"}},
+		{"after a tie", Filter{Tenant: "clinic-a", After: &Position{base.Add(time.Minute), "p3"}}, []string{"p2", "p1"}},
+		{"after a tie, oldest first", Filter{Tenant: "clinic-a", OldestFirst: true, After: &Position{base.Add(time.Minute), "p2"}}, []string{"p3", "p4"}},
+		{"match before limit", Filter{Tenant: "clinic-a", Match: cancer, Limit: 2, After: &Position{base.Add(2 * time.Minute), "p4"}}, []string{"p2", "p1"}},
+	}
+	for name, s := range stores {
+		for _, tt := range tests {
+			recs, err := s.Find(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("%s: %s: %v", name, tt.name, err)
+			}
+			var got []string
+			for _, r := range recs {
+				got = append(got, r.PredictionID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("%s: %s = %v, want %v", name, tt.name, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestSQLStoreContract(t *testing.T) {
+	t.Run("plain", func(t *testing.T) { testStoreContract(t, newSQLiteStore(t, nil)) })
+	t.Run("sealed", func(t *testing.T) { testStoreContract(t, newSQLiteStore(t, testKeyring(t, "clinic-a", "clinic-b"))) })
+}
+
+func TestNewSQLStoreRejectsUnknownDialect(t *testing.T) {
+	_, err := NewSQLStore(context.Background(), SQLConfig{Dialect: "mysql", Driver: "sqlite", DSN: filepath.Join(t.TempDir(), "p.db")})
+	if err == nil || !strings.Contains(err.Error(), "mysql") {
+		t.Errorf("NewSQLStore error = %v, want the dialect refused", err)
+	}
+}
+
+func TestSQLSealedRecords(t *testing.T) {
+	ctx := context.Background()
+	dsn := filepath.Join(t.TempDir(), "predictions.db")
+	open := func(keys *tenantkey.Keyring) *SQLStore {
+		s, err := NewSQLStore(ctx, SQLConfig{Dialect: SQLite, Driver: "sqlite", DSN: dsn, Keys: keys})
+		if err != nil {
+			t.Fatalf("open store: %v", err)
+		}
+		t.Cleanup(func() { s.Close() })
+		return s
+	}
+	keyDir := testKeyDir(t, "clinic-a", "clinic-b")
+	s := open(loadKeys(t, keyDir))
+	for _, rec := range []models.StoredPrediction{testRecord("p1", "clinic-a", time.Now()), testRecord("p2", "clinic-b", time.Now())} {
+		if err := s.Put(ctx, rec); err != nil {
+			t.Fatalf("put %s: %v", rec.PredictionID, err)
+		}
+	}
+	if err := s.Put(ctx, testRecord("p3", "clinic-c", time.Now())); !errors.Is(err, tenantkey.ErrNoKey) {
+		t.Errorf("put for a tenant without a key: error = %v, want ErrNoKey", err)
+	}
+	if _, err := s.Get(ctx, "p3"); !errors.Is(err, ErrNotFound) {
+		t.Error("a record that could not be sealed was saved")
+	}
+
+	// A sealed record copied onto another row does not open there.
+	if _, err := s.db.ExecContext(ctx, `UPDATE predictions SET record = (SELECT record FROM predictions WHERE prediction_id = 'p2') WHERE prediction_id = 'p1'`); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.Get(ctx, "p1"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("get of a moved record = %+v, %v; want a decryption error", got, err)
+	}
+	if err := s.Delete(ctx, "p1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Remove(filepath.Join(keyDir, "clinic-b.key")); err != nil {
+		t.Fatal(err)
+	}
+	withdrawn := open(loadKeys(t, keyDir))
+	if _, err := withdrawn.Get(ctx, "p2"); !errors.Is(err, ErrNotFound) || !errors.Is(err, tenantkey.ErrNoKey) {
+		t.Errorf("get after the key was withdrawn: error = %v, want ErrNotFound and ErrNoKey", err)
+	}
+	if recs, err := withdrawn.Find(ctx, Filter{}); err != nil || len(recs) != 0 {
+		t.Errorf("find after the key was withdrawn = %v, %v; want the record skipped", recs, err)
+	}
+	if _, err := open(nil).Get(ctx, "p2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("get without keys: error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,139 @@
+// backend/internal/store/store.go
+/*
+ * This file defines the prediction store abstraction.
+ *
+ * Every prediction we return is recorded so it can be looked up later by
+ * our ID or by the integrator's own identifiers (accession number, client
+ * reference). Backends implement the Store interface; handlers only ever
+ * talk to the interface.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package store
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// ErrNotFound is returned when no prediction matches the requested ID.
+var ErrNotFound = errors.New("prediction not found")
+
+// Filter selects predictions. Empty fields match everything; set fields
+// must match exactly.
+type Filter struct {
+	Tenant          string
+	AccessionNumber string
+	ClientReference string
+	// IDs restricts results to these prediction IDs.
+	IDs []string
+	// CreatedFrom and CreatedTo bound the creation time (inclusive/exclusive).
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// Match, when set, must accept a record too. It is applied to the
+	// decoded records before Limit, for conditions a backend cannot
+	// evaluate itself, e.g. on sealed fields.
+	Match func(models.StoredPrediction) bool
+	// OldestFirst lists records oldest first instead of newest first.
+	OldestFirst bool
+	// After, when set, resumes a listing: only records ordered after this
+	// position are returned.
+	After *Position
+	// Limit caps the number of results; zero means no limit.
+	Limit int
+	// IncludeDeleted also returns soft-deleted records, which are
+	// otherwise hidden.
+	IncludeDeleted bool
+}
+
+// Position is a place in a listing. Records are ordered by creation time,
+// then by ID, both in the listing's direction.
+type Position struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Store persists prediction records.
+type Store interface {
+	// Put inserts a record, or replaces the record with the same ID.
+	Put(ctx context.Context, rec models.StoredPrediction) error
+	// Get returns the record with the given ID or ErrNotFound.
+	Get(ctx context.Context, id string) (models.StoredPrediction, error)
+	// Update applies fn to the record with the given ID and saves the
+	// result atomically, so concurrent read-modify-write cycles cannot
+	// interleave. If fn returns an error nothing is saved and the error is
+	// returned as is.
+	Update(ctx context.Context, id string, fn func(*models.StoredPrediction) error) (models.StoredPrediction, error)
+	// Find returns matching records, newest first unless f.OldestFirst.
+	Find(ctx context.Context, f Filter) ([]models.StoredPrediction, error)
+	// Delete permanently removes the record with the given ID, including
+	// every persisted copy of it, or returns ErrNotFound.
+	Delete(ctx context.Context, id string) error
+}
+
+// matches reports whether rec satisfies every set field of f.
+func (f Filter) matches(rec models.StoredPrediction) bool {
+	if !f.IncludeDeleted && rec.DeletedAt != nil {
+		return false
+	}
+	if f.Tenant != "" && rec.Tenant != f.Tenant {
+		return false
+	}
+	if f.AccessionNumber != "" && rec.AccessionNumber != f.AccessionNumber {
+		return false
+	}
+	if f.ClientReference != "" && rec.ClientReference != f.ClientReference {
+		return false
+	}
+	if len(f.IDs) > 0 && !slices.Contains(f.IDs, rec.PredictionID) {
+		return false
+	}
+	if !f.CreatedFrom.IsZero() && rec.CreatedAt.Before(f.CreatedFrom) {
+		return false
+	}
+	if !f.CreatedTo.IsZero() && !rec.CreatedAt.Before(f.CreatedTo) {
+		return false
+	}
+	if f.After != nil && !f.before(*f.After, Position{rec.CreatedAt, rec.PredictionID}) {
+		return false
+	}
+	return f.Match == nil || f.Match(rec)
+}
+
+// before reports whether a is listed before b in f's order.
+func (f Filter) before(a, b Position) bool {
+	c := a.CreatedAt.Compare(b.CreatedAt)
+	if c == 0 {
+		c = strings.Compare(a.ID, b.ID)
+	}
+	if f.OldestFirst {
+		return c < 0
+	}
+	return c > 0
+}
+
+// sortRecords orders recs as f lists them and applies its limit.
+func (f Filter) sortRecords(recs []models.StoredPrediction) []models.StoredPrediction {
+	slices.SortFunc(recs, func(a, b models.StoredPrediction) int {
+		pa, pb := Position{a.CreatedAt, a.PredictionID}, Position{b.CreatedAt, b.PredictionID}
+		switch {
+		case f.before(pa, pb):
+			return -1
+		case f.before(pb, pa):
+			return 1
+		}
+		return 0
+	})
+	if f.Limit > 0 && len(recs) > f.Limit {
+		recs = recs[:f.Limit]
+	}
+	return recs
+}
@@ -0,0 +1,339 @@
+// backend/internal/store/writebehind.go
+/*
+ * This file implements write-behind persistence for a store.
+ *
+ * Even with a deadline and a circuit breaker (see guard.go), a database
+ * outage loses the history of every prediction made while it lasts. A
+ * WriteBehind store writes each record to a local spool directory -- one
+ * atomically written file, which survives a crash -- and returns at once;
+ * a background Flusher applies the queued records to the database in
+ * order and removes them once written. An outage then only delays
+ * history: clinical responses never wait on the database, and nothing is
+ * lost while it is down.
+ *
+ * Records still queued are served from memory, so a prediction can be
+ * looked up right after it was made. Update and Delete go to the
+ * database, after writing or discarding the record's queued copies, so
+ * read-modify-write cycles and purges keep their guarantees.
+ *
+ * With tenant keys the queued records are encrypted with their tenant's
+ * key. A queued write cannot be applied without its key, so drain the
+ * queue before withdrawing a key: a process finding writes it cannot read
+ * refuses to start rather than discard them.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/spool"
+	"github.com/josephed37/mammoscan-AI/backend/internal/tenantkey"
+)
+
+// writeKind tags queued prediction writes in the spool.
+const writeKind = "prediction"
+
+// queued is a record with writes still in the spool.
+type queued struct {
+	rec models.StoredPrediction
+	// writes are the spool IDs of the record's queued writes, oldest
+	// first; rec is the latest.
+	writes []string
+}
+
+// WriteBehind is a Store that queues writes on local disk and applies
+// them to another store in the background.
+type WriteBehind struct {
+	s       Store
+	queue   *spool.Queue
+	flusher *spool.Flusher
+	keys    *tenantkey.Keyring
+
+	mu      sync.Mutex
+	pending map[string]*queued
+
+	// flushMu serialises writes of queued records, so an older version
+	// can never land after a newer one.
+	flushMu sync.Mutex
+}
+
+// NewWriteBehind returns a store queueing writes in queue and applying
+// them to s, encrypted with keys when not nil; run Flusher().Run to apply
+// them. Writes left in the queue by a previous process are picked up.
+func NewWriteBehind(s Store, queue *spool.Queue, keys *tenantkey.Keyring, cfg spool.FlusherConfig) (*WriteBehind, error) {
+	w := &WriteBehind{s: s, queue: queue, keys: keys, pending: make(map[string]*queued)}
+	leftover, err := queue.Pending(0)
+	if err != nil {
+		return nil, fmt.Errorf("read write-behind queue: %w", err)
+	}
+	for _, r := range leftover {
+		rec, err := w.decode(r)
+		if err != nil {
+			return nil, err
+		}
+		w.remember(r.ID, rec)
+	}
+	if cfg.Name == "" {
+		cfg.Name = "prediction store"
+	}
+	w.flusher = spool.NewFlusher(queue, w.apply, cfg)
+	return w, nil
+}
+
+// Flusher returns the loop applying queued writes.
+func (w *WriteBehind) Flusher() *spool.Flusher {
+	return w.flusher
+}
+
+// remember records a queued write of rec.
+func (w *WriteBehind) remember(writeID string, rec models.StoredPrediction) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	q, ok := w.pending[rec.PredictionID]
+	if !ok {
+		q = &queued{}
+		w.pending[rec.PredictionID] = q
+	}
+	q.rec = rec
+	q.writes = append(q.writes, writeID)
+}
+
+// apply writes the queued record of a spooled write. Only the latest
+// version is written, which also covers the record's older queued writes.
+func (w *WriteBehind) apply(ctx context.Context, r spool.Record) error {
+	rec, err := w.decode(r)
+	if err != nil {
+		return err
+	}
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+	w.mu.Lock()
+	q, ok := w.pending[rec.PredictionID]
+	stale := !ok || !slices.Contains(q.writes, r.ID)
+	w.mu.Unlock()
+	if stale {
+		// Already written with a later version, or purged.
+		return nil
+	}
+	return w.flushLocked(ctx, rec.PredictionID)
+}
+
+// flushLocked writes the latest queued version of a record and removes
+// the writes it covers. flushMu must be held.
+func (w *WriteBehind) flushLocked(ctx context.Context, id string) error {
+	w.mu.Lock()
+	q, ok := w.pending[id]
+	if !ok {
+		w.mu.Unlock()
+		return nil
+	}
+	rec, writes := q.rec, slices.Clone(q.writes)
+	w.mu.Unlock()
+
+	if err := w.s.Put(ctx, rec); err != nil {
+		return err
+	}
+	w.forget(id, writes)
+	return nil
+}
+
+// forget removes queued writes of a record from the spool and memory.
+func (w *WriteBehind) forget(id string, writes []string) {
+	w.mu.Lock()
+	if q, ok := w.pending[id]; ok {
+		q.writes = slices.DeleteFunc(q.writes, func(wid string) bool { return slices.Contains(writes, wid) })
+		if len(q.writes) == 0 {
+			delete(w.pending, id)
+		}
+	}
+	w.mu.Unlock()
+	for _, wid := range writes {
+		// A copy left behind is harmless: apply finds it stale.
+		w.queue.Ack(wid)
+	}
+}
+
+// Put implements Store. The record is durable on local disk when Put
+// returns; it reaches the wrapped store in the background.
+func (w *WriteBehind) Put(_ context.Context, rec models.StoredPrediction) error {
+	payload, err := w.encode(rec)
+	if err != nil {
+		return err
+	}
+	writeID := newWriteID()
+	// Remembered first, so the flusher never finds the write unknown.
+	w.remember(writeID, rec)
+	if err := w.queue.Enqueue(spool.Record{ID: writeID, Kind: writeKind, Payload: payload}, nil); err != nil {
+		w.forget(rec.PredictionID, []string{writeID})
+		return fmt.Errorf("queue write: %w", err)
+	}
+	w.flusher.Wake()
+	return nil
+}
+
+// sealedWrite is the spool payload of a write with tenant keys. The
+// record is sealed with its prediction ID as associated data, so it
+// cannot be passed off as a write of another prediction.
+type sealedWrite struct {
+	PredictionID string `json:"prediction_id"`
+	Record       string `json:"record"`
+}
+
+// encode returns the spool payload of rec: the record, or with keys a
+// sealedWrite.
+func (w *WriteBehind) encode(rec models.StoredPrediction) ([]byte, error) {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return nil, fmt.Errorf("encode record: %w", err)
+	}
+	if w.keys == nil {
+		return payload, nil
+	}
+	sealed, err := w.keys.SealText(rec.Tenant, payload, []byte(rec.PredictionID))
+	if err != nil {
+		return nil, fmt.Errorf("encrypt record: %w", err)
+	}
+	return json.Marshal(sealedWrite{PredictionID: rec.PredictionID, Record: sealed})
+}
+
+// decode reads the record of a spooled write. Plain records, queued before
+// keys were configured, are read as they are.
+func (w *WriteBehind) decode(r spool.Record) (models.StoredPrediction, error) {
+	payload := []byte(r.Payload)
+	var sealed sealedWrite
+	if json.Unmarshal(payload, &sealed) == nil && tenantkey.IsSealedText(sealed.Record) {
+		if w.keys == nil {
+			return models.StoredPrediction{}, fmt.Errorf("queued write %s is encrypted and no tenant keys are configured", r.ID)
+		}
+		opened, err := w.keys.OpenText(sealed.Record, []byte(sealed.PredictionID))
+		if err != nil {
+			return models.StoredPrediction{}, fmt.Errorf("decrypt queued write %s: %w", r.ID, err)
+		}
+		payload = opened
+	}
+	var rec models.StoredPrediction
+	if err := json.Unmarshal(payload, &rec); err != nil {
+		return models.StoredPrediction{}, fmt.Errorf("decode queued write %s: %w", r.ID, err)
+	}
+	return rec, nil
+}
+
+// Get implements Store, answering from the queue while a record's writes
+// are pending.
+func (w *WriteBehind) Get(ctx context.Context, id string) (models.StoredPrediction, error) {
+	w.mu.Lock()
+	q, ok := w.pending[id]
+	var rec models.StoredPrediction
+	if ok {
+		rec = q.rec
+	}
+	w.mu.Unlock()
+	if ok {
+		return rec, nil
+	}
+	return w.s.Get(ctx, id)
+}
+
+// Update implements Store. Pending writes of the record are applied
+// first, so fn sees the latest version.
+func (w *WriteBehind) Update(ctx context.Context, id string, fn func(*models.StoredPrediction) error) (models.StoredPrediction, error) {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+	if err := w.flushLocked(ctx, id); err != nil {
+		return models.StoredPrediction{}, err
+	}
+	return w.s.Update(ctx, id, fn)
+}
+
+// Find implements Store, merging pending records into the wrapped
+// store's results.
+func (w *WriteBehind) Find(ctx context.Context, f Filter) ([]models.StoredPrediction, error) {
+	// Stored copies of queued records are dropped below, so as many more
+	// are read as are queued.
+	stored := f
+	if f.Limit > 0 {
+		w.mu.Lock()
+		stored.Limit += len(w.pending)
+		w.mu.Unlock()
+	}
+	recs, err := w.s.Find(ctx, stored)
+	if err != nil {
+		return nil, err
+	}
+	w.mu.Lock()
+	var out []models.StoredPrediction
+	for _, rec := range recs {
+		if _, ok := w.pending[rec.PredictionID]; !ok {
+			out = append(out, rec)
+		}
+	}
+	for _, q := range w.pending {
+		if f.matches(q.rec) {
+			out = append(out, q.rec)
+		}
+	}
+	w.mu.Unlock()
+	return f.sortRecords(out), nil
+}
+
+// Delete implements Store. The record's queued copies are removed from
+// the spool as well, so no copy of it remains on disk.
+func (w *WriteBehind) Delete(ctx context.Context, id string) error {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+	w.mu.Lock()
+	q, wasPending := w.pending[id]
+	var writes []string
+	if wasPending {
+		writes = slices.Clone(q.writes)
+	}
+	w.mu.Unlock()
+
+	err := w.s.Delete(ctx, id)
+	if errors.Is(err, ErrNotFound) && wasPending {
+		// The record had never reached the wrapped store.
+		err = nil
+	}
+	if err == nil {
+		w.forget(id, writes)
+	}
+	return err
+}
+
+// Pending returns the number of writes not yet applied.
+func (w *WriteBehind) Pending() int {
+	return w.flusher.Pending()
+}
+
+// Close closes the wrapped store if it has anything to close. Queued
+// writes stay on disk for the next process.
+func (w *WriteBehind) Close() error {
+	if closer, ok := w.s.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// newWriteID returns a time-ordered ID, so the spool applies writes in
+// the order they were made.
+func newWriteID() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.NewString()
+	}
+	return id.String()
+}
@@ -0,0 +1,201 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/spool"
+	"github.com/josephed37/mammoscan-AI/backend/internal/tenantkey"
+)
+
+// outageStore is a Store whose writes fail while down is set.
+type outageStore struct {
+	Store
+	down atomic.Bool
+}
+
+func (s *outageStore) Put(ctx context.Context, rec models.StoredPrediction) error {
+	if s.down.Load() {
+		return errors.New("database unavailable")
+	}
+	return s.Store.Put(ctx, rec)
+}
+
+// newWriteBehind returns a write-behind store over a memory store, with
+// its queue in dir.
+func newWriteBehind(t *testing.T, dir string, keys *tenantkey.Keyring) (*WriteBehind, *outageStore) {
+	t.Helper()
+	queue, err := spool.Open(dir)
+	if err != nil {
+		t.Fatalf("open queue: %v", err)
+	}
+	mem, err := NewMemoryStore(MemoryConfig{})
+	if err != nil {
+		t.Fatalf("new memory store: %v", err)
+	}
+	db := &outageStore{Store: mem}
+	w, err := NewWriteBehind(db, queue, keys, spool.FlusherConfig{})
+	if err != nil {
+		t.Fatalf("new write-behind: %v", err)
+	}
+	return w, db
+}
+
+func TestWriteBehindContract(t *testing.T) {
+	t.Run("plain", func(t *testing.T) {
+		w, _ := newWriteBehind(t, t.TempDir(), nil)
+		testStoreContract(t, w)
+	})
+	t.Run("sealed", func(t *testing.T) {
+		w, _ := newWriteBehind(t, t.TempDir(), testKeyring(t, "clinic-a", "clinic-b"))
+		testStoreContract(t, w)
+	})
+}
+
+func TestWriteBehindOutage(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	keys := testKeyring(t, "clinic-a")
+	w, db := newWriteBehind(t, dir, keys)
+	db.down.Store(true)
+
+	rec := testRecord("p1", "clinic-a", time.Now())
+	if err := w.Put(ctx, rec); err != nil {
+		t.Fatalf("put during an outage: %v", err)
+	}
+	if err := w.Put(ctx, testRecord("p2", "clinic-b", time.Now())); !errors.Is(err, tenantkey.ErrNoKey) {
+		t.Errorf("put for a tenant without a key: error = %v, want ErrNoKey", err)
+	}
+	if got, err := w.Get(ctx, "p1"); err != nil || got.Prediction != rec.Prediction {
+		t.Errorf("get of a queued record = %+v, %v", got, err)
+	}
+	if _, err := w.Flusher().FlushOnce(ctx); err == nil {
+		t.Error("flush during an outage succeeded")
+	}
+	if w.Pending() != 1 {
+		t.Fatalf("pending = %d, want the write kept", w.Pending())
+	}
+	if _, err := db.Get(ctx, "p1"); !errors.Is(err, ErrNotFound) {
+		t.Error("write reached the database during the outage")
+	}
+
+	// A restarted process picks the write up; without keys it refuses to
+	// start rather than drop it.
+	queue, _ := spool.Open(dir)
+	if _, err := NewWriteBehind(db, queue, nil, spool.FlusherConfig{}); err == nil {
+		t.Error("started without keys over sealed queued writes")
+	}
+	restarted, err := NewWriteBehind(db, queue, keys, spool.FlusherConfig{})
+	if err != nil {
+		t.Fatalf("restart: %v", err)
+	}
+	if _, err := restarted.Get(ctx, "p1"); err != nil {
+		t.Errorf("get after restart: %v", err)
+	}
+
+	db.down.Store(false)
+	if n, err := restarted.Flusher().FlushOnce(ctx); err != nil || n != 1 {
+		t.Fatalf("flush after the outage = %d, %v", n, err)
+	}
+	if restarted.Pending() != 0 {
+		t.Errorf("pending after flush = %d", restarted.Pending())
+	}
+	if got, err := db.Get(ctx, "p1"); err != nil || got.Prediction != rec.Prediction {
+		t.Errorf("database after flush = %+v, %v", got, err)
+	}
+}
+
+func TestWriteBehindAppliesLatestVersion(t *testing.T) {
+	ctx := context.Background()
+	w, db := newWriteBehind(t, t.TempDir(), nil)
+	db.down.Store(true)
+	rec := testRecord("p1", "clinic-a", time.Now())
+	w.Put(ctx, rec)
+	rec.Prediction = models.LabelNonCancer
+	w.Put(ctx, rec)
+
+	// Update applies the queued writes first, and fails with them.
+	if _, err := w.Update(ctx, "p1", func(*models.StoredPrediction) error { return nil }); err == nil {
+		t.Error("update during an outage succeeded")
+	}
+	db.down.Store(false)
+	got, err := w.Update(ctx, "p1", func(r *models.StoredPrediction) error {
+		if r.Prediction != models.LabelNonCancer {
+			t.Errorf("update saw %q, want the latest queued version", r.Prediction)
+		}
+		r.ModelVersion = "v8"
+		return nil
+	})
+	if err != nil || got.ModelVersion != "v8" {
+		t.Fatalf("update = %+v, %v", got, err)
+	}
+	// The writes the update covered are stale and must not overwrite it.
+	if _, err := w.Flusher().FlushOnce(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if got, _ := db.Get(ctx, "p1"); got.ModelVersion != "v8" {
+		t.Errorf("stale queued write overwrote the update: %+v", got)
+	}
+	if w.Pending() != 0 {
+		t.Errorf("pending = %d, want 0", w.Pending())
+	}
+}
+
+func TestWriteBehindDeletePurgesQueue(t *testing.T) {
+	ctx := context.Background()
+	w, db := newWriteBehind(t, t.TempDir(), nil)
+	db.down.Store(true)
+	w.Put(ctx, testRecord("p1", "clinic-a", time.Now()))
+	if err := w.Delete(ctx, "p1"); err != nil {
+		t.Fatalf("delete of a queued record: %v", err)
+	}
+	if w.Pending() != 0 {
+		t.Errorf("pending = %d, want the queued copy removed", w.Pending())
+	}
+	if _, err := w.Get(ctx, "p1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("get after delete: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestWriteBehindSealedWriteBoundToPrediction(t *testing.T) {
+	queue, err := spool.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("open queue: %v", err)
+	}
+	mem, err := NewMemoryStore(MemoryConfig{})
+	if err != nil {
+		t.Fatalf("new memory store: %v", err)
+	}
+	w, err := NewWriteBehind(mem, queue, testKeyring(t, "clinic-a"), spool.FlusherConfig{})
+	if err != nil {
+		t.Fatalf("new write-behind: %v", err)
+	}
+	rec := testRecord("p1", "clinic-a", time.Now())
+	payload, err := w.encode(rec)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if strings.Contains(string(payload), rec.AccessionNumber) || strings.Contains(string(payload), rec.ModelName) {
+		t.Fatalf("payload holds the record in plaintext: %s", payload)
+	}
+	got, err := w.decode(spool.Record{ID: "w1", Kind: writeKind, Payload: payload})
+	if err != nil || got.PredictionID != rec.PredictionID || got.Prediction != rec.Prediction {
+		t.Fatalf("decode = %+v, %v; want the record", got, err)
+	}
+
+	var sealed sealedWrite
+	if err := json.Unmarshal(payload, &sealed); err != nil {
+		t.Fatalf("read payload: %v", err)
+	}
+	sealed.PredictionID = "p2"
+	moved, _ := json.Marshal(sealed)
+	if got, err := w.decode(spool.Record{ID: "w2", Kind: writeKind, Payload: moved}); err == nil {
+		t.Errorf("decode of a record filed under another prediction = %+v, want an error", got)
+	}
+}
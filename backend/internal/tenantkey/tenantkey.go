@@ -0,0 +1,275 @@
+// backend/internal/tenantkey/tenantkey.go
+/*
+ * This file implements per-tenant encryption of persisted data.
+ *
+ * Each tenant's prediction history, retained images and queued writes are
+ * encrypted with that tenant's own key (AES-256-GCM), so a leaked key
+ * exposes one tenant and a tenant's data can be made unreadable -- at the
+ * end of a contract, say -- by withdrawing its key, without touching
+ * anyone else's. Withdrawing a key hides data, it does not delete it, and
+ * it is no way to serve a legal hold: held data must stay readable, so a
+ * tenant under hold keeps its key.
+ *
+ * Keys live in a directory, one file per tenant (`<tenant>.key`), holding
+ * base64-encoded 32-byte keys one per line: the first line encrypts, the
+ * others only decrypt, so a key is rotated by adding a new first line.
+ * `_default.key`, required, serves data without a tenant. A tenant with no
+ * file of its own -- never given one, or whose key was withdrawn -- has
+ * nothing sealed or looked up for it: its data is refused with ErrNoKey
+ * rather than written under a key its owner does not control. Only with
+ * Config.DefaultFallback do such tenants share the default key. Sealed
+ * data names the key that sealed it by an opaque ID derived from the key,
+ * never by tenant.
+ *
+ * Records looked up by value (accession number, client reference) are
+ * stored as blind indexes: a keyed hash per tenant key, so equality
+ * lookups still work within a tenant and reveal nothing across tenants.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package tenantkey
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultTenant names the key file used for data without a tenant, and,
+// with Config.DefaultFallback, for tenants without a key of their own.
+const DefaultTenant = "_default"
+
+// keySize is the AES-256 key length.
+const keySize = 32
+
+// magic starts every sealed blob; TextPrefix every sealed text.
+const (
+	magic      = "MSK1"
+	TextPrefix = "sealed:"
+)
+
+// idSize is the length of the key ID in a sealed blob.
+const idSize = 8
+
+// ErrNoKey is returned when the key needed is not (or no longer) in the
+// keyring.
+var ErrNoKey = errors.New("encryption key not available")
+
+// key is one tenant key.
+type key struct {
+	tenant string
+	id     string
+	aead   cipher.AEAD
+	index  []byte
+}
+
+// Config controls how a keyring is used.
+type Config struct {
+	// DefaultFallback seals and looks up the data of tenants without a
+	// key of their own with the default key, instead of refusing it.
+	DefaultFallback bool
+}
+
+// Keyring holds the keys of every tenant.
+type Keyring struct {
+	// tenants maps a tenant to its keys, the sealing key first.
+	tenants map[string][]*key
+	byID    map[string]*key
+	cfg     Config
+}
+
+// LoadDir reads every `<tenant>.key` file in dir.
+func LoadDir(dir string, cfg Config) (*Keyring, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.key"))
+	if err != nil {
+		return nil, err
+	}
+	k := &Keyring{tenants: make(map[string][]*key), byID: make(map[string]*key), cfg: cfg}
+	for _, path := range paths {
+		tenant := strings.TrimSuffix(filepath.Base(path), ".key")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := k.add(tenant, data); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if _, ok := k.tenants[DefaultTenant]; !ok {
+		// Without it, data of tenants without keys could not be written.
+		return nil, fmt.Errorf("no %s.key in %s", DefaultTenant, dir)
+	}
+	return k, nil
+}
+
+// add parses the keys of a tenant, one base64 key per line.
+func (k *Keyring) add(tenant string, data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(text)
+		if err != nil || len(raw) != keySize {
+			return fmt.Errorf("line %d: want a base64-encoded %d-byte key", line, keySize)
+		}
+		block, err := aes.NewCipher(raw)
+		if err != nil {
+			return err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(raw)
+		index := hmac.New(sha256.New, raw)
+		index.Write([]byte("blind index"))
+		kk := &key{tenant: tenant, id: string(sum[:idSize]), aead: aead, index: index.Sum(nil)}
+		if other, dup := k.byID[kk.id]; dup {
+			return fmt.Errorf("line %d: key already used for tenant %s", line, other.tenant)
+		}
+		k.byID[kk.id] = kk
+		k.tenants[tenant] = append(k.tenants[tenant], kk)
+	}
+	if len(k.tenants[tenant]) == 0 {
+		return fmt.Errorf("no keys")
+	}
+	return scanner.Err()
+}
+
+// Len returns the number of tenants with keys, the default included.
+func (k *Keyring) Len() int {
+	return len(k.tenants)
+}
+
+// Has reports whether data of tenant can be sealed: it has a key, or
+// shares the default one.
+func (k *Keyring) Has(tenant string) bool {
+	_, err := k.keysOf(tenant)
+	return err == nil
+}
+
+// keysOf returns the keys of tenant: the default keys for data without a
+// tenant, and for a tenant without keys only with Config.DefaultFallback.
+func (k *Keyring) keysOf(tenant string) ([]*key, error) {
+	if keys, ok := k.tenants[tenant]; ok && tenant != "" {
+		return keys, nil
+	}
+	if tenant == "" || k.cfg.DefaultFallback {
+		if keys, ok := k.tenants[DefaultTenant]; ok {
+			return keys, nil
+		}
+	}
+	return nil, fmt.Errorf("%w for tenant %q", ErrNoKey, tenant)
+}
+
+// Seal encrypts plaintext with the current key of tenant; it returns an
+// error wrapping ErrNoKey when the tenant has none. aad is
+// authenticated but not encrypted: binding a blob to its record ID stops
+// it from being replayed under another.
+func (k *Keyring) Seal(tenant string, plaintext, aad []byte) ([]byte, error) {
+	keys, err := k.keysOf(tenant)
+	if err != nil {
+		return nil, err
+	}
+	kk := keys[0]
+	nonce := make([]byte, kk.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, len(magic)+idSize+len(nonce)+len(plaintext)+kk.aead.Overhead())
+	out = append(out, magic...)
+	out = append(out, kk.id...)
+	out = append(out, nonce...)
+	return kk.aead.Seal(out, nonce, plaintext, aad), nil
+}
+
+// Open decrypts a blob sealed with any key in the keyring. It returns an
+// error wrapping ErrNoKey when the sealing key has been withdrawn.
+func (k *Keyring) Open(sealed, aad []byte) ([]byte, error) {
+	if !IsSealed(sealed) {
+		return nil, fmt.Errorf("not sealed data")
+	}
+	id := string(sealed[len(magic) : len(magic)+idSize])
+	kk, ok := k.byID[id]
+	if !ok {
+		return nil, ErrNoKey
+	}
+	rest := sealed[len(magic)+idSize:]
+	if len(rest) < kk.aead.NonceSize() {
+		return nil, fmt.Errorf("truncated sealed data")
+	}
+	nonce, ciphertext := rest[:kk.aead.NonceSize()], rest[kk.aead.NonceSize():]
+	plaintext, err := kk.aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+	return plaintext, nil
+}
+
+// SealText is Seal for text columns and lines: TextPrefix followed by the
+// base64 blob.
+func (k *Keyring) SealText(tenant string, plaintext, aad []byte) (string, error) {
+	sealed, err := k.Seal(tenant, plaintext, aad)
+	if err != nil {
+		return "", err
+	}
+	return TextPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// OpenText opens what SealText produced.
+func (k *Keyring) OpenText(text string, aad []byte) ([]byte, error) {
+	b64, ok := strings.CutPrefix(text, TextPrefix)
+	if !ok {
+		return nil, fmt.Errorf("not sealed text")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return nil, fmt.Errorf("decode sealed text: %w", err)
+	}
+	return k.Open(sealed, aad)
+}
+
+// Index returns the blind indexes of value for tenant, one per key of the
+// tenant, the current key's first. Empty values are not indexed.
+func (k *Keyring) Index(tenant, value string) []string {
+	if value == "" {
+		return nil
+	}
+	keys, err := k.keysOf(tenant)
+	if err != nil {
+		return nil
+	}
+	out := make([]string, len(keys))
+	for i, kk := range keys {
+		mac := hmac.New(sha256.New, kk.index)
+		mac.Write([]byte(value))
+		out[i] = "idx:" + hex.EncodeToString(mac.Sum(nil))
+	}
+	return out
+}
+
+// IsSealed reports whether data is a sealed blob.
+func IsSealed(data []byte) bool {
+	return len(data) > len(magic)+idSize && string(data[:len(magic)]) == magic
+}
+
+// IsSealedText reports whether text was produced by SealText.
+func IsSealedText(text string) bool {
+	return strings.HasPrefix(text, TextPrefix)
+}
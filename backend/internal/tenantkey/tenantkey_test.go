@@ -0,0 +1,202 @@
+package tenantkey
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func newKey(t *testing.T) string {
+	t.Helper()
+	raw := make([]byte, keySize)
+	if _, err := rand.Read(raw); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+// keyDir writes one key file per tenant, each holding the given lines.
+func keyDir(t *testing.T, files map[string][]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for tenant, lines := range files {
+		if err := os.WriteFile(filepath.Join(dir, tenant+".key"), []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func load(t *testing.T, dir string, cfg Config) *Keyring {
+	t.Helper()
+	k, err := LoadDir(dir, cfg)
+	if err != nil {
+		t.Fatalf("LoadDir: %v", err)
+	}
+	return k
+}
+
+func TestSealOpen(t *testing.T) {
+	k := load(t, keyDir(t, map[string][]string{
+		DefaultTenant: {newKey(t)},
+		"clinic-a":    {"# current", newKey(t), ""},
+	}), Config{})
+
+	sealed, err := k.Seal("clinic-a", []byte("study"), []byte("pred-1"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if !IsSealed(sealed) || bytes.Contains(sealed, []byte("study")) || bytes.Contains(sealed, []byte("clinic-a")) {
+		t.Fatalf("sealed blob %q leaks its plaintext or tenant", sealed)
+	}
+	if got, err := k.Open(sealed, []byte("pred-1")); err != nil || string(got) != "study" {
+		t.Fatalf("Open = %q, %v", got, err)
+	}
+	if _, err := k.Open(sealed, []byte("pred-2")); err == nil {
+		t.Error("Open with another record's AAD succeeded")
+	}
+	tampered := slices.Clone(sealed)
+	tampered[len(tampered)-1] ^= 1
+	if _, err := k.Open(tampered, []byte("pred-1")); err == nil {
+		t.Error("Open of a tampered blob succeeded")
+	}
+	for name, blob := range map[string][]byte{
+		"plaintext": []byte("study"),
+		"truncated": sealed[:len(magic)+idSize+4],
+		"empty":     nil,
+	} {
+		if _, err := k.Open(blob, []byte("pred-1")); err == nil {
+			t.Errorf("Open of %s data succeeded", name)
+		}
+	}
+
+	text, err := k.SealText("", []byte("no tenant"), nil)
+	if err != nil {
+		t.Fatalf("SealText without a tenant: %v", err)
+	}
+	if !IsSealedText(text) {
+		t.Fatalf("SealText = %q, want the %q prefix", text, TextPrefix)
+	}
+	if got, err := k.OpenText(text, nil); err != nil || string(got) != "no tenant" {
+		t.Errorf("OpenText = %q, %v", got, err)
+	}
+	for _, bad := range []string{"no tenant", TextPrefix + "%%%", TextPrefix + base64.StdEncoding.EncodeToString([]byte("MSK1short"))} {
+		if _, err := k.OpenText(bad, nil); err == nil {
+			t.Errorf("OpenText(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestTenantWithoutKey(t *testing.T) {
+	dir := keyDir(t, map[string][]string{DefaultTenant: {newKey(t)}})
+	strict := load(t, dir, Config{})
+	if strict.Has("clinic-b") {
+		t.Error("Has(clinic-b) without a key")
+	}
+	if !strict.Has("") {
+		t.Error("data without a tenant cannot be sealed")
+	}
+	if _, err := strict.Seal("clinic-b", []byte("x"), nil); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Seal for a tenant without a key: error = %v, want ErrNoKey", err)
+	}
+	if _, err := strict.SealText("clinic-b", []byte("x"), nil); !errors.Is(err, ErrNoKey) {
+		t.Errorf("SealText for a tenant without a key: error = %v, want ErrNoKey", err)
+	}
+	if got := strict.Index("clinic-b", "ACC-1"); got != nil {
+		t.Errorf("Index for a tenant without a key = %v, want none", got)
+	}
+	// The reserved name is not a tenant's own key.
+	if _, err := strict.Seal(DefaultTenant, []byte("x"), nil); err != nil {
+		t.Errorf("Seal for %s: %v", DefaultTenant, err)
+	}
+
+	shared := load(t, dir, Config{DefaultFallback: true})
+	sealed, err := shared.Seal("clinic-b", []byte("x"), nil)
+	if err != nil {
+		t.Fatalf("Seal with DefaultFallback: %v", err)
+	}
+	if _, err := strict.Open(sealed, nil); err != nil {
+		t.Errorf("default-sealed data not readable: %v", err)
+	}
+}
+
+func TestRotationAndWithdrawal(t *testing.T) {
+	oldKey, newKeyLine := newKey(t), newKey(t)
+	before := load(t, keyDir(t, map[string][]string{DefaultTenant: {newKey(t)}, "clinic-a": {oldKey}}), Config{})
+	sealed, err := before.Seal("clinic-a", []byte("study"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldIndex := before.Index("clinic-a", "ACC-1")
+
+	rotated := load(t, keyDir(t, map[string][]string{DefaultTenant: {newKey(t)}, "clinic-a": {newKeyLine, oldKey}}), Config{})
+	if got, err := rotated.Open(sealed, nil); err != nil || string(got) != "study" {
+		t.Errorf("Open with a rotated-out key = %q, %v", got, err)
+	}
+	resealed, err := rotated.Seal("clinic-a", []byte("study"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := before.Open(resealed, nil); !errors.Is(err, ErrNoKey) {
+		t.Errorf("new key used for sealing: Open with only the old key error = %v, want ErrNoKey", err)
+	}
+	index := rotated.Index("clinic-a", "ACC-1")
+	if len(index) != 2 || index[1] != oldIndex[0] || index[0] == oldIndex[0] {
+		t.Errorf("Index after rotation = %v, want the new key's first and %v second", index, oldIndex)
+	}
+
+	withdrawn := load(t, keyDir(t, map[string][]string{DefaultTenant: {newKey(t)}}), Config{})
+	if _, err := withdrawn.Open(sealed, nil); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Open after withdrawal: error = %v, want ErrNoKey", err)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	k := load(t, keyDir(t, map[string][]string{DefaultTenant: {newKey(t)}, "clinic-a": {newKey(t)}, "clinic-b": {newKey(t)}}), Config{})
+	a := k.Index("clinic-a", "ACC-1")
+	if len(a) != 1 || !strings.HasPrefix(a[0], "idx:") || strings.Contains(a[0], "ACC-1") {
+		t.Fatalf("Index = %v", a)
+	}
+	if again := k.Index("clinic-a", "ACC-1"); !slices.Equal(a, again) {
+		t.Error("Index is not deterministic")
+	}
+	if other := k.Index("clinic-a", "ACC-2"); slices.Equal(a, other) {
+		t.Error("different values share an index")
+	}
+	if b := k.Index("clinic-b", "ACC-1"); slices.Equal(a, b) {
+		t.Error("the same value indexes alike across tenants")
+	}
+	if got := k.Index("clinic-a", ""); got != nil {
+		t.Errorf("Index of an empty value = %v, want none", got)
+	}
+}
+
+func TestLoadDirRejectsMalformedKeys(t *testing.T) {
+	shared := newKey(t)
+	cases := []struct {
+		name  string
+		files map[string][]string
+		want  string
+	}{
+		{"no default", map[string][]string{"clinic-a": {newKey(t)}}, "no _default.key"},
+		{"empty dir", nil, "no _default.key"},
+		{"not base64", map[string][]string{DefaultTenant: {"not base64!"}}, "line 1"},
+		{"short key", map[string][]string{DefaultTenant: {newKey(t)}, "clinic-a": {"# rotated", base64.StdEncoding.EncodeToString(make([]byte, 16))}}, "line 2"},
+		{"no keys", map[string][]string{DefaultTenant: {newKey(t)}, "clinic-a": {"# withdrawn"}}, "no keys"},
+		{"shared key", map[string][]string{DefaultTenant: {shared}, "clinic-a": {shared}}, "already used"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadDir(keyDir(t, tc.files), Config{})
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("LoadDir error = %v, want one mentioning %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// backend/internal/threshold/threshold.go
+/*
+ * This file resolves the decision threshold of a model.
+ *
+ * A score at or above the threshold is labelled malignant. Where the
+ * threshold sits trades sensitivity against specificity, and each model
+ * version needs its own: a retrained model's scores are distributed
+ * differently. The threshold of a model is, in order of precedence:
+ *
+ *   1. the operator's override for its version (or name), from a
+ *      thresholds file
+ *   2. the threshold in the model's metadata sidecar, published by the
+ *      training pipeline alongside the artifact
+ *   3. the operator's default
+ *   4. the threshold chosen in our analysis of the champion model
+ *
+ * The thresholds file is JSON:
+ *
+ *   {"default": 0.12, "versions": {"1718000000000000": 0.105}}
+ *
+ * and the sidecar a JSON object with at least a "threshold" field.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package threshold
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config holds the operator's thresholds.
+type Config struct {
+	// Default applies to models with no override or sidecar; zero falls
+	// back to the built-in threshold.
+	Default float64 `json:"default,omitempty"`
+	// Versions overrides the threshold per model version or name.
+	Versions map[string]float64 `json:"versions,omitempty"`
+}
+
+// Load reads a thresholds file.
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, cfg.Validate()
+}
+
+// Validate checks that every threshold is a probability.
+func (c Config) Validate() error {
+	if c.Default != 0 {
+		if err := Check(c.Default); err != nil {
+			return fmt.Errorf("default: %w", err)
+		}
+	}
+	for v, t := range c.Versions {
+		if err := Check(t); err != nil {
+			return fmt.Errorf("version %s: %w", v, err)
+		}
+	}
+	return nil
+}
+
+// Check validates a single threshold.
+func Check(t float64) error {
+	if !(t > 0 && t < 1) {
+		return fmt.Errorf("threshold must be between 0 and 1 exclusive, got %v", t)
+	}
+	return nil
+}
+
+// Resolve returns the threshold of the model named name at version.
+// sidecar is the threshold from its metadata sidecar, zero if none;
+// builtin is the last resort.
+func (c Config) Resolve(name, version string, sidecar, builtin float64) float64 {
+	if t, ok := c.Versions[version]; ok && version != "" {
+		return t
+	}
+	if t, ok := c.Versions[name]; ok && name != "" {
+		return t
+	}
+	if sidecar != 0 {
+		return sidecar
+	}
+	if c.Default != 0 {
+		return c.Default
+	}
+	return builtin
+}
+
+// ParseSidecar extracts the threshold from a model metadata sidecar;
+// zero when it names none.
+func ParseSidecar(data []byte) (float64, error) {
+	var meta struct {
+		Threshold float64 `json:"threshold"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return 0, fmt.Errorf("model metadata: %w", err)
+	}
+	if meta.Threshold == 0 {
+		return 0, nil
+	}
+	if err := Check(meta.Threshold); err != nil {
+		return 0, fmt.Errorf("model metadata: %w", err)
+	}
+	return meta.Threshold, nil
+}
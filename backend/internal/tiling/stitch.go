@@ -0,0 +1,60 @@
+// backend/internal/tiling/stitch.go
+/*
+ * This file stitches patch scores into a probability map.
+ *
+ * The map is a coarse grid laid over the scored image. Each cell takes the
+ * mean score of the patches covering its centre, so overlapping patches
+ * blend smoothly and a suspicious region shows up where the patches that
+ * saw it overlap. It is a localisation hint, not a segmentation.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package tiling
+
+import (
+	"math"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// DefaultMapSize is the number of map cells along the image's long side.
+const DefaultMapSize = 32
+
+// Stitch builds a probability map with size cells along the long side of
+// the scored image.
+func Stitch(t *models.TiledScore, size int) *models.ProbabilityMap {
+	if size <= 0 {
+		size = DefaultMapSize
+	}
+	cell := float64(max(t.Width, t.Height)) / float64(size)
+	m := &models.ProbabilityMap{
+		Width:     max(int(math.Round(float64(t.Width)/cell)), 1),
+		Height:    max(int(math.Round(float64(t.Height)/cell)), 1),
+		CellPixel: cell,
+	}
+	m.Values = make([][]float64, m.Height)
+	for y := range m.Values {
+		m.Values[y] = make([]float64, m.Width)
+		cy := (float64(y) + 0.5) * float64(t.Height) / float64(m.Height)
+		for x := range m.Values[y] {
+			cx := (float64(x) + 0.5) * float64(t.Width) / float64(m.Width)
+			var sum float64
+			var n int
+			for _, p := range t.Patches {
+				if cx >= float64(p.X) && cx < float64(p.X+p.Width) && cy >= float64(p.Y) && cy < float64(p.Y+p.Height) {
+					sum += p.Score
+					n++
+				}
+			}
+			if n > 0 {
+				// Three decimals are plenty for a display map and keep
+				// the response small.
+				m.Values[y][x] = math.Round(sum/float64(n)*1000) / 1000
+			}
+		}
+	}
+	return m
+}
@@ -0,0 +1,176 @@
+// backend/internal/tiling/tiling.go
+/*
+ * This file implements tiled inference.
+ *
+ * High-resolution models are trained on patches of the full-resolution
+ * film rather than on a downscaled whole image. In tiling mode the decoded
+ * image is cut into overlapping square patches, each patch is scored on
+ * its own, and the patch scores are aggregated into the study score:
+ *
+ *   max       the most suspicious patch decides (the usual choice)
+ *   mean      average over patches
+ *   noisy_or  1 - Π(1 - p): any suspicious patch raises the score; with
+ *             heavy overlap the same lesion is counted several times
+ *
+ * The patch scores are also stitched into a low-resolution probability
+ * map (see stitch.go).
+ *
+ * Only one patch tensor exists at a time, so memory use is bounded by the
+ * patch size rather than by the model's notional full-image input.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package tiling
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// Aggregation names.
+const (
+	Max     = "max"
+	Mean    = "mean"
+	NoisyOr = "noisy_or"
+)
+
+// DefaultMaxPatches bounds the work done for a single image.
+const DefaultMaxPatches = 256
+
+// ErrTooManyPatches is returned when an image would need more patches
+// than the configured maximum.
+var ErrTooManyPatches = errors.New("image needs too many patches")
+
+// Config describes the tiling.
+type Config struct {
+	// PatchSize is the side of a patch in source pixels.
+	PatchSize int
+	// Overlap is the fraction of a patch shared with its neighbour.
+	Overlap float64
+	// Aggregation combines patch scores: Max, Mean or NoisyOr.
+	Aggregation string
+	// MaxPatches bounds the number of patches per image.
+	MaxPatches int
+	// MapSize is the probability map's resolution along the long side;
+	// negative disables the map.
+	MapSize int
+}
+
+// Validate checks the configuration.
+func (c Config) Validate() error {
+	if c.PatchSize <= 0 {
+		return fmt.Errorf("patch size must be positive, got %d", c.PatchSize)
+	}
+	if c.Overlap < 0 || c.Overlap >= 1 {
+		return fmt.Errorf("overlap must be in [0, 1), got %v", c.Overlap)
+	}
+	switch c.Aggregation {
+	case Max, Mean, NoisyOr:
+	default:
+		return fmt.Errorf("unknown aggregation %q (want max, mean or noisy_or)", c.Aggregation)
+	}
+	return nil
+}
+
+// ScoreFunc scores one patch.
+type ScoreFunc func(patch image.Image) (float64, error)
+
+// Run tiles img, scores every patch and aggregates the scores.
+func Run(img image.Image, cfg Config, score ScoreFunc) (float64, *models.TiledScore, error) {
+	b := img.Bounds()
+	xs := offsets(b.Dx(), cfg.PatchSize, cfg.Overlap)
+	ys := offsets(b.Dy(), cfg.PatchSize, cfg.Overlap)
+	limit := cfg.MaxPatches
+	if limit <= 0 {
+		limit = DefaultMaxPatches
+	}
+	if n := len(xs) * len(ys); n > limit {
+		return 0, nil, fmt.Errorf("%w: %d (limit %d)", ErrTooManyPatches, n, limit)
+	}
+
+	out := &models.TiledScore{
+		Aggregation: cfg.Aggregation,
+		PatchSize:   cfg.PatchSize,
+		Rows:        len(ys),
+		Cols:        len(xs),
+		Width:       b.Dx(),
+		Height:      b.Dy(),
+		Patches:     make([]models.PatchScore, 0, len(xs)*len(ys)),
+	}
+	for row, y := range ys {
+		for col, x := range xs {
+			r := image.Rect(x, y, min(x+cfg.PatchSize, b.Dx()), min(y+cfg.PatchSize, b.Dy())).Add(b.Min)
+			s, err := score(crop(img, r))
+			if err != nil {
+				return 0, nil, fmt.Errorf("patch %d,%d: %w", row, col, err)
+			}
+			out.Patches = append(out.Patches, models.PatchScore{
+				Row: row, Col: col,
+				X: r.Min.X - b.Min.X, Y: r.Min.Y - b.Min.Y, Width: r.Dx(), Height: r.Dy(),
+				Score: s,
+			})
+		}
+	}
+	if cfg.MapSize >= 0 {
+		out.ProbabilityMap = Stitch(out, cfg.MapSize)
+	}
+	return aggregate(out.Patches, cfg.Aggregation), out, nil
+}
+
+// offsets returns patch start positions along one axis: a regular stride,
+// with the last patch aligned to the far edge so the whole axis is covered.
+func offsets(length, patch int, overlap float64) []int {
+	if length <= patch {
+		return []int{0}
+	}
+	stride := max(int(math.Round(float64(patch)*(1-overlap))), 1)
+	var out []int
+	for x := 0; x+patch < length; x += stride {
+		out = append(out, x)
+	}
+	return append(out, length-patch)
+}
+
+func aggregate(patches []models.PatchScore, how string) float64 {
+	switch how {
+	case Mean:
+		var sum float64
+		for _, p := range patches {
+			sum += p.Score
+		}
+		return sum / float64(len(patches))
+	case NoisyOr:
+		miss := 1.0
+		for _, p := range patches {
+			miss *= 1 - p.Score
+		}
+		return 1 - miss
+	default:
+		var best float64
+		for _, p := range patches {
+			best = max(best, p.Score)
+		}
+		return best
+	}
+}
+
+// crop returns the r region of img, sharing pixels when the image type
+// allows it.
+func crop(img image.Image, r image.Rectangle) image.Image {
+	if s, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return s.SubImage(r)
+	}
+	out := image.NewNRGBA64(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(out, out.Bounds(), img, r.Min, draw.Src)
+	return out
+}
@@ -0,0 +1,201 @@
+// backend/internal/tta/tta.go
+/*
+ * This file implements test-time augmentation (TTA).
+ *
+ * A single forward pass can hinge on details that should not matter: a
+ * few degrees of positioning, which side of the detector the breast was
+ * on, how tightly the film was cropped. With TTA the study is scored
+ * several times, once per augmented copy, and the study score is the mean
+ * of the copies' scores. The spread of the scores says how much the
+ * model's opinion depends on such details.
+ *
+ * Augmentations are named in a comma-separated list:
+ *
+ *   identity     the study as uploaded
+ *   hflip        mirrored left to right
+ *   rotate:<d>   rotated by d degrees clockwise about the centre; the
+ *                corners left uncovered are black, like film background
+ *   crop:<f>     the centred fraction f (0-1) of each side
+ *
+ * DefaultAugmentations is "identity,hflip,rotate:-5,rotate:5,crop:0.9".
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package tta
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+)
+
+// DefaultAugmentations are the augmentations used unless configured.
+const DefaultAugmentations = "identity,hflip,rotate:-5,rotate:5,crop:0.9"
+
+// maxRotation bounds rotations: beyond it a copy no longer looks like a
+// mammogram taken the usual way.
+const maxRotation = 45
+
+// Augmentation is one transformation of the study.
+type Augmentation struct {
+	// Name is the augmentation as written in the list, e.g. "rotate:5".
+	Name  string
+	apply func(image.Image) image.Image
+}
+
+// Apply returns the augmented copy of img.
+func (a Augmentation) Apply(img image.Image) image.Image {
+	if a.apply == nil {
+		return img
+	}
+	return a.apply(img)
+}
+
+// Config configures TTA.
+type Config struct {
+	// Augmentations are the copies scored for every study.
+	Augmentations []Augmentation
+	// Default turns TTA on for requests that do not ask either way.
+	Default bool
+}
+
+// DefaultConfig returns DefaultAugmentations, off unless requested.
+func DefaultConfig() Config {
+	augs, err := Parse(DefaultAugmentations)
+	if err != nil {
+		panic(err)
+	}
+	return Config{Augmentations: augs}
+}
+
+// Names lists the configured augmentations.
+func (c Config) Names() []string {
+	names := make([]string, len(c.Augmentations))
+	for i, a := range c.Augmentations {
+		names[i] = a.Name
+	}
+	return names
+}
+
+// Parse reads a comma-separated list of augmentations.
+func Parse(s string) ([]Augmentation, error) {
+	var augs []Augmentation
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		a, err := parseOne(name)
+		if err != nil {
+			return nil, err
+		}
+		augs = append(augs, a)
+	}
+	if len(augs) == 0 {
+		return nil, fmt.Errorf("no augmentations in %q", s)
+	}
+	return augs, nil
+}
+
+func parseOne(name string) (Augmentation, error) {
+	kind, arg, hasArg := strings.Cut(name, ":")
+	switch {
+	case kind == "identity" && !hasArg:
+		return Augmentation{Name: name}, nil
+	case kind == "hflip" && !hasArg:
+		return Augmentation{Name: name, apply: flip}, nil
+	case kind == "rotate" && hasArg:
+		deg, err := strconv.ParseFloat(arg, 64)
+		if err != nil || math.Abs(deg) > maxRotation {
+			return Augmentation{}, fmt.Errorf("invalid rotation %q (want degrees within ±%d)", arg, maxRotation)
+		}
+		return Augmentation{Name: name, apply: func(img image.Image) image.Image { return rotate(img, deg) }}, nil
+	case kind == "crop" && hasArg:
+		f, err := strconv.ParseFloat(arg, 64)
+		if err != nil || f <= 0 || f > 1 {
+			return Augmentation{}, fmt.Errorf("invalid crop fraction %q (want 0-1)", arg)
+		}
+		return Augmentation{Name: name, apply: func(img image.Image) image.Image { return cropCenter(img, f) }}, nil
+	}
+	return Augmentation{}, fmt.Errorf("unknown augmentation %q (want identity, hflip, rotate:<degrees> or crop:<fraction>)", name)
+}
+
+// ScoreFunc scores one augmented copy of the study.
+type ScoreFunc func(img image.Image) (float64, error)
+
+// Run scores every augmented copy of img and averages the scores.
+func Run(img image.Image, augs []Augmentation, score ScoreFunc) (float64, *models.TTAScore, error) {
+	out := &models.TTAScore{Aggregation: "mean", Scores: make([]models.AugmentedScore, 0, len(augs))}
+	var sum, sumSq float64
+	for _, a := range augs {
+		s, err := score(a.Apply(img))
+		if err != nil {
+			return 0, nil, fmt.Errorf("augmentation %s: %w", a.Name, err)
+		}
+		out.Scores = append(out.Scores, models.AugmentedScore{Augmentation: a.Name, ConfidenceScore: s})
+		sum += s
+		sumSq += s * s
+	}
+	n := float64(len(augs))
+	mean := sum / n
+	out.StdDev = math.Sqrt(max(0, sumSq/n-mean*mean))
+	return mean, out, nil
+}
+
+// flip mirrors img left to right.
+func flip(img image.Image) image.Image {
+	b := img.Bounds()
+	return transform(img, f64.Aff3{
+		-1, 0, float64(b.Max.X),
+		0, 1, float64(-b.Min.Y),
+	})
+}
+
+// rotate turns img clockwise by deg degrees about its centre, keeping its
+// size.
+func rotate(img image.Image, deg float64) image.Image {
+	b := img.Bounds()
+	sin, cos := math.Sincos(deg * math.Pi / 180)
+	cx, cy := float64(b.Min.X)+float64(b.Dx())/2, float64(b.Min.Y)+float64(b.Dy())/2
+	dx, dy := float64(b.Dx())/2, float64(b.Dy())/2
+	return transform(img, f64.Aff3{
+		cos, -sin, dx - cos*cx + sin*cy,
+		sin, cos, dy - sin*cx - cos*cy,
+	})
+}
+
+// transform draws img through the source-to-destination matrix m onto a
+// black image of the same size.
+func transform(img image.Image, m f64.Aff3) image.Image {
+	b := img.Bounds()
+	out := image.NewNRGBA64(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(out, out.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	draw.BiLinear.Transform(out, m, img, b, draw.Over, nil)
+	return out
+}
+
+// cropCenter returns the centred fraction f of each side of img.
+func cropCenter(img image.Image, f float64) image.Image {
+	b := img.Bounds()
+	w, h := max(int(float64(b.Dx())*f), 1), max(int(float64(b.Dy())*f), 1)
+	r := image.Rect(0, 0, w, h).Add(b.Min).Add(image.Pt((b.Dx()-w)/2, (b.Dy()-h)/2))
+	if s, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return s.SubImage(r)
+	}
+	out := image.NewNRGBA64(image.Rect(0, 0, w, h))
+	draw.Draw(out, out.Bounds(), img, r.Min, draw.Src)
+	return out
+}
@@ -0,0 +1,112 @@
+// backend/internal/uploadtoken/uploadtoken.go
+/*
+ * This file contains the issuer for short-lived, single-use upload tokens.
+ *
+ * The web frontend must not hold a long-lived API key. Instead its backend
+ * (or a logged-in session) asks us for an upload token, hands it to the
+ * browser, and the browser uploads directly with it. A token is bound to
+ * the principal that minted it, expires after a few minutes, and is
+ * consumed by the first request that presents it.
+ *
+ * Only a digest of each token is kept, in memory; restarting the service
+ * invalidates outstanding tokens, which is harmless given their lifetime.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package uploadtoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/access"
+)
+
+// Prefix marks a bearer credential as an upload token rather than an API key.
+const Prefix = "ut_"
+
+// ErrInvalid is returned for unknown, expired or already-used tokens.
+var ErrInvalid = errors.New("upload token is invalid, expired or already used")
+
+// DefaultTTL is how long a token stays valid when no TTL is configured.
+const DefaultTTL = 5 * time.Minute
+
+// Issuer mints and redeems upload tokens.
+type Issuer struct {
+	ttl time.Duration
+
+	mu     sync.Mutex
+	tokens map[[sha256.Size]byte]entry
+}
+
+type entry struct {
+	principal access.Principal
+	expires   time.Time
+}
+
+// NewIssuer creates an issuer whose tokens live for ttl.
+func NewIssuer(ttl time.Duration) *Issuer {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Issuer{ttl: ttl, tokens: make(map[[sha256.Size]byte]entry)}
+}
+
+// Issue mints a token acting as principal and returns it with its expiry.
+func (i *Issuer) Issue(principal access.Principal) (string, time.Time, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, err
+	}
+	token := Prefix + base64.RawURLEncoding.EncodeToString(raw)
+	expires := time.Now().Add(i.ttl).UTC()
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.sweep()
+	i.tokens[sha256.Sum256([]byte(token))] = entry{principal: principal, expires: expires}
+	return token, expires, nil
+}
+
+// Redeem consumes a token and returns the principal it acts as.
+func (i *Issuer) Redeem(token string) (*access.Principal, error) {
+	if !strings.HasPrefix(token, Prefix) {
+		return nil, ErrInvalid
+	}
+	key := sha256.Sum256([]byte(token))
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	e, ok := i.tokens[key]
+	delete(i.tokens, key)
+	if !ok || time.Now().After(e.expires) {
+		return nil, ErrInvalid
+	}
+	return &e.principal, nil
+}
+
+// Len reports how many unredeemed tokens are outstanding.
+func (i *Issuer) Len() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.sweep()
+	return len(i.tokens)
+}
+
+// sweep drops expired tokens. The caller holds i.mu.
+func (i *Issuer) sweep() {
+	now := time.Now()
+	for k, e := range i.tokens {
+		if now.After(e.expires) {
+			delete(i.tokens, k)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+// backend/internal/webhook/delivery.go
+/*
+ * This file delivers events to the registered webhook endpoints.
+ *
+ * Every delivery is a JSON POST:
+ *
+ *   {"id": "<event id>", "type": "prediction.completed",
+ *    "tenant": "clinic-berlin", "created_at": "...", "data": {...}}
+ *
+ * signed with the endpoint's secret so receivers can authenticate it:
+ *
+ *   X-MammoScan-Event:     prediction.completed
+ *   X-MammoScan-Signature: t=<unix seconds>,v1=<hex HMAC-SHA256 of "t.body">
+ *   Idempotency-Key:       <event id>
+ *
+ * A delivery may arrive more than once (a retry after a timeout whose
+ * request did get through); it always carries the same event ID and
+ * Idempotency-Key, so receivers discard what they already processed.
+ *
+ * Deliveries run in the background off a bounded buffer and are retried a
+ * few times with backoff; an event that cannot be queued is dropped and
+ * counted, never allowed to hold up the request that produced it. Each
+ * endpoint has a circuit breaker: once an endpoint keeps failing, its
+ * deliveries are skipped without waiting on it until a cooldown passes,
+ * so one dead receiver does not delay everyone else's. Connections to
+ * the endpoints are pooled and kept alive between deliveries. With an
+ * outbox configured, deliveries are durable instead (see outbox.go).
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/dependency"
+	"github.com/josephed37/mammoscan-AI/backend/internal/models"
+	"github.com/josephed37/mammoscan-AI/backend/internal/spool"
+)
+
+// Delivery headers.
+const (
+	EventHeader       = "X-MammoScan-Event"
+	SignatureHeader   = "X-MammoScan-Signature"
+	IdempotencyHeader = "Idempotency-Key"
+)
+
+// deliveryTimeout bounds one attempt, whatever client is configured.
+const deliveryTimeout = 30 * time.Second
+
+// Event is the envelope POSTed to subscribers.
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Tenant    string    `json:"tenant,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// DispatcherConfig controls delivery.
+type DispatcherConfig struct {
+	// BufferSize is the number of deliveries held while endpoints catch up.
+	BufferSize int
+	// Attempts is the number of tries per delivery.
+	Attempts int
+	// Backoff is the wait before the first retry; it doubles each time.
+	Backoff time.Duration
+	// Client sends the requests (default: a pooled client with a 10s
+	// timeout).
+	Client *http.Client
+	// Breaker tunes the per-endpoint circuit breakers.
+	Breaker dependency.BreakerConfig
+	// Outbox, when set, holds every delivery on disk until its endpoint
+	// acknowledged it (see outbox.go); BufferSize, Attempts and Backoff
+	// then do not apply.
+	Outbox *spool.Queue
+	// OutboxInterval is the delay between delivery passes over the outbox
+	// when no new event wakes it (default 5s).
+	OutboxInterval time.Duration
+	// OutboxMaxAge is how long a delivery is retried before it is moved
+	// to DeadLetter (default 24h).
+	OutboxMaxAge time.Duration
+	// DeadLetter receives the outbox deliveries older than OutboxMaxAge.
+	// Without it they are retried for as long as they take.
+	DeadLetter *spool.Queue
+}
+
+// delivery is one event bound for one endpoint.
+type delivery struct {
+	endpoint Endpoint
+	event    Event
+	body     []byte
+}
+
+// Dispatcher fans events out to the subscribed endpoints of a tenant.
+type Dispatcher struct {
+	registry   *Registry
+	cfg        DispatcherConfig
+	deliveries chan delivery
+	dropped    atomic.Int64
+	skipped    atomic.Int64
+	wake       chan struct{}
+	relayWake  chan struct{}
+	source     EventSource
+
+	mu       sync.Mutex
+	breakers map[string]*dependency.Breaker
+}
+
+// NewDispatcher starts a background goroutine that delivers events to the
+// endpoints registered in registry.
+func NewDispatcher(registry *Registry, cfg DispatcherConfig) *Dispatcher {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 1024
+	}
+	if cfg.Attempts <= 0 {
+		cfg.Attempts = 3
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = time.Second
+	}
+	if cfg.Client == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.MaxIdleConnsPerHost = 8
+		transport.IdleConnTimeout = 90 * time.Second
+		cfg.Client = &http.Client{Timeout: 10 * time.Second, Transport: transport}
+	}
+	if cfg.OutboxInterval <= 0 {
+		cfg.OutboxInterval = 5 * time.Second
+	}
+	if cfg.OutboxMaxAge <= 0 {
+		cfg.OutboxMaxAge = 24 * time.Hour
+	}
+	d := &Dispatcher{
+		registry:   registry,
+		cfg:        cfg,
+		deliveries: make(chan delivery, cfg.BufferSize),
+		wake:       make(chan struct{}, 1),
+		relayWake:  make(chan struct{}, 1),
+		breakers:   make(map[string]*dependency.Breaker),
+	}
+	if cfg.Outbox != nil {
+		go d.drain(cfg.Outbox.Len())
+	} else {
+		go d.run()
+	}
+	return d
+}
+
+// Registry returns the endpoint registry the dispatcher delivers to.
+func (d *Dispatcher) Registry() *Registry {
+	if d == nil {
+		return nil
+	}
+	return d.registry
+}
+
+// Notify queues ev for every endpoint of its tenant subscribed to its
+// type. The event keeps its ID, which is the Idempotency-Key of its
+// deliveries, so the same event notified twice is recognised as one by
+// receivers. It never blocks and is a no-op on a nil dispatcher.
+func (d *Dispatcher) Notify(ev Event) {
+	if d == nil {
+		return
+	}
+	d.enqueue(d.registry.Subscribers(ev.Tenant, ev.Type), ev)
+}
+
+// Broadcast queues a service-wide event for every subscribed endpoint of
+// every tenant.
+func (d *Dispatcher) Broadcast(ev Event) {
+	if d == nil {
+		return
+	}
+	ev.Tenant = ""
+	d.enqueue(d.registry.AllSubscribers(ev.Type), ev)
+}
+
+func (d *Dispatcher) enqueue(endpoints []Endpoint, ev Event) {
+	if len(endpoints) == 0 {
+		return
+	}
+	if ev.ID == "" {
+		ev.ID = newEventID()
+	}
+	if ev.CreatedAt.IsZero() {
+		ev.CreatedAt = time.Now().UTC()
+	}
+	body, err := json.Marshal(ev)
+	if err != nil {
+		slog.Error("webhook: encode event failed", "event_type", ev.Type, "event_id", ev.ID, "tenant", ev.Tenant, "error", err)
+		return
+	}
+	if d.cfg.Outbox != nil {
+		d.store(endpoints, ev, body)
+		return
+	}
+	for _, e := range endpoints {
+		select {
+		case d.deliveries <- delivery{endpoint: e, event: ev, body: body}:
+		default:
+			d.dropped.Add(1)
+			slog.Warn("webhook: buffer full, delivery dropped", "event_type", ev.Type, "event_id", ev.ID, "tenant", ev.Tenant, "webhook", e.ID)
+		}
+	}
+}
+
+// Dropped returns how many deliveries were discarded because the buffer
+// was full.
+func (d *Dispatcher) Dropped() int64 {
+	return d.dropped.Load()
+}
+
+// Skipped returns how many deliveries were discarded because their
+// endpoint's circuit was open.
+func (d *Dispatcher) Skipped() int64 {
+	return d.skipped.Load()
+}
+
+// breaker returns the circuit breaker of the endpoint.
+func (d *Dispatcher) breaker(e Endpoint) *dependency.Breaker {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b, ok := d.breakers[e.ID]
+	if !ok {
+		b = dependency.NewBreaker("webhook "+e.ID, d.cfg.Breaker)
+		d.breakers[e.ID] = b
+	}
+	return b
+}
+
+// Status summarises the endpoints' breakers as one dependency: healthy
+// while no endpoint's circuit is open, reporting the endpoint with the
+// most consecutive failures.
+func (d *Dispatcher) Status() models.DependencyStatus {
+	d.mu.Lock()
+	breakers := make([]*dependency.Breaker, 0, len(d.breakers))
+	for _, b := range d.breakers {
+		breakers = append(breakers, b)
+	}
+	d.mu.Unlock()
+	worst := models.DependencyStatus{State: dependency.StateClosed, Healthy: true}
+	failing := 0
+	for _, b := range breakers {
+		s := b.Status()
+		if !s.Healthy {
+			failing++
+		}
+		if s.ConsecutiveFailures > worst.ConsecutiveFailures || (!s.Healthy && worst.Healthy) {
+			worst = s
+		}
+	}
+	worst.Name = "webhooks"
+	worst.Detail = fmt.Sprintf("%d of %d endpoints failing", failing, len(breakers))
+	if dropped, skipped := d.Dropped(), d.Skipped(); dropped+skipped > 0 {
+		worst.Detail += fmt.Sprintf("; %d deliveries dropped, %d skipped", dropped, skipped)
+	}
+	if d.cfg.Outbox != nil {
+		worst.Detail += fmt.Sprintf("; %d in the outbox, %d dead-lettered", d.Pending(), d.DeadLettered())
+	}
+	return worst
+}
+
+func (d *Dispatcher) run() {
+	for dl := range d.deliveries {
+		b := d.breaker(dl.endpoint)
+		backoff := d.cfg.Backoff
+		var err error
+		for attempt := 1; attempt <= d.cfg.Attempts; attempt++ {
+			if err = b.Do(func() error { return d.send(dl) }, nil); err == nil || errors.Is(err, dependency.ErrOpen) {
+				break
+			}
+			if attempt < d.cfg.Attempts {
+				time.Sleep(backoff)
+				backoff *= 2
+			}
+		}
+		switch {
+		case errors.Is(err, dependency.ErrOpen):
+			d.skipped.Add(1)
+			log.Printf("webhook: skipped %s event %s to webhook %s: %v", dl.event.Type, dl.event.ID, dl.endpoint.ID, err)
+		case err != nil:
+			log.Printf("webhook: %s event %s to webhook %s failed: %v", dl.event.Type, dl.event.ID, dl.endpoint.ID, err)
+		}
+	}
+}
+
+func (d *Dispatcher) send(dl delivery) error {
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dl.endpoint.URL, bytes.NewReader(dl.body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, dl.event.Type)
+	req.Header.Set(SignatureHeader, Sign(dl.endpoint.Secret, time.Now(), dl.body))
+	req.Header.Set(IdempotencyHeader, dl.event.ID)
+
+	resp, err := d.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the signature header value for body sent at t.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newEventID returns a time-ordered ID for an event that came without one.
+func newEventID() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.NewString()
+	}
+	return id.String()
+}
@@ -0,0 +1,259 @@
+// backend/internal/webhook/outbox.go
+/*
+ * This file implements the webhook outbox.
+ *
+ * Delivered from memory, an event is lost when the process stops before
+ * its endpoint answered, and retried until the attempts run out when the
+ * endpoint is slow, so a receiver can get it twice (duplicate RIS entries)
+ * or never. With an outbox, each delivery is written to a spool directory
+ * before the request that produced the event is answered, and removed
+ * only once its endpoint acknowledged it with a 2xx. A background loop
+ * sends the stored deliveries oldest first; an endpoint that fails is
+ * retried on the next pass, after the events queued behind it, so each
+ * endpoint receives its events in order.
+ *
+ * A delivery whose acknowledgement is lost (a timeout, a crash between
+ * the 2xx and the removal) is sent again with the same event ID and
+ * Idempotency-Key, so a receiver that records the keys it processed sees
+ * every event exactly once. The key is the event's own ID -- for
+ * prediction.completed, the prediction ID -- so an event stored twice is
+ * still delivered under one key. Deliveries older than OutboxMaxAge are
+ * moved to the dead letter queue (DispatcherConfig.DeadLetter), counted
+ * and logged, where an operator can inspect and replay them; they are
+ * never discarded. Signing secrets are not written to the outbox: the
+ * endpoint is looked up when the delivery is sent, and deliveries to
+ * endpoints since deleted or disabled are discarded.
+ *
+ * An event published after its prediction was saved is lost if the
+ * process stops in between. Where the prediction store keeps an event
+ * outbox of its own (store.OutboxStore), the prediction.completed event
+ * is written with the prediction instead, and RelayFrom moves such
+ * events from the store into this outbox, removing them from the store
+ * only once they are here.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"log/slog"
+	"time"
+
+	"github.com/josephed37/mammoscan-AI/backend/internal/dependency"
+	"github.com/josephed37/mammoscan-AI/backend/internal/spool"
+	"github.com/josephed37/mammoscan-AI/backend/internal/store"
+)
+
+// outboxKind tags webhook deliveries in the spool.
+const outboxKind = "webhook"
+
+// relayBatch bounds the events read from an EventSource at a time.
+const relayBatch = 100
+
+// relayTimeout bounds one read or acknowledgement of an EventSource.
+const relayTimeout = 10 * time.Second
+
+// EventSource is an event outbox kept by another store, such as the
+// prediction store's.
+type EventSource interface {
+	PendingEvents(ctx context.Context, limit int) ([]store.OutboxEvent, error)
+	AckEvents(ctx context.Context, ids ...string) error
+}
+
+// outboxEntry is a delivery as stored in the outbox.
+type outboxEntry struct {
+	Endpoint  string          `json:"endpoint"`
+	EventID   string          `json:"event_id"`
+	EventType string          `json:"event_type"`
+	Body      json.RawMessage `json:"body"`
+}
+
+// store writes one delivery per endpoint to the outbox and wakes the
+// delivery loop. It returns the first failed write; the deliveries that
+// could not be written are dropped.
+func (d *Dispatcher) store(endpoints []Endpoint, ev Event, body []byte) error {
+	var first error
+	for _, e := range endpoints {
+		payload, err := json.Marshal(outboxEntry{Endpoint: e.ID, EventID: ev.ID, EventType: ev.Type, Body: body})
+		if err == nil {
+			err = d.cfg.Outbox.Enqueue(spool.Record{ID: ev.ID + "." + e.ID, Kind: outboxKind, CreatedAt: ev.CreatedAt, Payload: payload}, nil)
+		}
+		if err != nil {
+			d.dropped.Add(1)
+			slog.Error("webhook: outbox write failed, delivery dropped", "event_type", ev.Type, "event_id", ev.ID, "tenant", ev.Tenant, "webhook", e.ID, "error", err)
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return first
+}
+
+// drain delivers the outbox until the process exits, starting with the
+// leftover deliveries a previous process left in it.
+func (d *Dispatcher) drain(leftover int) {
+	if leftover > 0 {
+		log.Printf("webhook: %d delivery(ies) left in the outbox from a previous run", leftover)
+	}
+	for {
+		d.drainOnce()
+		select {
+		case <-d.wake:
+		case <-time.After(d.cfg.OutboxInterval):
+		}
+	}
+}
+
+// drainOnce makes one pass over the outbox. An endpoint that fails gets
+// no further deliveries this pass, so its events keep their order.
+func (d *Dispatcher) drainOnce() {
+	records, err := d.cfg.Outbox.Pending(0)
+	if err != nil {
+		log.Printf("webhook: read outbox: %v", err)
+		return
+	}
+	blocked := make(map[string]bool)
+	for _, rec := range records {
+		var entry outboxEntry
+		if err := json.Unmarshal(rec.Payload, &entry); err != nil {
+			log.Printf("webhook: discarding unreadable outbox entry %s: %v", rec.ID, err)
+			d.cfg.Outbox.Ack(rec.ID)
+			continue
+		}
+		if blocked[entry.Endpoint] {
+			continue
+		}
+		if d.cfg.DeadLetter != nil && time.Since(rec.CreatedAt) > d.cfg.OutboxMaxAge {
+			if err := d.cfg.DeadLetter.Enqueue(rec, nil); err != nil {
+				log.Printf("webhook: move %s event %s for webhook %s to the dead letter queue: %v", entry.EventType, entry.EventID, entry.Endpoint, err)
+				continue
+			}
+			log.Printf("webhook: %s event %s to webhook %s undelivered after %s, moved to the dead letter queue", entry.EventType, entry.EventID, entry.Endpoint, d.cfg.OutboxMaxAge)
+			d.cfg.Outbox.Ack(rec.ID)
+			continue
+		}
+		e, ok := d.registry.endpoint(entry.Endpoint)
+		if !ok || e.Disabled {
+			// Deleted or disabled since the event was stored.
+			d.cfg.Outbox.Ack(rec.ID)
+			continue
+		}
+		dl := delivery{endpoint: e, event: Event{ID: entry.EventID, Type: entry.EventType}, body: entry.Body}
+		if err := d.breaker(e).Do(func() error { return d.send(dl) }, nil); err != nil {
+			blocked[e.ID] = true
+			if !errors.Is(err, dependency.ErrOpen) {
+				log.Printf("webhook: %s event %s to webhook %s failed, kept in the outbox: %v", entry.EventType, entry.EventID, e.ID, err)
+			}
+			continue
+		}
+		if err := d.cfg.Outbox.Ack(rec.ID); err != nil {
+			log.Printf("webhook: remove delivered event %s for webhook %s from the outbox: %v", entry.EventID, e.ID, err)
+		}
+	}
+}
+
+// Pending returns the number of deliveries waiting in the outbox; zero
+// without one.
+func (d *Dispatcher) Pending() int {
+	if d == nil || d.cfg.Outbox == nil {
+		return 0
+	}
+	return d.cfg.Outbox.Len()
+}
+
+// DeadLettered returns the number of deliveries in the dead letter
+// queue; zero without one.
+func (d *Dispatcher) DeadLettered() int {
+	if d == nil || d.cfg.DeadLetter == nil {
+		return 0
+	}
+	return d.cfg.DeadLetter.Len()
+}
+
+// RelayFrom starts moving the events of src into the outbox, each to the
+// endpoints subscribed to it, and removing them from src once stored. It
+// needs an outbox and must be called before the dispatcher is used; it is
+// a no-op otherwise.
+func (d *Dispatcher) RelayFrom(src EventSource) {
+	if d == nil || d.cfg.Outbox == nil || d.source != nil {
+		return
+	}
+	d.source = src
+	go d.relay()
+}
+
+// Relaying reports whether RelayFrom started a relay.
+func (d *Dispatcher) Relaying() bool {
+	return d != nil && d.source != nil
+}
+
+// RelayNow wakes the relay, as after writing events to its source.
+func (d *Dispatcher) RelayNow() {
+	if !d.Relaying() {
+		return
+	}
+	select {
+	case d.relayWake <- struct{}{}:
+	default:
+	}
+}
+
+// relay moves events from the source until the process exits.
+func (d *Dispatcher) relay() {
+	for {
+		for d.relayOnce() == relayBatch {
+			// A full batch moved; there may be more.
+		}
+		select {
+		case <-d.relayWake:
+		case <-time.After(d.cfg.OutboxInterval):
+		}
+	}
+}
+
+// relayOnce moves one batch of events and returns how many were moved. An
+// event that cannot be stored stays in the source for the next pass.
+func (d *Dispatcher) relayOnce() int {
+	ctx, cancel := context.WithTimeout(context.Background(), relayTimeout)
+	defer cancel()
+	evs, err := d.source.PendingEvents(ctx, relayBatch)
+	if err != nil {
+		log.Printf("webhook: read event outbox: %v", err)
+		return 0
+	}
+	var moved []string
+	for _, oe := range evs {
+		ev := Event{ID: oe.ID, Type: oe.Type, Tenant: oe.Tenant, CreatedAt: oe.CreatedAt, Data: oe.Data}
+		if endpoints := d.registry.Subscribers(ev.Tenant, ev.Type); len(endpoints) > 0 {
+			body, err := json.Marshal(ev)
+			if err != nil {
+				log.Printf("webhook: encode %s event %s: %v", ev.Type, ev.ID, err)
+				continue
+			}
+			if d.store(endpoints, ev, body) != nil {
+				continue
+			}
+		}
+		moved = append(moved, ev.ID)
+	}
+	if len(moved) > 0 {
+		if err := d.source.AckEvents(ctx, moved...); err != nil {
+			// They are stored again on the next pass, under the same
+			// IDs, so no endpoint gets a second delivery key.
+			log.Printf("webhook: remove %d relayed event(s) from the event outbox: %v", len(moved), err)
+			return 0
+		}
+	}
+	return len(moved)
+}
@@ -0,0 +1,369 @@
+// backend/internal/webhook/registry.go
+/*
+ * This file contains the per-tenant webhook registry.
+ *
+ * Each tenant manages its own webhook endpoints: a destination URL, the
+ * events it subscribes to and the secret used to sign deliveries. Secrets
+ * are generated here and only ever returned when an endpoint is created or
+ * its secret rotated. With a path configured the registry is saved to a
+ * JSON file (mode 0600, since it holds the secrets) after every change.
+ *
+ * Author: Joseph Edjeani
+ * Date:   October 17, 2026
+ * Version: 1.0.0
+ */
+
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types a webhook can subscribe to.
+const (
+	PredictionCompleted = "prediction.completed"
+	JobFailed           = "job.failed"
+	DriftAlert          = "drift.alert"
+	ModelStaleAlert     = "model.stale"
+	BreakGlassAlert     = "security.break_glass"
+)
+
+// Events lists every event type, in documentation order.
+var Events = []string{PredictionCompleted, JobFailed, DriftAlert, ModelStaleAlert, BreakGlassAlert}
+
+// MaxEndpoints bounds the endpoints one tenant may register.
+const MaxEndpoints = 20
+
+var (
+	// ErrNotFound is returned for an unknown endpoint ID.
+	ErrNotFound = errors.New("webhook not found")
+	// ErrInvalid wraps validation failures of an endpoint definition.
+	ErrInvalid = errors.New("invalid webhook")
+	// ErrLimit is returned when a tenant already has MaxEndpoints.
+	ErrLimit = errors.New("webhook limit reached")
+)
+
+// Endpoint is one registered webhook destination.
+type Endpoint struct {
+	ID          string    `json:"id"`
+	Tenant      string    `json:"tenant"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	Description string    `json:"description,omitempty"`
+	Disabled    bool      `json:"disabled"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// Secret signs deliveries. It is omitted from every response except
+	// the one that created or rotated it.
+	Secret string `json:"secret,omitempty"`
+}
+
+// Subscribes reports whether the endpoint wants event.
+func (e Endpoint) Subscribes(event string) bool {
+	return !e.Disabled && slices.Contains(e.Events, event)
+}
+
+// Redacted returns the endpoint without its secret.
+func (e Endpoint) Redacted() Endpoint {
+	e.Secret = ""
+	e.Events = slices.Clone(e.Events)
+	return e
+}
+
+// Spec is the client-supplied part of an endpoint. Nil fields are left
+// unchanged on update.
+type Spec struct {
+	URL         *string  `json:"url"`
+	Events      []string `json:"events"`
+	Description *string  `json:"description"`
+	Disabled    *bool    `json:"disabled"`
+}
+
+// apply copies the set fields of s onto e and validates the result.
+func (s Spec) apply(e *Endpoint) error {
+	if s.URL != nil {
+		e.URL = *s.URL
+	}
+	if s.Events != nil {
+		e.Events = slices.Clone(s.Events)
+		slices.Sort(e.Events)
+		e.Events = slices.Compact(e.Events)
+	}
+	if s.Description != nil {
+		e.Description = *s.Description
+	}
+	if s.Disabled != nil {
+		e.Disabled = *s.Disabled
+	}
+	return e.validate()
+}
+
+func (e *Endpoint) validate() error {
+	u, err := url.Parse(e.URL)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalid)
+	}
+	if len(e.Events) == 0 {
+		return fmt.Errorf("%w: at least one event is required", ErrInvalid)
+	}
+	for _, ev := range e.Events {
+		if !slices.Contains(Events, ev) {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalid, ev)
+		}
+	}
+	if len(e.Description) > 256 {
+		return fmt.Errorf("%w: description exceeds 256 characters", ErrInvalid)
+	}
+	return nil
+}
+
+// Registry holds every tenant's endpoints. It is safe for concurrent use.
+type Registry struct {
+	path string
+
+	mu        sync.RWMutex
+	endpoints map[string]Endpoint
+}
+
+// NewRegistry creates a registry, loading path if it exists. An empty
+// path keeps endpoints in memory only.
+func NewRegistry(path string) (*Registry, error) {
+	r := &Registry{path: path, endpoints: make(map[string]Endpoint)}
+	if path == "" {
+		return r, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []Endpoint
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for _, e := range list {
+		r.endpoints[e.ID] = e
+	}
+	return r, nil
+}
+
+// Create registers a new endpoint for tenant. The returned endpoint
+// includes its freshly generated secret.
+func (r *Registry) Create(tenant string, spec Spec) (Endpoint, error) {
+	now := time.Now().UTC()
+	e := Endpoint{ID: uuid.NewString(), Tenant: tenant, CreatedAt: now, UpdatedAt: now}
+	if err := spec.apply(&e); err != nil {
+		return Endpoint{}, err
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return Endpoint{}, err
+	}
+	e.Secret = secret
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.tenantEndpoints(tenant)) >= MaxEndpoints {
+		return Endpoint{}, fmt.Errorf("%w: a tenant may register at most %d webhooks", ErrLimit, MaxEndpoints)
+	}
+	r.endpoints[e.ID] = e
+	if err := r.save(); err != nil {
+		delete(r.endpoints, e.ID)
+		return Endpoint{}, err
+	}
+	return e, nil
+}
+
+// List returns tenant's endpoints, oldest first, without secrets.
+func (r *Registry) List(tenant string) []Endpoint {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := r.tenantEndpoints(tenant)
+	for i := range list {
+		list[i] = list[i].Redacted()
+	}
+	return list
+}
+
+// Get returns one of tenant's endpoints without its secret.
+func (r *Registry) Get(tenant, id string) (Endpoint, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.endpoints[id]
+	if !ok || e.Tenant != tenant {
+		return Endpoint{}, ErrNotFound
+	}
+	return e.Redacted(), nil
+}
+
+// endpoint returns an endpoint with its secret, for delivery.
+func (r *Registry) endpoint(id string) (Endpoint, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.endpoints[id]
+	return e, ok
+}
+
+// Update applies spec to one of tenant's endpoints.
+func (r *Registry) Update(tenant, id string, spec Spec) (Endpoint, error) {
+	return r.modify(tenant, id, func(e *Endpoint) error { return spec.apply(e) })
+}
+
+// RotateSecret replaces an endpoint's signing secret and returns the
+// endpoint with the new secret.
+func (r *Registry) RotateSecret(tenant, id string) (Endpoint, error) {
+	secret, err := newSecret()
+	if err != nil {
+		return Endpoint{}, err
+	}
+	e, err := r.modify(tenant, id, func(e *Endpoint) error {
+		e.Secret = secret
+		return nil
+	})
+	if err != nil {
+		return Endpoint{}, err
+	}
+	e.Secret = secret
+	return e, nil
+}
+
+// Delete removes one of tenant's endpoints.
+func (r *Registry) Delete(tenant, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.endpoints[id]
+	if !ok || e.Tenant != tenant {
+		return ErrNotFound
+	}
+	delete(r.endpoints, id)
+	if err := r.save(); err != nil {
+		r.endpoints[id] = e
+		return err
+	}
+	return nil
+}
+
+// Subscribers returns tenant's enabled endpoints subscribed to event,
+// secrets included, for delivery.
+func (r *Registry) Subscribers(tenant, event string) []Endpoint {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []Endpoint
+	for _, e := range r.tenantEndpoints(tenant) {
+		if e.Subscribes(event) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// AllSubscribers returns every tenant's enabled endpoints subscribed to
+// event, for service-wide events.
+func (r *Registry) AllSubscribers(event string) []Endpoint {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []Endpoint
+	for _, e := range r.endpoints {
+		if e.Subscribes(event) {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// modify applies fn to a copy of the endpoint and saves it if fn succeeds.
+// The returned endpoint has no secret.
+func (r *Registry) modify(tenant, id string, fn func(*Endpoint) error) (Endpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, ok := r.endpoints[id]
+	if !ok || old.Tenant != tenant {
+		return Endpoint{}, ErrNotFound
+	}
+	e := old
+	e.Events = slices.Clone(old.Events)
+	if err := fn(&e); err != nil {
+		return Endpoint{}, err
+	}
+	e.UpdatedAt = time.Now().UTC()
+	r.endpoints[id] = e
+	if err := r.save(); err != nil {
+		r.endpoints[id] = old
+		return Endpoint{}, err
+	}
+	return e.Redacted(), nil
+}
+
+// tenantEndpoints must be called with r.mu held.
+func (r *Registry) tenantEndpoints(tenant string) []Endpoint {
+	var list []Endpoint
+	for _, e := range r.endpoints {
+		if e.Tenant == tenant {
+			list = append(list, e)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.Before(list[j].CreatedAt)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// save writes the registry atomically. It must be called with r.mu held.
+func (r *Registry) save() error {
+	if r.path == "" {
+		return nil
+	}
+	list := make([]Endpoint, 0, len(r.endpoints))
+	for _, e := range r.endpoints {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), ".webhooks-*")
+	if err != nil {
+		return fmt.Errorf("save webhooks: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("save webhooks: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("save webhooks: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return fmt.Errorf("save webhooks: %w", err)
+	}
+	return nil
+}
+
+// newSecret returns a random signing secret.
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
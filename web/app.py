@@ -76,6 +76,19 @@ st.markdown("""
 """, unsafe_allow_html=True)
 
 API_URL = os.getenv("API_URL", "http://localhost:8080/api/v1/predict")
+# The API key never reaches the browser: it is only used server-side to mint
+# a single-use upload token for each submission.
+API_KEY = os.getenv("API_KEY")
+UPLOAD_TOKEN_URL = os.getenv("UPLOAD_TOKEN_URL", API_URL.rsplit("/", 1)[0] + "/upload-tokens")
+
+
+def upload_headers():
+    """Return auth headers for one upload, minting an upload token if an API key is configured."""
+    if not API_KEY:
+        return {}
+    resp = requests.post(UPLOAD_TOKEN_URL, headers={"X-API-Key": API_KEY}, timeout=10)
+    resp.raise_for_status()
+    return {"Authorization": f"Bearer {resp.json()['token']}"}
 
 
 # --- Page 1: Project Overview ---
@@ -269,7 +282,7 @@ def show_demo_page():
                     progress_bar.progress(50)
                     
                     # Send the request to our Go backend API
-                    response = requests.post(API_URL, files=files, timeout=60)
+                    response = requests.post(API_URL, files=files, headers=upload_headers(), timeout=60)
                     response.raise_for_status()
                     
                     progress_bar.progress(75)